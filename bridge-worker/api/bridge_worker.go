@@ -16,6 +16,29 @@ type BridgeWorker interface {
 	Import(BridgeWorkerContext, BridgeWorkerPayload) error
 	Destroy(BridgeWorkerContext, BridgeWorkerPayload) error
 	Finalize(BridgeWorkerContext, BridgeWorkerPayload) error
+	// Plan reports the changes Apply would make to the target given the payload's configuration
+	// data and live state, without changing the target. The plan is sent in an ActionResult with
+	// a human-readable Message and the ResourceMutationList encoded as JSON in Outputs.
+	Plan(BridgeWorkerContext, BridgeWorkerPayload) error
+}
+
+// PlanNotSupportedWorker can be embedded in bridge workers that don't support planning. Its
+// Plan reports that planning isn't supported.
+type PlanNotSupportedWorker struct{}
+
+func (PlanNotSupportedWorker) Plan(wctx BridgeWorkerContext, _ BridgeWorkerPayload) error {
+	return SendPlanNotSupported(wctx)
+}
+
+// SendPlanNotSupported reports that planning isn't supported.
+func SendPlanNotSupported(wctx BridgeWorkerContext) error {
+	return wctx.SendStatus(&ActionResult{
+		ActionResultBaseMeta: ActionResultBaseMeta{
+			Status:  ActionStatusCompleted,
+			Result:  ActionResultNone,
+			Message: "Plan is not supported by this bridge worker",
+		},
+	})
 }
 
 type WatchableWorker interface {
@@ -32,4 +32,5 @@ const (
 	ProviderFluxOCIWriter ProviderType = "FluxOCIWriter"
 	ProviderConfigMap     ProviderType = "ConfigMap"
 	ProviderAWS           ProviderType = "AWS"
+	ProviderVault         ProviderType = "Vault"
 )
@@ -0,0 +1,37 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package api
+
+import "context"
+
+// DiagnosableWorker is implemented by bridge workers that can check their own health, such as
+// their connectivity to and permissions in the systems they manage. The result is reported to
+// ConfigHub with the WorkerInfo when the worker connects and is displayed by cub worker diagnose.
+// The checks are run again whenever ConfigHub sends a worker event with ActionDiagnose, and the
+// refreshed result is sent back as the JSON-encoded Outputs of an ActionResult.
+type DiagnosableWorker interface {
+	Diagnose(ctx context.Context) DiagnosticResult
+}
+
+// DiagnosticResult contains the results of the checks performed by a DiagnosableWorker.
+type DiagnosticResult struct {
+	Checks []DiagnosticCheck `json:",omitempty" description:"Checks performed by the worker"`
+}
+
+// DiagnosticCheck is the result of a single diagnostic check.
+type DiagnosticCheck struct {
+	Name    string `description:"Name of the check"`
+	Passed  bool   `description:"True if the check passed"`
+	Message string `json:",omitempty" description:"Details of the outcome of the check"`
+}
+
+// Passed returns true if all of the checks passed.
+func (r DiagnosticResult) Passed() bool {
+	for _, check := range r.Checks {
+		if !check.Passed {
+			return false
+		}
+	}
+	return true
+}
@@ -26,4 +26,5 @@ type BridgeWorkerPayload struct {
 	TargetParams      []byte                  `swaggertype:"string" format:"byte" description:"Parameters of the Target attached to the Unit on which the action was performed"`
 	ExtraParams       []byte                  `swaggertype:"string" format:"byte" description:"Additional parameters associated with the action sent to the worker"`
 	RevisionNum       int64                   `description:"Sequence number of the revision of the Unit on which the action was performed"`
+	TimeoutSeconds    int64                   `description:"Maximum number of seconds to perform the action, including waiting for it to take effect; 0 means the worker's default"`
 }
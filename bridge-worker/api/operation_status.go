@@ -38,6 +38,8 @@ const (
 	ActionResultRefreshAndNoDrift ActionResultType = "RefreshAndNoDrift"
 	ActionResultImportCompleted   ActionResultType = "ImportCompleted"
 	ActionResultImportFailed      ActionResultType = "ImportFailed"
+	ActionResultPlanCompleted     ActionResultType = "PlanCompleted"
+	ActionResultPlanFailed        ActionResultType = "PlanFailed"
 
 	ActionResultFunctionInvocationCompleted ActionResultType = "FunctionInvocationCompleted"
 	ActionResultFunctionInvocationFailed    ActionResultType = "FunctionInvocationFailed"
@@ -53,7 +55,9 @@ const (
 	ActionRefresh   ActionType = "Refresh"
 	ActionImport    ActionType = "Import"
 	ActionFinalize  ActionType = "Finalize"
+	ActionPlan      ActionType = "Plan"
 	ActionHeartbeat ActionType = "Heartbeat"
+	ActionDiagnose  ActionType = "Diagnose"
 
 	ActionInvokeFunctions ActionType = "InvokeFunctions"
 	ActionListFunctions   ActionType = "ListFunctions"
@@ -6,4 +6,5 @@ package api
 type WorkerInfo struct {
 	BridgeWorkerInfo   BridgeWorkerInfo   `description:"BridgeWorker capabilities"`
 	FunctionWorkerInfo FunctionWorkerInfo `description:"FunctionWorker capabilities"`
+	Diagnostics        *DiagnosticResult  `json:",omitempty" description:"Results of the checks of the BridgeWorker, if it implements DiagnosableWorker"`
 }
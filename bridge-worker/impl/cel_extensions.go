@@ -0,0 +1,76 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package impl
+
+import (
+	"regexp"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/confighub/sdk/function/handler"
+)
+
+// registerKubernetesCELExtensions registers CEL helper functions for common Kubernetes checks
+func registerKubernetesCELExtensions(fh *handler.FunctionHandler) {
+	// hasLabel(labels, key) returns true if the labels map contains the key
+	fh.RegisterCELExtension("hasLabel", cel.Function("hasLabel",
+		cel.Overload("hasLabel_map_string",
+			[]*cel.Type{cel.MapType(cel.StringType, cel.DynType), cel.StringType},
+			cel.BoolType,
+			cel.BinaryBinding(func(labels, key ref.Val) ref.Val {
+				labelMap, ok := labels.(traits.Mapper)
+				if !ok {
+					return types.False
+				}
+				_, found := labelMap.Find(key)
+				return types.Bool(found)
+			}),
+		),
+	))
+	// parseQuantity(q) returns the value of a Kubernetes resource quantity, such as 512Mi or 2,
+	// rounded up to an integer
+	fh.RegisterCELExtension("parseQuantity", cel.Function("parseQuantity",
+		cel.Overload("parseQuantity_string",
+			[]*cel.Type{cel.StringType},
+			cel.IntType,
+			cel.UnaryBinding(func(q ref.Val) ref.Val {
+				s, ok := q.(types.String)
+				if !ok {
+					return types.MaybeNoSuchOverloadErr(q)
+				}
+				quantity, err := resource.ParseQuantity(string(s))
+				if err != nil {
+					return types.NewErr("invalid quantity %q: %v", string(s), err)
+				}
+				return types.Int(quantity.Value())
+			}),
+		),
+	))
+	// matchesRegexp(s, pattern) returns true if s contains a match of the regular expression
+	fh.RegisterCELExtension("matchesRegexp", cel.Function("matchesRegexp",
+		cel.Overload("matchesRegexp_string_string",
+			[]*cel.Type{cel.StringType, cel.StringType},
+			cel.BoolType,
+			cel.BinaryBinding(func(s, pattern ref.Val) ref.Val {
+				str, ok := s.(types.String)
+				if !ok {
+					return types.MaybeNoSuchOverloadErr(s)
+				}
+				patternStr, ok := pattern.(types.String)
+				if !ok {
+					return types.MaybeNoSuchOverloadErr(pattern)
+				}
+				re, err := regexp.Compile(string(patternStr))
+				if err != nil {
+					return types.NewErr("invalid regular expression %q: %v", string(patternStr), err)
+				}
+				return types.Bool(re.MatchString(string(str)))
+			}),
+		),
+	))
+}
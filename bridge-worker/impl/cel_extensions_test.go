@@ -0,0 +1,72 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	funcApi "github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const celExtensionsTestYAML = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    team: payments
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: ghcr.io/acme/web:1.2.3
+        resources:
+          requests:
+            memory: 512Mi
+            cpu: 500m
+`
+
+func runCELValidate(t *testing.T, expr string) (funcApi.ValidationResult, error) {
+	t.Helper()
+	fw := NewKubernetesFunctionWorker()
+	docs, err := gaby.ParseAll([]byte(celExtensionsTestYAML))
+	assert.NoError(t, err)
+	registration := fw.fh.ListCore()["cel-validate"]
+	args := []funcApi.FunctionArgument{{ParameterName: "validation-expr", Value: expr}}
+	_, output, err := registration.Function(&funcApi.FunctionContext{}, docs, args, nil)
+	result, ok := output.(funcApi.ValidationResult)
+	assert.True(t, ok)
+	return result, err
+}
+
+func TestKubernetesCELExtensions(t *testing.T) {
+	tests := []struct {
+		expr   string
+		passed bool
+	}{
+		{`hasLabel(r.metadata.labels, 'team')`, true},
+		{`hasLabel(r.metadata.labels, 'owner')`, false},
+		{`parseQuantity(r.spec.template.spec.containers[0].resources.requests.memory) == 536870912`, true},
+		{`parseQuantity(r.spec.template.spec.containers[0].resources.requests.cpu) == 1`, true},
+		{`matchesRegexp(r.spec.template.spec.containers[0].image, '^ghcr\\.io/acme/')`, true},
+		{`matchesRegexp(r.spec.template.spec.containers[0].image, '^docker\\.io/')`, false},
+	}
+	for _, test := range tests {
+		result, err := runCELValidate(t, test.expr)
+		assert.NoError(t, err, test.expr)
+		assert.Equal(t, test.passed, result.Passed, test.expr)
+	}
+}
+
+func TestKubernetesCELExtensionsErrors(t *testing.T) {
+	_, err := runCELValidate(t, `parseQuantity('lots') > 0`)
+	assert.ErrorContains(t, err, "invalid quantity")
+	_, err = runCELValidate(t, `matchesRegexp(r.metadata.name, '(')`)
+	assert.ErrorContains(t, err, "invalid regular expression")
+	_, err = runCELValidate(t, `isValidImage(r.metadata.name)`)
+	assert.ErrorContains(t, err, "failed to compile")
+}
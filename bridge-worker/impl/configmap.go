@@ -14,12 +14,16 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/confighub/sdk/bridge-worker/api"
 	"github.com/confighub/sdk/bridge-worker/lib"
+	"github.com/confighub/sdk/third_party/gaby"
 	"github.com/confighub/sdk/workerapi"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 type ConfigMapBridgeWorker struct {
 	KubernetesBridgeWorker
+	// ToolchainType is the toolchain of the configuration data stored in the ConfigMaps.
+	// It defaults to AppConfig/Properties.
+	ToolchainType workerapi.ToolchainType
 }
 
 var _ api.BridgeWorker = (*ConfigMapBridgeWorker)(nil)
@@ -72,9 +76,16 @@ func generateConfigMapFromData(args *configMapTemplateArgs) []byte {
 	return out.Bytes()
 }
 
+func (w *ConfigMapBridgeWorker) toolchainType() workerapi.ToolchainType {
+	if w.ToolchainType == "" {
+		return workerapi.ToolchainAppConfigProperties
+	}
+	return w.ToolchainType
+}
+
 func (w *ConfigMapBridgeWorker) Info(opts api.InfoOptions) api.BridgeWorkerInfo {
 	// TODO: Support other AppConfig types
-	return w.KubernetesBridgeWorker.InfoForToolchainAndProvider(opts, workerapi.ToolchainAppConfigProperties, api.ProviderConfigMap)
+	return w.KubernetesBridgeWorker.InfoForToolchainAndProvider(opts, w.toolchainType(), api.ProviderConfigMap)
 }
 
 // This is also defined in the function executor.
@@ -96,8 +107,9 @@ func truncateString(s string, n int) string {
 	return string([]rune(s)[:n])
 }
 
-func transformAppConfigToConfigMap(payload *api.BridgeWorkerPayload) {
-	configData := string(payload.Data)
+// extractPropertiesNamespace returns the namespace specified in Properties configuration data
+// and the data with the configHub fields commented out.
+func extractPropertiesNamespace(configData string) (string, string) {
 	// Extract the namespace. We could use get-string-path, but that would require conversion to YAML, etc.
 	namespaceMatch := namespaceRegexp.FindStringSubmatch(configData)
 	var namespace string
@@ -108,6 +120,43 @@ func transformAppConfigToConfigMap(payload *api.BridgeWorkerPayload) {
 	}
 	// Comment out configHub fields. We may want to uncomment these in functions instead.
 	configData = strings.ReplaceAll(configData, configHubPrefix, "#"+configHubPrefix)
+	return namespace, configData
+}
+
+// extractYAMLNamespace returns the namespace specified in YAML configuration data and the data
+// with the configHub fields removed. The data is returned unchanged if it can't be parsed.
+func extractYAMLNamespace(configData string) (string, string) {
+	namespace := "default"
+	docs, err := gaby.ParseAll([]byte(configData))
+	if err != nil || len(docs) != 1 {
+		return namespace, configData
+	}
+	doc := docs[0]
+	if value, ok := doc.Path(NamespaceProperty).Data().(string); ok && value != "" {
+		namespace = value
+	}
+	configHubField := strings.TrimSuffix(configHubPrefix, ".")
+	if !doc.Exists(configHubField) {
+		return namespace, configData
+	}
+	_ = doc.Delete(configHubField)
+	return namespace, doc.String()
+}
+
+func (w *ConfigMapBridgeWorker) transformAppConfigToConfigMap(payload *api.BridgeWorkerPayload) {
+	var namespace, configData, fileExtension string
+	switch w.toolchainType() {
+	case workerapi.ToolchainAppConfigYAML:
+		namespace, configData = extractYAMLNamespace(string(payload.Data))
+		fileExtension = ".yaml"
+	case workerapi.ToolchainAnsibleVars:
+		// The vars files are stored as is, such as for use by ansible-runner in the cluster
+		namespace, configData = "default", string(payload.Data)
+		fileExtension = ".yml"
+	default:
+		namespace, configData = extractPropertiesNamespace(string(payload.Data))
+		fileExtension = ".properties"
+	}
 	nameSuffix := truncateString(fmt.Sprintf("%x", sha256.Sum256(payload.Data)), 10)
 	args := &configMapTemplateArgs{
 		// TODO: ensure slug character set is valid
@@ -115,7 +164,7 @@ func transformAppConfigToConfigMap(payload *api.BridgeWorkerPayload) {
 		Namespace:   namespace,
 		Label:       payload.UnitSlug,
 		RevisionNum: fmt.Sprintf("%d", payload.RevisionNum),
-		DataName:    payload.UnitSlug + ".properties", // TODO: support other AppConfig types
+		DataName:    payload.UnitSlug + fileExtension,
 		ConfigData:  configData,
 	}
 	configMap := generateConfigMapFromData(args)
@@ -123,13 +172,13 @@ func transformAppConfigToConfigMap(payload *api.BridgeWorkerPayload) {
 }
 
 func (w *ConfigMapBridgeWorker) Apply(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
-	transformAppConfigToConfigMap(&payload)
+	w.transformAppConfigToConfigMap(&payload)
 	// TODO: GC configmaps more than a designated amount
 	return w.KubernetesBridgeWorker.Apply(wctx, payload)
 }
 
 func (w *ConfigMapBridgeWorker) WatchForApply(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
-	transformAppConfigToConfigMap(&payload)
+	w.transformAppConfigToConfigMap(&payload)
 	return w.KubernetesBridgeWorker.WatchForApply(wctx, payload)
 }
 
@@ -153,16 +202,21 @@ func (w *ConfigMapBridgeWorker) Import(wctx api.BridgeWorkerContext, payload api
 
 func (w *ConfigMapBridgeWorker) Destroy(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
 	// TODO: delete all generated configmaps
-	transformAppConfigToConfigMap(&payload)
+	w.transformAppConfigToConfigMap(&payload)
 	return w.KubernetesBridgeWorker.Destroy(wctx, payload)
 }
 
 func (w *ConfigMapBridgeWorker) WatchForDestroy(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
 	// TODO: delete all generated configmaps
-	transformAppConfigToConfigMap(&payload)
+	w.transformAppConfigToConfigMap(&payload)
 	return w.KubernetesBridgeWorker.WatchForDestroy(wctx, payload)
 }
 
+func (w *ConfigMapBridgeWorker) Plan(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	w.transformAppConfigToConfigMap(&payload)
+	return w.KubernetesBridgeWorker.Plan(wctx, payload)
+}
+
 func (w *ConfigMapBridgeWorker) Finalize(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
 	return w.KubernetesBridgeWorker.Finalize(wctx, payload)
 }
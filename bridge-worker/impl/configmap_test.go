@@ -0,0 +1,81 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+	"github.com/confighub/sdk/third_party/gaby"
+	"github.com/confighub/sdk/workerapi"
+)
+
+func TestConfigMapBridgeWorker_TransformYAML(t *testing.T) {
+	worker := &ConfigMapBridgeWorker{ToolchainType: workerapi.ToolchainAppConfigYAML}
+	payload := api.BridgeWorkerPayload{
+		UnitSlug:    "payments",
+		RevisionNum: 3,
+		Data: []byte(`configHub:
+  configName: payments-config
+  kubernetes:
+    namespace: payments
+service:
+  port: 8080
+`),
+	}
+	worker.transformAppConfigToConfigMap(&payload)
+
+	docs, err := gaby.ParseAll(payload.Data)
+	assert.NoError(t, err)
+	if !assert.Len(t, docs, 1) {
+		return
+	}
+	configMap := docs[0]
+	assert.Equal(t, "ConfigMap", configMap.Path("kind").Data())
+	assert.Equal(t, "payments", configMap.Path("metadata.namespace").Data())
+	data, ok := configMap.Path("data.payments~1yaml").Data().(string)
+	if assert.True(t, ok) {
+		assert.Equal(t, "service:\n  port: 8080\n", data)
+	}
+}
+
+func TestConfigMapBridgeWorker_TransformProperties(t *testing.T) {
+	worker := &ConfigMapBridgeWorker{}
+	payload := api.BridgeWorkerPayload{
+		UnitSlug: "payments",
+		Data:     []byte("configHub.kubernetes.namespace=payments\nservice.port=8080\n"),
+	}
+	worker.transformAppConfigToConfigMap(&payload)
+
+	docs, err := gaby.ParseAll(payload.Data)
+	assert.NoError(t, err)
+	if !assert.Len(t, docs, 1) {
+		return
+	}
+	assert.Equal(t, "payments", docs[0].Path("metadata.namespace").Data())
+	data, ok := docs[0].Path("data.payments~1properties").Data().(string)
+	if assert.True(t, ok) {
+		assert.Contains(t, data, "#configHub.kubernetes.namespace=payments")
+	}
+}
+
+func TestConfigMapBridgeWorker_TransformAnsibleVars(t *testing.T) {
+	worker := &ConfigMapBridgeWorker{ToolchainType: workerapi.ToolchainAnsibleVars}
+	varsData := "confighub_group: webservers\nnginx:\n  worker_processes: 4\n---\nconfighub_host: web1\n"
+	payload := api.BridgeWorkerPayload{
+		UnitSlug: "inventory",
+		Data:     []byte(varsData),
+	}
+	worker.transformAppConfigToConfigMap(&payload)
+
+	docs, err := gaby.ParseAll(payload.Data)
+	assert.NoError(t, err)
+	if !assert.Len(t, docs, 1) {
+		return
+	}
+	assert.Equal(t, "default", docs[0].Path("metadata.namespace").Data())
+	assert.Equal(t, varsData, docs[0].Path("data.inventory~1yml").Data())
+}
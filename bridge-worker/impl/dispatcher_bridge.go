@@ -6,7 +6,11 @@ package impl
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/google/uuid"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -23,25 +27,30 @@ type WorkerKey struct {
 // BridgeDispatcher is a bridge worker that delegates operations to registered workers
 // based on the toolchain and provider information in the request payload
 // It ensures operations on the same unit are processed sequentially
+// Apply and Destroy operations that were already completed, such as those re-sent by the server
+// after a reconnect, are not executed again
 type BridgeDispatcher struct {
 	mu              sync.RWMutex
 	workers         map[WorkerKey]api.BridgeWorker
 	ctx             context.Context
 	cancel          context.CancelFunc
 	disablePrefixes bool // Compatibility mode - disable target prefixes
+	operations      *operationCache
 }
 
 // Ensure Dispatcher implements the BridgeWorker interface
 var _ api.BridgeWorker = (*BridgeDispatcher)(nil)
+var _ api.DiagnosableWorker = (*BridgeDispatcher)(nil)
 
 // NewBridgeDispatcher creates a new Dispatcher instance with unit queue management
 func NewBridgeDispatcher() *BridgeDispatcher {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	d := &BridgeDispatcher{
-		workers: make(map[WorkerKey]api.BridgeWorker),
-		ctx:     ctx,
-		cancel:  cancel,
+		workers:    make(map[WorkerKey]api.BridgeWorker),
+		ctx:        ctx,
+		cancel:     cancel,
+		operations: newOperationCache(DefaultOperationCacheSize, DefaultOperationCacheTTL),
 	}
 
 	return d
@@ -54,6 +63,42 @@ func (d *BridgeDispatcher) SetDisablePrefixes(disable bool) {
 	d.disablePrefixes = disable
 }
 
+// SetOperationCache configures the number of completed operations remembered in order to skip
+// duplicate requests and how long they are remembered. A size of zero disables deduplication.
+func (d *BridgeDispatcher) SetOperationCache(size int, ttl time.Duration) {
+	d.operations.configure(size, ttl)
+}
+
+// deduplicate runs the operation unless an operation with the same action and QueuedOperationID
+// already completed successfully, in which case the last status it sent is re-sent without
+// executing it again. Failed operations aren't remembered, so that they can be retried.
+func (d *BridgeDispatcher) deduplicate(action api.ActionType, ctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload, run func(api.BridgeWorkerContext) error) error {
+	if payload.QueuedOperationID == uuid.Nil {
+		return run(ctx)
+	}
+	key := operationKey{action: action, queuedOperationID: payload.QueuedOperationID}
+	if op, ok := d.operations.get(key); ok {
+		log.Log.Info("Skipping duplicate operation",
+			"action", action,
+			"queuedOperationID", payload.QueuedOperationID,
+			"unitSlug", payload.UnitSlug,
+			"unitID", payload.UnitID)
+		if op.lastStatus != nil {
+			status := *op.lastStatus
+			return ctx.SendStatus(&status)
+		}
+		return nil
+	}
+
+	recorder := &recordingBridgeWorkerContext{BridgeWorkerContext: ctx}
+	err := run(recorder)
+	lastStatus := recorder.recordedStatus()
+	if err == nil && (lastStatus == nil || lastStatus.Status != api.ActionStatusFailed) {
+		d.operations.add(&completedOperation{key: key, lastStatus: lastStatus})
+	}
+	return err
+}
+
 // RegisterWorker registers a bridge worker for a specific toolchain and provider combination
 func (d *BridgeDispatcher) RegisterWorker(toolchainType workerapi.ToolchainType, providerType api.ProviderType, worker api.BridgeWorker) {
 	d.mu.Lock()
@@ -78,6 +123,36 @@ func (d *BridgeDispatcher) getWorker(toolchainType workerapi.ToolchainType, prov
 	return worker, nil
 }
 
+// Diagnose returns the checks of the registered workers that implement api.DiagnosableWorker,
+// with the name of each check prefixed by the toolchain and provider of its worker.
+func (d *BridgeDispatcher) Diagnose(ctx context.Context) api.DiagnosticResult {
+	d.mu.RLock()
+	keys := make([]WorkerKey, 0, len(d.workers))
+	workers := make(map[WorkerKey]api.DiagnosableWorker, len(d.workers))
+	for key, worker := range d.workers {
+		if diagnosable, ok := worker.(api.DiagnosableWorker); ok {
+			keys = append(keys, key)
+			workers[key] = diagnosable
+		}
+	}
+	d.mu.RUnlock()
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ToolchainType != keys[j].ToolchainType {
+			return keys[i].ToolchainType < keys[j].ToolchainType
+		}
+		return keys[i].ProviderType < keys[j].ProviderType
+	})
+
+	var result api.DiagnosticResult
+	for _, key := range keys {
+		for _, check := range workers[key].Diagnose(ctx).Checks {
+			check.Name = fmt.Sprintf("%s %s: %s", key.ToolchainType, key.ProviderType, check.Name)
+			result.Checks = append(result.Checks, check)
+		}
+	}
+	return result
+}
+
 // getProviderPrefix returns the appropriate prefix for a given provider type
 func (d *BridgeDispatcher) getProviderPrefix(providerType api.ProviderType) string {
 	switch providerType {
@@ -108,9 +183,8 @@ func (d *BridgeDispatcher) Info(opts api.InfoOptions) api.BridgeWorkerInfo {
 		if !d.disablePrefixes {
 			prefix = d.getProviderPrefix(key.ProviderType)
 		}
-		opt := api.InfoOptions{
-			Slug: prefix + opts.Slug,
-		}
+		opt := opts
+		opt.Slug = prefix + opts.Slug
 		info := worker.Info(opt)
 		for _, configType := range info.SupportedConfigTypes {
 			supportedConfigTypes = append(supportedConfigTypes, configType)
@@ -136,7 +210,9 @@ func (d *BridgeDispatcher) Apply(ctx api.BridgeWorkerContext, payload api.Bridge
 		"unitSlug", payload.UnitSlug,
 		"unitID", payload.UnitID)
 
-	return worker.Apply(ctx, payload)
+	return d.deduplicate(api.ActionApply, ctx, payload, func(ctx api.BridgeWorkerContext) error {
+		return worker.Apply(ctx, payload)
+	})
 }
 
 // Refresh delegates the Refresh operation to the appropriate worker
@@ -187,7 +263,9 @@ func (d *BridgeDispatcher) Destroy(ctx api.BridgeWorkerContext, payload api.Brid
 		"unitSlug", payload.UnitSlug,
 		"unitID", payload.UnitID)
 
-	return worker.Destroy(ctx, payload)
+	return d.deduplicate(api.ActionDestroy, ctx, payload, func(ctx api.BridgeWorkerContext) error {
+		return worker.Destroy(ctx, payload)
+	})
 }
 
 // Finalize delegates the Finalize operation to the appropriate worker
@@ -207,6 +285,22 @@ func (d *BridgeDispatcher) Finalize(ctx api.BridgeWorkerContext, payload api.Bri
 	return worker.Finalize(ctx, payload)
 }
 
+// Plan delegates the Plan operation to the appropriate worker
+func (d *BridgeDispatcher) Plan(ctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	worker, err := d.getWorker(payload.ToolchainType, payload.ProviderType)
+	if err != nil {
+		return err
+	}
+
+	log.Log.Info("Executing Plan operation",
+		"toolchainType", payload.ToolchainType,
+		"providerType", payload.ProviderType,
+		"unitSlug", payload.UnitSlug,
+		"unitID", payload.UnitID)
+
+	return worker.Plan(ctx, payload)
+}
+
 func (d *BridgeDispatcher) WatchForApply(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
 	worker, err := d.getWorker(payload.ToolchainType, payload.ProviderType)
 	if err != nil {
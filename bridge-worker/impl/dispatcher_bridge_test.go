@@ -0,0 +1,193 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+	"github.com/confighub/sdk/workerapi"
+)
+
+// countingBridgeWorker counts the operations it executes and reports them as completed.
+type countingBridgeWorker struct {
+	api.PlanNotSupportedWorker
+	applies    int
+	destroys   int
+	destroyErr error
+	infoSlug   string
+}
+
+func (w *countingBridgeWorker) Info(opts api.InfoOptions) api.BridgeWorkerInfo {
+	w.infoSlug = opts.Slug
+	return api.BridgeWorkerInfo{}
+}
+
+func (w *countingBridgeWorker) Apply(wctx api.BridgeWorkerContext, _ api.BridgeWorkerPayload) error {
+	w.applies++
+	return wctx.SendStatus(&api.ActionResult{
+		ActionResultBaseMeta: api.ActionResultBaseMeta{
+			Status: api.ActionStatusCompleted,
+			Result: api.ActionResultApplyCompleted,
+		},
+	})
+}
+
+func (w *countingBridgeWorker) Destroy(_ api.BridgeWorkerContext, _ api.BridgeWorkerPayload) error {
+	w.destroys++
+	return w.destroyErr
+}
+
+func (*countingBridgeWorker) Refresh(api.BridgeWorkerContext, api.BridgeWorkerPayload) error {
+	return nil
+}
+
+func (*countingBridgeWorker) Import(api.BridgeWorkerContext, api.BridgeWorkerPayload) error {
+	return nil
+}
+
+func (*countingBridgeWorker) Finalize(api.BridgeWorkerContext, api.BridgeWorkerPayload) error {
+	return nil
+}
+
+type statusRecordingContext struct {
+	statuses []*api.ActionResult
+}
+
+func (*statusRecordingContext) Context() context.Context { return context.Background() }
+func (*statusRecordingContext) GetServerURL() string     { return "" }
+func (*statusRecordingContext) GetWorkerID() string      { return "" }
+func (c *statusRecordingContext) SendStatus(result *api.ActionResult) error {
+	c.statuses = append(c.statuses, result)
+	return nil
+}
+
+func newTestDispatcher(worker api.BridgeWorker) *BridgeDispatcher {
+	d := NewBridgeDispatcher()
+	d.RegisterWorker(workerapi.ToolchainKubernetesYAML, api.ProviderKubernetes, worker)
+	return d
+}
+
+func testDispatcherPayload(operationID uuid.UUID) api.BridgeWorkerPayload {
+	return api.BridgeWorkerPayload{
+		QueuedOperationID: operationID,
+		ToolchainType:     workerapi.ToolchainKubernetesYAML,
+		ProviderType:      api.ProviderKubernetes,
+		UnitSlug:          "test-unit",
+	}
+}
+
+func TestBridgeDispatcher_DeduplicatesOperations(t *testing.T) {
+	worker := &countingBridgeWorker{}
+	d := newTestDispatcher(worker)
+	wctx := &statusRecordingContext{}
+	payload := testDispatcherPayload(uuid.New())
+
+	assert.NoError(t, d.Apply(wctx, payload))
+	assert.NoError(t, d.Apply(wctx, payload))
+	assert.Equal(t, 1, worker.applies)
+	// The completed status is re-sent for the duplicate
+	if assert.Len(t, wctx.statuses, 2) {
+		assert.Equal(t, api.ActionResultApplyCompleted, wctx.statuses[1].Result)
+	}
+
+	// A different operation or action is executed
+	assert.NoError(t, d.Apply(wctx, testDispatcherPayload(uuid.New())))
+	assert.NoError(t, d.Destroy(wctx, payload))
+	assert.Equal(t, 2, worker.applies)
+	assert.Equal(t, 1, worker.destroys)
+
+	// Operations without an ID are always executed
+	assert.NoError(t, d.Apply(wctx, testDispatcherPayload(uuid.Nil)))
+	assert.NoError(t, d.Apply(wctx, testDispatcherPayload(uuid.Nil)))
+	assert.Equal(t, 4, worker.applies)
+}
+
+func TestBridgeDispatcher_RetriesFailedOperations(t *testing.T) {
+	worker := &countingBridgeWorker{destroyErr: errors.New("destroy failed")}
+	d := newTestDispatcher(worker)
+	payload := testDispatcherPayload(uuid.New())
+
+	assert.EqualError(t, d.Destroy(&statusRecordingContext{}, payload), "destroy failed")
+	assert.EqualError(t, d.Destroy(&statusRecordingContext{}, payload), "destroy failed")
+	assert.Equal(t, 2, worker.destroys)
+
+	// Once the operation succeeds, it's remembered
+	worker.destroyErr = nil
+	assert.NoError(t, d.Destroy(&statusRecordingContext{}, payload))
+	assert.NoError(t, d.Destroy(&statusRecordingContext{}, payload))
+	assert.Equal(t, 3, worker.destroys)
+}
+
+func TestBridgeDispatcher_OperationCacheExpiryAndEviction(t *testing.T) {
+	worker := &countingBridgeWorker{}
+	d := newTestDispatcher(worker)
+	d.SetOperationCache(2, time.Minute)
+	now := time.Now()
+	d.operations.now = func() time.Time { return now }
+	wctx := &statusRecordingContext{}
+
+	first := testDispatcherPayload(uuid.New())
+	assert.NoError(t, d.Apply(wctx, first))
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, d.Apply(wctx, first))
+	assert.Equal(t, 2, worker.applies, "expired operation should be executed again")
+
+	second := testDispatcherPayload(uuid.New())
+	third := testDispatcherPayload(uuid.New())
+	assert.NoError(t, d.Apply(wctx, second))
+	assert.NoError(t, d.Apply(wctx, third))
+	assert.NoError(t, d.Apply(wctx, first))
+	assert.Equal(t, 5, worker.applies, "least recently used operation should be evicted")
+	assert.NoError(t, d.Apply(wctx, third))
+	assert.Equal(t, 5, worker.applies)
+
+	d.SetOperationCache(0, time.Minute)
+	assert.NoError(t, d.Apply(wctx, third))
+	assert.Equal(t, 6, worker.applies, "a size of zero disables deduplication")
+}
+
+func TestBridgeDispatcher_InfoPrefixes(t *testing.T) {
+	worker := &countingBridgeWorker{}
+	d := newTestDispatcher(worker)
+
+	d.Info(api.InfoOptions{Slug: "worker"})
+	assert.Equal(t, "k8s-worker", worker.infoSlug)
+
+	d.SetDisablePrefixes(true)
+	d.Info(api.InfoOptions{Slug: "worker"})
+	assert.Equal(t, "worker", worker.infoSlug)
+}
+
+// diagnosableCountingWorker reports a single check.
+type diagnosableCountingWorker struct {
+	countingBridgeWorker
+	passed bool
+}
+
+func (w *diagnosableCountingWorker) Diagnose(context.Context) api.DiagnosticResult {
+	return api.DiagnosticResult{Checks: []api.DiagnosticCheck{{Name: "cluster", Passed: w.passed}}}
+}
+
+func TestBridgeDispatcher_Diagnose(t *testing.T) {
+	d := newTestDispatcher(&diagnosableCountingWorker{passed: true})
+	// Workers that aren't diagnosable don't contribute checks
+	d.RegisterWorker(workerapi.ToolchainKubernetesYAML, api.ProviderConfigMap, &countingBridgeWorker{})
+	d.RegisterWorker(workerapi.ToolchainKubernetesYAML, api.ProviderFluxOCIWriter, &diagnosableCountingWorker{passed: false})
+
+	result := d.Diagnose(context.Background())
+	assert.False(t, result.Passed())
+	assert.Equal(t, []api.DiagnosticCheck{
+		{Name: "Kubernetes/YAML FluxOCIWriter: cluster", Passed: false},
+		{Name: "Kubernetes/YAML Kubernetes: cluster", Passed: true},
+	}, result.Checks)
+
+	assert.Empty(t, newTestDispatcher(&countingBridgeWorker{}).Diagnose(context.Background()).Checks)
+}
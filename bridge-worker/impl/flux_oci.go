@@ -292,6 +292,7 @@ func ParseFluxOCIParams(payload api.BridgeWorkerPayload) (*FluxOCIParams, error)
 }
 
 func (f FluxOCIWorker) Apply(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	logger := log.FromContext(wctx.Context())
 	params, err := ParseFluxOCIParams(payload)
 	if err != nil {
 		wctx.SendStatus(newActionResult(
@@ -338,7 +339,7 @@ func (f FluxOCIWorker) Apply(wctx api.BridgeWorkerContext, payload api.BridgeWor
 
 	digest, err := pushFunc(cli, tarGz, url, tags...)
 	if err != nil {
-		log.Log.Error(err, "Failed to push to registry")
+		logger.Error(err, "Failed to push to registry")
 		wctx.SendStatus(newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultApplyFailed,
@@ -363,7 +364,7 @@ func (f FluxOCIWorker) Apply(wctx api.BridgeWorkerContext, payload api.BridgeWor
 
 	jsonOutputs, err := json.Marshal(applyOutputs)
 	if err != nil {
-		log.Log.Error(err, "Failed to marshal outputs")
+		logger.Error(err, "Failed to marshal outputs")
 		status := newActionResult(
 			api.ActionStatusCompleted,
 			api.ActionResultApplyCompleted,
@@ -385,6 +386,7 @@ func (f FluxOCIWorker) Apply(wctx api.BridgeWorkerContext, payload api.BridgeWor
 }
 
 func (f FluxOCIWorker) Refresh(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	logger := log.FromContext(wctx.Context())
 	params, err := ParseFluxOCIParams(payload)
 	if err != nil {
 		wctx.SendStatus(newActionResult(
@@ -416,7 +418,7 @@ func (f FluxOCIWorker) Refresh(wctx api.BridgeWorkerContext, payload api.BridgeW
 	url := params.Repository + ":" + params.Tag
 	img, err := crane.Pull(url, cli.GetOptions()...)
 	if err != nil {
-		log.Log.Error(err, "Failed to pull image")
+		logger.Error(err, "Failed to pull image")
 		wctx.SendStatus(newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultRefreshFailed,
@@ -427,7 +429,7 @@ func (f FluxOCIWorker) Refresh(wctx api.BridgeWorkerContext, payload api.BridgeW
 
 	layers, err := img.Layers()
 	if err != nil {
-		log.Log.Error(err, "Failed to get layers")
+		logger.Error(err, "Failed to get layers")
 		wctx.SendStatus(newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultRefreshFailed,
@@ -437,7 +439,7 @@ func (f FluxOCIWorker) Refresh(wctx api.BridgeWorkerContext, payload api.BridgeW
 	}
 
 	if len(layers) == 0 {
-		log.Log.Error(err, "No layers found in image")
+		logger.Error(err, "No layers found in image")
 		wctx.SendStatus(newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultRefreshFailed,
@@ -450,7 +452,7 @@ func (f FluxOCIWorker) Refresh(wctx api.BridgeWorkerContext, payload api.BridgeW
 	layer := layers[0]
 	rc, err := layer.Uncompressed()
 	if err != nil {
-		log.Log.Error(err, "Failed to uncompress layer")
+		logger.Error(err, "Failed to uncompress layer")
 		wctx.SendStatus(newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultRefreshFailed,
@@ -467,7 +469,7 @@ func (f FluxOCIWorker) Refresh(wctx api.BridgeWorkerContext, payload api.BridgeW
 			break
 		}
 		if err != nil {
-			log.Log.Error(err, "Failed to read tar")
+			logger.Error(err, "Failed to read tar")
 			wctx.SendStatus(newActionResult(
 				api.ActionStatusFailed,
 				api.ActionResultRefreshFailed,
@@ -479,7 +481,7 @@ func (f FluxOCIWorker) Refresh(wctx api.BridgeWorkerContext, payload api.BridgeW
 		if header.Name == "manifest.yaml" {
 			content, err := io.ReadAll(tr)
 			if err != nil {
-				log.Log.Error(err, "Failed to read manifest")
+				logger.Error(err, "Failed to read manifest")
 				wctx.SendStatus(newActionResult(
 					api.ActionStatusFailed,
 					api.ActionResultRefreshFailed,
@@ -498,7 +500,7 @@ func (f FluxOCIWorker) Refresh(wctx api.BridgeWorkerContext, payload api.BridgeW
 		}
 	}
 
-	log.Log.Error(err, "Manifest not found in image")
+	logger.Error(err, "Manifest not found in image")
 	wctx.SendStatus(newActionResult(
 		api.ActionStatusFailed,
 		api.ActionResultRefreshFailed,
@@ -508,6 +510,7 @@ func (f FluxOCIWorker) Refresh(wctx api.BridgeWorkerContext, payload api.BridgeW
 }
 
 func (f FluxOCIWorker) Import(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	logger := log.FromContext(wctx.Context())
 	params, err := ParseFluxOCIParams(payload)
 	if err != nil {
 		wctx.SendStatus(newActionResult(
@@ -539,7 +542,7 @@ func (f FluxOCIWorker) Import(wctx api.BridgeWorkerContext, payload api.BridgeWo
 	url := params.Repository + ":" + params.Tag
 	img, err := crane.Pull(url, cli.GetOptions()...)
 	if err != nil {
-		log.Log.Error(err, "Failed to pull image")
+		logger.Error(err, "Failed to pull image")
 		wctx.SendStatus(newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultImportFailed,
@@ -550,7 +553,7 @@ func (f FluxOCIWorker) Import(wctx api.BridgeWorkerContext, payload api.BridgeWo
 
 	layers, err := img.Layers()
 	if err != nil {
-		log.Log.Error(err, "Failed to get layers")
+		logger.Error(err, "Failed to get layers")
 		wctx.SendStatus(newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultImportFailed,
@@ -560,7 +563,7 @@ func (f FluxOCIWorker) Import(wctx api.BridgeWorkerContext, payload api.BridgeWo
 	}
 
 	if len(layers) == 0 {
-		log.Log.Error(err, "No layers found in image")
+		logger.Error(err, "No layers found in image")
 		wctx.SendStatus(newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultImportFailed,
@@ -573,7 +576,7 @@ func (f FluxOCIWorker) Import(wctx api.BridgeWorkerContext, payload api.BridgeWo
 	layer := layers[0]
 	rc, err := layer.Uncompressed()
 	if err != nil {
-		log.Log.Error(err, "Failed to uncompress layer")
+		logger.Error(err, "Failed to uncompress layer")
 		wctx.SendStatus(newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultImportFailed,
@@ -590,7 +593,7 @@ func (f FluxOCIWorker) Import(wctx api.BridgeWorkerContext, payload api.BridgeWo
 			break
 		}
 		if err != nil {
-			log.Log.Error(err, "Failed to read tar")
+			logger.Error(err, "Failed to read tar")
 			wctx.SendStatus(newActionResult(
 				api.ActionStatusFailed,
 				api.ActionResultImportFailed,
@@ -602,7 +605,7 @@ func (f FluxOCIWorker) Import(wctx api.BridgeWorkerContext, payload api.BridgeWo
 		if header.Name == "manifest.yaml" {
 			content, err := io.ReadAll(tr)
 			if err != nil {
-				log.Log.Error(err, "Failed to read manifest")
+				logger.Error(err, "Failed to read manifest")
 				wctx.SendStatus(newActionResult(
 					api.ActionStatusFailed,
 					api.ActionResultImportFailed,
@@ -624,7 +627,7 @@ func (f FluxOCIWorker) Import(wctx api.BridgeWorkerContext, payload api.BridgeWo
 		}
 	}
 
-	log.Log.Error(err, "Manifest not found in image")
+	logger.Error(err, "Manifest not found in image")
 	wctx.SendStatus(newActionResult(
 		api.ActionStatusFailed,
 		api.ActionResultImportFailed,
@@ -698,6 +701,7 @@ func (f FluxOCIWorker) Destroy(wctx api.BridgeWorkerContext, payload api.BridgeW
 }
 
 func (f FluxOCIWorker) Finalize(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	logger := log.FromContext(wctx.Context())
 	if err := wctx.SendStatus(newActionResult(
 		api.ActionStatusProgressing,
 		api.ActionResultNone,
@@ -706,7 +710,7 @@ func (f FluxOCIWorker) Finalize(wctx api.BridgeWorkerContext, payload api.Bridge
 		return err
 	}
 
-	log.Log.Info("✅ Finalization completed successfully")
+	logger.Info("✅ Finalization completed successfully")
 
 	result := newActionResult(
 		api.ActionStatusCompleted,
@@ -715,3 +719,9 @@ func (f FluxOCIWorker) Finalize(wctx api.BridgeWorkerContext, payload api.Bridge
 	)
 	return wctx.SendStatus(result)
 }
+
+// Plan reports the changes pushing the configuration data would make to the previously pushed
+// manifests. Since the whole manifest is replaced, removed resources are reported as deleted.
+func (f FluxOCIWorker) Plan(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	return sendPlan(wctx, payload, true)
+}
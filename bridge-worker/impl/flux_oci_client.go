@@ -92,7 +92,7 @@ func NewFluxOCIWorkerConfig(worker *FluxOCIWorker, inCluster bool, authMethod, k
 	creds := ""
 	if authMethod == AuthMethodKubernetes && k8sSecretPath != "" {
 		var err error
-		creds, err = validateK8sSecretPath(k8sSecretPath)
+		creds, err = validateK8sSecretPath(context.Background(), k8sSecretPath)
 		if err != nil {
 			return fmt.Errorf("invalid Kubernetes secret path: %w", err)
 		}
@@ -106,7 +106,7 @@ func NewFluxOCIWorkerConfig(worker *FluxOCIWorker, inCluster bool, authMethod, k
 	return nil
 }
 
-func validateK8sSecretPath(k8sSecretPath string) (string, error) {
+func validateK8sSecretPath(ctx context.Context, k8sSecretPath string) (string, error) {
 	// Check for `.dockerconfigjson` file
 	dockerConfigJSONPath := filepath.Join(k8sSecretPath, ".dockerconfigjson")
 	if _, err := os.Stat(dockerConfigJSONPath); err == nil {
@@ -123,7 +123,7 @@ func validateK8sSecretPath(k8sSecretPath string) (string, error) {
 		}
 
 		// reuse to parse credentials
-		return ExtractCredentialsFromSecret(secret), nil
+		return ExtractCredentialsFromSecret(ctx, secret), nil
 	}
 
 	// Fallback to `username` and `password` files
@@ -266,6 +266,7 @@ func TryAuth(auths map[string]DockerAuth, key string) string {
 // 2) Docker config.json base64 auth
 // 3) Cloud-native provider if specified
 func LoginToRegistry(ctx context.Context, workerConfig *FluxOCIWorkerConfig, params *FluxOCIParams, newClientFunc NewClientFunc) (OCIClient, error) {
+	logger := log.FromContext(ctx)
 	var cred string
 	var provider oci.Provider
 
@@ -278,7 +279,7 @@ func LoginToRegistry(ctx context.Context, workerConfig *FluxOCIWorkerConfig, par
 		if err := cli.LoginWithProvider(ctx, url, provider); err == nil {
 			return cli, nil
 		}
-		log.Log.Info("Cloud provider authentication failed, falling back", "provider", params.Provider)
+		logger.Info("Cloud provider authentication failed, falling back", "provider", params.Provider)
 	}
 
 	// 2. Attempt Kubernetes secret credentials
@@ -289,11 +290,11 @@ func LoginToRegistry(ctx context.Context, workerConfig *FluxOCIWorkerConfig, par
 			if err := cli.LoginWithCredentials(cred); err == nil {
 				return cli, nil
 			}
-			log.Log.Info("Kubernetes secret name and namespace authentication failed, falling back",
+			logger.Info("Kubernetes secret name and namespace authentication failed, falling back",
 				"secretName", params.KubernetesSecretName,
 				"namespace", params.KubernetesSecretNamespace)
 		} else {
-			log.Log.Info("Failed to load Kubernetes secret credentials from params, falling back")
+			logger.Info("Failed to load Kubernetes secret credentials from params, falling back")
 		}
 	}
 
@@ -305,13 +306,13 @@ func LoginToRegistry(ctx context.Context, workerConfig *FluxOCIWorkerConfig, par
 		} else {
 			cfg, err := rest.InClusterConfig()
 			if err != nil {
-				log.Log.Info("Failed to load in-cluster configuration", "error", err.Error())
+				logger.Info("Failed to load in-cluster configuration", "error", err.Error())
 				break
 			}
 
 			k8sClient, err := ctrlclient.New(cfg, ctrlclient.Options{})
 			if err != nil {
-				log.Log.Info("Failed to create Kubernetes client", "error", err.Error())
+				logger.Info("Failed to create Kubernetes client", "error", err.Error())
 				break
 			}
 			cred = GetCredentialsFromImagePullSecrets(ctx, k8sClient)
@@ -338,7 +339,7 @@ func LoginToRegistry(ctx context.Context, workerConfig *FluxOCIWorkerConfig, par
 			}
 		}
 	default:
-		cred = GetDefaultKeychainCredentials(params, authn.DefaultKeychain)
+		cred = GetDefaultKeychainCredentials(ctx, params, authn.DefaultKeychain)
 		if cred != "" {
 			cli := newClientFunc()
 			if err := cli.LoginWithCredentials(cred); err == nil {
@@ -350,7 +351,7 @@ func LoginToRegistry(ctx context.Context, workerConfig *FluxOCIWorkerConfig, par
 	return nil, fmt.Errorf("all authentication methods failed")
 }
 
-func GetDefaultKeychainCredentials(params *FluxOCIParams, keychain authn.Keychain) string {
+func GetDefaultKeychainCredentials(ctx context.Context, params *FluxOCIParams, keychain authn.Keychain) string {
 	ref, err := name.ParseReference(params.Repository + ":" + params.Tag)
 	if err != nil {
 		return ""
@@ -367,7 +368,7 @@ func GetDefaultKeychainCredentials(params *FluxOCIParams, keychain authn.Keychai
 
 	ac, authErr := authnAuth.Authorization()
 	if authErr != nil {
-		log.Log.Info("Keychain authentication failed", "error", authErr.Error())
+		log.FromContext(ctx).Info("Keychain authentication failed", "error", authErr.Error())
 		return ""
 	}
 
@@ -388,46 +389,48 @@ func GetCloudProvider(provider string) oci.Provider {
 }
 
 func GetK8sSecretCredentials(ctx context.Context, params *FluxOCIParams) string {
+	logger := log.FromContext(ctx)
 	if params.KubernetesSecretName == "" || params.KubernetesSecretNamespace == "" {
 		return ""
 	}
 
 	cfg, err := ctrlConfig.GetConfig()
 	if err != nil {
-		log.Log.Info("Kubernetes configuration retrieval failed", "error", err.Error())
+		logger.Info("Kubernetes configuration retrieval failed", "error", err.Error())
 		return ""
 	}
 
 	k8sClient, err := ctrlclient.New(cfg, ctrlclient.Options{})
 	if err != nil {
-		log.Log.Info("Kubernetes client creation failed", "error", err.Error())
+		logger.Info("Kubernetes client creation failed", "error", err.Error())
 		return ""
 	}
 
 	var secret corev1.Secret
 	key := k8stypes.NamespacedName{Name: params.KubernetesSecretName, Namespace: params.KubernetesSecretNamespace}
 	if err := k8sClient.Get(ctx, key, &secret); err != nil {
-		log.Log.Info("Kubernetes secret retrieval failed", "error", err.Error())
+		logger.Info("Kubernetes secret retrieval failed", "error", err.Error())
 		return ""
 	}
 
-	return ExtractCredentialsFromSecret(secret)
+	return ExtractCredentialsFromSecret(ctx, secret)
 }
 
-func ExtractCredentialsFromSecret(secret corev1.Secret) string {
+func ExtractCredentialsFromSecret(ctx context.Context, secret corev1.Secret) string {
+	logger := log.FromContext(ctx)
 	// Check for `.dockerconfigjson` key
 	if dockerConfigJSON, ok := secret.Data[".dockerconfigjson"]; ok {
 		decoded, err := base64.StdEncoding.DecodeString(string(dockerConfigJSON))
 		// When mounting a secret as a volume or environment variable,
 		// the kubernetes decodes the base64 string.
 		if err != nil {
-			log.Log.Info("Failed to base64 decode .dockerconfigjson. Attempting JSON unmarshal", "error", err.Error())
+			logger.Info("Failed to base64 decode .dockerconfigjson. Attempting JSON unmarshal", "error", err.Error())
 			decoded = dockerConfigJSON
 		}
 
 		var dockerConfig DockerConfig
 		if err := json.Unmarshal(decoded, &dockerConfig); err != nil {
-			log.Log.Info("Failed to parse .dockerconfigjson", "error", err.Error())
+			logger.Info("Failed to parse .dockerconfigjson", "error", err.Error())
 			return ""
 		}
 
@@ -446,11 +449,12 @@ func ExtractCredentialsFromSecret(secret corev1.Secret) string {
 		}
 	}
 
-	log.Log.Info("No valid credentials found in Kubernetes secret", "name", secret.Name, "namespace", secret.Namespace)
+	logger.Info("No valid credentials found in Kubernetes secret", "name", secret.Name, "namespace", secret.Namespace)
 	return ""
 }
 
 func GetCredentialsFromImagePullSecrets(ctx context.Context, k8sClient ctrlclient.Client) string {
+	logger := log.FromContext(ctx)
 	// Get the service account associated with the pod
 	namespace := os.Getenv("POD_NAMESPACE")
 	if namespace == "" {
@@ -458,13 +462,13 @@ func GetCredentialsFromImagePullSecrets(ctx context.Context, k8sClient ctrlclien
 	}
 	podName := os.Getenv("POD_NAME")
 	if podName == "" {
-		log.Log.Info("POD_NAME environment variable is not set")
+		logger.Info("POD_NAME environment variable is not set")
 		return ""
 	}
 
 	var pod corev1.Pod
 	if err := k8sClient.Get(ctx, k8stypes.NamespacedName{Name: podName, Namespace: namespace}, &pod); err != nil {
-		log.Log.Info("Failed to retrieve pod information", "error", err.Error())
+		logger.Info("Failed to retrieve pod information", "error", err.Error())
 		return ""
 	}
 
@@ -475,7 +479,7 @@ func GetCredentialsFromImagePullSecrets(ctx context.Context, k8sClient ctrlclien
 
 	var serviceAccount corev1.ServiceAccount
 	if err := k8sClient.Get(ctx, k8stypes.NamespacedName{Name: serviceAccountName, Namespace: namespace}, &serviceAccount); err != nil {
-		log.Log.Info("Failed to retrieve service account", "error", err.Error())
+		logger.Info("Failed to retrieve service account", "error", err.Error())
 		return ""
 	}
 
@@ -483,16 +487,16 @@ func GetCredentialsFromImagePullSecrets(ctx context.Context, k8sClient ctrlclien
 	for _, pullSecret := range serviceAccount.ImagePullSecrets {
 		var secret corev1.Secret
 		if err := k8sClient.Get(ctx, k8stypes.NamespacedName{Name: pullSecret.Name, Namespace: namespace}, &secret); err != nil {
-			log.Log.Info("Failed to retrieve imagePullSecret", "secretName", pullSecret.Name, "error", err.Error())
+			logger.Info("Failed to retrieve imagePullSecret", "secretName", pullSecret.Name, "error", err.Error())
 			continue
 		}
 
-		cred := ExtractCredentialsFromSecret(secret)
+		cred := ExtractCredentialsFromSecret(ctx, secret)
 		if cred != "" {
 			return cred
 		}
 	}
 
-	log.Log.Info("No valid credentials found in imagePullSecrets")
+	logger.Info("No valid credentials found in imagePullSecrets")
 	return ""
 }
@@ -125,7 +125,7 @@ func TestGetDefaultKeychainCredentials(t *testing.T) {
 	// Use the mock keychain
 	mockKeychain := &MockKeychain{}
 
-	cred := GetDefaultKeychainCredentials(params, mockKeychain)
+	cred := GetDefaultKeychainCredentials(context.Background(), params, mockKeychain)
 	assert.Equal(t, "user:pass", cred)
 }
 
@@ -137,7 +137,7 @@ func TestGetDefaultKeychainCredentials_Invalid(t *testing.T) {
 
 	mockKeychain := &MockKeychain{FailForInvalidRepo: true}
 
-	cred := GetDefaultKeychainCredentials(params, mockKeychain)
+	cred := GetDefaultKeychainCredentials(context.Background(), params, mockKeychain)
 	assert.Equal(t, "", cred)
 }
 
@@ -221,7 +221,7 @@ func TestExtractCredentialsFromSecret_DockerConfigJSON(t *testing.T) {
 		},
 	}
 
-	creds := ExtractCredentialsFromSecret(secret)
+	creds := ExtractCredentialsFromSecret(context.Background(), secret)
 	assert.Equal(t, "user:password", creds)
 }
 
@@ -234,7 +234,7 @@ func TestExtractCredentialsFromSecret_UsernamePassword(t *testing.T) {
 		},
 	}
 
-	creds := ExtractCredentialsFromSecret(secret)
+	creds := ExtractCredentialsFromSecret(context.Background(), secret)
 	assert.Equal(t, "user:password", creds)
 }
 
@@ -244,7 +244,7 @@ func TestExtractCredentialsFromSecret_NoCredentials(t *testing.T) {
 		Data: map[string][]byte{},
 	}
 
-	creds := ExtractCredentialsFromSecret(secret)
+	creds := ExtractCredentialsFromSecret(context.Background(), secret)
 	assert.Equal(t, "", creds)
 }
 
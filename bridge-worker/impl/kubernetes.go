@@ -74,6 +74,11 @@ func (w *KubernetesBridgeWorker) Info(opts api.InfoOptions) api.BridgeWorkerInfo
 
 // This supports ToolchainTypes and ProviderTypes that generate and apply Kubernetes resources.
 func (w *KubernetesBridgeWorker) InfoForToolchainAndProvider(opts api.InfoOptions, toolchain workerapi.ToolchainType, provider api.ProviderType) api.BridgeWorkerInfo {
+	return w.infoForToolchainAndProvider(context.Background(), opts, toolchain, provider)
+}
+
+// infoForToolchainAndProvider logs with the logger from ctx.
+func (w *KubernetesBridgeWorker) infoForToolchainAndProvider(ctx context.Context, opts api.InfoOptions, toolchain workerapi.ToolchainType, provider api.ProviderType) api.BridgeWorkerInfo {
 	// Get available contexts
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	k8sCmdConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
@@ -97,7 +102,7 @@ func (w *KubernetesBridgeWorker) InfoForToolchainAndProvider(opts api.InfoOption
 	// and we don't need to list available contexts
 	if cfg, err := rest.InClusterConfig(); err == nil {
 		w.cfg = cfg
-		log.Log.Info("Running inside Kubernetes cluster, using in-cluster configuration")
+		log.FromContext(ctx).Info("Running inside Kubernetes cluster, using in-cluster configuration")
 		targetName := os.Getenv("IN_CLUSTER_TARGET_NAME")
 		if targetName == "" {
 			targetName = opts.Slug
@@ -145,6 +150,7 @@ func (w *KubernetesBridgeWorker) InfoForToolchainAndProvider(opts api.InfoOption
 }
 
 func (w *KubernetesBridgeWorker) Apply(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	logger := log.FromContext(wctx.Context())
 	_, kubeContext, err := parseTargetParams(payload)
 	if err != nil {
 		return lib.SafeSendStatus(wctx, newActionResult(
@@ -173,7 +179,7 @@ func (w *KubernetesBridgeWorker) Apply(wctx api.BridgeWorkerContext, payload api
 	}
 
 	// If namespace is not declared in the unit, it will be set to default on namespaced resources
-	setDefaultNamespaceIfNotDeclared(objects, k8sclient)
+	setDefaultNamespaceIfNotDeclared(wctx.Context(), objects, k8sclient)
 
 	if err := wctx.SendStatus(newActionResult(
 		api.ActionStatusProgressing,
@@ -183,9 +189,9 @@ func (w *KubernetesBridgeWorker) Apply(wctx api.BridgeWorkerContext, payload api
 		return err
 	}
 
-	changeSet, err := man.ApplyAllStaged(context.Background(), objects, ssa.DefaultApplyOptions())
+	changeSet, err := man.ApplyAllStaged(wctx.Context(), objects, ssa.DefaultApplyOptions())
 	if err != nil {
-		log.Log.Error(err, "Failed to apply resources")
+		logger.Error(err, "Failed to apply resources")
 		return lib.SafeSendStatus(wctx, newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultApplyFailed,
@@ -193,7 +199,7 @@ func (w *KubernetesBridgeWorker) Apply(wctx api.BridgeWorkerContext, payload api
 		), err)
 	}
 
-	log.Log.Info("🔄 Applying resources...", "count", len(objects))
+	logger.Info("🔄 Applying resources...", "count", len(objects))
 	if err := wctx.SendStatus(newActionResult(
 		api.ActionStatusProgressing,
 		api.ActionResultNone,
@@ -202,11 +208,11 @@ func (w *KubernetesBridgeWorker) Apply(wctx api.BridgeWorkerContext, payload api
 		return err
 	}
 
-	log.Log.Info("✅ Successfully initiated applying resources", "changeset_entries", len(changeSet.Entries))
+	logger.Info("✅ Successfully initiated applying resources", "changeset_entries", len(changeSet.Entries))
 	return nil
 }
 
-func setDefaultNamespaceIfNotDeclared(objects []*unstructured.Unstructured, k8sclient KubernetesClient) {
+func setDefaultNamespaceIfNotDeclared(ctx context.Context, objects []*unstructured.Unstructured, k8sclient KubernetesClient) {
 	for _, obj := range objects {
 		// obj.GetNamespace() returns empty string for cluster scoped objects
 		// and namespaced objects where namespace is not set
@@ -214,7 +220,7 @@ func setDefaultNamespaceIfNotDeclared(objects []*unstructured.Unstructured, k8sc
 			// check if it is a namespaced object so we don't set namespace on cluster scoped objects
 			isns, err := k8sclient.IsObjectNamespaced(obj)
 			if err == nil && isns {
-				log.Log.Info("🔄 Setting namespace to default on ", "name", obj.GetName())
+				log.FromContext(ctx).Info("🔄 Setting namespace to default on ", "name", obj.GetName())
 				// This is currently not configurable.
 				obj.SetNamespace("default")
 			}
@@ -222,6 +228,17 @@ func setDefaultNamespaceIfNotDeclared(objects []*unstructured.Unstructured, k8sc
 	}
 }
 
+// waitTimeout returns the timeout for waiting for resources, shortened so that the wait ends by
+// the deadline of ctx, if any, since waiting can't otherwise be canceled.
+func waitTimeout(ctx context.Context, timeout time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			return max(remaining, 0)
+		}
+	}
+	return timeout
+}
+
 func objectsToYAML(objects []*unstructured.Unstructured) (string, error) {
 	yamlData, err := ssautil.ObjectsToYAML(objects)
 	if err != nil {
@@ -232,7 +249,8 @@ func objectsToYAML(objects []*unstructured.Unstructured) (string, error) {
 }
 
 func (w *KubernetesBridgeWorker) WatchForApply(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
-	log.Log.Info("🔄 Waiting for resources to be ready...")
+	logger := log.FromContext(wctx.Context())
+	logger.Info("🔄 Waiting for resources to be ready...")
 	workerParams, kubeContext, err := parseTargetParams(payload)
 	if err != nil {
 		// if we can't parse the target params, we cannot look for the resources
@@ -261,7 +279,7 @@ func (w *KubernetesBridgeWorker) WatchForApply(wctx api.BridgeWorkerContext, pay
 			err.Error(),
 		), err))
 	}
-	setDefaultNamespaceIfNotDeclared(objects, k8sclient)
+	setDefaultNamespaceIfNotDeclared(wctx.Context(), objects, k8sclient)
 
 	if err := wctx.SendStatus(newActionResult(
 		api.ActionStatusProgressing,
@@ -276,17 +294,24 @@ func (w *KubernetesBridgeWorker) WatchForApply(wctx api.BridgeWorkerContext, pay
 	if workerParams.WaitTimeout != "" {
 		timeout, err := time.ParseDuration(workerParams.WaitTimeout)
 		if err != nil {
-			log.Log.Error(err, "Invalid wait timeout format, using default", "timeout", workerParams.WaitTimeout)
+			logger.Error(err, "Invalid wait timeout format, using default", "timeout", workerParams.WaitTimeout)
 		} else {
 			waitOpts.Timeout = timeout
-			log.Log.Info("Using custom wait timeout", "timeout", timeout.String())
+			logger.Info("Using custom wait timeout", "timeout", timeout.String())
 		}
 	}
 
+	waitOpts.Timeout = waitTimeout(wctx.Context(), waitOpts.Timeout)
+
 	// TODO: do we throw an error if the wait times out?
 	// Default behavior is to wait 2m0s
 	if err := man.Wait(objects, waitOpts); err != nil {
-		log.Log.Error(err, "Failed to wait for resources")
+		logger.Error(err, "Failed to wait for resources")
+		if ctxErr := wctx.Context().Err(); ctxErr != nil {
+			// The operation timed out or was canceled, so stop retrying. The timeout is
+			// reported by the worker.
+			return backoff.Permanent(fmt.Errorf("failed to wait for resources: %w", ctxErr))
+		}
 		if errors.Is(err, context.DeadlineExceeded) {
 			// log the error but don't return it
 			lib.SafeSendStatus(wctx, newActionResult(
@@ -303,7 +328,7 @@ func (w *KubernetesBridgeWorker) WatchForApply(wctx api.BridgeWorkerContext, pay
 			fmt.Sprintf("Failed to wait for resources: %v", err),
 		), err)
 	}
-	log.Log.Info("✅ All resources are ready")
+	logger.Info("✅ All resources are ready")
 
 	liveObjects, err := getLiveObjects(wctx, man, objects, true)
 	if err != nil {
@@ -316,7 +341,7 @@ func (w *KubernetesBridgeWorker) WatchForApply(wctx api.BridgeWorkerContext, pay
 
 	yamlData, err := objectsToYAML(liveObjects)
 	if err != nil {
-		log.Log.Error(err, "Failed to convert objects to YAML")
+		logger.Error(err, "Failed to convert objects to YAML")
 		return lib.SafeSendStatus(wctx, newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultApplyWaitFailed,
@@ -335,6 +360,7 @@ func (w *KubernetesBridgeWorker) WatchForApply(wctx api.BridgeWorkerContext, pay
 }
 
 func (w *KubernetesBridgeWorker) Refresh(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	logger := log.FromContext(wctx.Context())
 	_, kubeContext, err := parseTargetParams(payload)
 	if err != nil {
 		return lib.SafeSendStatus(wctx, newActionResult(
@@ -362,7 +388,7 @@ func (w *KubernetesBridgeWorker) Refresh(wctx api.BridgeWorkerContext, payload a
 		), err)
 	}
 
-	setDefaultNamespaceIfNotDeclared(objects, k8sclient)
+	setDefaultNamespaceIfNotDeclared(wctx.Context(), objects, k8sclient)
 
 	if err := wctx.SendStatus(newActionResult(
 		api.ActionStatusProgressing,
@@ -374,7 +400,7 @@ func (w *KubernetesBridgeWorker) Refresh(wctx api.BridgeWorkerContext, payload a
 
 	retrievedObjects, err := getLiveObjects(wctx, man, objects, true)
 	if err != nil {
-		log.Log.Error(err, "Failed to retrieve live objects")
+		logger.Error(err, "Failed to retrieve live objects")
 		return lib.SafeSendStatus(wctx, newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultRefreshFailed,
@@ -382,7 +408,7 @@ func (w *KubernetesBridgeWorker) Refresh(wctx api.BridgeWorkerContext, payload a
 		), err)
 	}
 
-	log.Log.Info("🔄 Retrieving resources...", "count", len(objects))
+	logger.Info("🔄 Retrieving resources...", "count", len(objects))
 	if err := wctx.SendStatus(newActionResult(
 		api.ActionStatusProgressing,
 		api.ActionResultNone,
@@ -393,7 +419,7 @@ func (w *KubernetesBridgeWorker) Refresh(wctx api.BridgeWorkerContext, payload a
 
 	yamlData, err := objectsToYAML(retrievedObjects)
 	if err != nil {
-		log.Log.Error(err, "Failed to convert objects to YAML")
+		logger.Error(err, "Failed to convert objects to YAML")
 		return lib.SafeSendStatus(wctx, newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultRefreshFailed,
@@ -403,7 +429,7 @@ func (w *KubernetesBridgeWorker) Refresh(wctx api.BridgeWorkerContext, payload a
 
 	patched, drifted, err := yamlkit.DiffPatch(payload.LiveState, []byte(yamlData), payload.Data, k8skit.K8sResourceProvider)
 	if err != nil {
-		log.Log.Error(err, "Failed to diff patch")
+		logger.Error(err, "Failed to diff patch")
 		return lib.SafeSendStatus(wctx, newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultRefreshFailed,
@@ -412,7 +438,7 @@ func (w *KubernetesBridgeWorker) Refresh(wctx api.BridgeWorkerContext, payload a
 	}
 
 	if !drifted {
-		log.Log.Info("✅ No drift detected")
+		logger.Info("✅ No drift detected")
 		result := newActionResult(
 			api.ActionStatusCompleted,
 			api.ActionResultRefreshAndNoDrift,
@@ -421,7 +447,7 @@ func (w *KubernetesBridgeWorker) Refresh(wctx api.BridgeWorkerContext, payload a
 		return wctx.SendStatus(result)
 	}
 
-	log.Log.Info("✅ Successfully retrieved resources", "count", len(retrievedObjects))
+	logger.Info("✅ Successfully retrieved resources", "count", len(retrievedObjects))
 
 	result := newActionResult(
 		api.ActionStatusCompleted,
@@ -434,6 +460,7 @@ func (w *KubernetesBridgeWorker) Refresh(wctx api.BridgeWorkerContext, payload a
 }
 
 func (w *KubernetesBridgeWorker) Import(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	logger := log.FromContext(wctx.Context())
 	_, kubeContext, err := parseTargetParams(payload)
 	if err != nil {
 		return lib.SafeSendStatus(wctx, newActionResult(
@@ -511,7 +538,7 @@ func (w *KubernetesBridgeWorker) Import(wctx api.BridgeWorkerContext, payload ap
 			objects = append(objects, u)
 		}
 
-		setDefaultNamespaceIfNotDeclared(objects, k8sclient)
+		setDefaultNamespaceIfNotDeclared(wctx.Context(), objects, k8sclient)
 		// Only get live objects if we're importing from stdin/file (legacy flow)
 		if err := wctx.SendStatus(newActionResult(
 			api.ActionStatusProgressing,
@@ -523,7 +550,7 @@ func (w *KubernetesBridgeWorker) Import(wctx api.BridgeWorkerContext, payload ap
 
 		retrievedObjects, err = getLiveObjects(wctx, man, objects, true)
 		if err != nil {
-			log.Log.Error(err, "Failed to retrieve live objects")
+			logger.Error(err, "Failed to retrieve live objects")
 			return lib.SafeSendStatus(wctx, newActionResult(
 				api.ActionStatusFailed,
 				api.ActionResultImportFailed,
@@ -560,7 +587,7 @@ func (w *KubernetesBridgeWorker) Import(wctx api.BridgeWorkerContext, payload ap
 
 	yamlForLiveState, err := objectsToYAML(retrievedObjects)
 	if err != nil {
-		log.Log.Error(err, "Failed to convert objects to YAML for live state")
+		logger.Error(err, "Failed to convert objects to YAML for live state")
 		return lib.SafeSendStatus(wctx, newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultImportFailed,
@@ -571,7 +598,7 @@ func (w *KubernetesBridgeWorker) Import(wctx api.BridgeWorkerContext, payload ap
 	//heuristic extra cleanup setups for objects to make them suitable for being unit.Data
 	yamlForData, err := objectsToYAML(extraCleanupObjects(retrievedObjects))
 	if err != nil {
-		log.Log.Error(err, "Failed to convert objects to YAML for data")
+		logger.Error(err, "Failed to convert objects to YAML for data")
 		return lib.SafeSendStatus(wctx, newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultImportFailed,
@@ -590,6 +617,7 @@ func (w *KubernetesBridgeWorker) Import(wctx api.BridgeWorkerContext, payload ap
 }
 
 func (w *KubernetesBridgeWorker) Destroy(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	logger := log.FromContext(wctx.Context())
 	_, kubeContext, err := parseTargetParams(payload)
 	if err != nil {
 		return lib.SafeSendStatus(wctx, newActionResult(
@@ -617,7 +645,7 @@ func (w *KubernetesBridgeWorker) Destroy(wctx api.BridgeWorkerContext, payload a
 		), err)
 	}
 
-	setDefaultNamespaceIfNotDeclared(objects, k8sclient)
+	setDefaultNamespaceIfNotDeclared(wctx.Context(), objects, k8sclient)
 	if err = wctx.SendStatus(newActionResult(
 		api.ActionStatusProgressing,
 		api.ActionResultNone,
@@ -626,22 +654,23 @@ func (w *KubernetesBridgeWorker) Destroy(wctx api.BridgeWorkerContext, payload a
 		return err
 	}
 
-	log.Log.Info("🔄 Starting resource destruction...")
-	changeSet, err := man.DeleteAll(context.Background(), objects, ssa.DefaultDeleteOptions())
+	logger.Info("🔄 Starting resource destruction...")
+	changeSet, err := man.DeleteAll(wctx.Context(), objects, ssa.DefaultDeleteOptions())
 	if err != nil {
-		log.Log.Error(err, "Failed to delete resources")
+		logger.Error(err, "Failed to delete resources")
 		return lib.SafeSendStatus(wctx, newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultDestroyFailed,
 			fmt.Sprintf("Failed to delete resources: %v", err),
 		), err)
 	}
-	log.Log.Info("✅ Successfully initiated destruction of resources", "changeset_entries", len(changeSet.Entries))
+	logger.Info("✅ Successfully initiated destruction of resources", "changeset_entries", len(changeSet.Entries))
 	return nil
 }
 
 func (w *KubernetesBridgeWorker) WatchForDestroy(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
-	log.Log.Info("🔄 Waiting for resources to be terminated...")
+	logger := log.FromContext(wctx.Context())
+	logger.Info("🔄 Waiting for resources to be terminated...")
 	workerParams, kubeContext, err := parseTargetParams(payload)
 	if err != nil {
 		return lib.SafeSendStatus(wctx, newActionResult(
@@ -668,7 +697,7 @@ func (w *KubernetesBridgeWorker) WatchForDestroy(wctx api.BridgeWorkerContext, p
 			err.Error(),
 		), err)
 	}
-	setDefaultNamespaceIfNotDeclared(objects, k8sclient)
+	setDefaultNamespaceIfNotDeclared(wctx.Context(), objects, k8sclient)
 
 	if err = wctx.SendStatus(newActionResult(
 		api.ActionStatusProgressing,
@@ -683,21 +712,22 @@ func (w *KubernetesBridgeWorker) WatchForDestroy(wctx api.BridgeWorkerContext, p
 	if workerParams.WaitTimeout != "" {
 		timeout, err := time.ParseDuration(workerParams.WaitTimeout)
 		if err != nil {
-			log.Log.Error(err, "Invalid wait timeout format, using default", "timeout", workerParams.WaitTimeout)
+			logger.Error(err, "Invalid wait timeout format, using default", "timeout", workerParams.WaitTimeout)
 		} else {
 			waitOpts.Timeout = timeout
-			log.Log.Info("Using custom wait timeout", "timeout", timeout.String())
+			logger.Info("Using custom wait timeout", "timeout", timeout.String())
 		}
 	}
+	waitOpts.Timeout = waitTimeout(wctx.Context(), waitOpts.Timeout)
 	if err := man.WaitForTermination(objects, waitOpts); err != nil {
-		log.Log.Error(err, "Failed to wait for resource termination")
+		logger.Error(err, "Failed to wait for resource termination")
 		return lib.SafeSendStatus(wctx, newActionResult(
 			api.ActionStatusFailed,
 			api.ActionResultDestroyWaitFailed,
 			fmt.Sprintf("Failed to wait for resource termination: %v", err),
 		), err)
 	}
-	log.Log.Info("✅ All resources terminated successfully")
+	logger.Info("✅ All resources terminated successfully")
 
 	result := newActionResult(
 		api.ActionStatusCompleted,
@@ -709,6 +739,7 @@ func (w *KubernetesBridgeWorker) WatchForDestroy(wctx api.BridgeWorkerContext, p
 }
 
 func (w *KubernetesBridgeWorker) Finalize(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	logger := log.FromContext(wctx.Context())
 	if err := wctx.SendStatus(newActionResult(
 		api.ActionStatusProgressing,
 		api.ActionResultNone,
@@ -717,7 +748,7 @@ func (w *KubernetesBridgeWorker) Finalize(wctx api.BridgeWorkerContext, payload
 		return err
 	}
 
-	log.Log.Info("✅ Finalization completed successfully")
+	logger.Info("✅ Finalization completed successfully")
 
 	result := newActionResult(
 		api.ActionStatusCompleted,
@@ -726,3 +757,9 @@ func (w *KubernetesBridgeWorker) Finalize(wctx api.BridgeWorkerContext, payload
 	)
 	return wctx.SendStatus(result)
 }
+
+// Plan reports the changes Apply would make to the live state. Resources that are only present
+// in the live state are not reported, since Apply doesn't delete them.
+func (w *KubernetesBridgeWorker) Plan(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	return sendPlan(wctx, payload, false)
+}
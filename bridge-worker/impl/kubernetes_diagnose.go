@@ -0,0 +1,67 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package impl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+	"github.com/confighub/sdk/workerapi"
+)
+
+var _ api.DiagnosableWorker = (*KubernetesBridgeWorker)(nil)
+
+// diagnosticTimeout bounds the time spent checking each cluster.
+const diagnosticTimeout = 10 * time.Second
+
+// Diagnose checks that each of the clusters the worker offers as targets can be reached and
+// that the worker is allowed to list namespaces in it.
+func (w *KubernetesBridgeWorker) Diagnose(ctx context.Context) api.DiagnosticResult {
+	var targets []api.Target
+	for _, configType := range w.infoForToolchainAndProvider(ctx, api.InfoOptions{}, workerapi.ToolchainKubernetesYAML, api.ProviderKubernetes).SupportedConfigTypes {
+		targets = append(targets, configType.AvailableTargets...)
+	}
+	return diagnoseKubernetesTargets(ctx, targets)
+}
+
+func diagnoseKubernetesTargets(ctx context.Context, targets []api.Target) api.DiagnosticResult {
+	if len(targets) == 0 {
+		return api.DiagnosticResult{Checks: []api.DiagnosticCheck{{
+			Name:    "kubeconfig",
+			Passed:  false,
+			Message: "No kubeconfig contexts were found and the worker isn't running in a cluster",
+		}}}
+	}
+	var result api.DiagnosticResult
+	for _, target := range targets {
+		kubeContext, _ := target.Params["KubeContext"].(string)
+		check := api.DiagnosticCheck{Name: fmt.Sprintf("target %s", target.Name)}
+		if err := checkKubernetesCluster(ctx, kubeContext); err != nil {
+			check.Message = err.Error()
+		} else {
+			check.Passed = true
+			check.Message = "Listed namespaces"
+		}
+		result.Checks = append(result.Checks, check)
+	}
+	return result
+}
+
+func checkKubernetesCluster(ctx context.Context, kubeContext string) error {
+	k8sclient, _, err := kubernetesClientFactory(kubeContext)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, diagnosticTimeout)
+	defer cancel()
+	if err := k8sclient.List(ctx, &corev1.NamespaceList{}, client.Limit(1)); err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ func NewKubernetesFunctionWorker() *KubernetesFunctionWorker {
 	function.RegisterKubernetes(fh)
 	// Register custom functions
 	registerCustomFunctions(fh)
+	registerKubernetesCELExtensions(fh)
 	return &KubernetesFunctionWorker{
 		fh: fh,
 	}
@@ -7,10 +7,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/cenkalti/backoff/v5"
 	"github.com/confighub/sdk/bridge-worker/api"
+	funcApi "github.com/confighub/sdk/function/api"
 	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
 	"github.com/fluxcd/pkg/ssa"
 	"github.com/stretchr/testify/assert"
@@ -197,6 +200,37 @@ func TestKubernetesBridgeWorker_WatchForApply_ContextDeadlineExceeded(t *testing
 }
 
 // Import operation test cases
+func TestKubernetesBridgeWorker_WatchForApply_OperationTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	mockCtx := new(MockBridgeWorkerContext)
+	mockCtx.On("Context").Return(ctx)
+	setupMockSendStatus(t, mockCtx, api.ActionStatusProgressing, api.ActionResultNone, "Waiting for the applied resources...")
+
+	mockManager, mockClient := setupMockResourceManager(t)
+	// The wait is shortened to end by the operation deadline
+	mockManager.On("Wait", mock.Anything, mock.MatchedBy(func(opts ssa.WaitOptions) bool {
+		return opts.Timeout == 0
+	})).Return(context.DeadlineExceeded)
+
+	restoreFunc := setupKubernetesClientFactory(t, mockClient, mockManager)
+	defer restoreFunc()
+
+	worker := &KubernetesBridgeWorker{}
+	payload := api.BridgeWorkerPayload{
+		TargetParams: testTargetParams,
+		Data:         testConfigMapYAML,
+	}
+
+	err := worker.WatchForApply(mockCtx, payload)
+	var permanentErr *backoff.PermanentError
+	assert.ErrorAs(t, err, &permanentErr, "waiting shouldn't be retried after the operation deadline")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	mockCtx.AssertNumberOfCalls(t, "SendStatus", 1)
+	mockManager.AssertNumberOfCalls(t, "Wait", 1)
+}
+
 func TestKubernetesBridgeWorker_Import(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -290,3 +324,87 @@ func TestKubernetesBridgeWorker_Import(t *testing.T) {
 		})
 	}
 }
+
+const testPlanDeploymentYAML = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+spec:
+  replicas: %d
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.27
+`
+
+func TestKubernetesBridgeWorker_Plan_ReplicaChange(t *testing.T) {
+	mockCtx := setupMockContext(t)
+	var result *api.ActionResult
+	mockCtx.On("SendStatus", mock.Anything).Run(func(args mock.Arguments) {
+		result = args.Get(0).(*api.ActionResult)
+	}).Return(nil)
+
+	liveState := fmt.Sprintf(testPlanDeploymentYAML, 2) + `status:
+  readyReplicas: 2
+`
+	payload := api.BridgeWorkerPayload{
+		Data:      []byte(fmt.Sprintf(testPlanDeploymentYAML, 3)),
+		LiveState: []byte(liveState),
+	}
+
+	worker := &KubernetesBridgeWorker{}
+	err := worker.Plan(mockCtx, payload)
+	assert.NoError(t, err)
+	mockCtx.AssertNumberOfCalls(t, "SendStatus", 1)
+	if !assert.NotNil(t, result) {
+		return
+	}
+	assert.Equal(t, api.ActionStatusCompleted, result.Status)
+	assert.Equal(t, api.ActionResultPlanCompleted, result.Result)
+	assert.Equal(t, "1 resource(s) would change:\n~ apps/v1/Deployment default/web\n    ~ spec.replicas: 3", result.Message)
+
+	var plan funcApi.ResourceMutationList
+	assert.NoError(t, json.Unmarshal(result.Outputs, &plan))
+	if assert.Len(t, plan, 1) {
+		assert.Equal(t, funcApi.MutationTypeUpdate, plan[0].ResourceMutationInfo.MutationType)
+		assert.Equal(t, funcApi.MutationTypeUpdate, plan[0].PathMutationMap["spec.replicas"].MutationType)
+		// Fields populated by the API server are not planned to be removed
+		_, hasStatus := plan[0].PathMutationMap["status"]
+		assert.False(t, hasStatus)
+	}
+}
+
+func TestKubernetesBridgeWorker_Plan_NoChanges(t *testing.T) {
+	mockCtx := setupMockContext(t)
+	setupMockSendStatus(t, mockCtx, api.ActionStatusCompleted, api.ActionResultPlanCompleted, "No changes")
+
+	deployment := []byte(fmt.Sprintf(testPlanDeploymentYAML, 2))
+	worker := &KubernetesBridgeWorker{}
+	err := worker.Plan(mockCtx, api.BridgeWorkerPayload{Data: deployment, LiveState: deployment})
+	assert.NoError(t, err)
+	mockCtx.AssertNumberOfCalls(t, "SendStatus", 1)
+}
+
+func TestKubernetesBridgeWorker_Diagnose(t *testing.T) {
+	mockManager, mockClient := setupMockResourceManager(t)
+	mockClient.On("List", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("namespaces is forbidden")).Once()
+	mockClient.On("List", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	defer setupKubernetesClientFactory(t, mockClient, mockManager)()
+
+	result := diagnoseKubernetesTargets(context.Background(), []api.Target{
+		{Name: "worker-dev", Params: KubernetesWorkerParams{KubeContext: "dev"}.ToMap()},
+		{Name: "worker-prod", Params: KubernetesWorkerParams{KubeContext: "prod"}.ToMap()},
+	})
+	assert.False(t, result.Passed())
+	if assert.Len(t, result.Checks, 2) {
+		assert.Equal(t, "target worker-dev", result.Checks[0].Name)
+		assert.False(t, result.Checks[0].Passed)
+		assert.Contains(t, result.Checks[0].Message, "namespaces is forbidden")
+		assert.True(t, result.Checks[1].Passed)
+	}
+
+	result = diagnoseKubernetesTargets(context.Background(), nil)
+	assert.False(t, result.Passed())
+}
@@ -20,7 +20,9 @@ import (
 	"github.com/confighub/sdk/workerapi"
 )
 
-type OpenTofuAWSWorker struct{}
+type OpenTofuAWSWorker struct {
+	api.PlanNotSupportedWorker
+}
 
 var _ api.BridgeWorker = (*OpenTofuAWSWorker)(nil)
 
@@ -0,0 +1,132 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package impl
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+)
+
+const (
+	// DefaultOperationCacheSize is the default number of completed operations remembered by the
+	// BridgeDispatcher in order to skip operations re-sent by the server.
+	DefaultOperationCacheSize = 1000
+	// DefaultOperationCacheTTL is the default duration for which a successfully completed operation
+	// is remembered.
+	DefaultOperationCacheTTL = 15 * time.Minute
+)
+
+type operationKey struct {
+	action            api.ActionType
+	queuedOperationID uuid.UUID
+}
+
+// completedOperation is the outcome of a successfully completed operation: the last status sent
+type completedOperation struct {
+	key         operationKey
+	lastStatus  *api.ActionResult
+	completedAt time.Time
+}
+
+// operationCache is an LRU cache of recently completed operations, keyed by action and
+// QueuedOperationID. Entries expire after the TTL.
+type operationCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // of *completedOperation, most recently used first
+	entries map[operationKey]*list.Element
+	now     func() time.Time
+}
+
+func newOperationCache(size int, ttl time.Duration) *operationCache {
+	return &operationCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[operationKey]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// configure changes the size and TTL of the cache, evicting entries as needed. A size of zero
+// disables the cache.
+func (c *operationCache) configure(size int, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size = size
+	c.ttl = ttl
+	c.evictLocked()
+}
+
+// get returns the completed operation for key, if it was completed within the TTL.
+func (c *operationCache) get(key operationKey) (*completedOperation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	op := elem.Value.(*completedOperation)
+	if c.now().Sub(op.completedAt) > c.ttl {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return op, true
+}
+
+func (c *operationCache) add(op *completedOperation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size <= 0 {
+		return
+	}
+	op.completedAt = c.now()
+	if elem, ok := c.entries[op.key]; ok {
+		elem.Value = op
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[op.key] = c.order.PushFront(op)
+	c.evictLocked()
+}
+
+func (c *operationCache) evictLocked() {
+	for c.order.Len() > max(c.size, 0) {
+		elem := c.order.Back()
+		c.order.Remove(elem)
+		delete(c.entries, elem.Value.(*completedOperation).key)
+	}
+}
+
+// recordingBridgeWorkerContext records the last status sent by an operation so that it can be
+// re-sent if the operation is requested again.
+type recordingBridgeWorkerContext struct {
+	api.BridgeWorkerContext
+	mu         sync.Mutex
+	lastStatus *api.ActionResult
+}
+
+func (r *recordingBridgeWorkerContext) SendStatus(result *api.ActionResult) error {
+	if result != nil {
+		status := *result
+		r.mu.Lock()
+		r.lastStatus = &status
+		r.mu.Unlock()
+	}
+	return r.BridgeWorkerContext.SendStatus(result)
+}
+
+func (r *recordingBridgeWorkerContext) recordedStatus() *api.ActionResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastStatus
+}
@@ -0,0 +1,126 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package impl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+	"github.com/confighub/sdk/bridge-worker/lib"
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/configkit/yamlkit"
+	funcApi "github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+// planKubernetesResources computes the mutations that would change the Kubernetes resources in
+// the live state into the resources in the configuration data. Resources that are only present in
+// the live state are reported as deleted only if includeDeletions is true, since not all bridges
+// remove resources that were dropped from the configuration data.
+func planKubernetesResources(data, liveState []byte, includeDeletions bool) (funcApi.ResourceMutationList, error) {
+	parsedData, err := gaby.ParseAll(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration data: %w", err)
+	}
+	parsedLiveState := gaby.Container{}
+	if len(bytes.TrimSpace(liveState)) > 0 {
+		parsedLiveState, err = gaby.ParseAll(liveState)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse live state: %w", err)
+		}
+		k8skit.K8sResourceProvider.RemoveLiveStateOnlyFields(parsedLiveState)
+	}
+
+	mutations, err := yamlkit.ComputeMutations(parsedLiveState, parsedData, 0, k8skit.K8sResourceProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute changes: %w", err)
+	}
+	plan := funcApi.ResourceMutationList{}
+	for _, mutation := range mutations {
+		switch mutation.ResourceMutationInfo.MutationType {
+		case funcApi.MutationTypeNone:
+			continue
+		case funcApi.MutationTypeDelete:
+			if !includeDeletions {
+				continue
+			}
+		}
+		plan = append(plan, mutation)
+	}
+	return plan, nil
+}
+
+var planMutationSymbols = map[funcApi.MutationType]string{
+	funcApi.MutationTypeAdd:     "+",
+	funcApi.MutationTypeUpdate:  "~",
+	funcApi.MutationTypeReplace: "-/+",
+	funcApi.MutationTypeDelete:  "-",
+}
+
+// formatPlan returns a human-readable summary of the planned mutations.
+func formatPlan(plan funcApi.ResourceMutationList) string {
+	if len(plan) == 0 {
+		return "No changes"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d resource(s) would change:\n", len(plan))
+	for _, mutation := range plan {
+		fmt.Fprintf(&sb, "%s %s %s\n",
+			planMutationSymbols[mutation.ResourceMutationInfo.MutationType],
+			mutation.Resource.ResourceType,
+			mutation.Resource.ResourceName,
+		)
+		if mutation.ResourceMutationInfo.MutationType != funcApi.MutationTypeUpdate {
+			continue
+		}
+		paths := make([]string, 0, len(mutation.PathMutationMap))
+		for path, mutationInfo := range mutation.PathMutationMap {
+			if mutationInfo.MutationType != funcApi.MutationTypeNone {
+				paths = append(paths, string(path))
+			}
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			mutationInfo := mutation.PathMutationMap[funcApi.ResolvedPath(path)]
+			value := strings.TrimSpace(mutationInfo.Value)
+			if mutationInfo.MutationType == funcApi.MutationTypeDelete || strings.Contains(value, "\n") {
+				fmt.Fprintf(&sb, "    %s %s\n", planMutationSymbols[mutationInfo.MutationType], path)
+			} else {
+				fmt.Fprintf(&sb, "    %s %s: %s\n", planMutationSymbols[mutationInfo.MutationType], path, value)
+			}
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// sendPlan sends the result of a Plan operation for Kubernetes resources.
+func sendPlan(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload, includeDeletions bool) error {
+	plan, err := planKubernetesResources(payload.Data, payload.LiveState, includeDeletions)
+	if err != nil {
+		return lib.SafeSendStatus(wctx, newActionResult(
+			api.ActionStatusFailed,
+			api.ActionResultPlanFailed,
+			err.Error(),
+		), err)
+	}
+	outputs, err := json.Marshal(plan)
+	if err != nil {
+		return lib.SafeSendStatus(wctx, newActionResult(
+			api.ActionStatusFailed,
+			api.ActionResultPlanFailed,
+			fmt.Sprintf("Failed to marshal plan: %v", err),
+		), err)
+	}
+	result := newActionResult(
+		api.ActionStatusCompleted,
+		api.ActionResultPlanCompleted,
+		formatPlan(plan),
+	)
+	result.Outputs = outputs
+	return wctx.SendStatus(result)
+}
@@ -0,0 +1,296 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package impl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	vault "github.com/hashicorp/vault/api"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+	"github.com/confighub/sdk/bridge-worker/lib"
+	"github.com/confighub/sdk/third_party/gaby"
+	"github.com/confighub/sdk/workerapi"
+)
+
+// VaultBridgeWorker applies Kubernetes Secrets whose values are read from HashiCorp Vault.
+// Secrets in the configuration data are annotated with the path of a KV version 2 secret in
+// Vault, and the data of the Secret is set to the key/value pairs of the Vault secret when the
+// Secret is applied. The secret values are never sent to ConfigHub: the data of Secrets is
+// removed from the live state reported by the worker.
+//
+// For example:
+//
+//	apiVersion: v1
+//	kind: Secret
+//	metadata:
+//	  name: db-credentials
+//	  namespace: prod
+//	  annotations:
+//	    confighub.com/VaultPath: apps/db
+type VaultBridgeWorker struct {
+	KubernetesBridgeWorker
+	Config *VaultBridgeWorkerConfig
+}
+
+var _ api.BridgeWorker = (*VaultBridgeWorker)(nil)
+var _ api.WatchableWorker = (*VaultBridgeWorker)(nil)
+
+const (
+	// VaultPathAnnotation is the annotation specifying the path of the Vault secret, relative to
+	// the mount path, from which the data of a Secret is read.
+	VaultPathAnnotation = "confighub.com/VaultPath"
+	// VaultMountAnnotation optionally overrides the mount path of the KV secrets engine for a Secret.
+	VaultMountAnnotation = "confighub.com/VaultMount"
+)
+
+const (
+	// VaultAuthMethodToken authenticates with the token in VAULT_TOKEN.
+	VaultAuthMethodToken = "token"
+	// VaultAuthMethodKubernetesRole authenticates with the Kubernetes auth method, logging in
+	// with the worker's service account token and the configured role.
+	VaultAuthMethodKubernetesRole = "kubernetes"
+
+	DefaultVaultMountPath          = "secret"
+	DefaultVaultKubernetesAuthPath = "kubernetes"
+	defaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+type VaultBridgeWorkerConfig struct {
+	// Address is the address of the Vault server. If empty, VAULT_ADDR is used.
+	Address string
+	// MountPath is the mount path of the KV version 2 secrets engine
+	MountPath string
+	// AuthMethod is VaultAuthMethodToken or VaultAuthMethodKubernetesRole
+	AuthMethod string
+	// KubernetesRole is the Vault role used with VaultAuthMethodKubernetesRole
+	KubernetesRole string
+	// KubernetesAuthPath is the mount path of the Kubernetes auth method
+	KubernetesAuthPath string
+	// ServiceAccountTokenPath is the file containing the service account token used to log in
+	// with VaultAuthMethodKubernetesRole
+	ServiceAccountTokenPath string
+}
+
+func NewVaultBridgeWorker() *VaultBridgeWorker {
+	return &VaultBridgeWorker{}
+}
+
+// NewVaultBridgeWorkerConfig configures worker to read secrets from the Vault server at address.
+// If role is not empty, the worker authenticates with the Kubernetes auth method using role;
+// otherwise, it authenticates with the token in VAULT_TOKEN.
+func NewVaultBridgeWorkerConfig(worker *VaultBridgeWorker, address, mountPath, role, kubernetesAuthPath string) error {
+	config := &VaultBridgeWorkerConfig{
+		Address:                 address,
+		MountPath:               mountPath,
+		AuthMethod:              VaultAuthMethodToken,
+		KubernetesRole:          role,
+		KubernetesAuthPath:      kubernetesAuthPath,
+		ServiceAccountTokenPath: defaultServiceAccountTokenPath,
+	}
+	if config.MountPath == "" {
+		config.MountPath = DefaultVaultMountPath
+	}
+	if config.KubernetesAuthPath == "" {
+		config.KubernetesAuthPath = DefaultVaultKubernetesAuthPath
+	}
+	if role != "" {
+		config.AuthMethod = VaultAuthMethodKubernetesRole
+	} else if os.Getenv(vault.EnvVaultToken) == "" {
+		return fmt.Errorf("either %s or a Vault Kubernetes role must be specified", vault.EnvVaultToken)
+	}
+	worker.Config = config
+	return nil
+}
+
+func (w *VaultBridgeWorker) config() *VaultBridgeWorkerConfig {
+	if w.Config == nil {
+		return &VaultBridgeWorkerConfig{
+			MountPath:  DefaultVaultMountPath,
+			AuthMethod: VaultAuthMethodToken,
+		}
+	}
+	return w.Config
+}
+
+func (w *VaultBridgeWorker) Info(opts api.InfoOptions) api.BridgeWorkerInfo {
+	return w.KubernetesBridgeWorker.InfoForToolchainAndProvider(opts, workerapi.ToolchainKubernetesYAML, api.ProviderVault)
+}
+
+// newVaultClient returns a Vault client authenticated as configured.
+func (w *VaultBridgeWorker) newVaultClient(ctx context.Context) (*vault.Client, error) {
+	config := w.config()
+	vaultConfig := vault.DefaultConfig()
+	if vaultConfig.Error != nil {
+		return nil, errors.Wrap(vaultConfig.Error, "failed to read the Vault configuration")
+	}
+	if config.Address != "" {
+		vaultConfig.Address = config.Address
+	}
+	client, err := vault.NewClient(vaultConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the Vault client")
+	}
+	if config.AuthMethod != VaultAuthMethodKubernetesRole {
+		return client, nil
+	}
+
+	jwt, err := os.ReadFile(config.ServiceAccountTokenPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the service account token")
+	}
+	loginPath := "auth/" + strings.Trim(config.KubernetesAuthPath, "/") + "/login"
+	secret, err := client.Logical().WriteWithContext(ctx, loginPath, map[string]any{
+		"role": config.KubernetesRole,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to log in to Vault with role %s", config.KubernetesRole)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return nil, errors.Newf("Vault login with role %s returned no token", config.KubernetesRole)
+	}
+	client.SetToken(secret.Auth.ClientToken)
+	return client, nil
+}
+
+// resolveVaultSecrets sets the data of the Secrets annotated with VaultPathAnnotation in the
+// configuration data to the values of the corresponding Vault secrets.
+func (w *VaultBridgeWorker) resolveVaultSecrets(ctx context.Context, data []byte) ([]byte, error) {
+	docs, err := gaby.ParseAll(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the configuration data")
+	}
+	var client *vault.Client
+	for _, doc := range docs {
+		vaultPath := vaultPathOfSecret(doc)
+		if vaultPath == "" {
+			continue
+		}
+		if client == nil {
+			client, err = w.newVaultClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		mountPath := w.config().MountPath
+		if mount, ok := doc.Path("metadata.annotations").S(VaultMountAnnotation).Data().(string); ok && mount != "" {
+			mountPath = mount
+		}
+		name, _ := doc.Path("metadata.name").Data().(string)
+		kvSecret, err := client.KVv2(mountPath).Get(ctx, vaultPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read Vault secret %s/%s for Secret %s", mountPath, vaultPath, name)
+		}
+		secretData := map[string]any{}
+		for key, value := range kvSecret.Data {
+			stringValue, ok := value.(string)
+			if !ok {
+				jsonValue, err := json.Marshal(value)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to encode key %s of Vault secret %s/%s", key, mountPath, vaultPath)
+				}
+				stringValue = string(jsonValue)
+			}
+			secretData[key] = base64.StdEncoding.EncodeToString([]byte(stringValue))
+		}
+		_ = doc.Delete("stringData")
+		if _, err := doc.Set(secretData, "data"); err != nil {
+			return nil, errors.Wrapf(err, "failed to set the data of Secret %s", name)
+		}
+		log.Log.Info("🔑 Read Secret data from Vault", "name", name, "path", mountPath+"/"+vaultPath, "keys", len(secretData))
+	}
+	return []byte(docs.String()), nil
+}
+
+func vaultPathOfSecret(doc *gaby.YamlDoc) string {
+	if kind, _ := doc.Path("kind").Data().(string); kind != "Secret" {
+		return ""
+	}
+	vaultPath, _ := doc.Path("metadata.annotations").S(VaultPathAnnotation).Data().(string)
+	return strings.Trim(vaultPath, "/")
+}
+
+// redactSecretData removes the data of all Secrets from the YAML configuration data.
+func redactSecretData(data []byte) []byte {
+	docs, err := gaby.ParseAll(data)
+	if err != nil {
+		// Don't risk sending secret values
+		return nil
+	}
+	for _, doc := range docs {
+		if kind, _ := doc.Path("kind").Data().(string); kind == "Secret" {
+			_ = doc.Delete("data")
+			_ = doc.Delete("stringData")
+		}
+	}
+	return []byte(docs.String())
+}
+
+// redactingBridgeWorkerContext removes secret values from the live state sent to ConfigHub.
+type redactingBridgeWorkerContext struct {
+	api.BridgeWorkerContext
+}
+
+func (r *redactingBridgeWorkerContext) SendStatus(result *api.ActionResult) error {
+	if result != nil && len(result.LiveState) > 0 {
+		redacted := *result
+		redacted.LiveState = redactSecretData(result.LiveState)
+		result = &redacted
+	}
+	return r.BridgeWorkerContext.SendStatus(result)
+}
+
+func (w *VaultBridgeWorker) Apply(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	data, err := w.resolveVaultSecrets(wctx.Context(), payload.Data)
+	if err != nil {
+		return lib.SafeSendStatus(wctx, newActionResult(
+			api.ActionStatusFailed,
+			api.ActionResultApplyFailed,
+			err.Error(),
+		), err)
+	}
+	payload.Data = data
+	return w.KubernetesBridgeWorker.Apply(&redactingBridgeWorkerContext{wctx}, payload)
+}
+
+func (w *VaultBridgeWorker) WatchForApply(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	return w.KubernetesBridgeWorker.WatchForApply(&redactingBridgeWorkerContext{wctx}, payload)
+}
+
+func (w *VaultBridgeWorker) Refresh(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	return w.KubernetesBridgeWorker.Refresh(&redactingBridgeWorkerContext{wctx}, payload)
+}
+
+func (w *VaultBridgeWorker) Import(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	// Importing would copy secret values out of the cluster
+	return lib.SafeSendStatus(wctx, newActionResult(
+		api.ActionStatusFailed,
+		api.ActionResultImportFailed,
+		"Import not supported",
+	), errors.New("Import not supported"))
+}
+
+func (w *VaultBridgeWorker) Destroy(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	return w.KubernetesBridgeWorker.Destroy(&redactingBridgeWorkerContext{wctx}, payload)
+}
+
+func (w *VaultBridgeWorker) WatchForDestroy(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	return w.KubernetesBridgeWorker.WatchForDestroy(&redactingBridgeWorkerContext{wctx}, payload)
+}
+
+func (w *VaultBridgeWorker) Plan(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	return w.KubernetesBridgeWorker.Plan(&redactingBridgeWorkerContext{wctx}, payload)
+}
+
+func (w *VaultBridgeWorker) Finalize(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	return w.KubernetesBridgeWorker.Finalize(wctx, payload)
+}
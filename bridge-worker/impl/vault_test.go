@@ -0,0 +1,156 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package impl
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const vaultTestSecret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: db-credentials
+  namespace: prod
+  annotations:
+    confighub.com/VaultPath: apps/db
+stringData:
+  placeholder: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  key: value
+`
+
+// newTestVaultServer serves the KV v2 secret apps/db and the Kubernetes auth login endpoint.
+// Secret reads must use wantToken.
+func newTestVaultServer(t *testing.T, wantToken string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/kubernetes/login":
+			var body map[string]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body["role"] != "worker" || body["jwt"] != "sa-token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"auth": map[string]any{"client_token": "login-token"}})
+		case "/v1/secret/data/apps/db":
+			if r.Header.Get("X-Vault-Token") != wantToken {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+				"data":     map[string]any{"username": "admin", "port": 5432},
+				"metadata": map[string]any{"version": 1},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func assertResolvedSecret(t *testing.T, data []byte) {
+	docs, err := gaby.ParseAll(data)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "YWRtaW4=", docs[0].Path("data.username").Data())
+	assert.Equal(t, "NTQzMg==", docs[0].Path("data.port").Data())
+	assert.False(t, docs[0].Exists("stringData"))
+	assert.Equal(t, "value", docs[1].Path("data.key").Data())
+}
+
+func TestVaultBridgeWorker_ResolveSecretsWithToken(t *testing.T) {
+	server := newTestVaultServer(t, "root-token")
+	t.Setenv("VAULT_TOKEN", "root-token")
+	worker := NewVaultBridgeWorker()
+	require.NoError(t, NewVaultBridgeWorkerConfig(worker, server.URL, "", "", ""))
+	assert.Equal(t, VaultAuthMethodToken, worker.Config.AuthMethod)
+
+	data, err := worker.resolveVaultSecrets(t.Context(), []byte(vaultTestSecret))
+	require.NoError(t, err)
+	assertResolvedSecret(t, data)
+}
+
+func TestVaultBridgeWorker_ResolveSecretsWithKubernetesRole(t *testing.T) {
+	server := newTestVaultServer(t, "login-token")
+	t.Setenv("VAULT_TOKEN", "")
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("sa-token\n"), 0o600))
+	worker := NewVaultBridgeWorker()
+	require.NoError(t, NewVaultBridgeWorkerConfig(worker, server.URL, "", "worker", ""))
+	assert.Equal(t, VaultAuthMethodKubernetesRole, worker.Config.AuthMethod)
+	worker.Config.ServiceAccountTokenPath = tokenPath
+
+	data, err := worker.resolveVaultSecrets(t.Context(), []byte(vaultTestSecret))
+	require.NoError(t, err)
+	assertResolvedSecret(t, data)
+
+	worker.Config.KubernetesRole = "other"
+	_, err = worker.resolveVaultSecrets(t.Context(), []byte(vaultTestSecret))
+	assert.ErrorContains(t, err, "failed to log in to Vault with role other")
+}
+
+func TestVaultBridgeWorker_ConfigRequiresCredentials(t *testing.T) {
+	t.Setenv("VAULT_TOKEN", "")
+	assert.Error(t, NewVaultBridgeWorkerConfig(NewVaultBridgeWorker(), "http://vault:8200", "", "", ""))
+}
+
+func TestVaultBridgeWorker_ApplyFailsForMissingSecret(t *testing.T) {
+	server := newTestVaultServer(t, "root-token")
+	t.Setenv("VAULT_TOKEN", "root-token")
+	worker := NewVaultBridgeWorker()
+	require.NoError(t, NewVaultBridgeWorkerConfig(worker, server.URL, "kv", "", ""))
+	wctx := &statusRecordingContext{}
+
+	err := worker.Apply(wctx, api.BridgeWorkerPayload{Data: []byte(vaultTestSecret)})
+	assert.ErrorContains(t, err, "failed to read Vault secret kv/apps/db for Secret db-credentials")
+	if assert.Len(t, wctx.statuses, 1) {
+		assert.Equal(t, api.ActionResultApplyFailed, wctx.statuses[0].Result)
+	}
+}
+
+func TestRedactingBridgeWorkerContext(t *testing.T) {
+	wctx := &statusRecordingContext{}
+	redacting := &redactingBridgeWorkerContext{wctx}
+	result := &api.ActionResult{LiveState: []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: db-credentials
+data:
+  username: YWRtaW4=
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  key: value
+`)}
+
+	require.NoError(t, redacting.SendStatus(result))
+	require.Len(t, wctx.statuses, 1)
+	docs, err := gaby.ParseAll(wctx.statuses[0].LiveState)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.False(t, docs[0].Exists("data"))
+	assert.Equal(t, "value", docs[1].Path("data.key").Data())
+	// The original result is not modified
+	assert.Contains(t, string(result.LiveState), "YWRtaW4=")
+}
@@ -17,6 +17,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/alitto/pond"
@@ -30,6 +31,7 @@ const (
 	eventsRoute        = "%s/api/bridge_worker/%s/stream"
 	resultRoute        = "%s/api/bridge_worker/%s/action_result"
 	workerSelfGetRoute = "%s/api/bridge_worker/%s/me"
+	healthRoute        = "%s/health"
 )
 
 type workerClient struct {
@@ -43,6 +45,17 @@ type workerClient struct {
 	functionWorker api.FunctionWorker
 	watcherPool    *pond.WorkerPool
 	unitQueues     *UnitQueueManager
+	eventCallback  ConnectionEventCallback
+	// operationTimeout is the default deadline of bridge operations; 0 means no deadline
+	operationTimeout time.Duration
+	// preflightTimeout is the deadline of the health check before connecting; 0 disables it
+	preflightTimeout time.Duration
+
+	// stopMu guards the state used to stop the client gracefully.
+	stopMu     sync.Mutex
+	stopping   bool
+	stopCtx    context.Context
+	stopStream context.CancelFunc
 }
 
 func newClient(serverURL, workerID, workerSecret string, bridgeWorker api.BridgeWorker, functionWorker api.FunctionWorker) *workerClient {
@@ -75,7 +88,8 @@ func newClient(serverURL, workerID, workerSecret string, bridgeWorker api.Bridge
 		transport = &http2.Transport{
 			AllowHTTP: true,
 			DialTLSContext: func(ctx context.Context, network, addr string, cfg *tls.Config) (netConn net.Conn, err error) {
-				return net.Dial(network, addr)
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, addr)
 			},
 		}
 		perfOpts(transport)
@@ -97,10 +111,20 @@ func newClient(serverURL, workerID, workerSecret string, bridgeWorker api.Bridge
 }
 
 func (c *workerClient) Start(ctx context.Context) error {
+	defer close(c.done)
+
+	if err := c.checkHealth(ctx); err != nil {
+		log.Printf("[ERROR] Pre-flight check failed: %v", err)
+		notifyConnectionEvent(c.eventCallback, ConnectionEventError, err)
+		return err
+	}
+
 	err := c.getBridgeWorkerSlug()
 	if err != nil {
 		log.Printf("[ERROR] Failed to get bridge worker slug: %v", err)
-		return fmt.Errorf("failed to get bridge worker slug: %v", err)
+		err = fmt.Errorf("failed to get bridge worker slug: %v", err)
+		notifyConnectionEvent(c.eventCallback, ConnectionEventError, err)
+		return err
 	}
 
 	// Start the unit queue manager
@@ -109,7 +133,58 @@ func (c *workerClient) Start(ctx context.Context) error {
 	// Ensure cleanup on exit
 	defer c.unitQueues.Stop()
 
-	return c.startStream(ctx)
+	err = c.startStream(ctx)
+	if stopCtx := c.stoppingContext(); stopCtx != nil {
+		// Allow the operations that were already received to complete
+		if waitErr := c.unitQueues.WaitIdle(stopCtx); waitErr != nil {
+			log.Printf("[WARNING] Canceling operations in progress: %v", waitErr)
+		}
+	}
+	return err
+}
+
+// Stop closes the event stream so that no more events are received and waits for the operations
+// already received to complete, after which Start returns. If ctx is done first, the remaining
+// operations are canceled and ctx's error is returned.
+func (c *workerClient) Stop(ctx context.Context) error {
+	c.stopMu.Lock()
+	c.stopping = true
+	c.stopCtx = ctx
+	stopStream := c.stopStream
+	c.stopMu.Unlock()
+	if stopStream != nil {
+		stopStream()
+	}
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stoppingContext returns the context passed to Stop, or nil if the client isn't being stopped.
+func (c *workerClient) stoppingContext() context.Context {
+	c.stopMu.Lock()
+	defer c.stopMu.Unlock()
+	if !c.stopping {
+		return nil
+	}
+	return c.stopCtx
+}
+
+// streamContext returns the context for the event stream request, which is canceled by Stop. It
+// returns nil if the client is already being stopped.
+func (c *workerClient) streamContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	c.stopMu.Lock()
+	defer c.stopMu.Unlock()
+	if c.stopping {
+		return nil, nil
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	c.stopStream = cancel
+	return streamCtx, cancel
 }
 
 func (c *workerClient) startStream(ctx context.Context) error {
@@ -124,6 +199,7 @@ func (c *workerClient) startStream(ctx context.Context) error {
 		BridgeWorkerInfo:   bridgeWorkerInfo,
 		FunctionWorkerInfo: functionWorkerInfo,
 	}
+	workerInfo.Diagnostics = c.diagnose(ctx)
 	infoJson, err := json.Marshal(workerInfo)
 	if err != nil {
 		log.Printf("[ERROR] Failed to marshal worker info: %v", err)
@@ -150,13 +226,25 @@ func (c *workerClient) startStream(ctx context.Context) error {
 		req.Header.Get("Cache-Control"),
 		req.Header.Get("Connection"))
 
-	req = req.WithContext(ctx)
+	// Events are processed using ctx rather than the stream context, so that closing the stream
+	// to stop the client doesn't cancel the operations in progress.
+	streamCtx, cancelStream := c.streamContext(ctx)
+	if streamCtx == nil {
+		return nil
+	}
+	defer cancelStream()
+	req = req.WithContext(streamCtx)
 	log.Printf("[DEBUG] Initiating connection to event stream...")
 	startTime := time.Now()
 	resp, err := c.client.Do(req)
 	if err != nil {
+		if c.stoppingContext() != nil {
+			return nil
+		}
 		log.Printf("[ERROR] Failed to connect to stream after %v: %v", time.Since(startTime), err)
-		return fmt.Errorf("error connecting to stream: %v", err)
+		err = fmt.Errorf("error connecting to stream: %v", err)
+		notifyConnectionEvent(c.eventCallback, ConnectionEventError, err)
+		return err
 	}
 
 	log.Printf("[INFO] Successfully connected to event stream in %v, status: %d %s",
@@ -168,8 +256,11 @@ func (c *workerClient) startStream(ctx context.Context) error {
 		log.Printf("[ERROR]: Server returned status %d: %s", resp.StatusCode, resp.Status)
 		log.Printf("[ERROR]: Response body:\n%s\n", string(body))
 
-		return fmt.Errorf("server returned status %d: %s", resp.StatusCode, resp.Status)
+		err = fmt.Errorf("server returned status %d: %s", resp.StatusCode, resp.Status)
+		notifyConnectionEvent(c.eventCallback, ConnectionEventError, err)
+		return err
 	}
+	notifyConnectionEvent(c.eventCallback, ConnectionEventConnected, nil)
 
 	// Log response headers for debugging
 	log.Printf("[DEBUG] Response headers:")
@@ -193,8 +284,15 @@ func (c *workerClient) startStream(ctx context.Context) error {
 				}
 				break
 			}
+			if c.stoppingContext() != nil {
+				log.Printf("[INFO] Event stream closed to stop the worker after processing %d events", eventCount)
+				break
+			}
 			log.Printf("[ERROR] Network/connection error while reading stream after %d events: %v", eventCount, err)
-			return fmt.Errorf("failed to read from event stream: %w", err)
+			err = fmt.Errorf("failed to read from event stream: %w", err)
+			notifyConnectionEvent(c.eventCallback, ConnectionEventError, err)
+			notifyConnectionEvent(c.eventCallback, ConnectionEventDisconnected, err)
+			return err
 		}
 
 		// Check for SSE "data:" prefix
@@ -230,6 +328,7 @@ func (c *workerClient) startStream(ctx context.Context) error {
 	}
 
 	log.Printf("[INFO] Event stream processing completed, handled %d total events", eventCount)
+	notifyConnectionEvent(c.eventCallback, ConnectionEventDisconnected, nil)
 	return nil
 }
 
@@ -319,6 +418,12 @@ func (c *workerClient) handleEvent(ctx context.Context, eventType string, data [
 			}
 			return
 		}
+		if op.Action == api.ActionDiagnose {
+			if err := c.sendDiagnostics(ctx); err != nil {
+				log.Printf("[ERROR] Failed to send diagnostics: %v", err)
+			}
+			return
+		}
 	case api.EventBridgeWorker:
 		var op api.BridgeWorkerEventRequest
 		if err := json.Unmarshal(data, &op); err != nil {
@@ -369,6 +474,35 @@ func (c *workerClient) handleEvent(ctx context.Context, eventType string, data [
 	}
 }
 
+// checkHealth requests the health endpoint of the server and returns an error if the server doesn't
+// respond within preflightTimeout or responds with a server error.
+func (c *workerClient) checkHealth(ctx context.Context) error {
+	if c.preflightTimeout <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.preflightTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(healthRoute, c.serverURL), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("server %s did not respond within %v", c.serverURL, c.preflightTimeout)
+		}
+		return fmt.Errorf("server %s is unreachable: %v", c.serverURL, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("server %s is unhealthy: %s", c.serverURL, resp.Status)
+	}
+	return nil
+}
+
 func (c *workerClient) getBridgeWorkerSlug() error {
 	getUrl := fmt.Sprintf(workerSelfGetRoute, c.serverURL, c.workerID)
 	req, err := http.NewRequest(http.MethodGet, getUrl, nil)
@@ -397,6 +531,55 @@ func (c *workerClient) getBridgeWorkerSlug() error {
 	return nil
 }
 
+// diagnose runs the checks of the bridge worker, or returns nil if it isn't a DiagnosableWorker or
+// performed no checks, as a dispatcher without diagnosable workers does.
+func (c *workerClient) diagnose(ctx context.Context) *api.DiagnosticResult {
+	diagnosable, ok := c.bridgeWorker.(api.DiagnosableWorker)
+	if !ok {
+		return nil
+	}
+	diagnostics := diagnosable.Diagnose(ctx)
+	if len(diagnostics.Checks) == 0 {
+		return nil
+	}
+	return &diagnostics
+}
+
+// sendDiagnostics runs the checks of the bridge worker again and sends the result, so that the
+// diagnostics displayed by cub worker diagnose aren't limited to the time the worker connected.
+func (c *workerClient) sendDiagnostics(ctx context.Context) error {
+	startedAt := time.Now()
+	result := &api.ActionResult{
+		ActionResultBaseMeta: api.ActionResultBaseMeta{
+			Action:    api.ActionDiagnose,
+			Result:    api.ActionResultNone,
+			Status:    api.ActionStatusCompleted,
+			StartedAt: startedAt,
+		},
+	}
+	diagnostics := c.diagnose(ctx)
+	if diagnostics == nil {
+		result.Status = api.ActionStatusFailed
+		result.Message = "The bridge worker doesn't support diagnostics"
+	} else {
+		outputs, err := json.Marshal(diagnostics)
+		if err != nil {
+			return fmt.Errorf("failed to marshal diagnostics: %v", err)
+		}
+		result.Outputs = outputs
+		failed := 0
+		for _, check := range diagnostics.Checks {
+			if !check.Passed {
+				failed++
+			}
+		}
+		result.Message = fmt.Sprintf("%d of %d checks passed", len(diagnostics.Checks)-failed, len(diagnostics.Checks))
+	}
+	terminatedAt := time.Now()
+	result.TerminatedAt = &terminatedAt
+	return c.sendResult(result)
+}
+
 func (c *workerClient) sendResult(result *api.ActionResult) error {
 	resultJSON, err := json.Marshal(result)
 	if err != nil {
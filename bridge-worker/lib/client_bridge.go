@@ -4,6 +4,7 @@
 package lib
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -31,36 +32,56 @@ func (c *workerClient) processBridgeCommand(workerContext *defaultBridgeWorkerCo
 		}
 	}
 
+	// The context is canceled when the operation completes, including waiting for it to take
+	// effect, unless the watcher takes over canceling it.
+	ctx, cancel, timeout := c.operationContext(workerContext.ctx, op.Payload)
+	workerContext.ctx = ctx
+	watching := false
+	defer func() {
+		if !watching {
+			cancel()
+		}
+	}()
+
 	switch action := op.Action; action {
 	case api.ActionApply:
 		setupSendResult(api.ActionApply)
 		watch, err := c.handleApply(workerContext, op.Payload)
 		if err != nil {
-			return err
+			return sendTimeoutStatus(workerContext, action, api.ActionResultApplyFailed, timeout, err)
 		}
 		if watch {
-			return c.handleWatchApply(workerContext, op.Payload)
+			watching = true
+			return c.handleWatchApply(workerContext, op.Payload, timeout, cancel)
 		}
 		return nil
 	case api.ActionRefresh:
 		setupSendResult(api.ActionRefresh)
-		return c.handleGet(workerContext, op.Payload)
+		err := c.handleGet(workerContext, op.Payload)
+		return sendTimeoutStatus(workerContext, action, api.ActionResultRefreshFailed, timeout, err)
 	case api.ActionImport:
 		setupSendResult(api.ActionImport)
-		return c.handleImport(workerContext, op.Payload)
+		err := c.handleImport(workerContext, op.Payload)
+		return sendTimeoutStatus(workerContext, action, api.ActionResultImportFailed, timeout, err)
 	case api.ActionDestroy:
 		setupSendResult(api.ActionDestroy)
 		watch, err := c.handleDestroy(workerContext, op.Payload)
 		if err != nil {
-			return err
+			return sendTimeoutStatus(workerContext, action, api.ActionResultDestroyFailed, timeout, err)
 		}
 		if watch {
-			return c.handleWatchDestroy(workerContext, op.Payload)
+			watching = true
+			return c.handleWatchDestroy(workerContext, op.Payload, timeout, cancel)
 		}
 		return nil
 	case api.ActionFinalize:
 		setupSendResult(api.ActionFinalize)
-		return c.handleFinalize(workerContext, op.Payload)
+		err := c.handleFinalize(workerContext, op.Payload)
+		return sendTimeoutStatus(workerContext, action, api.ActionResultNone, timeout, err)
+	case api.ActionPlan:
+		setupSendResult(api.ActionPlan)
+		err := c.handlePlan(workerContext, op.Payload)
+		return sendTimeoutStatus(workerContext, action, api.ActionResultPlanFailed, timeout, err)
 	default:
 		// For unknown actions, construct an error result and send it.
 		startedAt := time.Now()
@@ -82,15 +103,53 @@ func (c *workerClient) processBridgeCommand(workerContext *defaultBridgeWorkerCo
 	}
 }
 
+// operationContext returns the context in which to perform the operation with the payload. Its
+// deadline is derived from the timeout of the payload, if any, or else from the default operation
+// timeout of the worker, if any. The timeout is returned, or 0 if the context has no deadline.
+func (c *workerClient) operationContext(ctx context.Context, payload api.BridgeWorkerPayload) (context.Context, context.CancelFunc, time.Duration) {
+	timeout := c.operationTimeout
+	if payload.TimeoutSeconds > 0 {
+		timeout = time.Duration(payload.TimeoutSeconds) * time.Second
+	}
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(ctx)
+		return ctx, cancel, 0
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, timeout
+}
+
+// sendTimeoutStatus reports that the action failed because it timed out if the deadline of the
+// operation context was exceeded, and returns err. Bridges may block until the context is done,
+// so this status takes precedence over any the bridge reported.
+func sendTimeoutStatus(workerContext api.BridgeWorkerContext, action api.ActionType, result api.ActionResultType, timeout time.Duration, err error) error {
+	if timeout == 0 || !errors.Is(workerContext.Context().Err(), context.DeadlineExceeded) {
+		return err
+	}
+	if err == nil {
+		err = context.DeadlineExceeded
+	}
+	return SafeSendStatus(workerContext, &api.ActionResult{
+		ActionResultBaseMeta: api.ActionResultBaseMeta{
+			Status:  api.ActionStatusFailed,
+			Result:  result,
+			Message: fmt.Sprintf("%s timed out after %v", action, timeout),
+		},
+	}, err)
+}
+
 func (c *workerClient) handleApply(workerContext api.BridgeWorkerContext, payload api.BridgeWorkerPayload) (bool, error) {
 	log.Printf("📥 Received APPLY command with data: %s", string(payload.Data))
 	return true, c.bridgeWorker.Apply(workerContext, payload)
 }
 
-func (c *workerClient) handleWatchApply(workerContext api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+// handleWatchApply waits for the apply to take effect, if the bridge supports watching, and then
+// calls done.
+func (c *workerClient) handleWatchApply(workerContext api.BridgeWorkerContext, payload api.BridgeWorkerPayload, timeout time.Duration, done func()) error {
 	log.Printf("📥 Kick off watching for apply")
 	if watchable, ok := c.bridgeWorker.(api.WatchableWorker); ok {
 		c.watcherPool.Submit(func() {
+			defer done()
 			operation := func() (any, error) {
 				return nil, watchable.WatchForApply(workerContext, payload)
 			}
@@ -107,9 +166,12 @@ func (c *workerClient) handleWatchApply(workerContext api.BridgeWorkerContext, p
 			)
 			if err != nil {
 				log.Printf("Error watching for apply: %v", err)
+				_ = sendTimeoutStatus(workerContext, api.ActionApply, api.ActionResultApplyWaitFailed, timeout, err)
 			}
 		})
+		return nil
 	}
+	done()
 	return nil
 }
 
@@ -128,11 +190,14 @@ func (c *workerClient) handleDestroy(workerContext api.BridgeWorkerContext, payl
 	return true, c.bridgeWorker.Destroy(workerContext, payload)
 }
 
-func (c *workerClient) handleWatchDestroy(workerContext api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+// handleWatchDestroy waits for the destroy to take effect, if the bridge supports watching, and then
+// calls done.
+func (c *workerClient) handleWatchDestroy(workerContext api.BridgeWorkerContext, payload api.BridgeWorkerPayload, timeout time.Duration, done func()) error {
 	log.Printf("📥 Kick off watching for destroy")
 	// TODO rename api.WatchableWorker api.WatchableBridgeWorker
 	if watchable, ok := c.bridgeWorker.(api.WatchableWorker); ok {
 		c.watcherPool.Submit(func() {
+			defer done()
 			operation := func() (any, error) {
 				return nil, watchable.WatchForDestroy(workerContext, payload)
 			}
@@ -149,9 +214,12 @@ func (c *workerClient) handleWatchDestroy(workerContext api.BridgeWorkerContext,
 			)
 			if err != nil {
 				log.Printf("Error watching for destroy: %v", err)
+				_ = sendTimeoutStatus(workerContext, api.ActionDestroy, api.ActionResultDestroyWaitFailed, timeout, err)
 			}
 		})
+		return nil
 	}
+	done()
 	return nil
 }
 
@@ -159,3 +227,8 @@ func (c *workerClient) handleFinalize(workerContext api.BridgeWorkerContext, pay
 	log.Printf("📥 Received FINALIZE command with payload: %s", string(payload.Data))
 	return c.bridgeWorker.Finalize(workerContext, payload)
 }
+
+func (c *workerClient) handlePlan(workerContext api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	log.Printf("📥 Received PLAN command with data: %s", string(payload.Data))
+	return c.bridgeWorker.Plan(workerContext, payload)
+}
@@ -0,0 +1,111 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+)
+
+// blockingBridgeWorker blocks in Apply until the operation context is done.
+type blockingBridgeWorker struct {
+	testBridgeWorker
+}
+
+func (*blockingBridgeWorker) Apply(wctx api.BridgeWorkerContext, _ api.BridgeWorkerPayload) error {
+	if err := wctx.SendStatus(&api.ActionResult{
+		ActionResultBaseMeta: api.ActionResultBaseMeta{
+			Status:  api.ActionStatusProgressing,
+			Result:  api.ActionResultNone,
+			Message: "Applying resources...",
+		},
+	}); err != nil {
+		return err
+	}
+	<-wctx.Context().Done()
+	return wctx.Context().Err()
+}
+
+// newTestResultServer returns a server that records the action results sent by the worker.
+func newTestResultServer(t *testing.T) (*httptest.Server, func() []api.ActionResult) {
+	var mu sync.Mutex
+	var results []api.ActionResult
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/action_result", func(w http.ResponseWriter, r *http.Request) {
+		var result api.ActionResult
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&result)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, result)
+	})
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	t.Cleanup(server.Close)
+	return server, func() []api.ActionResult {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]api.ActionResult(nil), results...)
+	}
+}
+
+func TestProcessBridgeCommand_ApplyTimeout(t *testing.T) {
+	tests := []struct {
+		name             string
+		operationTimeout time.Duration
+		timeoutSeconds   int64
+		message          string
+	}{
+		{"worker default", 50 * time.Millisecond, 0, "Apply timed out after 50ms"},
+		{"server provided", time.Hour, 1, "Apply timed out after 1s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, results := newTestResultServer(t)
+			client := newClient(server.URL, "test-worker-id", "test-worker-secret", &blockingBridgeWorker{}, &testFunctionWorker{})
+			client.operationTimeout = tt.operationTimeout
+			workerContext := &defaultBridgeWorkerContext{
+				ctx:       context.Background(),
+				serverURL: server.URL,
+				workerID:  "test-worker-id",
+			}
+
+			errc := make(chan error, 1)
+			go func() {
+				errc <- client.processBridgeCommand(workerContext, api.BridgeWorkerEventRequest{
+					Action:  api.ActionApply,
+					Payload: api.BridgeWorkerPayload{TimeoutSeconds: tt.timeoutSeconds},
+				})
+			}()
+			select {
+			case err := <-errc:
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			case <-time.After(5 * time.Second):
+				t.Fatal("apply wasn't canceled by the deadline")
+			}
+
+			got := results()
+			require.Len(t, got, 2)
+			assert.Equal(t, api.ActionStatusProgressing, got[0].Status)
+			assert.Equal(t, api.ActionApply, got[1].Action)
+			assert.Equal(t, api.ActionStatusFailed, got[1].Status)
+			assert.Equal(t, api.ActionResultApplyFailed, got[1].Result)
+			assert.Equal(t, tt.message, got[1].Message)
+			assert.NotNil(t, got[1].TerminatedAt)
+		})
+	}
+}
@@ -37,6 +37,12 @@ type UnitQueueManager struct {
 	cleanupCtx     context.Context
 	cleanupCancel  context.CancelFunc
 	errorChannel   chan error
+
+	// pending counts the events that have been queued but not yet processed. idle is closed
+	// whenever pending is zero.
+	pendingMu sync.Mutex
+	pending   int
+	idle      chan struct{}
 }
 
 type unitQueue struct {
@@ -55,10 +61,13 @@ type queuedEvent struct {
 
 // NewUnitQueueManager creates a new UnitQueueManager instance
 func NewUnitQueueManager() *UnitQueueManager {
+	idle := make(chan struct{})
+	close(idle)
 	return &UnitQueueManager{
 		bridgeQueues:   make(map[string]*unitQueue),
 		functionQueues: make(map[string]*unitQueue),
 		errorChannel:   make(chan error, errorChannelBuffer),
+		idle:           idle,
 	}
 }
 
@@ -175,6 +184,7 @@ func (u *UnitQueueManager) processQueue(q *unitQueue, queueType QueueType) {
 
 			// Process event with timeout protection to prevent deadlocks
 			u.processEventWithTimeout(event, queueType, q.unitID)
+			u.removePending()
 		}
 	}
 }
@@ -184,8 +194,10 @@ func (u *UnitQueueManager) QueueBridgeEvent(ctx context.Context, event api.Bridg
 	unitID := event.Payload.UnitID.String()
 	q := u.getOrCreateQueue(unitID, BridgeQueueType, ctx)
 
+	u.addPending()
 	select {
 	case <-ctx.Done():
+		u.removePending()
 		log.Printf("Context cancelled, not queuing bridge event for unit %s", unitID)
 		return
 	case q.events <- queuedEvent{
@@ -197,6 +209,7 @@ func (u *UnitQueueManager) QueueBridgeEvent(ctx context.Context, event api.Bridg
 	}:
 		log.Printf("Queued bridge event for unit %s, action %s", unitID, event.Action)
 	default:
+		u.removePending()
 		log.Printf("Bridge queue full for unit %s, dropping bridge event", unitID)
 	}
 }
@@ -206,8 +219,10 @@ func (u *UnitQueueManager) QueueFunctionEvent(ctx context.Context, event api.Fun
 	unitID := event.Payload.InvocationRequest.UnitID.String()
 	q := u.getOrCreateQueue(unitID, FunctionQueueType, ctx)
 
+	u.addPending()
 	select {
 	case <-ctx.Done():
+		u.removePending()
 		log.Printf("Context cancelled, not queuing function event for unit %s", unitID)
 		return
 	case q.events <- queuedEvent{
@@ -219,10 +234,42 @@ func (u *UnitQueueManager) QueueFunctionEvent(ctx context.Context, event api.Fun
 	}:
 		log.Printf("Queued function event for unit %s, action %s", unitID, event.Action)
 	default:
+		u.removePending()
 		log.Printf("Function queue full for unit %s, dropping function event", unitID)
 	}
 }
 
+func (u *UnitQueueManager) addPending() {
+	u.pendingMu.Lock()
+	defer u.pendingMu.Unlock()
+	if u.pending == 0 {
+		u.idle = make(chan struct{})
+	}
+	u.pending++
+}
+
+func (u *UnitQueueManager) removePending() {
+	u.pendingMu.Lock()
+	defer u.pendingMu.Unlock()
+	u.pending--
+	if u.pending == 0 {
+		close(u.idle)
+	}
+}
+
+// WaitIdle waits until all of the queued events have been processed or ctx is done.
+func (u *UnitQueueManager) WaitIdle(ctx context.Context) error {
+	u.pendingMu.Lock()
+	idle := u.idle
+	u.pendingMu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // cleanupIdleQueues periodically removes idle queues to prevent resource leaks
 func (u *UnitQueueManager) cleanupIdleQueues() {
 	defer u.wg.Done()
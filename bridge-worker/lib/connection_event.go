@@ -0,0 +1,50 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package lib
+
+import (
+	"time"
+)
+
+// ConnectionEventType identifies a transition in the state of the worker's connection to ConfigHub.
+type ConnectionEventType string
+
+const (
+	// ConnectionEventConnected is reported when the event stream has been opened.
+	ConnectionEventConnected ConnectionEventType = "Connected"
+	// ConnectionEventDisconnected is reported when an open event stream is closed.
+	ConnectionEventDisconnected ConnectionEventType = "Disconnected"
+	// ConnectionEventError is reported when connecting fails or the event stream fails. Err is set.
+	ConnectionEventError ConnectionEventType = "Error"
+	// ConnectionEventReconnecting is reported before a lost connection is reestablished. The worker
+	// doesn't reconnect on its own; it is reported by the connector in the worker package, which
+	// starts a new worker when the connection of the previous one is closed or lost. Err is set if
+	// the connection was lost due to an error.
+	ConnectionEventReconnecting ConnectionEventType = "Reconnecting"
+)
+
+// ConnectionEvent describes a transition in the state of the worker's connection to ConfigHub.
+type ConnectionEvent struct {
+	Type      ConnectionEventType
+	Timestamp time.Time
+	Err       error
+}
+
+// ConnectionEventCallback is called on each connection state transition.
+type ConnectionEventCallback func(event ConnectionEvent)
+
+// notifyConnectionEvent calls the callback, if any, in its own goroutine so that the worker is
+// never blocked by it. As a consequence, callbacks may observe events out of order; the
+// Timestamp reflects the order in which they occurred.
+func notifyConnectionEvent(callback ConnectionEventCallback, eventType ConnectionEventType, err error) {
+	if callback == nil {
+		return
+	}
+	event := ConnectionEvent{
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Err:       err,
+	}
+	go callback(event)
+}
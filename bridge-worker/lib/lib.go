@@ -7,17 +7,35 @@ import (
 	"context"
 	"errors"
 	"log"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/confighub/sdk/bridge-worker/api"
 )
 
+// DefaultPreflightTimeout is the time Start waits for the ConfigHub server to respond to the
+// health check when no timeout was set with WithPreflightTimeout.
+const DefaultPreflightTimeout = 10 * time.Second
+
 type Worker struct {
 	confighubURL   string
 	workerId       string
 	workerSecret   string
 	bridgeWorker   api.BridgeWorker
 	functionWorker api.FunctionWorker
-	client         *workerClient
+	logger         logr.Logger
+	eventCallback  ConnectionEventCallback
+	// operationTimeout is the default deadline of bridge operations; 0 means no deadline
+	operationTimeout time.Duration
+	// preflightTimeout is the deadline of the health check in Start; 0 means the default
+	preflightTimeout time.Duration
+
+	clientMu sync.Mutex
+	client   *workerClient
+	stopped  bool
 }
 
 func New(url, id, secret string) *Worker {
@@ -38,9 +56,53 @@ func (b *Worker) WithFunctionWorker(functionWorker api.FunctionWorker) *Worker {
 	return b
 }
 
+// WithLogger sets the logger available to the bridge and function worker implementations via
+// log.FromContext on the worker context. The process-wide controller-runtime logger isn't changed.
+// If no logger is set, the logger from the context passed to Start is used.
+func (b *Worker) WithLogger(logger logr.Logger) *Worker {
+	b.logger = logger
+	return b
+}
+
+// WithConnectionEventCallback sets a callback that is called on each transition in the state of
+// the connection to ConfigHub. The callback is called in its own goroutine and must not block.
+func (b *Worker) WithConnectionEventCallback(callback ConnectionEventCallback) *Worker {
+	b.eventCallback = callback
+	return b
+}
+
+// WithOperationTimeout sets the default maximum duration of each bridge operation, such as an
+// apply and the wait for the applied resources to become ready. A timeout provided with the
+// operation by ConfigHub takes precedence. When the deadline is exceeded, the context of the
+// operation is canceled and a timeout is reported as the status of the operation. By default,
+// operations have no deadline.
+func (b *Worker) WithOperationTimeout(timeout time.Duration) *Worker {
+	b.operationTimeout = timeout
+	return b
+}
+
+// WithPreflightTimeout sets the maximum time Start waits for the health endpoint of the ConfigHub
+// server to respond before connecting. If the server doesn't respond in time, or responds with a
+// server error, Start returns an error rather than retrying, so that a worker running in a
+// container exits instead of lingering half-started. The default is DefaultPreflightTimeout. A
+// negative timeout disables the check.
+func (b *Worker) WithPreflightTimeout(timeout time.Duration) *Worker {
+	b.preflightTimeout = timeout
+	return b
+}
+
 func (b *Worker) Start(ctx context.Context) error {
+	if b.logger.GetSink() != nil {
+		ctx = crlog.IntoContext(ctx, b.logger)
+	}
+
 	client := newClient(b.confighubURL, b.workerId, b.workerSecret, b.bridgeWorker, b.functionWorker)
-	b.client = client
+	client.eventCallback = b.eventCallback
+	client.operationTimeout = b.operationTimeout
+	client.preflightTimeout = b.preflightTimeout
+	if client.preflightTimeout == 0 {
+		client.preflightTimeout = DefaultPreflightTimeout
+	}
 
 	subCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -62,9 +124,32 @@ func (b *Worker) Start(ctx context.Context) error {
 	}
 	log.Printf("Starting worker with ID: %s", b.workerId)
 	log.Printf("Starting worker with Token: %s...", b.workerSecret[:8])
-	if err := b.client.Start(subCtx); err != nil {
+	b.clientMu.Lock()
+	b.client = client
+	stopped := b.stopped
+	b.clientMu.Unlock()
+	if stopped {
+		log.Printf("Worker stopped before connecting")
+		return nil
+	}
+	if err := client.Start(subCtx); err != nil {
 		log.Printf("Error starting worker: %v", err)
 		return err
 	}
 	return nil
 }
+
+// Stop stops the worker gracefully: it stops receiving events from ConfigHub and waits for the
+// operations already received to complete, after which Start returns. If ctx is done first, the
+// remaining operations are canceled and ctx's error is returned. If Stop is called before Start
+// connects, Start returns without connecting.
+func (b *Worker) Stop(ctx context.Context) error {
+	b.clientMu.Lock()
+	b.stopped = true
+	client := b.client
+	b.clientMu.Unlock()
+	if client == nil {
+		return nil
+	}
+	return client.Stop(ctx)
+}
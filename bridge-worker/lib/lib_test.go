@@ -0,0 +1,290 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+)
+
+// testBridgeWorker only implements Info; the other operations aren't expected to be invoked.
+type testBridgeWorker struct {
+	api.BridgeWorker
+}
+
+func (*testBridgeWorker) Info(api.InfoOptions) api.BridgeWorkerInfo {
+	return api.BridgeWorkerInfo{}
+}
+
+// testFunctionWorker only implements Info; functions aren't expected to be invoked.
+type testFunctionWorker struct {
+	api.FunctionWorker
+}
+
+func (*testFunctionWorker) Info() api.FunctionWorkerInfo {
+	return api.FunctionWorkerInfo{}
+}
+
+// loggingBridgeWorker logs with the logger from the context when it's diagnosed.
+type loggingBridgeWorker struct {
+	testBridgeWorker
+}
+
+func (*loggingBridgeWorker) Diagnose(ctx context.Context) api.DiagnosticResult {
+	crlog.FromContext(ctx).Info("Diagnosing")
+	return api.DiagnosticResult{}
+}
+
+func TestWorker_WithLogger(t *testing.T) {
+	var mu sync.Mutex
+	var lines []string
+	logger := funcr.New(func(prefix, args string) {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, args)
+	}, funcr.Options{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/me", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Slug": "test-worker"}`))
+	})
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	defer server.Close()
+
+	worker := New(server.URL, "test-worker-id", "test-worker-secret").
+		WithBridgeWorker(&loggingBridgeWorker{}).
+		WithFunctionWorker(&testFunctionWorker{}).
+		WithLogger(logger)
+	assert.NoError(t, worker.Start(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The logger is passed to the bridge worker in the context
+	assert.Equal(t, []string{`"level"=0 "msg"="Diagnosing"`}, lines)
+}
+
+func collectConnectionEvents(t *testing.T, count int) (ConnectionEventCallback, func() []ConnectionEvent) {
+	events := make(chan ConnectionEvent, count)
+	callback := func(event ConnectionEvent) {
+		events <- event
+	}
+	wait := func() []ConnectionEvent {
+		var received []ConnectionEvent
+		for range count {
+			select {
+			case event := <-events:
+				received = append(received, event)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for connection events; received %v", received)
+			}
+		}
+		// Callbacks run in their own goroutines, so they may arrive out of order
+		sort.Slice(received, func(i, j int) bool { return received[i].Timestamp.Before(received[j].Timestamp) })
+		return received
+	}
+	return callback, wait
+}
+
+func TestWorker_ConnectionEventCallback_Error(t *testing.T) {
+	callback, wait := collectConnectionEvents(t, 1)
+	worker := New("http://127.0.0.1:1", "test-worker-id", "test-worker-secret").
+		WithConnectionEventCallback(callback)
+	err := worker.Start(context.Background())
+	assert.Error(t, err)
+
+	events := wait()
+	assert.Equal(t, ConnectionEventError, events[0].Type)
+	assert.Error(t, events[0].Err)
+	assert.False(t, events[0].Timestamp.IsZero())
+}
+
+func TestWorker_ConnectionEventCallback_ConnectedAndDisconnected(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/me", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Slug": "test-worker"}`))
+	})
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/stream", func(w http.ResponseWriter, r *http.Request) {
+		// Close the stream immediately
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	defer server.Close()
+
+	callback, wait := collectConnectionEvents(t, 2)
+	worker := New(server.URL, "test-worker-id", "test-worker-secret").
+		WithBridgeWorker(&testBridgeWorker{}).
+		WithFunctionWorker(&testFunctionWorker{}).
+		WithConnectionEventCallback(callback)
+	err := worker.Start(context.Background())
+	assert.NoError(t, err)
+
+	events := wait()
+	assert.Equal(t, ConnectionEventConnected, events[0].Type)
+	assert.NoError(t, events[0].Err)
+	assert.Equal(t, ConnectionEventDisconnected, events[1].Type)
+}
+
+// diagnosableBridgeWorker reports a fixed diagnostic result.
+type diagnosableBridgeWorker struct {
+	testBridgeWorker
+}
+
+func (*diagnosableBridgeWorker) Diagnose(context.Context) api.DiagnosticResult {
+	return api.DiagnosticResult{Checks: []api.DiagnosticCheck{{Name: "target", Passed: false, Message: "forbidden"}}}
+}
+
+func TestWorker_ReportsDiagnostics(t *testing.T) {
+	infos := make(chan api.WorkerInfo, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/me", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Slug": "test-worker"}`))
+	})
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/stream", func(w http.ResponseWriter, r *http.Request) {
+		var info api.WorkerInfo
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&info))
+		infos <- info
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	defer server.Close()
+
+	worker := New(server.URL, "test-worker-id", "test-worker-secret").
+		WithBridgeWorker(&diagnosableBridgeWorker{}).
+		WithFunctionWorker(&testFunctionWorker{})
+	assert.NoError(t, worker.Start(context.Background()))
+
+	info := <-infos
+	if assert.NotNil(t, info.Diagnostics) {
+		assert.False(t, info.Diagnostics.Passed())
+		assert.Equal(t, "forbidden", info.Diagnostics.Checks[0].Message)
+	}
+}
+
+func TestWorker_RefreshesDiagnostics(t *testing.T) {
+	results := make(chan api.ActionResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/me", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Slug": "test-worker"}`))
+	})
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`data: {"Event": "WorkerEvent", "Data": {"Action": "Diagnose"}}` + "\n"))
+	})
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/action_result", func(w http.ResponseWriter, r *http.Request) {
+		var result api.ActionResult
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&result))
+		results <- result
+	})
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	defer server.Close()
+
+	worker := New(server.URL, "test-worker-id", "test-worker-secret").
+		WithBridgeWorker(&diagnosableBridgeWorker{}).
+		WithFunctionWorker(&testFunctionWorker{})
+	assert.NoError(t, worker.Start(context.Background()))
+
+	result := <-results
+	assert.Equal(t, api.ActionDiagnose, result.Action)
+	assert.Equal(t, api.ActionStatusCompleted, result.Status)
+	assert.Equal(t, "0 of 1 checks passed", result.Message)
+	var diagnostics api.DiagnosticResult
+	assert.NoError(t, json.Unmarshal(result.Outputs, &diagnostics))
+	assert.Equal(t, "forbidden", diagnostics.Checks[0].Message)
+}
+
+func TestWorker_Stop(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/me", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Slug": "test-worker"}`))
+	})
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/stream", func(w http.ResponseWriter, r *http.Request) {
+		// Keep the stream open until the worker closes it
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	defer server.Close()
+
+	callback, wait := collectConnectionEvents(t, 1)
+	worker := New(server.URL, "test-worker-id", "test-worker-secret").
+		WithBridgeWorker(&testBridgeWorker{}).
+		WithFunctionWorker(&testFunctionWorker{}).
+		WithConnectionEventCallback(callback)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- worker.Start(context.Background())
+	}()
+	assert.Equal(t, ConnectionEventConnected, wait()[0].Type)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, worker.Stop(ctx))
+	select {
+	case err := <-errc:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start didn't return after Stop")
+	}
+}
+
+func TestWorker_PreflightTimeout(t *testing.T) {
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		// Don't respond until the test is done
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/me", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("worker connected after the pre-flight check timed out")
+	})
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	defer server.Close()
+	defer close(release)
+
+	callback, wait := collectConnectionEvents(t, 1)
+	worker := New(server.URL, "test-worker-id", "test-worker-secret").
+		WithBridgeWorker(&testBridgeWorker{}).
+		WithConnectionEventCallback(callback).
+		WithPreflightTimeout(100 * time.Millisecond)
+	start := time.Now()
+	err := worker.Start(context.Background())
+	assert.ErrorContains(t, err, "did not respond within 100ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, ConnectionEventError, wait()[0].Type)
+}
+
+func TestWorker_PreflightServerError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	defer server.Close()
+
+	worker := New(server.URL, "test-worker-id", "test-worker-secret").
+		WithBridgeWorker(&testBridgeWorker{})
+	err := worker.Start(context.Background())
+	assert.ErrorContains(t, err, "is unhealthy: 503 Service Unavailable")
+}
@@ -0,0 +1,22 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Trigger a Plan operation, which reports the changes an Apply would make without applying them",
+	RunE:  planRunE,
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+}
+
+func planRunE(cmd *cobra.Command, args []string) error {
+	return executeOperation("Plan", cmd, args)
+}
@@ -9,8 +9,13 @@ import (
 	neturl "net/url"
 	"os"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap/zapcore"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -18,6 +23,7 @@ import (
 	"github.com/confighub/sdk/bridge-worker/api"
 	"github.com/confighub/sdk/bridge-worker/impl"
 	"github.com/confighub/sdk/bridge-worker/lib"
+	"github.com/confighub/sdk/cmd/internal/signalhandler"
 	"github.com/confighub/sdk/workerapi"
 )
 
@@ -31,8 +37,21 @@ The available worker types are:
 - flux-oci-writer
 - opentofu-aws
 - properties-configmap
+- app-config-yaml
+- ansible-vars
+- vault
 
-They can be comma separated like "kubernetes,properties-configmap"
+They can be comma separated like "kubernetes,properties-configmap", which enables
+--enable-multiplexer unless it's disabled explicitly, so that the targets of the worker
+types are distinct.
+
+On SIGHUP, the worker finishes the operations in progress and reconnects with
+CONFIGHUB_URL, CONFIGHUB_WORKER_PORT, CONFIGHUB_WORKER_ID, and CONFIGHUB_WORKER_SECRET
+re-read from the environment. Values specified by flags are not reloaded.
+
+On SIGTERM or SIGINT, the worker stops accepting new operations, waits up to --drain-timeout
+for the operations in progress to complete, and exits. A second SIGTERM or SIGINT exits
+immediately.
 `,
 	SilenceErrors:     true,
 	SilenceUsage:      true,
@@ -54,33 +73,64 @@ var rootArgs struct {
 	inCluster            bool
 	authMethod           string // "kubernetes", "cloud", "docker-config", "keychain"
 	kubernetesSecretPath string
-	enableMultiplexer    bool   // Enable new multiplexer mode with prefixes
+	enableMultiplexer    bool // Enable new multiplexer mode with prefixes
+	vaultAddress         string
+	vaultMountPath       string
+	vaultKubernetesRole  string
+	vaultKubernetesAuth  string
+	drainTimeout         time.Duration
+	logFormat            string
+	logLevel             string
 	// autoRefresh  bool
 }
 
-func init() {
-	url := defaultConfighubURL
-	if envUrl := os.Getenv("CONFIGHUB_URL"); envUrl != "" {
-		parsedURL, err := neturl.Parse(envUrl)
-		if err != nil {
-			log.FromContext(context.Background()).Error(err, "Bad CONFIGHUB_URL")
-			url = defaultConfighubURL
-		} else {
-			if parsedURL.Scheme == "" {
-				parsedURL.Scheme = defaultConfighubScheme
-			}
-			if parsedURL.Host == "" {
-				parsedURL.Host = defaultConfighubHost
-			}
-			// Drop any ports, paths, query params, etc.
-			url = parsedURL.Scheme + "://" + parsedURL.Hostname()
-		}
+const defaultDrainTimeout = 30 * time.Second
+
+const (
+	logFormatDev  = "dev"
+	logFormatJSON = "json"
+)
+
+// logLevels maps the values of --log-level to zap levels
+var logLevels = map[string]zapcore.Level{
+	"debug": zapcore.DebugLevel,
+	"info":  zapcore.InfoLevel,
+	"warn":  zapcore.WarnLevel,
+	"error": zapcore.ErrorLevel,
+}
+
+// configHubURLFromEnv returns the ConfigHub URL specified by CONFIGHUB_URL, or the default URL.
+func configHubURLFromEnv() string {
+	envUrl := os.Getenv("CONFIGHUB_URL")
+	if envUrl == "" {
+		return defaultConfighubURL
+	}
+	parsedURL, err := neturl.Parse(envUrl)
+	if err != nil {
+		log.FromContext(context.Background()).Error(err, "Bad CONFIGHUB_URL")
+		return defaultConfighubURL
+	}
+	if parsedURL.Scheme == "" {
+		parsedURL.Scheme = defaultConfighubScheme
+	}
+	if parsedURL.Host == "" {
+		parsedURL.Host = defaultConfighubHost
 	}
+	// Drop any ports, paths, query params, etc.
+	return parsedURL.Scheme + "://" + parsedURL.Hostname()
+}
 
-	workerPort := "443"
+// workerPortFromEnv returns the worker port specified by CONFIGHUB_WORKER_PORT, or the default port.
+func workerPortFromEnv() string {
 	if p := os.Getenv("CONFIGHUB_WORKER_PORT"); p != "" {
-		workerPort = p
+		return p
 	}
+	return "443"
+}
+
+func init() {
+	url := configHubURLFromEnv()
+	workerPort := workerPortFromEnv()
 
 	authMethod := "keychain"
 	if am := os.Getenv("AUTH_METHOD"); am != "" {
@@ -94,6 +144,12 @@ func init() {
 		inCluster = true
 	}
 
+	// Logs are collected from pods, so they are structured when running in a cluster
+	logFormat := logFormatDev
+	if inCluster {
+		logFormat = logFormatJSON
+	}
+
 	enableMultiplexer := false
 	if os.Getenv("ENABLE_MULTIPLEXER") == "true" {
 		enableMultiplexer = true
@@ -109,7 +165,14 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&rootArgs.inCluster, "in-cluster", inCluster, "Enable in-cluster deployment for FluxOCIWorker (use Kubernetes secrets or cloud provider credentials) (IN_CLUSTER)")
 	rootCmd.PersistentFlags().StringVar(&rootArgs.authMethod, "auth-method", authMethod, "Authentication method for FluxOCIWorker (kubernetes, cloud, docker-config, keychain) (AUTH_METHOD)")
 	rootCmd.PersistentFlags().StringVar(&rootArgs.kubernetesSecretPath, "kubernetes-secret-path", kubernetesSecretPath, "Path to the Kubernetes secret mounted as a volume. For use with k8s auth-method and FluxOCIWorker (KUBERNETES_SECRET_PATH)")
-	rootCmd.PersistentFlags().BoolVar(&rootArgs.enableMultiplexer, "enable-multiplexer", enableMultiplexer, "Enable multiplexer mode with prefixes and multi-worker support (ENABLE_MULTIPLEXER)")
+	rootCmd.PersistentFlags().StringVar(&rootArgs.vaultAddress, "vault-address", os.Getenv("VAULT_ADDR"), "Address of the Vault server for VaultBridgeWorker (VAULT_ADDR)")
+	rootCmd.PersistentFlags().StringVar(&rootArgs.vaultMountPath, "vault-mount-path", os.Getenv("VAULT_MOUNT_PATH"), "Mount path of the Vault KV v2 secrets engine for VaultBridgeWorker, secret by default (VAULT_MOUNT_PATH)")
+	rootCmd.PersistentFlags().StringVar(&rootArgs.vaultKubernetesRole, "vault-kubernetes-role", os.Getenv("VAULT_KUBERNETES_ROLE"), "Vault role for Kubernetes service account auth for VaultBridgeWorker. If not set, VAULT_TOKEN is used (VAULT_KUBERNETES_ROLE)")
+	rootCmd.PersistentFlags().StringVar(&rootArgs.vaultKubernetesAuth, "vault-kubernetes-auth-path", os.Getenv("VAULT_KUBERNETES_AUTH_PATH"), "Mount path of the Vault Kubernetes auth method for VaultBridgeWorker, kubernetes by default (VAULT_KUBERNETES_AUTH_PATH)")
+	rootCmd.PersistentFlags().BoolVar(&rootArgs.enableMultiplexer, "enable-multiplexer", enableMultiplexer, "Prefix the targets of each provider, such as with k8s-, so that the targets of multiple worker types are distinct (ENABLE_MULTIPLEXER)")
+	rootCmd.PersistentFlags().DurationVar(&rootArgs.drainTimeout, "drain-timeout", defaultDrainTimeout, "Maximum time to wait for the operations in progress to complete on SIGTERM or SIGINT before canceling them")
+	rootCmd.PersistentFlags().StringVar(&rootArgs.logFormat, "log-format", logFormat, "Log format: dev for human-readable logs or json for structured logs; json by default when IN_CLUSTER is true")
+	rootCmd.PersistentFlags().StringVar(&rootArgs.logLevel, "log-level", "info", "Minimum level of logged messages: debug, info, warn, or error")
 }
 
 const (
@@ -117,6 +180,9 @@ const (
 	WorkerTypeFluxOCIWriter       = "flux-oci-writer"
 	WorkerTypeOpenTofuAWS         = "opentofu-aws"
 	WorkerTypePropertiesConfigMap = "properties-configmap"
+	WorkerTypeAppConfigYAML       = "app-config-yaml"
+	WorkerTypeAnsibleVars         = "ansible-vars"
+	WorkerTypeVault               = "vault"
 	// TODO: remove "properties" from the worker type once we can support multiple function workers
 	// TODO: add configmap-flux type.
 )
@@ -127,12 +193,16 @@ var availableBridgeWorkers = map[string]api.BridgeWorker{
 	WorkerTypeFluxOCIWriter:       impl.NewFluxOCIWorker(),
 	WorkerTypeOpenTofuAWS:         &impl.OpenTofuAWSWorker{},
 	WorkerTypePropertiesConfigMap: &impl.ConfigMapBridgeWorker{},
+	WorkerTypeAppConfigYAML:       &impl.ConfigMapBridgeWorker{ToolchainType: workerapi.ToolchainAppConfigYAML},
+	WorkerTypeAnsibleVars:         &impl.ConfigMapBridgeWorker{ToolchainType: workerapi.ToolchainAnsibleVars},
 }
 
 // Initialize individual function workers first
 var k8sFunctionWorker = impl.NewKubernetesFunctionWorker()
 var propertiesFunctionWorker = impl.NewPropertiesFunctionWorker()
 var opentofuFunctionWorker = impl.NewOpentofuFunctionWorker()
+var appConfigYAMLFunctionWorker = impl.NewAppConfigYAMLFunctionWorker()
+var ansibleVarsFunctionWorker = impl.NewAnsibleVarsFunctionWorker()
 
 // Map of available function workers by worker type
 var availableFunctionWorkers = map[string]api.FunctionWorker{
@@ -140,9 +210,19 @@ var availableFunctionWorkers = map[string]api.FunctionWorker{
 	WorkerTypeFluxOCIWriter:       k8sFunctionWorker,
 	WorkerTypeOpenTofuAWS:         opentofuFunctionWorker,
 	WorkerTypePropertiesConfigMap: propertiesFunctionWorker,
+	WorkerTypeAppConfigYAML:       appConfigYAMLFunctionWorker,
+	WorkerTypeAnsibleVars:         ansibleVarsFunctionWorker,
+	WorkerTypeVault:               k8sFunctionWorker,
 }
 
 func rootPreRunE(cmd *cobra.Command, args []string) error {
+	logger, err := newWorkerLogger(rootArgs.logFormat, rootArgs.logLevel)
+	if err != nil {
+		return err
+	}
+	workerLogger = logger
+	log.SetLogger(workerLogger)
+
 	// ignore required flag marking for version command
 	if cmd != versionCmd {
 		if os.Getenv("CONFIGHUB_WORKER_ID") == "" {
@@ -167,71 +247,81 @@ func workerTypeToToolchainAndProvider(workerType string) (workerapi.ToolchainTyp
 		return workerapi.ToolchainOpenTofuHCL, api.ProviderAWS
 	case WorkerTypePropertiesConfigMap:
 		return workerapi.ToolchainAppConfigProperties, api.ProviderConfigMap
+	case WorkerTypeAppConfigYAML:
+		return workerapi.ToolchainAppConfigYAML, api.ProviderConfigMap
+	case WorkerTypeAnsibleVars:
+		return workerapi.ToolchainAnsibleVars, api.ProviderConfigMap
+	case WorkerTypeVault:
+		return workerapi.ToolchainKubernetesYAML, api.ProviderVault
 	default:
 		return "", ""
 	}
 }
 
-func rootRunE(cmd *cobra.Command, args []string) error {
-	// Check if multiplexer mode is enabled
-	if !rootArgs.enableMultiplexer {
-		log.FromContext(context.Background()).Info("Running in legacy mode (multiplexer disabled by default)")
-		
-		// In legacy mode, only support single worker type
-		if strings.Contains(args[0], ",") {
-			return fmt.Errorf("multiple worker types not supported in legacy mode. Enable multiplexer with --enable-multiplexer or ENABLE_MULTIPLEXER=true")
-		}
-		
-		// Use the old behavior - direct worker without dispatcher
-		bridgeWorker, ok := availableBridgeWorkers[args[0]]
-		if !ok {
-			return fmt.Errorf("unknown bridge worker %s", args[0])
-		}
-		
-		if args[0] == WorkerTypeFluxOCIWriter {
-			// Additional initialization for FluxOCIWorker
-			if fluxWorker, ok := bridgeWorker.(*impl.FluxOCIWorker); ok {
-				err := impl.NewFluxOCIWorkerConfig(fluxWorker,
-					rootArgs.inCluster,
-					rootArgs.authMethod,
-					rootArgs.kubernetesSecretPath,
-				)
-				if err != nil {
-					return fmt.Errorf("failed to initialize FluxOCIWorker: %w", err)
-				}
-			}
+func initVaultBridgeWorker(vaultWorker *impl.VaultBridgeWorker) error {
+	err := impl.NewVaultBridgeWorkerConfig(vaultWorker,
+		rootArgs.vaultAddress,
+		rootArgs.vaultMountPath,
+		rootArgs.vaultKubernetesRole,
+		rootArgs.vaultKubernetesAuth,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to initialize VaultBridgeWorker: %w", err)
+	}
+	return nil
+}
+
+// newBridgeWorker returns the bridge worker for the worker type, initialized from the flags if it
+// requires configuration.
+func newBridgeWorker(workerType string) (api.BridgeWorker, error) {
+	switch workerType {
+	case WorkerTypeFluxOCIWriter:
+		fluxWorker := impl.NewFluxOCIWorker()
+		err := impl.NewFluxOCIWorkerConfig(fluxWorker,
+			rootArgs.inCluster,
+			rootArgs.authMethod,
+			rootArgs.kubernetesSecretPath,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize FluxOCIWorker: %w", err)
 		}
-		
-		functionWorker, ok := availableFunctionWorkers[args[0]]
-		if !ok {
-			return fmt.Errorf("unknown function worker %s", args[0])
+		return fluxWorker, nil
+	case WorkerTypeVault:
+		vaultWorker := impl.NewVaultBridgeWorker()
+		if err := initVaultBridgeWorker(vaultWorker); err != nil {
+			return nil, err
 		}
-		
-		// Use legacy mode without dispatcher
-		return runWorkerLegacy(bridgeWorker, functionWorker)
+		return vaultWorker, nil
+	}
+	bridgeWorker, ok := availableBridgeWorkers[workerType]
+	if !ok {
+		return nil, fmt.Errorf("unknown bridge worker type %s", workerType)
 	}
+	return bridgeWorker, nil
+}
 
-	// New multiplexer mode
+func rootRunE(cmd *cobra.Command, args []string) error {
 	// workerType is a comma separated string like "kubernetes,flux-oci-writer"
-	// Get the input worker types string from command-line arguments
-	workerTypesStr := args[0]
-
-	// Split the worker types string by comma
-	workerTypes := strings.Split(workerTypesStr, ",")
+	workerTypes := strings.Split(args[0], ",")
 
-	// Initialize appropriate workers based on the input
+	// The workers of all of the worker types are registered with dispatchers, which route each
+	// operation to the worker for its toolchain and provider and serialize operations on the same unit.
 	bridgeDispatcher := impl.NewBridgeDispatcher()
 	functionDispatcher := impl.NewFunctionDispatcher()
 
-	// Only enable prefixes if multiplexer mode is explicitly enabled
-	if !rootArgs.enableMultiplexer {
-		bridgeDispatcher.SetDisablePrefixes(true)
+	// The multiplexer mode only determines whether the targets of each provider are prefixed,
+	// such as with k8s-, so that they are distinct. Without it, the targets have the same slugs
+	// as those of workers that predate the dispatchers. It's enabled for multiple worker types
+	// unless it was disabled explicitly, as cub worker run does, since their targets could collide.
+	if len(workerTypes) > 1 && !rootArgs.enableMultiplexer {
+		if cmd.Flags().Changed("enable-multiplexer") || os.Getenv("ENABLE_MULTIPLEXER") != "" {
+			return fmt.Errorf("multiple worker types require the multiplexer mode; don't disable it with --enable-multiplexer or ENABLE_MULTIPLEXER")
+		}
+		rootArgs.enableMultiplexer = true
 	}
+	bridgeDispatcher.SetDisablePrefixes(!rootArgs.enableMultiplexer)
+	log.FromContext(context.Background()).Info("Starting worker", "workerTypes", workerTypes, "enableMultiplexer", rootArgs.enableMultiplexer)
 
-	// For multiple worker types or explicitly using generic worker, use dispatchers
-	log.FromContext(context.Background()).Info("Using dispatcher pattern for multi-worker support with unit-level serialization")
-
-	// Process each worker type and register with dispatchers
 	for _, workerType := range workerTypes {
 		// Convert worker type to toolchain type and provider type
 		toolchainType, providerType := workerTypeToToolchainAndProvider(workerType)
@@ -239,125 +329,170 @@ func rootRunE(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("could not determine toolchain/provider for worker type %s", workerType)
 		}
 
-		// Register bridge worker based on worker type
-		if directBridgeWorker, ok := availableBridgeWorkers[workerType]; ok {
-			// Special case for FluxOCIWriter - initialize it
-			if workerType == WorkerTypeFluxOCIWriter {
-				fluxWorker := impl.NewFluxOCIWorker()
-				err := impl.NewFluxOCIWorkerConfig(fluxWorker,
-					rootArgs.inCluster,
-					rootArgs.authMethod,
-					rootArgs.kubernetesSecretPath,
-				)
-				if err != nil {
-					return fmt.Errorf("failed to initialize FluxOCIWorker: %w", err)
-				}
-				// Use fresh instance for dispatcher registration
-				bridgeDispatcher.RegisterWorker(toolchainType, providerType, fluxWorker)
-			} else {
-				// Register other workers directly
-				bridgeDispatcher.RegisterWorker(toolchainType, providerType, directBridgeWorker)
-			}
-
-			log.FromContext(context.Background()).Info("Registered bridge worker",
-				"workerType", workerType,
-				"toolchainType", toolchainType,
-				"providerType", providerType)
-		} else {
-			return fmt.Errorf("unknown bridge worker type %s", workerType)
+		bridgeWorker, err := newBridgeWorker(workerType)
+		if err != nil {
+			return err
 		}
+		bridgeDispatcher.RegisterWorker(toolchainType, providerType, bridgeWorker)
+		log.FromContext(context.Background()).Info("Registered bridge worker",
+			"workerType", workerType,
+			"toolchainType", toolchainType,
+			"providerType", providerType)
 
-		// Register function worker based on worker type
-		if directFunctionWorker, ok := availableFunctionWorkers[workerType]; ok {
-			// Register with function dispatcher if not already registered
-			functionDispatcher.RegisterWorker(toolchainType, directFunctionWorker)
-
-			log.FromContext(context.Background()).Info("Registered function worker",
-				"workerType", workerType,
-				"toolchainType", toolchainType)
-		} else {
+		functionWorker, ok := availableFunctionWorkers[workerType]
+		if !ok {
 			return fmt.Errorf("unknown function worker type %s", workerType)
 		}
+		functionDispatcher.RegisterWorker(toolchainType, functionWorker)
+		log.FromContext(context.Background()).Info("Registered function worker",
+			"workerType", workerType,
+			"toolchainType", toolchainType)
 	}
 
+	return runWorker(context.Background(), cmd.Flags(), bridgeDispatcher, functionDispatcher)
+}
+
+// workerURL returns the URL of the ConfigHub server with the worker port.
+func workerURL() string {
 	// Check if the URL already contains a port
 	parsedURL, err := neturl.Parse(rootArgs.configHubURL)
 	if err != nil {
 		// Handle potential parsing error, though init() should prevent this
 		log.FromContext(context.Background()).Error(err, "Failed to parse configHubURL", "url", rootArgs.configHubURL)
-		// Decide on fallback behavior, e.g., use default or return error
 		// For now, let's proceed with the potentially malformed URL, assuming init handled basics
+		return rootArgs.configHubURL
 	}
 
-	finalURL := rootArgs.configHubURL // Default to original URL
-
-	if err == nil { // Only proceed if parsing was successful
-		hostname := parsedURL.Hostname() // Get hostname without port
-		if hostname == "" {
-			log.FromContext(context.Background()).Info("Could not extract hostname from URL, not modifying port", "url", rootArgs.configHubURL)
-		} else if parsedURL.Scheme == "" {
-			// Handle case where scheme is missing (though init tries to add https)
-			log.FromContext(context.Background()).Info("URL scheme is missing, cannot reliably reconstruct URL with new port", "url", rootArgs.configHubURL)
-		} else {
-			// Always use the workerPort, replacing existing or appending
-			// Reconstruct the URL: scheme://hostname:workerPort
-			finalURL = fmt.Sprintf("%s://%s:%s", parsedURL.Scheme, hostname, rootArgs.workerPort)
-		}
-	} // Note: If err != nil, finalURL remains rootArgs.configHubURL
-
-	w := lib.New(finalURL, // Use the potentially modified URL
-		rootArgs.workerID,
-		rootArgs.workerSecret).
-		WithBridgeWorker(bridgeDispatcher).
-		WithFunctionWorker(functionDispatcher)
-	if err := w.Start(context.Background()); err != nil {
-		log.FromContext(context.Background()).Error(err, "failed to start worker")
-		return err
+	hostname := parsedURL.Hostname() // Get hostname without port
+	if hostname == "" {
+		log.FromContext(context.Background()).Info("Could not extract hostname from URL, not modifying port", "url", rootArgs.configHubURL)
+		return rootArgs.configHubURL
 	}
-	return nil
+	if parsedURL.Scheme == "" {
+		// Handle case where scheme is missing (though init tries to add https)
+		log.FromContext(context.Background()).Info("URL scheme is missing, cannot reliably reconstruct URL with new port", "url", rootArgs.configHubURL)
+		return rootArgs.configHubURL
+	}
+	// Always use the workerPort, replacing existing or appending
+	// Reconstruct the URL: scheme://hostname:workerPort
+	return fmt.Sprintf("%s://%s:%s", parsedURL.Scheme, hostname, rootArgs.workerPort)
 }
 
-func runWorkerLegacy(bridgeWorker api.BridgeWorker, functionWorker api.FunctionWorker) error {
-	// Check if the URL already contains a port
-	parsedURL, err := neturl.Parse(rootArgs.configHubURL)
-	if err != nil {
-		// Handle potential parsing error, though init() should prevent this
-		log.FromContext(context.Background()).Error(err, "Failed to parse configHubURL", "url", rootArgs.configHubURL)
-		// Decide on fallback behavior, e.g., use default or return error
-		// For now, let's proceed with the potentially malformed URL, assuming init handled basics
+// reloadWorkerConfig re-reads the ConfigHub URL, worker port, worker ID, and worker secret from
+// the environment. Values specified by flags take precedence and aren't reloaded.
+func reloadWorkerConfig(flags *pflag.FlagSet) {
+	if !flags.Changed("url") {
+		rootArgs.configHubURL = configHubURLFromEnv()
+	}
+	if !flags.Changed("worker-port") {
+		rootArgs.workerPort = workerPortFromEnv()
 	}
+	if !flags.Changed("worker-id") {
+		rootArgs.workerID = os.Getenv("CONFIGHUB_WORKER_ID")
+	}
+	if !flags.Changed("worker-secret") {
+		rootArgs.workerSecret = os.Getenv("CONFIGHUB_WORKER_SECRET")
+	}
+}
 
-	finalURL := rootArgs.configHubURL // Default to original URL
-
-	if err == nil { // Only proceed if parsing was successful
-		hostname := parsedURL.Hostname() // Get hostname without port
-		if hostname == "" {
-			log.FromContext(context.Background()).Info("Could not extract hostname from URL, not modifying port", "url", rootArgs.configHubURL)
-		} else if parsedURL.Scheme == "" {
-			// Handle case where scheme is missing (though init tries to add https)
-			log.FromContext(context.Background()).Info("URL scheme is missing, cannot reliably reconstruct URL with new port", "url", rootArgs.configHubURL)
-		} else {
-			// Always use the workerPort, replacing existing or appending
-			// Reconstruct the URL: scheme://hostname:workerPort
-			finalURL = fmt.Sprintf("%s://%s:%s", parsedURL.Scheme, hostname, rootArgs.workerPort)
+// runWorker runs a worker with the specified bridge and function workers until it exits. On
+// SIGHUP, the worker stops receiving new events and waits for the operations in progress to
+// complete, and then a new worker is started with the configuration reloaded from the environment.
+// On SIGTERM or SIGINT, the worker is drained the same way, waiting at most the drain timeout,
+// and runWorker returns nil. A second SIGTERM or SIGINT exits the process immediately. When ctx
+// is done, the operations in progress are canceled and runWorker returns nil.
+func runWorker(ctx context.Context, flags *pflag.FlagSet, bridgeWorker api.BridgeWorker, functionWorker api.FunctionWorker) error {
+	// The logger is available to the bridge and function workers via log.FromContext
+	ctx, cancel := context.WithCancel(log.IntoContext(ctx, workerLogger))
+	defer cancel()
+	reload := signalhandler.Subscribe(ctx, syscall.SIGHUP)
+	shutdown := signalhandler.Subscribe(ctx, syscall.SIGTERM, syscall.SIGINT)
+
+	for {
+		w := lib.New(workerURL(),
+			rootArgs.workerID,
+			rootArgs.workerSecret).
+			WithBridgeWorker(bridgeWorker).
+			WithFunctionWorker(functionWorker).
+			WithLogger(workerLogger)
+		errc := make(chan error, 1)
+		go func() {
+			errc <- w.Start(ctx)
+		}()
+
+		select {
+		case err := <-errc:
+			if err != nil {
+				log.FromContext(ctx).Error(err, "failed to start worker")
+			}
+			return err
+		case <-ctx.Done():
+			if err := <-errc; err != nil {
+				log.FromContext(ctx).Error(err, "worker stopped with an error")
+			}
+			return nil
+		case <-reload:
+			log.FromContext(ctx).Info("Received SIGHUP, reloading worker configuration", "drainTimeout", rootArgs.drainTimeout.String())
+			drainCtx, drainCancel := context.WithTimeout(ctx, rootArgs.drainTimeout)
+			if err := w.Stop(drainCtx); err != nil {
+				log.FromContext(ctx).Error(err, "operations didn't complete within the drain timeout and were canceled")
+			}
+			err := <-errc
+			drainCancel()
+			if err != nil {
+				log.FromContext(ctx).Error(err, "worker stopped with an error")
+			}
+			reloadWorkerConfig(flags)
+		case sig := <-shutdown:
+			log.FromContext(ctx).Info("Received signal, draining the operations in progress", "signal", sig.String(), "drainTimeout", rootArgs.drainTimeout.String())
+			go func() {
+				select {
+				case sig := <-shutdown:
+					log.FromContext(ctx).Info("Received second signal, exiting immediately", "signal", sig.String())
+					os.Exit(1)
+				case <-ctx.Done():
+				}
+			}()
+			drainCtx, drainCancel := context.WithTimeout(ctx, rootArgs.drainTimeout)
+			if err := w.Stop(drainCtx); err != nil {
+				log.FromContext(ctx).Error(err, "operations didn't complete within the drain timeout and were canceled")
+			}
+			err := <-errc
+			drainCancel()
+			if err != nil {
+				log.FromContext(ctx).Error(err, "worker stopped with an error")
+			}
+			return nil
 		}
-	} // Note: If err != nil, finalURL remains rootArgs.configHubURL
-
-	w := lib.New(finalURL, // Use the potentially modified URL
-		rootArgs.workerID,
-		rootArgs.workerSecret).
-		WithBridgeWorker(bridgeWorker).
-		WithFunctionWorker(functionWorker)
-	if err := w.Start(context.Background()); err != nil {
-		log.FromContext(context.Background()).Error(err, "failed to start worker")
-		return err
 	}
-	return nil
+}
+
+// workerLogger is the logger used by the worker and the bridge and function worker implementations.
+// It is replaced by the logger configured by --log-format and --log-level before the command runs.
+var workerLogger = zap.New(zap.UseDevMode(true))
+
+// newWorkerLogger returns a logger that writes messages of at least the specified level in the
+// specified format.
+func newWorkerLogger(format, level string) (logr.Logger, error) {
+	zapLevel, ok := logLevels[level]
+	if !ok {
+		return logr.Logger{}, fmt.Errorf("invalid --log-level %q: must be debug, info, warn, or error", level)
+	}
+	var opts []zap.Opts
+	switch format {
+	case logFormatDev:
+		opts = append(opts, zap.UseDevMode(true))
+	case logFormatJSON:
+		opts = append(opts, zap.UseDevMode(false), zap.JSONEncoder())
+	default:
+		return logr.Logger{}, fmt.Errorf("invalid --log-format %q: must be %s or %s", format, logFormatDev, logFormatJSON)
+	}
+	opts = append(opts, zap.Level(zapLevel))
+	return zap.New(opts...), nil
 }
 
 func main() {
-	logr := zap.New(zap.UseDevMode(true))
-	log.SetLogger(logr)
+	log.SetLogger(workerLogger)
 	if err := rootCmd.Execute(); err != nil {
 		log.FromContext(context.Background()).Error(err, "failed to execute command")
 	}
@@ -0,0 +1,223 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/google/uuid"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+)
+
+// testBridgeWorker only implements Info; the other operations aren't expected to be invoked.
+type testBridgeWorker struct {
+	api.BridgeWorker
+}
+
+func (*testBridgeWorker) Info(api.InfoOptions) api.BridgeWorkerInfo {
+	return api.BridgeWorkerInfo{}
+}
+
+// testFunctionWorker only implements Info; functions aren't expected to be invoked.
+type testFunctionWorker struct {
+	api.FunctionWorker
+}
+
+func (*testFunctionWorker) Info() api.FunctionWorkerInfo {
+	return api.FunctionWorkerInfo{}
+}
+
+type workerConnection struct {
+	workerID      string
+	authorization string
+}
+
+func TestRunWorkerReloadsConfigurationOnSIGHUP(t *testing.T) {
+	connections := make(chan workerConnection, 10)
+	server := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workerID := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/bridge_worker/"), "/")[0]
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/me"):
+			_, _ = w.Write([]byte(`{"Slug": "test-worker"}`))
+		case strings.HasSuffix(r.URL.Path, "/stream"):
+			connections <- workerConnection{workerID: workerID, authorization: r.Header.Get("Authorization")}
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			// Keep the stream open until the worker closes it
+			<-r.Context().Done()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}), &http2.Server{}))
+	t.Cleanup(server.Close)
+	serverURL, err := neturl.Parse(server.URL)
+	assert.NoError(t, err)
+
+	t.Setenv("CONFIGHUB_URL", "http://"+serverURL.Hostname())
+	t.Setenv("CONFIGHUB_WORKER_PORT", serverURL.Port())
+	t.Setenv("CONFIGHUB_WORKER_ID", "first-worker-id")
+	t.Setenv("CONFIGHUB_WORKER_SECRET", "first-worker-secret")
+	reloadWorkerConfig(rootCmd.PersistentFlags())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		errc <- runWorker(ctx, rootCmd.PersistentFlags(), &testBridgeWorker{}, &testFunctionWorker{})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case err := <-errc:
+			assert.NoError(t, err)
+		case <-time.After(10 * time.Second):
+			t.Error("runWorker didn't return after its context was canceled")
+		}
+	})
+
+	waitForConnection := func() workerConnection {
+		select {
+		case connection := <-connections:
+			return connection
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the worker to connect")
+		}
+		return workerConnection{}
+	}
+	connection := waitForConnection()
+	assert.Equal(t, "first-worker-id", connection.workerID)
+	assert.Equal(t, "Bearer first-worker-secret", connection.authorization)
+
+	t.Setenv("CONFIGHUB_WORKER_ID", "second-worker-id")
+	t.Setenv("CONFIGHUB_WORKER_SECRET", "second-worker-secret")
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+
+	connection = waitForConnection()
+	assert.Equal(t, "second-worker-id", connection.workerID)
+	assert.Equal(t, "Bearer second-worker-secret", connection.authorization)
+}
+
+// slowApplyBridgeWorker implements an Apply that takes applyDuration to complete.
+type slowApplyBridgeWorker struct {
+	testBridgeWorker
+	applyDuration time.Duration
+	started       chan struct{}
+	completed     chan error
+}
+
+func (w *slowApplyBridgeWorker) Apply(workerContext api.BridgeWorkerContext, _ api.BridgeWorkerPayload) error {
+	close(w.started)
+	select {
+	case <-time.After(w.applyDuration):
+		w.completed <- nil
+	case <-workerContext.Context().Done():
+		w.completed <- workerContext.Context().Err()
+	}
+	return nil
+}
+
+func TestRunWorkerDrainsOnSIGTERM(t *testing.T) {
+	applyEvent, err := json.Marshal(api.EventMessage{
+		Event: api.EventBridgeWorker,
+		Data: api.BridgeWorkerEventRequest{
+			Action:  api.ActionApply,
+			Payload: api.BridgeWorkerPayload{UnitID: uuid.New(), SpaceID: uuid.New()},
+		},
+	})
+	assert.NoError(t, err)
+	server := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/me"):
+			_, _ = w.Write([]byte(`{"Slug": "test-worker"}`))
+		case strings.HasSuffix(r.URL.Path, "/stream"):
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprintf(w, "data: %s\n", applyEvent)
+			w.(http.Flusher).Flush()
+			// Keep the stream open until the worker closes it
+			<-r.Context().Done()
+		case strings.HasSuffix(r.URL.Path, "/action_result"):
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}), &http2.Server{}))
+	defer server.Close()
+	serverURL, err := neturl.Parse(server.URL)
+	assert.NoError(t, err)
+
+	t.Setenv("CONFIGHUB_URL", "http://"+serverURL.Hostname())
+	t.Setenv("CONFIGHUB_WORKER_PORT", serverURL.Port())
+	t.Setenv("CONFIGHUB_WORKER_ID", "drain-worker-id")
+	t.Setenv("CONFIGHUB_WORKER_SECRET", "drain-worker-secret")
+	reloadWorkerConfig(rootCmd.PersistentFlags())
+	savedDrainTimeout := rootArgs.drainTimeout
+	rootArgs.drainTimeout = 10 * time.Second
+	defer func() { rootArgs.drainTimeout = savedDrainTimeout }()
+
+	bridgeWorker := &slowApplyBridgeWorker{
+		applyDuration: 500 * time.Millisecond,
+		started:       make(chan struct{}),
+		completed:     make(chan error, 1),
+	}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- runWorker(context.Background(), rootCmd.PersistentFlags(), bridgeWorker, &testFunctionWorker{})
+	}()
+
+	select {
+	case <-bridgeWorker.started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the apply to start")
+	}
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+
+	select {
+	case err := <-errc:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("runWorker didn't return after SIGTERM")
+	}
+	// The apply was allowed to finish before runWorker returned
+	select {
+	case err := <-bridgeWorker.completed:
+		assert.NoError(t, err)
+	default:
+		t.Fatal("the apply didn't complete")
+	}
+}
+
+func TestNewWorkerLogger(t *testing.T) {
+	for _, format := range []string{logFormatDev, logFormatJSON} {
+		logger, err := newWorkerLogger(format, "debug")
+		assert.NoError(t, err)
+		assert.True(t, logger.V(1).Enabled(), "debug messages should be logged with --log-format %s", format)
+	}
+	logger, err := newWorkerLogger(logFormatJSON, "info")
+	assert.NoError(t, err)
+	assert.True(t, logger.Enabled())
+	assert.False(t, logger.V(1).Enabled())
+
+	// There are no warnings in logr, so only errors are logged
+	logger, err = newWorkerLogger(logFormatJSON, "warn")
+	assert.NoError(t, err)
+	assert.False(t, logger.Enabled())
+
+	_, err = newWorkerLogger("text", "info")
+	assert.ErrorContains(t, err, "--log-format")
+	_, err = newWorkerLogger(logFormatDev, "trace")
+	assert.ErrorContains(t, err, "--log-level")
+}
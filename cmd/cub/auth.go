@@ -23,10 +23,11 @@ func init() {
 }
 
 func LoadSession() (AuthSession, error) {
-	var session AuthSession
+	return loadSessionFile(sessionFilePath(""))
+}
 
-	// Define the config file path
-	configFile := filepath.Join(os.Getenv("HOME"), ".confighub", "session.json")
+func loadSessionFile(configFile string) (AuthSession, error) {
+	var session AuthSession
 
 	// Read the JSON data from the file
 	data, err := os.ReadFile(configFile)
@@ -43,12 +44,12 @@ func LoadSession() (AuthSession, error) {
 }
 
 func SaveSession(session AuthSession) error {
-	// Define the config directory and file path
-	configDir := filepath.Join(os.Getenv("HOME"), ".confighub")
-	configFile := filepath.Join(configDir, "session.json")
+	return saveSessionFile(sessionFilePath(""), session)
+}
 
+func saveSessionFile(configFile string, session AuthSession) error {
 	// Ensure the config directory exists
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
@@ -12,7 +12,6 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"sync"
 	"time"
@@ -291,12 +290,12 @@ func getSession(clientID string, codeVerifier string, authorizationCode string,
 
 // AuthorizeWorker implements worker authentication using JWT
 func AuthorizeWorker() error {
-	// Get worker credentials from environment variables
-	workerID := os.Getenv("CONFIGHUB_WORKER_ID")
-	workerSecret := os.Getenv("CONFIGHUB_WORKER_SECRET")
+	// Get worker credentials from environment variables or the configuration file
+	workerID := configValue("worker-id")
+	workerSecret := configValue("worker-secret")
 
 	if workerID == "" || workerSecret == "" {
-		return fmt.Errorf("CONFIGHUB_WORKER_ID and CONFIGHUB_WORKER_SECRET environment variables must be set")
+		return fmt.Errorf("CONFIGHUB_WORKER_ID and CONFIGHUB_WORKER_SECRET environment variables, or worker-id and worker-secret in %s, must be set", ConfigFileEnvVar)
 	}
 
 	// Create the request body
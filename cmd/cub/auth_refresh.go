@@ -0,0 +1,106 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var authRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Renew the access token",
+	Long: `Renew the access token of the current session using its refresh token, without logging in again. The stored session is updated with the new tokens.
+
+Access tokens that expire within 5 minutes are also renewed automatically before running other commands.
+
+Examples:
+  # Renew the access token of the current context
+  cub auth refresh
+
+  # Renew the access token of a saved context
+  cub auth refresh --context prod`,
+	Args: cobra.ExactArgs(0),
+	RunE: authRefreshCmdRun,
+}
+
+func init() {
+	authCmd.AddCommand(authRefreshCmd)
+}
+
+// tokenRefreshWindow is how long before its expiration an access token is renewed automatically
+const tokenRefreshWindow = 5 * time.Minute
+
+func authRefreshCmdRun(cmd *cobra.Command, args []string) error {
+	if err := refreshSession(); err != nil {
+		return err
+	}
+	if !quiet {
+		if expiresAt, ok := accessTokenExpiry(authSession.AccessToken); ok {
+			tprint("Access token renewed; it expires at %s", expiresAt.Local().Format(time.RFC3339))
+		} else {
+			tprint("Access token renewed")
+		}
+	}
+	return nil
+}
+
+// accessTokenExpiry returns the expiration time of a JWT access token and true, or false if the
+// token isn't a JWT with an expiration time. The signature isn't verified.
+func accessTokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
+
+// accessTokenExpiring returns true if the access token of the session expires within
+// tokenRefreshWindow and can be renewed.
+func accessTokenExpiring(session *AuthSession, now time.Time) bool {
+	if session.AuthType == AuthTypeBasic || session.RefreshToken == "" {
+		return false
+	}
+	expiresAt, ok := accessTokenExpiry(session.AccessToken)
+	return ok && expiresAt.Sub(now) < tokenRefreshWindow
+}
+
+// refreshSession renews the tokens of authSession for its organization and saves the session to
+// the session file of the selected context.
+func refreshSession() error {
+	if authSession.AuthType == AuthTypeBasic {
+		return errors.New("basic authentication sessions don't have access tokens to renew")
+	}
+	if authSession.RefreshToken == "" {
+		return newAppError(ExitAuthError, errors.New("no refresh token found. Please run 'cub auth login' first"))
+	}
+	// Switching to the current organization issues new tokens
+	newTokens, err := callSwitchOrganizationAPI(authSession.RefreshToken, authSession.OrganizationID)
+	if err != nil {
+		return newAppError(ExitAuthError, fmt.Errorf("failed to renew access token: %w", err))
+	}
+	authSession.AccessToken = newTokens.AccessToken
+	authSession.RefreshToken = newTokens.RefreshToken
+	if err := saveSessionFile(sessionFilePath(selectedContextName), authSession); err != nil {
+		return err
+	}
+	authHeader = setAuthHeader(&authSession)
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAccessToken returns an unsigned JWT expiring at expiresAt.
+func testAccessToken(name string, expiresAt time.Time) string {
+	payload := fmt.Sprintf(`{"sub":%q,"exp":%d}`, name, expiresAt.Unix())
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig"
+}
+
+func saveAuthGlobals(t *testing.T) {
+	t.Helper()
+	savedSession, savedHeader, savedContext, savedClient := authSession, authHeader, cubContext, cubClientNew
+	t.Cleanup(func() {
+		authSession, authHeader, cubContext, cubClientNew = savedSession, savedHeader, savedContext, savedClient
+	})
+}
+
+func TestAuthWhoami(t *testing.T) {
+	saveAuthGlobals(t)
+	cubContext.ConfigHubURL = "https://hub.example.com"
+
+	authSession = AuthSession{User: User{Email: "test@example.com"}, AuthType: AuthTypeBasic, BasicAuthPassword: "secret"}
+	output := captureStdout(t, func() { assert.NoError(t, authWhoamiCmdRun(authWhoamiCmd, nil)) })
+	assert.Equal(t, "test@example.com\n", output)
+
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	authSession = AuthSession{
+		User:           User{ID: "user_01", Email: "ada@example.com", FirstName: "Ada", LastName: "Lovelace"},
+		AccessToken:    testAccessToken("user_01", expiresAt),
+		OrganizationID: "org_01",
+		AuthType:       "Bearer",
+	}
+	output = captureStdout(t, func() { assert.NoError(t, authWhoamiCmdRun(authWhoamiCmd, nil)) })
+	for _, expected := range []string{"ada@example.com", "Ada Lovelace", "user_01", "org_01", "https://hub.example.com"} {
+		assert.Contains(t, output, expected)
+	}
+	result := currentWhoami()
+	if assert.NotNil(t, result.ExpiresAt) {
+		assert.True(t, expiresAt.Equal(*result.ExpiresAt))
+	}
+}
+
+func TestAccessTokenExpiring(t *testing.T) {
+	now := time.Now()
+	session := &AuthSession{AccessToken: testAccessToken("a", now.Add(time.Minute)), RefreshToken: "refresh", AuthType: "Bearer"}
+	assert.True(t, accessTokenExpiring(session, now))
+	session.AccessToken = testAccessToken("a", now.Add(time.Hour))
+	assert.False(t, accessTokenExpiring(session, now))
+	session.AccessToken = "opaque"
+	assert.False(t, accessTokenExpiring(session, now))
+	session.AccessToken = testAccessToken("a", now.Add(-time.Minute))
+	session.RefreshToken = ""
+	assert.False(t, accessTokenExpiring(session, now))
+}
+
+func TestGlobalPreRunRefreshesExpiringToken(t *testing.T) {
+	saveAuthGlobals(t)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(ConfigFileEnvVar, "")
+
+	renewed := testAccessToken("user_01", time.Now().Add(time.Hour))
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/auth/switch-organization", r.URL.Path)
+		assert.Equal(t, "org_01", r.URL.Query().Get("organization_id"))
+		cookie, err := r.Cookie("confighub_refresh_token")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "refresh-1", cookie.Value)
+		}
+		http.SetCookie(w, &http.Cookie{Name: "confighub_session", Value: renewed})
+		http.SetCookie(w, &http.Cookie{Name: "confighub_refresh_token", Value: "refresh-2"})
+		w.Header().Set("Location", "/")
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer server.Close()
+	cubContext.ConfigHubURL = server.URL
+
+	session := AuthSession{
+		User:           User{Email: "ada@example.com"},
+		AccessToken:    testAccessToken("user_01", time.Now().Add(time.Minute)),
+		RefreshToken:   "refresh-1",
+		OrganizationID: "org_01",
+		AuthType:       "Bearer",
+	}
+	require.NoError(t, SaveSession(session))
+
+	cmd := &cobra.Command{Use: "list"}
+	require.NoError(t, globalPreRun(cmd, nil))
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, renewed, authSession.AccessToken)
+	saved, err := LoadSession()
+	require.NoError(t, err)
+	assert.Equal(t, renewed, saved.AccessToken)
+	assert.Equal(t, "refresh-2", saved.RefreshToken)
+
+	// The renewed token isn't expiring, so it isn't renewed again
+	require.NoError(t, globalPreRun(cmd, nil))
+	assert.Equal(t, 1, requests)
+}
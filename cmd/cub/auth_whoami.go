@@ -0,0 +1,91 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var authWhoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Display the authenticated user",
+	Long: `Display the user and organization of the current session.
+
+Examples:
+  # Display the user of the current context
+  cub auth whoami
+
+  # Display the email of the user
+  cub auth whoami --jq .Email`,
+	Args: cobra.ExactArgs(0),
+	RunE: authWhoamiCmdRun,
+}
+
+func init() {
+	enableJsonFlag(authWhoamiCmd)
+	enableJqFlag(authWhoamiCmd)
+	authCmd.AddCommand(authWhoamiCmd)
+}
+
+type whoami struct {
+	Email          string
+	Name           string     `json:",omitempty"`
+	UserID         string     `json:",omitempty"`
+	OrganizationID string     `json:",omitempty"`
+	AuthType       string     `json:",omitempty"`
+	ConfigHubURL   string     `json:",omitempty"`
+	ExpiresAt      *time.Time `json:",omitempty"`
+}
+
+func currentWhoami() whoami {
+	result := whoami{
+		Email:        authSession.User.Email,
+		AuthType:     authSession.AuthType,
+		ConfigHubURL: cubContext.ConfigHubURL,
+	}
+	if authSession.AuthType == AuthTypeBasic {
+		return result
+	}
+	result.Name = strings.TrimSpace(authSession.User.FirstName + " " + authSession.User.LastName)
+	result.UserID = authSession.User.ID
+	result.OrganizationID = authSession.OrganizationID
+	if expiresAt, ok := accessTokenExpiry(authSession.AccessToken); ok {
+		result.ExpiresAt = &expiresAt
+	}
+	return result
+}
+
+func authWhoamiCmdRun(cmd *cobra.Command, args []string) error {
+	result := currentWhoami()
+	if !jsonOutput && jq == "" {
+		displayWhoami(result)
+	}
+	if jsonOutput {
+		displayJSON(result)
+	}
+	if jq != "" {
+		displayJQ(result)
+	}
+	return nil
+}
+
+func displayWhoami(result whoami) {
+	if result.AuthType == AuthTypeBasic {
+		tprint("%s", result.Email)
+		return
+	}
+	detail := detailView()
+	detail.Append([]string{"Email", result.Email})
+	detail.Append([]string{"Name", result.Name})
+	detail.Append([]string{"User ID", result.UserID})
+	detail.Append([]string{"Organization ID", result.OrganizationID})
+	detail.Append([]string{"Server", result.ConfigHubURL})
+	if result.ExpiresAt != nil {
+		detail.Append([]string{"Token Expires At", result.ExpiresAt.Local().Format(time.RFC3339)})
+	}
+	detail.Render()
+}
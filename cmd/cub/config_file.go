@@ -0,0 +1,101 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileEnvVar is the environment variable specifying the path of a YAML or JSON configuration
+// file. The keys of the file are flag names, such as debug, space, or output, whose values are used
+// for flags not specified on the command line. In addition, the following keys are recognized:
+//
+//	url            ConfigHub server URL; overridden by CONFIGHUB_URL
+//	worker-id      worker ID used by auth login --as-worker; overridden by CONFIGHUB_WORKER_ID
+//	worker-secret  worker secret used by auth login --as-worker; overridden by CONFIGHUB_WORKER_SECRET
+//
+// The values of the file are overridden by environment variables, which are overridden by flags.
+// Flags set from the file are not marked as changed, so they behave like defaults: in particular,
+// required flags must still be specified on the command line.
+const ConfigFileEnvVar = "CONFIGHUB_CONFIG_FILE"
+
+// configFileEnvVars maps keys of the configuration file to the environment variables that override them
+var configFileEnvVars = map[string]string{
+	"url":           "CONFIGHUB_URL",
+	"worker-id":     "CONFIGHUB_WORKER_ID",
+	"worker-secret": "CONFIGHUB_WORKER_SECRET",
+	"debug":         "CONFIGHUB_DEBUG",
+	"no-color":      "NO_COLOR",
+}
+
+// cubConfig contains the values loaded from the configuration file, if any
+var cubConfig map[string]any
+
+// loadConfigFile loads the configuration file specified by CONFIGHUB_CONFIG_FILE, if set.
+func loadConfigFile() error {
+	cubConfig = nil
+	configFile := os.Getenv(ConfigFileEnvVar)
+	if configFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s %s: %w", ConfigFileEnvVar, configFile, err)
+	}
+	// JSON is a subset of YAML
+	var config map[string]any
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse %s %s: %w", ConfigFileEnvVar, configFile, err)
+	}
+	cubConfig = config
+	return nil
+}
+
+// configValue returns the value of a string setting from the environment variable overriding key,
+// if set, or else from the configuration file.
+func configValue(key string) string {
+	if envVar, ok := configFileEnvVars[key]; ok && os.Getenv(envVar) != "" {
+		return os.Getenv(envVar)
+	}
+	value, ok := cubConfig[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// applyConfigFileFlags sets the flags of cmd that weren't specified on the command line to the
+// values in the configuration file, unless overridden by environment variables or set from the
+// profile, as reported by applyProfileFlags.
+func applyConfigFileFlags(cmd *cobra.Command, profileFlags map[string]bool) error {
+	if len(cubConfig) == 0 {
+		return nil
+	}
+	var err error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		value, ok := cubConfig[flag.Name]
+		if err != nil || !ok || value == nil || flag.Changed || profileFlags[flag.Name] {
+			return
+		}
+		if envVar, ok := configFileEnvVars[flag.Name]; ok && os.Getenv(envVar) != "" {
+			return
+		}
+		values, isList := value.([]any)
+		if !isList {
+			values = []any{value}
+		}
+		for _, v := range values {
+			if setErr := flag.Value.Set(fmt.Sprint(v)); setErr != nil {
+				err = fmt.Errorf("invalid value %v for %s in %s: %w", v, flag.Name, ConfigFileEnvVar, setErr)
+				return
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,119 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestConfigFile(t *testing.T, name, content string) {
+	t.Helper()
+	configFile := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(configFile, []byte(content), 0600))
+	t.Setenv(ConfigFileEnvVar, configFile)
+	require.NoError(t, loadConfigFile())
+	t.Cleanup(func() { cubConfig = nil })
+}
+
+func TestConfigFile_Values(t *testing.T) {
+	t.Setenv("CONFIGHUB_URL", "")
+	t.Setenv("CONFIGHUB_WORKER_ID", "")
+	writeTestConfigFile(t, ".confighub.yaml", `url: https://file.example.com
+worker-id: file-worker
+`)
+	assert.Equal(t, "https://file.example.com", configValue("url"))
+	assert.Equal(t, "file-worker", configValue("worker-id"))
+	assert.Equal(t, "", configValue("worker-secret"))
+
+	// Environment variables override the file
+	savedContext := cubContext
+	t.Cleanup(func() { cubContext = savedContext })
+	t.Setenv("CONFIGHUB_URL", "https://env.example.com")
+	assert.Equal(t, "https://env.example.com", configValue("url"))
+	assert.Equal(t, "env.example.com", getEnvURL().Host)
+}
+
+func TestConfigFile_JSON(t *testing.T) {
+	writeTestConfigFile(t, "confighub.json", `{"space": "json-space", "debug": true}`)
+	assert.Equal(t, "json-space", configValue("space"))
+	assert.Equal(t, "true", configValue("debug"))
+}
+
+func TestConfigFile_Invalid(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "bad.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("- not\n- a map\n"), 0600))
+	t.Setenv(ConfigFileEnvVar, configFile)
+	assert.ErrorContains(t, loadConfigFile(), "failed to parse "+ConfigFileEnvVar)
+
+	t.Setenv(ConfigFileEnvVar, filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, loadConfigFile(), "failed to read "+ConfigFileEnvVar)
+	cubConfig = nil
+}
+
+func TestConfigFile_ApplyFlags(t *testing.T) {
+	t.Setenv("CONFIGHUB_DEBUG", "")
+	writeTestConfigFile(t, ".confighub.yaml", `space: file-space
+quiet: true
+label:
+- a=1
+- b=2
+count: 5
+unknown-flag: ignored
+`)
+	var space string
+	var quiet bool
+	var labels []string
+	var count int
+	cmd := &cobra.Command{Use: "test", Run: func(*cobra.Command, []string) {}}
+	cmd.Flags().StringVar(&space, "space", "", "")
+	cmd.Flags().BoolVar(&quiet, "quiet", false, "")
+	cmd.Flags().StringSliceVar(&labels, "label", []string{"default=0"}, "")
+	cmd.Flags().IntVar(&count, "count", 1, "")
+	require.NoError(t, cmd.ParseFlags([]string{"--count", "7"}))
+
+	require.NoError(t, applyConfigFileFlags(cmd, nil))
+	assert.Equal(t, "file-space", space)
+	assert.True(t, quiet)
+	assert.Equal(t, []string{"a=1", "b=2"}, labels)
+	// Flags specified on the command line override the file
+	assert.Equal(t, 7, count)
+	// Values from the file are defaults rather than flags specified on the command line
+	assert.False(t, cmd.Flags().Changed("space"))
+	assert.True(t, cmd.Flags().Changed("count"))
+}
+
+func TestConfigFile_ApplyFlagsEnvOverride(t *testing.T) {
+	t.Setenv("CONFIGHUB_DEBUG", "0")
+	writeTestConfigFile(t, ".confighub.yaml", "debug: true\n")
+	var debugFlag bool
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().BoolVar(&debugFlag, "debug", false, "")
+	require.NoError(t, cmd.ParseFlags(nil))
+	require.NoError(t, applyConfigFileFlags(cmd, nil))
+	assert.False(t, debugFlag)
+}
+
+func TestConfigFile_ApplyFlagsInvalidValue(t *testing.T) {
+	writeTestConfigFile(t, ".confighub.yaml", "count: many\n")
+	var count int
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().IntVar(&count, "count", 1, "")
+	require.NoError(t, cmd.ParseFlags(nil))
+	assert.ErrorContains(t, applyConfigFileFlags(cmd, nil), "invalid value many for count in "+ConfigFileEnvVar)
+}
+
+func TestConfigFile_SpacePreRunReturnsErrors(t *testing.T) {
+	writeTestConfigFile(t, ".confighub.yaml", "count: many\n")
+	var count int
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().IntVar(&count, "count", 1, "")
+	require.NoError(t, cmd.ParseFlags(nil))
+	assert.ErrorContains(t, spacePreRunE(cmd, nil), "invalid value many for count in "+ConfigFileEnvVar)
+}
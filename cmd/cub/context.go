@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -19,6 +20,8 @@ var cubContext = CubContext{
 }
 
 type CubContext struct {
+	// Name is the name the context was saved as, if any
+	Name              string `json:"name,omitempty"`
 	ConfigHubURL      string `json:"-"`
 	ConfigHubURLSaved string `json:"confighub_url"`
 	Space             string `json:"space"`
@@ -36,9 +39,77 @@ func init() {
 	rootCmd.AddCommand(contextCmd)
 }
 
+// selectedContextName is the saved context selected with --context, overriding the current context
+var selectedContextName string
+
+// validateContextName checks that the name of a saved context can be used as part of a file
+// name in the ConfigHub directory.
+func validateContextName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || filepath.Base(name) != name {
+		return fmt.Errorf("invalid context name %q: context names must not be empty or contain path separators", name)
+	}
+	return nil
+}
+
+// contextFilePath returns the path of the file of the named saved context, or of the current
+// context if name is empty.
+func contextFilePath(name string) string {
+	if name == "" {
+		return filepath.Join(os.Getenv("HOME"), CONFIGHUB_DIR, "context.json")
+	}
+	return filepath.Join(os.Getenv("HOME"), CONFIGHUB_DIR, fmt.Sprintf("context-%s.json", name))
+}
+
+// sessionFilePath returns the path of the session file of the named saved context, or of the
+// current context if name is empty.
+func sessionFilePath(name string) string {
+	if name == "" {
+		return filepath.Join(os.Getenv("HOME"), CONFIGHUB_DIR, "session.json")
+	}
+	return filepath.Join(os.Getenv("HOME"), CONFIGHUB_DIR, fmt.Sprintf("session-%s.json", name))
+}
+
+// readSavedContext reads the named saved context, or the current context if name is empty,
+// without making it current.
+func readSavedContext(name string) (CubContext, error) {
+	var savedContext CubContext
+	if name != "" {
+		if err := validateContextName(name); err != nil {
+			return savedContext, err
+		}
+	}
+	data, err := os.ReadFile(contextFilePath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return savedContext, fmt.Errorf("context %s does not exist", name)
+		}
+		return savedContext, fmt.Errorf("failed to read context %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, &savedContext); err != nil {
+		return savedContext, fmt.Errorf("failed to unmarshal context %s: %w", name, err)
+	}
+	return savedContext, nil
+}
+
+// selectContext replaces the current context for this invocation with the named saved context
+// and returns its session. The saved default context is not changed.
+func selectContext(name string) (AuthSession, error) {
+	savedContext, err := readSavedContext(name)
+	if err != nil {
+		return AuthSession{}, err
+	}
+	if _, err := os.Stat(sessionFilePath(name)); err != nil {
+		return AuthSession{}, fmt.Errorf("context (session) %s does not exist", name)
+	}
+	cubContext = savedContext
+	_ = getEnvURL()
+	return loadSessionFile(sessionFilePath(name))
+}
+
 func LoadCubContext() {
+	failOnError(loadConfigFile())
 
-	contextFile := filepath.Join(os.Getenv("HOME"), CONFIGHUB_DIR, "context.json")
+	contextFile := contextFilePath("")
 	_, err := os.Stat(contextFile)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -0,0 +1,138 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var contextListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved contexts",
+	Long: `List the contexts saved with cub context save. The current context is marked with *.
+
+Examples:
+  # List saved contexts
+  cub context list`,
+	Args: cobra.ExactArgs(0),
+	RunE: contextListCmdRun,
+}
+
+var contextShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show a saved context",
+	Long: `Show the ConfigHub URL, user, space, and organization of a saved context, or of the current context if no name is specified.
+
+Examples:
+  # Show the context saved as staging
+  cub context show staging`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: contextShowCmdRun,
+}
+
+var contextDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved context",
+	Long: `Delete a context saved with cub context save. The current context is not changed.
+
+Examples:
+  # Delete the context saved as staging
+  cub context delete staging`,
+	Args: cobra.ExactArgs(1),
+	RunE: contextDeleteCmdRun,
+}
+
+func init() {
+	enableNoheaderFlag(contextListCmd)
+	contextCmd.AddCommand(contextListCmd)
+	contextCmd.AddCommand(contextShowCmd)
+	contextCmd.AddCommand(contextDeleteCmd)
+}
+
+// listSavedContextNames returns the names of the saved contexts, sorted.
+func listSavedContextNames() ([]string, error) {
+	matches, err := filepath.Glob(contextFilePath("*"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), "context-"), ".json")
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+func contextListCmdRun(_ *cobra.Command, _ []string) error {
+	names, err := listSavedContextNames()
+	if err != nil {
+		return err
+	}
+	table := tableView()
+	if !noheader {
+		table.SetHeader([]string{"Current", "Name", "ConfigHub URL", "Space"})
+	}
+	for _, name := range names {
+		savedContext, err := readSavedContext(name)
+		if err != nil {
+			return err
+		}
+		current := ""
+		if name == cubContext.Name {
+			current = "*"
+		}
+		table.Append([]string{current, name, savedContext.ConfigHubURLSaved, savedContext.Space})
+	}
+	table.Render()
+	return nil
+}
+
+func contextShowCmdRun(_ *cobra.Command, args []string) error {
+	shownContext := cubContext
+	shownContext.ConfigHubURLSaved = cubContext.ConfigHubURL
+	session := authSession
+	if len(args) > 0 {
+		var err error
+		shownContext, err = readSavedContext(args[0])
+		if err != nil {
+			return err
+		}
+		session, err = loadSessionFile(sessionFilePath(args[0]))
+		if err != nil {
+			return fmt.Errorf("context (session) %s does not exist", args[0])
+		}
+	}
+	view := tableView()
+	view.Append([]string{"Name", shownContext.Name})
+	view.Append([]string{"ConfigHub URL", shownContext.ConfigHubURLSaved})
+	view.Append([]string{"User Email", session.User.Email})
+	view.Append([]string{"Space", fmt.Sprintf("%s (%s)", shownContext.Space, shownContext.SpaceID)})
+	view.Append([]string{"Organization", shownContext.OrganizationID})
+	view.Render()
+	return nil
+}
+
+func contextDeleteCmdRun(_ *cobra.Command, args []string) error {
+	name := args[0]
+	if err := validateContextName(name); err != nil {
+		return err
+	}
+	if _, err := os.Stat(contextFilePath(name)); err != nil {
+		return fmt.Errorf("context %s does not exist", name)
+	}
+	for _, file := range []string{contextFilePath(name), sessionFilePath(name)} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	tprint("Context %s deleted", name)
+	return nil
+}
@@ -37,7 +37,11 @@ func init() {
 }
 
 func contextSaveCmdRun(cmd *cobra.Command, args []string) error {
+	if err := validateContextName(args[0]); err != nil {
+		return err
+	}
 	cubContext.ConfigHubURLSaved = cubContext.ConfigHubURL
+	cubContext.Name = args[0]
 	SaveCubContext(cubContext)
 	err := copyContextFiles(args[0])
 	if err != nil {
@@ -57,6 +61,9 @@ func contextUseCmdRun(cmd *cobra.Command, args []string) error {
 }
 
 func useContext(name string) error {
+	if err := validateContextName(name); err != nil {
+		return err
+	}
 	contextFile := filepath.Join(os.Getenv("HOME"), CONFIGHUB_DIR, fmt.Sprintf("context-%s.json", name))
 	_, err := os.Stat(contextFile)
 	if err != nil {
@@ -79,6 +86,9 @@ func useContext(name string) error {
 }
 
 func copyContextFiles(destName string) error {
+	if err := validateContextName(destName); err != nil {
+		return err
+	}
 	destContextFile := filepath.Join(os.Getenv("HOME"), CONFIGHUB_DIR, fmt.Sprintf("context-%s.json", destName))
 	destSessionFile := filepath.Join(os.Getenv("HOME"), CONFIGHUB_DIR, fmt.Sprintf("session-%s.json", destName))
 	if !overwrite {
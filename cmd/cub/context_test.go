@@ -0,0 +1,116 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestContext(t *testing.T, name, url, space, email string) {
+	t.Helper()
+	assert.NoError(t, os.MkdirAll(filepath.Join(os.Getenv("HOME"), CONFIGHUB_DIR), 0755))
+	data, err := json.Marshal(CubContext{Name: name, ConfigHubURLSaved: url, Space: space})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(contextFilePath(name), data, 0600))
+	data, err = json.Marshal(AuthSession{User: User{Email: email}, AccessToken: "token-" + space})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(sessionFilePath(name), data, 0600))
+}
+
+func setupTestContexts(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CONFIGHUB_URL", "")
+	savedContext := cubContext
+	t.Cleanup(func() { cubContext = savedContext })
+	writeTestContext(t, "staging", "https://staging.example.com", "staging-space", "dev@example.com")
+	writeTestContext(t, "prod", "https://prod.example.com", "prod-space", "ops@example.com")
+	// The current context is staging
+	writeTestContext(t, "", "https://staging.example.com", "staging-space", "dev@example.com")
+	data, err := os.ReadFile(contextFilePath("staging"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(contextFilePath(""), data, 0600))
+}
+
+func TestContextUseSwitchesCurrentContext(t *testing.T) {
+	setupTestContexts(t)
+
+	assert.NoError(t, useContext("prod"))
+	cubContext = CubContext{}
+	LoadCubContext()
+	_ = getEnvURL()
+	assert.Equal(t, "prod", cubContext.Name)
+	assert.Equal(t, "prod-space", cubContext.Space)
+	assert.Equal(t, "https://prod.example.com", cubContext.ConfigHubURL)
+	session, err := LoadSession()
+	assert.NoError(t, err)
+	assert.Equal(t, "ops@example.com", session.User.Email)
+
+	assert.EqualError(t, useContext("missing"), "context missing does not exist")
+}
+
+func TestContextFlagOverridesSavedDefault(t *testing.T) {
+	setupTestContexts(t)
+	LoadCubContext()
+
+	session, err := selectContext("prod")
+	assert.NoError(t, err)
+	assert.Equal(t, "ops@example.com", session.User.Email)
+	assert.Equal(t, "token-prod-space", session.AccessToken)
+	assert.Equal(t, "prod-space", cubContext.Space)
+	assert.Equal(t, "https://prod.example.com", cubContext.ConfigHubURL)
+
+	// The saved default context is unchanged
+	saved, err := readSavedContext("")
+	assert.NoError(t, err)
+	assert.Equal(t, "staging", saved.Name)
+
+	_, err = selectContext("missing")
+	assert.EqualError(t, err, "context missing does not exist")
+}
+
+func TestContextListAndDelete(t *testing.T) {
+	setupTestContexts(t)
+	LoadCubContext()
+
+	names, err := listSavedContextNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"prod", "staging"}, names)
+
+	out := captureStdout(t, func() {
+		assert.NoError(t, contextListCmdRun(contextListCmd, nil))
+	})
+	assert.Regexp(t, `\*\s+staging\s+https://staging.example.com`, out)
+	assert.NotRegexp(t, `\*\s+prod`, out)
+
+	captureStdout(t, func() {
+		assert.NoError(t, contextDeleteCmdRun(contextDeleteCmd, []string{"prod"}))
+	})
+	names, err = listSavedContextNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"staging"}, names)
+	_, err = os.Stat(sessionFilePath("prod"))
+	assert.True(t, os.IsNotExist(err))
+	assert.EqualError(t, contextDeleteCmdRun(contextDeleteCmd, []string{"prod"}), "context prod does not exist")
+}
+
+func TestContextNamesMustNotContainPathSeparators(t *testing.T) {
+	setupTestContexts(t)
+	LoadCubContext()
+
+	for _, name := range []string{"", ".", "..", "../prod", "a/b", `a\b`} {
+		assert.ErrorContains(t, validateContextName(name), "invalid context name", name)
+	}
+	assert.NoError(t, validateContextName("prod"))
+
+	assert.ErrorContains(t, useContext("../prod"), "invalid context name")
+	assert.ErrorContains(t, contextDeleteCmdRun(contextDeleteCmd, []string{"../staging"}), "invalid context name")
+	_, err := selectContext("../../etc/prod")
+	assert.ErrorContains(t, err, "invalid context name")
+}
@@ -0,0 +1,86 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Exit codes returned by cub so that scripts can distinguish classes of failures
+const (
+	ExitOK              = 0
+	ExitGenericError    = 1
+	ExitAuthError       = 2
+	ExitNotFound        = 3
+	ExitValidationError = 4
+	ExitTimeout         = 5
+)
+
+const exitCodesHelp = `Exit codes:
+  0  success
+  1  generic error
+  2  authentication or authorization failure
+  3  entity not found
+  4  invalid request or validation failure
+  5  timeout`
+
+// AppError is an error that determines the exit code of cub.
+type AppError struct {
+	Code int
+	Err  error
+}
+
+func (e *AppError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+func newAppError(code int, err error) *AppError {
+	return &AppError{Code: code, Err: err}
+}
+
+// exitCodeForHTTPStatus returns the exit code corresponding to an HTTP error status code.
+func exitCodeForHTTPStatus(statusCode int) int {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ExitAuthError
+	case http.StatusNotFound:
+		return ExitNotFound
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ExitValidationError
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ExitTimeout
+	default:
+		return ExitGenericError
+	}
+}
+
+// exitCode returns the exit code for err.
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	if isTimeoutError(err) {
+		return ExitTimeout
+	}
+	return ExitGenericError
+}
+
+func isTimeoutError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,58 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+// testErrorResponse mimics the structure of the generated response types
+type testErrorResponse struct {
+	HTTPResponse *http.Response
+	JSON200      *goclientnew.Space
+	JSON401      *goclientnew.StandardErrorResponse
+	JSON404      *goclientnew.StandardErrorResponse
+	JSON500      *goclientnew.StandardErrorResponse
+}
+
+func (r testErrorResponse) StatusCode() int {
+	return r.HTTPResponse.StatusCode
+}
+
+func TestInterpretErrorGenericExitCodes(t *testing.T) {
+	message := &goclientnew.StandardErrorResponse{Message: "nope"}
+	testCases := []struct {
+		name     string
+		resp     testErrorResponse
+		expected int
+	}{
+		{"unauthorized", testErrorResponse{HTTPResponse: &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}}, JSON401: message}, ExitAuthError},
+		{"not found", testErrorResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}}, JSON404: message}, ExitNotFound},
+		{"server error", testErrorResponse{HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}}, JSON500: message}, ExitGenericError},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := InterpretErrorGeneric(nil, tc.resp)
+			assert.ErrorContains(t, err, "nope")
+			assert.Equal(t, tc.expected, exitCode(err))
+			// The exit code survives wrapping
+			assert.Equal(t, tc.expected, exitCode(fmt.Errorf("listing spaces: %w", err)))
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitOK, exitCode(nil))
+	assert.Equal(t, ExitGenericError, exitCode(errors.New("failed")))
+	assert.Equal(t, ExitTimeout, exitCode(fmt.Errorf("request failed: %w", context.DeadlineExceeded)))
+	assert.Equal(t, ExitValidationError, exitCode(newAppError(ExitValidationError, errors.New("invalid"))))
+}
@@ -252,7 +252,8 @@ func buildWhereClauseFromIdentifiers(identifiers []string, uuidField, slugField
 	// Build the IN clause
 	var values []string
 	for _, identifier := range identifiers {
-		values = append(values, fmt.Sprintf("'%s'", identifier))
+		// Single quotes within string literals are escaped by doubling them
+		values = append(values, "'"+strings.ReplaceAll(identifier, "'", "''")+"'")
 	}
 
 	return fmt.Sprintf("%s IN (%s)", field, strings.Join(values, ", ")), nil
@@ -417,6 +418,14 @@ func functionDoCommandRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// validationDetailString returns the message of the detail, marked if it's only a warning.
+func validationDetailString(detail api.ValidationDetail) string {
+	if detail.Severity == api.ValidationSeverityWarn {
+		return "warning: " + detail.Message
+	}
+	return detail.Message
+}
+
 func outputFunctionInvocationResponse(respMsgs *[]goclientnew.FunctionInvocationsResponse) {
 	for _, respMsg := range *respMsgs {
 		if !quiet && !outputOnly && !dataOnly && !outputValuesOnly {
@@ -495,7 +504,7 @@ func outputFunctionInvocationResponse(respMsgs *[]goclientnew.FunctionInvocation
 							if j > 0 {
 								details += ","
 							}
-							details += " " + detail
+							details += " " + validationDetailString(detail)
 						}
 						tprint("%v%s", payload.Passed, details)
 						tprintRaw("Attributes:")
@@ -511,7 +520,7 @@ func outputFunctionInvocationResponse(respMsgs *[]goclientnew.FunctionInvocation
 							if j > 0 {
 								details += ","
 							}
-							details += " " + detail
+							details += " " + validationDetailString(detail)
 						}
 						tprint("%v %d%s", payload[i].Passed, payload[i].Index, details)
 						tprintRaw("Attributes:")
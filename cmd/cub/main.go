@@ -4,6 +4,8 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"embed"
 	"encoding/base64"
@@ -25,10 +27,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/gosimple/slug"
 	"github.com/itchyny/gojq"
+	"github.com/mattn/go-isatty"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+	"github.com/confighub/sdk/third_party/gaby"
 )
 
 const (
@@ -46,14 +50,39 @@ var cubClientNew *goclientnew.ClientWithResponses
 var authHeader goclientnew.RequestEditorFn
 var authSession AuthSession
 
+// CubTransport sets the User-Agent and X-Request-ID of requests and optionally dumps requests and
+// responses.
+// It doesn't set Accept-Encoding so that the underlying http.Transport both requests gzip
+// compression and transparently decompresses responses; setting the header explicitly would
+// disable the transparent decompression.
 type CubTransport struct {
 	RoundTripper http.RoundTripper
 	Agent        string
 	Debug        bool
+	// CompressRequests enables gzip compression of request bodies of at least
+	// compressRequestThreshold bytes.
+	CompressRequests bool
 }
 
+// compressRequestThreshold is the minimum size of request bodies compressed when
+// CompressRequests is set. Smaller bodies don't benefit from compression.
+const compressRequestThreshold = 64 * 1024
+
+// requestIDHeader identifies each request made by cub, so that users can quote it in support cases.
+const requestIDHeader = "X-Request-ID"
+
 func (ct *CubTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	r.Header.Set("User-Agent", ct.Agent)
+	if r.Header.Get(requestIDHeader) == "" {
+		r.Header.Set(requestIDHeader, uuid.NewString())
+	}
+	if ct.CompressRequests {
+		compressed, err := gzipRequestBody(r)
+		if err != nil {
+			return nil, err
+		}
+		r = compressed
+	}
 
 	if ct.Debug {
 		dump, err := httputil.DumpRequestOut(r, true)
@@ -62,11 +91,13 @@ func (ct *CubTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 		}
 		fmt.Println(string(dump))
 	}
+	start := time.Now()
 	res, err := ct.RoundTripper.RoundTrip(r)
 	if err != nil {
 		return nil, err
 	}
 	if ct.Debug {
+		fmt.Printf("Request %s: %s in %v\n", r.Header.Get(requestIDHeader), res.Status, time.Since(start).Round(time.Millisecond))
 		dump, err := httputil.DumpResponse(res, true)
 		if err != nil {
 			return res, err
@@ -76,6 +107,37 @@ func (ct *CubTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	return res, nil
 }
 
+// gzipRequestBody returns a copy of r with its body compressed with gzip if the body is at least
+// compressRequestThreshold bytes and isn't already encoded. Otherwise r is returned.
+func gzipRequestBody(r *http.Request) (*http.Request, error) {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength < compressRequestThreshold || r.Header.Get("Content-Encoding") != "" {
+		return r, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(body); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	compressed := buf.Bytes()
+
+	r = r.Clone(r.Context())
+	r.Header.Set("Content-Encoding", "gzip")
+	r.ContentLength = int64(len(compressed))
+	r.Body = io.NopCloser(bytes.NewReader(compressed))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	return r, nil
+}
+
 var IsAgent bool = os.Getenv("CONFIGHUB_AGENT") != ""
 
 // Helper functions for dynamic help text generation
@@ -107,8 +169,8 @@ To change the default confighub host, set CONFIGHUB_URL environment variable.`
 		glamour.WithAutoStyle(),
 		glamour.WithWordWrap(80),
 	)
-	if err != nil || IsAgent {
-		// Fallback to raw markdown if glamour fails
+	if err != nil || IsAgent || color.NoColor {
+		// Fallback to raw markdown if glamour fails or color is disabled
 		return string(content)
 	}
 
@@ -124,8 +186,11 @@ To change the default confighub host, set CONFIGHUB_URL environment variable.`
 func getSimpleHelp() string {
 	return `Command line tool for using ConfigHub
 To change the default confighub host, set CONFIGHUB_URL environment variable.
+To read the host and default flag values from a YAML or JSON file, set CONFIGHUB_CONFIG_FILE to its path.
 
-AI agents should set CONFIGHUB_AGENT=1 for all cub commands with --help, and execute cub --help-overview to display a detailed overview.`
+AI agents should set CONFIGHUB_AGENT=1 for all cub commands with --help, and execute cub --help-overview to display a detailed overview.
+
+` + exitCodesHelp
 }
 
 func saveAgentsFile() error {
@@ -156,6 +221,14 @@ var rootCmd = &cobra.Command{
 }
 
 func globalPreRun(cmd *cobra.Command, args []string) error {
+	profileFlags, err := applyProfileFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if err := applyConfigFileFlags(cmd, profileFlags); err != nil {
+		return err
+	}
+	configureColor()
 	if debug {
 		err := os.Setenv("CONFIGHUB_DEBUG", "1")
 		if err != nil {
@@ -168,19 +241,35 @@ func globalPreRun(cmd *cobra.Command, args []string) error {
 	}
 
 	// Add an authentication check to all commands
-	var err error
-	authSession, err = LoadSession()
+	if selectedContextName != "" {
+		authSession, err = selectContext(selectedContextName)
+		if err != nil {
+			return err
+		}
+	} else {
+		authSession, err = LoadSession()
+	}
 	if err != nil {
 		tprint("No session. Only unauthenticated commands will work")
 	} else {
+		if !slices.Contains([]*cobra.Command{authLoginCmd, authTestLoginCmd, authRefreshCmd}, cmd) && accessTokenExpiring(&authSession, time.Now()) {
+			// If the token can't be renewed, the command fails if the token has expired
+			if err := refreshSession(); err != nil && debug {
+				tprintErr("Failed to renew the access token: %v", err)
+			}
+		}
 		authHeader = setAuthHeader(&authSession)
 	}
 
 	// Require authentication except for "login"
 	if !slices.Contains([]string{"login", "test-login"}, cmd.Name()) && authSession.BasicAuthPassword == "" && authSession.AccessToken == "" {
-		return errors.New("you must be authenticated to execute this command. Log in with the command: cub auth login")
+		return newAppError(ExitAuthError, errors.New("you must be authenticated to execute this command. Log in with the command: cub auth login"))
 	}
 
+	requestTimeout, err = resolveRequestTimeout(cmd)
+	if err != nil {
+		return err
+	}
 	cubClientNew, err = initializeClient()
 	if err != nil {
 		return err
@@ -195,9 +284,9 @@ func getEnvURL() *url.URL {
 		Host:   "hub.confighub.com",
 		Path:   "/api",
 	}
-	if os.Getenv("CONFIGHUB_URL") != "" {
-		cubContext.ConfigHubURL = os.Getenv("CONFIGHUB_URL")
-		splitHost := strings.Split(os.Getenv("CONFIGHUB_URL"), "://")
+	if configHubURL := configValue("url"); configHubURL != "" {
+		cubContext.ConfigHubURL = configHubURL
+		splitHost := strings.Split(configHubURL, "://")
 		baseURL = &url.URL{
 			Scheme: splitHost[0],
 			Host:   splitHost[1],
@@ -231,6 +320,12 @@ func main() {
 	LoadCubContext()
 	_ = getEnvURL()
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Debug output")
+	rootCmd.PersistentFlags().BoolVar(&compressRequests, "compress-requests", false, "Compress large request bodies, such as configuration data read with --from-stdin, with gzip")
+	rootCmd.PersistentFlags().StringVar(&selectedContextName, "context", "", "Name of a saved context (see cub context list) to use instead of the current context")
+	rootCmd.PersistentFlags().String("timeout", "", "Timeout of each API request as a duration with units, such as 10s or 2m; defaults to "+TimeoutEnvVar+" or 30s. Commands with --wait use it as the completion timeout, which defaults to 2m")
+	rootCmd.PersistentFlags().StringVar(&selectedProfileName, "profile", "", "Name of a profile (see cub config profile set) whose flag values to use as defaults")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output; also disabled by setting NO_COLOR or when output is not a terminal")
+	cobra.OnInitialize(configureColor)
 
 	// Add --help-overview flag
 	var helpOverview bool
@@ -241,6 +336,8 @@ func main() {
 
 	// Override the help function to handle --help-overview
 	rootCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		// Initializers aren't run for help
+		configureColor()
 		if helpOverview {
 			fmt.Print(getFormattedOverview())
 			return
@@ -283,9 +380,10 @@ func setAuthHeaderToken(authSession *AuthSession) string {
 
 func initializeClient() (*goclientnew.ClientWithResponses, error) {
 	ct := &CubTransport{
-		RoundTripper: http.DefaultTransport,
-		Agent:        "cub",
-		Debug:        debug,
+		RoundTripper:     http.DefaultTransport,
+		Agent:            "cub",
+		Debug:            debug,
+		CompressRequests: compressRequests,
 	}
 	baseURL := getEnvURL()
 
@@ -295,7 +393,7 @@ func initializeClient() (*goclientnew.ClientWithResponses, error) {
 			c.RequestEditors = append(c.RequestEditors, authHeader)
 		}
 		return nil
-	})
+	}, goclientnew.WithTimeout(requestTimeout))
 }
 
 // Do not call this directly from a command for error responses from API requests.
@@ -303,7 +401,7 @@ func initializeClient() (*goclientnew.ClientWithResponses, error) {
 func failOnError(err error) {
 	if err != nil {
 		tprintErr("Failed: %s", err.Error())
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -369,8 +467,34 @@ func tprint(format string, args ...interface{}) {
 	fmt.Printf(format, args...)
 }
 
+// configureColor disables colored output if requested with --no-color or NO_COLOR. Otherwise,
+// colored output is disabled for stdout if stdout is not a terminal, and for the errors printed
+// to stderr if stderr is not a terminal.
+func configureColor() {
+	disabled := noColor || os.Getenv("NO_COLOR") != ""
+	if disabled || !isTerminal(os.Stdout) {
+		color.NoColor = true
+	}
+	stderrNoColor = disabled || !isTerminal(os.Stderr)
+}
+
+// stderrNoColor disables colored output to stderr. It's set by configureColor.
+var stderrNoColor = true
+
+// isTerminal reports whether the file is a terminal. It's a variable so that tests can simulate
+// terminals.
+var isTerminal = func(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
 func tprintErr(format string, args ...interface{}) {
 	red := color.New(color.FgRed).Add(color.Bold)
+	// color.NoColor only applies to stdout
+	if stderrNoColor {
+		red.DisableColor()
+	} else {
+		red.EnableColor()
+	}
 	redf := red.SprintFunc()
 	// Ensure there are no leading newlines and exactly one trailing newline.
 	format = strings.Trim(format, "\n") + "\n"
@@ -473,10 +597,13 @@ var contains = ""
 var verbose = false
 var quiet = false
 var jsonOutput = false
+var ndjsonOutput = false
 var jq = ""
 var names = false
 var selectFields = ""
 var debug = false
+var compressRequests = false
+var noColor = false
 var noheader = false
 var wait = true
 var timeout = "2m"
@@ -511,7 +638,7 @@ func setLabels(labelMap *map[string]string) error {
 }
 
 func enableFromStdinFlag(cmd *cobra.Command) {
-	cmd.Flags().BoolVar(&flagPopulateModelFromStdin, "from-stdin", false, "Read the ConfigHub entity JSON (e.g., retrieved with cub <entity> get --quiet --json) from stdin; merged with command arguments on create, and merged with command arguments and existing entity on update")
+	cmd.Flags().BoolVar(&flagPopulateModelFromStdin, "from-stdin", false, "Read the ConfigHub entity JSON (e.g., retrieved with cub <entity> get --quiet --json) from stdin; merged with command arguments on create, and merged with command arguments and existing entity on update. A YAML stream beginning with --- is merged document by document")
 }
 
 func enableReplaceFlag(cmd *cobra.Command) {
@@ -519,7 +646,7 @@ func enableReplaceFlag(cmd *cobra.Command) {
 }
 
 func enableFilenameFlag(cmd *cobra.Command) {
-	cmd.Flags().StringVar(&flagFilename, "filename", "", "Read the ConfigHub entity JSON from file, URL (https://), or stdin (-); mutually exclusive with --from-stdin")
+	cmd.Flags().StringVar(&flagFilename, "filename", "", "Read the ConfigHub entity JSON, or a YAML stream beginning with ---, from file, URL (https://), or stdin (-); mutually exclusive with --from-stdin")
 }
 
 func enableVerboseFlag(cmd *cobra.Command) {
@@ -542,6 +669,10 @@ func enableJsonFlag(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "JSON output, suppressing default output")
 }
 
+func enableNdjsonFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&ndjsonOutput, "ndjson", false, "Newline-delimited JSON output with one compact JSON object per line, suppressing default output; takes precedence over --json. With --jq, each result of the expression is output as a line of compact JSON, and cub exits with code 1 if there are no results")
+}
+
 func enableNamesFlag(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&names, "names", false, "Only output names, suppressing default output")
 }
@@ -555,7 +686,7 @@ func enableSelectFlag(cmd *cobra.Command) {
 }
 
 func enableWhereFlag(cmd *cobra.Command) {
-	cmd.Flags().StringVar(&where, "where", "", "Filter expression using SQL-inspired syntax. Supports conjunctions with AND. String operators: =, !=, <, >, <=, >=, LIKE, ILIKE, ~~, !~~, ~, ~*, !~, !~*. Pattern matching with LIKE/ILIKE uses % and _ wildcards. Regex operators (~, ~*, !~, !~*) support POSIX regular expressions. Examples: \"Slug LIKE 'app-%'\", \"DisplayName ILIKE '%backend%'\", \"Slug ~ '^[a-z]+-[0-9]+$'\"")
+	cmd.Flags().StringVar(&where, "where", "", "Filter expression using SQL-inspired syntax. Supports conjunctions with AND. String operators: =, !=, <, >, <=, >=, LIKE, ILIKE, ~~, !~~, ~, ~*, !~, !~*. Pattern matching with LIKE/ILIKE uses % and _ wildcards. Regex operators (~, ~*, !~, !~*) support POSIX regular expressions. Nested paths use dotted segments, with dots within keys escaped as ~1, and * matches any key; they can drill into JSON stored in map fields like Annotations, and are filtered client-side if the server doesn't support them. Examples: \"Slug LIKE 'app-%'\", \"DisplayName ILIKE '%backend%'\", \"Slug ~ '^[a-z]+-[0-9]+$'\", \"Annotations.config.owner = 'alice'\", \"Labels.* = 'backend'\"")
 }
 
 func enableContainsFlag(cmd *cobra.Command) {
@@ -578,6 +709,7 @@ func addStandardListFlags(cmd *cobra.Command) {
 	enableNamesFlag(cmd)
 	enableQuietFlag(cmd)
 	enableJsonFlag(cmd)
+	enableNdjsonFlag(cmd)
 	enableJqFlag(cmd)
 	enableNoheaderFlag(cmd)
 }
@@ -628,8 +760,64 @@ func populateNewModelFromStdin(v interface{}) error {
 	return mergeEntityWithData(v, jsonBytes)
 }
 
+// mergeEntityWithData unmarshals the entity JSON in data into v. If data is a YAML stream beginning
+// with "---", each document is an entity and the documents are merged in order: the last value
+// wins for scalar fields, maps are merged, and arrays are appended.
 func mergeEntityWithData(v any, data []byte) error {
-	return json.Unmarshal(data, v)
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("---")) {
+		return json.Unmarshal(data, v)
+	}
+	docs, err := gaby.ParseAll(data)
+	if err != nil {
+		return err
+	}
+	var merged any
+	for i, doc := range docs {
+		if doc.IsEmptyDoc() {
+			continue
+		}
+		docBytes, err := doc.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		var docValue any
+		if err := json.Unmarshal(docBytes, &docValue); err != nil {
+			return err
+		}
+		if _, ok := docValue.(map[string]any); !ok {
+			return fmt.Errorf("document %d is not an object", i+1)
+		}
+		merged = mergeEntityValues(merged, docValue)
+	}
+	if merged == nil {
+		return nil
+	}
+	mergedBytes, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(mergedBytes, v)
+}
+
+func mergeEntityValues(existing, incoming any) any {
+	switch incomingValue := incoming.(type) {
+	case map[string]any:
+		existingMap, ok := existing.(map[string]any)
+		if !ok {
+			return incomingValue
+		}
+		for key, value := range incomingValue {
+			existingMap[key] = mergeEntityValues(existingMap[key], value)
+		}
+		return existingMap
+	case []any:
+		if existingArray, ok := existing.([]any); ok {
+			return append(existingArray, incomingValue...)
+		}
+		return incomingValue
+	default:
+		return incoming
+	}
 }
 
 func populateModelFromFile(v any, filename string) error {
@@ -677,11 +865,29 @@ func populateModelFromFlags(v any) error {
 }
 
 func displayJSON(entity any) {
+	if docs, ok := entity.(gaby.Container); ok {
+		jsonArray, err := docs.ToJSONArray()
+		failOnError(err)
+		var outBuffer bytes.Buffer
+		err = json.Indent(&outBuffer, jsonArray, "", "  ")
+		failOnError(err)
+		tprintRaw(outBuffer.String())
+		return
+	}
 	outBytes, err := json.MarshalIndent(entity, "", "  ")
 	failOnError(err)
 	tprintRaw(string(outBytes))
 }
 
+// displayNDJSON prints each element on its own line as compact JSON.
+func displayNDJSON[Entity any](entities []*Entity) {
+	for _, entity := range entities {
+		outBytes, err := json.Marshal(entity)
+		failOnError(err)
+		fmt.Println(string(outBytes))
+	}
+}
+
 func displayJQForBytes(outBytes []byte, jqExpr string) {
 	var tree any
 	err := json.Unmarshal(outBytes, &tree)
@@ -689,6 +895,7 @@ func displayJQForBytes(outBytes []byte, jqExpr string) {
 	jqQuery, err := gojq.Parse(jqExpr)
 	failOnError(err)
 	iter := jqQuery.Run(tree)
+	numResults := 0
 	for {
 		value, ok := iter.Next()
 		if !ok {
@@ -700,6 +907,14 @@ func displayJQForBytes(outBytes []byte, jqExpr string) {
 			}
 			failOnError(err)
 		}
+		numResults++
+		if ndjsonOutput {
+			// Stream each result as a line of compact JSON
+			resultBytes, err := json.Marshal(value)
+			failOnError(err)
+			fmt.Println(string(resultBytes))
+			continue
+		}
 		switch v := value.(type) {
 		case string, int, bool:
 			tprint("%v", v)
@@ -707,6 +922,9 @@ func displayJQForBytes(outBytes []byte, jqExpr string) {
 			displayJSON(value)
 		}
 	}
+	if ndjsonOutput && numResults == 0 {
+		os.Exit(ExitGenericError)
+	}
 }
 
 func displayJQ(entity any) {
@@ -748,7 +966,9 @@ type ModelConstraint interface {
 		goclientnew.ExtendedChangeSet |
 		goclientnew.Unit |
 		goclientnew.UnitEvent |
-		goclientnew.ExtendedUnit
+		goclientnew.ExtendedUnit |
+		goclientnew.FunctionInvocationsResponse |
+		goclientnew.ResourceMutation
 }
 
 func displayCreateResults[Entity ModelConstraint](entity *Entity, entityName, slug, id string, display func(entity *Entity)) {
@@ -789,7 +1009,7 @@ func displayUpdateResults[Entity ModelConstraint](entity *Entity, entityName, sl
 
 func displayListResults[Entity ModelConstraint](entities []*Entity, getSlug func(entity *Entity) string, display func(entities []*Entity)) {
 	// Check if any alternative output format is specified
-	hasAlternativeOutput := names || jsonOutput || jq != ""
+	hasAlternativeOutput := names || jsonOutput || ndjsonOutput || jq != ""
 
 	if (!quiet || verbose) && !hasAlternativeOutput {
 		display(entities)
@@ -803,7 +1023,12 @@ func displayListResults[Entity ModelConstraint](entities []*Entity, getSlug func
 		}
 		table.Render()
 	}
-	if jsonOutput {
+	// --jq takes precedence over --ndjson, which takes precedence over --json. --ndjson
+	// changes how the results of --jq are output.
+	if ndjsonOutput && jq == "" {
+		displayNDJSON(entities)
+	}
+	if jsonOutput && !ndjsonOutput {
 		displayJSON(entities)
 	}
 	if jq != "" {
@@ -0,0 +1,203 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+func captureStderr(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	savedStderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = savedStderr }()
+	f()
+	w.Close()
+	out, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	return string(out)
+}
+
+func TestNoColorDisablesErrorColor(t *testing.T) {
+	savedNoColor, savedStderrNoColor, savedNoColorFlag, savedIsTerminal := color.NoColor, stderrNoColor, noColor, isTerminal
+	t.Cleanup(func() {
+		color.NoColor, stderrNoColor, noColor, isTerminal = savedNoColor, savedStderrNoColor, savedNoColorFlag, savedIsTerminal
+	})
+	// Simulate a color-capable terminal so that only NO_COLOR and --no-color disable color.
+	isTerminal = func(*os.File) bool { return true }
+
+	printErr := func() string {
+		color.NoColor = false
+		configureColor()
+		return captureStderr(t, func() {
+			tprintErr("Failed: %s", "something went wrong")
+		})
+	}
+	assert.Contains(t, printErr(), "\x1b[")
+
+	t.Setenv("NO_COLOR", "1")
+	out := printErr()
+	assert.True(t, color.NoColor)
+	assert.False(t, strings.Contains(out, "\x1b["), "expected no ANSI escape codes in %q", out)
+	assert.Equal(t, "Failed: something went wrong\n", out)
+
+	t.Setenv("NO_COLOR", "")
+	noColor = true
+	assert.Equal(t, "Failed: something went wrong\n", printErr())
+	assert.True(t, color.NoColor)
+}
+
+func TestColorDependsOnEachStream(t *testing.T) {
+	savedNoColor, savedStderrNoColor, savedIsTerminal := color.NoColor, stderrNoColor, isTerminal
+	t.Cleanup(func() {
+		color.NoColor, stderrNoColor, isTerminal = savedNoColor, savedStderrNoColor, savedIsTerminal
+	})
+	t.Setenv("NO_COLOR", "")
+
+	// Errors are colored when only stdout is redirected
+	isTerminal = func(f *os.File) bool { return f == os.Stderr }
+	color.NoColor = false
+	configureColor()
+	assert.True(t, color.NoColor)
+	assert.Contains(t, captureStderr(t, func() { tprintErr("Failed") }), "\x1b[")
+
+	// Stdout is colored when only stderr is redirected
+	isTerminal = func(f *os.File) bool { return f == os.Stdout }
+	color.NoColor = false
+	configureColor()
+	assert.False(t, color.NoColor)
+	assert.Equal(t, "Failed\n", captureStderr(t, func() { tprintErr("Failed") }))
+}
+
+func TestMergeEntityWithMultiDocumentYAML(t *testing.T) {
+	unit := goclientnew.Unit{Slug: "existing", Labels: map[string]string{"keep": "yes"}}
+	err := mergeEntityWithData(&unit, []byte(`---
+Slug: first
+DisplayName: First
+Labels:
+  tier: backend
+ApprovedBy:
+- a1b2c3d4-0000-0000-0000-000000000001
+---
+---
+Slug: second
+Labels:
+  env: prod
+ApprovedBy:
+- a1b2c3d4-0000-0000-0000-000000000002
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "second", unit.Slug)
+	assert.Equal(t, "First", unit.DisplayName)
+	assert.Equal(t, map[string]string{"keep": "yes", "tier": "backend", "env": "prod"}, unit.Labels)
+	assert.Len(t, unit.ApprovedBy, 2)
+
+	// JSON is unmarshaled as before
+	err = mergeEntityWithData(&unit, []byte(`{"Slug": "third"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "third", unit.Slug)
+
+	assert.EqualError(t, mergeEntityWithData(&unit, []byte("---\n- a\n")), "document 1 is not an object")
+}
+
+func TestCubTransportDecompressesGzipResponses(t *testing.T) {
+	const body = `{"Slug":"my-space"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Accept-Encoding"), "gzip")
+		assert.Equal(t, "cub", r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Type", "application/json")
+		gz := gzip.NewWriter(w)
+		_, err := gz.Write([]byte(body))
+		assert.NoError(t, err)
+		assert.NoError(t, gz.Close())
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &CubTransport{RoundTripper: http.DefaultTransport, Agent: "cub"}}
+	res, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(data))
+	assert.True(t, res.Uncompressed)
+}
+
+func TestCubTransportCompressesLargeRequests(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			reader = gz
+		}
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		received = append(received, r.Header.Get("Content-Encoding")+":"+string(data))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &CubTransport{RoundTripper: http.DefaultTransport, Agent: "cub", CompressRequests: true}}
+	large := strings.Repeat("a", compressRequestThreshold)
+	for _, body := range []string{"small", large} {
+		res, err := client.Post(server.URL, "application/json", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+	assert.Equal(t, []string{":small", "gzip:" + large}, received)
+}
+
+func TestCubTransportSetsRequestID(t *testing.T) {
+	var requestIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, r.Header.Get("X-Request-ID"))
+		if len(requestIDs) > 1 {
+			w.Header().Set("X-Request-ID", "server-trace-123")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"Message":"space not found"}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client, err := goclientnew.NewClientWithResponses(server.URL, goclientnew.WithHTTPClient(&http.Client{
+		Transport: &CubTransport{RoundTripper: http.DefaultTransport, Agent: "cub"},
+	}))
+	require.NoError(t, err)
+
+	// Without an ID in the response, the error includes the ID that was sent
+	res, err := client.GetSpaceWithResponse(context.Background(), uuid.New(), &goclientnew.GetSpaceParams{})
+	require.NoError(t, err)
+	require.Len(t, requestIDs, 1)
+	_, err = uuid.Parse(requestIDs[0])
+	assert.NoError(t, err)
+	assert.EqualError(t, InterpretErrorGeneric(nil, res), "HTTP 404 for req "+requestIDs[0]+": space not found")
+
+	// The ID echoed by the server takes precedence
+	res, err = client.GetSpaceWithResponse(context.Background(), uuid.New(), &goclientnew.GetSpaceParams{})
+	require.NoError(t, err)
+	require.Len(t, requestIDs, 2)
+	assert.NotEqual(t, requestIDs[0], requestIDs[1])
+	assert.EqualError(t, InterpretErrorGeneric(nil, res), "HTTP 404 for req server-trace-123: space not found")
+}
@@ -6,6 +6,7 @@ package main
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
@@ -39,6 +40,12 @@ Examples:
   # List mutations with specific criteria
   cub mutation list --space my-space --where 'MutationNum > 1' my-ns
 
+  # List mutations created in the last 24 hours
+  cub mutation list --space my-space --since 24h my-ns
+
+  # List mutations created during January 2025
+  cub mutation list --space my-space --since 2025-01-01T00:00:00Z --until 2025-02-01T00:00:00Z my-ns
+
 `,
 	Args: cobra.ExactArgs(1),
 	RunE: mutationListCmdRun,
@@ -58,13 +65,17 @@ var mutationCustomColumnDependencies = map[string][]string{}
 
 func init() {
 	addStandardListFlags(mutationListCmd)
+	enableTimeWindowFlags(mutationListCmd)
 	mutationListCmd.Flags().BoolVar(&byUnitID, "by-unit-id", false, "use unit id instead of slug")
 	mutationCmd.AddCommand(mutationListCmd)
 }
 
 func mutationListCmdRun(cmd *cobra.Command, args []string) error {
+	window, err := parseTimeWindow(since, until, time.Now())
+	if err != nil {
+		return err
+	}
 	var unit *goclientnew.Unit
-	var err error
 	if byUnitID {
 		unit, err = apiGetUnit(args[0], "*")
 	} else {
@@ -77,6 +88,9 @@ func mutationListCmdRun(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	mutations = filterByTimeWindow(mutations, window, func(extendedMutation *goclientnew.ExtendedMutation) time.Time {
+		return extendedMutation.Mutation.CreatedAt
+	})
 	displayListResults(mutations, getMutationSlugFromExtended, displayMutationList)
 	return nil
 }
@@ -0,0 +1,210 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+var mutationSourcesArgs struct {
+	revision int64
+	pending  bool
+}
+
+var mutationSourcesCmd = &cobra.Command{
+	Use:   "sources <unit>",
+	Short: "List the mutations of each resource of a unit",
+	Long: `List the mutation sources of the resources of a unit: for each resource, the type of the last mutation, the number of paths changed, and the index of the function or mutation that changed it. By default the mutation sources of the head revision are listed.
+
+The --where filter is evaluated client-side on the ResourceType, ResourceName, MutationType, PathCount, and Index fields. With --json, the PathMutationMap of each resource is included.
+
+Examples:
+  # List the mutation sources of the head revision of a unit
+  cub mutation sources --space my-space my-deployment
+
+  # List the mutation sources of revision 3
+  cub mutation sources --space my-space --revision 3 my-deployment
+
+  # List the changes that haven't been applied yet
+  cub mutation sources --space my-space --pending my-deployment
+
+  # List the updated resources
+  cub mutation sources --space my-space --where "MutationType = 'Update'" my-deployment
+
+  # Show the changed paths of each resource
+  cub mutation sources --space my-space --json my-deployment
+
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: mutationSourcesCmdRun,
+}
+
+func init() {
+	enableWhereFlag(mutationSourcesCmd)
+	enableNamesFlag(mutationSourcesCmd)
+	enableQuietFlag(mutationSourcesCmd)
+	enableJsonFlag(mutationSourcesCmd)
+	enableNdjsonFlag(mutationSourcesCmd)
+	enableJqFlag(mutationSourcesCmd)
+	enableNoheaderFlag(mutationSourcesCmd)
+	mutationSourcesCmd.Flags().Int64Var(&mutationSourcesArgs.revision, "revision", 0, "revision number whose mutation sources to list; the head revision by default")
+	mutationSourcesCmd.Flags().BoolVar(&mutationSourcesArgs.pending, "pending", false, "only list the changes made since the last applied revision")
+	mutationCmd.AddCommand(mutationSourcesCmd)
+}
+
+func mutationSourcesCmdRun(cmd *cobra.Command, args []string) error {
+	conditions, err := parseWhereConditions(where)
+	if err != nil {
+		return err
+	}
+	unit, err := apiGetUnitFromSlug(args[0], "*")
+	if err != nil {
+		return err
+	}
+	sources := unit.MutationSources
+	if mutationSourcesArgs.revision != 0 {
+		revision, err := apiGetRevisionFromNumber(mutationSourcesArgs.revision, unit.UnitID.String(), "*")
+		if err != nil {
+			return err
+		}
+		sources = revision.MutationSources
+	}
+	var resourceMutations []goclientnew.ResourceMutation
+	if sources != nil {
+		resourceMutations = *sources
+	}
+	if mutationSourcesArgs.pending {
+		var applied []goclientnew.ResourceMutation
+		if unit.LastAppliedRevisionNum != 0 {
+			appliedRevision, err := apiGetRevisionFromNumber(unit.LastAppliedRevisionNum, unit.UnitID.String(), "*")
+			if err != nil {
+				return err
+			}
+			if appliedRevision.MutationSources != nil {
+				applied = *appliedRevision.MutationSources
+			}
+		}
+		resourceMutations = pendingResourceMutations(resourceMutations, applied)
+	}
+
+	entities := make([]*goclientnew.ResourceMutation, len(resourceMutations))
+	for i := range resourceMutations {
+		entities[i] = &resourceMutations[i]
+	}
+	entities, err = filterByNestedWhere(entities, conditions, resourceMutationFields)
+	if err != nil {
+		return err
+	}
+	displayListResults(entities, resourceMutationName, displayResourceMutationList)
+	return nil
+}
+
+func resourceMutationName(resourceMutation *goclientnew.ResourceMutation) string {
+	if resourceMutation.Resource == nil {
+		return ""
+	}
+	return resourceMutation.Resource.ResourceName
+}
+
+// resourceMutationFields returns the fields of the resource mutation that --where conditions can
+// refer to.
+func resourceMutationFields(resourceMutation *goclientnew.ResourceMutation) any {
+	fields := map[string]any{
+		"ResourceName": resourceMutationName(resourceMutation),
+		"PathCount":    0,
+		"Index":        0,
+	}
+	if resourceMutation.Resource != nil {
+		fields["ResourceType"] = resourceMutation.Resource.ResourceType
+	}
+	if resourceMutation.ResourceMutationInfo != nil {
+		fields["Index"] = resourceMutation.ResourceMutationInfo.Index
+		if resourceMutation.ResourceMutationInfo.MutationType != nil {
+			fields["MutationType"] = string(*resourceMutation.ResourceMutationInfo.MutationType)
+		}
+	}
+	if resourceMutation.PathMutationMap != nil {
+		fields["PathCount"] = len(*resourceMutation.PathMutationMap)
+	}
+	return fields
+}
+
+func displayResourceMutationList(resourceMutations []*goclientnew.ResourceMutation) {
+	table := tableView()
+	if !noheader {
+		table.SetHeader([]string{"ResourceType", "ResourceName", "MutationType", "PathCount", "Index"})
+	}
+	for _, resourceMutation := range resourceMutations {
+		fields := resourceMutationFields(resourceMutation).(map[string]any)
+		table.Append([]string{
+			fmt.Sprint(fields["ResourceType"]),
+			fmt.Sprint(fields["ResourceName"]),
+			fmt.Sprint(fields["MutationType"]),
+			fmt.Sprint(fields["PathCount"]),
+			fmt.Sprint(fields["Index"]),
+		})
+	}
+	table.Render()
+}
+
+func mutationInfoEqual(a, b goclientnew.MutationInfo) bool {
+	var aType, bType goclientnew.MutationType
+	if a.MutationType != nil {
+		aType = *a.MutationType
+	}
+	if b.MutationType != nil {
+		bType = *b.MutationType
+	}
+	return aType == bType && a.Index == b.Index && a.Predicate == b.Predicate && a.Value == b.Value
+}
+
+// pendingResourceMutations returns the resource mutations with only the path mutations that
+// differ from those of the applied revision. Resources without such paths are omitted.
+func pendingResourceMutations(resourceMutations, applied []goclientnew.ResourceMutation) []goclientnew.ResourceMutation {
+	type resourceKey struct{ resourceType, resourceName string }
+	key := func(resourceMutation *goclientnew.ResourceMutation) resourceKey {
+		if resourceMutation.Resource == nil {
+			return resourceKey{}
+		}
+		return resourceKey{resourceMutation.Resource.ResourceType, resourceMutation.Resource.ResourceName}
+	}
+	appliedPaths := map[resourceKey]goclientnew.MutationMap{}
+	for i := range applied {
+		if applied[i].PathMutationMap != nil {
+			appliedPaths[key(&applied[i])] = *applied[i].PathMutationMap
+		}
+	}
+
+	pending := []goclientnew.ResourceMutation{}
+	for i := range resourceMutations {
+		resourceMutation := resourceMutations[i]
+		if resourceMutation.PathMutationMap == nil {
+			continue
+		}
+		appliedMap := appliedPaths[key(&resourceMutation)]
+		pendingMap := goclientnew.MutationMap{}
+		paths := make([]string, 0, len(*resourceMutation.PathMutationMap))
+		for path := range *resourceMutation.PathMutationMap {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			info := (*resourceMutation.PathMutationMap)[path]
+			if appliedInfo, found := appliedMap[path]; !found || !mutationInfoEqual(info, appliedInfo) {
+				pendingMap[path] = info
+			}
+		}
+		if len(pendingMap) == 0 {
+			continue
+		}
+		resourceMutation.PathMutationMap = &pendingMap
+		pending = append(pending, resourceMutation)
+	}
+	return pending
+}
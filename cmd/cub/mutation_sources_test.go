@@ -0,0 +1,75 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+func testResourceMutation(resourceName string, mutationType goclientnew.MutationType, paths goclientnew.MutationMap) goclientnew.ResourceMutation {
+	return goclientnew.ResourceMutation{
+		Resource:             &goclientnew.ResourceInfo{ResourceType: "apps/v1/Deployment", ResourceName: resourceName},
+		ResourceMutationInfo: &goclientnew.MutationInfo{Index: 1, MutationType: &mutationType},
+		PathMutationMap:      &paths,
+	}
+}
+
+func TestPendingResourceMutations(t *testing.T) {
+	update := goclientnew.Update
+	applied := []goclientnew.ResourceMutation{
+		testResourceMutation("web", goclientnew.Update, goclientnew.MutationMap{
+			"spec.replicas": {Index: 1, MutationType: &update, Value: "2"},
+		}),
+		testResourceMutation("db", goclientnew.Update, goclientnew.MutationMap{
+			"spec.replicas": {Index: 1, MutationType: &update, Value: "1"},
+		}),
+	}
+	head := []goclientnew.ResourceMutation{
+		testResourceMutation("web", goclientnew.Update, goclientnew.MutationMap{
+			"spec.replicas":                         {Index: 2, MutationType: &update, Value: "3"},
+			"spec.template.spec.containers.0.image": {Index: 1, MutationType: &update, Value: "web:1"},
+		}),
+		testResourceMutation("db", goclientnew.Update, goclientnew.MutationMap{
+			"spec.replicas": {Index: 1, MutationType: &update, Value: "1"},
+		}),
+		testResourceMutation("cache", goclientnew.Add, goclientnew.MutationMap{}),
+	}
+
+	pending := pendingResourceMutations(head, applied)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "web", pending[0].Resource.ResourceName)
+	assert.Len(t, *pending[0].PathMutationMap, 2)
+	// The input isn't modified
+	assert.Len(t, *head[1].PathMutationMap, 1)
+
+	pending = pendingResourceMutations(head[1:], nil)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "db", pending[0].Resource.ResourceName)
+}
+
+func TestResourceMutationWhere(t *testing.T) {
+	update := goclientnew.Update
+	resourceMutations := []*goclientnew.ResourceMutation{}
+	for _, resourceMutation := range []goclientnew.ResourceMutation{
+		testResourceMutation("web", goclientnew.Update, goclientnew.MutationMap{"spec.replicas": {MutationType: &update}}),
+		testResourceMutation("db", goclientnew.Add, goclientnew.MutationMap{}),
+	} {
+		resourceMutations = append(resourceMutations, &resourceMutation)
+	}
+
+	conditions, err := parseWhereConditions("MutationType = 'Update'")
+	require.NoError(t, err)
+	filtered, err := filterByNestedWhere(resourceMutations, conditions, resourceMutationFields)
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "web", resourceMutationName(filtered[0]))
+
+	_, err = parseWhereConditions("PathCount > 0")
+	assert.Error(t, err)
+}
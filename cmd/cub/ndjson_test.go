@@ -0,0 +1,120 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	savedStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = savedStdout }()
+	f()
+	w.Close()
+	out, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	return string(out)
+}
+
+func setOutputFlags(t *testing.T, ndjson, json bool, jqExpr string) {
+	t.Helper()
+	savedNdjson, savedJson, savedJq := ndjsonOutput, jsonOutput, jq
+	t.Cleanup(func() { ndjsonOutput, jsonOutput, jq = savedNdjson, savedJson, savedJq })
+	ndjsonOutput, jsonOutput, jq = ndjson, json, jqExpr
+}
+
+func testSpaces() []*goclientnew.Space {
+	return []*goclientnew.Space{
+		{Slug: "space-1", DisplayName: "Space 1"},
+		{Slug: "space-2", DisplayName: "Space 2"},
+		{Slug: "space-3", DisplayName: "Space 3"},
+	}
+}
+
+func failDisplay(t *testing.T) func(entities []*goclientnew.Space) {
+	return func(entities []*goclientnew.Space) {
+		t.Error("default output should be suppressed")
+	}
+}
+
+func TestDisplayListResultsNDJSON(t *testing.T) {
+	setOutputFlags(t, true, false, "")
+	spaces := testSpaces()
+
+	out := captureStdout(t, func() {
+		displayListResults(spaces, func(s *goclientnew.Space) string { return s.Slug }, failDisplay(t))
+	})
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	assert.Len(t, lines, len(spaces))
+	for i, line := range lines {
+		expected, err := json.Marshal(spaces[i])
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), line)
+	}
+}
+
+func TestDisplayListResultsNDJSONEmpty(t *testing.T) {
+	setOutputFlags(t, true, false, "")
+	out := captureStdout(t, func() {
+		displayListResults([]*goclientnew.Space{}, nil, failDisplay(t))
+	})
+	assert.Empty(t, out)
+}
+
+func TestDisplayListResultsNDJSONPrecedence(t *testing.T) {
+	// --ndjson takes precedence over --json
+	setOutputFlags(t, true, true, "")
+	out := captureStdout(t, func() {
+		displayListResults(testSpaces(), nil, failDisplay(t))
+	})
+	assert.Len(t, strings.Split(strings.TrimSuffix(out, "\n"), "\n"), 3)
+
+	// --jq takes precedence over --ndjson, which outputs the jq results as JSON
+	setOutputFlags(t, true, false, ".[].Slug")
+	out = captureStdout(t, func() {
+		displayListResults(testSpaces(), nil, failDisplay(t))
+	})
+	assert.Equal(t, "\"space-1\"\n\"space-2\"\n\"space-3\"\n", out)
+
+	setOutputFlags(t, false, false, ".[].Slug")
+	out = captureStdout(t, func() {
+		displayListResults(testSpaces(), nil, failDisplay(t))
+	})
+	assert.Equal(t, "space-1\nspace-2\nspace-3\n", out)
+}
+
+func TestDisplayJQNDJSON(t *testing.T) {
+	setOutputFlags(t, true, false, ".items[]")
+	items := map[string]any{
+		"items": []map[string]any{
+			{"metadata": map[string]any{"name": "web", "labels": map[string]string{"app": "web"}}},
+			{"metadata": map[string]any{"name": "db"}},
+			{"ports": []int{80, 443}},
+		},
+	}
+	out := captureStdout(t, func() {
+		displayJQ(items)
+	})
+	assert.Equal(t, `{"metadata":{"labels":{"app":"web"},"name":"web"}}
+{"metadata":{"name":"db"}}
+{"ports":[80,443]}
+`, out)
+
+	// Each line is a complete JSON value
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		assert.True(t, json.Valid([]byte(line)), line)
+	}
+}
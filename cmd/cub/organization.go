@@ -26,7 +26,9 @@ var selectedOrganizationSlug string
 
 // to be used by sub-commands that requires organization ID
 func organizationPreRunE(cmd *cobra.Command, args []string) error {
-	globalPreRun(cmd, args)
+	if err := globalPreRun(cmd, args); err != nil {
+		return err
+	}
 
 	selectedOrg := &goclientnew.Organization{}
 	if cubContext.OrganizationID == "" {
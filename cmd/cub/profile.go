@@ -0,0 +1,189 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// selectedProfileName is the profile selected with --profile, if any
+var selectedProfileName string
+
+var configCmd = &cobra.Command{
+	Use:               "config",
+	Short:             "Configuration commands",
+	Long:              `Configuration commands`,
+	PersistentPreRunE: configPreRunE,
+}
+
+var configProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Profile commands",
+	Long: `Profile commands. A profile is a named set of default flag values, saved in ~/.confighub/profiles.json
+and shared by all contexts. The values of the profile selected with --profile are used for flags
+not specified on the command line, and override the values in CONFIGHUB_CONFIG_FILE. Like the
+values in CONFIGHUB_CONFIG_FILE, they behave like flag defaults, so required flags must still be
+specified on the command line.`,
+}
+
+var configProfileSetCmd = &cobra.Command{
+	Use:   "set <name> <flag>=<value> ...",
+	Short: "Set default flag values of a profile",
+	Long: `Set default flag values of a profile, creating the profile if it doesn't exist. Flag names are
+specified without the leading dashes. An empty value removes the flag from the profile. Values of
+flags that accept lists are separated by commas.
+
+# Always produce quiet JSON output in scripts
+cub config profile set scripting quiet=true json=true
+cub unit list --space my-space --profile scripting
+
+# Remove a flag from a profile
+cub config profile set scripting quiet=`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: configProfileSetCmdRun,
+}
+
+func init() {
+	configProfileCmd.AddCommand(configProfileSetCmd)
+	configCmd.AddCommand(configProfileCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// configPreRunE replaces globalPreRun so that profiles can be managed without a session.
+func configPreRunE(cmd *cobra.Command, args []string) error {
+	if err := applyConfigFileFlags(cmd, nil); err != nil {
+		return err
+	}
+	configureColor()
+	return nil
+}
+
+// cubProfiles maps profile names to the default values of flags, by flag name.
+type cubProfiles map[string]map[string]string
+
+// profilesFilePath returns the path of the file containing the profiles. Profiles aren't saved
+// in the contexts so that they're kept when switching contexts.
+func profilesFilePath() string {
+	return filepath.Join(os.Getenv("HOME"), CONFIGHUB_DIR, "profiles.json")
+}
+
+// loadProfiles reads the saved profiles. There are none if the file doesn't exist.
+func loadProfiles() (cubProfiles, error) {
+	profiles := cubProfiles{}
+	data, err := os.ReadFile(profilesFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return profiles, nil
+		}
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+func saveProfiles(profiles cubProfiles) error {
+	configDir := filepath.Join(os.Getenv("HOME"), CONFIGHUB_DIR)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal profiles: %w", err)
+	}
+	if err := os.WriteFile(profilesFilePath(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write profiles to file: %w", err)
+	}
+	return nil
+}
+
+func configProfileSetCmdRun(_ *cobra.Command, args []string) error {
+	profiles, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+	if err := setProfileFlags(profiles, args[0], args[1:]); err != nil {
+		return err
+	}
+	if err := saveProfiles(profiles); err != nil {
+		return err
+	}
+	tprint("Profile %s updated", args[0])
+	return nil
+}
+
+// setProfileFlags updates the named profile with the specified <flag>=<value> pairs.
+func setProfileFlags(profiles cubProfiles, name string, flagValues []string) error {
+	if name == "" {
+		return fmt.Errorf("profile name must not be empty")
+	}
+	profile := profiles[name]
+	if profile == nil {
+		profile = map[string]string{}
+	}
+	for _, flagValue := range flagValues {
+		flagName, value, found := strings.Cut(flagValue, "=")
+		flagName = strings.TrimLeft(flagName, "-")
+		if !found || flagName == "" {
+			return fmt.Errorf("invalid flag value %s; expected <flag>=<value>", flagValue)
+		}
+		if flagName == "profile" {
+			return fmt.Errorf("profiles cannot set the profile flag")
+		}
+		if value == "" {
+			delete(profile, flagName)
+		} else {
+			profile[flagName] = value
+		}
+	}
+	if len(profile) == 0 {
+		delete(profiles, name)
+	} else {
+		profiles[name] = profile
+	}
+	return nil
+}
+
+// applyProfileFlags sets the flags of cmd that weren't specified on the command line to the
+// values of the profile selected with --profile, if any, and returns the names of the flags it
+// set. Flags of the profile not accepted by cmd are ignored. Like the values of the configuration
+// file, the values of the profile don't mark the flags as changed.
+func applyProfileFlags(cmd *cobra.Command) (map[string]bool, error) {
+	if selectedProfileName == "" {
+		return nil, nil
+	}
+	profiles, err := loadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	profile, ok := profiles[selectedProfileName]
+	if !ok {
+		return nil, fmt.Errorf("profile %s does not exist", selectedProfileName)
+	}
+	flagNames := make([]string, 0, len(profile))
+	for flagName := range profile {
+		flagNames = append(flagNames, flagName)
+	}
+	sort.Strings(flagNames)
+	applied := map[string]bool{}
+	for _, flagName := range flagNames {
+		flag := cmd.Flags().Lookup(flagName)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := flag.Value.Set(profile[flagName]); err != nil {
+			return nil, fmt.Errorf("invalid value %s for %s in profile %s: %w", profile[flagName], flagName, selectedProfileName, err)
+		}
+		applied[flagName] = true
+	}
+	return applied, nil
+}
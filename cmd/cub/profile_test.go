@@ -0,0 +1,116 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetProfileFlags(t *testing.T) {
+	profiles := cubProfiles{}
+	require.NoError(t, setProfileFlags(profiles, "scripting", []string{"quiet=true", "--json=true", "label=a=1,b=2"}))
+	assert.Equal(t, map[string]string{"quiet": "true", "json": "true", "label": "a=1,b=2"}, profiles["scripting"])
+
+	// Empty values remove flags, and empty profiles are removed
+	require.NoError(t, setProfileFlags(profiles, "scripting", []string{"json=", "label="}))
+	assert.Equal(t, map[string]string{"quiet": "true"}, profiles["scripting"])
+	require.NoError(t, setProfileFlags(profiles, "scripting", []string{"quiet="}))
+	assert.NotContains(t, profiles, "scripting")
+
+	assert.EqualError(t, setProfileFlags(profiles, "scripting", []string{"quiet"}), "invalid flag value quiet; expected <flag>=<value>")
+	assert.EqualError(t, setProfileFlags(profiles, "scripting", []string{"profile=other"}), "profiles cannot set the profile flag")
+	assert.EqualError(t, setProfileFlags(profiles, "", []string{"quiet=true"}), "profile name must not be empty")
+}
+
+func TestProfilesAreKeptWhenSwitchingContexts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	savedContext := cubContext
+	t.Cleanup(func() { cubContext = savedContext })
+
+	profiles, err := loadProfiles()
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+	require.NoError(t, setProfileFlags(profiles, "scripting", []string{"quiet=true"}))
+	require.NoError(t, saveProfiles(profiles))
+
+	// Replacing the current context doesn't affect the profiles
+	require.NoError(t, SaveCubContext(CubContext{Name: "other", Space: "other-space"}))
+	profiles, err = loadProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, cubProfiles{"scripting": {"quiet": "true"}}, profiles)
+}
+
+func TestApplyProfileFlags(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { selectedProfileName = "" })
+	require.NoError(t, saveProfiles(cubProfiles{
+		"debugging": {"verbose": "true", "label": "a=1,b=2", "count": "5", "unknown-flag": "ignored"},
+		"broken":    {"count": "many"},
+	}))
+	newCmd := func() (*cobra.Command, *bool, *[]string, *int) {
+		var verbose bool
+		var labels []string
+		var count int
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().BoolVar(&verbose, "verbose", false, "")
+		cmd.Flags().StringSliceVar(&labels, "label", []string{"default=0"}, "")
+		cmd.Flags().IntVar(&count, "count", 1, "")
+		return cmd, &verbose, &labels, &count
+	}
+
+	// No profile selected
+	cmd, verbose, _, _ := newCmd()
+	require.NoError(t, cmd.ParseFlags(nil))
+	applied, err := applyProfileFlags(cmd)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.False(t, *verbose)
+
+	selectedProfileName = "debugging"
+	cmd, verbose, labels, count := newCmd()
+	require.NoError(t, cmd.ParseFlags([]string{"--count", "7"}))
+	applied, err = applyProfileFlags(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"verbose": true, "label": true}, applied)
+	assert.True(t, *verbose)
+	assert.Equal(t, []string{"a=1", "b=2"}, *labels)
+	// Flags specified on the command line override the profile
+	assert.Equal(t, 7, *count)
+	// Profile values behave like defaults, so they don't satisfy required flags
+	assert.False(t, cmd.Flags().Changed("verbose"))
+
+	// Profile values take precedence over the configuration file
+	writeTestConfigFile(t, ".confighub.yaml", "verbose: false\ncount: 9\nlabel: [c=3]\n")
+	cmd, verbose, labels, count = newCmd()
+	require.NoError(t, cmd.ParseFlags(nil))
+	applied, err = applyProfileFlags(cmd)
+	require.NoError(t, err)
+	require.NoError(t, applyConfigFileFlags(cmd, applied))
+	assert.True(t, *verbose)
+	assert.Equal(t, []string{"a=1", "b=2"}, *labels)
+	assert.Equal(t, 5, *count)
+
+	selectedProfileName = "broken"
+	cmd, _, _, _ = newCmd()
+	require.NoError(t, cmd.ParseFlags(nil))
+	_, err = applyProfileFlags(cmd)
+	assert.ErrorContains(t, err, "invalid value many for count in profile broken")
+
+	selectedProfileName = "missing"
+	_, err = applyProfileFlags(cmd)
+	assert.EqualError(t, err, "profile missing does not exist")
+}
+
+func TestProfile_SpacePreRunReturnsErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { selectedProfileName = "" })
+	selectedProfileName = "missing"
+	cmd := &cobra.Command{Use: "test"}
+	require.NoError(t, cmd.ParseFlags(nil))
+	assert.EqualError(t, spacePreRunE(cmd, nil), "profile missing does not exist")
+}
@@ -0,0 +1,45 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// TimeoutEnvVar is the environment variable specifying the default timeout of API requests, as a
+// duration such as 30s or 2m, for commands without --wait.
+const TimeoutEnvVar = "CONFIGHUB_TIMEOUT"
+
+// defaultRequestTimeout is the timeout of API requests of commands without --wait if neither
+// --timeout nor CONFIGHUB_TIMEOUT is set.
+const defaultRequestTimeout = 30 * time.Second
+
+// requestTimeout is the timeout of each API request of the current command
+var requestTimeout = defaultRequestTimeout
+
+// resolveRequestTimeout returns the timeout of API requests for cmd. Commands with --wait use
+// their completion timeout, so that no request outlives it. Other commands use --timeout if
+// specified, or else CONFIGHUB_TIMEOUT, or else 30s.
+func resolveRequestTimeout(cmd *cobra.Command) (time.Duration, error) {
+	timeoutFlag := cmd.Flags().Lookup("timeout")
+	if timeoutFlag != nil && (timeoutFlag.Changed || cmd.Flags().Lookup("wait") != nil) {
+		duration, err := time.ParseDuration(timeoutFlag.Value.String())
+		if err != nil {
+			return 0, fmt.Errorf("invalid timeout duration %s: %w", timeoutFlag.Value.String(), err)
+		}
+		return duration, nil
+	}
+	if envTimeout := os.Getenv(TimeoutEnvVar); envTimeout != "" {
+		duration, err := time.ParseDuration(envTimeout)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %s: %w", TimeoutEnvVar, envTimeout, err)
+		}
+		return duration, nil
+	}
+	return defaultRequestTimeout, nil
+}
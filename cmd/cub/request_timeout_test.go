@@ -0,0 +1,51 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRequestTimeout(t *testing.T) {
+	newCmd := func(withWait bool, args ...string) *cobra.Command {
+		root := &cobra.Command{Use: "root"}
+		root.PersistentFlags().String("timeout", "", "")
+		cmd := &cobra.Command{Use: "test", Run: func(*cobra.Command, []string) {}}
+		if withWait {
+			cmd.Flags().Bool("wait", true, "")
+			cmd.Flags().String("timeout", "2m", "")
+		}
+		root.AddCommand(cmd)
+		root.SetArgs(append([]string{"test"}, args...))
+		require.NoError(t, root.Execute())
+		return cmd
+	}
+	resolve := func(cmd *cobra.Command) time.Duration {
+		timeout, err := resolveRequestTimeout(cmd)
+		require.NoError(t, err)
+		return timeout
+	}
+
+	t.Setenv(TimeoutEnvVar, "")
+	assert.Equal(t, defaultRequestTimeout, resolve(newCmd(false)))
+	assert.Equal(t, 5*time.Second, resolve(newCmd(false, "--timeout", "5s")))
+	assert.Equal(t, 2*time.Minute, resolve(newCmd(true)))
+	assert.Equal(t, 10*time.Minute, resolve(newCmd(true, "--timeout", "10m")))
+
+	t.Setenv(TimeoutEnvVar, "45s")
+	assert.Equal(t, 45*time.Second, resolve(newCmd(false)))
+	assert.Equal(t, 5*time.Second, resolve(newCmd(false, "--timeout", "5s")))
+	assert.Equal(t, 2*time.Minute, resolve(newCmd(true)))
+
+	t.Setenv(TimeoutEnvVar, "soon")
+	_, err := resolveRequestTimeout(newCmd(false))
+	assert.ErrorContains(t, err, "invalid CONFIGHUB_TIMEOUT soon")
+	_, err = resolveRequestTimeout(newCmd(false, "--timeout", "later"))
+	assert.ErrorContains(t, err, "invalid timeout duration later")
+}
@@ -6,6 +6,7 @@ package main
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
@@ -37,6 +38,12 @@ Examples:
   # List revisions with specific criteria
   cub revision list --space my-space --where 'RevisionNum > 1' my-ns
 
+  # List revisions created in the last 24 hours
+  cub revision list --space my-space --since 24h my-ns
+
+  # List revisions created during January 2025
+  cub revision list --space my-space --since 2025-01-01T00:00:00Z --until 2025-02-01T00:00:00Z my-ns
+
 `,
 	Args: cobra.ExactArgs(1),
 	RunE: revisionListCmdRun,
@@ -56,12 +63,16 @@ var revisionCustomColumnDependencies = map[string][]string{}
 
 func init() {
 	addStandardListFlags(revisionListCmd)
+	enableTimeWindowFlags(revisionListCmd)
 	revisionCmd.AddCommand(revisionListCmd)
 }
 
 func revisionListCmdRun(cmd *cobra.Command, args []string) error {
+	window, err := parseTimeWindow(since, until, time.Now())
+	if err != nil {
+		return err
+	}
 	var unit *goclientnew.Unit
-	var err error
 	unit, err = apiGetUnitFromSlug(args[0], "*") // get all fields for now
 	if err != nil {
 		return err
@@ -70,6 +81,9 @@ func revisionListCmdRun(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	revisions = filterByTimeWindow(revisions, window, func(extendedRevision *goclientnew.ExtendedRevision) time.Time {
+		return extendedRevision.Revision.CreatedAt
+	})
 	displayListResults(revisions, getRevisionSlug, displayRevisionList)
 	return nil
 }
@@ -0,0 +1,234 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// setManagedUnitsAnnotation is the set annotation that records the IDs of the units applied by
+// the most recent set apply. It is used to determine which units were removed from the set.
+const setManagedUnitsAnnotation = "ManagedUnits"
+
+var setApplyArgs struct {
+	prune  bool
+	dryRun bool
+}
+
+var setApplyCmd = &cobra.Command{
+	Use:   "apply <slug or id>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Apply the units in a set to their targets",
+	Long: `Apply all of the units in a set to their targets.
+
+Each apply records the units in the set in the set's ManagedUnits annotation. With --prune,
+units that were applied by a previous set apply but have since been removed from the set are
+destroyed, which deletes their resources from their targets through the bridge worker. Units
+that were moved to another set, were never applied, or no longer exist are not pruned.
+
+Examples:
+  # Apply all units in a set
+  cub set apply --space my-space my-set
+
+  # Apply a set and destroy the units that were removed from it
+  cub set apply --space my-space my-set --prune --wait
+
+  # Show what would be applied and pruned without making changes
+  cub set apply --space my-space my-set --prune --dry-run`,
+	RunE: setApplyCmdRun,
+}
+
+func init() {
+	enableWaitFlag(setApplyCmd)
+	enableQuietFlagForOperation(setApplyCmd)
+	enableJsonFlag(setApplyCmd)
+	setApplyCmd.Flags().BoolVar(&setApplyArgs.prune, "prune", false, "destroy units that were applied by a previous set apply but are no longer in the set")
+	setApplyCmd.Flags().BoolVar(&setApplyArgs.dryRun, "dry-run", false, "show what would be applied and pruned without making changes")
+	setCmd.AddCommand(setApplyCmd)
+}
+
+func setApplyCmdRun(_ *cobra.Command, args []string) error {
+	setDetails, err := apiGetSetFromSlug(args[0], "*") // get all fields for RMW
+	if err != nil {
+		return err
+	}
+
+	setWhere := "SetID = '" + setDetails.SetID.String() + "'"
+	units, err := apiListUnits(selectedSpaceID, setWhere, "*")
+	if err != nil {
+		return err
+	}
+
+	if len(units) > 0 {
+		if err := applySetUnits(setWhere); err != nil {
+			return err
+		}
+	} else if !quiet {
+		tprint("No units found in set %s", setDetails.Slug)
+	}
+
+	// Without --prune, units removed from the set remain managed so that a later prune removes them.
+	unpruned := staleSetUnitIDs(setManagedUnitIDs(setDetails), units)
+	if setApplyArgs.prune {
+		unpruned = pruneSetUnits(setDetails, unpruned)
+	}
+	if setApplyArgs.dryRun {
+		return nil
+	}
+
+	// Units that failed to be pruned remain managed so that the next prune retries them.
+	managed := make([]uuid.UUID, 0, len(units)+len(unpruned))
+	for _, unit := range units {
+		managed = append(managed, unit.UnitID)
+	}
+	managed = append(managed, unpruned...)
+	if err := apiRecordSetManagedUnits(setDetails, managed); err != nil {
+		return err
+	}
+	if setApplyArgs.prune && len(unpruned) > 0 {
+		return newAppError(ExitGenericError, fmt.Errorf("failed to prune %d unit(s) from set %s", len(unpruned), setDetails.Slug))
+	}
+	return nil
+}
+
+func applySetUnits(setWhere string) error {
+	params := &goclientnew.BulkApplyUnitsParams{
+		Where: addSpaceIDToWhereClause(setWhere, selectedSpaceID),
+	}
+	include := "UnitEventID,TargetID,UpstreamUnitID,SpaceID"
+	params.Include = &include
+	if setApplyArgs.dryRun {
+		params.DryRun = &setApplyArgs.dryRun
+	}
+
+	resp, err := cubClientNew.BulkApplyUnitsWithResponse(ctx, params)
+	if IsAPIError(err, resp) {
+		return InterpretErrorGeneric(err, resp)
+	}
+
+	// Handle the response - could be 200 (all success) or 207 (mixed results)
+	var responses *[]goclientnew.UnitActionResponse
+	if resp.JSON200 != nil {
+		responses = resp.JSON200
+	} else if resp.JSON207 != nil {
+		responses = resp.JSON207
+	} else {
+		return errors.New("unexpected response from bulk apply API")
+	}
+
+	return handleBulkApplyResponse(responses, setApplyArgs.dryRun)
+}
+
+// pruneSetUnits destroys the specified units that were removed from the set and returns the IDs
+// of the units that could not be destroyed.
+func pruneSetUnits(setDetails *goclientnew.Set, staleUnitIDs []uuid.UUID) []uuid.UUID {
+	var unpruned []uuid.UUID
+	if len(staleUnitIDs) == 0 {
+		if !quiet {
+			tprint("No units to prune from set %s", setDetails.Slug)
+		}
+		return unpruned
+	}
+	for _, unitID := range staleUnitIDs {
+		unitDetails, err := apiGetUnit(unitID.String(), "*")
+		if err != nil {
+			// The unit was most likely deleted, so it has nothing left to destroy
+			if !quiet {
+				tprint("Skipping prune of unit %s: %v", unitID, err)
+			}
+			continue
+		}
+		if !shouldPruneSetUnit(unitDetails, setDetails.SetID) {
+			continue
+		}
+		if setApplyArgs.dryRun {
+			if !quiet {
+				tprint("Would prune unit %s (%s)", unitDetails.Slug, unitID)
+			}
+			continue
+		}
+		destroyRes, err := cubClientNew.DestroyUnitWithResponse(ctx, unitDetails.SpaceID, unitID)
+		if IsAPIError(err, destroyRes) {
+			tprint("Failed to prune unit %s (%s): %v", unitDetails.Slug, unitID, InterpretErrorGeneric(err, destroyRes))
+			unpruned = append(unpruned, unitID)
+			continue
+		}
+		if !quiet && !wait {
+			tprint("Queued destroy for pruned unit %s (%s)", unitDetails.Slug, unitID)
+		}
+		if wait {
+			if err := awaitCompletion("destroy", destroyRes.JSON200); err != nil {
+				tprint("Failed to prune unit %s (%s): %v", unitDetails.Slug, unitID, err)
+				unpruned = append(unpruned, unitID)
+			}
+		}
+	}
+	return unpruned
+}
+
+// shouldPruneSetUnit returns true if a unit removed from the set still has resources on its
+// target and hasn't been added to a different set, which now manages it.
+func shouldPruneSetUnit(unitDetails *goclientnew.Unit, setID uuid.UUID) bool {
+	if unitDetails.SetID != nil && *unitDetails.SetID != setID {
+		return false
+	}
+	return unitDetails.TargetID != nil && unitDetails.LiveRevisionNum > 0
+}
+
+// setManagedUnitIDs returns the unit IDs recorded by the most recent set apply.
+func setManagedUnitIDs(setDetails *goclientnew.Set) []uuid.UUID {
+	var unitIDs []uuid.UUID
+	for _, id := range strings.Split(setDetails.Annotations[setManagedUnitsAnnotation], ",") {
+		unitID, err := uuid.Parse(strings.TrimSpace(id))
+		if err == nil {
+			unitIDs = append(unitIDs, unitID)
+		}
+	}
+	return unitIDs
+}
+
+// staleSetUnitIDs returns the previously managed unit IDs that are not among the current units
+// of the set.
+func staleSetUnitIDs(managed []uuid.UUID, units []*goclientnew.Unit) []uuid.UUID {
+	current := make(map[uuid.UUID]struct{}, len(units))
+	for _, unit := range units {
+		current[unit.UnitID] = struct{}{}
+	}
+	var stale []uuid.UUID
+	for _, unitID := range managed {
+		if _, ok := current[unitID]; !ok {
+			stale = append(stale, unitID)
+		}
+	}
+	return stale
+}
+
+func apiRecordSetManagedUnits(setDetails *goclientnew.Set, unitIDs []uuid.UUID) error {
+	ids := make([]string, 0, len(unitIDs))
+	for _, unitID := range unitIDs {
+		ids = append(ids, unitID.String())
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	value := strings.Join(ids, ",")
+	if setDetails.Annotations[setManagedUnitsAnnotation] == value {
+		return nil
+	}
+	if setDetails.Annotations == nil {
+		setDetails.Annotations = make(map[string]string)
+	}
+	setDetails.Annotations[setManagedUnitsAnnotation] = value
+	setRes, err := cubClientNew.UpdateSetWithResponse(ctx, setDetails.SpaceID, setDetails.SetID, *setDetails)
+	if IsAPIError(err, setRes) {
+		return InterpretErrorGeneric(err, setRes)
+	}
+	return nil
+}
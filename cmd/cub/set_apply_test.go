@@ -0,0 +1,174 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+// setApplyTestServer simulates a set whose last apply managed unitA and unitB, after unitB has
+// been removed from the set.
+type setApplyTestServer struct {
+	spaceID, setID, targetID, unitA, unitB uuid.UUID
+
+	mu          sync.Mutex
+	destroyed   []uuid.UUID
+	annotations map[string]string
+	updated     bool
+	// destroyFails makes the destroy of unitB fail
+	destroyFails bool
+}
+
+func newSetApplyTestServer(t *testing.T) (*setApplyTestServer, *httptest.Server) {
+	s := &setApplyTestServer{
+		spaceID:  uuid.New(),
+		setID:    uuid.New(),
+		targetID: uuid.New(),
+		unitA:    uuid.New(),
+		unitB:    uuid.New(),
+	}
+	s.annotations = map[string]string{setManagedUnitsAnnotation: s.unitA.String() + "," + s.unitB.String()}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		spacePrefix := "/space/" + s.spaceID.String()
+		var response any
+		switch r.URL.Path {
+		case spacePrefix + "/set/" + s.setID.String():
+			if r.Method == http.MethodGet {
+				response = goclientnew.ExtendedSet{Set: s.set()}
+				break
+			}
+			var set goclientnew.Set
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&set))
+			s.annotations = set.Annotations
+			s.updated = true
+			response = set
+		case spacePrefix + "/unit":
+			assert.Equal(t, "SetID = '"+s.setID.String()+"'", r.URL.Query().Get("where"))
+			response = []goclientnew.ExtendedUnit{{Unit: s.unit(s.unitA, &s.setID)}}
+		case "/unit/apply":
+			response = []goclientnew.UnitActionResponse{}
+		case spacePrefix + "/unit/" + s.unitB.String():
+			// unitB was removed from the set but its resources are still live
+			response = goclientnew.ExtendedUnit{Unit: s.unit(s.unitB, nil)}
+		case spacePrefix + "/unit/" + s.unitB.String() + "/destroy":
+			if s.destroyFails {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				assert.NoError(t, json.NewEncoder(w).Encode(goclientnew.StandardErrorResponse{Code: "500", Message: "destroy failed"}))
+				return
+			}
+			s.destroyed = append(s.destroyed, s.unitB)
+			response = goclientnew.QueuedOperation{UnitID: s.unitB, SpaceID: s.spaceID}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	return s, server
+}
+
+func (s *setApplyTestServer) set() *goclientnew.Set {
+	return &goclientnew.Set{
+		SetID:       s.setID,
+		SpaceID:     s.spaceID,
+		Slug:        "my-set",
+		Annotations: s.annotations,
+	}
+}
+
+func (s *setApplyTestServer) unit(unitID uuid.UUID, setID *uuid.UUID) *goclientnew.Unit {
+	return &goclientnew.Unit{
+		UnitID:          unitID,
+		SpaceID:         s.spaceID,
+		Slug:            "unit-" + unitID.String()[:8],
+		SetID:           setID,
+		TargetID:        &s.targetID,
+		LiveRevisionNum: 1,
+	}
+}
+
+func runSetApplyForTest(t *testing.T, prune, dryRun bool) *setApplyTestServer {
+	s, server := newSetApplyTestServer(t)
+	assert.NoError(t, runSetApplyWithServer(t, s, server, prune, dryRun))
+	return s
+}
+
+func runSetApplyWithServer(t *testing.T, s *setApplyTestServer, server *httptest.Server, prune, dryRun bool) error {
+	t.Cleanup(server.Close)
+
+	previousClient, previousSpaceID := cubClientNew, selectedSpaceID
+	var err error
+	cubClientNew, err = goclientnew.NewClientWithResponses(server.URL)
+	assert.NoError(t, err)
+	selectedSpaceID = s.spaceID.String()
+	quiet = true
+	previousWait := wait
+	wait = false
+	setApplyArgs.prune = prune
+	setApplyArgs.dryRun = dryRun
+	t.Cleanup(func() {
+		cubClientNew, selectedSpaceID = previousClient, previousSpaceID
+		quiet = false
+		wait = previousWait
+		setApplyArgs.prune = false
+		setApplyArgs.dryRun = false
+	})
+
+	return setApplyCmdRun(nil, []string{s.setID.String()})
+}
+
+func TestSetApplyPrunesUnitRemovedFromSet(t *testing.T) {
+	s := runSetApplyForTest(t, true, false)
+	assert.Equal(t, []uuid.UUID{s.unitB}, s.destroyed)
+	assert.Equal(t, s.unitA.String(), s.annotations[setManagedUnitsAnnotation])
+}
+
+func TestSetApplyPruneFailureReturnsError(t *testing.T) {
+	s, server := newSetApplyTestServer(t)
+	s.destroyFails = true
+	err := runSetApplyWithServer(t, s, server, true, false)
+	var appErr *AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ExitGenericError, exitCode(err))
+	// The unit that failed to be pruned remains managed so that the next prune retries it
+	assert.ElementsMatch(t, []uuid.UUID{s.unitA, s.unitB}, setManagedUnitIDs(&goclientnew.Set{Annotations: s.annotations}))
+}
+
+func TestSetApplyPruneDryRunMakesNoChanges(t *testing.T) {
+	s := runSetApplyForTest(t, true, true)
+	assert.Empty(t, s.destroyed)
+	assert.False(t, s.updated)
+}
+
+func TestSetApplyWithoutPruneKeepsRemovedUnit(t *testing.T) {
+	s := runSetApplyForTest(t, false, false)
+	assert.Empty(t, s.destroyed)
+	// The removed unit remains managed so that a later prune destroys it
+	assert.ElementsMatch(t, []uuid.UUID{s.unitA, s.unitB}, setManagedUnitIDs(&goclientnew.Set{Annotations: s.annotations}))
+}
+
+func TestShouldPruneSetUnit(t *testing.T) {
+	setID := uuid.New()
+	otherSetID := uuid.New()
+	targetID := uuid.New()
+	assert.True(t, shouldPruneSetUnit(&goclientnew.Unit{TargetID: &targetID, LiveRevisionNum: 2}, setID))
+	assert.True(t, shouldPruneSetUnit(&goclientnew.Unit{SetID: &setID, TargetID: &targetID, LiveRevisionNum: 2}, setID))
+	assert.False(t, shouldPruneSetUnit(&goclientnew.Unit{SetID: &otherSetID, TargetID: &targetID, LiveRevisionNum: 2}, setID))
+	assert.False(t, shouldPruneSetUnit(&goclientnew.Unit{TargetID: &targetID}, setID))
+	assert.False(t, shouldPruneSetUnit(&goclientnew.Unit{LiveRevisionNum: 2}, setID))
+}
@@ -50,7 +50,9 @@ func addSpaceFlags(cmd *cobra.Command) {
 
 // to be used by sub-commands that requires space ID
 func spacePreRunE(cmd *cobra.Command, args []string) error {
-	globalPreRun(cmd, args)
+	if err := globalPreRun(cmd, args); err != nil {
+		return err
+	}
 
 	if spaceFlag != "" {
 		if spaceFlag == "*" {
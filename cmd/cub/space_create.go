@@ -238,7 +238,7 @@ func handleBulkSpaceCreateOrUpdateResponse(responses []goclientnew.SpaceCreateOr
 // UnmarshalBinary interface implementation
 func UnmarshalBinary(m *goclientnew.Space, b []byte) error {
 	var res goclientnew.Space
-	if err := json.Unmarshal(b, res); err != nil {
+	if err := json.Unmarshal(b, &res); err != nil {
 		return err
 	}
 	*m = res
@@ -0,0 +1,289 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+var spaceExportCmd = &cobra.Command{
+	Use:   "export <space-slug>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Export a space to a bundle file",
+	Long: `Export the workers, invocations, sets, targets, units, links, and triggers of a space to a gzipped
+tar file, which can be imported into another space with 'cub space import'. The bundle contains a
+manifest.json file describing its contents and a JSON file for each type of entity. Units are exported
+with the configuration data of their head revisions. Worker secrets and live state are not exported.
+
+# Export a space to my-space.tar.gz
+cub space export my-space
+
+# Export a space to a specific file
+cub space export my-space --out backup.tar.gz`,
+	RunE: spaceExportCmdRun,
+}
+
+var spaceExportArgs struct {
+	out string
+}
+
+func init() {
+	spaceExportCmd.Flags().StringVar(&spaceExportArgs.out, "out", "", "file to write the bundle to; defaults to <space-slug>.tar.gz")
+	spaceCmd.AddCommand(spaceExportCmd)
+}
+
+// spaceBundleVersion is the version of the bundle format written by space export.
+const spaceBundleVersion = 1
+
+const spaceBundleManifestFile = "manifest.json"
+
+// spaceBundleManifest describes the contents of a space bundle.
+type spaceBundleManifest struct {
+	Version    int
+	Space      string
+	SpaceID    uuid.UUID
+	ExportedAt time.Time
+	// Number of entities in each file of the bundle
+	Files map[string]int
+}
+
+// spaceBundle contains the entities of a space. The entities retain their IDs so that the
+// references between them can be remapped when they're imported.
+type spaceBundle struct {
+	Manifest      spaceBundleManifest
+	BridgeWorkers []*goclientnew.BridgeWorker
+	Invocations   []*goclientnew.Invocation
+	Sets          []*goclientnew.Set
+	Targets       []*goclientnew.Target
+	Units         []*goclientnew.Unit
+	Links         []*goclientnew.Link
+	Triggers      []*goclientnew.Trigger
+}
+
+type spaceBundleFile struct {
+	name     string
+	entities any
+	count    int
+}
+
+// files returns the entity files of the bundle in the order in which they must be imported.
+func (b *spaceBundle) files() []spaceBundleFile {
+	return []spaceBundleFile{
+		{"workers.json", &b.BridgeWorkers, len(b.BridgeWorkers)},
+		{"invocations.json", &b.Invocations, len(b.Invocations)},
+		{"sets.json", &b.Sets, len(b.Sets)},
+		{"targets.json", &b.Targets, len(b.Targets)},
+		{"units.json", &b.Units, len(b.Units)},
+		{"links.json", &b.Links, len(b.Links)},
+		{"triggers.json", &b.Triggers, len(b.Triggers)},
+	}
+}
+
+func spaceExportCmdRun(_ *cobra.Command, args []string) error {
+	space, err := apiGetSpaceFromSlug(args[0], "*") // get all fields for now
+	if err != nil {
+		return err
+	}
+	bundle, err := exportSpace(space)
+	if err != nil {
+		return err
+	}
+	out := spaceExportArgs.out
+	if out == "" {
+		out = space.Slug + ".tar.gz"
+	}
+	file, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := writeSpaceBundle(file, bundle); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if !quiet {
+		tprint("Exported %d units, %d sets, %d targets, %d triggers, and %d links of space %s to %s",
+			len(bundle.Units), len(bundle.Sets), len(bundle.Targets), len(bundle.Triggers), len(bundle.Links), space.Slug, out)
+	}
+	return nil
+}
+
+// exportSpace fetches all of the entities of the space.
+func exportSpace(space *goclientnew.Space) (*spaceBundle, error) {
+	spaceID := space.SpaceID.String()
+	bundle := &spaceBundle{
+		Manifest: spaceBundleManifest{
+			Version:    spaceBundleVersion,
+			Space:      space.Slug,
+			SpaceID:    space.SpaceID,
+			ExportedAt: time.Now().UTC(),
+		},
+	}
+
+	workers, err := apiListBridgeworkers(spaceID, "", "*")
+	if err != nil {
+		return nil, err
+	}
+	for _, worker := range workers {
+		if worker.BridgeWorker != nil {
+			exported := *worker.BridgeWorker
+			// New secrets are generated for imported workers
+			exported.Secret = ""
+			bundle.BridgeWorkers = append(bundle.BridgeWorkers, &exported)
+		}
+	}
+	invocations, err := apiListInvocations(spaceID, "", "*")
+	if err != nil {
+		return nil, err
+	}
+	for _, invocation := range invocations {
+		if invocation.Invocation != nil {
+			bundle.Invocations = append(bundle.Invocations, invocation.Invocation)
+		}
+	}
+	bundle.Sets, err = apiListSets(spaceID, "", "*")
+	if err != nil {
+		return nil, err
+	}
+	targets, err := apiListTargets(spaceID, "", "*")
+	if err != nil {
+		return nil, err
+	}
+	for _, target := range targets {
+		if target.Target != nil {
+			bundle.Targets = append(bundle.Targets, target.Target)
+		}
+	}
+	units, err := apiListUnits(spaceID, "", "*")
+	if err != nil {
+		return nil, err
+	}
+	for _, unit := range units {
+		exported := *unit
+		// Live state belongs to the target rather than the configuration
+		exported.LiveState = ""
+		bundle.Units = append(bundle.Units, &exported)
+	}
+	links, err := apiListLinks(spaceID, "", "*")
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		if link.Link != nil {
+			bundle.Links = append(bundle.Links, link.Link)
+		}
+	}
+	triggers, err := apiListTriggers(spaceID, "", "*")
+	if err != nil {
+		return nil, err
+	}
+	for _, trigger := range triggers {
+		if trigger.Trigger != nil {
+			bundle.Triggers = append(bundle.Triggers, trigger.Trigger)
+		}
+	}
+	return bundle, nil
+}
+
+// writeSpaceBundle writes the bundle as a gzipped tar file containing the manifest followed
+// by the entity files.
+func writeSpaceBundle(w io.Writer, bundle *spaceBundle) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	bundle.Manifest.Files = map[string]int{}
+	for _, file := range bundle.files() {
+		bundle.Manifest.Files[file.name] = file.count
+	}
+	writeFile := func(name string, contents any) error {
+		data, err := json.MarshalIndent(contents, "", "  ")
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: bundle.Manifest.ExportedAt,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = tarWriter.Write(data)
+		return err
+	}
+	if err := writeFile(spaceBundleManifestFile, bundle.Manifest); err != nil {
+		return err
+	}
+	for _, file := range bundle.files() {
+		if err := writeFile(file.name, file.entities); err != nil {
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// readSpaceBundle reads a bundle written by writeSpaceBundle.
+func readSpaceBundle(r io.Reader) (*spaceBundle, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a space bundle: %w", err)
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+	bundle := &spaceBundle{}
+	files := map[string]any{spaceBundleManifestFile: &bundle.Manifest}
+	for _, file := range bundle.files() {
+		files[file.name] = file.entities
+	}
+	hasManifest := false
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		entities, known := files[header.Name]
+		if !known {
+			continue
+		}
+		if err := json.NewDecoder(tarReader).Decode(entities); err != nil {
+			return nil, fmt.Errorf("failed to read %s from space bundle: %w", header.Name, err)
+		}
+		if header.Name == spaceBundleManifestFile {
+			hasManifest = true
+			if bundle.Manifest.Version > spaceBundleVersion {
+				return nil, fmt.Errorf("space bundle version %d is not supported; upgrade cub to import it", bundle.Manifest.Version)
+			}
+		}
+	}
+	if !hasManifest {
+		return nil, fmt.Errorf("not a space bundle: %s is missing", spaceBundleManifestFile)
+	}
+	// Detect truncated or partially written bundles
+	for _, file := range bundle.files() {
+		if file.count != bundle.Manifest.Files[file.name] {
+			return nil, fmt.Errorf("space bundle is incomplete: %s contains %d entities rather than %d", file.name, file.count, bundle.Manifest.Files[file.name])
+		}
+	}
+	return bundle, nil
+}
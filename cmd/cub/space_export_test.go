@@ -0,0 +1,275 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+// spaceEntityKinds maps the path segments of space-scoped entities to their extended entity
+// fields and ID fields.
+var spaceEntityKinds = map[string]struct{ field, idField string }{
+	"bridge_worker": {"BridgeWorker", "BridgeWorkerID"},
+	"invocation":    {"Invocation", "InvocationID"},
+	"set":           {"Set", "SetID"},
+	"target":        {"Target", "TargetID"},
+	"unit":          {"Unit", "UnitID"},
+	"link":          {"Link", "LinkID"},
+	"trigger":       {"Trigger", "TriggerID"},
+}
+
+// spaceTestServer stores the entities of spaces in memory, keyed by space ID and kind.
+type spaceTestServer struct {
+	mu       sync.Mutex
+	entities map[string]map[string][]map[string]any
+	updates  int
+}
+
+func newSpaceTestServer(t *testing.T) *spaceTestServer {
+	s := &spaceTestServer{entities: map[string]map[string][]map[string]any{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// /space/<space-id>/<kind>[/<id>]
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if len(parts) < 3 || parts[0] != "space" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		spaceID, kind := parts[1], parts[2]
+		var response any
+		switch {
+		case len(parts) == 3 && r.Method == http.MethodGet:
+			list := []map[string]any{}
+			for _, entity := range s.entities[spaceID][kind] {
+				list = append(list, map[string]any{spaceEntityKinds[kind].field: entity})
+			}
+			response = list
+		case len(parts) == 3 && r.Method == http.MethodPost:
+			var entity map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&entity))
+			if upstreamUnitID := r.URL.Query().Get("upstream_unit_id"); upstreamUnitID != "" {
+				// Clones start with the data of their upstream units
+				upstreamSpaceID := r.URL.Query().Get("upstream_space_id")
+				upstream := s.find(upstreamSpaceID, "unit", upstreamUnitID)
+				if assert.NotNil(t, upstream, "upstream unit %s", upstreamUnitID) {
+					entity["Data"] = upstream["Data"]
+					entity["UpstreamUnitID"] = upstreamUnitID
+					entity["UpstreamSpaceID"] = upstreamSpaceID
+				}
+			}
+			response = s.add(spaceID, kind, entity)
+		case len(parts) == 4 && kind == "unit" && r.Method == http.MethodPut:
+			var entity map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&entity))
+			existing := s.find(spaceID, kind, parts[3])
+			assert.NotNil(t, existing)
+			for key, value := range entity {
+				existing[key] = value
+			}
+			s.updates++
+			response = existing
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	t.Cleanup(server.Close)
+
+	savedClient := cubClientNew
+	t.Cleanup(func() { cubClientNew = savedClient })
+	var err error
+	cubClientNew, err = goclientnew.NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+	return s
+}
+
+// add stores the entity with a new ID and returns it.
+func (s *spaceTestServer) add(spaceID, kind string, entity map[string]any) map[string]any {
+	entity[spaceEntityKinds[kind].idField] = uuid.New().String()
+	entity["SpaceID"] = spaceID
+	if s.entities[spaceID] == nil {
+		s.entities[spaceID] = map[string][]map[string]any{}
+	}
+	s.entities[spaceID][kind] = append(s.entities[spaceID][kind], entity)
+	return entity
+}
+
+func (s *spaceTestServer) find(spaceID, kind, id string) map[string]any {
+	for _, entity := range s.entities[spaceID][kind] {
+		if entity[spaceEntityKinds[kind].idField] == id {
+			return entity
+		}
+	}
+	return nil
+}
+
+func (s *spaceTestServer) findSlug(spaceID, kind, slug string) map[string]any {
+	for _, entity := range s.entities[spaceID][kind] {
+		if entity["Slug"] == slug {
+			return entity
+		}
+	}
+	return nil
+}
+
+// seed stores the entity, given as an API model, and returns its ID.
+func (s *spaceTestServer) seed(t *testing.T, spaceID uuid.UUID, kind string, model any) uuid.UUID {
+	data, err := json.Marshal(model)
+	require.NoError(t, err)
+	var entity map[string]any
+	require.NoError(t, json.Unmarshal(data, &entity))
+	entity = s.add(spaceID.String(), kind, entity)
+	return uuid.MustParse(entity[spaceEntityKinds[kind].idField].(string))
+}
+
+func TestSpaceExportImportRoundTrip(t *testing.T) {
+	s := newSpaceTestServer(t)
+	sourceSpaceID, destSpaceID, otherSpaceID := uuid.New(), uuid.New(), uuid.New()
+	baseData := base64.StdEncoding.EncodeToString([]byte("replicas: 1\n"))
+	appData := base64.StdEncoding.EncodeToString([]byte("replicas: 3\n"))
+
+	workerID := s.seed(t, sourceSpaceID, "bridge_worker", goclientnew.BridgeWorker{Slug: "worker", Secret: "secret"})
+	invocationID := s.seed(t, sourceSpaceID, "invocation", goclientnew.Invocation{Slug: "check", FunctionName: "no-placeholders", ToolchainType: "Kubernetes/YAML", BridgeWorkerID: &workerID})
+	setID := s.seed(t, sourceSpaceID, "set", goclientnew.Set{Slug: "apps", Annotations: map[string]string{setManagedUnitsAnnotation: uuid.NewString(), "owner": "platform"}})
+	targetID := s.seed(t, sourceSpaceID, "target", goclientnew.Target{Slug: "cluster", BridgeWorkerID: workerID, ProviderType: "Kubernetes", ToolchainType: "Kubernetes/YAML"})
+	baseID := s.seed(t, sourceSpaceID, "unit", goclientnew.Unit{Slug: "base", ToolchainType: "Kubernetes/YAML", Data: baseData, SetID: &setID, TargetID: &targetID, HeadRevisionNum: 4})
+	appID := s.seed(t, sourceSpaceID, "unit", goclientnew.Unit{Slug: "app", ToolchainType: "Kubernetes/YAML", Data: appData, UpstreamUnitID: &baseID, UpstreamSpaceID: &sourceSpaceID, HeadRevisionNum: 2})
+	sharedID := s.seed(t, otherSpaceID, "unit", goclientnew.Unit{Slug: "shared", ToolchainType: "Kubernetes/YAML"})
+	s.seed(t, sourceSpaceID, "link", goclientnew.Link{Slug: "app-to-base", FromUnitID: appID, ToUnitID: baseID, ToSpaceID: sourceSpaceID})
+	s.seed(t, sourceSpaceID, "link", goclientnew.Link{Slug: "app-to-shared", FromUnitID: appID, ToUnitID: sharedID, ToSpaceID: otherSpaceID})
+	s.seed(t, sourceSpaceID, "trigger", goclientnew.Trigger{Slug: "validate", Event: "Mutation", ToolchainType: "Kubernetes/YAML", InvocationID: &invocationID, Validating: true})
+
+	bundle, err := exportSpace(&goclientnew.Space{SpaceID: sourceSpaceID, Slug: "source"})
+	require.NoError(t, err)
+	assert.Empty(t, bundle.BridgeWorkers[0].Secret)
+	var buffer bytes.Buffer
+	require.NoError(t, writeSpaceBundle(&buffer, bundle))
+	read, err := readSpaceBundle(&buffer)
+	require.NoError(t, err)
+	assert.Equal(t, "source", read.Manifest.Space)
+	assert.Equal(t, map[string]int{
+		"workers.json": 1, "invocations.json": 1, "sets.json": 1, "targets.json": 1,
+		"units.json": 2, "links.json": 2, "triggers.json": 1,
+	}, read.Manifest.Files)
+
+	imported, err := importSpace(read, destSpaceID)
+	require.NoError(t, err)
+	assert.Len(t, imported.Units, 2)
+	dest := destSpaceID.String()
+	for kind, count := range map[string]int{"bridge_worker": 1, "invocation": 1, "set": 1, "target": 1, "unit": 2, "link": 2, "trigger": 1} {
+		assert.Len(t, s.entities[dest][kind], count, kind)
+	}
+
+	// References within the bundle refer to the imported entities
+	worker := s.findSlug(dest, "bridge_worker", "worker")
+	invocation := s.findSlug(dest, "invocation", "check")
+	set := s.findSlug(dest, "set", "apps")
+	target := s.findSlug(dest, "target", "cluster")
+	base := s.findSlug(dest, "unit", "base")
+	app := s.findSlug(dest, "unit", "app")
+	assert.Equal(t, worker["BridgeWorkerID"], invocation["BridgeWorkerID"])
+	assert.Equal(t, worker["BridgeWorkerID"], target["BridgeWorkerID"])
+	assert.Equal(t, map[string]any{"owner": "platform"}, set["Annotations"])
+	assert.Equal(t, set["SetID"], base["SetID"])
+	assert.Equal(t, target["TargetID"], base["TargetID"])
+	assert.Equal(t, baseData, base["Data"])
+	assert.Equal(t, "Imported from revision 4 in space source", base["LastChangeDescription"])
+	assert.Equal(t, invocation["InvocationID"], s.findSlug(dest, "trigger", "validate")["InvocationID"])
+
+	// The clone keeps its own data
+	assert.Equal(t, base["UnitID"], app["UpstreamUnitID"])
+	assert.Equal(t, dest, app["UpstreamSpaceID"])
+	assert.Equal(t, appData, app["Data"])
+	assert.Equal(t, 1, s.updates)
+
+	// Links within the space are remapped and links to other spaces are kept
+	baseLink := s.findSlug(dest, "link", "app-to-base")
+	assert.Equal(t, app["UnitID"], baseLink["FromUnitID"])
+	assert.Equal(t, base["UnitID"], baseLink["ToUnitID"])
+	assert.Equal(t, dest, baseLink["ToSpaceID"])
+	sharedLink := s.findSlug(dest, "link", "app-to-shared")
+	assert.Equal(t, sharedID.String(), sharedLink["ToUnitID"])
+	assert.Equal(t, otherSpaceID.String(), sharedLink["ToSpaceID"])
+}
+
+func TestReadSpaceBundleErrors(t *testing.T) {
+	_, err := readSpaceBundle(strings.NewReader("not a bundle"))
+	assert.ErrorContains(t, err, "not a space bundle")
+
+	bundle := &spaceBundle{Manifest: spaceBundleManifest{Version: spaceBundleVersion + 1}}
+	var buffer bytes.Buffer
+	require.NoError(t, writeSpaceBundle(&buffer, bundle))
+	_, err = readSpaceBundle(&buffer)
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestSpaceImportReportsPartialImports(t *testing.T) {
+	s := newSpaceTestServer(t)
+	savedSpaceID, savedSpaceSlug, savedQuiet := selectedSpaceID, selectedSpaceSlug, quiet
+	t.Cleanup(func() { selectedSpaceID, selectedSpaceSlug, quiet = savedSpaceID, savedSpaceSlug, savedQuiet })
+	destSpaceID := uuid.New()
+	selectedSpaceID, selectedSpaceSlug, quiet = destSpaceID.String(), "dest", false
+
+	// The units can't be ordered, so the import fails after creating the set
+	baseID, appID := uuid.New(), uuid.New()
+	bundle := &spaceBundle{
+		Manifest: spaceBundleManifest{Version: spaceBundleVersion, Space: "source", SpaceID: uuid.New()},
+		Sets:     []*goclientnew.Set{{Slug: "apps"}},
+		Units: []*goclientnew.Unit{
+			{UnitID: baseID, Slug: "base", UpstreamUnitID: &appID},
+			{UnitID: appID, Slug: "app", UpstreamUnitID: &baseID},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, writeSpaceBundle(file, bundle))
+	require.NoError(t, file.Close())
+
+	output := captureStdout(t, func() {
+		assert.ErrorContains(t, spaceImportCmdRun(spaceImportCmd, []string{path}), "cycle")
+	})
+	assert.Equal(t, "Created 0 workers, 0 invocations, 1 sets, 0 targets, 0 units, 0 links, and 0 triggers in space dest before the import failed\n", output)
+	assert.Len(t, s.entities[destSpaceID.String()]["set"], 1)
+}
+
+func TestUnitsInUpstreamOrder(t *testing.T) {
+	baseID, midID, leafID := uuid.New(), uuid.New(), uuid.New()
+	units := []*goclientnew.Unit{
+		{UnitID: leafID, Slug: "leaf", UpstreamUnitID: &midID},
+		{UnitID: midID, Slug: "mid", UpstreamUnitID: &baseID},
+		{UnitID: baseID, Slug: "base"},
+	}
+	ordered, err := unitsInUpstreamOrder(units)
+	require.NoError(t, err)
+	slugs := []string{}
+	for _, unit := range ordered {
+		slugs = append(slugs, unit.Slug)
+	}
+	assert.Equal(t, []string{"base", "mid", "leaf"}, slugs)
+
+	units[2].UpstreamUnitID = &leafID
+	_, err = unitsInUpstreamOrder(units)
+	assert.Error(t, err)
+}
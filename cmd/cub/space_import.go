@@ -0,0 +1,282 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+var spaceImportCmd = &cobra.Command{
+	Use:   "import <bundle-file>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Import a space bundle into a space",
+	Long: `Import a bundle written by 'cub space export' into an existing space, creating its workers,
+invocations, sets, targets, units, links, and triggers. References between the entities of the
+bundle are remapped to the IDs of the newly created entities, and references to entities in other
+spaces are kept. Triggers are created last so that they don't run on the imported units. Imported
+workers have new secrets, which can be retrieved with 'cub worker get-secret'.
+
+# Create a space and import a bundle into it
+cub space create restored-space
+cub space import my-space.tar.gz --space restored-space`,
+	PersistentPreRunE: spacePreRunE,
+	RunE:              spaceImportCmdRun,
+}
+
+func init() {
+	addSpaceFlags(spaceImportCmd)
+	spaceCmd.AddCommand(spaceImportCmd)
+}
+
+func spaceImportCmdRun(_ *cobra.Command, args []string) error {
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	bundle, err := readSpaceBundle(file)
+	if err != nil {
+		return err
+	}
+	imported, err := importSpace(bundle, uuid.MustParse(selectedSpaceID))
+	if err != nil {
+		// The entities created before the failure remain in the space
+		if !quiet {
+			tprint("Created %d workers, %d invocations, %d sets, %d targets, %d units, %d links, and %d triggers in space %s before the import failed",
+				len(imported.BridgeWorkers), len(imported.Invocations), len(imported.Sets), len(imported.Targets), len(imported.Units), len(imported.Links), len(imported.Triggers), selectedSpaceSlug)
+		}
+		return err
+	}
+	if !quiet {
+		tprint("Imported %d units, %d sets, %d targets, %d triggers, and %d links of space %s into space %s",
+			len(imported.Units), len(imported.Sets), len(imported.Targets), len(imported.Triggers), len(imported.Links), bundle.Manifest.Space, selectedSpaceSlug)
+	}
+	return nil
+}
+
+// spaceImportIDs maps the IDs of the entities in a bundle, including the exported space, to
+// the IDs of the entities created from them.
+type spaceImportIDs map[uuid.UUID]uuid.UUID
+
+// remap returns the ID of the entity created for the ID, or the ID itself if it refers to an
+// entity outside the bundle.
+func (ids spaceImportIDs) remap(id uuid.UUID) uuid.UUID {
+	if newID, found := ids[id]; found {
+		return newID
+	}
+	return id
+}
+
+func (ids spaceImportIDs) remapOptional(id *uuid.UUID) *uuid.UUID {
+	if id == nil {
+		return nil
+	}
+	newID := ids.remap(*id)
+	return &newID
+}
+
+// importSpace creates the entities of the bundle in the space, in dependency order, and returns
+// the created entities.
+func importSpace(bundle *spaceBundle, spaceID uuid.UUID) (*spaceBundle, error) {
+	ids := spaceImportIDs{bundle.Manifest.SpaceID: spaceID}
+	imported := &spaceBundle{Manifest: bundle.Manifest}
+	imported.Manifest.SpaceID = spaceID
+
+	for _, worker := range bundle.BridgeWorkers {
+		res, err := cubClientNew.CreateBridgeWorkerWithResponse(ctx, spaceID, goclientnew.BridgeWorker{
+			SpaceID:     spaceID,
+			Slug:        worker.Slug,
+			DisplayName: worker.DisplayName,
+			Labels:      worker.Labels,
+			Annotations: worker.Annotations,
+		})
+		if IsAPIError(err, res) {
+			return imported, fmt.Errorf("failed to create worker %s: %w", worker.Slug, InterpretErrorGeneric(err, res))
+		}
+		ids[worker.BridgeWorkerID] = res.JSON200.BridgeWorkerID
+		imported.BridgeWorkers = append(imported.BridgeWorkers, res.JSON200)
+	}
+
+	for _, invocation := range bundle.Invocations {
+		res, err := cubClientNew.CreateInvocationWithResponse(ctx, spaceID, goclientnew.Invocation{
+			SpaceID:        spaceID,
+			Slug:           invocation.Slug,
+			DisplayName:    invocation.DisplayName,
+			Labels:         invocation.Labels,
+			Annotations:    invocation.Annotations,
+			ToolchainType:  invocation.ToolchainType,
+			FunctionName:   invocation.FunctionName,
+			Arguments:      invocation.Arguments,
+			BridgeWorkerID: ids.remapOptional(invocation.BridgeWorkerID),
+		})
+		if IsAPIError(err, res) {
+			return imported, fmt.Errorf("failed to create invocation %s: %w", invocation.Slug, InterpretErrorGeneric(err, res))
+		}
+		ids[invocation.InvocationID] = res.JSON200.InvocationID
+		imported.Invocations = append(imported.Invocations, res.JSON200)
+	}
+
+	for _, set := range bundle.Sets {
+		annotations := map[string]string{}
+		for key, value := range set.Annotations {
+			// None of the imported units have been applied
+			if key != setManagedUnitsAnnotation {
+				annotations[key] = value
+			}
+		}
+		res, err := cubClientNew.CreateSetWithResponse(ctx, spaceID, goclientnew.Set{
+			SpaceID:     spaceID,
+			Slug:        set.Slug,
+			DisplayName: set.DisplayName,
+			Labels:      set.Labels,
+			Annotations: annotations,
+		})
+		if IsAPIError(err, res) {
+			return imported, fmt.Errorf("failed to create set %s: %w", set.Slug, InterpretErrorGeneric(err, res))
+		}
+		ids[set.SetID] = res.JSON200.SetID
+		imported.Sets = append(imported.Sets, res.JSON200)
+	}
+
+	for _, target := range bundle.Targets {
+		res, err := cubClientNew.CreateTargetWithResponse(ctx, spaceID, goclientnew.Target{
+			SpaceID:        spaceID,
+			Slug:           target.Slug,
+			DisplayName:    target.DisplayName,
+			Labels:         target.Labels,
+			Annotations:    target.Annotations,
+			BridgeWorkerID: ids.remap(target.BridgeWorkerID),
+			Parameters:     target.Parameters,
+			ProviderType:   target.ProviderType,
+			ToolchainType:  target.ToolchainType,
+		})
+		if IsAPIError(err, res) {
+			return imported, fmt.Errorf("failed to create target %s: %w", target.Slug, InterpretErrorGeneric(err, res))
+		}
+		ids[target.TargetID] = res.JSON200.TargetID
+		imported.Targets = append(imported.Targets, res.JSON200)
+	}
+
+	units, err := unitsInUpstreamOrder(bundle.Units)
+	if err != nil {
+		return imported, err
+	}
+	for _, unit := range units {
+		created, err := importUnit(unit, spaceID, ids, bundle.Manifest.Space)
+		if err != nil {
+			return imported, err
+		}
+		ids[unit.UnitID] = created.UnitID
+		imported.Units = append(imported.Units, created)
+	}
+
+	for _, link := range bundle.Links {
+		res, err := cubClientNew.CreateLinkWithResponse(ctx, spaceID, goclientnew.Link{
+			SpaceID:     spaceID,
+			Slug:        link.Slug,
+			DisplayName: link.DisplayName,
+			Labels:      link.Labels,
+			Annotations: link.Annotations,
+			FromUnitID:  ids.remap(link.FromUnitID),
+			ToUnitID:    ids.remap(link.ToUnitID),
+			ToSpaceID:   ids.remap(link.ToSpaceID),
+		})
+		if IsAPIError(err, res) {
+			return imported, fmt.Errorf("failed to create link %s: %w", link.Slug, InterpretErrorGeneric(err, res))
+		}
+		ids[link.LinkID] = res.JSON200.LinkID
+		imported.Links = append(imported.Links, res.JSON200)
+	}
+
+	for _, trigger := range bundle.Triggers {
+		res, err := cubClientNew.CreateTriggerWithResponse(ctx, spaceID, goclientnew.Trigger{
+			SpaceID:        spaceID,
+			Slug:           trigger.Slug,
+			DisplayName:    trigger.DisplayName,
+			Labels:         trigger.Labels,
+			Annotations:    trigger.Annotations,
+			Event:          trigger.Event,
+			ToolchainType:  trigger.ToolchainType,
+			FunctionName:   trigger.FunctionName,
+			Arguments:      trigger.Arguments,
+			BridgeWorkerID: ids.remapOptional(trigger.BridgeWorkerID),
+			InvocationID:   ids.remapOptional(trigger.InvocationID),
+			Disabled:       trigger.Disabled,
+			Enforced:       trigger.Enforced,
+			Validating:     trigger.Validating,
+		})
+		if IsAPIError(err, res) {
+			return imported, fmt.Errorf("failed to create trigger %s: %w", trigger.Slug, InterpretErrorGeneric(err, res))
+		}
+		ids[trigger.TriggerID] = res.JSON200.TriggerID
+		imported.Triggers = append(imported.Triggers, res.JSON200)
+	}
+	return imported, nil
+}
+
+// unitsInUpstreamOrder orders the units so that units whose upstream units are in the bundle
+// follow them.
+func unitsInUpstreamOrder(units []*goclientnew.Unit) ([]*goclientnew.Unit, error) {
+	pending := map[uuid.UUID]bool{}
+	for _, unit := range units {
+		pending[unit.UnitID] = true
+	}
+	ordered := make([]*goclientnew.Unit, 0, len(units))
+	for len(ordered) < len(units) {
+		progressed := false
+		for _, unit := range units {
+			if !pending[unit.UnitID] || (unit.UpstreamUnitID != nil && pending[*unit.UpstreamUnitID]) {
+				continue
+			}
+			delete(pending, unit.UnitID)
+			ordered = append(ordered, unit)
+			progressed = true
+		}
+		if !progressed {
+			return nil, fmt.Errorf("space bundle contains a cycle of upstream units")
+		}
+	}
+	return ordered, nil
+}
+
+// importUnit creates the unit with the configuration data it had when it was exported. Units
+// with upstream units are cloned from them so that they can be upgraded, and then updated if
+// their data differs from that of their upstream units.
+func importUnit(unit *goclientnew.Unit, spaceID uuid.UUID, ids spaceImportIDs, exportedSpaceSlug string) (*goclientnew.Unit, error) {
+	params := &goclientnew.CreateUnitParams{}
+	if unit.UpstreamUnitID != nil && unit.UpstreamSpaceID != nil {
+		params.UpstreamUnitId = ids.remapOptional(unit.UpstreamUnitID)
+		params.UpstreamSpaceId = ids.remapOptional(unit.UpstreamSpaceID)
+	}
+	newUnit := goclientnew.Unit{
+		SpaceID:               spaceID,
+		Slug:                  unit.Slug,
+		DisplayName:           unit.DisplayName,
+		Labels:                unit.Labels,
+		Annotations:           unit.Annotations,
+		ToolchainType:         unit.ToolchainType,
+		Data:                  unit.Data,
+		SetID:                 ids.remapOptional(unit.SetID),
+		TargetID:              ids.remapOptional(unit.TargetID),
+		LastChangeDescription: fmt.Sprintf("Imported from revision %d in space %s", unit.HeadRevisionNum, exportedSpaceSlug),
+	}
+	res, err := cubClientNew.CreateUnitWithResponse(ctx, spaceID, params, newUnit)
+	if IsAPIError(err, res) {
+		return nil, fmt.Errorf("failed to create unit %s: %w", unit.Slug, InterpretErrorGeneric(err, res))
+	}
+	created := res.JSON200
+	if created.Data == unit.Data {
+		return created, nil
+	}
+	updated := *created
+	updated.Data = unit.Data
+	updated.LastChangeDescription = newUnit.LastChangeDescription
+	return updateUnit(spaceID, &updated, &goclientnew.UpdateUnitParams{})
+}
@@ -0,0 +1,130 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/confighub/sdk/cmd/internal/tree"
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+// defaultParentSpaceLabel is the space label containing the slug of the parent of a space
+const defaultParentSpaceLabel = "ParentSpace"
+
+var spaceTreeArgs struct {
+	root        string
+	depth       int
+	parentLabel string
+}
+
+var spaceTreeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "Display the space hierarchy as a tree",
+	Long: `Display the spaces you have access to in this organization as a tree, with the display name and number of units of each space. The parent of a space is the space whose slug is the value of its ParentSpace label, or of the label specified by --parent-label. Spaces without parents are the roots of the trees.
+
+Examples:
+  # Display all spaces
+  cub space tree
+
+  # Display the spaces below platform, two levels deep
+  cub space tree --root platform --depth 2
+
+  # Display the hierarchy specified by the Team label
+  cub space tree --parent-label Team
+`,
+	Args: cobra.NoArgs,
+	RunE: spaceTreeCmdRun,
+}
+
+func init() {
+	enableWhereFlag(spaceTreeCmd)
+	spaceTreeCmd.Flags().StringVar(&spaceTreeArgs.root, "root", "", "slug of the space to display as the root of the tree")
+	spaceTreeCmd.Flags().IntVar(&spaceTreeArgs.depth, "depth", 0, "maximum number of levels to display, including the root; 0 means unlimited")
+	spaceTreeCmd.Flags().StringVar(&spaceTreeArgs.parentLabel, "parent-label", defaultParentSpaceLabel, "space label containing the slug of the parent space")
+	spaceCmd.AddCommand(spaceTreeCmd)
+}
+
+func spaceTreeCmdRun(cmd *cobra.Command, args []string) error {
+	if spaceTreeArgs.depth < 0 {
+		return newAppError(ExitValidationError, fmt.Errorf("--depth must not be negative: %d", spaceTreeArgs.depth))
+	}
+	extendedSpaces, err := apiListExtendedSpaces(where, "*")
+	if err != nil {
+		return err
+	}
+	roots, err := buildSpaceTree(extendedSpaces, spaceTreeArgs.parentLabel, spaceTreeArgs.root)
+	if err != nil {
+		return err
+	}
+	return tree.Render(os.Stdout, roots, spaceTreeArgs.depth)
+}
+
+func spaceTreeLabel(extendedSpace *goclientnew.ExtendedSpace) string {
+	space := extendedSpace.Space
+	label := space.Slug
+	if space.DisplayName != "" && space.DisplayName != space.Slug {
+		label += " (" + space.DisplayName + ")"
+	}
+	units := "units"
+	if extendedSpace.TotalUnitCount == 1 {
+		units = "unit"
+	}
+	return fmt.Sprintf("%s [%d %s]", label, extendedSpace.TotalUnitCount, units)
+}
+
+// buildSpaceTree returns the roots of the trees of spaces whose parents are specified by the
+// parentLabel label, sorted by slug. Spaces whose parents aren't in the list, or that are in a
+// cycle of parents, are roots. If root is not empty, only the tree of the space with that slug
+// is returned.
+func buildSpaceTree(extendedSpaces []*goclientnew.ExtendedSpace, parentLabel string, root string) ([]*tree.Node, error) {
+	sort.Slice(extendedSpaces, func(i, j int) bool {
+		return extendedSpaces[i].Space.Slug < extendedSpaces[j].Space.Slug
+	})
+	nodes := map[string]*tree.Node{}
+	parents := map[string]string{}
+	for _, extendedSpace := range extendedSpaces {
+		slug := extendedSpace.Space.Slug
+		nodes[slug] = &tree.Node{Label: spaceTreeLabel(extendedSpace)}
+		parents[slug] = extendedSpace.Space.Labels[parentLabel]
+	}
+	hasParent := func(slug string) bool {
+		parent := parents[slug]
+		if _, found := nodes[parent]; !found || parent == slug {
+			return false
+		}
+		// Spaces in cycles are treated as roots
+		visited := map[string]bool{slug: true}
+		for ancestor := parent; ancestor != ""; ancestor = parents[ancestor] {
+			if visited[ancestor] {
+				return ancestor != slug
+			}
+			visited[ancestor] = true
+		}
+		return true
+	}
+
+	roots := []*tree.Node{}
+	for _, extendedSpace := range extendedSpaces {
+		slug := extendedSpace.Space.Slug
+		if hasParent(slug) {
+			parentNode := nodes[parents[slug]]
+			parentNode.Children = append(parentNode.Children, nodes[slug])
+		} else {
+			roots = append(roots, nodes[slug])
+		}
+	}
+	if root == "" {
+		return roots, nil
+	}
+	rootNode, found := nodes[root]
+	if !found {
+		return nil, newAppError(ExitNotFound, fmt.Errorf("space %s not found", root))
+	}
+	return []*tree.Node{rootNode}, nil
+}
@@ -0,0 +1,62 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/cmd/internal/tree"
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+func testTreeSpace(slug, displayName, parent string, unitCount int64) *goclientnew.ExtendedSpace {
+	labels := map[string]string{}
+	if parent != "" {
+		labels[defaultParentSpaceLabel] = parent
+	}
+	return &goclientnew.ExtendedSpace{
+		Space:          &goclientnew.Space{Slug: slug, DisplayName: displayName, Labels: labels},
+		TotalUnitCount: unitCount,
+	}
+}
+
+func TestBuildSpaceTree(t *testing.T) {
+	spaces := func() []*goclientnew.ExtendedSpace {
+		return []*goclientnew.ExtendedSpace{
+			testTreeSpace("prod", "Production", "platform", 3),
+			testTreeSpace("platform", "platform", "", 0),
+			testTreeSpace("dev", "", "platform", 1),
+			testTreeSpace("dev-alice", "", "dev", 2),
+			testTreeSpace("orphan", "", "missing", 0),
+			testTreeSpace("loop-a", "", "loop-b", 0),
+			testTreeSpace("loop-b", "", "loop-a", 0),
+		}
+	}
+
+	roots, err := buildSpaceTree(spaces(), defaultParentSpaceLabel, "")
+	require.NoError(t, err)
+	var b strings.Builder
+	require.NoError(t, tree.Render(&b, roots, 0))
+	assert.Equal(t, `loop-a [0 units]
+loop-b [0 units]
+orphan [0 units]
+platform [0 units]
+├── dev [1 unit]
+│   └── dev-alice [2 units]
+└── prod (Production) [3 units]
+`, b.String())
+
+	roots, err = buildSpaceTree(spaces(), defaultParentSpaceLabel, "dev")
+	require.NoError(t, err)
+	b.Reset()
+	require.NoError(t, tree.Render(&b, roots, 0))
+	assert.Equal(t, "dev [1 unit]\n└── dev-alice [2 units]\n", b.String())
+
+	_, err = buildSpaceTree(spaces(), defaultParentSpaceLabel, "staging")
+	assert.Error(t, err)
+}
@@ -53,6 +53,7 @@ func IsAPIError(err error, resp APIResponse) bool {
 
 // InterpretErrorGeneric checks the response for any errors and returns an error if found.
 // If we found no non-nil JSON4xx or JSON5xx, presumably it is a 2xx success or client initiated termination.
+// Errors for HTTP error responses are AppErrors with the exit code corresponding to the status code.
 func InterpretErrorGeneric(err error, resp interface{}) error {
 	if err != nil {
 		return err
@@ -74,7 +75,7 @@ func InterpretErrorGeneric(err error, resp interface{}) error {
 		httpResponseValue := httpResponseField.Interface()
 		httpResponse, ok := httpResponseValue.(*http.Response)
 		if ok {
-			requestID = httpResponse.Header.Get("X-Request-Id")
+			requestID = responseRequestID(httpResponse)
 		}
 	}
 
@@ -91,16 +92,31 @@ func InterpretErrorGeneric(err error, resp interface{}) error {
 			res := v.MethodByName("StatusCode").Call(nil)
 			code := res[0].Int()
 			stdErrRes, ok := field.Interface().(*goclientnew.StandardErrorResponse)
+			exitCode := exitCodeForHTTPStatus(int(code))
 			if !ok {
-				return fmt.Errorf("Unexpected response type for %s status %d req %s: %v", name, code, requestID, field.Type())
+				return newAppError(exitCode, fmt.Errorf("Unexpected response type for %s status %d req %s: %v", name, code, requestID, field.Type()))
 			}
-			return fmt.Errorf("HTTP %d for req %s: %s", code, requestID, stdErrRes.Message)
+			return newAppError(exitCode, fmt.Errorf("HTTP %d for req %s: %s", code, requestID, stdErrRes.Message))
 		}
 	}
 	// This should be a nil JSON200 response
 	return errors.New("no response body from server for req " + requestID)
 }
 
+// responseRequestID returns the request or trace ID echoed by the server in the response, if
+// any, or else the X-Request-ID sent by CubTransport.
+func responseRequestID(httpResponse *http.Response) string {
+	for _, header := range []string{requestIDHeader, "X-Trace-ID"} {
+		if id := httpResponse.Header.Get(header); id != "" {
+			return id
+		}
+	}
+	if httpResponse.Request != nil {
+		return httpResponse.Request.Header.Get(requestIDHeader)
+	}
+	return ""
+}
+
 // displayResponseErrorDetails displays detailed information for a single ResponseError
 func displayResponseErrorDetails(respError *goclientnew.ResponseError) {
 	table := detailView()
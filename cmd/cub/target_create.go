@@ -122,8 +122,10 @@ func validateToolchainAndProvider(toolchainType string, providerType string) err
 	// TODO: Use SupportedToolchains
 	if toolchainType != string(workerapi.ToolchainKubernetesYAML) &&
 		toolchainType != string(workerapi.ToolchainOpenTofuHCL) &&
-		toolchainType != string(workerapi.ToolchainAppConfigProperties) {
-		return errors.New("toolchain must be one of: Kubernetes/YAML, OpenTofu/HCL, AppConfig/Properties")
+		toolchainType != string(workerapi.ToolchainAppConfigProperties) &&
+		toolchainType != string(workerapi.ToolchainAppConfigYAML) &&
+		toolchainType != string(workerapi.ToolchainAnsibleVars) {
+		return errors.New("toolchain must be one of: Kubernetes/YAML, OpenTofu/HCL, AppConfig/Properties, AppConfig/YAML, Ansible/Vars")
 	}
 	if providerType != string(api.ProviderKubernetes) &&
 		providerType != string(api.ProviderAWS) &&
@@ -140,10 +142,12 @@ func validateToolchainAndProvider(toolchainType string, providerType string) err
 	}
 	if providerType == string(api.ProviderConfigMap) &&
 		toolchainType != string(workerapi.ToolchainAppConfigProperties) &&
+		toolchainType != string(workerapi.ToolchainAppConfigYAML) &&
 		toolchainType != string(workerapi.ToolchainAppConfigTOML) &&
 		toolchainType != string(workerapi.ToolchainAppConfigINI) &&
-		toolchainType != string(workerapi.ToolchainAppConfigEnv) {
-		return errors.New("provider ConfigMap requires toolchain AppConfig/Properties, AppConfig/TOML, AppConfig/INI, or AppConfig/Env")
+		toolchainType != string(workerapi.ToolchainAppConfigEnv) &&
+		toolchainType != string(workerapi.ToolchainAnsibleVars) {
+		return errors.New("provider ConfigMap requires toolchain AppConfig/Properties, AppConfig/YAML, AppConfig/TOML, AppConfig/INI, AppConfig/Env, or Ansible/Vars")
 	}
 	return nil
 }
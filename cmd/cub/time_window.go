@@ -0,0 +1,88 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	since string
+	until string
+)
+
+func enableTimeWindowFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&since, "since", "", "Only list entries created at or after this time: a duration before now, such as 30m, 24h, or 7d, or an RFC3339 timestamp, such as 2025-01-01T00:00:00Z. Can be combined with --where using AND logic")
+	cmd.Flags().StringVar(&until, "until", "", "Only list entries created at or before this time, in the same format as --since")
+}
+
+// timeWindow is the range of creation times specified by --since and --until. Zero times are unbounded.
+type timeWindow struct {
+	Since time.Time
+	Until time.Time
+}
+
+var dayDurationRegexp = regexp.MustCompile(`^(\d+)d$`)
+
+// parseTimeBound parses a duration before now, such as 24h or 7d, or an RFC3339 timestamp.
+func parseTimeBound(flagName string, value string, now time.Time) (time.Time, error) {
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+	if matches := dayDurationRegexp.FindStringSubmatch(value); matches != nil {
+		days, err := strconv.Atoi(matches[1])
+		if err == nil {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return time.Time{}, newAppError(ExitValidationError, fmt.Errorf("invalid --%s %q: expected a duration such as 24h or 7d or an RFC3339 timestamp such as 2025-01-01T00:00:00Z", flagName, value))
+	}
+	return now.Add(-duration), nil
+}
+
+// parseTimeWindow parses the values of --since and --until relative to now.
+func parseTimeWindow(sinceValue, untilValue string, now time.Time) (timeWindow, error) {
+	var window timeWindow
+	var err error
+	if sinceValue != "" {
+		if window.Since, err = parseTimeBound("since", sinceValue, now); err != nil {
+			return window, err
+		}
+	}
+	if untilValue != "" {
+		if window.Until, err = parseTimeBound("until", untilValue, now); err != nil {
+			return window, err
+		}
+	}
+	if !window.Since.IsZero() && !window.Until.IsZero() && window.Since.After(window.Until) {
+		return window, newAppError(ExitValidationError, fmt.Errorf("--since %s is after --until %s", window.Since.Format(time.RFC3339), window.Until.Format(time.RFC3339)))
+	}
+	return window, nil
+}
+
+// Contains returns true if t is within the window, inclusive of its bounds.
+func (w timeWindow) Contains(t time.Time) bool {
+	return (w.Since.IsZero() || !t.Before(w.Since)) && (w.Until.IsZero() || !t.After(w.Until))
+}
+
+// filterByTimeWindow returns the entities whose creation times, as returned by getCreatedAt, are within the window.
+func filterByTimeWindow[Entity any](entities []Entity, window timeWindow, getCreatedAt func(Entity) time.Time) []Entity {
+	if window.Since.IsZero() && window.Until.IsZero() {
+		return entities
+	}
+	filtered := []Entity{}
+	for _, entity := range entities {
+		if window.Contains(getCreatedAt(entity)) {
+			filtered = append(filtered, entity)
+		}
+	}
+	return filtered
+}
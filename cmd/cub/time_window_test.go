@@ -0,0 +1,67 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+func TestParseTimeWindow(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	window, err := parseTimeWindow("24h", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-24*time.Hour), window.Since)
+	assert.True(t, window.Until.IsZero())
+
+	window, err = parseTimeWindow("7d", "2025-03-09T08:30:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC), window.Since)
+	assert.Equal(t, time.Date(2025, 3, 9, 8, 30, 0, 0, time.UTC), window.Until)
+
+	window, err = parseTimeWindow("2025-03-01T00:00:00-08:00", "", now)
+	require.NoError(t, err)
+	assert.True(t, window.Since.Equal(time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)))
+
+	_, err = parseTimeWindow("yesterday", "", now)
+	assert.ErrorContains(t, err, `invalid --since "yesterday"`)
+	assert.Equal(t, ExitValidationError, exitCode(err))
+	_, err = parseTimeWindow("", "-1h", now)
+	assert.ErrorContains(t, err, `invalid --until "-1h"`)
+	_, err = parseTimeWindow("1h", "2h", now)
+	assert.ErrorContains(t, err, "is after --until")
+}
+
+func TestFilterByTimeWindow(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	revisions := []*goclientnew.ExtendedRevision{}
+	for i, age := range []time.Duration{time.Hour, 23 * time.Hour, 24 * time.Hour, 72 * time.Hour} {
+		revisions = append(revisions, &goclientnew.ExtendedRevision{
+			Revision: &goclientnew.Revision{RevisionNum: int64(4 - i), CreatedAt: now.Add(-age)},
+		})
+	}
+	revisionNums := func(window timeWindow) []int64 {
+		nums := []int64{}
+		for _, revision := range filterByTimeWindow(revisions, window, func(r *goclientnew.ExtendedRevision) time.Time { return r.Revision.CreatedAt }) {
+			nums = append(nums, revision.Revision.RevisionNum)
+		}
+		return nums
+	}
+
+	window, err := parseTimeWindow("24h", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 3, 2}, revisionNums(window))
+
+	window, err = parseTimeWindow("3d", "2h", now)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 2, 1}, revisionNums(window))
+
+	assert.Equal(t, []int64{4, 3, 2, 1}, revisionNums(timeWindow{}))
+}
@@ -155,10 +155,10 @@ func runBulkUnitApply() error {
 		return errors.New("unexpected response from bulk apply API")
 	}
 
-	return handleBulkApplyResponse(responses)
+	return handleBulkApplyResponse(responses, unitApplyArgs.dryRun)
 }
 
-func handleBulkApplyResponse(results *[]goclientnew.UnitActionResponse) error {
+func handleBulkApplyResponse(results *[]goclientnew.UnitActionResponse, dryRun bool) error {
 	if results == nil || len(*results) == 0 {
 		if !quiet {
 			tprint("No units found matching the filter")
@@ -206,7 +206,7 @@ func handleBulkApplyResponse(results *[]goclientnew.UnitActionResponse) error {
 	// Display summary
 	if !quiet {
 		tprint("") // blank line before summary
-		if unitApplyArgs.dryRun {
+		if dryRun {
 			tprint("Dry run completed (no changes made)")
 			tprint("Units that would be applied: %d", successCount)
 			if failureCount > 0 {
@@ -223,7 +223,7 @@ func handleBulkApplyResponse(results *[]goclientnew.UnitActionResponse) error {
 	}
 
 	// If wait flag is set and not dry run, wait for all operations to complete
-	if wait && !unitApplyArgs.dryRun && len(queuedOps) > 0 {
+	if wait && !dryRun && len(queuedOps) > 0 {
 		if !quiet {
 			tprint("")
 			tprint("Waiting for %d operation(s) to complete...", len(queuedOps))
@@ -337,7 +337,7 @@ func awaitCompletion(action string, queuedOp *goclientnew.QueuedOperation) error
 		}
 	}
 	if !done {
-		return errors.New(string(*queuedOp.Action) + " didn't complete on unit " + unitIDString)
+		return newAppError(ExitTimeout, errors.New(string(*queuedOp.Action)+" didn't complete on unit "+unitIDString+" within "+timeout))
 	}
 	if failed {
 		return errors.New(string(*queuedOp.Action) + " failed on unit " + unitIDString)
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -37,6 +38,10 @@ Examples:
   # List units with specific labels
   cub unit list --space my-space --where "Labels.tier = 'Backend'"
 
+  # List units by a key of the JSON stored in an annotation, or by any label value
+  cub unit list --space my-space --where "Annotations.config.owner = 'alice'"
+  cub unit list --space my-space --where "Labels.* = 'Backend'"
+
   # List units with approval gates
   cub unit list --space my-space --where "ApplyGates.require-approval/is-approved = true"
 
@@ -173,7 +178,6 @@ func init() {
 }
 
 func unitListCmdRun(cmd *cobra.Command, args []string) error {
-	var err error
 	if whereData != "" {
 		if selectedSpaceID != "*" {
 			slugQuery := "SpaceID='" + selectedSpaceID + "'"
@@ -185,17 +189,22 @@ func unitListCmdRun(cmd *cobra.Command, args []string) error {
 			selectedSpaceID = "*"
 		}
 	}
-	var extendedUnits []*goclientnew.ExtendedUnit
-	if selectedSpaceID == "*" {
-		extendedUnits, err = apiSearchUnits(where, resourceType, whereData, selectFields)
-		if err != nil {
-			return err
+	listUnits := func(whereFilter string) ([]*goclientnew.ExtendedUnit, error) {
+		selectParam := selectFields
+		if whereFilter != where {
+			// Nested conditions are filtered client-side, so the fields they reference are needed
+			selectParam = "*"
 		}
-	} else {
-		extendedUnits, err = apiListExtendedUnits(selectedSpaceID, where, selectFields)
-		if err != nil {
-			return err
+		if selectedSpaceID == "*" {
+			return apiSearchUnits(whereFilter, resourceType, whereData, selectParam)
 		}
+		return apiListExtendedUnits(selectedSpaceID, whereFilter, selectParam)
+	}
+	extendedUnits, err := listWithNestedWhere(where, listUnits, func(extendedUnit *goclientnew.ExtendedUnit) any {
+		return extendedUnit.Unit
+	})
+	if err != nil {
+		return err
 	}
 	displayListResults(extendedUnits, getExtendedUnitSlug, displayExtendedUnitList)
 	return nil
@@ -227,6 +236,11 @@ func apiListUnits(spaceID string, whereFilter string, selectParam string) ([]*go
 }
 
 func apiListExtendedUnits(spaceID string, whereFilter string, selectParam string) ([]*goclientnew.ExtendedUnit, error) {
+	return apiListExtendedUnitsWithContext(ctx, spaceID, whereFilter, selectParam)
+}
+
+// apiListExtendedUnitsWithContext is apiListExtendedUnits with the request bound to ctx.
+func apiListExtendedUnitsWithContext(ctx context.Context, spaceID string, whereFilter string, selectParam string) ([]*goclientnew.ExtendedUnit, error) {
 	newParams := &goclientnew.ListUnitsParams{}
 	if whereFilter != "" {
 		newParams.Where = &whereFilter
@@ -0,0 +1,199 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+var unitPromoteCmd = &cobra.Command{
+	Use:   "promote <unit-slug>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Promote a unit's configuration from one space to another",
+	Long: `Promote the current configuration data of a unit from a source space to the unit with the same
+slug in a target space, creating a new revision of the target unit. If the unit doesn't exist in
+the target space, it's created with the toolchain type, labels, and data of the source unit.
+
+# Promote a unit from staging to production
+cub unit promote my-app --from staging --to production
+
+# Show what would be promoted without changing anything
+cub unit promote my-app --from staging --to production --dry-run
+
+# Promote with a change description
+cub unit promote my-app --from staging --to production --change-desc "Promote release 1.2"
+
+# Promote and wait until the promoted revision has been applied, such as by a trigger
+cub unit promote my-app --from staging --to production --wait --timeout 5m`,
+	// --from and --to select the spaces, so --space isn't required
+	PersistentPreRunE: globalPreRun,
+	RunE:              unitPromoteCmdRun,
+}
+
+var unitPromoteArgs struct {
+	fromSpace string
+	toSpace   string
+	dryRun    bool
+}
+
+func init() {
+	enableVerboseFlag(unitPromoteCmd)
+	enableQuietFlag(unitPromoteCmd)
+	enableJsonFlag(unitPromoteCmd)
+	enableJqFlag(unitPromoteCmd)
+	// Promotion doesn't apply the unit, so unlike other commands it doesn't wait by default
+	unitPromoteCmd.Flags().BoolVar(&wait, "wait", false, "wait until the promoted revision is live on the target of the unit")
+	unitPromoteCmd.Flags().StringVar(&timeout, "timeout", "2m", "completion timeout as a duration with units, such as 10s or 2m")
+	unitPromoteCmd.Flags().StringVar(&unitPromoteArgs.fromSpace, "from", "", "slug of the space to promote the unit from")
+	unitPromoteCmd.Flags().StringVar(&unitPromoteArgs.toSpace, "to", "", "slug of the space to promote the unit to")
+	unitPromoteCmd.Flags().BoolVar(&unitPromoteArgs.dryRun, "dry-run", false, "show what would be promoted without creating or updating the unit")
+	unitPromoteCmd.Flags().StringVar(&changeDescription, "change-desc", "", "change description")
+	_ = unitPromoteCmd.MarkFlagRequired("from")
+	_ = unitPromoteCmd.MarkFlagRequired("to")
+	unitCmd.AddCommand(unitPromoteCmd)
+}
+
+func unitPromoteCmdRun(_ *cobra.Command, args []string) error {
+	if unitPromoteArgs.fromSpace == unitPromoteArgs.toSpace {
+		return errors.New("--from and --to must be different spaces")
+	}
+	fromSpace, err := apiGetSpaceFromSlug(unitPromoteArgs.fromSpace, "*") // get all fields for now
+	if err != nil {
+		return err
+	}
+	toSpace, err := apiGetSpaceFromSlug(unitPromoteArgs.toSpace, "*") // get all fields for now
+	if err != nil {
+		return err
+	}
+	source, err := apiGetUnitFromSlugInSpace(args[0], fromSpace.SpaceID.String(), "*") // get all fields for now
+	if err != nil {
+		return err
+	}
+	// A missing target unit isn't an error, so list rather than get it
+	targets, err := apiListUnits(toSpace.SpaceID.String(), "Slug = '"+source.Slug+"'", "*")
+	if err != nil {
+		return err
+	}
+	var target *goclientnew.Unit
+	for _, unit := range targets {
+		if unit.Slug == source.Slug {
+			target = unit
+		}
+	}
+
+	description := changeDescription
+	if description == "" {
+		description = fmt.Sprintf("Promoted from revision %d in space %s", source.HeadRevisionNum, fromSpace.Slug)
+	}
+	promoted := promotedUnit(source, target, toSpace.SpaceID, description)
+	if unitPromoteArgs.dryRun {
+		displayPromotionPlan(source, target, fromSpace.Slug, toSpace.Slug)
+		return nil
+	}
+
+	if target == nil {
+		unitRes, err := cubClientNew.CreateUnitWithResponse(ctx, toSpace.SpaceID, &goclientnew.CreateUnitParams{}, *promoted)
+		if IsAPIError(err, unitRes) {
+			return InterpretErrorGeneric(err, unitRes)
+		}
+		unitDetails := unitRes.JSON200
+		if err := awaitPromotion(unitDetails); err != nil {
+			return err
+		}
+		displayCreateResults(unitDetails, "unit", unitDetails.Slug, unitDetails.UnitID.String(), displayUnitDetails)
+		return nil
+	}
+
+	unitDetails, err := updateUnit(toSpace.SpaceID, promoted, &goclientnew.UpdateUnitParams{})
+	if err != nil {
+		return err
+	}
+	if err := awaitPromotion(unitDetails); err != nil {
+		return err
+	}
+	displayUpdateResults(unitDetails, "unit", unitDetails.Slug, unitDetails.UnitID.String(), displayUnitDetails)
+	return nil
+}
+
+// awaitPromotion waits for the triggers of the promoted unit to execute and, with --wait, for the
+// promoted revision to become live.
+func awaitPromotion(unitDetails *goclientnew.Unit) error {
+	if err := awaitTriggersRemoval(unitDetails); err != nil {
+		return err
+	}
+	if !wait {
+		return nil
+	}
+	if unitDetails.TargetID == nil {
+		return fmt.Errorf("unit %s has no target, so the promoted revision can't become live", unitDetails.Slug)
+	}
+	return awaitLiveRevision(unitDetails, unitDetails.HeadRevisionNum)
+}
+
+// awaitLiveRevision polls the unit until its LiveRevisionNum reaches revisionNum, which happens
+// when the revision is applied, or --timeout elapses.
+func awaitLiveRevision(unitDetails *goclientnew.Unit, revisionNum int64) error {
+	timeoutDuration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return errors.New("invalid timeout duration " + timeout)
+	}
+	unitID, spaceID := unitDetails.UnitID.String(), unitDetails.SpaceID.String()
+	sleepDuration := 200 * time.Millisecond
+	maxSleepDuration := sleepDuration * 32
+	startTime := time.Now()
+	for unitDetails.LiveRevisionNum < revisionNum {
+		if time.Since(startTime) >= timeoutDuration {
+			return newAppError(ExitTimeout, fmt.Errorf("revision %d of unit %s didn't become live within %s", revisionNum, unitDetails.Slug, timeout))
+		}
+		time.Sleep(sleepDuration)
+		sleepDuration *= 2
+		if sleepDuration > maxSleepDuration {
+			sleepDuration = maxSleepDuration
+		}
+		unitDetails, err = apiGetUnitInSpace(unitID, spaceID, "*") // get all fields for now
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promotedUnit returns the unit to create or update in the target space so that its data is that
+// of the source unit. The target is nil if the unit doesn't exist in the target space yet, in which
+// case it's created with the slug, toolchain type, and labels of the source.
+func promotedUnit(source, target *goclientnew.Unit, targetSpaceID uuid.UUID, description string) *goclientnew.Unit {
+	if target == nil {
+		return &goclientnew.Unit{
+			SpaceID:               targetSpaceID,
+			Slug:                  source.Slug,
+			DisplayName:           source.DisplayName,
+			ToolchainType:         source.ToolchainType,
+			Labels:                source.Labels,
+			Data:                  source.Data,
+			LastChangeDescription: description,
+		}
+	}
+	promoted := *target
+	promoted.Data = source.Data
+	promoted.LastChangeDescription = description
+	return &promoted
+}
+
+func displayPromotionPlan(source, target *goclientnew.Unit, fromSpaceSlug, toSpaceSlug string) {
+	switch {
+	case target == nil:
+		tprint("Would create unit %s in space %s from revision %d in space %s", source.Slug, toSpaceSlug, source.HeadRevisionNum, fromSpaceSlug)
+	case target.Data == source.Data:
+		tprint("Unit %s in space %s already has the data of revision %d in space %s", source.Slug, toSpaceSlug, source.HeadRevisionNum, fromSpaceSlug)
+	default:
+		tprint("Would update unit %s in space %s from revision %d to the data of revision %d in space %s", source.Slug, toSpaceSlug, target.HeadRevisionNum, source.HeadRevisionNum, fromSpaceSlug)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+func TestPromotedUnit(t *testing.T) {
+	sourceSpaceID := uuid.New()
+	targetSpaceID := uuid.New()
+	source := &goclientnew.Unit{
+		SpaceID:         sourceSpaceID,
+		UnitID:          uuid.New(),
+		Slug:            "my-app",
+		ToolchainType:   "Kubernetes/YAML",
+		Labels:          map[string]string{"tier": "backend"},
+		Data:            "new-data",
+		HeadRevisionNum: 7,
+	}
+
+	created := promotedUnit(source, nil, targetSpaceID, "promote")
+	assert.Equal(t, &goclientnew.Unit{
+		SpaceID:               targetSpaceID,
+		Slug:                  "my-app",
+		ToolchainType:         "Kubernetes/YAML",
+		Labels:                map[string]string{"tier": "backend"},
+		Data:                  "new-data",
+		LastChangeDescription: "promote",
+	}, created)
+
+	target := &goclientnew.Unit{
+		SpaceID:         targetSpaceID,
+		UnitID:          uuid.New(),
+		Slug:            "my-app",
+		ToolchainType:   "Kubernetes/YAML",
+		Labels:          map[string]string{"tier": "backend", "env": "prod"},
+		Data:            "old-data",
+		HeadRevisionNum: 3,
+	}
+	updated := promotedUnit(source, target, targetSpaceID, "promote")
+	assert.Equal(t, target.UnitID, updated.UnitID)
+	assert.Equal(t, target.Labels, updated.Labels)
+	assert.Equal(t, "new-data", updated.Data)
+	assert.Equal(t, "promote", updated.LastChangeDescription)
+	assert.Equal(t, "old-data", target.Data, "the target unit shouldn't be modified")
+}
+
+func TestAwaitPromotion(t *testing.T) {
+	savedClient, savedWait, savedTimeout := cubClientNew, wait, timeout
+	t.Cleanup(func() { cubClientNew, wait, timeout = savedClient, savedWait, savedTimeout })
+	targetID := uuid.New()
+	unit := &goclientnew.Unit{SpaceID: uuid.New(), UnitID: uuid.New(), Slug: "my-app", TargetID: &targetID, HeadRevisionNum: 4, LiveRevisionNum: 3}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/space/"+unit.SpaceID.String()+"/unit/"+unit.UnitID.String(), r.URL.Path)
+		polls++
+		live := *unit
+		if polls > 1 {
+			// The promoted revision is applied by the second poll
+			live.LiveRevisionNum = 4
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(goclientnew.ExtendedUnit{Unit: &live}))
+	}))
+	defer server.Close()
+	var err error
+	cubClientNew, err = goclientnew.NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+
+	// Without --wait, only the triggers are awaited
+	wait, timeout = false, "2m"
+	assert.NoError(t, awaitPromotion(unit))
+	assert.Equal(t, 0, polls)
+
+	wait = true
+	assert.NoError(t, awaitPromotion(unit))
+	assert.Equal(t, 2, polls)
+
+	timeout = "0s"
+	assert.ErrorContains(t, awaitPromotion(unit), "revision 4 of unit my-app didn't become live within 0s")
+
+	unit.TargetID = nil
+	assert.ErrorContains(t, awaitPromotion(unit), "has no target")
+}
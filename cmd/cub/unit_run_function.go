@@ -0,0 +1,152 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/shlex"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+var unitRunFunctionCmd = &cobra.Command{
+	Use:   "run-function <unit-slug> [<function-name>]",
+	Short: "Run functions on a unit",
+	Long: `Run one or more functions on the configuration data of a unit and show the result: the mutated
+configuration data of mutating functions, and the output of other functions, such as validation results.
+
+The function specified as an argument receives the arguments specified with --arg as
+<parameter-name>=<value>. Additional functions to run after it are specified with --function as
+the function name followed by its arguments, quoted as in a shell.
+
+Examples:
+  # Set the replicas of a unit's workloads
+  cub unit run-function --space my-space my-deployment set-replicas --arg replicas=3
+
+  # Preview a change without updating the unit
+  cub unit run-function --space my-space my-deployment set-image --arg container-name=nginx --arg container-image=nginx:1.27 --dry-run
+
+  # Chain functions: set the image and then validate that no placeholders remain
+  cub unit run-function --space my-space my-deployment --function "set-image nginx nginx:1.27" --function no-placeholders`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: unitRunFunctionCmdRun,
+}
+
+var unitRunFunctionArgs struct {
+	args       []string
+	functions  []string
+	dryRun     bool
+	changeDesc string
+}
+
+func init() {
+	unitRunFunctionCmd.Flags().StringArrayVar(&unitRunFunctionArgs.args, "arg", nil, "argument of the function as <parameter-name>=<value>; may be repeated")
+	unitRunFunctionCmd.Flags().StringArrayVar(&unitRunFunctionArgs.functions, "function", nil, "function to run, followed by its arguments, such as \"set-replicas 3\"; may be repeated to chain functions")
+	unitRunFunctionCmd.Flags().BoolVar(&unitRunFunctionArgs.dryRun, "dry-run", false, "run the functions but skip updating the configuration data")
+	unitRunFunctionCmd.Flags().StringVar(&unitRunFunctionArgs.changeDesc, "change-desc", "", "change description")
+	enableQuietFlag(unitRunFunctionCmd)
+	enableJsonFlag(unitRunFunctionCmd)
+	enableJqFlag(unitRunFunctionCmd)
+	unitCmd.AddCommand(unitRunFunctionCmd)
+}
+
+// buildUnitRunFunctionInvocations returns the function invocations specified by the optional
+// function name argument with its --arg arguments followed by the --function flags.
+func buildUnitRunFunctionInvocations(functionName string, args, functions []string) ([]goclientnew.FunctionInvocation, error) {
+	invocations := []goclientnew.FunctionInvocation{}
+	if functionName != "" {
+		namedArgs := make([]string, 0, len(args))
+		for _, arg := range args {
+			name, value, found := strings.Cut(arg, "=")
+			if !found || name == "" {
+				return nil, newAppError(ExitValidationError, fmt.Errorf("invalid argument %q; expected <parameter-name>=<value>", arg))
+			}
+			namedArgs = append(namedArgs, "--"+name+"="+value)
+		}
+		invocations = append(invocations, *initializeFunctionInvocation(functionName, namedArgs))
+	} else if len(args) > 0 {
+		return nil, newAppError(ExitValidationError, errors.New("--arg requires a function name argument"))
+	}
+	for _, function := range functions {
+		words, err := shlex.Split(function)
+		if err != nil {
+			return nil, newAppError(ExitValidationError, fmt.Errorf("invalid function %q: %w", function, err))
+		}
+		if len(words) == 0 {
+			return nil, newAppError(ExitValidationError, errors.New("--function must not be empty"))
+		}
+		invocations = append(invocations, *initializeFunctionInvocation(words[0], words[1:]))
+	}
+	if len(invocations) == 0 {
+		return nil, newAppError(ExitValidationError, errors.New("a function name argument or --function is required"))
+	}
+	return invocations, nil
+}
+
+func apiRunFunctionsOnUnit(unitSlug string, invocations []goclientnew.FunctionInvocation) ([]goclientnew.FunctionInvocationsResponse, error) {
+	unitWhere, err := buildWhereClauseFromUnits([]string{unitSlug})
+	if err != nil {
+		return nil, err
+	}
+	body := goclientnew.FunctionInvocationsRequest{
+		CastStringArgsToScalars: true,
+		StopOnError:             true,
+		ChangeDescription:       unitRunFunctionArgs.changeDesc,
+		FunctionInvocations:     &invocations,
+	}
+	params := &goclientnew.InvokeFunctionsParams{Where: &unitWhere}
+	if unitRunFunctionArgs.dryRun {
+		dryRunStr := "true"
+		params.DryRun = &dryRunStr
+	}
+	funcRes, err := cubClientNew.InvokeFunctionsWithResponse(ctx, uuid.MustParse(selectedSpaceID), params, body)
+	if IsAPIError(err, funcRes) {
+		return nil, InterpretErrorGeneric(err, funcRes)
+	}
+	var resp []goclientnew.FunctionInvocationsResponse
+	if funcRes.JSON200 != nil {
+		resp = *funcRes.JSON200
+	} else if funcRes.JSON207 != nil {
+		resp = *funcRes.JSON207
+	}
+	if len(resp) == 0 {
+		return nil, newAppError(ExitNotFound, fmt.Errorf("unit %s not found", unitSlug))
+	}
+	return resp, nil
+}
+
+func unitRunFunctionCmdRun(_ *cobra.Command, args []string) error {
+	if err := validateSpaceFlag(false); err != nil {
+		return err
+	}
+	functionName := ""
+	if len(args) > 1 {
+		functionName = args[1]
+	}
+	invocations, err := buildUnitRunFunctionInvocations(functionName, unitRunFunctionArgs.args, unitRunFunctionArgs.functions)
+	if err != nil {
+		return err
+	}
+	resp, err := apiRunFunctionsOnUnit(args[0], invocations)
+	if err != nil {
+		return err
+	}
+
+	hasAlternativeOutput := jsonOutput || jq != ""
+	if !hasAlternativeOutput {
+		outputFunctionInvocationResponse(&resp)
+	}
+	if jsonOutput {
+		displayJSON(resp)
+	}
+	if jq != "" {
+		displayJQ(resp)
+	}
+	return functionResponseError(&resp[0])
+}
@@ -0,0 +1,94 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+func TestBuildUnitRunFunctionInvocations(t *testing.T) {
+	invocations, err := buildUnitRunFunctionInvocations("set-replicas", []string{"replicas=3"}, []string{`set-env main "GREETING=hello world"`, "no-placeholders"})
+	require.NoError(t, err)
+	require.Len(t, invocations, 3)
+	assert.Equal(t, "set-replicas", invocations[0].FunctionName)
+	require.Len(t, invocations[0].Arguments, 1)
+	assert.Equal(t, "replicas", *invocations[0].Arguments[0].ParameterName)
+	value, err := invocations[0].Arguments[0].Value.AsFunctionArgumentValue0()
+	require.NoError(t, err)
+	assert.Equal(t, "3", value)
+
+	assert.Equal(t, "set-env", invocations[1].FunctionName)
+	require.Len(t, invocations[1].Arguments, 2)
+	assert.Nil(t, invocations[1].Arguments[1].ParameterName)
+	value, err = invocations[1].Arguments[1].Value.AsFunctionArgumentValue0()
+	require.NoError(t, err)
+	assert.Equal(t, "GREETING=hello world", value)
+	assert.Equal(t, "no-placeholders", invocations[2].FunctionName)
+	assert.Empty(t, invocations[2].Arguments)
+
+	_, err = buildUnitRunFunctionInvocations("", nil, nil)
+	assert.Error(t, err)
+	_, err = buildUnitRunFunctionInvocations("", []string{"replicas=3"}, []string{"no-placeholders"})
+	assert.EqualError(t, err, "--arg requires a function name argument")
+	_, err = buildUnitRunFunctionInvocations("set-replicas", []string{"3"}, nil)
+	assert.Equal(t, ExitValidationError, exitCode(err))
+}
+
+func TestUnitRunFunctionReturnsMutatedUnit(t *testing.T) {
+	spaceID := uuid.New()
+	unitID := uuid.New()
+	mutatedData := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 3\n"
+	var request goclientnew.FunctionInvocationsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/space/"+spaceID.String()+"/function/invoke", r.URL.Path)
+		assert.Equal(t, "Slug IN ('web')", r.URL.Query().Get("where"))
+		assert.Equal(t, "true", r.URL.Query().Get("dry_run"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode([]goclientnew.FunctionInvocationsResponse{{
+			Success:    true,
+			SpaceID:    spaceID,
+			UnitID:     unitID,
+			ConfigData: base64.StdEncoding.EncodeToString([]byte(mutatedData)),
+			Mutators:   []int{0},
+		}}))
+	}))
+	defer server.Close()
+
+	savedClient, savedSpaceID, savedDryRun := cubClientNew, selectedSpaceID, unitRunFunctionArgs.dryRun
+	t.Cleanup(func() {
+		cubClientNew, selectedSpaceID, unitRunFunctionArgs.dryRun = savedClient, savedSpaceID, savedDryRun
+	})
+	var err error
+	cubClientNew, err = goclientnew.NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+	selectedSpaceID = spaceID.String()
+	unitRunFunctionArgs.dryRun = true
+
+	invocations, err := buildUnitRunFunctionInvocations("set-replicas", []string{"replicas=3"}, nil)
+	require.NoError(t, err)
+	resp, err := apiRunFunctionsOnUnit("web", invocations)
+	require.NoError(t, err)
+	require.NotNil(t, request.FunctionInvocations)
+	assert.Equal(t, "set-replicas", (*request.FunctionInvocations)[0].FunctionName)
+	assert.True(t, request.StopOnError)
+	require.Len(t, resp, 1)
+	assert.Equal(t, unitID, resp[0].UnitID)
+	assert.NoError(t, functionResponseError(&resp[0]))
+
+	out := captureStdout(t, func() {
+		outputFunctionInvocationResponse(&resp)
+	})
+	assert.Contains(t, out, "replicas: 3")
+}
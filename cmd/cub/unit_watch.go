@@ -0,0 +1,183 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+var unitWatchCmd = &cobra.Command{
+	Use:   "watch [<name or id>]",
+	Short: "Watch units for changes",
+	Args:  cobra.MaximumNArgs(1),
+	Long:  getUnitWatchHelp(),
+	RunE:  unitWatchCmdRun,
+}
+
+func getUnitWatchHelp() string {
+	baseHelp := `Watch a unit, or all units in a space, and print each revision or status change as it is observed.
+The units are polled at the specified interval until interrupted with Ctrl-C.
+
+Examples:
+  # Watch a single unit
+  cub unit watch --space my-space my-deployment
+
+  # Watch all units in a space with a specific label
+  cub unit watch --space my-space --where "Labels.tier = 'Backend'"
+
+  # Poll every 2 seconds and emit a stream of JSON objects
+  cub unit watch --space my-space --interval 2s --json my-deployment`
+
+	agentContext := `Useful for observing apply progress without repeatedly running 'unit get'.
+
+Each update includes the head, live, and last applied revision numbers and the unit status.
+With --json, each update is printed as a single-line JSON object containing the extended unit.`
+
+	return getCommandHelp(baseHelp, agentContext)
+}
+
+var watchInterval time.Duration
+
+func init() {
+	enableWhereFlag(unitWatchCmd)
+	enableContainsFlag(unitWatchCmd)
+	enableJsonFlag(unitWatchCmd)
+	unitWatchCmd.Flags().DurationVar(&watchInterval, "interval", 5*time.Second, "polling interval as a duration with units, such as 10s or 2m")
+	unitCmd.AddCommand(unitWatchCmd)
+}
+
+func unitWatchCmdRun(cmd *cobra.Command, args []string) error {
+	if watchInterval <= 0 {
+		return errors.New("interval must be positive")
+	}
+	whereFilter := where
+	if len(args) == 1 {
+		unitQuery, err := buildWhereClauseFromIdentifiers(args, "UnitID", "Slug")
+		if err != nil {
+			return err
+		}
+		if whereFilter != "" {
+			whereFilter += " AND " + unitQuery
+		} else {
+			whereFilter = unitQuery
+		}
+	}
+
+	watchCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fetch := func(fetchCtx context.Context) ([]*goclientnew.ExtendedUnit, error) {
+		return apiListExtendedUnitsWithContext(fetchCtx, selectedSpaceID, whereFilter, "*")
+	}
+	return watchUnits(watchCtx, os.Stdout, fetch, watchInterval)
+}
+
+// unitWatchState contains the unit attributes that are reported when they change.
+type unitWatchState struct {
+	HeadRevisionNum        int64
+	LiveRevisionNum        int64
+	LastAppliedRevisionNum int64
+	Status                 string
+	Action                 string
+	Drift                  string
+}
+
+func getUnitWatchState(extendedUnit *goclientnew.ExtendedUnit) unitWatchState {
+	state := unitWatchState{
+		HeadRevisionNum:        extendedUnit.Unit.HeadRevisionNum,
+		LiveRevisionNum:        extendedUnit.Unit.LiveRevisionNum,
+		LastAppliedRevisionNum: extendedUnit.Unit.LastAppliedRevisionNum,
+	}
+	if extendedUnit.UnitStatus != nil {
+		state.Status = extendedUnit.UnitStatus.Status
+		state.Drift = extendedUnit.UnitStatus.Drift
+		if extendedUnit.UnitStatus.Action != nil {
+			state.Action = string(*extendedUnit.UnitStatus.Action)
+		}
+	}
+	return state
+}
+
+// watchUnits polls for units until the context is canceled, writing each unit whose state
+// differs from the previous poll to w.
+func watchUnits(
+	watchCtx context.Context,
+	w io.Writer,
+	fetch func(context.Context) ([]*goclientnew.ExtendedUnit, error),
+	interval time.Duration,
+) error {
+	lastStates := map[uuid.UUID]unitWatchState{}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		extendedUnits, err := fetch(watchCtx)
+		if err != nil {
+			// Don't report cancellation due to Ctrl-C as a failure
+			if watchCtx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		extendedUnits = slices.DeleteFunc(extendedUnits, func(extendedUnit *goclientnew.ExtendedUnit) bool {
+			return extendedUnit == nil || extendedUnit.Unit == nil
+		})
+		sort.Slice(extendedUnits, func(i, j int) bool {
+			return extendedUnits[i].Unit.Slug < extendedUnits[j].Unit.Slug
+		})
+		for _, extendedUnit := range extendedUnits {
+			state := getUnitWatchState(extendedUnit)
+			lastState, seen := lastStates[extendedUnit.Unit.UnitID]
+			if seen && lastState == state {
+				continue
+			}
+			lastStates[extendedUnit.Unit.UnitID] = state
+			if err := displayUnitWatchUpdate(w, extendedUnit, state); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-watchCtx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func displayUnitWatchUpdate(w io.Writer, extendedUnit *goclientnew.ExtendedUnit, state unitWatchState) error {
+	if jsonOutput {
+		outBytes, err := json.Marshal(extendedUnit)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(outBytes))
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s %s head=%d live=%d applied=%d status=%s action=%s drift=%s\n",
+		time.Now().Format(time.RFC3339),
+		extendedUnit.Unit.Slug,
+		state.HeadRevisionNum,
+		state.LiveRevisionNum,
+		state.LastAppliedRevisionNum,
+		state.Status,
+		state.Action,
+		state.Drift,
+	)
+	return err
+}
@@ -0,0 +1,85 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+func TestUnitWatchPrintsEachRevision(t *testing.T) {
+	spaceID := uuid.New()
+	unitID := uuid.New()
+	watchCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/space/"+spaceID.String()+"/unit", r.URL.Path)
+		mu.Lock()
+		polls++
+		revision := int64(polls)
+		if revision > 2 {
+			revision = 2
+			// Both revisions have been emitted
+			cancel()
+		}
+		mu.Unlock()
+		units := []goclientnew.ExtendedUnit{
+			// Entries without units are skipped
+			{},
+			{
+				Unit: &goclientnew.Unit{
+					UnitID:          unitID,
+					SpaceID:         spaceID,
+					Slug:            "my-deployment",
+					HeadRevisionNum: revision,
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(units))
+	}))
+	defer server.Close()
+
+	previousClient, previousSpaceID := cubClientNew, selectedSpaceID
+	t.Cleanup(func() {
+		cubClientNew, selectedSpaceID = previousClient, previousSpaceID
+	})
+	var err error
+	cubClientNew, err = goclientnew.NewClientWithResponses(server.URL)
+	assert.NoError(t, err)
+	selectedSpaceID = spaceID.String()
+
+	var out bytes.Buffer
+	fetch := func(fetchCtx context.Context) ([]*goclientnew.ExtendedUnit, error) {
+		return apiListExtendedUnitsWithContext(fetchCtx, selectedSpaceID, "Slug = 'my-deployment'", "*")
+	}
+	err = watchUnits(watchCtx, &out, fetch, 10*time.Millisecond)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "my-deployment head=1 ")
+	assert.Contains(t, lines[1], "my-deployment head=2 ")
+}
+
+func TestUnitWatchQueryEscapesQuotes(t *testing.T) {
+	query, err := buildWhereClauseFromIdentifiers([]string{"it's"}, "UnitID", "Slug")
+	assert.NoError(t, err)
+	assert.Equal(t, "Slug IN ('it''s')", query)
+}
@@ -0,0 +1,18 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validation commands",
+	Long:  `The validate subcommands validate configuration data`,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,251 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/labstack/gommon/log"
+	"github.com/spf13/cobra"
+
+	"github.com/confighub/sdk/bridge-worker/impl"
+	"github.com/confighub/sdk/function"
+	"github.com/confighub/sdk/function/api"
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+	"github.com/confighub/sdk/workerapi"
+)
+
+var validateOfflineCmd = &cobra.Command{
+	Use:   "offline",
+	Short: "Validate a configuration file locally",
+	Long: `Validate a configuration file locally by running a function on it, without connecting to ConfigHub.
+The function is executed in-process, so no server, worker, or authentication is needed, which makes
+this suitable for pre-commit checks. Only hermetic functions, which don't depend on anything other
+than the configuration data and arguments, are supported. The Kubernetes validate function downloads
+the JSON schemas of the resource types, so it requires network access to GitHub.
+
+The exit code is 0 if the function succeeds and all validations pass, 4 if a validation fails, and 1
+for other errors.
+
+Examples:
+  # Check that no placeholders remain
+  cub validate offline --file deployment.yaml
+
+  # Validate Kubernetes resource names
+  cub validate offline --file deployment.yaml --function validate-resource-names
+
+  # Validate with a CEL expression
+  cub validate offline --file deployment.yaml --function cel-validate --arg validation-expr='r.kind != "Deployment" || r.spec.replicas > 1'
+
+  # Validate an AppConfig properties file
+  cub validate offline --toolchain AppConfig/Properties --file app.properties --function no-placeholders`,
+	Args:              cobra.ExactArgs(0),
+	PersistentPreRunE: validateOfflinePreRunE,
+	RunE:              validateOfflineCmdRun,
+}
+
+var validateOfflineArgs struct {
+	toolchainType string
+	filename      string
+	functionName  string
+	args          []string
+}
+
+func init() {
+	validateOfflineCmd.Flags().StringVar(&validateOfflineArgs.toolchainType, "toolchain", string(workerapi.ToolchainKubernetesYAML), "Toolchain type of the configuration file")
+	validateOfflineCmd.Flags().StringVar(&validateOfflineArgs.filename, "file", "", "Configuration file to validate, or - for stdin")
+	validateOfflineCmd.Flags().StringVar(&validateOfflineArgs.functionName, "function", "no-placeholders", "Function to run")
+	validateOfflineCmd.Flags().StringArrayVar(&validateOfflineArgs.args, "arg", nil, "Function argument as <parameter-name>=<value>; may be repeated")
+	_ = validateOfflineCmd.MarkFlagRequired("file")
+	enableQuietFlag(validateOfflineCmd)
+	enableJsonFlag(validateOfflineCmd)
+	enableJqFlag(validateOfflineCmd)
+	validateCmd.AddCommand(validateOfflineCmd)
+}
+
+// validateOfflinePreRunE replaces globalPreRun so that no session or client is needed.
+func validateOfflinePreRunE(cmd *cobra.Command, args []string) error {
+	profileFlags, err := applyProfileFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if err := applyConfigFileFlags(cmd, profileFlags); err != nil {
+		return err
+	}
+	if !debug && os.Getenv("CONFIGHUB_DEBUG") != "1" {
+		// Silence the function handler's per-invocation logging
+		log.SetLevel(log.OFF)
+	}
+	return nil
+}
+
+// offlineFunctionExecutor runs functions of a toolchain in-process.
+type offlineFunctionExecutor struct {
+	signatures map[string]api.FunctionSignature
+	invoke     func(ctx context.Context, request *api.FunctionInvocationRequest) (*api.FunctionInvocationResponse, error)
+}
+
+type offlineFunctionWorkerContext struct {
+	ctx context.Context
+}
+
+func (c *offlineFunctionWorkerContext) Context() context.Context {
+	return c.ctx
+}
+
+// offlineFunctionExecutors caches the executors, since function registration updates global path registries
+var offlineFunctionExecutors = map[workerapi.ToolchainType]*offlineFunctionExecutor{}
+
+func getOfflineFunctionExecutor(toolchainType workerapi.ToolchainType) (*offlineFunctionExecutor, error) {
+	if executor, ok := offlineFunctionExecutors[toolchainType]; ok {
+		return executor, nil
+	}
+	executor, err := newOfflineFunctionExecutor(toolchainType)
+	if err != nil {
+		return nil, err
+	}
+	offlineFunctionExecutors[toolchainType] = executor
+	return executor, nil
+}
+
+func newOfflineFunctionExecutor(toolchainType workerapi.ToolchainType) (*offlineFunctionExecutor, error) {
+	if toolchainType == workerapi.ToolchainKubernetesYAML {
+		// The Kubernetes function worker registers custom functions and CEL extensions in
+		// addition to the standard functions
+		worker := impl.NewKubernetesFunctionWorker()
+		return &offlineFunctionExecutor{
+			signatures: worker.Info().SupportedFunctions[toolchainType],
+			invoke: func(ctx context.Context, request *api.FunctionInvocationRequest) (*api.FunctionInvocationResponse, error) {
+				response, err := worker.Invoke(&offlineFunctionWorkerContext{ctx: ctx}, *request)
+				return &response, err
+			},
+		}, nil
+	}
+	executor := function.NewStandardExecutor()
+	signatures, ok := executor.RegisteredFunctions()[toolchainType]
+	if !ok {
+		return nil, newAppError(ExitValidationError, fmt.Errorf("unsupported toolchain %s", toolchainType))
+	}
+	return &offlineFunctionExecutor{signatures: signatures, invoke: executor.Invoke}, nil
+}
+
+// parseOfflineFunctionArguments converts <parameter-name>=<value> arguments to named function arguments.
+func parseOfflineFunctionArguments(args []string) ([]api.FunctionArgument, error) {
+	functionArgs := make([]api.FunctionArgument, 0, len(args))
+	for _, arg := range args {
+		name, value, found := strings.Cut(arg, "=")
+		if !found || name == "" {
+			return nil, newAppError(ExitValidationError, fmt.Errorf("invalid argument %q; expected <parameter-name>=<value>", arg))
+		}
+		functionArgs = append(functionArgs, api.FunctionArgument{ParameterName: name, Value: value})
+	}
+	return functionArgs, nil
+}
+
+// runOfflineFunction runs the function on the configuration data and returns the response in the
+// form returned by the server.
+func runOfflineFunction(toolchainType workerapi.ToolchainType, data []byte, functionName string, args []string) (*goclientnew.FunctionInvocationsResponse, error) {
+	executor, err := getOfflineFunctionExecutor(toolchainType)
+	if err != nil {
+		return nil, err
+	}
+	signature, ok := executor.signatures[functionName]
+	if !ok {
+		return nil, newAppError(ExitValidationError, fmt.Errorf("function %s not found for toolchain %s", functionName, toolchainType))
+	}
+	if !signature.Hermetic {
+		return nil, newAppError(ExitValidationError, fmt.Errorf("function %s is not hermetic and is not supported offline", functionName))
+	}
+	functionArgs, err := parseOfflineFunctionArguments(args)
+	if err != nil {
+		return nil, err
+	}
+	request := &api.FunctionInvocationRequest{
+		FunctionContext: api.FunctionContext{
+			ToolchainType:   toolchainType,
+			UnitDisplayName: validateOfflineArgs.filename,
+		},
+		ConfigData:              data,
+		CastStringArgsToScalars: true,
+		FunctionInvocations: api.FunctionInvocationList{
+			{FunctionName: functionName, Arguments: functionArgs},
+		},
+	}
+	response, err := executor.invoke(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the response to the client type so that it's displayed like the server's responses
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	var clientResponse goclientnew.FunctionInvocationsResponse
+	if err := json.Unmarshal(responseBytes, &clientResponse); err != nil {
+		return nil, err
+	}
+	if !response.Success && len(response.ErrorMessages) > 0 {
+		clientResponse.Error = &goclientnew.ResponseError{
+			Message: strings.Join(response.ErrorMessages, "; "),
+		}
+	}
+	return &clientResponse, nil
+}
+
+// functionResponseError returns an error if a function failed or any validation failed.
+func functionResponseError(response *goclientnew.FunctionInvocationsResponse) error {
+	if !response.Success {
+		// The error details were displayed with the response
+		return errors.New("function failed")
+	}
+	if response.OutputType != string(api.OutputTypeValidationResult) && response.OutputType != string(api.OutputTypeValidationResultList) {
+		return nil
+	}
+	output, err := base64.StdEncoding.DecodeString(response.Output)
+	if err != nil {
+		return err
+	}
+	var results api.ValidationResultList
+	if err := json.Unmarshal(output, &results); err != nil {
+		var result api.ValidationResult
+		if err := json.Unmarshal(output, &result); err != nil {
+			return err
+		}
+		results = api.ValidationResultList{result}
+	}
+	for _, result := range results {
+		if !result.Passed {
+			return newAppError(ExitValidationError, errors.New("validation failed"))
+		}
+	}
+	return nil
+}
+
+func validateOfflineCmdRun(cmd *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+	if validateOfflineArgs.filename == "-" {
+		data, err = readStdin()
+	} else {
+		data, err = os.ReadFile(validateOfflineArgs.filename)
+	}
+	if err != nil {
+		return err
+	}
+	response, err := runOfflineFunction(workerapi.ToolchainType(validateOfflineArgs.toolchainType), data, validateOfflineArgs.functionName, validateOfflineArgs.args)
+	if err != nil {
+		return err
+	}
+	displayGetResults(response, func(response *goclientnew.FunctionInvocationsResponse) {
+		outputFunctionInvocationResponse(&[]goclientnew.FunctionInvocationsResponse{*response})
+	})
+	return functionResponseError(response)
+}
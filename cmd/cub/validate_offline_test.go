@@ -0,0 +1,61 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/workerapi"
+)
+
+const offlineTestDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.27
+`
+
+func TestRunOfflineFunction(t *testing.T) {
+	response, err := runOfflineFunction(workerapi.ToolchainKubernetesYAML, []byte(offlineTestDeployment), "no-placeholders", nil)
+	require.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.NoError(t, functionResponseError(response))
+
+	response, err = runOfflineFunction(workerapi.ToolchainKubernetesYAML, []byte(offlineTestDeployment), "cel-validate", []string{"validation-expr=r.spec.replicas > 1"})
+	require.NoError(t, err)
+	assert.True(t, response.Success)
+	err = functionResponseError(response)
+	assert.EqualError(t, err, "validation failed")
+	assert.Equal(t, ExitValidationError, exitCode(err))
+
+	response, err = runOfflineFunction(workerapi.ToolchainAppConfigProperties, []byte("url=confighubplaceholder\n"), "no-placeholders", nil)
+	require.NoError(t, err)
+	assert.Equal(t, ExitValidationError, exitCode(functionResponseError(response)))
+
+	// Function errors are reported in the response
+	response, err = runOfflineFunction(workerapi.ToolchainKubernetesYAML, []byte(offlineTestDeployment), "no-placeholders", []string{"extra=1"})
+	require.NoError(t, err)
+	assert.False(t, response.Success)
+	require.NotNil(t, response.Error)
+	assert.Contains(t, response.Error.Message, "too many arguments")
+	assert.Equal(t, ExitGenericError, exitCode(functionResponseError(response)))
+}
+
+func TestRunOfflineFunctionErrors(t *testing.T) {
+	_, err := runOfflineFunction(workerapi.ToolchainKubernetesYAML, []byte(offlineTestDeployment), "no-such-function", nil)
+	assert.EqualError(t, err, "function no-such-function not found for toolchain Kubernetes/YAML")
+	_, err = runOfflineFunction("Unknown/Toolchain", nil, "no-placeholders", nil)
+	assert.EqualError(t, err, "unsupported toolchain Unknown/Toolchain")
+	_, err = runOfflineFunction(workerapi.ToolchainKubernetesYAML, []byte(offlineTestDeployment), "cel-validate", []string{"=r.kind == 'Deployment'"})
+	assert.ErrorContains(t, err, "expected <parameter-name>=<value>")
+}
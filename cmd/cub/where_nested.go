@@ -0,0 +1,274 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// nestedWhereCondition is a --where condition on a nested path, such as
+// "Annotations.config.owner = 'alice'", which drills into the JSON stored in an annotation, or a
+// path containing a * wildcard, such as "Labels.* = 'backend'". Path segments use the same syntax
+// as function paths: segments are separated by dots and dots within a segment are escaped as ~1.
+type nestedWhereCondition struct {
+	Path     []string
+	Operator string
+	Value    string
+}
+
+var whereConditionRegexp = regexp.MustCompile(`^([A-Za-z](?:[\w.*/-]|~1)*)\s*(!=|=|!~|~|(?i:ILIKE|LIKE)\s)\s*(.*)$`)
+var whereConjunctionRegexp = regexp.MustCompile(`(?i)\s+AND\s+`)
+
+// splitWhereConjuncts splits a where expression at ANDs that are not within quotes.
+func splitWhereConjuncts(where string) []string {
+	conjuncts := []string{}
+	start := 0
+	inQuotes := false
+	for i := 0; i < len(where); i++ {
+		if where[i] == '\'' {
+			inQuotes = !inQuotes
+			continue
+		}
+		if inQuotes {
+			continue
+		}
+		loc := whereConjunctionRegexp.FindStringIndex(where[i:])
+		if loc == nil {
+			break
+		}
+		if loc[0] != 0 {
+			continue
+		}
+		conjuncts = append(conjuncts, strings.TrimSpace(where[start:i]))
+		start = i + loc[1]
+		i = start - 1
+	}
+	return append(conjuncts, strings.TrimSpace(where[start:]))
+}
+
+// isNestedWherePath returns true for paths the server may not support: paths more than one
+// level below an entity field and paths with wildcards.
+func isNestedWherePath(path []string) bool {
+	if len(path) > 2 {
+		return true
+	}
+	for _, segment := range path {
+		if segment == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// splitNestedWhere separates the conditions on nested paths from a where expression. It returns
+// the expression with the nested conditions removed and the nested conditions.
+func splitNestedWhere(where string) (string, []nestedWhereCondition) {
+	if strings.TrimSpace(where) == "" {
+		return where, nil
+	}
+	serverConjuncts := []string{}
+	conditions := []nestedWhereCondition{}
+	for _, conjunct := range splitWhereConjuncts(where) {
+		condition, ok := parseWhereCondition(conjunct)
+		if !ok || !isNestedWherePath(condition.Path) {
+			serverConjuncts = append(serverConjuncts, conjunct)
+			continue
+		}
+		conditions = append(conditions, condition)
+	}
+	return strings.Join(serverConjuncts, " AND "), conditions
+}
+
+// parseWhereCondition parses a single condition of a where expression, returning false if it
+// isn't of a form that can be evaluated client-side.
+func parseWhereCondition(conjunct string) (nestedWhereCondition, bool) {
+	matches := whereConditionRegexp.FindStringSubmatch(conjunct)
+	if matches == nil {
+		return nestedWhereCondition{}, false
+	}
+	path := strings.Split(matches[1], ".")
+	for i := range path {
+		path[i] = strings.ReplaceAll(path[i], "~1", ".")
+	}
+	value := strings.TrimSpace(matches[3])
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return nestedWhereCondition{
+		Path:     path,
+		Operator: strings.ToUpper(strings.TrimSpace(matches[2])),
+		Value:    value,
+	}, true
+}
+
+// parseWhereConditions parses all of the conditions of a where expression for entities that are
+// only filtered client-side.
+func parseWhereConditions(where string) ([]nestedWhereCondition, error) {
+	if strings.TrimSpace(where) == "" {
+		return nil, nil
+	}
+	conditions := []nestedWhereCondition{}
+	for _, conjunct := range splitWhereConjuncts(where) {
+		condition, ok := parseWhereCondition(conjunct)
+		if !ok {
+			return nil, newAppError(ExitValidationError, fmt.Errorf("unsupported where condition %q: only =, !=, ~, !~, LIKE, and ILIKE are supported", conjunct))
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// nestedValues returns the values at path within value. JSON stored in strings is decoded in order
+// to continue traversing the path.
+func nestedValues(value any, path []string) []any {
+	if len(path) == 0 {
+		return []any{value}
+	}
+	if s, ok := value.(string); ok {
+		var decoded any
+		if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+			return nil
+		}
+		value = decoded
+	}
+	segment := path[0]
+	values := []any{}
+	switch v := value.(type) {
+	case map[string]any:
+		if segment == "*" {
+			for _, child := range v {
+				values = append(values, nestedValues(child, path[1:])...)
+			}
+		} else if child, ok := v[segment]; ok {
+			values = append(values, nestedValues(child, path[1:])...)
+		}
+	case []any:
+		for _, child := range v {
+			if segment == "*" {
+				values = append(values, nestedValues(child, path[1:])...)
+			}
+		}
+	}
+	return values
+}
+
+// likePatternToRegexp converts a SQL LIKE pattern to an anchored regular expression.
+func likePatternToRegexp(pattern string, caseInsensitive bool) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	if caseInsensitive {
+		sb.WriteString("(?i)")
+	}
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// matches returns true if any value at the condition's path satisfies the condition. The negated
+// operators != and !~ are satisfied if no value matches.
+func (c *nestedWhereCondition) matches(object any) (bool, error) {
+	var match func(string) bool
+	negated := false
+	switch c.Operator {
+	case "!=":
+		negated = true
+		fallthrough
+	case "=":
+		match = func(s string) bool { return s == c.Value }
+	case "!~":
+		negated = true
+		fallthrough
+	case "~":
+		re, err := regexp.Compile(c.Value)
+		if err != nil {
+			return false, fmt.Errorf("invalid regular expression %s: %w", c.Value, err)
+		}
+		match = re.MatchString
+	case "LIKE", "ILIKE":
+		re, err := likePatternToRegexp(c.Value, c.Operator == "ILIKE")
+		if err != nil {
+			return false, err
+		}
+		match = re.MatchString
+	default:
+		return false, fmt.Errorf("operator %s is not supported for nested path %s", c.Operator, strings.Join(c.Path, "."))
+	}
+	found := false
+	for _, value := range nestedValues(object, c.Path) {
+		s, ok := value.(string)
+		if !ok {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+			s = string(encoded)
+		}
+		if match(s) {
+			found = true
+			break
+		}
+	}
+	return found != negated, nil
+}
+
+// filterByNestedWhere returns the entities satisfying all of the conditions. getObject returns the
+// object the paths are relative to, such as the Unit of an ExtendedUnit.
+func filterByNestedWhere[Entity any](entities []Entity, conditions []nestedWhereCondition, getObject func(Entity) any) ([]Entity, error) {
+	filtered := make([]Entity, 0, len(entities))
+	for _, entity := range entities {
+		encoded, err := json.Marshal(getObject(entity))
+		if err != nil {
+			return nil, err
+		}
+		var object any
+		if err := json.Unmarshal(encoded, &object); err != nil {
+			return nil, err
+		}
+		matched := true
+		for i := range conditions {
+			matched, err = conditions[i].matches(object)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				break
+			}
+		}
+		if matched {
+			filtered = append(filtered, entity)
+		}
+	}
+	return filtered, nil
+}
+
+// listWithNestedWhere lists entities with the where filter. If the server rejects the filter and
+// it contains conditions on nested paths, the entities are listed without those conditions and
+// then filtered client-side.
+func listWithNestedWhere[Entity any](whereFilter string, list func(whereFilter string) ([]Entity, error), getObject func(Entity) any) ([]Entity, error) {
+	entities, err := list(whereFilter)
+	if err == nil || exitCode(err) != ExitValidationError {
+		return entities, err
+	}
+	serverWhere, conditions := splitNestedWhere(whereFilter)
+	if len(conditions) == 0 {
+		return nil, err
+	}
+	entities, err = list(serverWhere)
+	if err != nil {
+		return nil, err
+	}
+	return filterByNestedWhere(entities, conditions, getObject)
+}
@@ -0,0 +1,78 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+func TestSplitNestedWhere(t *testing.T) {
+	serverWhere, conditions := splitNestedWhere("Slug LIKE 'app-%' AND Annotations.config.owner = 'o''brien' and Labels.* != 'x AND y' AND Labels.tier = 'web'")
+	assert.Equal(t, "Slug LIKE 'app-%' AND Labels.tier = 'web'", serverWhere)
+	assert.Equal(t, []nestedWhereCondition{
+		{Path: []string{"Annotations", "config", "owner"}, Operator: "=", Value: "o'brien"},
+		{Path: []string{"Labels", "*"}, Operator: "!=", Value: "x AND y"},
+	}, conditions)
+
+	serverWhere, conditions = splitNestedWhere("Annotations.example~1com/config.owner ILIKE 'A%'")
+	assert.Equal(t, "", serverWhere)
+	assert.Equal(t, []nestedWhereCondition{
+		{Path: []string{"Annotations", "example.com/config", "owner"}, Operator: "ILIKE", Value: "A%"},
+	}, conditions)
+}
+
+func testNestedWhereUnits() []*goclientnew.ExtendedUnit {
+	return []*goclientnew.ExtendedUnit{
+		{Unit: &goclientnew.Unit{Slug: "web", Labels: map[string]string{"tier": "frontend"}, Annotations: map[string]string{"config": `{"owner":"alice","teams":["web","ops"]}`}}},
+		{Unit: &goclientnew.Unit{Slug: "db", Labels: map[string]string{"tier": "backend"}, Annotations: map[string]string{"config": `{"owner":"bob","teams":["data"]}`}}},
+		{Unit: &goclientnew.Unit{Slug: "cache", Labels: map[string]string{"role": "backend"}}},
+	}
+}
+
+func nestedWhereSlugs(t *testing.T, where string) []string {
+	_, conditions := splitNestedWhere(where)
+	filtered, err := filterByNestedWhere(testNestedWhereUnits(), conditions, func(u *goclientnew.ExtendedUnit) any { return u.Unit })
+	require.NoError(t, err)
+	slugs := []string{}
+	for _, u := range filtered {
+		slugs = append(slugs, u.Unit.Slug)
+	}
+	return slugs
+}
+
+func TestFilterByNestedWhere(t *testing.T) {
+	assert.Equal(t, []string{"web"}, nestedWhereSlugs(t, "Annotations.config.owner = 'alice'"))
+	assert.Equal(t, []string{"db", "cache"}, nestedWhereSlugs(t, "Annotations.config.owner != 'alice'"))
+	assert.Equal(t, []string{"web"}, nestedWhereSlugs(t, "Annotations.config.teams.* = 'ops'"))
+	assert.Equal(t, []string{"db", "cache"}, nestedWhereSlugs(t, "Labels.* = 'backend'"))
+	assert.Equal(t, []string{"db"}, nestedWhereSlugs(t, "Labels.* LIKE 'back%' AND Annotations.*.owner ~ '^b'"))
+}
+
+func TestListWithNestedWhereFallback(t *testing.T) {
+	var requested []string
+	list := func(whereFilter string) ([]*goclientnew.ExtendedUnit, error) {
+		requested = append(requested, whereFilter)
+		if whereFilter != "Slug != 'cache'" {
+			return nil, newAppError(ExitValidationError, errors.New("HTTP 400: invalid where"))
+		}
+		return testNestedWhereUnits()[:2], nil
+	}
+	units, err := listWithNestedWhere("Slug != 'cache' AND Annotations.config.owner = 'bob'", list, func(u *goclientnew.ExtendedUnit) any { return u.Unit })
+	require.NoError(t, err)
+	require.Len(t, units, 1)
+	assert.Equal(t, "db", units[0].Unit.Slug)
+	assert.Equal(t, []string{"Slug != 'cache' AND Annotations.config.owner = 'bob'", "Slug != 'cache'"}, requested)
+
+	// Other errors and filters without nested conditions are not retried
+	requested = nil
+	_, err = listWithNestedWhere("Slug = 'web'", list, func(u *goclientnew.ExtendedUnit) any { return u.Unit })
+	assert.Error(t, err)
+	assert.Len(t, requested, 1)
+}
@@ -0,0 +1,176 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+var workerDiagnoseCmd = &cobra.Command{
+	Use:   "diagnose <worker-slug>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Check the connectivity and health of a worker",
+	Long: `Check the connectivity and health of a worker. The following checks are performed:
+
+  Connection   the most recent status reported for the worker isn't Disconnected
+  Condition    the condition of the worker is Ready
+  Heartbeat    the worker was seen within the heartbeat threshold
+  Worker       the checks performed by the worker itself when it connected, if it supports them
+
+A summary of the checks is displayed; use --json for the results in JSON. The command fails with
+exit code 4 if any check fails.
+
+# Diagnose a worker
+cub worker diagnose --space my-space my-worker
+
+# Allow the last heartbeat to be up to 10 minutes old
+cub worker diagnose --space my-space my-worker --heartbeat-threshold 10m`,
+	RunE: workerDiagnoseCmdRun,
+}
+
+var workerDiagnoseArgs struct {
+	heartbeatThreshold time.Duration
+}
+
+func init() {
+	enableJsonFlag(workerDiagnoseCmd)
+	workerDiagnoseCmd.Flags().DurationVar(&workerDiagnoseArgs.heartbeatThreshold, "heartbeat-threshold", 2*time.Minute, "maximum age of the last heartbeat of a healthy worker")
+	workerCmd.AddCommand(workerDiagnoseCmd)
+}
+
+const (
+	diagnosticPass = "PASS"
+	diagnosticFail = "FAIL"
+	diagnosticSkip = "SKIP"
+)
+
+type workerDiagnosticCheck struct {
+	Check   string
+	Result  string
+	Message string
+}
+
+type workerDiagnosis struct {
+	BridgeWorkerSlug string
+	Passed           bool
+	Checks           []workerDiagnosticCheck
+}
+
+func workerDiagnoseCmdRun(_ *cobra.Command, args []string) error {
+	entity, err := apiGetBridgeWorkerFromSlug(args[0], "*")
+	if err != nil {
+		return err
+	}
+	spaceID := uuid.MustParse(selectedSpaceID)
+
+	// The diagnostics reported by the worker aren't part of the generated client's WorkerInfo
+	workerRes, err := cubClientNew.GetBridgeWorkerWithResponse(ctx, spaceID, entity.BridgeWorkerID, &goclientnew.GetBridgeWorkerParams{})
+	if IsAPIError(err, workerRes) {
+		return InterpretErrorGeneric(err, workerRes)
+	}
+	var providedInfo struct {
+		BridgeWorker struct {
+			ProvidedInfo *api.WorkerInfo
+		}
+	}
+	if err := json.Unmarshal(workerRes.Body, &providedInfo); err != nil {
+		return fmt.Errorf("failed to decode worker: %w", err)
+	}
+
+	statusRes, err := cubClientNew.ListBridgeWorkerStatusesWithResponse(ctx, spaceID, entity.BridgeWorkerID)
+	if IsAPIError(err, statusRes) {
+		return InterpretErrorGeneric(err, statusRes)
+	}
+
+	diagnosis := diagnoseWorker(entity, *statusRes.JSON200, providedInfo.BridgeWorker.ProvidedInfo, workerDiagnoseArgs.heartbeatThreshold, time.Now())
+	if jsonOutput {
+		displayJSON(diagnosis)
+	} else {
+		displayWorkerDiagnosis(diagnosis)
+	}
+	if !diagnosis.Passed {
+		return newAppError(ExitValidationError, errors.New("worker diagnosis failed"))
+	}
+	return nil
+}
+
+// diagnoseWorker checks the worker's statuses, condition, and heartbeat, and includes the checks
+// reported by the worker in info, if any.
+func diagnoseWorker(worker *goclientnew.BridgeWorker, statuses []goclientnew.BridgeWorkerStatus, info *api.WorkerInfo, heartbeatThreshold time.Duration, now time.Time) workerDiagnosis {
+	diagnosis := workerDiagnosis{BridgeWorkerSlug: worker.Slug}
+	addCheck := func(check string, passed bool, message string) {
+		result := diagnosticPass
+		if !passed {
+			result = diagnosticFail
+		}
+		diagnosis.Checks = append(diagnosis.Checks, workerDiagnosticCheck{Check: check, Result: result, Message: message})
+	}
+
+	var latest *goclientnew.BridgeWorkerStatus
+	for i := range statuses {
+		if latest == nil || statuses[i].SeenAt.After(latest.SeenAt) {
+			latest = &statuses[i]
+		}
+	}
+	if latest == nil {
+		addCheck("Connection", false, "no status has been reported by the worker")
+	} else {
+		addCheck("Connection", latest.Status != "Disconnected", fmt.Sprintf("%s from %s", latest.Status, latest.IPAddress))
+	}
+
+	conditionMessage := worker.Condition
+	if worker.LastMessage != "" {
+		conditionMessage += ": " + worker.LastMessage
+	}
+	addCheck("Condition", worker.Condition == "Ready", conditionMessage)
+
+	lastSeen := worker.LastSeenAt
+	if latest != nil && latest.SeenAt.After(lastSeen) {
+		lastSeen = latest.SeenAt
+	}
+	if lastSeen.IsZero() {
+		addCheck("Heartbeat", false, "the worker has never been seen")
+	} else {
+		age := now.Sub(lastSeen).Truncate(time.Second)
+		addCheck("Heartbeat", age <= heartbeatThreshold, fmt.Sprintf("last seen %v ago; threshold %v", age, heartbeatThreshold))
+	}
+
+	if info == nil || info.Diagnostics == nil {
+		diagnosis.Checks = append(diagnosis.Checks, workerDiagnosticCheck{
+			Check:   "Worker",
+			Result:  diagnosticSkip,
+			Message: "the worker doesn't report diagnostics",
+		})
+	} else {
+		for _, check := range info.Diagnostics.Checks {
+			addCheck("Worker: "+check.Name, check.Passed, check.Message)
+		}
+	}
+
+	diagnosis.Passed = true
+	for _, check := range diagnosis.Checks {
+		if check.Result == diagnosticFail {
+			diagnosis.Passed = false
+		}
+	}
+	return diagnosis
+}
+
+func displayWorkerDiagnosis(diagnosis workerDiagnosis) {
+	table := tableView()
+	table.SetHeader([]string{"Check", "Result", "Message"})
+	for _, check := range diagnosis.Checks {
+		table.Append([]string{check.Check, check.Result, check.Message})
+	}
+	table.Render()
+}
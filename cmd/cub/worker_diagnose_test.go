@@ -0,0 +1,50 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/bridge-worker/api"
+	goclientnew "github.com/confighub/sdk/openapi/goclient-new"
+)
+
+func TestDiagnoseWorker(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	worker := &goclientnew.BridgeWorker{Slug: "my-worker", Condition: "Ready", LastSeenAt: now.Add(-3 * time.Minute)}
+	statuses := []goclientnew.BridgeWorkerStatus{
+		{Status: "Disconnected", IPAddress: "10.0.0.1", SeenAt: now.Add(-time.Hour)},
+		{Status: "Connected", IPAddress: "10.0.0.2", SeenAt: now.Add(-30 * time.Second)},
+	}
+	info := &api.WorkerInfo{Diagnostics: &api.DiagnosticResult{Checks: []api.DiagnosticCheck{
+		{Name: "kubernetes", Passed: true},
+	}}}
+
+	diagnosis := diagnoseWorker(worker, statuses, info, time.Minute, now)
+	assert.True(t, diagnosis.Passed)
+	assert.Equal(t, []workerDiagnosticCheck{
+		{Check: "Connection", Result: diagnosticPass, Message: "Connected from 10.0.0.2"},
+		{Check: "Condition", Result: diagnosticPass, Message: "Ready"},
+		{Check: "Heartbeat", Result: diagnosticPass, Message: "last seen 30s ago; threshold 1m0s"},
+		{Check: "Worker: kubernetes", Result: diagnosticPass},
+	}, diagnosis.Checks)
+
+	worker.Condition = "Unresponsive"
+	info.Diagnostics.Checks[0] = api.DiagnosticCheck{Name: "kubernetes", Message: "forbidden"}
+	diagnosis = diagnoseWorker(worker, statuses[:1], info, time.Minute, now)
+	assert.False(t, diagnosis.Passed)
+	assert.Equal(t, []string{diagnosticFail, diagnosticFail, diagnosticFail, diagnosticFail}, []string{
+		diagnosis.Checks[0].Result, diagnosis.Checks[1].Result, diagnosis.Checks[2].Result, diagnosis.Checks[3].Result,
+	})
+	assert.Equal(t, "last seen 3m0s ago; threshold 1m0s", diagnosis.Checks[2].Message)
+
+	diagnosis = diagnoseWorker(&goclientnew.BridgeWorker{Condition: "Ready"}, nil, nil, time.Minute, now)
+	assert.False(t, diagnosis.Passed)
+	assert.Equal(t, "no status has been reported by the worker", diagnosis.Checks[0].Message)
+	assert.Equal(t, "the worker has never been seen", diagnosis.Checks[2].Message)
+	assert.Equal(t, diagnosticSkip, diagnosis.Checks[3].Result)
+}
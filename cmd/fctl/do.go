@@ -129,11 +129,13 @@ func newDoCommand() *cobra.Command {
 	}
 	cmd.Flags().BoolVar(&dataOnly, "data-only", false, "show config data without other response details")
 	cmd.Flags().BoolVar(&outputOnly, "output-only", false, "show function output only")
+	cmd.Flags().BoolVar(&attributeTable, "table", false, "show AttributeValueList output as a table")
 
 	return cmd
 }
 
 var dataOnly bool
+var attributeTable bool
 var outputOnly bool
 var numFilters int
 var stop bool
@@ -215,6 +217,14 @@ func outputFunctionInvocationResponse(data []byte, respMsg *api.FunctionInvocati
 			} else {
 				fmt.Print(payload.Payload)
 			}
+		case api.OutputTypeAttributeValueList:
+			var payload api.AttributeValueList
+			err := json.Unmarshal(respMsg.Output, &payload)
+			if err == nil && attributeTable {
+				outputAttributeValueTable(payload)
+				break
+			}
+			fallthrough
 		default:
 			// Output should be JSON, but if there's an error print the raw output
 			var out bytes.Buffer
@@ -228,6 +238,21 @@ func outputFunctionInvocationResponse(data []byte, respMsg *api.FunctionInvocati
 	}
 }
 
+func outputAttributeValueTable(attributeValues api.AttributeValueList) {
+	table := tableView()
+	table.SetHeader([]string{"ResourceType", "ResourceName", "Path", "Value", "Source"})
+	for _, attributeValue := range attributeValues {
+		table.Append([]string{
+			string(attributeValue.ResourceType),
+			string(attributeValue.ResourceName),
+			string(attributeValue.Path),
+			fmt.Sprintf("%v", attributeValue.Value),
+			string(attributeValue.Source),
+		})
+	}
+	table.Render()
+}
+
 func readFile(fileName string) []byte {
 	data, err := os.ReadFile(fileName)
 	if err != nil {
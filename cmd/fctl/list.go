@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 
+	"github.com/confighub/sdk/function/api"
 	"github.com/confighub/sdk/function/client"
 	"github.com/spf13/cobra"
 )
@@ -18,7 +19,7 @@ func newListCommand() *cobra.Command {
 		Short: "List available functions",
 		Args:  cobra.ExactArgs(0),
 		Run: func(_ /*cmd*/ *cobra.Command, _ []string) {
-			respMsg, err := client.GetFunctionList(transportConfig, toolchain)
+			respMsg, err := client.GetFunctionListForResourceType(transportConfig, toolchain, api.ResourceType(listResourceType))
 			failOnError(err)
 
 			// Timestamps disrupt golden outputs
@@ -66,5 +67,9 @@ func newListCommand() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVar(&listResourceType, "resource-type", "", "only list functions that apply to the specified resource type")
+
 	return cmd
 }
+
+var listResourceType string
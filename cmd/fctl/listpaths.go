@@ -6,24 +6,54 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/confighub/sdk/function/client"
 	"github.com/spf13/cobra"
 )
 
+var maxDepth int
+
 func newListPathsCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "listpaths",
-		Short: "List registered paths",
-		Args:  cobra.ExactArgs(0),
-		Run: func(_ /*cmd*/ *cobra.Command, _ []string) {
-			respMsg, err := client.GetRegisteredPaths(transportConfig, toolchain)
-			failOnError(err)
-			out, err := json.MarshalIndent(respMsg, "", "  ")
+		Use:   "listpaths [<filename or - for stdin> [<resource type>]]",
+		Short: "List registered paths, or the paths of the leaves of config data",
+		Long: `List the paths registered by the toolchain. If a file is specified, list the paths and values
+of all of the leaves of its resources instead, using the list-paths function, optionally only those
+of resources of the specified type.`,
+		Args: cobra.MaximumNArgs(2),
+		Run: func(_ /*cmd*/ *cobra.Command, args []string) {
+			if len(args) == 0 {
+				respMsg, err := client.GetRegisteredPaths(transportConfig, toolchain)
+				failOnError(err)
+				out, err := json.MarshalIndent(respMsg, "", "  ")
+				failOnError(err)
+				fmt.Println(string(out))
+				return
+			}
+
+			var content []byte
+			if args[0] == "-" {
+				content = readStdin()
+			} else {
+				content = readFile(args[0])
+			}
+			invokeArgs := []string{"--max-depth=" + strconv.Itoa(maxDepth)}
+			if len(args) > 1 {
+				invokeArgs = append(invokeArgs, "--resource-type="+args[1])
+			}
+			respMsg, err := InvokeFunction(transportConfig, toolchain, content, fakeFunctionContext("listpaths"), "list-paths", invokeArgs...)
 			failOnError(err)
-			fmt.Println(string(out))
+			if !respMsg.Success {
+				failOnError(fmt.Errorf("list-paths failed: %s", strings.Join(respMsg.ErrorMessages, "; ")))
+			}
+			outputOnly = true
+			outputFunctionInvocationResponse(content, respMsg)
 		},
 	}
+	cmd.Flags().IntVar(&maxDepth, "max-depth", 0, "maximum number of path segments of the paths of config data; 0 is unlimited")
+	cmd.Flags().BoolVar(&attributeTable, "table", false, "show the paths of config data as a table")
 
 	return cmd
 }
@@ -54,6 +54,7 @@ ${FCTL} do ${DIR}/set-pod-defaults.yaml MyApp validate > ${DIR}/validate-set-pod
 ${FCTL} do test-data/deployment.yaml "MyDeployment" --data-only -- set-pod-defaults --pod-security=true --automount-service-account-token=true --security-context=true --resources=true --probes=false > ${DIR}/set-pod-defaults-no-probes.yaml
 ${FCTL} do test-data/deployment-sample.yaml "MyDeployment" set-default-names > ${DIR}/set-default-names.txt
 ${FCTL} do test-data/service.yaml "MyApp" get-attributes > ${DIR}/get-attributes.txt
+${FCTL} do --table test-data/service.yaml "MyApp" get-attributes > ${DIR}/get-attributes-table.txt
 ${FCTL} do test-data/deployment.yaml "MyApp" get-attributes > ${DIR}/get-attributes2.txt
 ${FCTL} do test-data/deployment-sample.yaml "MyApp" get-needed > ${DIR}/get-needed.txt
 ${FCTL} do test-data/hpa.yaml "MyObj" get-needed > ${DIR}/get-needed2.txt
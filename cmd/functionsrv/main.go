@@ -8,9 +8,7 @@ import (
 	"flag"
 	"log/slog"
 	"os"
-	"os/signal"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -22,6 +20,7 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/confighub/sdk/cmd/internal/signalhandler"
 	"github.com/confighub/sdk/function/server"
 )
 
@@ -65,7 +64,12 @@ func main() {
 
 	httpServer := server.RunServer(ctx, grp, false)
 
-	handleIntercepts(ctx, grp, httpServer)
+	signalhandler.HandleShutdown(ctx, grp, logger, time.Duration(terminationGracePeriodSeconds)*time.Second, func(shutdownCtx context.Context) error {
+		if httpServer != nil {
+			return shutdown(shutdownCtx, httpServer)
+		}
+		return nil
+	})
 
 	if errGrp := grp.Wait(); errGrp != nil {
 		logger.Error("application unexpectedly shut down", "error", errGrp)
@@ -75,41 +79,6 @@ func main() {
 	logger.Info("application gracefully shut down")
 }
 
-func interceptSignals(ctx context.Context) {
-	sigc := make(chan os.Signal, 1)
-	signal.Notify(sigc,
-		syscall.SIGHUP,
-		syscall.SIGINT,
-		syscall.SIGTERM,
-		syscall.SIGQUIT)
-
-	select {
-	case <-ctx.Done():
-	case sig := <-sigc:
-		logger.Info("intercepted signal", "signal", sig)
-	}
-}
-
-func handleIntercepts(ctx context.Context, grp *errgroup.Group, httpServer *echo.Echo) {
-	grp.Go(func() error {
-		interceptSignals(ctx)
-
-		go func() {
-			interceptSignals(ctx)
-			logger.Error("forcibly shutting down on second signal")
-			os.Exit(1)
-		}()
-
-		shutdownCtx, shutCancel := context.WithTimeout(ctx, time.Duration(terminationGracePeriodSeconds)*time.Second)
-		defer shutCancel()
-
-		if httpServer != nil {
-			return shutdown(shutdownCtx, httpServer)
-		}
-		return nil
-	})
-}
-
 func shutdown(ctx context.Context, httpServer *echo.Echo) (errs error) {
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -0,0 +1,70 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package signalhandler provides the signal handling shared by the long-running commands.
+package signalhandler
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ShutdownSignals are the signals that request a graceful shutdown.
+var ShutdownSignals = []os.Signal{
+	syscall.SIGHUP,
+	syscall.SIGINT,
+	syscall.SIGTERM,
+	syscall.SIGQUIT,
+}
+
+// Subscribe returns a channel that receives the specified signals until ctx is done.
+func Subscribe(ctx context.Context, signals ...os.Signal) <-chan os.Signal {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, signals...)
+	go func() {
+		<-ctx.Done()
+		signal.Stop(sigc)
+	}()
+	return sigc
+}
+
+// Intercept blocks until one of the specified signals is received or ctx is done. It returns the
+// signal received, or nil if ctx is done.
+func Intercept(ctx context.Context, logger *slog.Logger, signals ...os.Signal) os.Signal {
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	select {
+	case <-ctx.Done():
+		return nil
+	case sig := <-Subscribe(subCtx, signals...):
+		logger.Info("intercepted signal", "signal", sig)
+		return sig
+	}
+}
+
+// HandleShutdown adds a goroutine to grp that waits for one of the ShutdownSignals and then calls
+// shutdown with a context that expires after gracePeriod. A second signal forcibly exits the
+// process.
+func HandleShutdown(ctx context.Context, grp *errgroup.Group, logger *slog.Logger, gracePeriod time.Duration, shutdown func(ctx context.Context) error) {
+	grp.Go(func() error {
+		Intercept(ctx, logger, ShutdownSignals...)
+
+		go func() {
+			if Intercept(ctx, logger, ShutdownSignals...) != nil {
+				logger.Error("forcibly shutting down on second signal")
+				os.Exit(1)
+			}
+		}()
+
+		shutdownCtx, shutCancel := context.WithTimeout(ctx, gracePeriod)
+		defer shutCancel()
+
+		return shutdown(shutdownCtx)
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package tree renders hierarchies of entities, such as spaces, as ASCII trees.
+package tree
+
+import (
+	"fmt"
+	"io"
+)
+
+// Node is a node of a tree to render.
+type Node struct {
+	Label    string
+	Children []*Node
+}
+
+// Render writes the trees with the specified roots to w, one node per line. Roots are written
+// without indentation and their descendants are connected with ├──, └──, and │. If maxDepth is
+// positive, only nodes up to maxDepth levels deep are written, where roots are at level 1.
+func Render(w io.Writer, roots []*Node, maxDepth int) error {
+	for _, root := range roots {
+		if _, err := fmt.Fprintln(w, root.Label); err != nil {
+			return err
+		}
+		if err := renderChildren(w, root, "", 2, maxDepth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderChildren(w io.Writer, node *Node, prefix string, depth int, maxDepth int) error {
+	if maxDepth > 0 && depth > maxDepth {
+		return nil
+	}
+	for i, child := range node.Children {
+		connector, childPrefix := "├── ", "│   "
+		if i == len(node.Children)-1 {
+			connector, childPrefix = "└── ", "    "
+		}
+		if _, err := fmt.Fprintln(w, prefix+connector+child.Label); err != nil {
+			return err
+		}
+		if err := renderChildren(w, child, prefix+childPrefix, depth+1, maxDepth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
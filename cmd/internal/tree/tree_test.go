@@ -0,0 +1,50 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package tree
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	roots := []*Node{
+		{Label: "platform", Children: []*Node{
+			{Label: "platform-dev", Children: []*Node{
+				{Label: "platform-dev-eu"},
+				{Label: "platform-dev-us"},
+			}},
+			{Label: "platform-prod", Children: []*Node{
+				{Label: "platform-prod-us"},
+			}},
+		}},
+		{Label: "sandbox"},
+	}
+
+	var out strings.Builder
+	require.NoError(t, Render(&out, roots, 0))
+	assert.Equal(t, `platform
+├── platform-dev
+│   ├── platform-dev-eu
+│   └── platform-dev-us
+└── platform-prod
+    └── platform-prod-us
+sandbox
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, Render(&out, roots, 2))
+	assert.Equal(t, `platform
+├── platform-dev
+└── platform-prod
+sandbox
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, Render(&out, roots, 1))
+	assert.Equal(t, "platform\nsandbox\n", out.String())
+}
@@ -0,0 +1,159 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package ansiblekit is used to interpret Ansible/Vars configuration units, which contain
+// Ansible group_vars and host_vars files.
+package ansiblekit
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+// User data errors should not be logged here. They will be logged by the caller.
+// Errors indicate that the operation could not be completed.
+// Messages should be acceptable to return to the user, and should indicate the
+// location of the problem in the configuration data.
+
+type AnsibleVarsResourceProviderType struct{}
+
+var pathRegistry = make(api.AttributeNameToResourceTypeToPathToVisitorInfoType)
+
+func (*AnsibleVarsResourceProviderType) GetPathRegistry() api.AttributeNameToResourceTypeToPathToVisitorInfoType {
+	return pathRegistry
+}
+
+// AnsibleVarsResourceProvider implements the ResourceProvider and ConfigConverter interfaces for Ansible/Vars.
+var AnsibleVarsResourceProvider = &AnsibleVarsResourceProviderType{}
+
+// DefaultResourceCategory returns the default resource category to asssume, which is AppConfig in this case.
+func (*AnsibleVarsResourceProviderType) DefaultResourceCategory() api.ResourceCategory {
+	return api.ResourceCategoryAppConfig
+}
+
+// ResourceCategoryGetter just returns ResourceCategoryAppConfig for YAML documents.
+func (*AnsibleVarsResourceProviderType) ResourceCategoryGetter(doc *gaby.YamlDoc) (api.ResourceCategory, error) {
+	return api.ResourceCategoryAppConfig, nil
+}
+
+// Each YAML document of a unit corresponds to one group_vars or host_vars file. Since the name
+// of the group or host is the name of the file rather than part of its contents, it's recorded
+// in the variable confighub_group or confighub_host, respectively.
+const (
+	ResourceTypeGroupVars = api.ResourceType("ansible/group_vars")
+	ResourceTypeHostVars  = api.ResourceType("ansible/host_vars")
+	GroupNamePath         = api.ResolvedPath("confighub_group")
+	HostNamePath          = api.ResolvedPath("confighub_host")
+	// DefaultGroupName is the group containing all hosts, which is assumed for documents
+	// that specify neither a group nor a host.
+	DefaultGroupName = api.ResourceName("all")
+)
+
+// ResourceTypeGetter returns ansible/host_vars for documents that specify confighub_host and
+// ansible/group_vars otherwise.
+func (*AnsibleVarsResourceProviderType) ResourceTypeGetter(doc *gaby.YamlDoc) (api.ResourceType, error) {
+	if doc.Exists(string(HostNamePath)) {
+		return ResourceTypeHostVars, nil
+	}
+	return ResourceTypeGroupVars, nil
+}
+
+// ResourceNameGetter extracts the variable confighub_host or confighub_group, and returns the
+// all group if neither is present.
+func (*AnsibleVarsResourceProviderType) ResourceNameGetter(doc *gaby.YamlDoc) (api.ResourceName, error) {
+	for _, namePath := range []api.ResolvedPath{HostNamePath, GroupNamePath} {
+		name, hasName, err := yamlkit.YamlSafePathGetValue[string](doc, namePath, true)
+		if err != nil {
+			return "", err
+		}
+		if hasName {
+			return api.ResourceName(name), nil
+		}
+	}
+	return DefaultGroupName, nil
+}
+
+func (*AnsibleVarsResourceProviderType) ScopelessResourceNamePath() api.ResolvedPath {
+	return GroupNamePath
+}
+
+// SetResourceName sets confighub_host for host_vars documents and confighub_group otherwise.
+func (*AnsibleVarsResourceProviderType) SetResourceName(doc *gaby.YamlDoc, name string) error {
+	namePath := GroupNamePath
+	if doc.Exists(string(HostNamePath)) {
+		namePath = HostNamePath
+	}
+	_, err := doc.SetP(name, string(namePath))
+	return err
+}
+
+func (*AnsibleVarsResourceProviderType) TypeDescription() string {
+	return "ansible/group_vars or ansible/host_vars"
+}
+
+const nameSeparatorString = "_"
+
+// NormalizeName replaces the characters that aren't valid in Ansible group names with underscores.
+func (*AnsibleVarsResourceProviderType) NormalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+func (*AnsibleVarsResourceProviderType) NameSeparator() string {
+	return nameSeparatorString
+}
+
+const (
+	contextPathPrefix = "confighub_"
+)
+
+// ContextPath returns the variable for the context field, such as confighub_unit_slug for
+// UnitSlug, since Ansible variable names can't contain dots.
+func (*AnsibleVarsResourceProviderType) ContextPath(contextField string) string {
+	var sb strings.Builder
+	sb.WriteString(contextPathPrefix)
+	runes := []rune(contextField)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]) {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// ResourceAndCategoryTypeMaps returns maps of all resources in the provided list of parsed YAML
+// documents, from from names to categories+types and categories+types to names.
+func (*AnsibleVarsResourceProviderType) ResourceAndCategoryTypeMaps(docs gaby.Container) (resourceMap yamlkit.ResourceNameToCategoryTypesMap, categoryTypeMap yamlkit.ResourceCategoryTypeToNamesMap, err error) {
+	return yamlkit.ResourceAndCategoryTypeMaps(docs, AnsibleVarsResourceProvider)
+}
+
+func (*AnsibleVarsResourceProviderType) RemoveScopeFromResourceName(resourceName api.ResourceName) api.ResourceName {
+	return resourceName
+}
+
+func (*AnsibleVarsResourceProviderType) ResourceTypesAreSimilar(resourceTypeA, resourceTypeB api.ResourceType) bool {
+	return resourceTypeA == resourceTypeB
+}
+
+// NativeToYAML returns the data unchanged, since vars files are YAML.
+func (*AnsibleVarsResourceProviderType) NativeToYAML(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+// YAMLToNative returns the data unchanged, since vars files are YAML.
+func (*AnsibleVarsResourceProviderType) YAMLToNative(yamlData []byte) ([]byte, error) {
+	return yamlData, nil
+}
+
+func (*AnsibleVarsResourceProviderType) DataType() api.DataType {
+	return api.DataTypeYAML
+}
@@ -0,0 +1,54 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package ansiblekit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const ansibleVarsFixture = `ntp_servers:
+  - 0.pool.ntp.org
+---
+confighub_group: webservers
+nginx:
+  worker_processes: 4
+---
+confighub_host: web1.example.com
+nginx:
+  worker_processes: 8
+`
+
+func TestAnsibleVarsResourceProvider(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(ansibleVarsFixture))
+	assert.NoError(t, err)
+	assert.Len(t, docs, 3)
+
+	resourceMap, _, err := AnsibleVarsResourceProvider.ResourceAndCategoryTypeMaps(docs)
+	assert.NoError(t, err)
+	assert.Equal(t, yamlkit.ResourceNameToCategoryTypesMap{
+		"all":              {{ResourceCategory: api.ResourceCategoryAppConfig, ResourceType: ResourceTypeGroupVars}},
+		"webservers":       {{ResourceCategory: api.ResourceCategoryAppConfig, ResourceType: ResourceTypeGroupVars}},
+		"web1.example.com": {{ResourceCategory: api.ResourceCategoryAppConfig, ResourceType: ResourceTypeHostVars}},
+	}, resourceMap)
+
+	err = AnsibleVarsResourceProvider.SetResourceName(docs[1], "appservers")
+	assert.NoError(t, err)
+	err = AnsibleVarsResourceProvider.SetResourceName(docs[2], "web2.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "appservers", docs[1].Path(string(GroupNamePath)).Data())
+	assert.Equal(t, "web2.example.com", docs[2].Path(string(HostNamePath)).Data())
+	assert.False(t, docs[2].Exists(string(GroupNamePath)))
+}
+
+func TestAnsibleVarsNames(t *testing.T) {
+	assert.Equal(t, "web_servers_eu_1", AnsibleVarsResourceProvider.NormalizeName("web-servers.eu-1"))
+	assert.Equal(t, "confighub_unit_slug", AnsibleVarsResourceProvider.ContextPath("UnitSlug"))
+	assert.Equal(t, "confighub_space_id", AnsibleVarsResourceProvider.ContextPath("SpaceID"))
+}
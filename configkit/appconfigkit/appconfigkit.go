@@ -0,0 +1,122 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package appconfigkit is used to interpret AppConfig/YAML configuration units, such as
+// freeform YAML configuration stored in AWS AppConfig.
+package appconfigkit
+
+import (
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+// User data errors should not be logged here. They will be logged by the caller.
+// Errors indicate that the operation could not be completed.
+// Messages should be acceptable to return to the user, and should indicate the
+// location of the problem in the configuration data.
+
+type AppConfigYAMLResourceProviderType struct{}
+
+var pathRegistry = make(api.AttributeNameToResourceTypeToPathToVisitorInfoType)
+
+func (*AppConfigYAMLResourceProviderType) GetPathRegistry() api.AttributeNameToResourceTypeToPathToVisitorInfoType {
+	return pathRegistry
+}
+
+// AppConfigYAMLResourceProvider implements the ResourceProvider and ConfigConverter interfaces for AppConfig/YAML.
+var AppConfigYAMLResourceProvider = &AppConfigYAMLResourceProviderType{}
+
+// DefaultResourceCategory returns the default resource category to asssume, which is AppConfig in this case.
+func (*AppConfigYAMLResourceProviderType) DefaultResourceCategory() api.ResourceCategory {
+	return api.ResourceCategoryAppConfig
+}
+
+// ResourceCategoryGetter just returns ResourceCategoryAppConfig for YAML documents.
+func (*AppConfigYAMLResourceProviderType) ResourceCategoryGetter(doc *gaby.YamlDoc) (api.ResourceCategory, error) {
+	return api.ResourceCategoryAppConfig, nil
+}
+
+const (
+	ResourceTypeAppConfigYAML = api.ResourceType("app-config/yaml")
+	ResourceNameNoName        = api.ResourceName("NoName")
+	ConfigNamePath            = api.ResolvedPath("configHub.configName")
+)
+
+// ResourceTypeGetter returns app-config/yaml. The whole document is treated as a single
+// resource, since freeform configuration has no schema identifying its type.
+func (*AppConfigYAMLResourceProviderType) ResourceTypeGetter(doc *gaby.YamlDoc) (api.ResourceType, error) {
+	return ResourceTypeAppConfigYAML, nil
+}
+
+// ResourceNameGetter extracts the property configHub.configName, and returns NoName if not present.
+func (*AppConfigYAMLResourceProviderType) ResourceNameGetter(doc *gaby.YamlDoc) (api.ResourceName, error) {
+	name, hasName, err := yamlkit.YamlSafePathGetValue[string](doc, ConfigNamePath, true)
+	if err != nil {
+		return "", err
+	}
+	if hasName {
+		return api.ResourceName(name), nil
+	}
+	return ResourceNameNoName, nil
+}
+
+func (*AppConfigYAMLResourceProviderType) ScopelessResourceNamePath() api.ResolvedPath {
+	return ConfigNamePath
+}
+
+func (*AppConfigYAMLResourceProviderType) SetResourceName(doc *gaby.YamlDoc, name string) error {
+	_, err := doc.SetP(name, string(ConfigNamePath))
+	return err
+}
+
+func (*AppConfigYAMLResourceProviderType) TypeDescription() string {
+	return "app-config/yaml"
+}
+
+const nameSeparatorString = ""
+
+func (*AppConfigYAMLResourceProviderType) NormalizeName(name string) string {
+	// Virtually all characters are valid
+	return name
+}
+
+func (*AppConfigYAMLResourceProviderType) NameSeparator() string {
+	return nameSeparatorString
+}
+
+const (
+	contextPathPrefix = "configHub."
+)
+
+func (*AppConfigYAMLResourceProviderType) ContextPath(contextField string) string {
+	return contextPathPrefix + yamlkit.LowerFirst(contextField)
+}
+
+// ResourceAndCategoryTypeMaps returns maps of all resources in the provided list of parsed YAML
+// documents, from from names to categories+types and categories+types to names.
+func (*AppConfigYAMLResourceProviderType) ResourceAndCategoryTypeMaps(docs gaby.Container) (resourceMap yamlkit.ResourceNameToCategoryTypesMap, categoryTypeMap yamlkit.ResourceCategoryTypeToNamesMap, err error) {
+	return yamlkit.ResourceAndCategoryTypeMaps(docs, AppConfigYAMLResourceProvider)
+}
+
+func (*AppConfigYAMLResourceProviderType) RemoveScopeFromResourceName(resourceName api.ResourceName) api.ResourceName {
+	return resourceName
+}
+
+func (*AppConfigYAMLResourceProviderType) ResourceTypesAreSimilar(resourceTypeA, resourceTypeB api.ResourceType) bool {
+	return resourceTypeA == resourceTypeB
+}
+
+// NativeToYAML returns the data unchanged, since the native format is YAML.
+func (*AppConfigYAMLResourceProviderType) NativeToYAML(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+// YAMLToNative returns the data unchanged, since the native format is YAML.
+func (*AppConfigYAMLResourceProviderType) YAMLToNative(yamlData []byte) ([]byte, error) {
+	return yamlData, nil
+}
+
+func (*AppConfigYAMLResourceProviderType) DataType() api.DataType {
+	return api.DataTypeYAML
+}
@@ -0,0 +1,65 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package appconfigkit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const appConfigFixture = `configHub:
+  configName: payments-config
+service:
+  name: payments
+  port: 8080
+features:
+  - fraud-detection
+  - retries
+`
+
+func TestAppConfigYAMLResourceProvider(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(appConfigFixture))
+	assert.NoError(t, err)
+	assert.Len(t, docs, 1)
+
+	resourceMap, categoryTypeMap, err := AppConfigYAMLResourceProvider.ResourceAndCategoryTypeMaps(docs)
+	assert.NoError(t, err)
+	assert.Equal(t, yamlkit.ResourceNameToCategoryTypesMap{
+		"payments-config": {{ResourceCategory: api.ResourceCategoryAppConfig, ResourceType: ResourceTypeAppConfigYAML}},
+	}, resourceMap)
+	assert.Equal(t, []api.ResourceName{"payments-config"},
+		categoryTypeMap[api.ResourceCategoryType{ResourceCategory: api.ResourceCategoryAppConfig, ResourceType: ResourceTypeAppConfigYAML}])
+
+	err = AppConfigYAMLResourceProvider.SetResourceName(docs[0], "orders-config")
+	assert.NoError(t, err)
+	resourceName, err := AppConfigYAMLResourceProvider.ResourceNameGetter(docs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, api.ResourceName("orders-config"), resourceName)
+}
+
+func TestAppConfigYAMLResourceProvider_NoName(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte("service:\n  name: payments\n"))
+	assert.NoError(t, err)
+	resourceName, err := AppConfigYAMLResourceProvider.ResourceNameGetter(docs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, ResourceNameNoName, resourceName)
+	resourceType, err := AppConfigYAMLResourceProvider.ResourceTypeGetter(docs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, ResourceTypeAppConfigYAML, resourceType)
+}
+
+func TestAppConfigYAMLConversion(t *testing.T) {
+	yamlData, err := AppConfigYAMLResourceProvider.NativeToYAML([]byte(appConfigFixture))
+	assert.NoError(t, err)
+	assert.Equal(t, appConfigFixture, string(yamlData))
+	nativeData, err := AppConfigYAMLResourceProvider.YAMLToNative(yamlData)
+	assert.NoError(t, err)
+	assert.Equal(t, appConfigFixture, string(nativeData))
+	assert.Equal(t, api.DataTypeYAML, AppConfigYAMLResourceProvider.DataType())
+}
@@ -60,7 +60,7 @@ func isReference(categoryTypeMap yamlkit.ResourceCategoryTypeToNamesMap, s strin
 			category = api.ResourceCategoryResource
 			typeIndex = 0
 		}
-		categoryType := api.ResourceCategoryType{category, api.ResourceType(segments[typeIndex])}
+		categoryType := api.ResourceCategoryType{ResourceCategory: category, ResourceType: api.ResourceType(segments[typeIndex])}
 		name := segments[typeIndex+1]
 		// fmt.Printf("type %s, name %s\n", string(categoryType.ResourceType), name)
 		names, found := categoryTypeMap[categoryType]
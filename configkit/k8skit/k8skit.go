@@ -343,6 +343,51 @@ func (*K8sResourceProviderType) ContextPath(contextField string) string {
 	return contextPathPrefx + safeKey
 }
 
+// Context isn't meaningful for these resource types, which aren't owned by workloads.
+var contextPathExceptions = []api.ResourceType{
+	"v1/ServiceAccount",
+	"v1/ConfigMap",
+	"v1/Secret",
+}
+
+func (*K8sResourceProviderType) ContextPathExceptions() []api.ResourceType {
+	return contextPathExceptions
+}
+
+func (*K8sResourceProviderType) LabelPath(labelKey string) string {
+	return "metadata.labels." + yamlkit.EscapeDotsInPathSegment(labelKey)
+}
+
+// liveStateOnlyPaths are paths populated by the Kubernetes API server that are not expected
+// to be present in the config data.
+var liveStateOnlyPaths = []string{
+	"status",
+	"metadata.managedFields",
+	"metadata.resourceVersion",
+	"metadata.uid",
+	"metadata.generation",
+	"metadata.creationTimestamp",
+	"metadata.selfLink",
+	"metadata.annotations." + yamlkit.EscapeDotsInPathSegment("kubectl.kubernetes.io/last-applied-configuration"),
+	"metadata.annotations." + yamlkit.EscapeDotsInPathSegment("deployment.kubernetes.io/revision"),
+}
+
+// RemoveLiveStateOnlyFields removes fields populated by the Kubernetes API server from the
+// live state documents so that they can be compared with config data.
+func (*K8sResourceProviderType) RemoveLiveStateOnlyFields(liveState gaby.Container) {
+	for _, doc := range liveState {
+		for _, path := range liveStateOnlyPaths {
+			if doc.ExistsP(path) {
+				_ = doc.DeleteP(path)
+			}
+		}
+		// Remove empty annotations left over after removing server-populated ones
+		if annotations, ok := doc.Path("metadata.annotations").Data().(map[string]any); ok && len(annotations) == 0 {
+			_ = doc.DeleteP("metadata.annotations")
+		}
+	}
+}
+
 // The conversions are no-ops since Kubernetes/YAML is already YAML.
 
 func (*K8sResourceProviderType) NativeToYAML(data []byte) ([]byte, error) {
@@ -5,6 +5,7 @@ package k8skit
 
 import (
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -92,6 +93,24 @@ spec:
 				},
 			},
 		},
+		{
+			name: "BOM prefix",
+			data: "\uFEFF" + createPod(t, "pod1", ""),
+			want: yamlkit.ResourceNameToCategoryTypesMap{
+				api.ResourceName("/pod1"): {{ResourceCategory: api.ResourceCategoryResource, ResourceType: testResourceTypePod}},
+			},
+		},
+		{
+			name: "Carriage return line endings",
+			data: strings.ReplaceAll(joinYAMLDocs(
+				createPod(t, "pod1", ""),
+				createService(t, "svc1", "", "svc1"),
+			), "\n", "\r"),
+			want: yamlkit.ResourceNameToCategoryTypesMap{
+				api.ResourceName("/pod1"): {{ResourceCategory: api.ResourceCategoryResource, ResourceType: testResourceTypePod}},
+				api.ResourceName("/svc1"): {{ResourceCategory: api.ResourceCategoryResource, ResourceType: testResourceTypeService}},
+			},
+		},
 		{
 			name: "Missing metadata",
 			data: `apiVersion: v1
@@ -58,6 +58,14 @@ func newEmbeddedAccessor(embeddedAccessorType api.EmbeddedAccessorType, config s
 	case api.EmbeddedAccessorRegexp:
 		a, err := newRegexpAccessor(config)
 		return a, err
+	case api.EmbeddedAccessorURL:
+		return &URLAccessor{}, nil
+	case api.EmbeddedAccessorKeyValue:
+		return &KeyValueAccessor{PairSeparator: config}, nil
+	case api.EmbeddedAccessorJSON:
+		return &JSONAccessor{}, nil
+	case api.EmbeddedAccessorAuto:
+		return &AutoAccessor{}, nil
 	default:
 		return nil, errors.New("accessor type not supported")
 	}
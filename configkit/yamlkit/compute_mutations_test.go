@@ -4,7 +4,9 @@
 package yamlkit_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/confighub/sdk/configkit/k8skit"
@@ -57,6 +59,31 @@ spec:
       containers:
       - name: mycontainer
         image: nginx:latest
+`,
+			functionIndex: 1,
+			validateResult: func(t *testing.T, mutations api.ResourceMutationList) {
+				assert.Len(t, mutations, 1)
+				assert.Equal(t, api.MutationTypeNone, mutations[0].ResourceMutationInfo.MutationType)
+				assert.Empty(t, mutations[0].PathMutationMap)
+			},
+		},
+		{
+			name: "Comment changes only",
+			previous: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+  namespace: example
+spec:
+  replicas: 3 # scaled for launch
+`,
+			modified: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myapp
+  namespace: example
+spec:
+  replicas: 3 # scaled back after launch
 `,
 			functionIndex: 1,
 			validateResult: func(t *testing.T, mutations api.ResourceMutationList) {
@@ -626,3 +653,181 @@ rollingUpdate:
 		})
 	}
 }
+
+// matchQualityFixture contains resources that are renamed or unchanged between revisions.
+// expectedMatches maps each modified resource name to the previous resource name it should be matched
+// with, or to the empty string if it should be treated as a new resource.
+func matchQualityFixture() (previous, modified string, expectedMatches map[api.ResourceName]api.ResourceName) {
+	largeDeployment := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: large
+  namespace: example
+spec:
+  template:
+    metadata:
+      labels:
+`
+	for i := 0; i < 100; i++ {
+		largeDeployment += fmt.Sprintf("        label-%d: value-%d\n", i, i)
+	}
+	configMap := func(name string, keys ...string) string {
+		doc := fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n  namespace: example\ndata:\n", name)
+		for _, key := range keys {
+			doc += fmt.Sprintf("  %s: %s-value\n", key, key)
+		}
+		return doc
+	}
+	previous = largeDeployment +
+		"---\n" + configMap("backend-config", "shared") +
+		"---\n" + configMap("frontend-config", "shared") +
+		"---\n" + configMap("db-primary", "host", "port") +
+		"---\n" + configMap("db-replica", "host", "port")
+	// The renamed resources have unchanged content, so they match each of the candidates equally well
+	// except for their names.
+	modified = largeDeployment +
+		"---\n" + configMap("frontend-config-v2", "shared") +
+		"---\n" + configMap("db-replica-2", "host", "port")
+	expectedMatches = map[api.ResourceName]api.ResourceName{
+		"example/large":              "example/large",
+		"example/frontend-config-v2": "example/frontend-config",
+		"example/db-replica-2":       "example/db-replica",
+	}
+	return previous, modified, expectedMatches
+}
+
+func countCorrectMatches(mutations api.ResourceMutationList, expectedMatches map[api.ResourceName]api.ResourceName) int {
+	correct := 0
+	for _, mutation := range mutations {
+		expectedMatch, ok := expectedMatches[mutation.Resource.ResourceName]
+		if !ok {
+			continue
+		}
+		if mutation.ResourceMutationInfo.MutationType == api.MutationTypeAdd {
+			if expectedMatch == "" {
+				correct++
+			}
+			continue
+		}
+		if _, matched := mutation.Aliases[expectedMatch]; matched && expectedMatch != "" {
+			correct++
+		}
+	}
+	return correct
+}
+
+// legacyMatches reproduces the previous matching algorithm, which normalized the number of changes by the
+// number of lines in the whole modified container and broke ties by position, for comparison.
+func legacyMatches(previousDocs, modifiedDocs gaby.Container) api.ResourceMutationList {
+	mutations := api.ResourceMutationList{}
+	numDocLines := strings.Count(modifiedDocs.String(), "\n")
+	for _, modifiedDoc := range modifiedDocs {
+		_, modifiedType, modifiedName, _ := yamlkit.GetResourceCategoryTypeName(modifiedDoc, k8skit.K8sResourceProvider)
+		matchName := api.ResourceName("")
+		minMutationLength := -1
+		for _, previousDoc := range previousDocs {
+			_, previousType, previousName, _ := yamlkit.GetResourceCategoryTypeName(previousDoc, k8skit.K8sResourceProvider)
+			if previousType != modifiedType {
+				continue
+			}
+			mutationMap := api.MutationMap{}
+			yamlkit.ComputeMutationsForDocs("", previousDoc, modifiedDoc, 0, mutationMap)
+			if previousName == modifiedName {
+				matchName = previousName
+				minMutationLength = 0
+				break
+			}
+			if minMutationLength < 0 || len(mutationMap) < minMutationLength {
+				minMutationLength = len(mutationMap)
+				matchName = previousName
+			}
+		}
+		mutation := api.ResourceMutation{Resource: api.ResourceInfo{ResourceName: modifiedName}}
+		if minMutationLength < 0 || float64(minMutationLength)/float64(numDocLines) > 1.0 {
+			mutation.ResourceMutationInfo.MutationType = api.MutationTypeAdd
+		} else {
+			mutation.ResourceMutationInfo.MutationType = api.MutationTypeUpdate
+			mutation.Aliases = map[api.ResourceName]struct{}{matchName: {}}
+		}
+		mutations = append(mutations, mutation)
+	}
+	return mutations
+}
+
+func TestComputeMutationsMatchQuality(t *testing.T) {
+	previous, modified, expectedMatches := matchQualityFixture()
+	previousDocs, err := gaby.ParseAll([]byte(previous))
+	assert.NoError(t, err)
+	modifiedDocs, err := gaby.ParseAll([]byte(modified))
+	assert.NoError(t, err)
+
+	mutations, err := yamlkit.ComputeMutations(previousDocs, modifiedDocs, 0, k8skit.K8sResourceProvider)
+	assert.NoError(t, err)
+	assert.Equal(t, len(expectedMatches), countCorrectMatches(mutations, expectedMatches))
+	// The previous algorithm preferred the first candidate on ties.
+	assert.Less(t, countCorrectMatches(legacyMatches(previousDocs, modifiedDocs), expectedMatches), len(expectedMatches))
+}
+
+func BenchmarkComputeMutationsMatchQuality(b *testing.B) {
+	previous, modified, expectedMatches := matchQualityFixture()
+	previousDocs, err := gaby.ParseAll([]byte(previous))
+	assert.NoError(b, err)
+	modifiedDocs, err := gaby.ParseAll([]byte(modified))
+	assert.NoError(b, err)
+
+	b.Run("legacy", func(b *testing.B) {
+		correct := 0
+		for i := 0; i < b.N; i++ {
+			correct = countCorrectMatches(legacyMatches(previousDocs, modifiedDocs), expectedMatches)
+		}
+		b.ReportMetric(float64(correct)/float64(len(expectedMatches)), "match-accuracy")
+	})
+	b.Run("current", func(b *testing.B) {
+		correct := 0
+		for i := 0; i < b.N; i++ {
+			mutations, err := yamlkit.ComputeMutations(previousDocs, modifiedDocs, 0, k8skit.K8sResourceProvider)
+			if err != nil {
+				b.Fatal(err)
+			}
+			correct = countCorrectMatches(mutations, expectedMatches)
+		}
+		b.ReportMetric(float64(correct)/float64(len(expectedMatches)), "match-accuracy")
+	})
+}
+
+func TestComputeMutationsForDocsIsDeterministic(t *testing.T) {
+	// The keys a.b and a~1b have the same escaped path, so the mutation recorded for it depends
+	// on the order in which the keys are processed.
+	previous := `data:
+  a.b: one
+  a~1b: two
+  c: three
+  d: four
+  e: five
+  f:
+    g: six
+`
+	modified := `data:
+  a.b: uno
+  a~1b: dos
+  c: tres
+  h: eight
+  f: seven
+`
+	computeMutations := func() []byte {
+		previousDoc, err := gaby.ParseYAML([]byte(previous))
+		assert.NoError(t, err)
+		modifiedDoc, err := gaby.ParseYAML([]byte(modified))
+		assert.NoError(t, err)
+		mutationMap := api.MutationMap{}
+		yamlkit.ComputeMutationsForDocs("", previousDoc, modifiedDoc, 1, mutationMap)
+		serialized, err := json.Marshal(mutationMap)
+		assert.NoError(t, err)
+		return serialized
+	}
+
+	expected := computeMutations()
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, string(expected), string(computeMutations()))
+	}
+}
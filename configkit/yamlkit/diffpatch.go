@@ -72,3 +72,46 @@ func DiffPatch(original, modified, targetData []byte, resourceProvider ResourceP
 	}
 	return buf.Bytes(), true, nil
 }
+
+// OverlayResources overlays the resources in overlay onto the resources in parsedData with the
+// same resource type and name, as with gaby.YamlDoc.Overlay. Maps are merged, other values are
+// replaced, and keys set to null in the overlay are removed. Resources only in the overlay are
+// appended, and resources only in parsedData are kept.
+func OverlayResources(parsedData, overlay gaby.Container, resourceProvider ResourceProvider) (gaby.Container, error) {
+	resourceKey := func(doc *gaby.YamlDoc) (string, error) {
+		resourceType, err := resourceProvider.ResourceTypeGetter(doc)
+		if err != nil {
+			return "", err
+		}
+		resourceName, err := resourceProvider.ResourceNameGetter(doc)
+		if err != nil {
+			return "", err
+		}
+		return string(resourceType) + "#" + string(resourceName), nil
+	}
+
+	docIndex := map[string]int{}
+	for i, doc := range parsedData {
+		key, err := resourceKey(doc)
+		if err != nil {
+			return parsedData, fmt.Errorf("failed to identify resource %d: %v", i, err)
+		}
+		docIndex[key] = i
+	}
+	for i, overlayDoc := range overlay {
+		key, err := resourceKey(overlayDoc)
+		if err != nil {
+			return parsedData, fmt.Errorf("failed to identify overlay resource %d: %v", i, err)
+		}
+		index, found := docIndex[key]
+		if !found {
+			docIndex[key] = len(parsedData)
+			parsedData = append(parsedData, overlayDoc)
+			continue
+		}
+		if err := parsedData[index].Overlay(overlayDoc); err != nil {
+			return parsedData, fmt.Errorf("failed to overlay resource %s: %v", key, err)
+		}
+	}
+	return parsedData, nil
+}
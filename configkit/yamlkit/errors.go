@@ -0,0 +1,70 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package yamlkit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/confighub/sdk/function/api"
+)
+
+var (
+	// ErrPathNotFound is matched by errors.Is for errors reporting that a path doesn't exist.
+	ErrPathNotFound = errors.New("path not found")
+	// ErrInternal indicates an unexpected condition that isn't caused by the configuration data.
+	// Errors wrapping it describe where the condition was detected.
+	ErrInternal = errors.New("internal error")
+)
+
+// PathNotFoundError is returned when a path expected to exist in a document doesn't exist.
+type PathNotFoundError struct {
+	Path api.ResolvedPath
+}
+
+func (e *PathNotFoundError) Error() string {
+	return fmt.Sprintf("%s not found", string(e.Path))
+}
+
+// Is reports whether target is ErrPathNotFound.
+func (e *PathNotFoundError) Is(target error) bool {
+	return target == ErrPathNotFound
+}
+
+// TypeMismatchError is returned when the value at a path isn't of the expected type. The resource
+// type and name are set when the value was found by visiting the paths of resources.
+type TypeMismatchError struct {
+	ResourceType api.ResourceType
+	ResourceName api.ResourceName
+	Path         api.ResolvedPath
+	Value        any
+	// Want is the expected type: a DataType or a Go type.
+	Want string
+	// Got is the actual type of Value, in the same form as Want.
+	Got string
+}
+
+func (e *TypeMismatchError) Error() string {
+	location := ""
+	if e.Path != "" {
+		location = " at path " + string(e.Path)
+	}
+	if e.ResourceType != "" {
+		location += fmt.Sprintf(" of %s %q", e.ResourceType, e.ResourceName)
+	}
+	return fmt.Sprintf("value %v%s is of type %s but expected %s", e.Value, location, e.Got, e.Want)
+}
+
+// newGoTypeMismatchError returns a TypeMismatchError for a value that couldn't be converted to the Go type T.
+func newGoTypeMismatchError[T any](resourceInfo api.ResourceInfo, path api.ResolvedPath, value any) *TypeMismatchError {
+	var want T
+	return &TypeMismatchError{
+		ResourceType: resourceInfo.ResourceType,
+		ResourceName: resourceInfo.ResourceName,
+		Path:         path,
+		Value:        value,
+		Want:         fmt.Sprintf("%T", want),
+		Got:          fmt.Sprintf("%T", value),
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package yamlkit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const errorsTestYAML = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+spec:
+  replicas: "3"
+`
+
+func TestTypeMismatchError_GetPathsAnyType(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(errorsTestYAML))
+	assert.NoError(t, err)
+	resourceTypeToPaths := api.ResourceTypeToPathToVisitorInfoType{
+		"apps/v1/Deployment": {
+			"spec.replicas": {Path: "spec.replicas", AttributeName: "replicas", DataType: api.DataTypeInt},
+		},
+	}
+	_, err = GetPathsAnyType(docs, resourceTypeToPaths, []any{}, NewMockResourceProvider(), api.DataTypeInt, false)
+
+	var typeMismatch *TypeMismatchError
+	if assert.True(t, errors.As(err, &typeMismatch)) {
+		assert.Equal(t, api.ResolvedPath("spec.replicas"), typeMismatch.Path)
+		assert.Equal(t, api.ResourceName("default/web"), typeMismatch.ResourceName)
+		assert.Equal(t, string(api.DataTypeInt), typeMismatch.Want)
+		assert.Equal(t, string(api.DataTypeString), typeMismatch.Got)
+	}
+	assert.EqualError(t, err, `value 3 at path spec.replicas of apps/v1/Deployment "default/web" is of type string but expected int`)
+}
+
+func TestTypeMismatchError_VisitPaths(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(errorsTestYAML))
+	assert.NoError(t, err)
+	resourceTypeToPaths := api.ResourceTypeToPathToVisitorInfoType{
+		"apps/v1/Deployment": {
+			"spec.replicas": {Path: "spec.replicas", AttributeName: "replicas", DataType: api.DataTypeInt},
+		},
+	}
+	visitor := func(_ *gaby.YamlDoc, output any, _ VisitorContext, _ int) (any, error) {
+		return output, nil
+	}
+	_, err = VisitPaths(docs, resourceTypeToPaths, []any{}, nil, NewMockResourceProvider(), visitor, false)
+
+	var typeMismatch *TypeMismatchError
+	if assert.True(t, errors.As(err, &typeMismatch)) {
+		assert.Equal(t, "int", typeMismatch.Want)
+		assert.Equal(t, "string", typeMismatch.Got)
+	}
+	assert.ErrorContains(t, err, "at path spec.replicas")
+}
+
+func TestYamlSafePathGetValue_Errors(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(errorsTestYAML))
+	assert.NoError(t, err)
+
+	_, _, err = YamlSafePathGetValue[int](docs[0], "spec.replicas", false)
+	var typeMismatch *TypeMismatchError
+	assert.True(t, errors.As(err, &typeMismatch))
+	assert.EqualError(t, err, "value 3 at path spec.replicas is of type string but expected int")
+
+	_, _, err = YamlSafePathGetValue[int](docs[0], "spec.minReadySeconds", false)
+	var pathNotFound *PathNotFoundError
+	if assert.True(t, errors.As(err, &pathNotFound)) {
+		assert.Equal(t, api.ResolvedPath("spec.minReadySeconds"), pathNotFound.Path)
+	}
+	assert.ErrorIs(t, err, ErrPathNotFound)
+	assert.EqualError(t, err, "spec.minReadySeconds not found")
+
+	_, found, err := YamlSafePathGetValue[int](docs[0], "spec.minReadySeconds", true)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,71 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package yamlkit
+
+import (
+	"github.com/confighub/sdk/function/api"
+)
+
+// LinkCandidate is a proposed link that would satisfy a Needed attribute with the value of a
+// Provided attribute. SetterInvocation is the setter of the Needed attribute that matched the
+// getter of the Provided attribute.
+type LinkCandidate struct {
+	Needed           api.AttributeValue
+	Provided         api.AttributeValue
+	SetterInvocation api.FunctionInvocation
+}
+
+// MatchNeededProvided returns the candidate links between the Needed and Provided attributes.
+// As described for RegisterProvidedPaths, a Provided attribute can satisfy a Needed attribute
+// when they have the same attribute name and the arguments of the getter of the Provided attribute
+// match the leading arguments of one of the setters of the Needed attribute. The function names
+// aren't compared. The candidates are returned in the order of the Needed attributes and then of
+// the Provided attributes.
+func MatchNeededProvided(needed, provided api.AttributeValueList) []LinkCandidate {
+	candidates := []LinkCandidate{}
+	for _, neededValue := range needed {
+		if neededValue.Info == nil {
+			continue
+		}
+		for _, providedValue := range provided {
+			if providedValue.AttributeName != neededValue.AttributeName ||
+				providedValue.Info == nil || providedValue.Info.GetterInvocation == nil {
+				continue
+			}
+			// An attribute can't satisfy itself
+			if providedValue.ResourceType == neededValue.ResourceType &&
+				providedValue.ResourceName == neededValue.ResourceName &&
+				providedValue.Path == neededValue.Path {
+				continue
+			}
+			for _, setterInvocation := range neededValue.Info.SetterInvocations {
+				if setterMatchesGetter(&setterInvocation, providedValue.Info.GetterInvocation) {
+					candidates = append(candidates, LinkCandidate{
+						Needed:           neededValue,
+						Provided:         providedValue,
+						SetterInvocation: setterInvocation,
+					})
+					break
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+// setterMatchesGetter reports whether the setter's arguments begin with the getter's arguments.
+// The setter's parameters are expected to be the getter's parameters plus the value to set.
+func setterMatchesGetter(setterInvocation, getterInvocation *api.FunctionInvocation) bool {
+	if len(setterInvocation.Arguments) < len(getterInvocation.Arguments) {
+		return false
+	}
+	for i, getterArgument := range getterInvocation.Arguments {
+		setterArgument := setterInvocation.Arguments[i]
+		if setterArgument.ParameterName != getterArgument.ParameterName ||
+			setterArgument.Value != getterArgument.Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package yamlkit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/function/api"
+)
+
+func testLinkAttribute(resourceType api.ResourceType, path api.ResolvedPath, value any, details *api.AttributeDetails) api.AttributeValue {
+	return api.AttributeValue{
+		AttributeInfo: api.AttributeInfo{
+			AttributeIdentifier: api.AttributeIdentifier{
+				ResourceInfo: api.ResourceInfo{ResourceType: resourceType, ResourceName: "ns/name"},
+				Path:         path,
+			},
+			AttributeMetadata: api.AttributeMetadata{
+				AttributeName: api.AttributeNameResourceName,
+				DataType:      api.DataTypeString,
+				Info:          details,
+			},
+		},
+		Value: value,
+	}
+}
+
+func TestMatchNeededProvided(t *testing.T) {
+	resourceTypeArgument := func(resourceType string) []api.FunctionArgument {
+		return []api.FunctionArgument{{ParameterName: "resource-type", Value: resourceType}}
+	}
+	needed := api.AttributeValueList{
+		testLinkAttribute("apps/v1/Deployment", "spec.template.spec.volumes.0.configMap.name", "confighubplaceholder", &api.AttributeDetails{
+			SetterInvocations: []api.FunctionInvocation{
+				{FunctionName: "set-references-of-type", Arguments: resourceTypeArgument("v1/ConfigMap")},
+			},
+		}),
+	}
+	configMapName := testLinkAttribute("v1/ConfigMap", "metadata.name", "app-config", &api.AttributeDetails{
+		GetterInvocation: &api.FunctionInvocation{FunctionName: "get-resources-of-type", Arguments: resourceTypeArgument("v1/ConfigMap")},
+	})
+	provided := api.AttributeValueList{
+		testLinkAttribute("v1/Secret", "metadata.name", "app-secret", &api.AttributeDetails{
+			GetterInvocation: &api.FunctionInvocation{FunctionName: "get-resources-of-type", Arguments: resourceTypeArgument("v1/Secret")},
+		}),
+		configMapName,
+		// No getter
+		testLinkAttribute("v1/ConfigMap", "metadata.name", "other-config", nil),
+	}
+
+	assert.Equal(t, []LinkCandidate{{
+		Needed:           needed[0],
+		Provided:         configMapName,
+		SetterInvocation: needed[0].Info.SetterInvocations[0],
+	}}, MatchNeededProvided(needed, provided))
+	assert.Empty(t, MatchNeededProvided(needed, provided[:1]))
+}
@@ -0,0 +1,203 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package yamlkit
+
+import (
+	"strings"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+// MockResourceProvider is a configurable ResourceProvider intended for unit tests of functions
+// that require a resource provider, such as the path visitors. By default it interprets documents
+// like Kubernetes resources: the resource type is <apiVersion>/<kind> and the resource name is
+// <metadata.namespace>/<metadata.name>. The category, type, and name of specific documents can be
+// overridden using the chainable With* methods. Each mock has its own path registry.
+type MockResourceProvider struct {
+	resourceCategories map[*gaby.YamlDoc]api.ResourceCategory
+	resourceTypes      map[*gaby.YamlDoc]api.ResourceType
+	resourceNames      map[*gaby.YamlDoc]api.ResourceName
+	contextPaths       map[string]string
+	contextExceptions  []api.ResourceType
+	typeDescription    string
+	nameSeparator      string
+	pathRegistry       api.AttributeNameToResourceTypeToPathToVisitorInfoType
+}
+
+const (
+	mockScopelessResourceNamePath = "metadata.name"
+	mockContextPathPrefix         = ".metadata.annotations."
+	mockContextKeyPrefix          = "confighub.com/"
+)
+
+// NewMockResourceProvider returns a MockResourceProvider with default behavior and an empty path registry.
+func NewMockResourceProvider() *MockResourceProvider {
+	return &MockResourceProvider{
+		resourceCategories: make(map[*gaby.YamlDoc]api.ResourceCategory),
+		resourceTypes:      make(map[*gaby.YamlDoc]api.ResourceType),
+		resourceNames:      make(map[*gaby.YamlDoc]api.ResourceName),
+		contextPaths:       make(map[string]string),
+		typeDescription:    "apiVersion/kind",
+		nameSeparator:      "-",
+		pathRegistry:       NewEmptyRegistry(),
+	}
+}
+
+// NewEmptyRegistry returns a fresh, empty path registry.
+func NewEmptyRegistry() api.AttributeNameToResourceTypeToPathToVisitorInfoType {
+	return make(api.AttributeNameToResourceTypeToPathToVisitorInfoType)
+}
+
+// WithResourceCategory overrides the resource category returned for the specified document.
+func (m *MockResourceProvider) WithResourceCategory(doc *gaby.YamlDoc, resourceCategory api.ResourceCategory) *MockResourceProvider {
+	m.resourceCategories[doc] = resourceCategory
+	return m
+}
+
+// WithResourceType overrides the resource type returned for the specified document.
+func (m *MockResourceProvider) WithResourceType(doc *gaby.YamlDoc, resourceType api.ResourceType) *MockResourceProvider {
+	m.resourceTypes[doc] = resourceType
+	return m
+}
+
+// WithResourceName overrides the resource name returned for the specified document.
+func (m *MockResourceProvider) WithResourceName(doc *gaby.YamlDoc, resourceName api.ResourceName) *MockResourceProvider {
+	m.resourceNames[doc] = resourceName
+	return m
+}
+
+// WithContextPath overrides the path returned for the specified context field.
+func (m *MockResourceProvider) WithContextPath(contextField, path string) *MockResourceProvider {
+	m.contextPaths[contextField] = path
+	return m
+}
+
+// WithContextPathExceptions sets the resource types that context isn't added to.
+func (m *MockResourceProvider) WithContextPathExceptions(resourceTypes ...api.ResourceType) *MockResourceProvider {
+	m.contextExceptions = resourceTypes
+	return m
+}
+
+// WithTypeDescription overrides the type description.
+func (m *MockResourceProvider) WithTypeDescription(typeDescription string) *MockResourceProvider {
+	m.typeDescription = typeDescription
+	return m
+}
+
+// WithNameSeparator overrides the name separator.
+func (m *MockResourceProvider) WithNameSeparator(nameSeparator string) *MockResourceProvider {
+	m.nameSeparator = nameSeparator
+	return m
+}
+
+// WithPathRegistry replaces the path registry.
+func (m *MockResourceProvider) WithPathRegistry(pathRegistry api.AttributeNameToResourceTypeToPathToVisitorInfoType) *MockResourceProvider {
+	m.pathRegistry = pathRegistry
+	return m
+}
+
+func (m *MockResourceProvider) DefaultResourceCategory() api.ResourceCategory {
+	return api.ResourceCategoryResource
+}
+
+func (m *MockResourceProvider) ResourceCategoryGetter(doc *gaby.YamlDoc) (api.ResourceCategory, error) {
+	resourceCategory, ok := m.resourceCategories[doc]
+	if ok {
+		return resourceCategory, nil
+	}
+	return m.DefaultResourceCategory(), nil
+}
+
+func (m *MockResourceProvider) ResourceTypeGetter(doc *gaby.YamlDoc) (api.ResourceType, error) {
+	resourceType, ok := m.resourceTypes[doc]
+	if ok {
+		return resourceType, nil
+	}
+	apiVersion, _, err := YamlSafePathGetValue[string](doc, api.ResolvedPath("apiVersion"), false)
+	if err != nil {
+		return "", err
+	}
+	kind, _, err := YamlSafePathGetValue[string](doc, api.ResolvedPath("kind"), false)
+	if err != nil {
+		return "", err
+	}
+	return api.ResourceType(apiVersion + "/" + kind), nil
+}
+
+func (m *MockResourceProvider) ResourceNameGetter(doc *gaby.YamlDoc) (api.ResourceName, error) {
+	resourceName, ok := m.resourceNames[doc]
+	if ok {
+		return resourceName, nil
+	}
+	namespace, _, err := YamlSafePathGetValue[string](doc, api.ResolvedPath("metadata.namespace"), true)
+	if err != nil {
+		return "", err
+	}
+	name, _, err := YamlSafePathGetValue[string](doc, api.ResolvedPath(mockScopelessResourceNamePath), false)
+	if err != nil {
+		return "", err
+	}
+	return api.ResourceName(namespace + "/" + name), nil
+}
+
+func (m *MockResourceProvider) RemoveScopeFromResourceName(resourceName api.ResourceName) api.ResourceName {
+	_, justResourceName, found := strings.Cut(string(resourceName), "/")
+	if !found {
+		return resourceName
+	}
+	return api.ResourceName(justResourceName)
+}
+
+func (m *MockResourceProvider) ScopelessResourceNamePath() api.ResolvedPath {
+	return api.ResolvedPath(mockScopelessResourceNamePath)
+}
+
+func (m *MockResourceProvider) SetResourceName(doc *gaby.YamlDoc, name string) error {
+	_, err := doc.SetP(name, mockScopelessResourceNamePath)
+	if err != nil {
+		return err
+	}
+	resourceName, ok := m.resourceNames[doc]
+	if ok {
+		scope, _, found := strings.Cut(string(resourceName), "/")
+		if !found {
+			scope = ""
+		}
+		m.resourceNames[doc] = api.ResourceName(scope + "/" + name)
+	}
+	return nil
+}
+
+func (m *MockResourceProvider) ResourceTypesAreSimilar(resourceTypeA, resourceTypeB api.ResourceType) bool {
+	return resourceTypeA == resourceTypeB
+}
+
+func (m *MockResourceProvider) TypeDescription() string {
+	return m.typeDescription
+}
+
+func (m *MockResourceProvider) NormalizeName(name string) string {
+	return name
+}
+
+func (m *MockResourceProvider) NameSeparator() string {
+	return m.nameSeparator
+}
+
+func (m *MockResourceProvider) ContextPath(contextField string) string {
+	path, ok := m.contextPaths[contextField]
+	if ok {
+		return path
+	}
+	return mockContextPathPrefix + EscapeDotsInPathSegment(mockContextKeyPrefix+contextField)
+}
+
+func (m *MockResourceProvider) ContextPathExceptions() []api.ResourceType {
+	return m.contextExceptions
+}
+
+func (m *MockResourceProvider) GetPathRegistry() api.AttributeNameToResourceTypeToPathToVisitorInfoType {
+	return m.pathRegistry
+}
@@ -0,0 +1,178 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package yamlkit
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/function/api"
+)
+
+func TestRegistrationConflicts(t *testing.T) {
+	const resourceType = api.ResourceType("apps/v1/Deployment")
+	const path = api.UnresolvedPath("spec.replicas")
+
+	type registration struct {
+		attributeName api.AttributeName
+		pathInfo      api.PathVisitorInfo
+	}
+	tests := []struct {
+		name              string
+		registrations     []registration
+		expectedConflicts int
+		// Conflicts under the same attribute name are also reported at registration time.
+		expectedRegistrationErrors int
+	}{
+		{
+			name: "identical registrations are merged",
+			registrations: []registration{
+				{api.AttributeNameGeneral, api.PathVisitorInfo{Path: path, AttributeName: api.AttributeNameGeneral, DataType: api.DataTypeInt}},
+				{api.AttributeNameGeneral, api.PathVisitorInfo{Path: path, AttributeName: api.AttributeNameGeneral, DataType: api.DataTypeInt}},
+			},
+			expectedConflicts: 0,
+		},
+		{
+			name: "mismatched data type under the same attribute",
+			registrations: []registration{
+				{api.AttributeNameGeneral, api.PathVisitorInfo{Path: path, AttributeName: api.AttributeNameGeneral, DataType: api.DataTypeInt}},
+				{api.AttributeNameGeneral, api.PathVisitorInfo{Path: path, AttributeName: api.AttributeNameGeneral, DataType: api.DataTypeString}},
+			},
+			expectedConflicts:          1,
+			expectedRegistrationErrors: 1,
+		},
+		{
+			name: "mismatched description under the same attribute",
+			registrations: []registration{
+				{api.AttributeNameDetail, api.PathVisitorInfo{Path: path, AttributeName: api.AttributeNameDetail, DataType: api.DataTypeInt, Info: &api.AttributeDetails{Description: "Replicas"}}},
+				{api.AttributeNameDetail, api.PathVisitorInfo{Path: path, AttributeName: api.AttributeNameDetail, DataType: api.DataTypeInt, Info: &api.AttributeDetails{Description: "Pods"}}},
+			},
+			expectedConflicts:          1,
+			expectedRegistrationErrors: 1,
+		},
+		{
+			name: "mismatched data type under different attributes",
+			registrations: []registration{
+				{api.AttributeNameGeneral, api.PathVisitorInfo{Path: path, AttributeName: api.AttributeNameGeneral, DataType: api.DataTypeInt}},
+				{api.AttributeNameDetail, api.PathVisitorInfo{Path: path, AttributeName: api.AttributeNameDetail, DataType: api.DataTypeString}},
+			},
+			expectedConflicts: 1,
+		},
+		{
+			name: "different attribute names with the same data type",
+			registrations: []registration{
+				{api.AttributeNameGeneral, api.PathVisitorInfo{Path: path, AttributeName: api.AttributeNameGeneral, DataType: api.DataTypeInt}},
+				{api.AttributeNameDetail, api.PathVisitorInfo{Path: path, AttributeName: api.AttributeNameDetail, DataType: api.DataTypeInt}},
+			},
+			expectedConflicts: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewMockResourceProvider()
+			registrationErrors := 0
+			for _, reg := range tt.registrations {
+				pathInfo := reg.pathInfo
+				err := RegisterPathsByAttributeName(
+					provider,
+					reg.attributeName,
+					resourceType,
+					api.PathToVisitorInfoType{path: &pathInfo},
+					nil,
+					nil,
+					false,
+				)
+				if err != nil {
+					var conflict *RegistrationConflictError
+					assert.True(t, errors.As(err, &conflict))
+					assert.Equal(t, reg.attributeName, conflict.AttributeName)
+					assert.Equal(t, resourceType, conflict.ResourceType)
+					assert.Equal(t, path, conflict.Path)
+					registrationErrors++
+				}
+			}
+
+			errs := ValidateRegistry(provider)
+			assert.Len(t, errs, tt.expectedConflicts)
+			for _, err := range errs {
+				var conflict *RegistrationConflictError
+				assert.True(t, errors.As(err, &conflict))
+				assert.Equal(t, path, conflict.Path)
+			}
+			assert.Equal(t, tt.expectedRegistrationErrors, registrationErrors)
+		})
+	}
+}
+
+func TestRegistrationConflictsAreScopedToRegistry(t *testing.T) {
+	const resourceType = api.ResourceType("apps/v1/Deployment")
+	const path = api.UnresolvedPath("spec.replicas")
+	conflicting, other := NewMockResourceProvider(), NewMockResourceProvider()
+	for _, provider := range []*MockResourceProvider{conflicting, other} {
+		MustRegisterPathsByAttributeName(provider, api.AttributeNameGeneral, resourceType,
+			api.PathToVisitorInfoType{path: {Path: path, AttributeName: api.AttributeNameGeneral, DataType: api.DataTypeInt}}, nil, nil, false)
+	}
+	mismatched := api.PathToVisitorInfoType{path: {Path: path, AttributeName: api.AttributeNameGeneral, DataType: api.DataTypeString}}
+	assert.Error(t, RegisterPathsByAttributeName(conflicting, api.AttributeNameGeneral, resourceType, mismatched, nil, nil, false))
+
+	assert.Len(t, ValidateRegistry(conflicting), 1)
+	assert.Empty(t, ValidateRegistry(other))
+
+	assert.Panics(t, func() {
+		MustRegisterPathsByAttributeName(other, api.AttributeNameGeneral, resourceType, mismatched, nil, nil, false)
+	})
+}
+
+// TestConcurrentRegistration registers and looks up paths from multiple goroutines, as happens
+// when multiple workers share a process. Run with -race to detect unsynchronized access.
+func TestConcurrentRegistration(t *testing.T) {
+	const goroutines = 8
+	const registrationsPerGoroutine = 50
+	providers := []*MockResourceProvider{NewMockResourceProvider(), NewMockResourceProvider()}
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			provider := providers[g%len(providers)]
+			for i := 0; i < registrationsPerGoroutine; i++ {
+				resourceType := api.ResourceType(fmt.Sprintf("example.com/v1/Kind%d", i%5))
+				path := api.UnresolvedPath(fmt.Sprintf("spec.items.*.field%d-%d", g, i))
+				err := RegisterPathsByAttributeName(
+					provider,
+					api.AttributeNameGeneral,
+					resourceType,
+					api.PathToVisitorInfoType{path: {Path: path, AttributeName: api.AttributeNameGeneral, DataType: api.DataTypeString}},
+					&api.FunctionInvocation{FunctionName: "get-field"},
+					&api.FunctionInvocation{FunctionName: "set-field"},
+					i%2 == 0,
+				)
+				assert.NoError(t, err)
+				// The returned registry is read without holding the lock
+				for _, pathInfos := range GetPathRegistryForAttributeName(provider, api.AttributeNameGeneral) {
+					for range pathInfos {
+					}
+				}
+				GetPathVisitorInfo(provider, resourceType, path)
+				ResourceTypesForAttribute(api.AttributeNameGeneral, provider)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	total := 0
+	for _, provider := range providers {
+		assert.Empty(t, ValidateRegistry(provider))
+		for _, pathInfos := range GetPathRegistryForAttributeName(provider, api.AttributeNameGeneral) {
+			total += len(pathInfos)
+		}
+	}
+	assert.Equal(t, goroutines*registrationsPerGoroutine, total)
+}
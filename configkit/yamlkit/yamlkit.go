@@ -6,12 +6,17 @@ package yamlkit
 
 import (
 	"fmt"
+	"maps"
 	"math"
+	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 
+	"github.com/alecthomas/participle/v2/lexer"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/errors/join"
 	"github.com/labstack/gommon/log"
@@ -33,8 +38,29 @@ const (
 	PlaceHolderBlockApplyString           = "confighubplaceholder"
 	DeprecatedPlaceHolderBlockApplyString = "replaceme" // will be removed
 	PlaceHolderBlockApplyInt              = 999999999
+	PlaceHolderBlockApplyFloat            = 999999999.0
 )
 
+// IsPlaceholderValue returns true if value is or contains a placeholder.
+func IsPlaceholderValue(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(v, PlaceHolderBlockApplyString) || strings.Contains(v, DeprecatedPlaceHolderBlockApplyString)
+	case int:
+		return v == PlaceHolderBlockApplyInt
+	case float64:
+		return v == PlaceHolderBlockApplyFloat
+	}
+	return false
+}
+
+// WithPlaceholders returns the attribute values that are or contain placeholders.
+func WithPlaceholders(attributeValues api.AttributeValueList) api.AttributeValueList {
+	return attributeValues.Filter(func(attributeValue api.AttributeValue) bool {
+		return IsPlaceholderValue(attributeValue.Value)
+	})
+}
+
 // This is not in a more general place because it is expected to be used after conversion of other
 // formats to YAML.
 
@@ -55,6 +81,36 @@ type ResourceProvider interface {
 	GetPathRegistry() api.AttributeNameToResourceTypeToPathToVisitorInfoType
 }
 
+// ContextPathExceptionProvider is implemented by resource providers with resource types that
+// context isn't added to.
+type ContextPathExceptionProvider interface {
+	ContextPathExceptions() []api.ResourceType
+}
+
+// LiveStateNormalizer is implemented by resource providers whose live state contains fields that
+// aren't set in configuration data, such as fields populated by the API server.
+type LiveStateNormalizer interface {
+	// RemoveLiveStateOnlyFields removes those fields from the live state documents so that they
+	// can be compared with configuration data.
+	RemoveLiveStateOnlyFields(liveState gaby.Container)
+}
+
+// LabelPathProvider is implemented by resource providers whose resources support labels.
+type LabelPathProvider interface {
+	// LabelPath returns the path of the label with the specified key.
+	LabelPath(labelKey string) string
+}
+
+// LabelPath returns the path of the label with the specified key, or the empty string if the
+// resources of the resource provider don't support labels.
+func LabelPath(resourceProvider ResourceProvider, labelKey string) string {
+	labelPathProvider, ok := resourceProvider.(LabelPathProvider)
+	if !ok {
+		return ""
+	}
+	return labelPathProvider.LabelPath(labelKey)
+}
+
 // LowerFirst lowercases the first character, which is useful for converting PascalCase to camelCase
 func LowerFirst(s string) string {
 	if len(s) == 0 {
@@ -68,6 +124,7 @@ func LowerFirst(s string) string {
 type ResourceTypeToPathPrefixSetType map[api.ResourceType]map[string]struct{}
 
 var resourceTypeToPathPrefixToIsWildcarded = make(ResourceTypeToPathPrefixSetType)
+var pathPrefixWildcardsLock sync.RWMutex
 
 // YamlSafePathGetDoc returns a document node at a fully resolved path and whether it was found.
 // An error indicates a parsing error. An error is also returned if the path is expected to exist.
@@ -84,13 +141,30 @@ func YamlSafePathGetDoc(
 		if notFoundOk {
 			return nil, false, nil
 		} else {
-			return nil, false, fmt.Errorf("%s not found", resolvedPathString)
+			return nil, false, &PathNotFoundError{Path: resolvedPath}
 		}
 	}
 	subdoc := doc.Path(resolvedPathString)
 	return subdoc, true, nil
 }
 
+// PathExists returns whether the specified path exists in the document. The path may contain
+// associative lookups and wildcards, in which case it exists if at least one resolved path exists.
+func PathExists(doc *gaby.YamlDoc, unresolvedPath api.UnresolvedPath) bool {
+	resolvedPaths, err := ResolveAssociativePaths(doc, unresolvedPath, "", false)
+	if err != nil {
+		// Not found is expected
+		return false
+	}
+	for _, resolvedPath := range resolvedPaths {
+		_, found, err := YamlSafePathGetDoc(doc, resolvedPath.Path, true)
+		if err == nil && found {
+			return true
+		}
+	}
+	return false
+}
+
 // YamlSafePathGetValueAnyType returns a value at a fully resolved path and whether it was found.
 // An error indicates a parsing error.
 func YamlSafePathGetValueAnyType(
@@ -107,7 +181,7 @@ func YamlSafePathGetValueAnyType(
 		if notFoundOk {
 			return result, false, nil
 		} else {
-			return result, false, fmt.Errorf("%s not found", string(resolvedPath))
+			return result, false, &PathNotFoundError{Path: resolvedPath}
 		}
 	}
 	result = subdoc.Data()
@@ -130,17 +204,30 @@ func YamlSafePathGetValue[T api.Scalar](
 		if notFoundOk {
 			return result, false, nil
 		} else {
-			return result, false, fmt.Errorf("%s not found", string(resolvedPath))
+			return result, false, &PathNotFoundError{Path: resolvedPath}
 		}
 	}
 	var ok bool
-	result, ok = subdoc.Data().(T)
+	result, ok = scalarValue[T](subdoc.Data())
 	if !ok {
-		return result, found, fmt.Errorf("value %v cannot be converted to %T", subdoc.Data(), result)
+		return result, found, newGoTypeMismatchError[T](api.ResourceInfo{}, resolvedPath, subdoc.Data())
 	}
 	return result, found, nil
 }
 
+// scalarValue returns value as a T. Ints are also accepted as float64s, since YAML floats may be
+// written without fractional parts.
+func scalarValue[T api.Scalar](value any) (T, bool) {
+	if intValue, isInt := value.(int); isInt {
+		var zero T
+		if _, isFloat := any(zero).(float64); isFloat {
+			return any(float64(intValue)).(T), true
+		}
+	}
+	result, ok := value.(T)
+	return result, ok
+}
+
 func GetResourceCategoryTypeName(doc *gaby.YamlDoc, resourceProvider ResourceProvider) (api.ResourceCategory, api.ResourceType, api.ResourceName, error) {
 	resourceInfo, err := GetResourceInfo(doc, resourceProvider)
 	if err != nil {
@@ -203,6 +290,92 @@ func VisitResources(parsedData gaby.Container, output any, resourceProvider Reso
 	return output, nil
 }
 
+// VisitResourcesParallel is like VisitResources, but it divides the documents into contiguous
+// batches that are processed concurrently by up to concurrency goroutines. If concurrency <= 1,
+// the documents are visited sequentially by VisitResources.
+//
+// If output is a slice, each batch accumulates into its own slice of the same type, and the batches
+// are concatenated in document order, so visitors that append to the output run in parallel.
+// Otherwise the shared output is protected by a mutex, which serializes the visitor calls while
+// the resource information of the documents is still determined concurrently. In either case
+// the visitor must not modify parsedData or unsynchronized state other than the output.
+func VisitResourcesParallel(parsedData gaby.Container, output any, resourceProvider ResourceProvider, visitor ResourceVisitorFunc, concurrency int) (any, error) {
+	if concurrency <= 1 || len(parsedData) <= 1 {
+		return VisitResources(parsedData, output, resourceProvider, visitor)
+	}
+	batchSize := (len(parsedData) + concurrency - 1) / concurrency
+	numBatches := (len(parsedData) + batchSize - 1) / batchSize
+	outputValue := reflect.ValueOf(output)
+	outputIsSlice := outputValue.Kind() == reflect.Slice
+
+	batchOutputs := make([]any, numBatches)
+	batchErrs := make([][]error, numBatches)
+	var outputMutex sync.Mutex
+	var wg sync.WaitGroup
+	for batch := 0; batch < numBatches; batch++ {
+		start := batch * batchSize
+		end := min(start+batchSize, len(parsedData))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var batchOutput any
+			if outputIsSlice {
+				batchOutput = reflect.MakeSlice(outputValue.Type(), 0, end-start).Interface()
+			}
+			for index := start; index < end; index++ {
+				doc := parsedData[index]
+				resourceInfo, err := GetResourceInfo(doc, resourceProvider)
+				if err != nil {
+					batchErrs[batch] = append(batchErrs[batch], err)
+					continue
+				}
+				if outputIsSlice {
+					newOutput, errs := visitor(doc, batchOutput, index, resourceInfo)
+					if len(errs) != 0 {
+						batchErrs[batch] = append(batchErrs[batch], errs...)
+					} else {
+						batchOutput = newOutput
+					}
+					continue
+				}
+				outputMutex.Lock()
+				newOutput, errs := visitor(doc, output, index, resourceInfo)
+				if len(errs) != 0 {
+					batchErrs[batch] = append(batchErrs[batch], errs...)
+				} else {
+					output = newOutput
+				}
+				outputMutex.Unlock()
+			}
+			batchOutputs[batch] = batchOutput
+		}()
+	}
+	wg.Wait()
+
+	multiErrs := []error{}
+	for batch := range batchErrs {
+		multiErrs = append(multiErrs, batchErrs[batch]...)
+	}
+	if outputIsSlice {
+		merged := outputValue
+		for _, batchOutput := range batchOutputs {
+			batchValue := reflect.ValueOf(batchOutput)
+			if !batchValue.IsValid() || batchValue.Type() != outputValue.Type() {
+				multiErrs = append(multiErrs, fmt.Errorf("%w: visitor output of type %T is not of type %T", ErrInternal, batchOutput, output))
+				continue
+			}
+			merged = reflect.AppendSlice(merged, batchValue)
+		}
+		output = merged.Interface()
+	}
+	if len(multiErrs) != 0 {
+		err := errors.WithStack(join.Join(multiErrs...))
+		log.Debugf("VisitResourcesParallel errors: %v", err)
+		return output, err
+	}
+	return output, nil
+}
+
 type ResourceNameToCategoryTypesMap map[api.ResourceName][]api.ResourceCategoryType
 type ResourceCategoryTypeToNamesMap map[api.ResourceCategoryType][]api.ResourceName
 type ResourceInfoToDocMap map[api.ResourceInfo]int
@@ -232,6 +405,38 @@ func ResourceAndCategoryTypeMaps(parsedData gaby.Container, resourceProvider Res
 	return resourceMap, categoryTypeMap, err
 }
 
+// GroupByResourceType partitions the provided list of parsed YAML documents by resource type
+// in a single pass. Each group contains its documents in their original order.
+func GroupByResourceType(parsedData gaby.Container, resourceProvider ResourceProvider) (map[api.ResourceType]gaby.Container, error) {
+	indices, err := GroupIndicesByResourceType(parsedData, resourceProvider)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[api.ResourceType]gaby.Container, len(indices))
+	for resourceType, typeIndices := range indices {
+		group := make(gaby.Container, 0, len(typeIndices))
+		for _, i := range typeIndices {
+			group = append(group, parsedData[i])
+		}
+		groups[resourceType] = group
+	}
+	return groups, nil
+}
+
+// GroupIndicesByResourceType is like GroupByResourceType, but each group contains the indices
+// of its documents in ascending order, for callers that modify parsedData.
+func GroupIndicesByResourceType(parsedData gaby.Container, resourceProvider ResourceProvider) (map[api.ResourceType][]int, error) {
+	groups := make(map[api.ResourceType][]int)
+	for i, doc := range parsedData {
+		resourceType, err := resourceProvider.ResourceTypeGetter(doc)
+		if err != nil {
+			return nil, err
+		}
+		groups[resourceType] = append(groups[resourceType], i)
+	}
+	return groups, nil
+}
+
 // ResourceToDocMap returns a map of all resources in the provided list of parsed YAML
 // documents to their document index.
 func ResourceToDocMap(parsedData gaby.Container, resourceProvider ResourceProvider) (resourceMap ResourceInfoToDocMap, err error) {
@@ -276,6 +481,85 @@ func PathIsResolved(path string, includeAt bool) bool {
 	return !strings.ContainsAny(path, "?*|")
 }
 
+// isIndexExpression returns whether the path segment is a negative array index, such as -1 for
+// the last element, or a range of indices, such as 1:3. Like other numeric segments, these are
+// only interpreted as indices when traversing arrays.
+func isIndexExpression(segment string) bool {
+	start, end, isRange := strings.Cut(segment, ":")
+	if !isRange {
+		return isNegativeInteger(segment)
+	}
+	return (start == "" || isInteger(start)) && (end == "" || isInteger(end))
+}
+
+func isInteger(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+func isNegativeInteger(s string) bool {
+	i, err := strconv.Atoi(s)
+	return err == nil && i < 0
+}
+
+// resolveIndexExpression converts a negative index or range of indices into the corresponding
+// non-negative indices of an array of the specified length. Ranges include the start index and
+// exclude the end index. Negative range bounds are relative to the end of the array, omitted
+// bounds default to the beginning and end of the array, and bounds outside the array are
+// clamped to it. A negative index outside the array is an error.
+func resolveIndexExpression(segment string, length int) ([]int, error) {
+	startString, endString, isRange := strings.Cut(segment, ":")
+	if !isRange {
+		index, err := strconv.Atoi(segment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index '%s'", segment)
+		}
+		if index < 0 {
+			index += length
+		}
+		if index < 0 || index >= length {
+			return nil, fmt.Errorf("index %s out of range for array of length %d", segment, length)
+		}
+		return []int{index}, nil
+	}
+
+	bound := func(boundString string, defaultValue int) (int, error) {
+		if boundString == "" {
+			return defaultValue, nil
+		}
+		value, err := strconv.Atoi(boundString)
+		if err != nil {
+			return 0, fmt.Errorf("invalid index range '%s'", segment)
+		}
+		if value < 0 {
+			value += length
+		}
+		return min(max(value, 0), length), nil
+	}
+	start, err := bound(startString, 0)
+	if err != nil {
+		return nil, err
+	}
+	end, err := bound(endString, length)
+	if err != nil {
+		return nil, err
+	}
+	indices := []int{}
+	for index := start; index < end; index++ {
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
+func pathHasIndexExpressions(segments []string) bool {
+	for _, segment := range segments {
+		if isIndexExpression(segment) {
+			return true
+		}
+	}
+	return false
+}
+
 // parseParameterInfo parses a @ prefixed segment to extract parameter value and name
 // Returns (segment, parameterValue, parameterName, error)
 func parseParameterInfo(segment string) (string, string, string, error) {
@@ -299,7 +583,8 @@ func parseParameterInfo(segment string) (string, string, string, error) {
 }
 
 // ResolveAssociativePaths resolves an associative path with associative lookups (?) and wildcards (*, *?, *@)
-// into specific resolved paths and discovered path parameters.
+// into specific resolved paths and discovered path parameters. Array index segments may also be negative,
+// such as -1 for the last element, or ranges, such as 1:3, which resolve to multiple paths.
 // See the documentation for api.UnresolvedPath for more details.
 func ResolveAssociativePaths(
 	doc *gaby.YamlDoc,
@@ -312,12 +597,12 @@ func ResolveAssociativePaths(
 	if path == "" {
 		return []ResolvedPathInfo{}, fmt.Errorf("path cannot be empty")
 	}
-	if PathIsResolved(path, true) {
-		return []ResolvedPathInfo{{Path: api.ResolvedPath(path)}}, nil
-	}
 	// DotPathToSlice converts escaped dots back to unescaped dots, so we need to convert
 	// them back when constructing the path
 	segments := gaby.DotPathToSlice(path)
+	if PathIsResolved(path, true) && !pathHasIndexExpressions(segments) {
+		return []ResolvedPathInfo{{Path: api.ResolvedPath(path)}}, nil
+	}
 	var constraintSegments []string
 	if resolvedPath != "" {
 		constraintSegments = gaby.DotPathToSlice(string(resolvedPath))
@@ -359,6 +644,13 @@ func ResolveAssociativePaths(
 
 		switch {
 		case strings.HasPrefix(segment, "*"):
+			if workList[0].ParentNode.IsNil() {
+				// Nothing to traverse beneath a null value, such as `annotations: null`
+				// Dequeue and continue
+				workList = workList[1:]
+				continue
+			}
+
 			// Gaby Search supports wildcards, at least for array sequence nodes,
 			// but I'm unsure how it returns multiple results. We resolve wildcards here.
 
@@ -488,6 +780,12 @@ func ResolveAssociativePaths(
 
 		default:
 			// Regular segment. Assume it matches the constraint, if any.
+			if workList[0].ParentNode.IsNil() && !upsert {
+				// Nothing to traverse beneath a null value, such as `annotations: null`
+				// Dequeue and continue
+				workList = workList[1:]
+				continue
+			}
 			var parameterName, parameterValue string
 			var err error
 
@@ -501,6 +799,26 @@ func ResolveAssociativePaths(
 				return []ResolvedPathInfo{}, err
 			}
 
+			// Negative indices and index ranges
+			if parentNode := workList[0].ParentNode; parentNode != nil && parentNode.IsArray() && isIndexExpression(segment) {
+				indices, err := resolveIndexExpression(segment, len(parentNode.Children()))
+				if err != nil {
+					return []ResolvedPathInfo{}, err
+				}
+				for _, index := range indices {
+					indexString := strconv.Itoa(index)
+					workList = append(workList, currentPosition{
+						ResolvedSegments:    append(slices.Clip(workList[0].ResolvedSegments), indexString),
+						CurrentSegmentIndex: workList[0].CurrentSegmentIndex + 1,
+						PathArguments:       slices.Clip(workList[0].PathArguments),
+						ParentNode:          parentNode.Index(index),
+					})
+				}
+				// Dequeue
+				workList = workList[1:]
+				continue
+			}
+
 			// This segment traversal doesn't need to have dots escaped
 			currentNode := workList[0].ParentNode.S(segment)
 			if currentNode == nil && !upsert {
@@ -522,7 +840,7 @@ func ResolveAssociativePaths(
 			if upsert {
 				allRemainingResolved := true
 				for i := workList[0].CurrentSegmentIndex; i < len(segments); i++ {
-					if !PathIsResolved(segments[i], false) {
+					if !PathIsResolved(segments[i], false) || isIndexExpression(segments[i]) {
 						allRemainingResolved = false
 						break
 					}
@@ -571,6 +889,8 @@ func IsNumber(s string) bool {
 }
 
 func prefixIsWildcarded(resourceType api.ResourceType, prefix string) bool {
+	pathPrefixWildcardsLock.RLock()
+	defer pathPrefixWildcardsLock.RUnlock()
 	_, present := resourceTypeToPathPrefixToIsWildcarded[resourceType]
 	if !present {
 		return false
@@ -621,6 +941,8 @@ func normalizePath(resourceType api.ResourceType, path api.UnresolvedPath, prese
 }
 
 func registerPathWildcards(resourceType api.ResourceType, path api.UnresolvedPath) {
+	pathPrefixWildcardsLock.Lock()
+	defer pathPrefixWildcardsLock.Unlock()
 	segments := gaby.DotPathToSlice(string(path))
 	prefix := ""
 	for i, segment := range segments {
@@ -740,13 +1062,62 @@ func setFunctionInvocationsInVisitorPathInfo(
 	}
 }
 
+// RegistrationConflictError is returned when a path is registered more than once under the same
+// attribute name and resource type with a specification that doesn't match the existing registration.
+type RegistrationConflictError struct {
+	AttributeName api.AttributeName
+	ResourceType  api.ResourceType
+	Path          api.UnresolvedPath
+	Existing      *api.PathVisitorInfo
+	New           *api.PathVisitorInfo
+}
+
+func (e *RegistrationConflictError) Error() string {
+	return fmt.Sprintf("conflicting registration of path %s for resource type %s under attribute %s: %v vs %v",
+		e.Path, e.ResourceType, e.AttributeName, *e.New, *e.Existing)
+}
+
+// pathRegistryState contains the lock protecting the path registry of a resource provider and the
+// conflicts detected while registering paths in it, keyed by the registered path visitor
+// specification that was retained. The registry may be shared by multiple workers in the same
+// process, so the lock must be held for reading when accessing the registry and for writing when
+// modifying it.
+type pathRegistryState struct {
+	lock      sync.RWMutex
+	conflicts map[*api.PathVisitorInfo][]*RegistrationConflictError
+}
+
+// pathRegistryStates maps each ResourceProvider to its *pathRegistryState.
+var pathRegistryStates sync.Map
+
+func getPathRegistryState(resourceProvider ResourceProvider) *pathRegistryState {
+	state, ok := pathRegistryStates.Load(resourceProvider)
+	if !ok {
+		state, _ = pathRegistryStates.LoadOrStore(resourceProvider, &pathRegistryState{
+			conflicts: make(map[*api.PathVisitorInfo][]*RegistrationConflictError),
+		})
+	}
+	return state.(*pathRegistryState)
+}
+
+// ReadPathRegistry calls read with the path registry of the resource provider while holding the
+// registry's lock for reading, so that paths aren't registered concurrently. read must not modify
+// the registry or retain it.
+func ReadPathRegistry(resourceProvider ResourceProvider, read func(pathRegistry api.AttributeNameToResourceTypeToPathToVisitorInfoType)) {
+	state := getPathRegistryState(resourceProvider)
+	state.lock.RLock()
+	defer state.lock.RUnlock()
+	read(resourceProvider.GetPathRegistry())
+}
+
 func registerPaths(
+	attributeName api.AttributeName,
 	registry api.ResourceTypeToPathToVisitorInfoType,
 	resourceType api.ResourceType,
 	pathInfos api.PathToVisitorInfoType,
 	getterFunctionInvocation *api.FunctionInvocation,
 	setterFunctionInvocation *api.FunctionInvocation,
-) {
+) []*RegistrationConflictError {
 	_, ok := registry[resourceType]
 	if !ok {
 		registry[resourceType] = make(api.PathToVisitorInfoType)
@@ -754,16 +1125,23 @@ func registerPaths(
 			registry[resourceType][path] = pathInfo
 			setFunctionInvocationsInVisitorPathInfo(pathInfo, getterFunctionInvocation, setterFunctionInvocation)
 		}
-		return
+		return nil
 	}
 
 	// Some paths could already be registered under the same attribute name.
 	// Example: resource references that could refer to multiple resource types.
+	var conflicts []*RegistrationConflictError
 	for path, newPathInfo := range pathInfos {
 		oldPathInfo, present := registry[resourceType][path]
 		if present {
 			if !VisitorInfoEqual(oldPathInfo, newPathInfo, false) {
-				log.Errorf("info mismatch for path %s: %v vs %v", newPathInfo.Path, newPathInfo, oldPathInfo)
+				conflicts = append(conflicts, &RegistrationConflictError{
+					AttributeName: attributeName,
+					ResourceType:  resourceType,
+					Path:          path,
+					Existing:      oldPathInfo,
+					New:           newPathInfo,
+				})
 			}
 			newPathInfo = oldPathInfo
 		} else {
@@ -771,6 +1149,7 @@ func registerPaths(
 		}
 		setFunctionInvocationsInVisitorPathInfo(newPathInfo, getterFunctionInvocation, setterFunctionInvocation)
 	}
+	return conflicts
 }
 
 // RegisterPathsByAttributeName registers the specified path visitor specifications under the
@@ -783,7 +1162,9 @@ func registerPaths(
 // is used for references to resource names. Other attribute names are used for specific setters and/or
 // getters, especially for attributes that appear in multiple resource types and/or locations.
 // Provided values are special in that they represent sources of values for attributes of the specified
-// attribute name, though they are logically distinct kinds of attributes.
+// attribute name, though they are logically distinct kinds of attributes. An error wrapping a
+// *RegistrationConflictError is returned for each path whose specification conflicts with an existing
+// registration. Conflicts are also retained so that they can be reported later by ValidateRegistry.
 func RegisterPathsByAttributeName(
 	resourceProvider ResourceProvider,
 	attributeName api.AttributeName,
@@ -792,7 +1173,10 @@ func RegisterPathsByAttributeName(
 	getterFunctionInvocation *api.FunctionInvocation,
 	setterFunctionInvocation *api.FunctionInvocation,
 	normalizePaths bool,
-) {
+) error {
+	state := getPathRegistryState(resourceProvider)
+	state.lock.Lock()
+	defer state.lock.Unlock()
 	pathRegistry := resourceProvider.GetPathRegistry()
 	_, present := pathRegistry[attributeName]
 	if !present {
@@ -809,30 +1193,142 @@ func RegisterPathsByAttributeName(
 			newPathInfos[fullyNormalizedPath] = &newPathInfo
 		}
 	}
-	registerPaths(
+	conflicts := registerPaths(
+		attributeName,
 		pathRegistry[attributeName],
 		resourceType,
 		newPathInfos,
 		getterFunctionInvocation,
 		setterFunctionInvocation,
 	)
+	if len(conflicts) == 0 {
+		return nil
+	}
+	multiErrs := make([]error, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		state.conflicts[conflict.Existing] = append(state.conflicts[conflict.Existing], conflict)
+		multiErrs = append(multiErrs, conflict)
+	}
+	return join.Join(multiErrs...)
+}
+
+// MustRegisterPathsByAttributeName calls RegisterPathsByAttributeName for registrations made when
+// functions are initialized, and panics if the paths conflict with existing registrations.
+func MustRegisterPathsByAttributeName(
+	resourceProvider ResourceProvider,
+	attributeName api.AttributeName,
+	resourceType api.ResourceType,
+	pathInfos api.PathToVisitorInfoType,
+	getterFunctionInvocation *api.FunctionInvocation,
+	setterFunctionInvocation *api.FunctionInvocation,
+	normalizePaths bool,
+) {
+	err := RegisterPathsByAttributeName(resourceProvider, attributeName, resourceType, pathInfos,
+		getterFunctionInvocation, setterFunctionInvocation, normalizePaths)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ValidateRegistry checks the path registry of the resource provider for conflicting registrations
+// and returns all conflicts found. Conflicts include paths registered more than once under the same
+// attribute name and resource type with mismatched specifications, and paths registered under different
+// attribute names for the same resource type with different data types or embedded accessors. It is
+// intended to be called after all paths have been registered so that registration bugs can be caught early,
+// and callers should treat any conflicts as fatal.
+func ValidateRegistry(resourceProvider ResourceProvider) []error {
+	state := getPathRegistryState(resourceProvider)
+	state.lock.RLock()
+	defer state.lock.RUnlock()
+	registryConflicts := state.conflicts
+	pathRegistry := resourceProvider.GetPathRegistry()
+	attributeNames := make([]api.AttributeName, 0, len(pathRegistry))
+	for attributeName := range pathRegistry {
+		attributeNames = append(attributeNames, attributeName)
+	}
+	sort.Slice(attributeNames, func(i, j int) bool { return attributeNames[i] < attributeNames[j] })
+
+	type resourceTypeAndPath struct {
+		resourceType api.ResourceType
+		path         api.UnresolvedPath
+	}
+	firstRegistrations := make(map[resourceTypeAndPath]*api.PathVisitorInfo)
+	var errs []error
+	for _, attributeName := range attributeNames {
+		resourceTypeToPaths := pathRegistry[attributeName]
+		resourceTypes := make([]api.ResourceType, 0, len(resourceTypeToPaths))
+		for resourceType := range resourceTypeToPaths {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+		sort.Slice(resourceTypes, func(i, j int) bool { return resourceTypes[i] < resourceTypes[j] })
+
+		for _, resourceType := range resourceTypes {
+			pathInfos := resourceTypeToPaths[resourceType]
+			paths := make([]api.UnresolvedPath, 0, len(pathInfos))
+			for path := range pathInfos {
+				paths = append(paths, path)
+			}
+			sort.Slice(paths, func(i, j int) bool { return paths[i] < paths[j] })
+
+			for _, path := range paths {
+				pathInfo := pathInfos[path]
+				for _, conflict := range registryConflicts[pathInfo] {
+					if conflict.AttributeName == attributeName && conflict.ResourceType == resourceType && conflict.Path == path {
+						errs = append(errs, conflict)
+					}
+				}
+
+				key := resourceTypeAndPath{resourceType: resourceType, path: path}
+				firstPathInfo, present := firstRegistrations[key]
+				if !present {
+					firstRegistrations[key] = pathInfo
+					continue
+				}
+				if firstPathInfo.DataType != pathInfo.DataType ||
+					firstPathInfo.EmbeddedAccessorType != pathInfo.EmbeddedAccessorType ||
+					firstPathInfo.EmbeddedAccessorConfig != pathInfo.EmbeddedAccessorConfig {
+					errs = append(errs, &RegistrationConflictError{
+						AttributeName: attributeName,
+						ResourceType:  resourceType,
+						Path:          path,
+						Existing:      firstPathInfo,
+						New:           pathInfo,
+					})
+				}
+			}
+		}
+	}
+	return errs
 }
 
 // GetPathRegistryForAttributeName returns the registry for the specified attribute to pass
-// to a visitor function.
+// to a visitor function. The registry is copied so that it can be used while paths are being
+// registered concurrently. The path visitor specifications are shared with the registry and
+// must not be modified.
 func GetPathRegistryForAttributeName(
 	resourceProvider ResourceProvider,
 	attributeName api.AttributeName,
 ) api.ResourceTypeToPathToVisitorInfoType {
-	pathRegistry := resourceProvider.GetPathRegistry()
-	var resourceTypeToPathToVisitorInfo api.ResourceTypeToPathToVisitorInfoType
-	resourceTypeToPathToVisitorInfo, _ = pathRegistry[attributeName]
-	return resourceTypeToPathToVisitorInfo
+	state := getPathRegistryState(resourceProvider)
+	state.lock.RLock()
+	defer state.lock.RUnlock()
+	resourceTypeToPathToVisitorInfo, present := resourceProvider.GetPathRegistry()[attributeName]
+	if !present {
+		return nil
+	}
+	registryCopy := make(api.ResourceTypeToPathToVisitorInfoType, len(resourceTypeToPathToVisitorInfo))
+	for resourceType, pathInfos := range resourceTypeToPathToVisitorInfo {
+		registryCopy[resourceType] = maps.Clone(pathInfos)
+	}
+	return registryCopy
 }
 
 // ResourceTypesForAttribute returns a list of resource types associated with the specified attribute.
 func ResourceTypesForAttribute(attributeName api.AttributeName, resourceProvider ResourceProvider) []api.ResourceType {
-	resourceTypeToPaths := GetPathRegistryForAttributeName(resourceProvider, attributeName)
+	state := getPathRegistryState(resourceProvider)
+	state.lock.RLock()
+	defer state.lock.RUnlock()
+	resourceTypeToPaths := resourceProvider.GetPathRegistry()[attributeName]
 	resourceTypes := make([]api.ResourceType, 0, len(resourceTypeToPaths))
 	for resourceType := range resourceTypeToPaths {
 		resourceTypes = append(resourceTypes, resourceType)
@@ -866,6 +1362,9 @@ func GetPathVisitorInfo(resourceProvider ResourceProvider, resourceType api.Reso
 	var visitorInfo *api.PathVisitorInfo
 	var resourceTypeToPathToVisitorInfo api.ResourceTypeToPathToVisitorInfoType
 	var present bool
+	state := getPathRegistryState(resourceProvider)
+	state.lock.RLock()
+	defer state.lock.RUnlock()
 	pathRegistry := resourceProvider.GetPathRegistry()
 	resourceTypeToPathToVisitorInfo, present = pathRegistry[api.AttributeNameGeneral]
 	if !present {
@@ -906,8 +1405,19 @@ func RegisterNeededPaths(
 	resourceType api.ResourceType,
 	pathInfos api.PathToVisitorInfoType,
 	setterFunctionInvocation *api.FunctionInvocation,
+) error {
+	return RegisterPathsByAttributeName(resourceProvider, api.AttributeNameNeededValue, resourceType, pathInfos, nil, setterFunctionInvocation, false)
+}
+
+// MustRegisterNeededPaths calls RegisterNeededPaths for registrations made when functions are
+// initialized, and panics if the paths conflict with existing registrations.
+func MustRegisterNeededPaths(
+	resourceProvider ResourceProvider,
+	resourceType api.ResourceType,
+	pathInfos api.PathToVisitorInfoType,
+	setterFunctionInvocation *api.FunctionInvocation,
 ) {
-	RegisterPathsByAttributeName(resourceProvider, api.AttributeNameNeededValue, resourceType, pathInfos, nil, setterFunctionInvocation, false)
+	MustRegisterPathsByAttributeName(resourceProvider, api.AttributeNameNeededValue, resourceType, pathInfos, nil, setterFunctionInvocation, false)
 }
 
 // RegisterProvidedPaths registers paths in the api.AttributeNameProvidedValue path registry.
@@ -922,8 +1432,61 @@ func RegisterProvidedPaths(
 	resourceType api.ResourceType,
 	pathInfos api.PathToVisitorInfoType,
 	getterFunctionInvocation *api.FunctionInvocation,
+) error {
+	return RegisterPathsByAttributeName(resourceProvider, api.AttributeNameProvidedValue, resourceType, pathInfos, getterFunctionInvocation, nil, false)
+}
+
+// MustRegisterProvidedPaths calls RegisterProvidedPaths for registrations made when functions are
+// initialized, and panics if the paths conflict with existing registrations.
+func MustRegisterProvidedPaths(
+	resourceProvider ResourceProvider,
+	resourceType api.ResourceType,
+	pathInfos api.PathToVisitorInfoType,
+	getterFunctionInvocation *api.FunctionInvocation,
 ) {
-	RegisterPathsByAttributeName(resourceProvider, api.AttributeNameProvidedValue, resourceType, pathInfos, getterFunctionInvocation, nil, false)
+	MustRegisterPathsByAttributeName(resourceProvider, api.AttributeNameProvidedValue, resourceType, pathInfos, getterFunctionInvocation, nil, false)
+}
+
+// ContextFields are the fields of the function context that are set at the context paths of
+// resources.
+var ContextFields = []string{"UnitSlug", "SpaceID", "RevisionNum"}
+
+// ContextPathForField returns the context path of the field as a path registry path.
+func ContextPathForField(resourceProvider ResourceProvider, contextField string) api.UnresolvedPath {
+	return api.UnresolvedPath(strings.TrimPrefix(resourceProvider.ContextPath(contextField), "."))
+}
+
+// RegisterContextPaths registers the context paths of the resource provider in the
+// api.AttributeNameContext path registry for all resource types. If the resource provider is a
+// ContextPathExceptionProvider, the resource types returned by ContextPathExceptions are registered
+// as TypeExceptions so that they're skipped when the paths are visited. Nothing is registered if the
+// resource provider doesn't support context.
+func RegisterContextPaths(resourceProvider ResourceProvider) error {
+	if resourceProvider.ContextPath(ContextFields[0]) == "" {
+		return nil
+	}
+	var typeExceptions map[api.ResourceType]struct{}
+	var exceptions []api.ResourceType
+	if exceptionProvider, ok := resourceProvider.(ContextPathExceptionProvider); ok {
+		exceptions = exceptionProvider.ContextPathExceptions()
+	}
+	for _, resourceType := range exceptions {
+		if typeExceptions == nil {
+			typeExceptions = map[api.ResourceType]struct{}{}
+		}
+		typeExceptions[resourceType] = struct{}{}
+	}
+	pathInfos := api.PathToVisitorInfoType{}
+	for _, contextField := range ContextFields {
+		path := ContextPathForField(resourceProvider, contextField)
+		pathInfos[path] = &api.PathVisitorInfo{
+			Path:           path,
+			AttributeName:  api.AttributeNameContext,
+			DataType:       api.DataTypeString,
+			TypeExceptions: typeExceptions,
+		}
+	}
+	return RegisterPathsByAttributeName(resourceProvider, api.AttributeNameContext, api.ResourceTypeAny, pathInfos, nil, nil, false)
 }
 
 // VisitorContext contains information passed to visitor functions for each path traversed.
@@ -970,6 +1533,8 @@ func VisitPaths[T api.Scalar](
 			case int:
 				// Use negative value to distinguish from 0
 				return visitor(doc, output, context, any(-PlaceHolderBlockApplyInt).(T))
+			case float64:
+				return visitor(doc, output, context, any(-PlaceHolderBlockApplyFloat).(T))
 			case bool:
 				// Use false since there's not a better option
 				return visitor(doc, output, context, any(false).(T))
@@ -977,11 +1542,11 @@ func VisitPaths[T api.Scalar](
 				return output, fmt.Errorf("unsupported type %T for upsert with nil currentDoc at path %s", defaultValue, string(context.Path))
 			}
 		}
-		currentValue, ok := currentDoc.Data().(T)
+		currentValue, ok := scalarValue[T](currentDoc.Data())
 		if ok {
 			return visitor(doc, output, context, currentValue)
 		}
-		return output, fmt.Errorf("value %v at path %s cannot be converted to %T", currentDoc.Data(), string(context.Path), currentValue)
+		return output, newGoTypeMismatchError[T](context.ResourceInfo, context.Path, currentDoc.Data())
 	}
 	return VisitPathsDoc(parsedData, resourceTypeToPaths, keys, output, resourceProvider, docVisitor, upsert)
 }
@@ -1221,7 +1786,7 @@ func appendGetterAndSetterArguments(details *api.AttributeDetails, arguments []a
 
 // GetPaths traverses the specified path patterns of the specified resource types and returns
 // an api.AttributeValueList containing the values and registered information about all of
-// the found attributes matching the path patterns. Use only for int and bool attributes.
+// the found attributes matching the path patterns. Use only for int, bool, and float attributes.
 // Use GetStringPaths for string attributes.
 func GetPaths[T api.Scalar](
 	parsedData gaby.Container,
@@ -1237,6 +1802,8 @@ func GetPaths[T api.Scalar](
 		dataType = api.DataTypeInt
 	case bool:
 		dataType = api.DataTypeBool
+	case float64:
+		dataType = api.DataTypeFloat
 	default:
 		// Invalid; strings supported in a dedicated function
 		return nil, fmt.Errorf("type %T not supported", zero)
@@ -1272,8 +1839,15 @@ func GetPathsAnyType(
 			}
 		case int:
 			currentDataType = api.DataTypeInt
+			if dataType == api.DataTypeFloat {
+				// Floats may be written without fractional parts
+				currentValue = float64(v)
+				currentDataType = api.DataTypeFloat
+			}
 		case bool:
 			currentDataType = api.DataTypeBool
+		case float64:
+			currentDataType = api.DataTypeFloat
 		default:
 			// Invalid; strings supported in a dedicated function
 			return output, fmt.Errorf("type %T not supported", v)
@@ -1281,7 +1855,14 @@ func GetPathsAnyType(
 
 		// Apply type filtering based on dataType parameter
 		if dataType != api.DataTypeNone && dataType != currentDataType {
-			return output, fmt.Errorf("value %v at path %s is of type %s but expected %s", currentValue, string(context.Path), currentDataType, dataType)
+			return output, &TypeMismatchError{
+				ResourceType: context.ResourceType,
+				ResourceName: context.ResourceName,
+				Path:         context.Path,
+				Value:        currentValue,
+				Want:         string(dataType),
+				Got:          string(currentDataType),
+			}
 		}
 
 		// Apply needed values filtering if requested
@@ -1297,6 +1878,10 @@ func GetPathsAnyType(
 				if intVal, ok := currentValue.(int); ok && intVal != PlaceHolderBlockApplyInt {
 					return output, nil // skip if there's already a value
 				}
+			case api.DataTypeFloat:
+				if floatVal, ok := currentValue.(float64); ok && floatVal != PlaceHolderBlockApplyFloat {
+					return output, nil // skip if there's already a value
+				}
 			case api.DataTypeBool:
 				// No placeholder for bool
 			}
@@ -1306,12 +1891,11 @@ func GetPathsAnyType(
 
 		visitorValues, ok := output.([]api.AttributeValue)
 		if !ok {
-			log.Debugf("couldn't convert output to []api.AttributeValue{}")
-			return output, fmt.Errorf("internal error") // TODO: define an error type
+			return output, fmt.Errorf("%w: visitor output of type %T at path %s of %s %q is not []api.AttributeValue", ErrInternal, output, string(context.Path), context.ResourceType, context.ResourceName)
 		}
 		var attributeValue api.AttributeValue
 		comment := currentDoc.GetComments()
-		attributeValue = api.AttributeValue{AttributeInfo: attr, Value: currentValue, Comment: comment}
+		attributeValue = api.AttributeValue{AttributeInfo: attr, Value: currentValue, Comment: comment, Source: api.AttributeSourceDesiredState}
 		attributeValue.Info = appendGetterAndSetterArguments(attributeValue.Info, context.Arguments)
 		visitorValues = append(visitorValues, attributeValue)
 		return visitorValues, nil
@@ -1323,8 +1907,7 @@ func GetPathsAnyType(
 	}
 	values, ok := output.([]api.AttributeValue)
 	if !ok {
-		log.Debugf("couldn't convert output to []api.AttributeValue{}")
-		return values, fmt.Errorf("internal error") // TODO: define an error type
+		return values, fmt.Errorf("%w: visitor output of type %T is not []api.AttributeValue", ErrInternal, output)
 	}
 	// TODO: Revisit. Did this for predictable order.
 	sort.Slice(values, attributeValueCompareFunction(values))
@@ -1334,8 +1917,8 @@ func GetPathsAnyType(
 // GetNeededPaths traverses the specified path patterns of the specified resource types and returns
 // an api.AttributeValueList containing the values and registered information about all of
 // the found attributes matching the path patterns that Need values. Currently "Need" is determined
-// using placeholder values, 999999999 (9 9s) for integers. Use only for ints. Bools have no
-// placeholder value.
+// using placeholder values, 999999999 (9 9s) for integers and floats. Use only for ints and
+// floats. Bools have no placeholder value.
 // Use GetNeededStringPaths for strings.
 func GetNeededPaths[T api.Scalar](
 	parsedData gaby.Container,
@@ -1351,6 +1934,8 @@ func GetNeededPaths[T api.Scalar](
 		dataType = api.DataTypeInt
 	case bool:
 		dataType = api.DataTypeBool
+	case float64:
+		dataType = api.DataTypeFloat
 	default:
 		// Invalid; strings supported in a dedicated function
 		return nil, fmt.Errorf("type %T not supported", zero)
@@ -1388,7 +1973,8 @@ func GetNeededStringPaths(
 
 // UpdateStringPathsFunction traverses the specified path patterns of the specified resource types.
 // The updater function simply needs to return the new attribute value. It can also inject fields
-// embedded in strings using registered embedded accessors.
+// embedded in strings using registered embedded accessors. Literal (|) and folded (>) block
+// scalars keep their style when the new value is also multi-line.
 func UpdateStringPathsFunction(
 	parsedData gaby.Container,
 	resourceTypeToPaths api.ResourceTypeToPathToVisitorInfoType,
@@ -1585,20 +2171,198 @@ func FindYAMLPathsByValue(parsedData gaby.Container, resourceProvider ResourcePr
 	return paths
 }
 
+// FindYAMLLeafPaths returns the paths and values of all of the leaves of the resources: scalars
+// and empty maps and arrays. Maps and arrays at maxDepth path segments are returned as leaves
+// rather than traversed, unless maxDepth is 0. Keys are escaped so that the paths can be passed to
+// other functions.
+func FindYAMLLeafPaths(parsedData gaby.Container, resourceProvider ResourceProvider, maxDepth int) api.AttributeValueList {
+	var paths api.AttributeValueList
+
+	var traverse func(path string, depth int, doc *gaby.YamlDoc, resourceInfo *api.ResourceInfo)
+	traverse = func(path string, depth int, doc *gaby.YamlDoc, resourceInfo *api.ResourceInfo) {
+		if maxDepth == 0 || depth < maxDepth {
+			if children := doc.ChildrenMap(); len(children) > 0 {
+				for key, child := range children {
+					currentPath := EscapeDotsInPathSegment(key)
+					if path != "" {
+						currentPath = path + "." + currentPath
+					}
+					traverse(currentPath, depth+1, child, resourceInfo)
+				}
+				return
+			}
+			// Empty maps also have non-nil children
+			if arrayChildren := doc.Children(); len(arrayChildren) > 0 {
+				for index, child := range arrayChildren {
+					traverse(path+"."+strconv.Itoa(index), depth+1, child, resourceInfo)
+				}
+				return
+			}
+		}
+		if path != "" {
+			paths = append(paths, attributeValueForPath(api.ResolvedPath(path), resourceInfo, doc.Data()))
+		}
+	}
+
+	visitor := func(doc *gaby.YamlDoc, _ any, _ int, resourceInfo *api.ResourceInfo) (any, []error) {
+		traverse("", 0, doc, resourceInfo)
+		return nil, []error{}
+	}
+	VisitResources(parsedData, nil, resourceProvider, visitor)
+
+	sort.Slice(paths, attributeValueCompareFunction(paths))
+
+	return paths
+}
+
+// YQError is returned by EvalYQExpression when the yq expression can't be parsed or evaluated.
+type YQError struct {
+	// Expression is the yq expression that failed.
+	Expression string
+	// InputLength is the length of the input YAML in bytes.
+	InputLength int
+	// Position is the 1-based character position in Expression at which the error was
+	// detected, or 0 if the yq library didn't report a position.
+	Position int
+	// Err is the error returned by the yq library.
+	Err error
+}
+
+func (e *YQError) Error() string {
+	var message string
+	var lexerError *lexer.Error
+	if errors.As(e.Err, &lexerError) {
+		message = lexerError.Message()
+	} else {
+		message = e.Err.Error()
+	}
+	if e.Position > 0 {
+		return fmt.Sprintf("yq expression %q failed at position %d: %s (input length %d)", e.Expression, e.Position, message, e.InputLength)
+	}
+	return fmt.Sprintf("yq expression %q failed: %s (input length %d)", e.Expression, message, e.InputLength)
+}
+
+func (e *YQError) Unwrap() error {
+	return e.Err
+}
+
+func newYQError(expr string, yamlString string, err error) *YQError {
+	yqError := &YQError{
+		Expression:  expr,
+		InputLength: len(yamlString),
+		Err:         err,
+	}
+	var lexerError *lexer.Error
+	if errors.As(err, &lexerError) {
+		yqError.Position = lexerError.Pos.Column
+	}
+	return yqError
+}
+
+// YQOptions specifies how EvalYQExpressionWithOptions evaluates a yq expression and encodes the
+// result. The zero value matches the behavior of EvalYQExpression.
+type YQOptions struct {
+	// Indent is the number of spaces used to indent the result. Zero selects the yq default of 2.
+	Indent int
+	// StripComments removes comments from the result. By default comments are preserved.
+	StripComments bool
+	// PerDocument evaluates the expression on each document of the input separately, like
+	// yq eval, rather than on all of the documents together, like yq eval-all. Expressions
+	// that only reference the current document produce the same results either way, but
+	// evaluation per document doesn't require all of the documents to be decoded at once.
+	PerDocument bool
+}
+
+// yqInitOnce guards the initialization of the yq library's global expression parser and logger.
+var yqInitOnce sync.Once
+
+func initYQ() {
+	yqInitOnce.Do(func() {
+		yqlogger.SetLevel(yqlogger.WARNING, "yq-lib")
+		yqlib.InitExpressionParser()
+	})
+}
+
+// YQExpressionHasAssignment parses the yq expression and returns true if it contains an assignment,
+// such as = or |=, or the del operator. Errors are of type *YQError.
+func YQExpressionHasAssignment(expr string) (bool, error) {
+	initYQ()
+	node, err := yqlib.ExpressionParser.ParseExpression(expr)
+	if err != nil {
+		return false, newYQError(expr, "", err)
+	}
+	return yqNodeHasAssignment(node), nil
+}
+
+func yqNodeHasAssignment(node *yqlib.ExpressionNode) bool {
+	if node == nil {
+		return false
+	}
+	if node.Operation != nil && node.Operation.OperationType != nil {
+		operationType := node.Operation.OperationType.Type
+		// ASSIGN_VARIABLE binds a variable with as, which doesn't change the data
+		if operationType == "DELETE" || (strings.Contains(operationType, "ASSIGN") && operationType != "ASSIGN_VARIABLE") {
+			return true
+		}
+	}
+	return yqNodeHasAssignment(node.LHS) || yqNodeHasAssignment(node.RHS)
+}
+
+// EvalYQExpression evaluates the yq expression on yamlString and returns the result. The expression
+// is parsed before the input is decoded so that syntax errors are reported without evaluation.
+// Errors are of type *YQError.
 func EvalYQExpression(expr string, yamlString string) (string, error) {
-	yqlogger.SetLevel(yqlogger.WARNING, "yq-lib")
-	encoder := yqlib.NewYamlEncoder(yqlib.ConfiguredYamlPreferences)
-	decoder := yqlib.NewYamlDecoder(yqlib.ConfiguredYamlPreferences)
-	result, err := yqlib.NewStringEvaluator().EvaluateAll(expr, yamlString, encoder, decoder)
+	return EvalYQExpressionWithOptions(expr, yamlString, YQOptions{})
+}
+
+// EvalYQExpressionWithOptions evaluates the yq expression on yamlString as specified by options
+// and returns the result. Errors are of type *YQError.
+func EvalYQExpressionWithOptions(expr string, yamlString string, options YQOptions) (string, error) {
+	initYQ()
+	_, err := yqlib.ExpressionParser.ParseExpression(expr)
 	if err != nil {
-		return "", err
+		return "", newYQError(expr, yamlString, err)
+	}
+	evalExpr := expr
+	if options.StripComments {
+		evalExpr = "(" + expr + `) | ... comments = ""`
+	}
+	preferences := yqlib.ConfiguredYamlPreferences.Copy()
+	if options.Indent > 0 {
+		preferences.Indent = options.Indent
+	}
+	encoder := yqlib.NewYamlEncoder(preferences)
+	decoder := yqlib.NewYamlDecoder(preferences)
+	var result string
+	if options.PerDocument {
+		result, err = yqlib.NewStringEvaluator().Evaluate(evalExpr, yamlString, encoder, decoder)
+	} else {
+		result, err = yqlib.NewStringEvaluator().EvaluateAll(evalExpr, yamlString, encoder, decoder)
+	}
+	if err != nil {
+		return "", newYQError(expr, yamlString, err)
 	}
 	return result, nil
 }
 
+// mutationDiffSerializeOptions are used to compare values when computing mutations, so that
+// changes to comments alone aren't considered mutations.
+var mutationDiffSerializeOptions = gaby.SerializeOptions{AddTrailingNewline: true}
+
+// sortedChildKeys returns the keys of the children of a map in sorted order.
+func sortedChildKeys(children map[string]*gaby.YamlDoc) []string {
+	keys := make([]string, 0, len(children))
+	for key := range children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // ComputeMutationsForDocs determines the edits that have been performed to transform the previousDoc
 // into modifiedDoc. The resulting mutations are associated with the provided functionIndex.
-// The pathMutationMap is modified in place.
+// The pathMutationMap is modified in place. Map keys are processed in sorted order, so the
+// mutations are the same for the same inputs.
 func ComputeMutationsForDocs(rootPath string, previousDoc *gaby.YamlDoc, modifiedDoc *gaby.YamlDoc, functionIndex int64, pathMutationMap api.MutationMap) {
 	// TODO: Determine whether there should be any error conditions.
 
@@ -1653,8 +2417,9 @@ func ComputeMutationsForDocs(rootPath string, previousDoc *gaby.YamlDoc, modifie
 				continue // process next stack element
 			}
 
-			// Process all modified children
-			for key, modifiedChild := range modifiedChildren {
+			// Process all modified children in sorted order so that the mutations are deterministic
+			for _, key := range sortedChildKeys(modifiedChildren) {
+				modifiedChild := modifiedChildren[key]
 				var currentPath string
 				if path != "" {
 					currentPath = path + "." + EscapeDotsInPathSegment(key)
@@ -1684,7 +2449,8 @@ func ComputeMutationsForDocs(rootPath string, previousDoc *gaby.YamlDoc, modifie
 			}
 
 			// Remaining previousChildren must have been deleted
-			for key, previousChild := range previousChildren {
+			for _, key := range sortedChildKeys(previousChildren) {
+				previousChild := previousChildren[key]
 				var currentPath string
 				if path != "" {
 					currentPath = path + "." + EscapeDotsInPathSegment(key)
@@ -1713,7 +2479,8 @@ func ComputeMutationsForDocs(rootPath string, previousDoc *gaby.YamlDoc, modifie
 				// modifiedDoc is an empty map.
 				if len(previousChildren) != 0 {
 					// The map children were deleted.
-					for key, previousChild := range previousChildren {
+					for _, key := range sortedChildKeys(previousChildren) {
+						previousChild := previousChildren[key]
 						var currentPath string
 						if path != "" {
 							currentPath = path + "." + EscapeDotsInPathSegment(key)
@@ -1788,8 +2555,11 @@ func ComputeMutationsForDocs(rootPath string, previousDoc *gaby.YamlDoc, modifie
 				index++
 			}
 		} else {
-			// modifiedDoc must be a value. Compare the contents.
-			if modifiedDoc.String() != previousDoc.String() {
+			// modifiedDoc must be a value. Compare the contents, ignoring comments. Values that
+			// can't be serialized are reported as changed.
+			modifiedValue, modifiedErr := modifiedDoc.StringWithOptions(mutationDiffSerializeOptions)
+			previousValue, previousErr := previousDoc.StringWithOptions(mutationDiffSerializeOptions)
+			if modifiedErr != nil || previousErr != nil || modifiedValue != previousValue {
 				pathMutationMap[api.ResolvedPath(path)] = api.MutationInfo{
 					MutationType: api.MutationTypeUpdate,
 					Index:        functionIndex,
@@ -1807,10 +2577,47 @@ const OriginalNameAnnotation = "confighub.com/OriginalName"
 
 var originalNamePath = "metadata.annotations." + EscapeDotsInPathSegment(OriginalNameAnnotation)
 
+// lineCount returns the number of lines in the serialized document.
+func lineCount(doc *gaby.YamlDoc) int {
+	return strings.Count(doc.String(), "\n") + 1
+}
+
+// commonPrefixLength returns the length in bytes of the longest common prefix of a and b.
+func commonPrefixLength(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
 // ComputeMutations performs a kind of diff between two configuration Units where it determines what
 // modifications were made at the resource/element level and at the path level. They are recorded in a
 // way that can be accumulated and updated over subsequent edits and transformations.
+// Resources whose category, type, and name are unchanged are matched directly; the similarity
+// search is only performed for the remaining resources, such as those that were renamed.
 func ComputeMutations(previousParsedData, modifiedParsedData gaby.Container, functionIndex int64, resourceProvider ResourceProvider) (api.ResourceMutationList, error) {
+	return computeMutations(previousParsedData, modifiedParsedData, functionIndex, resourceProvider, true)
+}
+
+// resourceIdentity contains the attributes used to match resources across revisions.
+type resourceIdentity struct {
+	category     api.ResourceCategory
+	resourceType api.ResourceType
+	name         api.ResourceName
+	nameOnly     api.ResourceName
+}
+
+// exactMatchKey returns the key used to match resources whose category, type, and name are unchanged.
+func (r resourceIdentity) exactMatchKey() resourceIdentity {
+	return resourceIdentity{category: r.category, resourceType: r.resourceType, name: r.name}
+}
+
+// computeMutations implements ComputeMutations. If useExactMatches is false, all resources are
+// matched using the similarity search, which is slower but otherwise produces the same result.
+func computeMutations(previousParsedData, modifiedParsedData gaby.Container, functionIndex int64, resourceProvider ResourceProvider, useExactMatches bool) (api.ResourceMutationList, error) {
 	// There are limits in how accurately we can determine the correspondence between resources/elements
 	// across revisions. Once resources/elements change too significantly, they will be determined to be
 	// distinct. Some properties, such as the ResourceCategory, ResourceType, and ResourceName, carry more
@@ -1835,6 +2642,24 @@ func ComputeMutations(previousParsedData, modifiedParsedData gaby.Container, fun
 	// We could start with either the previous docs or the modified docs. I chose the latter, since the
 	// modified docs represent the new/current content.
 
+	// Resource attributes are needed repeatedly by the similarity search, so extract them once.
+	previousResources := make([]resourceIdentity, len(previousParsedData))
+	exactMatches := map[resourceIdentity][]int{}
+	for previousDocIndex, previousDoc := range previousParsedData {
+		previousResourceCategory, previousResourceType, previousResourceName, err := GetResourceCategoryTypeName(previousDoc, resourceProvider)
+		if err != nil {
+			return nil, err
+		}
+		previousResources[previousDocIndex] = resourceIdentity{
+			category:     previousResourceCategory,
+			resourceType: previousResourceType,
+			name:         previousResourceName,
+			nameOnly:     resourceProvider.RemoveScopeFromResourceName(previousResourceName),
+		}
+		key := previousResources[previousDocIndex].exactMatchKey()
+		exactMatches[key] = append(exactMatches[key], previousDocIndex)
+	}
+
 	mutations := api.ResourceMutationList{}
 	previousDocMatched := make([]bool, len(previousParsedData))
 	minUnmatchedPreviousDocIndex := 0
@@ -1847,79 +2672,101 @@ func ComputeMutations(previousParsedData, modifiedParsedData gaby.Container, fun
 		}
 		modifiedResourceNameOnly := resourceProvider.RemoveScopeFromResourceName(modifiedResourceName)
 
-		// Check whether the "next" resource obviously matches in the previous doc list.
-		// If not, we need to search for it. We could make maps of type and name to index,
-		// but that wouldn't work in the case of type changes, so I'm punting on that for
-		// simplicity for now.
-
-		// Search previousDocMatched starting with minUnmatchedPreviousDocIndex.
+		// Check whether the resource obviously matches one in the previous doc list, which is the
+		// common case. If not, we need to search for it, since its type or name may have changed.
 		matchIndex := -1
 		bestMatchScore := math.MaxFloat64
 		// TODO: Determine a reasonable threshold. If the name of a Namespace changes, that's one line in 4, or 0.25.
 		// It's also possible that we should always consider another resource of the same type as the same resource
 		// if there's only one.
 		maxMatchScore := 1.0
-		numDocLines := strings.Count(modifiedParsedData.String(), "\n")
 		var pathMutationMap api.MutationMap
-		minMutationLength := math.MaxInt
+		bestCommonPrefixLength := -1
 		aliases := map[api.ResourceName]struct{}{}
 		aliasesWithoutScopes := map[api.ResourceName]struct{}{}
-		for previousDocIndex := minUnmatchedPreviousDocIndex; previousDocIndex < len(previousDocMatched); previousDocIndex++ {
-			previousDoc := previousParsedData[previousDocIndex]
-			previousResourceCategory, previousResourceType, previousResourceName, err := GetResourceCategoryTypeName(previousDoc, resourceProvider)
-			if err != nil {
-				return nil, err
-			}
-			if previousResourceCategory != modifiedResourceCategory {
-				continue
-			}
-			// TODO: favor exact match
-			if !resourceProvider.ResourceTypesAreSimilar(previousResourceType, modifiedResourceType) {
-				continue
-			}
-
-			// Do a deep diff
-			tmpMutationMap := api.MutationMap{}
-			ComputeMutationsForDocs("", previousDoc, modifiedDoc, functionIndex, tmpMutationMap)
-
-			// TODO: favor exact match
-			// TODO: special-case changes of a placeholder scope to a non-placeholder scope
-			previousResourceNameOnly := resourceProvider.RemoveScopeFromResourceName(previousResourceName)
-			if previousResourceName == modifiedResourceName || previousResourceNameOnly == modifiedResourceNameOnly {
+		if useExactMatches {
+			key := resourceIdentity{category: modifiedResourceCategory, resourceType: modifiedResourceType, name: modifiedResourceName}
+			for _, previousDocIndex := range exactMatches[key] {
+				if previousDocMatched[previousDocIndex] {
+					continue
+				}
 				matchIndex = previousDocIndex
 				bestMatchScore = 0.0
-				pathMutationMap = tmpMutationMap
-				// Re-initialize aliases and aliasesWithoutScopes
-				aliases = map[api.ResourceName]struct{}{
-					previousResourceName: {},
-				}
-				aliasesWithoutScopes = map[api.ResourceName]struct{}{
-					previousResourceNameOnly: {},
-				}
+				pathMutationMap = api.MutationMap{}
+				ComputeMutationsForDocs("", previousParsedData[previousDocIndex], modifiedDoc, functionIndex, pathMutationMap)
+				aliases[previousResources[previousDocIndex].name] = struct{}{}
+				aliasesWithoutScopes[previousResources[previousDocIndex].nameOnly] = struct{}{}
 				break
 			}
-			// TODO: Figure out a better way to determine name changes.
-			// Kustomize records name changes when they occur at the field mutation level, but
-			// that doesn't work for out-of-band (non-filter) changes.
-			// https://github.com/kubernetes-sigs/kustomize/blob/616c08480583c24b1828111a6e9e720735676979/api/filters/prefix/prefix.go#L29
-			// https://github.com/kubernetes-sigs/kustomize/blob/616c08480583c24b1828111a6e9e720735676979/api/filters/suffix/suffix.go#L29
-			// TODO: special-case changes of the placeholder name to a non-placeholder name
-			// TODO: special-case matching indices and/or clones by setting the score to the minimum matching score
-			// TODO: special-case the only resource of matching type
-			// TODO: some attributes, like container names and images, are more important than others
-			// TODO: Do we need a name kernel pattern to deal with common prefixes and suffixes?
-			// TODO: take into account the number of subpaths (leaf values) of the paths in the map
-			if len(tmpMutationMap) < minMutationLength {
-				minMutationLength = len(tmpMutationMap)
-				pathMutationMap = tmpMutationMap
-				bestMatchScore = float64(minMutationLength) / float64(numDocLines)
-				matchIndex = previousDocIndex
-				// Re-initialize aliases and aliasesWithoutScopes
-				aliases = map[api.ResourceName]struct{}{
-					previousResourceName: {},
+		}
+
+		// Otherwise search the unmatched previous docs starting with minUnmatchedPreviousDocIndex.
+		if matchIndex < 0 {
+			modifiedDocLines := lineCount(modifiedDoc)
+			for previousDocIndex := minUnmatchedPreviousDocIndex; previousDocIndex < len(previousDocMatched); previousDocIndex++ {
+				if previousDocMatched[previousDocIndex] {
+					continue
+				}
+				previousDoc := previousParsedData[previousDocIndex]
+				previousResourceCategory := previousResources[previousDocIndex].category
+				previousResourceType := previousResources[previousDocIndex].resourceType
+				previousResourceName := previousResources[previousDocIndex].name
+				if previousResourceCategory != modifiedResourceCategory {
+					continue
+				}
+				// TODO: favor exact match
+				if !resourceProvider.ResourceTypesAreSimilar(previousResourceType, modifiedResourceType) {
+					continue
+				}
+
+				// Do a deep diff
+				tmpMutationMap := api.MutationMap{}
+				ComputeMutationsForDocs("", previousDoc, modifiedDoc, functionIndex, tmpMutationMap)
+
+				// TODO: favor exact match
+				// TODO: special-case changes of a placeholder scope to a non-placeholder scope
+				previousResourceNameOnly := previousResources[previousDocIndex].nameOnly
+				if previousResourceName == modifiedResourceName || previousResourceNameOnly == modifiedResourceNameOnly {
+					matchIndex = previousDocIndex
+					bestMatchScore = 0.0
+					pathMutationMap = tmpMutationMap
+					// Re-initialize aliases and aliasesWithoutScopes
+					aliases = map[api.ResourceName]struct{}{
+						previousResourceName: {},
+					}
+					aliasesWithoutScopes = map[api.ResourceName]struct{}{
+						previousResourceNameOnly: {},
+					}
+					break
 				}
-				aliasesWithoutScopes = map[api.ResourceName]struct{}{
-					previousResourceNameOnly: {},
+				// TODO: Figure out a better way to determine name changes.
+				// Kustomize records name changes when they occur at the field mutation level, but
+				// that doesn't work for out-of-band (non-filter) changes.
+				// https://github.com/kubernetes-sigs/kustomize/blob/616c08480583c24b1828111a6e9e720735676979/api/filters/prefix/prefix.go#L29
+				// https://github.com/kubernetes-sigs/kustomize/blob/616c08480583c24b1828111a6e9e720735676979/api/filters/suffix/suffix.go#L29
+				// TODO: special-case changes of the placeholder name to a non-placeholder name
+				// TODO: special-case matching indices and/or clones by setting the score to the minimum matching score
+				// TODO: special-case the only resource of matching type
+				// TODO: some attributes, like container names and images, are more important than others
+				// TODO: Do we need a name kernel pattern to deal with common prefixes and suffixes?
+				// TODO: take into account the number of subpaths (leaf values) of the paths in the map
+				// The number of changes is normalized by the size of the larger of the two documents being
+				// compared so that small changes in large documents aren't favored over equivalent changes
+				// in small documents. Ties are broken in favor of the most similar name.
+				score := float64(len(tmpMutationMap)) / float64(max(lineCount(previousDoc), modifiedDocLines))
+				commonPrefixLength := commonPrefixLength(string(previousResourceName), string(modifiedResourceName))
+				if score < bestMatchScore || (score == bestMatchScore && commonPrefixLength > bestCommonPrefixLength) {
+					bestMatchScore = score
+					bestCommonPrefixLength = commonPrefixLength
+					pathMutationMap = tmpMutationMap
+					matchIndex = previousDocIndex
+					// Re-initialize aliases and aliasesWithoutScopes
+					aliases = map[api.ResourceName]struct{}{
+						previousResourceName: {},
+					}
+					aliasesWithoutScopes = map[api.ResourceName]struct{}{
+						previousResourceNameOnly: {},
+					}
 				}
 			}
 		}
@@ -2005,11 +2852,10 @@ func ComputeMutations(previousParsedData, modifiedParsedData gaby.Container, fun
 		}
 
 		previousDoc := previousParsedData[minUnmatchedPreviousDocIndex]
-		previousResourceCategory, previousResourceType, previousResourceName, err := GetResourceCategoryTypeName(previousDoc, resourceProvider)
-		if err != nil {
-			return nil, err
-		}
-		previousResourceNameOnly := resourceProvider.RemoveScopeFromResourceName(previousResourceName)
+		previousResourceCategory := previousResources[minUnmatchedPreviousDocIndex].category
+		previousResourceType := previousResources[minUnmatchedPreviousDocIndex].resourceType
+		previousResourceName := previousResources[minUnmatchedPreviousDocIndex].name
+		previousResourceNameOnly := previousResources[minUnmatchedPreviousDocIndex].nameOnly
 		mutations = append(mutations, api.ResourceMutation{
 			Resource: api.ResourceInfo{
 				ResourceType:             previousResourceType,
@@ -4,6 +4,9 @@
 package yamlkit
 
 import (
+	"fmt"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,23 +15,6 @@ import (
 	"github.com/confighub/sdk/third_party/gaby"
 )
 
-// testResourceProvider is a minimal ResourceProvider for testing purposes only.
-type testResourceProvider struct{}
-
-func (testResourceProvider) ResourceTypeGetter(doc *gaby.YamlDoc) (api.ResourceType, error) {
-	apiVersion, _, _ := YamlSafePathGetValue[string](doc, api.ResolvedPath("apiVersion"), false)
-	kind, _, _ := YamlSafePathGetValue[string](doc, api.ResolvedPath("kind"), false)
-	return api.ResourceType(apiVersion + "/" + kind), nil
-}
-
-func (testResourceProvider) ResourceNameGetter(doc *gaby.YamlDoc) (api.ResourceName, error) {
-	namespace, _, _ := YamlSafePathGetValue[string](doc, api.ResolvedPath("metadata.namespace"), true)
-	name, _, _ := YamlSafePathGetValue[string](doc, api.ResolvedPath("metadata.name"), false)
-	return api.ResourceName(namespace + "/" + name), nil
-}
-
-var testProvider = testResourceProvider{}
-
 func TestResolveAssociation(t *testing.T) {
 	// YAML fixture
 	yamlFixture := `apiVersion: apps/v1
@@ -189,6 +175,65 @@ subjects:
 	assert.Equal(t, api.ResolvedPath("subjects.1.namespace"), results[1].Path)
 }
 
+const indexExpressionFixture = `spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1.0
+      - name: sidecar
+        image: sidecar:1.0
+      - name: proxy
+        image: proxy:1.0
+`
+
+func TestResolveAssociativePaths_NegativeIndex(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(indexExpressionFixture))
+	assert.NoError(t, err)
+	results, err := ResolveAssociativePaths(docs[0], api.UnresolvedPath("spec.template.spec.containers.-1.image"), "", false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, api.ResolvedPath("spec.template.spec.containers.2.image"), results[0].Path)
+
+	results, err = ResolveAssociativePaths(docs[0], api.UnresolvedPath("spec.template.spec.containers.-3.name"), "", false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, api.ResolvedPath("spec.template.spec.containers.0.name"), results[0].Path)
+}
+
+func TestResolveAssociativePaths_NegativeIndexOutOfRange(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(indexExpressionFixture))
+	assert.NoError(t, err)
+	_, err = ResolveAssociativePaths(docs[0], api.UnresolvedPath("spec.template.spec.containers.-4.image"), "", false)
+	assert.ErrorContains(t, err, "index -4 out of range for array of length 3")
+}
+
+func TestResolveAssociativePaths_IndexRange(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(indexExpressionFixture))
+	assert.NoError(t, err)
+	results, err := ResolveAssociativePaths(docs[0], api.UnresolvedPath("spec.template.spec.containers.1:3.image"), "", false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, api.ResolvedPath("spec.template.spec.containers.1.image"), results[0].Path)
+	assert.Equal(t, api.ResolvedPath("spec.template.spec.containers.2.image"), results[1].Path)
+
+	// Ranges are clamped to the array and may be open-ended or relative to the end
+	results, err = ResolveAssociativePaths(docs[0], api.UnresolvedPath("spec.template.spec.containers.-2:10.name"), "", false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, api.ResolvedPath("spec.template.spec.containers.1.name"), results[0].Path)
+	assert.Equal(t, api.ResolvedPath("spec.template.spec.containers.2.name"), results[1].Path)
+
+	results, err = ResolveAssociativePaths(docs[0], api.UnresolvedPath("spec.template.spec.containers.:1.name"), "", false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, api.ResolvedPath("spec.template.spec.containers.0.name"), results[0].Path)
+
+	results, err = ResolveAssociativePaths(docs[0], api.UnresolvedPath("spec.template.spec.containers.5:7.name"), "", false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 0)
+}
+
 func TestResolveAssociation_NamedAssociation(t *testing.T) {
 	// YAML fixture with multiple containers
 	yamlFixture := `apiVersion: apps/v1
@@ -451,3 +496,489 @@ spec:
 	assert.Equal(t, "container-name", results[2].PathArguments[0].ParameterName)
 	assert.Equal(t, "container-three", results[2].PathArguments[0].Value)
 }
+
+func TestResolveAssociativePaths_NullNode(t *testing.T) {
+	yamlFixture := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example-deployment
+  annotations: null
+  labels:
+spec:
+  template:
+    spec:
+      containers: null
+`
+	docs, err := gaby.ParseAll([]byte(yamlFixture))
+	assert.NoError(t, err)
+	for _, path := range []string{
+		"metadata.annotations.*",
+		"metadata.annotations.*@:key",
+		"metadata.annotations.@key:key=example.com/owner.value",
+		"metadata.labels.*",
+		"spec.template.spec.containers.*.image",
+		"spec.template.spec.containers.?name=main.image",
+	} {
+		results, err := ResolveAssociativePaths(docs[0], api.UnresolvedPath(path), "", false)
+		assert.NoError(t, err, path)
+		assert.Empty(t, results, path)
+	}
+
+	resourceTypeToPaths := api.ResourceTypeToPathToVisitorInfoType{
+		api.ResourceType("apps/v1/Deployment"): {
+			"metadata.annotations.*@:annotation-key": {
+				Path:          "metadata.annotations.*@:annotation-key",
+				AttributeName: api.AttributeNameGeneral,
+				DataType:      api.DataTypeString,
+			},
+		},
+	}
+	visited := 0
+	visitor := func(_ *gaby.YamlDoc, output any, _ VisitorContext, _ *gaby.YamlDoc) (any, error) {
+		visited++
+		return output, nil
+	}
+	_, err = VisitPathsDoc(docs, resourceTypeToPaths, []any{}, nil, NewMockResourceProvider(), visitor, false)
+	assert.NoError(t, err)
+	assert.Zero(t, visited)
+}
+
+func TestMockResourceProvider_Paths(t *testing.T) {
+	yamlFixture := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example-deployment
+  namespace: example
+spec:
+  template:
+    spec:
+      containers:
+      - name: container-one
+        image: nginx:1.14.2
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+spec:
+  image: redis:5.0
+`
+	docs, err := gaby.ParseAll([]byte(yamlFixture))
+	assert.NoError(t, err)
+	provider := NewMockResourceProvider().
+		WithResourceType(docs[1], api.ResourceType("Widget")).
+		WithResourceName(docs[1], api.ResourceName("custom-widget"))
+
+	resourceType, err := provider.ResourceTypeGetter(docs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, api.ResourceType("apps/v1/Deployment"), resourceType)
+	resourceName, err := provider.ResourceNameGetter(docs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, api.ResourceName("example/example-deployment"), resourceName)
+	resourceType, err = provider.ResourceTypeGetter(docs[1])
+	assert.NoError(t, err)
+	assert.Equal(t, api.ResourceType("Widget"), resourceType)
+
+	resourceTypeToPaths := api.ResourceTypeToPathToVisitorInfoType{
+		api.ResourceType("apps/v1/Deployment"): {
+			"spec.template.spec.containers.*.image": {
+				Path:          "spec.template.spec.containers.*.image",
+				AttributeName: api.AttributeNameGeneral,
+				DataType:      api.DataTypeString,
+			},
+		},
+		api.ResourceType("Widget"): {
+			"spec.image": {
+				Path:          "spec.image",
+				AttributeName: api.AttributeNameGeneral,
+				DataType:      api.DataTypeString,
+			},
+		},
+	}
+	values, err := GetStringPaths(docs, resourceTypeToPaths, []any{}, provider)
+	assert.NoError(t, err)
+	assert.Len(t, values, 2)
+	// Values are sorted by resource type
+	assert.Equal(t, api.ResourceName("custom-widget"), values[0].ResourceName)
+	assert.Equal(t, "redis:5.0", values[0].Value)
+	assert.Equal(t, "nginx:1.14.2", values[1].Value)
+
+	err = UpdateStringPaths(docs, resourceTypeToPaths, []any{}, provider, "busybox:latest", false)
+	assert.NoError(t, err)
+	values, err = GetStringPaths(docs, resourceTypeToPaths, []any{}, provider)
+	assert.NoError(t, err)
+	assert.Len(t, values, 2)
+	for _, value := range values {
+		assert.Equal(t, "busybox:latest", value.Value)
+	}
+}
+
+func TestUpdateStringPathsPreservesBlockScalarStyle(t *testing.T) {
+	yamlFixture := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: scripts
+data:
+  startup: |
+    #!/bin/sh
+    echo starting
+`
+	docs, err := gaby.ParseAll([]byte(yamlFixture))
+	assert.NoError(t, err)
+	resourceTypeToPaths := api.ResourceTypeToPathToVisitorInfoType{
+		api.ResourceType("v1/ConfigMap"): {
+			"data.startup": {
+				Path:          "data.startup",
+				AttributeName: api.AttributeNameGeneral,
+				DataType:      api.DataTypeString,
+			},
+		},
+	}
+	err = UpdateStringPaths(docs, resourceTypeToPaths, []any{}, NewMockResourceProvider(), "#!/bin/sh\necho starting\nexec server\n", false)
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: scripts
+data:
+  startup: |
+    #!/bin/sh
+    echo starting
+    exec server
+`, docs[0].String())
+}
+
+func TestEvalYQExpression(t *testing.T) {
+	input := "metadata:\n  name: example\n"
+	result, err := EvalYQExpression(".metadata.name", input)
+	assert.NoError(t, err)
+	assert.Equal(t, "example\n", result)
+
+	tests := []struct {
+		expr     string
+		position int
+		message  string
+	}{
+		{".a[", 0, "could not find matching `]`"},
+		{"(.a", 0, "missing close bracket"},
+		{".a ==", 0, "'==' expects 2 args"},
+		{"@@@", 1, "invalid input text"},
+		{".a | badop(1)", 6, `invalid input text "badop(1)"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := EvalYQExpression(tt.expr, input)
+			var yqError *YQError
+			if !assert.ErrorAs(t, err, &yqError) {
+				return
+			}
+			assert.Equal(t, tt.expr, yqError.Expression)
+			assert.Equal(t, len(input), yqError.InputLength)
+			assert.Equal(t, tt.position, yqError.Position)
+			assert.ErrorContains(t, err, tt.message)
+			assert.ErrorContains(t, err, tt.expr)
+		})
+	}
+}
+
+func TestEvalYQExpressionWithOptions(t *testing.T) {
+	input := `# Deployment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web # the name
+spec:
+  replicas: 2
+---
+# Service
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+	// Comments and document separators survive a no-op
+	result, err := EvalYQExpression(".", input)
+	assert.NoError(t, err)
+	assert.Equal(t, input, result)
+	result, err = EvalYQExpressionWithOptions(".", input, YQOptions{PerDocument: true})
+	assert.NoError(t, err)
+	assert.Equal(t, input, result)
+
+	result, err = EvalYQExpressionWithOptions(".", input, YQOptions{StripComments: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 2\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\n", result)
+
+	result, err = EvalYQExpressionWithOptions(`select(.kind == "Deployment")`, input, YQOptions{Indent: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, `# Deployment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+    name: web # the name
+spec:
+    replicas: 2
+`, result)
+
+	// Expressions are evaluated on all documents together unless PerDocument is set
+	result, err = EvalYQExpression("[.] | length", input)
+	assert.NoError(t, err)
+	assert.Equal(t, "2\n", result)
+	result, err = EvalYQExpressionWithOptions("[.] | length", input, YQOptions{PerDocument: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n1\n", result)
+
+	// Errors are reported for the original expression
+	_, err = EvalYQExpressionWithOptions(".a[", input, YQOptions{StripComments: true})
+	var yqError *YQError
+	if assert.ErrorAs(t, err, &yqError) {
+		assert.Equal(t, ".a[", yqError.Expression)
+	}
+}
+
+func TestPathExists(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(`spec:
+  containers:
+  - name: app
+  - name: sidecar
+    image: sidecar:1.0
+`))
+	assert.NoError(t, err)
+	doc := docs[0]
+	assert.True(t, PathExists(doc, "spec.containers.0.name"))
+	assert.False(t, PathExists(doc, "spec.containers.0.image"))
+	assert.True(t, PathExists(doc, "spec.containers.*.image"))
+	assert.True(t, PathExists(doc, "spec.containers.?name=sidecar.image"))
+	assert.False(t, PathExists(doc, "spec.containers.?name=app.image"))
+	assert.False(t, PathExists(doc, "spec.volumes.*.name"))
+}
+
+func TestGroupByResourceType(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: c
+`))
+	assert.NoError(t, err)
+	groups, err := GroupByResourceType(docs, NewMockResourceProvider())
+	assert.NoError(t, err)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, gaby.Container{docs[0], docs[2]}, groups["v1/ConfigMap"])
+	assert.Equal(t, gaby.Container{docs[1]}, groups["apps/v1/Deployment"])
+	indices, err := GroupIndicesByResourceType(docs, NewMockResourceProvider())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, indices["v1/ConfigMap"])
+	assert.Equal(t, []int{1}, indices["apps/v1/Deployment"])
+
+	// Resource type getter errors are propagated
+	docs, err = gaby.ParseAll([]byte("metadata:\n  name: no-type\n"))
+	assert.NoError(t, err)
+	_, err = GroupByResourceType(docs, NewMockResourceProvider())
+	assert.Error(t, err)
+}
+
+func TestWithPlaceholders(t *testing.T) {
+	attributeValue := func(path api.ResolvedPath, value any) api.AttributeValue {
+		return api.AttributeValue{
+			AttributeInfo: api.AttributeInfo{AttributeIdentifier: api.AttributeIdentifier{Path: path}},
+			Value:         value,
+		}
+	}
+	attributeValues := api.AttributeValueList{
+		attributeValue("metadata.namespace", PlaceHolderBlockApplyString),
+		attributeValue("metadata.name", "app"),
+		attributeValue("spec.replicas", PlaceHolderBlockApplyInt),
+		attributeValue("spec.minReadySeconds", 10),
+		attributeValue("spec.paused", false),
+		attributeValue("spec.template.spec.containers.0.image", "registry.example.com/"+PlaceHolderBlockApplyString+":1.0"),
+		attributeValue("spec.template.spec.containers.1.image", DeprecatedPlaceHolderBlockApplyString),
+	}
+	assert.Equal(t, api.AttributeValueList{
+		attributeValues[0],
+		attributeValues[2],
+		attributeValues[5],
+		attributeValues[6],
+	}, WithPlaceholders(attributeValues))
+	assert.Empty(t, WithPlaceholders(attributeValues[3:5]))
+}
+
+func visitResourcesTestData(tb testing.TB, numDocs int) gaby.Container {
+	var sb strings.Builder
+	for i := 0; i < numDocs; i++ {
+		fmt.Fprintf(&sb, `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app-%d
+  namespace: default
+spec:
+  replicas: %d
+  template:
+    spec:
+      containers:
+      - name: main
+        image: nginx:1.27
+`, i, i%5)
+	}
+	docs, err := gaby.ParseAll([]byte(sb.String()))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return docs
+}
+
+// serializingVisitor appends the resource name and serialized document to the output
+func serializingVisitor(doc *gaby.YamlDoc, output any, _ int, resourceInfo *api.ResourceInfo) (any, []error) {
+	return append(output.(api.ResourceList), api.Resource{ResourceInfo: *resourceInfo, ResourceBody: doc.String()}), nil
+}
+
+func TestVisitResourcesParallel(t *testing.T) {
+	docs := visitResourcesTestData(t, 37)
+	provider := NewMockResourceProvider()
+	expected, err := VisitResources(docs, api.ResourceList{}, provider, serializingVisitor)
+	assert.NoError(t, err)
+
+	for _, concurrency := range []int{0, 1, 2, 5, 37, 100} {
+		output, err := VisitResourcesParallel(docs, api.ResourceList{}, provider, serializingVisitor, concurrency)
+		assert.NoError(t, err)
+		// The results are in document order
+		assert.Equal(t, expected, output, "concurrency %d", concurrency)
+	}
+
+	// Non-slice outputs are shared by all of the visitor calls
+	countVisitor := func(_ *gaby.YamlDoc, output any, index int, _ *api.ResourceInfo) (any, []error) {
+		counts := output.(map[string]int)
+		counts["docs"]++
+		counts["indexes"] += index
+		return counts, nil
+	}
+	output, err := VisitResourcesParallel(docs, map[string]int{}, provider, countVisitor, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"docs": 37, "indexes": 37 * 36 / 2}, output)
+}
+
+func TestVisitResourcesParallel_Errors(t *testing.T) {
+	docs := visitResourcesTestData(t, 10)
+	visitor := func(doc *gaby.YamlDoc, output any, index int, resourceInfo *api.ResourceInfo) (any, []error) {
+		if index%3 == 0 {
+			return output, []error{fmt.Errorf("failed on %s", resourceInfo.ResourceName)}
+		}
+		return serializingVisitor(doc, output, index, resourceInfo)
+	}
+	output, err := VisitResourcesParallel(docs, api.ResourceList{}, NewMockResourceProvider(), visitor, 3)
+	assert.EqualError(t, err, "failed on default/app-0\nfailed on default/app-3\nfailed on default/app-6\nfailed on default/app-9")
+	assert.Len(t, output, 6)
+}
+
+func BenchmarkVisitResources(b *testing.B) {
+	docs := visitResourcesTestData(b, 500)
+	provider := NewMockResourceProvider()
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = VisitResources(docs, api.ResourceList{}, provider, serializingVisitor)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = VisitResourcesParallel(docs, api.ResourceList{}, provider, serializingVisitor, runtime.GOMAXPROCS(0))
+		}
+	})
+}
+
+func TestComputeMutationsExactMatches(t *testing.T) {
+	provider := NewMockResourceProvider()
+	previousDocs := visitResourcesTestData(t, 20)
+	modifiedDocs := visitResourcesTestData(t, 21)
+	// Rename a resource, change its scope, modify one, and delete one
+	_, err := modifiedDocs[3].SetP("app-3-renamed", "metadata.name")
+	assert.NoError(t, err)
+	_, err = modifiedDocs[7].SetP("other", "metadata.namespace")
+	assert.NoError(t, err)
+	_, err = modifiedDocs[11].SetP("nginx:1.28", "spec.template.spec.containers.0.image")
+	assert.NoError(t, err)
+	modifiedDocs = append(modifiedDocs[:15], modifiedDocs[16:]...)
+
+	expected, err := computeMutations(previousDocs, modifiedDocs, 1, provider, false)
+	assert.NoError(t, err)
+	mutations, err := ComputeMutations(previousDocs, modifiedDocs, 1, provider)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, mutations)
+
+	mutationTypes := map[api.ResourceName]api.MutationType{}
+	for _, mutation := range mutations {
+		mutationTypes[mutation.Resource.ResourceName] = mutation.ResourceMutationInfo.MutationType
+	}
+	assert.Equal(t, api.MutationTypeUpdate, mutationTypes["default/app-3-renamed"])
+	assert.Equal(t, api.MutationTypeUpdate, mutationTypes["other/app-7"])
+	assert.Equal(t, api.MutationTypeUpdate, mutationTypes["default/app-11"])
+	assert.Equal(t, api.MutationTypeNone, mutationTypes["default/app-12"])
+	// The deleted resource is similar enough to the new one to be considered the same resource
+	assert.Equal(t, api.MutationTypeUpdate, mutationTypes["default/app-20"])
+	_, matched := mutationAliases(mutations, "default/app-20")["default/app-15"]
+	assert.True(t, matched)
+}
+
+func mutationAliases(mutations api.ResourceMutationList, resourceName api.ResourceName) map[api.ResourceName]struct{} {
+	for _, mutation := range mutations {
+		if mutation.Resource.ResourceName == resourceName {
+			return mutation.Aliases
+		}
+	}
+	return nil
+}
+
+func BenchmarkComputeMutations(b *testing.B) {
+	provider := NewMockResourceProvider()
+	// When an early resource is deleted, every subsequent resource is compared with it by the
+	// similarity search, even though the names of the resources are unchanged.
+	previousDocs := visitResourcesTestData(b, 200)
+	modifiedDocs := visitResourcesTestData(b, 200)[1:]
+	for i := 0; i < len(modifiedDocs); i += 10 {
+		if _, err := modifiedDocs[i].SetP("nginx:1.28", "spec.template.spec.containers.0.image"); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.Run("exact-matches", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = computeMutations(previousDocs, modifiedDocs, 0, provider, true)
+		}
+	})
+	b.Run("similarity-search", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = computeMutations(previousDocs, modifiedDocs, 0, provider, false)
+		}
+	})
+}
+
+func TestYQExpressionHasAssignment(t *testing.T) {
+	for expr, expected := range map[string]bool{
+		".spec.replicas":                      false,
+		`.metadata.name == "a=b"`:             false,
+		".spec.replicas >= 3":                 false,
+		`.a as $x | $x`:                       false,
+		".spec.replicas = 3":                  true,
+		`.metadata.name = "a=b"`:              true,
+		".spec.replicas |= . + 1":             true,
+		".spec.replicas += 1":                 true,
+		`(select(.kind == "A") | .x) = 1`:     true,
+		"del(.metadata.annotations)":          true,
+		`.metadata.labels.app style="double"`: true,
+	} {
+		hasAssignment, err := YQExpressionHasAssignment(expr)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, expected, hasAssignment, expr)
+	}
+
+	_, err := YQExpressionHasAssignment(".a = (")
+	var yqErr *YQError
+	assert.ErrorAs(t, err, &yqErr)
+}
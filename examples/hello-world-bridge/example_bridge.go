@@ -18,6 +18,9 @@ import (
 // ExampleBridge implements the Bridge interface
 // This is a simple example bridge that demonstrates the basic structure
 type ExampleBridge struct {
+	// This example doesn't support Plan
+	api.PlanNotSupportedWorker
+
 	// Add any fields you need for your bridge implementation
 	name    string
 	baseDir string
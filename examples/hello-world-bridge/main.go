@@ -6,15 +6,16 @@ package main
 
 import (
 	"log"
+	"log/slog"
 	"os"
 
 	"github.com/confighub/sdk/worker"
 )
 
 func main() {
-	// Note: The SDK currently uses controller-runtime logging internally.
-	// Until the SDK provides a way to configure logging, you may see warnings about
-	// uninitialized loggers. These can be safely ignored for this example.
+	// Note: The SDK uses controller-runtime logging internally. Set a logger with
+	// log.SetLogger from sigs.k8s.io/controller-runtime/pkg/log, or provide one to the
+	// bridges using the Logger field of worker.ConnectorOptions.
 
 	// For your own logging, you can use standard log package as shown in this example
 	log.Printf("[INFO] Starting hello-world-bridge example...")
@@ -40,12 +41,14 @@ func main() {
 
 	// The connector is the "engine" of the worker. You register bridges and functions with it.
 	// Then you start it and it connects to ConfigHub and offers its local capabilities to your ConfigHub org.
+	// The event callback is notified when the connection to ConfigHub changes state. It is called
+	// in its own goroutine and must not block.
 	connector, err := worker.NewConnector(worker.ConnectorOptions{
 		WorkerID:         os.Getenv("CONFIGHUB_WORKER_ID"),
 		WorkerSecret:     os.Getenv("CONFIGHUB_WORKER_SECRET"),
 		ConfigHubURL:     os.Getenv("CONFIGHUB_URL"),
 		BridgeDispatcher: &bridgeDispatcher,
-	})
+	}.WithConnectorEventCallback(logConnectorEvent))
 
 	if err != nil {
 		log.Fatalf("Failed to create connector: %v", err)
@@ -57,3 +60,12 @@ func main() {
 		log.Fatalf("Failed to start connector: %v", err)
 	}
 }
+
+// logConnectorEvent logs connection state transitions using slog.
+func logConnectorEvent(event worker.ConnectorEvent) {
+	if event.Err != nil {
+		slog.Error("Connector event", "type", event.Type, "timestamp", event.Timestamp, "error", event.Err)
+		return
+	}
+	slog.Info("Connector event", "type", event.Type, "timestamp", event.Timestamp)
+}
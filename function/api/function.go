@@ -35,13 +35,15 @@ func HashConfigData(data []byte) RevisionHash {
 var SupportedToolchains = map[workerapi.ToolchainType]string{
 	workerapi.ToolchainKubernetesYAML:      "/kubernetes",
 	workerapi.ToolchainAppConfigProperties: "/properties",
+	workerapi.ToolchainAppConfigYAML:       "/appconfig-yaml",
+	workerapi.ToolchainAnsibleVars:         "/ansible-vars",
 	workerapi.ToolchainOpenTofuHCL:         "/opentofu",
 }
 
 // TODO: Unify DataType and OutputType.
 
 // DataType represents the data type of a function parameter or configuration attribute.
-// The data type can be a scalar type (string, int, bool), a structured format (JSON, YAML),
+// The data type can be a scalar type (string, int, bool, float), a structured format (JSON, YAML),
 // or a well known structured data type (e.g., AttributeValueList).
 type DataType string
 
@@ -51,6 +53,7 @@ const (
 	DataTypeString = DataType("string")
 	DataTypeInt    = DataType("int")
 	DataTypeBool   = DataType("bool")
+	DataTypeFloat  = DataType("float")
 	DataTypeEnum   = DataType("enum")
 
 	// Additional Storage types
@@ -169,6 +172,7 @@ const (
 	AttributeNameSubdomain               = AttributeName("subdomain")
 	AttributeNameDetail                  = AttributeName("detail")
 	AttributeNameDefaultName             = AttributeName("default-name")
+	AttributeNameContext                 = AttributeName("context")
 )
 
 // EmbeddedAccessorType specifies the type of format the embedded accessor can marshal
@@ -177,7 +181,17 @@ type EmbeddedAccessorType string
 
 const (
 	EmbeddedAccessorRegexp = "Regexp"
-	// EmbeddedAccessorJSON = "JSON"
+	// EmbeddedAccessorURL accesses the components of URLs: scheme, username, password, host, port,
+	// path, query, fragment, and query.<parameter>.
+	EmbeddedAccessorURL = "URL"
+	// EmbeddedAccessorKeyValue accesses the values of key=value pairs in connection strings, such as
+	// "host=db port=5432" or "Server=db;Database=app". The optional configuration is the pair
+	// separator; by default, pairs are separated by semicolons if present, or else whitespace.
+	EmbeddedAccessorKeyValue = "KeyValue"
+	// EmbeddedAccessorJSON accesses the values at dotted paths within JSON documents.
+	EmbeddedAccessorJSON = "JSON"
+	// EmbeddedAccessorAuto detects the format of each value: JSON, URL, or key=value pairs.
+	EmbeddedAccessorAuto = "Auto"
 	// EmbeddedAccessorYAML = "YAML"
 )
 
@@ -219,6 +233,12 @@ type FunctionContext struct {
 	// SpaceSlug is the Slug of the Space of the configuration Unit.
 	SpaceSlug string
 
+	// Labels contains the labels of the Space of the configuration Unit.
+	Labels map[string]string
+
+	// Annotations contains the annotations of the Space of the configuration Unit.
+	Annotations map[string]string
+
 	// OrganizationID is the id of the Organization of the configuration Unit.
 	OrganizationID uuid.UUID
 
@@ -244,8 +264,40 @@ type FunctionContext struct {
 	// for determining whether it has been changed since it was last written.
 	PreviousContentHash RevisionHash
 
-	// Usernames of users that have approved this revision of the configuration data.
-	ApprovedBy []string
+	// Users that have approved this revision of the configuration data, with the roles in which
+	// they approved it. Users encoded as plain strings by older clients have the role ApproverRoleAny.
+	ApprovedBy []Approver
+}
+
+// ApproverRoleAny is the role of approvers whose role is not known, such as approvers
+// encoded as plain strings.
+const ApproverRoleAny = "any"
+
+// Approver identifies a user that approved a revision of configuration data and the role
+// in which they approved it.
+type Approver struct {
+	Email string
+	Role  string
+}
+
+// UnmarshalJSON accepts either an Approver object or, for backward compatibility, a plain
+// string identifying the user, in which case the Role is ApproverRoleAny.
+func (a *Approver) UnmarshalJSON(data []byte) error {
+	var user string
+	if err := json.Unmarshal(data, &user); err == nil {
+		*a = Approver{Email: user, Role: ApproverRoleAny}
+		return nil
+	}
+	type approver Approver
+	var decoded approver
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*a = Approver(decoded)
+	if a.Role == "" {
+		a.Role = ApproverRoleAny
+	}
+	return nil
 }
 
 // InstanceString returns a string that uniquely identifies the configuration Unit and,
@@ -258,7 +310,7 @@ func (fc *FunctionContext) InstanceString() string {
 }
 
 type Scalar interface {
-	~string | ~int | ~bool
+	~string | ~int | ~bool | ~float64
 }
 
 // FunctionArgument specifies the value of an argument in a function invocation and, optionally,
@@ -386,28 +438,207 @@ type AttributeDetails struct {
 	// PartitionRegexp    string              `json:",omitempty"`    // not used yet
 }
 
+// AttributeSource specifies whether an attribute value was obtained from the desired state, the
+// configuration data, or from the live state.
+type AttributeSource string
+
+const (
+	AttributeSourceDesiredState = AttributeSource("DesiredState")
+	AttributeSourceLiveState    = AttributeSource("LiveState")
+)
+
 // AttributeValue provides the value of an attribute in addition to information about the attribute.
 type AttributeValue struct {
 	AttributeInfo
 	Value   any
-	Comment string `json:",omitempty"`
+	Comment string          `json:",omitempty"`
+	Source  AttributeSource `json:",omitempty" swaggertype:"string"`
 }
 type AttributeValueList []AttributeValue
 
+// Filter returns the attribute values for which pred returns true, in their original order. If pred
+// returns true for all of the attribute values, the list itself is returned without copying.
+func (l AttributeValueList) Filter(pred func(AttributeValue) bool) AttributeValueList {
+	for i := range l {
+		if pred(l[i]) {
+			continue
+		}
+		// Allocate only once the first attribute value is dropped
+		filtered := make(AttributeValueList, i, len(l)-1)
+		copy(filtered, l[:i])
+		for _, attributeValue := range l[i+1:] {
+			if pred(attributeValue) {
+				filtered = append(filtered, attributeValue)
+			}
+		}
+		return filtered
+	}
+	return l
+}
+
+// ByResourceType returns the attribute values of resources of the specified resource type.
+func (l AttributeValueList) ByResourceType(resourceType ResourceType) AttributeValueList {
+	return l.Filter(func(attributeValue AttributeValue) bool {
+		return attributeValue.ResourceType == resourceType
+	})
+}
+
+// ByAttributeName returns the attribute values with the specified attribute name.
+func (l AttributeValueList) ByAttributeName(attributeName AttributeName) AttributeValueList {
+	return l.Filter(func(attributeValue AttributeValue) bool {
+		return attributeValue.AttributeName == attributeName
+	})
+}
+
+// AttributeDescription describes an attribute registered for a resource type, independent of
+// whether the attribute is present in any particular configuration data.
+type AttributeDescription struct {
+	ResourceType        ResourceType      `swaggertype:"string"`
+	Path                UnresolvedPath    `swaggertype:"string"`
+	AttributeName       AttributeName     `swaggertype:"string"`
+	DataType            DataType          `swaggertype:"string"`
+	ValueConstraints    *ValueConstraints `json:",omitempty"` // constraints on the values accepted by the setter, if any
+	GetterFunctionName  string            `json:",omitempty"`
+	SetterFunctionNames []string          `json:",omitempty"`
+	Description         string            `json:",omitempty"`
+}
+type AttributeDescriptionList []AttributeDescription
+
+// ValidationSeverity grades a validation result or detail. Warnings are worth reporting but
+// less serious than failures, such as when a recommended but not required setting is absent.
+type ValidationSeverity string
+
+const (
+	ValidationSeverityPass = ValidationSeverity("Pass")
+	ValidationSeverityWarn = ValidationSeverity("Warn")
+	ValidationSeverityFail = ValidationSeverity("Fail")
+)
+
+var validationSeverityRanks = map[ValidationSeverity]int{
+	ValidationSeverityPass: 0,
+	ValidationSeverityWarn: 1,
+	ValidationSeverityFail: 2,
+}
+
+// MaxValidationSeverity returns the more severe of the two severities.
+func MaxValidationSeverity(a, b ValidationSeverity) ValidationSeverity {
+	if validationSeverityRanks[b] > validationSeverityRanks[a] {
+		return b
+	}
+	return a
+}
+
+// ValidationDetail describes a single problem found by a validation function.
+type ValidationDetail struct {
+	Message  string
+	Severity ValidationSeverity
+}
+
+// UnmarshalJSON accepts either a ValidationDetail object or, for backward compatibility, a
+// plain string message, in which case the Severity is ValidationSeverityFail.
+func (d *ValidationDetail) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*d = ValidationDetail{Message: message, Severity: ValidationSeverityFail}
+		return nil
+	}
+	type validationDetail ValidationDetail
+	var decoded validationDetail
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*d = ValidationDetail(decoded)
+	if d.Severity == "" {
+		d.Severity = ValidationSeverityFail
+	}
+	return nil
+}
+
+// ValidationDetailsWithSeverity returns details for the messages, all with the same severity.
+func ValidationDetailsWithSeverity(messages []string, severity ValidationSeverity) []ValidationDetail {
+	details := make([]ValidationDetail, len(messages))
+	for i, message := range messages {
+		details[i] = ValidationDetail{Message: message, Severity: severity}
+	}
+	return details
+}
+
 // ValidationResult specifies whether a single validation function or sequence of validation
-// functions passed for the given configuration Unit.
+// functions passed for the given configuration Unit. Passed is set by the function and decides
+// whether the validation passed. Severity grades the result for reporting: a passing result may
+// be graded Warn to report problems that shouldn't fail validation, and a failed result is graded
+// Fail. Results without a Severity, such as from older functions, are graded by Passed.
+//
+// In JSON, Details is encoded as the list of detail messages, as it was before details had
+// severities, so that older clients can still read it, and the details with their severities
+// are encoded as DetailsWithSeverity.
 type ValidationResult struct {
 	Passed           bool               // true if valid, false otherwise
+	Severity         ValidationSeverity `json:",omitempty"` // Pass, Warn, or Fail
 	Index            int                // index of the function invocation corresponding to the result
-	Details          []string           `json:",omitempty"` // optional list of failure details
+	Details          []ValidationDetail `json:",omitempty"` // optional list of failure or warning details
 	FailedAttributes AttributeValueList `json:",omitempty"` // optional list of failed attributes; preferred over Details
 }
 
+// EffectiveSeverity returns the Severity of the result, or, if it isn't set, Pass or Fail
+// depending on whether the result passed.
+func (r *ValidationResult) EffectiveSeverity() ValidationSeverity {
+	if r.Severity != "" {
+		return r.Severity
+	}
+	if r.Passed {
+		return ValidationSeverityPass
+	}
+	return ValidationSeverityFail
+}
+
+// DetailMessages returns the messages of the Details.
+func (r *ValidationResult) DetailMessages() []string {
+	messages := make([]string, len(r.Details))
+	for i := range r.Details {
+		messages[i] = r.Details[i].Message
+	}
+	return messages
+}
+
+type validationResult ValidationResult
+
+func (r ValidationResult) MarshalJSON() ([]byte, error) {
+	encoded := struct {
+		validationResult
+		Details             []string           `json:",omitempty"`
+		DetailsWithSeverity []ValidationDetail `json:",omitempty"`
+	}{validationResult: validationResult(r), DetailsWithSeverity: r.Details}
+	if len(r.Details) > 0 {
+		encoded.Details = r.DetailMessages()
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON prefers DetailsWithSeverity and otherwise reads Details, which contains plain
+// messages when encoded by older functions.
+func (r *ValidationResult) UnmarshalJSON(data []byte) error {
+	var decoded struct {
+		validationResult
+		Details             []ValidationDetail
+		DetailsWithSeverity []ValidationDetail
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = ValidationResult(decoded.validationResult)
+	r.Details = decoded.DetailsWithSeverity
+	if len(r.Details) == 0 {
+		r.Details = decoded.Details
+	}
+	return nil
+}
+
 type ValidationResultList []ValidationResult
 
 var (
-	ValidationResultTrue  = ValidationResult{Passed: true}
-	ValidationResultFalse = ValidationResult{Passed: false}
+	ValidationResultTrue  = ValidationResult{Passed: true, Severity: ValidationSeverityPass}
+	ValidationResultFalse = ValidationResult{Passed: false, Severity: ValidationSeverityFail}
 )
 
 type YAMLPayload struct {
@@ -432,6 +663,20 @@ type FunctionSignature struct {
 	AffectedResourceTypes []ResourceType      `json:",omitempty" description:"Resource types the function applies to; * if all"`
 }
 
+// AffectsResourceType returns true if the function applies to the specified resource type,
+// which is the case if AffectedResourceTypes contains it or ResourceTypeAny, or is empty.
+func (f *FunctionSignature) AffectsResourceType(resourceType ResourceType) bool {
+	if len(f.AffectedResourceTypes) == 0 {
+		return true
+	}
+	for _, affectedResourceType := range f.AffectedResourceTypes {
+		if affectedResourceType == ResourceTypeAny || affectedResourceType == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
 // FunctionParameter organizing metadata
 // NOTE: I am aware of the similarity to OpenAPI and JSONSchema.
 
@@ -494,10 +739,10 @@ func AttributeNameForResourceType(resourceType ResourceType) AttributeName {
 	return AttributeName(string(AttributeNameResourceName) + "/" + string(resourceType))
 }
 
-// All types except int and bool are always serialized as strings
+// All types except int, bool, and float are always serialized as strings
 func DataTypeIsSerializedAsString(dataType DataType) bool {
 	switch dataType {
-	case DataTypeInt, DataTypeBool:
+	case DataTypeInt, DataTypeBool, DataTypeFloat:
 		return false
 	}
 	return true
@@ -567,7 +812,10 @@ func CombineOutputs(
 				messages = append(messages, "couldn't convert new result to ValidationResult")
 				return output, messages
 			}
+			// Passed is never changed, but failed results are graded as failures
+			newResult.Severity = newResult.EffectiveSeverity()
 			if !newResult.Passed {
+				newResult.Severity = ValidationSeverityFail
 				messages = append(messages, fmt.Sprintf("function failed: %s at %d on %s",
 					functionName, functionInvocationIndex, instance))
 			}
@@ -578,6 +826,7 @@ func CombineOutputs(
 					return output, messages
 				}
 				newResult.Passed = newResult.Passed && previousResult.Passed
+				newResult.Severity = MaxValidationSeverity(newResult.Severity, previousResult.EffectiveSeverity())
 				newResult.Details = append(newResult.Details, previousResult.Details...)
 				// Index is not set
 				output = newResult
@@ -0,0 +1,172 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproverUnmarshalJSON(t *testing.T) {
+	var functionContext FunctionContext
+	// Older clients encode approvers as plain strings
+	data := `{"ApprovedBy": ["legacy-user", {"Email": "alice@example.com", "Role": "security"}, {"Email": "bob@example.com"}]}`
+	err := json.Unmarshal([]byte(data), &functionContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []Approver{
+		{Email: "legacy-user", Role: ApproverRoleAny},
+		{Email: "alice@example.com", Role: "security"},
+		{Email: "bob@example.com", Role: ApproverRoleAny},
+	}, functionContext.ApprovedBy)
+
+	out, err := json.Marshal(functionContext)
+	assert.NoError(t, err)
+	var decoded FunctionContext
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Equal(t, functionContext, decoded)
+
+	var approvers []Approver
+	assert.Error(t, json.Unmarshal([]byte(`[42]`), &approvers))
+}
+
+func testAttributeValueList() AttributeValueList {
+	attributeValue := func(resourceType ResourceType, attributeName AttributeName, path ResolvedPath, value any) AttributeValue {
+		return AttributeValue{
+			AttributeInfo: AttributeInfo{
+				AttributeIdentifier: AttributeIdentifier{
+					ResourceInfo: ResourceInfo{ResourceType: resourceType, ResourceName: "default/app"},
+					Path:         path,
+				},
+				AttributeMetadata: AttributeMetadata{AttributeName: attributeName},
+			},
+			Value: value,
+		}
+	}
+	return AttributeValueList{
+		attributeValue("apps/v1/Deployment", "container-image", "spec.template.spec.containers.0.image", "nginx:1.27"),
+		attributeValue("apps/v1/Deployment", "replicas", "spec.replicas", 3),
+		attributeValue("v1/Service", "", "spec.ports.0.port", 80),
+		attributeValue("apps/v1/StatefulSet", "container-image", "spec.template.spec.containers.0.image", "redis:7"),
+	}
+}
+
+func TestAttributeValueList_Filter(t *testing.T) {
+	list := testAttributeValueList()
+
+	all := list.Filter(func(AttributeValue) bool { return true })
+	assert.Equal(t, list, all)
+	// No copy is made when nothing is filtered out
+	assert.Same(t, &list[0], &all[0])
+
+	assert.Empty(t, list.Filter(func(AttributeValue) bool { return false }))
+
+	ints := list.Filter(func(attributeValue AttributeValue) bool {
+		_, ok := attributeValue.Value.(int)
+		return ok
+	})
+	assert.Equal(t, AttributeValueList{list[1], list[2]}, ints)
+	// The original list is not modified
+	assert.Equal(t, testAttributeValueList(), list)
+}
+
+func TestAttributeValueList_ByResourceType(t *testing.T) {
+	list := testAttributeValueList()
+	assert.Equal(t, AttributeValueList{list[0], list[1]}, list.ByResourceType("apps/v1/Deployment"))
+	assert.Equal(t, AttributeValueList{list[2]}, list.ByResourceType("v1/Service"))
+	assert.Empty(t, list.ByResourceType("v1/ConfigMap"))
+}
+
+func TestAttributeValueList_ByAttributeName(t *testing.T) {
+	list := testAttributeValueList()
+	assert.Equal(t, AttributeValueList{list[0], list[3]}, list.ByAttributeName("container-image"))
+	assert.Equal(t, AttributeValueList{list[2]}, list.ByAttributeName(""))
+	assert.Empty(t, AttributeValueList(nil).ByAttributeName("replicas"))
+}
+
+func TestFunctionSignatureAffectsResourceType(t *testing.T) {
+	deploymentOnly := FunctionSignature{AffectedResourceTypes: []ResourceType{"apps/v1/Deployment"}}
+	assert.True(t, deploymentOnly.AffectsResourceType("apps/v1/Deployment"))
+	assert.False(t, deploymentOnly.AffectsResourceType("v1/ConfigMap"))
+
+	anyType := FunctionSignature{AffectedResourceTypes: []ResourceType{ResourceTypeAny}}
+	assert.True(t, anyType.AffectsResourceType("v1/ConfigMap"))
+
+	unspecified := FunctionSignature{}
+	assert.True(t, unspecified.AffectsResourceType("v1/ConfigMap"))
+}
+
+func TestValidationDetailUnmarshalJSON(t *testing.T) {
+	var result ValidationResult
+	data := `{"Passed": false, "Details": ["legacy failure", {"Message": "no limits", "Severity": "Warn"}, {"Message": "bad image"}]}`
+	err := json.Unmarshal([]byte(data), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationDetail{
+		{Message: "legacy failure", Severity: ValidationSeverityFail},
+		{Message: "no limits", Severity: ValidationSeverityWarn},
+		{Message: "bad image", Severity: ValidationSeverityFail},
+	}, result.Details)
+	assert.Equal(t, []string{"legacy failure", "no limits", "bad image"}, result.DetailMessages())
+	// Severity wasn't set, so it's inferred from Passed
+	assert.Equal(t, ValidationSeverityFail, result.EffectiveSeverity())
+
+	assert.Error(t, json.Unmarshal([]byte(`[42]`), &result.Details))
+}
+
+func TestValidationResultJSON(t *testing.T) {
+	result := ValidationResult{
+		Passed:   true,
+		Severity: ValidationSeverityWarn,
+		Details:  []ValidationDetail{{Message: "no limits", Severity: ValidationSeverityWarn}},
+	}
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	// Older clients read Details as messages
+	var legacy struct{ Details []string }
+	require.NoError(t, json.Unmarshal(data, &legacy))
+	assert.Equal(t, []string{"no limits"}, legacy.Details)
+
+	var decoded ValidationResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, result, decoded)
+
+	data, err = json.Marshal(ValidationResultTrue)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Details")
+}
+
+func TestCombineValidationResultSeverities(t *testing.T) {
+	warning := ValidationResult{
+		Passed:   true,
+		Severity: ValidationSeverityWarn,
+		Details:  []ValidationDetail{{Message: "no limits", Severity: ValidationSeverityWarn}},
+	}
+	var output any
+	var messages []string
+	for i, result := range []ValidationResult{warning, ValidationResultTrue} {
+		output, messages = CombineOutputs("fn", "unit", OutputTypeValidationResult, OutputTypeValidationResult, output, result, true, i, messages)
+	}
+	assert.Empty(t, messages)
+	combined := output.(ValidationResult)
+	assert.True(t, combined.Passed)
+	assert.Equal(t, ValidationSeverityWarn, combined.Severity)
+	assert.Len(t, combined.Details, 1)
+
+	output, _ = CombineOutputs("fn", "unit", OutputTypeValidationResult, OutputTypeValidationResult, output, ValidationResultFalse, true, 2, messages)
+	combined = output.(ValidationResult)
+	assert.False(t, combined.Passed)
+	assert.Equal(t, ValidationSeverityFail, combined.Severity)
+
+	// Passed is never changed, and failed results are graded as failures
+	failedWarning := ValidationResult{Passed: false, Severity: ValidationSeverityWarn}
+	output, messages = CombineOutputs("fn", "unit", OutputTypeValidationResult, OutputTypeValidationResult, nil, failedWarning, false, 0, nil)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, ValidationResultList{ValidationResultFalse}, output)
+	output, _ = CombineOutputs("fn", "unit", OutputTypeValidationResult, OutputTypeValidationResult, nil, failedWarning, true, 0, nil)
+	assert.False(t, output.(ValidationResult).Passed)
+	output, _ = CombineOutputs("fn", "unit", OutputTypeValidationResult, OutputTypeValidationResult, nil, ValidationResult{Passed: false}, false, 0, nil)
+	assert.Equal(t, ValidationResultList{ValidationResultFalse}, output)
+}
@@ -4,6 +4,7 @@
 package api
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -186,3 +187,90 @@ func TestKubernetesPathSupport(t *testing.T) {
 		})
 	}
 }
+
+// whereFilterFuzzCorpus contains valid and malformed where filters used to seed the fuzz targets
+var whereFilterFuzzCorpus = []string{
+	"name = 'test' AND age > 18",
+	"kind NOT IN ('Secret')",
+	"metadata.namespace IN ('default', 'production') AND kind = 'Pod'",
+	"spec.template.spec.containers.*.image#uri = 'nginx'",
+	"spec.template.spec.containers.?name=main.image LIKE 'nginx%'",
+	"metadata.annotations.confighub~1com/test = 'x'",
+	"spec.replicas >= -1",
+	"enabled != true",
+	"spec.containers.0.|name = 'app'",
+	"",
+	"   ",
+	"name =",
+	"= 'x'",
+	"name = 'unterminated",
+	"name IN (",
+	"name IN ()",
+	"name = 'a' AND",
+	"name = 'a' OR kind = 'b'",
+	"näme = 'ü'",
+	"name = '😀'",
+	"name = 'x'",
+	"a.b.c.d.e.f.g.h.i.j.k.l.m.n.o.p.q.r.s.t.u.v.w.x.y.z = 1",
+	"name = ''''",
+	"LEN(name) > 1",
+	"a.|b.|c = 1",
+	"?=",
+	"*?:",
+	"@:x = 1",
+}
+
+func FuzzParseAndValidateWhereFilter(f *testing.F) {
+	for _, query := range whereFilterFuzzCorpus {
+		f.Add(query)
+	}
+	f.Fuzz(func(t *testing.T, query string) {
+		expressions, err := ParseAndValidateWhereFilter(query)
+		if err != nil {
+			return
+		}
+		for _, expression := range expressions {
+			if expression == nil || expression.Path == "" || expression.Operator == "" {
+				t.Fatalf("incomplete expression %+v parsed from %q", expression, query)
+			}
+		}
+		// The import parser accepts a subset of the standard operators
+		_, _ = ParseAndValidateWhereFilterForImport(query)
+	})
+}
+
+func FuzzParseLiteral(f *testing.F) {
+	for _, literal := range []string{"18", "-1", "true", "false", "'test'", "''", "'unterminated", "'ü😀'", "1.5", "tru", "", " 'x'"} {
+		f.Add(literal)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		remaining, literal, dataType, err := ParseLiteral(input)
+		if err != nil {
+			if remaining != input || literal != "" || dataType != DataTypeNone {
+				t.Fatalf("ParseLiteral(%q) consumed input despite error %v", input, err)
+			}
+			return
+		}
+		if literal == "" || literal+remaining != input {
+			t.Fatalf("ParseLiteral(%q) returned literal %q and remaining %q", input, literal, remaining)
+		}
+	})
+}
+
+func FuzzParseAndValidateBinaryExpression(f *testing.F) {
+	for _, query := range whereFilterFuzzCorpus {
+		f.Add(query)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		remaining, expression, err := ParseAndValidateBinaryExpression(input)
+		if expression == nil {
+			t.Fatalf("ParseAndValidateBinaryExpression(%q) returned a nil expression", input)
+		}
+		if !strings.HasSuffix(input, remaining) {
+			t.Fatalf("ParseAndValidateBinaryExpression(%q) returned remaining %q that isn't a suffix of the input", input, remaining)
+		}
+		if err == nil && (len(remaining) >= len(input) || expression.Path == "" || expression.Literal == "") {
+			t.Fatalf("ParseAndValidateBinaryExpression(%q) returned %+v and remaining %q", input, expression, remaining)
+		}
+	})
+}
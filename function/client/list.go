@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -18,8 +19,17 @@ import (
 )
 
 func GetFunctionList(transportConfig *TransportConfig, toolchain workerapi.ToolchainType) (map[string]api.FunctionSignature, error) {
+	return GetFunctionListForResourceType(transportConfig, toolchain, "")
+}
+
+// GetFunctionListForResourceType returns the functions that apply to the specified resource type,
+// or all functions if the resource type is empty.
+func GetFunctionListForResourceType(transportConfig *TransportConfig, toolchain workerapi.ToolchainType, resourceType api.ResourceType) (map[string]api.FunctionSignature, error) {
 	// Send the request
 	url := transportConfig.GetToolchainURL(toolchain)
+	if resourceType != "" {
+		url += "?" + neturl.Values{"resource-type": {string(resourceType)}}.Encode()
+	}
 	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, bytes.NewReader([]byte{})) //nolint:G107 // dynamic URL for testing
 	if err != nil {
 		return nil, errors.WithStack(err)
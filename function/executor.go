@@ -30,6 +30,8 @@ import (
 	"fmt"
 
 	"github.com/confighub/sdk/configkit"
+	"github.com/confighub/sdk/configkit/ansiblekit"
+	"github.com/confighub/sdk/configkit/appconfigkit"
 	"github.com/confighub/sdk/configkit/hclkit"
 	"github.com/confighub/sdk/configkit/k8skit"
 	"github.com/confighub/sdk/configkit/propkit"
@@ -48,12 +50,16 @@ var converters = map[workerapi.ToolchainType]configkit.ConfigConverter{
 	workerapi.ToolchainKubernetesYAML:      k8skit.K8sResourceProvider,
 	workerapi.ToolchainOpenTofuHCL:         hclkit.HclResourceProvider,
 	workerapi.ToolchainAppConfigProperties: propkit.PropertiesResourceProvider,
+	workerapi.ToolchainAppConfigYAML:       appconfigkit.AppConfigYAMLResourceProvider,
+	workerapi.ToolchainAnsibleVars:         ansiblekit.AnsibleVarsResourceProvider,
 }
 
 var registrators = map[workerapi.ToolchainType]func(*handler.FunctionHandler){
 	workerapi.ToolchainKubernetesYAML:      RegisterKubernetes,
 	workerapi.ToolchainOpenTofuHCL:         RegisterOpenTofu,
 	workerapi.ToolchainAppConfigProperties: RegisterProperties,
+	workerapi.ToolchainAppConfigYAML:       RegisterAppConfigYAML,
+	workerapi.ToolchainAnsibleVars:         RegisterAnsibleVars,
 }
 
 type FunctionExecutor struct {
@@ -10,9 +10,11 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"slices"
 	"strconv"
 
 	"github.com/cockroachdb/errors"
+	"github.com/google/cel-go/cel"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/gommon/log"
 
@@ -33,15 +35,20 @@ type FunctionProvider interface {
 type FunctionRegistry interface {
 	RegisterFunction(functionName string, registration *FunctionRegistration) error
 	GetHandlerImplementation(functionName string) FunctionImplementation
+	GetFunctionSignature(functionName string) *api.FunctionSignature
 	SetPathRegistry(pathRegistry api.AttributeNameToResourceTypeToPathToVisitorInfoType)
 	SetConverter(converter configkit.ConfigConverter)
 	GetConverter() configkit.ConfigConverter
+	RegisterCELExtension(name string, extension cel.EnvOption)
+	CELExtensions() []cel.EnvOption
 }
 
 type FunctionHandler struct {
-	functionMap  map[string]*FunctionRegistration
-	pathRegistry api.AttributeNameToResourceTypeToPathToVisitorInfoType
-	converter    configkit.ConfigConverter
+	functionMap   map[string]*FunctionRegistration
+	pathRegistry  api.AttributeNameToResourceTypeToPathToVisitorInfoType
+	converter     configkit.ConfigConverter
+	parseOptions  gaby.ParseOptions
+	celExtensions map[string]cel.EnvOption
 }
 
 // Ensure FunctionHandler implements FunctionRegistry
@@ -51,6 +58,7 @@ func NewFunctionHandler() *FunctionHandler {
 	fh := &FunctionHandler{}
 	fh.functionMap = make(map[string]*FunctionRegistration)
 	fh.pathRegistry = make(api.AttributeNameToResourceTypeToPathToVisitorInfoType)
+	fh.celExtensions = make(map[string]cel.EnvOption)
 	return fh
 }
 
@@ -59,9 +67,24 @@ func (fh *FunctionHandler) GetHandlerImplementation(functionName string) Functio
 	if !ok {
 		return nil
 	}
+	if registration.Function == nil && registration.ContextFunction != nil {
+		return func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return registration.ContextFunction(context.Background(), functionContext, parsedData, args, liveState)
+		}
+	}
 	return registration.Function
 }
 
+// GetFunctionSignature returns the signature of the specified function, or nil if the function
+// isn't registered.
+func (fh *FunctionHandler) GetFunctionSignature(functionName string) *api.FunctionSignature {
+	registration, ok := fh.functionMap[functionName]
+	if !ok {
+		return nil
+	}
+	return &registration.FunctionSignature
+}
+
 func (fh *FunctionHandler) SetConverter(converter configkit.ConfigConverter) {
 	fh.converter = converter
 }
@@ -70,6 +93,40 @@ func (fh *FunctionHandler) GetConverter() configkit.ConfigConverter {
 	return fh.converter
 }
 
+// SetParseOptions sets the options, such as resource limits, used to parse the configuration
+// data of function invocations.
+func (fh *FunctionHandler) SetParseOptions(parseOptions gaby.ParseOptions) {
+	fh.parseOptions = parseOptions
+}
+
+// RegisterCELExtension makes a toolchain-specific CEL library, typically declared with
+// cel.Function, available to CEL expressions evaluated by functions such as cel-validate.
+// Registering an extension with the same name replaces the previous one.
+func (fh *FunctionHandler) RegisterCELExtension(name string, extension cel.EnvOption) {
+	fh.celExtensions[name] = extension
+}
+
+// CELExtensions returns the registered CEL extensions, ordered by name.
+func (fh *FunctionHandler) CELExtensions() []cel.EnvOption {
+	names := make([]string, 0, len(fh.celExtensions))
+	for name := range fh.celExtensions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	extensions := make([]cel.EnvOption, 0, len(names))
+	for _, name := range names {
+		extensions = append(extensions, fh.celExtensions[name])
+	}
+	return extensions
+}
+
+// ParseErrorResponse is the body of the Bad Request response of Invoke when the configuration
+// data is syntactically invalid.
+type ParseErrorResponse struct {
+	Message    string
+	ParseError *gaby.ParseError
+}
+
 func (fh *FunctionHandler) Invoke(c echo.Context) error {
 	var functionInvocation api.FunctionInvocationRequest
 	err := c.Bind(&functionInvocation)
@@ -79,6 +136,13 @@ func (fh *FunctionHandler) Invoke(c echo.Context) error {
 	}
 
 	resp, err := fh.InvokeCore(c.Request().Context(), &functionInvocation)
+	var parseError *gaby.ParseError
+	if errors.As(err, &parseError) {
+		return echo.NewHTTPError(http.StatusBadRequest, ParseErrorResponse{
+			Message:    err.Error(),
+			ParseError: parseError,
+		})
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest,
 			errors.Wrap(err, "functions couldn't execute on provided data"))
@@ -153,11 +217,11 @@ func (fh *FunctionHandler) InvokeCore(ctx context.Context, functionInvocation *a
 		var newParsedData gaby.Container
 		var functionOutput any
 		var err error
-		newParsedData, err = gaby.ParseAll(serializedData)
+		newParsedData, err = gaby.ParseAll(serializedData, fh.parseOptions)
 		if err != nil {
 			return nil, errors.Wrap(err, "configuration data parsing error")
 		}
-		newParsedData, functionOutput, err = f.Function(&functionContext, newParsedData, arguments, functionInvocation.LiveState)
+		newParsedData, functionOutput, err = f.Invoke(ctx, &functionContext, newParsedData, arguments, functionInvocation.LiveState)
 		if err == nil && isFilter {
 			validationResult, ok := functionOutput.(api.ValidationResult)
 			if !ok {
@@ -181,7 +245,7 @@ func (fh *FunctionHandler) InvokeCore(ctx context.Context, functionInvocation *a
 		invocationInfo += ": succeeded"
 		log.Info(invocationInfo)
 
-		if f.Mutating {
+		if f.IsMutating(arguments) {
 			newSerializedDataString := newParsedData.String()
 			newSerializedData := []byte(newSerializedDataString)
 			if !bytes.Equal(serializedData, newSerializedData) {
@@ -383,6 +447,16 @@ func ValidateAndBuildArguments(invocation *api.FunctionInvocation, f *api.Functi
 				} else {
 					return nil, fmt.Errorf("argument %s data type %s is not of a string type", argumentName, parameter.DataType)
 				}
+			case api.DataTypeFloat:
+				if castStringArgsToScalars {
+					floatVal, err := strconv.ParseFloat(v, 64)
+					if err != nil {
+						return nil, fmt.Errorf("cannot convert argument %s value %s to float: %w", argumentName, v, err)
+					}
+					invocation.Arguments[i].Value = floatVal
+				} else {
+					return nil, fmt.Errorf("argument %s data type %s is not of a string type", argumentName, parameter.DataType)
+				}
 			default:
 				if !api.DataTypeIsSerializedAsString(parameter.DataType) {
 					return nil, fmt.Errorf("argument %s data type %s is not of a string type", argumentName, parameter.DataType)
@@ -409,6 +483,9 @@ func ValidateAndBuildArguments(invocation *api.FunctionInvocation, f *api.Functi
 				}
 			}
 		case float64:
+			if parameter.DataType == api.DataTypeFloat {
+				break
+			}
 			if parameter.DataType != api.DataTypeInt {
 				return nil, fmt.Errorf("argument %s data type %s is not of type %s", argumentName, parameter.DataType, api.DataTypeInt)
 			}
@@ -417,7 +494,9 @@ func ValidateAndBuildArguments(invocation *api.FunctionInvocation, f *api.Functi
 				return nil, fmt.Errorf("argument %s value %d is out of range %s", argumentName, int(v), intConstraintString(parameter.ValueConstraints))
 			}
 		case int:
-			if parameter.DataType != api.DataTypeInt {
+			if parameter.DataType == api.DataTypeFloat {
+				invocation.Arguments[i].Value = float64(v)
+			} else if parameter.DataType != api.DataTypeInt {
 				return nil, fmt.Errorf("argument %s data type %s is not of type %s", argumentName, parameter.DataType, api.DataTypeInt)
 			} else if !validateIntArg(v, parameter.ValueConstraints) {
 				return nil, fmt.Errorf("argument %s value %d is out of range %s", argumentName, v, intConstraintString(parameter.ValueConstraints))
@@ -470,9 +549,29 @@ func (fh *FunctionHandler) ListCore() map[string]*FunctionRegistration {
 	return fh.functionMap
 }
 
+// ResourceTypeQueryParameter is the query parameter of List that restricts the functions
+// returned to those that apply to the specified resource type.
+const ResourceTypeQueryParameter = "resource-type"
+
+// ListForResourceType returns the functions that apply to the specified resource type,
+// or all functions if the resource type is empty.
+func (fh *FunctionHandler) ListForResourceType(resourceType api.ResourceType) map[string]*FunctionRegistration {
+	if resourceType == "" {
+		return fh.functionMap
+	}
+	functionMap := make(map[string]*FunctionRegistration)
+	for functionName, registration := range fh.functionMap {
+		if registration.AffectsResourceType(resourceType) {
+			functionMap[functionName] = registration
+		}
+	}
+	return functionMap
+}
+
 func (fh *FunctionHandler) List(c echo.Context) error {
 	// TODO: pagination
-	return c.JSON(http.StatusOK, fh.functionMap) //nolint:wrapcheck // basic return
+	resourceType := api.ResourceType(c.QueryParam(ResourceTypeQueryParameter))
+	return c.JSON(http.StatusOK, fh.ListForResourceType(resourceType)) //nolint:wrapcheck // basic return
 }
 
 func (fh *FunctionHandler) ListPaths(c echo.Context) error {
@@ -505,9 +604,43 @@ func (fh *FunctionHandler) RegisterFunction(functionName string, registration *F
 
 type FunctionImplementation func(*api.FunctionContext, gaby.Container, []api.FunctionArgument, []byte) (gaby.Container, any, error)
 
+// ContextFunctionImplementation is the implementation of a function that calls external services,
+// which receives the context of the invocation to bound and cancel its requests.
+type ContextFunctionImplementation func(context.Context, *api.FunctionContext, gaby.Container, []api.FunctionArgument, []byte) (gaby.Container, any, error)
+
 type FunctionRegistration struct {
 	api.FunctionSignature
 	Function FunctionImplementation `json:"-"` // implementation
+	// ContextFunction, if set, is the implementation used instead of Function, for functions that
+	// need the context of the invocation.
+	ContextFunction ContextFunctionImplementation `json:"-"`
+	// MutatingParameterName, if set, is the name of a boolean parameter that determines whether
+	// an invocation of the function changes the configuration data. Mutating applies if the
+	// argument is omitted.
+	MutatingParameterName string `json:"-"`
+}
+
+// Invoke calls the implementation of the function, passing ctx to ContextFunction, if set.
+func (f *FunctionRegistration) Invoke(ctx context.Context, functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+	if f.ContextFunction != nil {
+		return f.ContextFunction(ctx, functionContext, parsedData, args, liveState)
+	}
+	return f.Function(functionContext, parsedData, args, liveState)
+}
+
+// IsMutating returns true if an invocation of the function with the specified arguments may
+// change the configuration data.
+func (f *FunctionRegistration) IsMutating(args []api.FunctionArgument) bool {
+	if f.MutatingParameterName == "" {
+		return f.Mutating
+	}
+	for _, arg := range args {
+		if arg.ParameterName == f.MutatingParameterName {
+			mutating, _ := arg.Value.(bool)
+			return mutating
+		}
+	}
+	return f.Mutating
 }
 
 // SetPathRegistry sets the path registry.
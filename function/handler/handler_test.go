@@ -0,0 +1,60 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/function/handler"
+	"github.com/confighub/sdk/function/internal/handlers/kubernetes"
+)
+
+func TestInvokeReturnsParseErrorDetails(t *testing.T) {
+	fh := handler.NewFunctionHandler()
+	kubernetes.KubernetesRegistrar.RegisterFunctions(fh)
+
+	body, err := json.Marshal(api.FunctionInvocationRequest{
+		ConfigData:          []byte("apiVersion: v1\nkind: ConfigMap\n---\nmetadata:\n  - name\n name: x\n"),
+		FunctionInvocations: api.FunctionInvocationList{{FunctionName: "get-resources"}},
+	})
+	require.NoError(t, err)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/function/kubernetes", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	err = fh.Invoke(e.NewContext(req, rec))
+	var httpError *echo.HTTPError
+	require.ErrorAs(t, err, &httpError)
+	assert.Equal(t, http.StatusBadRequest, httpError.Code)
+	response, ok := httpError.Message.(handler.ParseErrorResponse)
+	require.True(t, ok, "unexpected message %v", httpError.Message)
+	assert.Equal(t, 1, response.ParseError.Index)
+	assert.Equal(t, 2, response.ParseError.Line)
+	assert.Contains(t, response.Message, "YAML document 1, line 2")
+}
+
+func TestFunctionRegistrationIsMutating(t *testing.T) {
+	f := &handler.FunctionRegistration{
+		FunctionSignature:     api.FunctionSignature{FunctionName: "yq", Mutating: false},
+		MutatingParameterName: "mutating",
+	}
+	assert.False(t, f.IsMutating(nil))
+	assert.False(t, f.IsMutating([]api.FunctionArgument{{ParameterName: "mutating", Value: false}}))
+	assert.True(t, f.IsMutating([]api.FunctionArgument{{ParameterName: "yq-expression", Value: ".a = 1"}, {ParameterName: "mutating", Value: true}}))
+
+	f.MutatingParameterName = ""
+	assert.False(t, f.IsMutating([]api.FunctionArgument{{ParameterName: "mutating", Value: true}}))
+	f.Mutating = true
+	assert.True(t, f.IsMutating(nil))
+}
@@ -0,0 +1,32 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package ansible
+
+import (
+	"github.com/confighub/sdk/configkit/ansiblekit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/function/handler"
+	"github.com/confighub/sdk/workerapi"
+)
+
+type AnsibleVarsRegistrarType struct{}
+
+var AnsibleVarsRegistrar = &AnsibleVarsRegistrarType{}
+
+func (r *AnsibleVarsRegistrarType) RegisterFunctions(fh handler.FunctionRegistry) {
+	initStandardFunctions()
+	if err := registerStandardFunctions(fh); err != nil {
+		// Conflicting path registrations are programming errors, so fail initialization
+		panic(err)
+	}
+	fh.SetConverter(ansiblekit.AnsibleVarsResourceProvider)
+}
+
+func (r *AnsibleVarsRegistrarType) GetToolchainPath() string {
+	return api.SupportedToolchains[workerapi.ToolchainAnsibleVars]
+}
+
+func (r *AnsibleVarsRegistrarType) SetPathRegistry(fh handler.FunctionRegistry) {
+	fh.SetPathRegistry(ansiblekit.AnsibleVarsResourceProvider.GetPathRegistry())
+}
@@ -0,0 +1,127 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package ansible
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/confighub/sdk/configkit/ansiblekit"
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/function/handler"
+	"github.com/confighub/sdk/function/internal/handlers/generic"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func registerStandardFunctions(fh handler.FunctionRegistry) error {
+	if err := generic.RegisterStandardFunctions(fh, ansiblekit.AnsibleVarsResourceProvider, ansiblekit.AnsibleVarsResourceProvider); err != nil {
+		return err
+	}
+	fh.RegisterFunction("validate", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "validate",
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "passed",
+				Description: "True if the configuration passes validation, false otherwise",
+				OutputType:  api.OutputTypeValidationResult,
+			},
+			Mutating:              false,
+			Validating:            true,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Returns true if every vars file is a map of valid Ansible variable names",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: ansibleFnValidate,
+	})
+	return nil
+}
+
+func initStandardFunctions() {
+	basicNameTemplate := generic.StandardNameTemplate(ansiblekit.AnsibleVarsResourceProvider.NameSeparator())
+	var defaultNames = api.ResourceTypeToPathToVisitorInfoType{
+		ansiblekit.ResourceTypeGroupVars: {
+			api.UnresolvedPath(ansiblekit.GroupNamePath): {
+				Path:          api.UnresolvedPath(ansiblekit.GroupNamePath),
+				AttributeName: api.AttributeNameResourceName,
+				DataType:      api.DataTypeString,
+				Info:          &api.AttributeDetails{GenerationTemplate: basicNameTemplate},
+			},
+		},
+		ansiblekit.ResourceTypeHostVars: {
+			api.UnresolvedPath(ansiblekit.HostNamePath): {
+				Path:          api.UnresolvedPath(ansiblekit.HostNamePath),
+				AttributeName: api.AttributeNameResourceName,
+				DataType:      api.DataTypeString,
+				Info:          &api.AttributeDetails{GenerationTemplate: basicNameTemplate},
+			},
+		},
+	}
+	setterFunctionInvocation := &api.FunctionInvocation{
+		FunctionName: "set-default-names",
+	}
+	for resourceType, pathInfos := range defaultNames {
+		yamlkit.MustRegisterPathsByAttributeName(
+			ansiblekit.AnsibleVarsResourceProvider,
+			api.AttributeNameDefaultName,
+			resourceType,
+			pathInfos,
+			nil,
+			setterFunctionInvocation,
+			false,
+		)
+		yamlkit.MustRegisterPathsByAttributeName(
+			ansiblekit.AnsibleVarsResourceProvider,
+			api.AttributeNameGeneral,
+			resourceType,
+			pathInfos,
+			nil,
+			setterFunctionInvocation,
+			true,
+		)
+	}
+}
+
+// https://docs.ansible.com/ansible/latest/playbook_guide/playbooks_variables.html#creating-valid-variable-names
+var variableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func ansibleFnValidate(_ *api.FunctionContext, parsedData gaby.Container, _ []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	result := api.ValidationResultTrue
+	visitor := func(doc *gaby.YamlDoc, output any, index int, resourceInfo *api.ResourceInfo) (any, []error) {
+		if _, isMap := doc.Data().(map[string]any); !isMap && doc.Data() != nil {
+			result.Passed = false
+			result.Severity = api.ValidationSeverityFail
+			result.Details = append(result.Details, api.ValidationDetail{
+				Message:  fmt.Sprintf("%s %s: vars file isn't a map of variables", resourceInfo.ResourceType, resourceInfo.ResourceName),
+				Severity: api.ValidationSeverityFail,
+			})
+			return output, nil
+		}
+		variables := doc.ChildrenMap()
+		names := make([]string, 0, len(variables))
+		for name := range variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if !variableNameRegexp.MatchString(name) {
+				result.Passed = false
+				result.Severity = api.ValidationSeverityFail
+				result.Details = append(result.Details, api.ValidationDetail{
+					Message:  fmt.Sprintf("%s %s: invalid variable name %s", resourceInfo.ResourceType, resourceInfo.ResourceName, name),
+					Severity: api.ValidationSeverityFail,
+				})
+			}
+		}
+		return output, nil
+	}
+	_, err := yamlkit.VisitResources(parsedData, nil, ansiblekit.AnsibleVarsResourceProvider, visitor)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	return parsedData, result, nil
+}
@@ -0,0 +1,114 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package ansible
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/function/handler"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const ansibleVarsFixture = `ntp_servers:
+  - 0.pool.ntp.org
+---
+confighub_group: webservers
+nginx:
+  worker_processes: 4
+  tls:
+    enabled: false
+---
+confighub_group: dbservers
+postgres:
+  max_connections: 100
+---
+confighub_host: web1.example.com
+nginx:
+  worker_processes: 8
+`
+
+func newTestHandler() *handler.FunctionHandler {
+	fh := handler.NewFunctionHandler()
+	AnsibleVarsRegistrar.RegisterFunctions(fh)
+	AnsibleVarsRegistrar.SetPathRegistry(fh)
+	return fh
+}
+
+func invoke(t *testing.T, fh *handler.FunctionHandler, functionName string, docs gaby.Container, args ...any) (gaby.Container, any) {
+	functionArgs := make([]api.FunctionArgument, len(args))
+	for i, arg := range args {
+		functionArgs[i].Value = arg
+	}
+	docs, output, err := fh.ListCore()[functionName].Function(&api.FunctionContext{}, docs, functionArgs, []byte{})
+	assert.NoError(t, err)
+	return docs, output
+}
+
+func TestStandardFunctions(t *testing.T) {
+	fh := newTestHandler()
+	docs, err := gaby.ParseAll([]byte(ansibleVarsFixture))
+	assert.NoError(t, err)
+
+	_, output := invoke(t, fh, "get-resources", docs)
+	resources, ok := output.(api.ResourceList)
+	if assert.True(t, ok) && assert.Len(t, resources, 4) {
+		assert.Equal(t, api.ResourceName("all"), resources[0].ResourceName)
+		assert.Equal(t, api.ResourceName("webservers"), resources[1].ResourceName)
+		assert.Equal(t, api.ResourceType("ansible/group_vars"), resources[1].ResourceType)
+		assert.Equal(t, api.ResourceName("web1.example.com"), resources[3].ResourceName)
+		assert.Equal(t, api.ResourceType("ansible/host_vars"), resources[3].ResourceType)
+	}
+
+	// Nested variables of group_vars files are read and written like any other paths
+	_, output = invoke(t, fh, "get-int-path", docs, "ansible/group_vars", "nginx.worker_processes")
+	values, ok := output.(api.AttributeValueList)
+	if assert.True(t, ok) && assert.Len(t, values, 1) {
+		assert.Equal(t, 4, values[0].Value)
+		assert.Equal(t, api.ResourceName("webservers"), values[0].ResourceName)
+	}
+	docs, _ = invoke(t, fh, "set-bool-path", docs, "ansible/group_vars", "nginx.tls.enabled", true)
+	assert.Equal(t, true, docs[1].Path("nginx.tls.enabled").Data())
+	// The host_vars file isn't of the specified type
+	assert.Equal(t, 8, docs[3].Path("nginx.worker_processes").Data())
+
+	_, output = invoke(t, fh, "validate", docs)
+	assert.Equal(t, api.ValidationResultTrue, output)
+}
+
+func TestWhereFilter(t *testing.T) {
+	fh := newTestHandler()
+	docs, err := gaby.ParseAll([]byte(ansibleVarsFixture))
+	assert.NoError(t, err)
+
+	_, output := invoke(t, fh, "where-filter", docs, "ansible/group_vars", "confighub_group = 'dbservers' AND postgres.max_connections >= 100")
+	assert.Equal(t, true, output.(api.ValidationResult).Passed)
+	_, output = invoke(t, fh, "where-filter", docs, "ansible/group_vars", "confighub_group = 'webservers' AND postgres.max_connections >= 100")
+	assert.Equal(t, false, output.(api.ValidationResult).Passed)
+	_, output = invoke(t, fh, "where-filter", docs, "ansible/host_vars", "nginx.worker_processes > 4")
+	assert.Equal(t, true, output.(api.ValidationResult).Passed)
+}
+
+func TestValidate(t *testing.T) {
+	fh := newTestHandler()
+	docs, err := gaby.ParseAll([]byte(`confighub_group: webservers
+nginx-port: 80
+---
+confighub_host: web1.example.com
+1st_server: true
+`))
+	assert.NoError(t, err)
+
+	_, output := invoke(t, fh, "validate", docs)
+	assert.Equal(t, api.ValidationResult{
+		Passed:   false,
+		Severity: api.ValidationSeverityFail,
+		Details: []api.ValidationDetail{
+			{Message: "ansible/group_vars webservers: invalid variable name nginx-port", Severity: api.ValidationSeverityFail},
+			{Message: "ansible/host_vars web1.example.com: invalid variable name 1st_server", Severity: api.ValidationSeverityFail},
+		},
+	}, output)
+}
@@ -0,0 +1,32 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package appconfig
+
+import (
+	"github.com/confighub/sdk/configkit/appconfigkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/function/handler"
+	"github.com/confighub/sdk/workerapi"
+)
+
+type AppConfigYAMLRegistrarType struct{}
+
+var AppConfigYAMLRegistrar = &AppConfigYAMLRegistrarType{}
+
+func (r *AppConfigYAMLRegistrarType) RegisterFunctions(fh handler.FunctionRegistry) {
+	initStandardFunctions()
+	if err := registerStandardFunctions(fh); err != nil {
+		// Conflicting path registrations are programming errors, so fail initialization
+		panic(err)
+	}
+	fh.SetConverter(appconfigkit.AppConfigYAMLResourceProvider)
+}
+
+func (r *AppConfigYAMLRegistrarType) GetToolchainPath() string {
+	return api.SupportedToolchains[workerapi.ToolchainAppConfigYAML]
+}
+
+func (r *AppConfigYAMLRegistrarType) SetPathRegistry(fh handler.FunctionRegistry) {
+	fh.SetPathRegistry(appconfigkit.AppConfigYAMLResourceProvider.GetPathRegistry())
+}
@@ -0,0 +1,99 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package appconfig
+
+import (
+	"github.com/confighub/sdk/configkit/appconfigkit"
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/function/handler"
+	"github.com/confighub/sdk/function/internal/handlers/generic"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func registerStandardFunctions(fh handler.FunctionRegistry) error {
+	if err := generic.RegisterStandardFunctions(fh, appconfigkit.AppConfigYAMLResourceProvider, appconfigkit.AppConfigYAMLResourceProvider); err != nil {
+		return err
+	}
+	fh.RegisterFunction("validate", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "validate",
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "passed",
+				Description: "True if the configuration passes validation, false otherwise",
+				OutputType:  api.OutputTypeValidationResult,
+			},
+			Mutating:              false,
+			Validating:            true,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Returns true if the configuration passes validation",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: appConfigFnValidate,
+	})
+	return nil
+}
+
+// This is also defined in the bridge.
+const NamespaceProperty = "configHub.kubernetes.namespace"
+
+func initStandardFunctions() {
+	basicNameTemplate := generic.StandardNameTemplate(appconfigkit.AppConfigYAMLResourceProvider.NameSeparator())
+	var defaultNames = api.ResourceTypeToPathToVisitorInfoType{
+		api.ResourceTypeAny: {
+			api.UnresolvedPath(appconfigkit.AppConfigYAMLResourceProvider.ScopelessResourceNamePath()): {
+				Path:          api.UnresolvedPath(appconfigkit.AppConfigYAMLResourceProvider.ScopelessResourceNamePath()),
+				AttributeName: api.AttributeNameResourceName,
+				DataType:      api.DataTypeString,
+				Info:          &api.AttributeDetails{GenerationTemplate: basicNameTemplate},
+			},
+		},
+	}
+	setterFunctionInvocation := &api.FunctionInvocation{
+		FunctionName: "set-default-names",
+	}
+	for resourceType, pathInfos := range defaultNames {
+		yamlkit.MustRegisterPathsByAttributeName(
+			appconfigkit.AppConfigYAMLResourceProvider,
+			api.AttributeNameDefaultName,
+			resourceType,
+			pathInfos,
+			nil,
+			setterFunctionInvocation,
+			false,
+		)
+		yamlkit.MustRegisterPathsByAttributeName(
+			appconfigkit.AppConfigYAMLResourceProvider,
+			api.AttributeNameGeneral,
+			resourceType,
+			pathInfos,
+			nil,
+			setterFunctionInvocation,
+			true,
+		)
+	}
+
+	path := api.UnresolvedPath(NamespaceProperty)
+	pathInfos := api.PathToVisitorInfoType{
+		path: {
+			Path:          path,
+			AttributeName: api.AttributeNameResourceName,
+			DataType:      api.DataTypeString,
+		},
+	}
+	// Function to set the value. The parameters are expected to match the corresponding
+	// get function's parameters plus its result.
+	setterFunctionInvocation = &api.FunctionInvocation{
+		FunctionName: "set-references-of-type",
+		Arguments:    []api.FunctionArgument{{ParameterName: "resource-type", Value: "v1/Namespace"}},
+	}
+	yamlkit.MustRegisterNeededPaths(appconfigkit.AppConfigYAMLResourceProvider, api.ResourceTypeAny, pathInfos, setterFunctionInvocation)
+}
+
+func appConfigFnValidate(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	// The configuration data is freeform, so it's valid as long as it parses, which it did
+	return parsedData, api.ValidationResultTrue, nil
+}
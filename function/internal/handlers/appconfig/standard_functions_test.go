@@ -0,0 +1,110 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package appconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/function/handler"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const appConfigFixture = `configHub:
+  configName: payments-config
+service:
+  name: payments
+  port: 8080
+  logLevel: info
+`
+
+func newTestHandler() *handler.FunctionHandler {
+	fh := handler.NewFunctionHandler()
+	AppConfigYAMLRegistrar.RegisterFunctions(fh)
+	AppConfigYAMLRegistrar.SetPathRegistry(fh)
+	return fh
+}
+
+func invoke(t *testing.T, fh *handler.FunctionHandler, functionName string, docs gaby.Container, args ...any) (gaby.Container, any) {
+	functionArgs := make([]api.FunctionArgument, len(args))
+	for i, arg := range args {
+		functionArgs[i].Value = arg
+	}
+	docs, output, err := fh.ListCore()[functionName].Function(&api.FunctionContext{}, docs, functionArgs, []byte{})
+	assert.NoError(t, err)
+	return docs, output
+}
+
+func TestStandardFunctions(t *testing.T) {
+	fh := newTestHandler()
+	docs, err := gaby.ParseAll([]byte(appConfigFixture))
+	assert.NoError(t, err)
+
+	_, output := invoke(t, fh, "get-resources", docs)
+	resources, ok := output.(api.ResourceList)
+	if assert.True(t, ok) && assert.Len(t, resources, 1) {
+		assert.Equal(t, api.ResourceName("payments-config"), resources[0].ResourceName)
+		assert.Equal(t, api.ResourceType("app-config/yaml"), resources[0].ResourceType)
+	}
+
+	docs, _ = invoke(t, fh, "set-string-path", docs, "app-config/yaml", "service.logLevel", "debug")
+	docs, _ = invoke(t, fh, "set-int-path", docs, "app-config/yaml", "service.port", 9090)
+	assert.Equal(t, "debug", docs[0].Path("service.logLevel").Data())
+	assert.Equal(t, 9090, docs[0].Path("service.port").Data())
+
+	_, output = invoke(t, fh, "get-string-path", docs, "app-config/yaml", "service.name")
+	values, ok := output.(api.AttributeValueList)
+	if assert.True(t, ok) && assert.Len(t, values, 1) {
+		assert.Equal(t, "payments", values[0].Value)
+	}
+
+	_, output = invoke(t, fh, "validate", docs)
+	assert.Equal(t, api.ValidationResultTrue, output)
+}
+
+func TestFloatPathFunctions(t *testing.T) {
+	fh := newTestHandler()
+	docs, err := gaby.ParseAll([]byte(`configHub:
+  configName: payments-config
+service:
+  sampleRate: 0.5
+  maxBytes: 1.5e3
+  timeoutMultiplier: 2
+`))
+	assert.NoError(t, err)
+
+	_, output := invoke(t, fh, "get-float-path", docs, "app-config/yaml", "service.sampleRate")
+	values, ok := output.(api.AttributeValueList)
+	if assert.True(t, ok) && assert.Len(t, values, 1) {
+		assert.Equal(t, 0.5, values[0].Value)
+		assert.Equal(t, api.DataTypeFloat, values[0].DataType)
+	}
+	// Floats may be written without fractional parts
+	_, output = invoke(t, fh, "get-float-path", docs, "app-config/yaml", "service.timeoutMultiplier")
+	values, ok = output.(api.AttributeValueList)
+	if assert.True(t, ok) && assert.Len(t, values, 1) {
+		assert.Equal(t, 2.0, values[0].Value)
+	}
+	_, _, err = fh.ListCore()["get-float-path"].Function(&api.FunctionContext{}, docs, []api.FunctionArgument{{Value: "app-config/yaml"}, {Value: "configHub.configName"}}, []byte{})
+	assert.Error(t, err)
+
+	docs, _ = invoke(t, fh, "set-float-path", docs, "app-config/yaml", "service.sampleRate", 0.25)
+	docs, _ = invoke(t, fh, "set-float-path", docs, "app-config/yaml", "service.maxBytes", 2048.0)
+	docs, _ = invoke(t, fh, "set-float-path", docs, "app-config/yaml", "service.backoff", 1.5)
+	assert.Equal(t, `configHub:
+  configName: payments-config
+service:
+  sampleRate: 0.25
+  maxBytes: 2048.0
+  timeoutMultiplier: 2
+  backoff: 1.5
+`, docs.String())
+
+	// Floats without fractional parts are read back as floats
+	reparsed, err := gaby.ParseAll([]byte(docs.String()))
+	assert.NoError(t, err)
+	assert.Equal(t, 2048.0, reparsed[0].Path("service.maxBytes").Data())
+}
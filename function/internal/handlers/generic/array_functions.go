@@ -0,0 +1,67 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func genericFnEnsureArrayCount(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	resourceType := api.ResourceType(args[0].Value.(string))
+	unresolvedPath := api.UnresolvedPath(args[1].Value.(string))
+	count := args[2].Value.(int)
+	if count < 0 {
+		return parsedData, nil, fmt.Errorf("count must not be negative: %d", count)
+	}
+	var templateNode *yaml.Node
+	if len(args) > 3 && args[3].Value.(string) != "" {
+		templateDoc, err := gaby.ParseYAML([]byte(args[3].Value.(string)))
+		if err != nil {
+			return parsedData, nil, fmt.Errorf("failed to parse template: %w", err)
+		}
+		templateNode = templateDoc.YNode()
+	}
+
+	resourceTypeToPaths := GetVisitorMapForPath(resourceProvider, resourceType, unresolvedPath)
+	visitor := func(doc *gaby.YamlDoc, output any, context yamlkit.VisitorContext, currentDoc *gaby.YamlDoc) (any, error) {
+		if !currentDoc.IsArray() {
+			return output, fmt.Errorf("path %s of %s %s is not an array", context.Path, context.ResourceInfo.ResourceType, context.ResourceInfo.ResourceName)
+		}
+		return output, ensureArrayCount(currentDoc, count, templateNode)
+	}
+	_, err := yamlkit.VisitPathsDoc(parsedData, resourceTypeToPaths, []any{}, nil, resourceProvider, visitor, false)
+	return parsedData, nil, err
+}
+
+// ensureArrayCount removes elements from the end of the array or appends copies of the template
+// node, or of the last element if the template is nil, until the array has count elements.
+func ensureArrayCount(array *gaby.YamlDoc, count int, templateNode *yaml.Node) error {
+	elements := array.Children()
+	for i := len(elements) - 1; i >= count; i-- {
+		if err := array.ArrayRemove(i); err != nil {
+			return err
+		}
+	}
+	if len(elements) >= count {
+		return nil
+	}
+	if templateNode == nil {
+		if len(elements) == 0 {
+			return fmt.Errorf("a template is required to add elements to an empty array")
+		}
+		templateNode = elements[len(elements)-1].YNode()
+	}
+	arrayNode := array.YNode()
+	for i := len(elements); i < count; i++ {
+		arrayNode.Content = append(arrayNode.Content, yaml.CopyYNode(templateNode))
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const ensureArrayCountFixture = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - name: http
+    port: 80
+  - name: https
+    port: 443
+`
+
+func runEnsureArrayCount(t *testing.T, yaml string, path string, count int, template string) (gaby.Container, error) {
+	parsedData, err := gaby.ParseAll([]byte(yaml))
+	require.NoError(t, err)
+	args := []api.FunctionArgument{
+		{ParameterName: "resource-type", Value: "v1/Service"},
+		{ParameterName: "path", Value: path},
+		{ParameterName: "count", Value: count},
+	}
+	if template != "" {
+		args = append(args, api.FunctionArgument{ParameterName: "template", Value: template})
+	}
+	result, _, err := genericFnEnsureArrayCount(k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, args, nil)
+	return result, err
+}
+
+func TestEnsureArrayCount_Grow(t *testing.T) {
+	result, err := runEnsureArrayCount(t, ensureArrayCountFixture, "spec.ports", 4, "")
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - name: http
+    port: 80
+  - name: https
+    port: 443
+  - name: https
+    port: 443
+  - name: https
+    port: 443
+`, result.String())
+
+	// The appended elements are copies, not aliases of the last element
+	_, err = result[0].SetP(8443, "spec.ports.3.port")
+	require.NoError(t, err)
+	assert.Equal(t, 443, result[0].Path("spec.ports.2.port").Data())
+}
+
+func TestEnsureArrayCount_Shrink(t *testing.T) {
+	result, err := runEnsureArrayCount(t, ensureArrayCountFixture, "spec.ports", 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - name: http
+    port: 80
+`, result.String())
+
+	result, err = runEnsureArrayCount(t, ensureArrayCountFixture, "spec.ports", 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(result[0].Path("spec.ports").Children()))
+}
+
+func TestEnsureArrayCount_Template(t *testing.T) {
+	result, err := runEnsureArrayCount(t, ensureArrayCountFixture, "spec.ports", 3, "name: metrics\nport: 9090\n")
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - name: http
+    port: 80
+  - name: https
+    port: 443
+  - name: metrics
+    port: 9090
+`, result.String())
+
+	// The template is not needed when the array already has enough elements
+	result, err = runEnsureArrayCount(t, ensureArrayCountFixture, "spec.ports", 2, "name: metrics\nport: 9090\n")
+	require.NoError(t, err)
+	assert.Equal(t, ensureArrayCountFixture, result.String())
+}
+
+func TestEnsureArrayCount_Errors(t *testing.T) {
+	_, err := runEnsureArrayCount(t, ensureArrayCountFixture, "spec.ports", -1, "")
+	assert.Error(t, err)
+
+	_, err = runEnsureArrayCount(t, ensureArrayCountFixture, "metadata.name", 2, "")
+	assert.ErrorContains(t, err, "not an array")
+
+	emptyPorts := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports: []\n"
+	_, err = runEnsureArrayCount(t, emptyPorts, "spec.ports", 1, "")
+	assert.ErrorContains(t, err, "template is required")
+
+	result, err := runEnsureArrayCount(t, emptyPorts, "spec.ports", 1, "name: http\nport: 80\n")
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(result[0].Path("spec.ports").Children()))
+}
@@ -0,0 +1,81 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const copyResourceFixture = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: prod
+data:
+  mode: production
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings-canary
+  namespace: prod
+data:
+  mode: canary
+`
+
+func copyResource(t *testing.T, args ...any) (gaby.Container, error) {
+	parsedData, err := gaby.ParseAll([]byte(copyResourceFixture))
+	assert.NoError(t, err)
+	parameterNames := []string{"resource-type", "source-name", "destination-name", "overwrite"}
+	functionArgs := make([]api.FunctionArgument, len(args))
+	for i, arg := range args {
+		functionArgs[i] = api.FunctionArgument{ParameterName: parameterNames[i], Value: arg}
+	}
+	result, _, err := genericFnCopyResource(k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, functionArgs, nil)
+	return result, err
+}
+
+func TestCopyResource(t *testing.T) {
+	result, err := copyResource(t, "v1/ConfigMap", "settings", "settings-copy")
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.Equal(t, copyResourceFixture+`---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings-copy
+  namespace: prod
+data:
+  mode: production
+`, result.String())
+
+	// The copy is independent of the original
+	_, err = result[2].SetP("staging", "data.mode")
+	assert.NoError(t, err)
+	assert.Equal(t, "production", result[0].Path("data.mode").Data())
+	assert.Equal(t, "settings", result[0].Path("metadata.name").Data())
+}
+
+func TestCopyResourceOverwrite(t *testing.T) {
+	_, err := copyResource(t, "v1/ConfigMap", "settings", "settings-canary")
+	assert.EqualError(t, err, "resource with type v1/ConfigMap and name settings-canary already exists")
+
+	result, err := copyResource(t, "v1/ConfigMap", "settings", "settings-canary", true)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "settings-canary", result[1].Path("metadata.name").Data())
+	assert.Equal(t, "production", result[1].Path("data.mode").Data())
+	assert.Equal(t, "settings", result[0].Path("metadata.name").Data())
+}
+
+func TestCopyResourceMissingSource(t *testing.T) {
+	_, err := copyResource(t, "v1/ConfigMap", "other", "other-copy")
+	assert.EqualError(t, err, "resource with type v1/ConfigMap and name other not found")
+}
@@ -0,0 +1,138 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const driftConfigFixture = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+  namespace: prod
+  labels:
+    app: backend
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: backend
+        image: backend:1.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: backend-config
+  namespace: prod
+data:
+  key: value
+`
+
+const driftLiveStateFixture = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+  namespace: prod
+  uid: 3f0d2f6e-3a55-4f5c-9a0e-6f6b2c0a7d4e
+  resourceVersion: "12345"
+  generation: 3
+  creationTimestamp: "2025-01-01T00:00:00Z"
+  annotations:
+    deployment.kubernetes.io/revision: "3"
+  labels:
+    app: backend
+    team: payments
+spec:
+  replicas: 5
+  template:
+    spec:
+      containers:
+      - name: backend
+        image: backend:1.0
+status:
+  replicas: 5
+  readyReplicas: 5
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: backend-config
+  namespace: prod
+  resourceVersion: "678"
+data:
+  key: value
+`
+
+func runDrift(t *testing.T, liveState string) api.ResourceMutationList {
+	parsedData, err := gaby.ParseAll([]byte(driftConfigFixture))
+	assert.NoError(t, err)
+	_, output, err := genericFnDrift(k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, nil, []byte(liveState))
+	assert.NoError(t, err)
+	drift, ok := output.(api.ResourceMutationList)
+	assert.True(t, ok)
+	return drift
+}
+
+func TestDrift_LabelAndReplicas(t *testing.T) {
+	drift := runDrift(t, driftLiveStateFixture)
+	// The ConfigMap differs only in server-populated fields, so only the Deployment has drifted
+	if !assert.Len(t, drift, 1) {
+		return
+	}
+	assert.Equal(t, api.ResourceType("apps/v1/Deployment"), drift[0].Resource.ResourceType)
+	assert.Equal(t, api.ResourceName("prod/backend"), drift[0].Resource.ResourceName)
+	assert.Equal(t, api.MutationTypeUpdate, drift[0].ResourceMutationInfo.MutationType)
+	assert.Equal(t, api.MutationMap{
+		"metadata.labels.team": {MutationType: api.MutationTypeAdd, Predicate: true, Value: "payments\n"},
+		"spec.replicas":        {MutationType: api.MutationTypeUpdate, Predicate: true, Value: "5\n"},
+	}, drift[0].PathMutationMap)
+}
+
+func TestDrift_MissingAndExtraResources(t *testing.T) {
+	liveState := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+  namespace: prod
+  labels:
+    app: backend
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: backend
+        image: backend:1.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: backend
+  namespace: prod
+spec:
+  ports:
+  - port: 80
+`
+	drift := runDrift(t, liveState)
+	if !assert.Len(t, drift, 2) {
+		return
+	}
+	assert.Equal(t, api.ResourceType("v1/Service"), drift[0].Resource.ResourceType)
+	assert.Equal(t, api.MutationTypeAdd, drift[0].ResourceMutationInfo.MutationType)
+	assert.Equal(t, api.ResourceName("prod/backend-config"), drift[1].Resource.ResourceName)
+	assert.Equal(t, api.MutationTypeDelete, drift[1].ResourceMutationInfo.MutationType)
+}
+
+func TestDrift_NoLiveState(t *testing.T) {
+	assert.Empty(t, runDrift(t, ""))
+	assert.Empty(t, runDrift(t, "\n"))
+}
@@ -0,0 +1,98 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const expandEnvFixture = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  cluster: ${CLUSTER_NAME}
+  url: https://$HOST:8080/${UNDEFINED}
+  price: $$5
+  unit: ${UnitSlug}
+  command: echo $(POD_NAME)
+`
+
+func runExpandEnv(t *testing.T, strict bool, pairs ...string) (gaby.Container, error) {
+	parsedData, err := gaby.ParseAll([]byte(expandEnvFixture))
+	assert.NoError(t, err)
+	args := []api.FunctionArgument{{ParameterName: "strict", Value: strict}}
+	for _, pair := range pairs {
+		args = append(args, api.FunctionArgument{ParameterName: "env-key-value", Value: pair})
+	}
+	functionContext := &api.FunctionContext{UnitSlug: "my-unit"}
+	result, _, err := genericFnExpandEnv(k8skit.K8sResourceProvider, functionContext, parsedData, args, nil)
+	return result, err
+}
+
+func TestExpandEnv_NonStrict(t *testing.T) {
+	result, err := runExpandEnv(t, false, "CLUSTER_NAME=prod", "HOST=example.com")
+	assert.NoError(t, err)
+	expected := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  cluster: prod
+  url: https://example.com:8080/${UNDEFINED}
+  price: $5
+  unit: my-unit
+  command: echo $(POD_NAME)
+`
+	assert.YAMLEq(t, expected, result.String())
+}
+
+func TestExpandEnv_Strict(t *testing.T) {
+	result, err := runExpandEnv(t, true, "CLUSTER_NAME=prod", "HOST=example.com")
+	assert.ErrorContains(t, err, "undefined variables: UNDEFINED")
+	// Nothing is expanded when an error is returned
+	assert.Equal(t, "${CLUSTER_NAME}", result[0].Path("data.cluster").Data())
+
+	result, err = runExpandEnv(t, true, "CLUSTER_NAME=prod", "HOST=example.com", "UNDEFINED=defined")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com:8080/defined", result[0].Path("data.url").Data())
+}
+
+func TestExpandEnv_Escaping(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"$$", "$"},
+		{"$$HOST", "$HOST"},
+		{"$$$HOST", "$example.com"},
+		{"${HOST}$$", "example.com$"},
+		{"cost: $", "cost: $"},
+		{"${HOST", "${HOST"},
+		{"$1", "$1"},
+	}
+	variables := map[string]string{"HOST": "example.com"}
+	for _, tt := range tests {
+		actual, undefined, err := expandEnv(tt.input, variables)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, actual, "input %q", tt.input)
+		assert.Empty(t, undefined)
+	}
+}
+
+func TestExpandEnv_EmptyReference(t *testing.T) {
+	_, _, err := expandEnv("prefix-${}", map[string]string{"": "value"})
+	assert.ErrorContains(t, err, "empty variable reference")
+}
+
+func TestExpandEnv_InvalidPair(t *testing.T) {
+	_, err := runExpandEnv(t, false, "=value")
+	assert.Error(t, err)
+}
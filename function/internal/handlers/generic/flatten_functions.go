@@ -0,0 +1,162 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const defaultFlattenSeparator = "."
+
+// flattenArgs returns the resource type, map path, and key separator arguments of flatten and unflatten.
+func flattenArgs(args []api.FunctionArgument) (api.ResourceType, string, string) {
+	resourceType := api.ResourceType(args[0].Value.(string))
+	path := ""
+	if len(args) > 1 {
+		path = args[1].Value.(string)
+	}
+	separator := defaultFlattenSeparator
+	if len(args) > 2 && args[2].Value.(string) != "" {
+		separator = args[2].Value.(string)
+	}
+	return resourceType, path, separator
+}
+
+// visitFlattenMaps calls visitor with the map node at the specified path in each resource of the
+// specified type. Resources that don't contain the path are skipped.
+func visitFlattenMaps(resourceProvider yamlkit.ResourceProvider, parsedData gaby.Container, resourceType api.ResourceType, path string, visitor func(node *yaml.Node) error) error {
+	for _, doc := range parsedData {
+		docResourceType, err := resourceProvider.ResourceTypeGetter(doc)
+		if err != nil {
+			return err
+		}
+		if docResourceType != resourceType {
+			continue
+		}
+		mapDoc := doc
+		if path != "" {
+			if !doc.ExistsP(path) {
+				continue
+			}
+			mapDoc = doc.Path(path)
+		}
+		node := mapDoc.YNode()
+		if node.Kind != yaml.MappingNode {
+			resourceName, _ := resourceProvider.ResourceNameGetter(doc)
+			return fmt.Errorf("path %q in resource %s is not a map", path, resourceName)
+		}
+		if err := visitor(node); err != nil {
+			resourceName, _ := resourceProvider.ResourceNameGetter(doc)
+			return fmt.Errorf("resource %s: %w", resourceName, err)
+		}
+	}
+	return nil
+}
+
+func genericFnFlatten(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	resourceType, path, separator := flattenArgs(args)
+	err := visitFlattenMaps(resourceProvider, parsedData, resourceType, path, func(node *yaml.Node) error {
+		flat := &yaml.Node{Kind: yaml.MappingNode, Tag: yaml.NodeTagMap}
+		if err := flattenMappingNode(node, "", separator, flat); err != nil {
+			return err
+		}
+		node.Content = flat.Content
+		return nil
+	})
+	return parsedData, nil, err
+}
+
+// flattenMappingNode appends the leaves of the nested maps of node to flat, with keys that join
+// the keys of the enclosing maps with the separator. Arrays, scalars, and empty maps are leaves.
+func flattenMappingNode(node *yaml.Node, prefix, separator string, flat *yaml.Node) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		segment := keyNode.Value
+		if separator == defaultFlattenSeparator {
+			segment = yamlkit.EscapeDotsInPathSegment(segment)
+		} else if strings.Contains(segment, separator) {
+			return fmt.Errorf("key %q contains the separator %q", segment, separator)
+		}
+		key := segment
+		if prefix != "" {
+			key = prefix + separator + segment
+		}
+		if valueNode.Kind == yaml.MappingNode && len(valueNode.Content) > 0 {
+			if err := flattenMappingNode(valueNode, key, separator, flat); err != nil {
+				return err
+			}
+			continue
+		}
+		flatKeyNode := *keyNode
+		flatKeyNode.Value = key
+		flat.Content = append(flat.Content, &flatKeyNode, valueNode)
+	}
+	return nil
+}
+
+func genericFnUnflatten(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	resourceType, path, separator := flattenArgs(args)
+	err := visitFlattenMaps(resourceProvider, parsedData, resourceType, path, func(node *yaml.Node) error {
+		nested, err := unflattenMappingNode(node, separator)
+		if err != nil {
+			return err
+		}
+		node.Content = nested.Content
+		return nil
+	})
+	return parsedData, nil, err
+}
+
+// unflattenMappingNode returns a map with nested maps for the keys of node split by the separator.
+func unflattenMappingNode(node *yaml.Node, separator string) (*yaml.Node, error) {
+	nested := &yaml.Node{Kind: yaml.MappingNode, Tag: yaml.NodeTagMap}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		segments := strings.Split(keyNode.Value, separator)
+		current := nested
+		for j, segment := range segments {
+			if segment == "" {
+				return nil, fmt.Errorf("key %q contains an empty segment", keyNode.Value)
+			}
+			if separator == defaultFlattenSeparator {
+				segment = strings.ReplaceAll(segment, yamlkit.EscapeDotsInPathSegment(defaultFlattenSeparator), defaultFlattenSeparator)
+			}
+			child := mappingNodeValue(current, segment)
+			if j == len(segments)-1 {
+				if child != nil {
+					return nil, fmt.Errorf("key %q conflicts with another key", keyNode.Value)
+				}
+				nestedKeyNode := *keyNode
+				nestedKeyNode.Value = segment
+				current.Content = append(current.Content, &nestedKeyNode, valueNode)
+				break
+			}
+			if child == nil {
+				child = &yaml.Node{Kind: yaml.MappingNode, Tag: yaml.NodeTagMap}
+				current.Content = append(current.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: yaml.NodeTagString, Value: segment}, child)
+			} else if child.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("key %q conflicts with another key", keyNode.Value)
+			}
+			current = child
+		}
+	}
+	return nested, nil
+}
+
+// mappingNodeValue returns the value of the specified key in the map node, or nil if it isn't present.
+func mappingNodeValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
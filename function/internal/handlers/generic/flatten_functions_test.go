@@ -0,0 +1,111 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const flattenNestedFixture = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  server:
+    http:
+      port: "8080"
+      host: example.com
+    tls:
+      enabled: "true"
+  log.level: debug
+  tags:
+  - a
+  - b
+`
+
+const flattenFlatFixture = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  server.http.port: "8080"
+  server.http.host: example.com
+  server.tls.enabled: "true"
+  log~1level: debug
+  tags:
+  - a
+  - b
+`
+
+func runFlatten(t *testing.T, functionName, yaml string, args ...string) (gaby.Container, error) {
+	parsedData, err := gaby.ParseAll([]byte(yaml))
+	assert.NoError(t, err)
+	functionArgs := []api.FunctionArgument{{ParameterName: "resource-type", Value: "v1/ConfigMap"}}
+	for _, arg := range args {
+		functionArgs = append(functionArgs, api.FunctionArgument{Value: arg})
+	}
+	fn := genericFnFlatten
+	if functionName == "unflatten" {
+		fn = genericFnUnflatten
+	}
+	result, _, err := fn(k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, functionArgs, nil)
+	return result, err
+}
+
+func TestFlatten_RoundTrip(t *testing.T) {
+	flat, err := runFlatten(t, "flatten", flattenNestedFixture, "data")
+	assert.NoError(t, err)
+	assert.Equal(t, flattenFlatFixture, flat.String())
+	assert.Equal(t, "debug", flat[0].S("data", "log~1level").Data())
+
+	nested, err := runFlatten(t, "unflatten", flat.String(), "data")
+	assert.NoError(t, err)
+	assert.Equal(t, flattenNestedFixture, nested.String())
+}
+
+func TestFlatten_Separator(t *testing.T) {
+	flat, err := runFlatten(t, "flatten", flattenNestedFixture, "data", "/")
+	assert.NoError(t, err)
+	assert.Equal(t, "8080", flat[0].Path("data.server/http/port").Data())
+	// Dots aren't escaped when they aren't the separator
+	assert.Equal(t, "debug", flat[0].Path("data.log~1level").Data())
+
+	nested, err := runFlatten(t, "unflatten", flat.String(), "data", "/")
+	assert.NoError(t, err)
+	assert.Equal(t, flattenNestedFixture, nested.String())
+
+	_, err = runFlatten(t, "flatten", flattenNestedFixture, "data", "l")
+	assert.ErrorContains(t, err, "contains the separator")
+}
+
+func TestUnflatten_Conflict(t *testing.T) {
+	_, err := runFlatten(t, "unflatten", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  server: example.com
+  server.port: "8080"
+`, "data")
+	assert.ErrorContains(t, err, `key "server.port" conflicts with another key`)
+}
+
+func TestFlatten_SkipsOtherResources(t *testing.T) {
+	const deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+`
+	result, err := runFlatten(t, "flatten", deployment, "data")
+	assert.NoError(t, err)
+	assert.Equal(t, deployment, result.String())
+}
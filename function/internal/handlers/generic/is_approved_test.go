@@ -0,0 +1,90 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func TestIsApproved_Roles(t *testing.T) {
+	parsedData, err := gaby.ParseAll([]byte(expandEnvFixture))
+	assert.NoError(t, err)
+	functionContext := &api.FunctionContext{
+		PreviousContentHash: api.HashConfigData([]byte(parsedData.String())),
+		ApprovedBy: []api.Approver{
+			{Email: "legacy-user", Role: api.ApproverRoleAny},
+			{Email: "alice@example.com", Role: "security"},
+			{Email: "bob@example.com", Role: "security"},
+			{Email: "carol@example.com", Role: "sre"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		numApprovers int
+		approverRole string
+		expected     bool
+	}{
+		{"all roles", 4, "", true},
+		{"one too few in all roles", 5, "", false},
+		{"too few in all roles", 6, "", false},
+		{"explicit any", 4, api.ApproverRoleAny, true},
+		{"security", 2, "security", true},
+		{"one too few security", 3, "security", false},
+		{"sre", 1, "sre", true},
+		{"legacy approvers don't have specific roles", 1, "dba", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []api.FunctionArgument{{ParameterName: "num-approvers", Value: tt.numApprovers}}
+			if tt.approverRole != "" {
+				args = append(args, api.FunctionArgument{ParameterName: "approver-role", Value: tt.approverRole})
+			}
+			_, output, err := genericFnIsApproved(k8skit.K8sResourceProvider, functionContext, parsedData, args, nil)
+			assert.NoError(t, err)
+			result := output.(api.ValidationResult)
+			assert.Equal(t, tt.expected, result.Passed)
+			if tt.expected {
+				assert.Equal(t, api.ValidationSeverityPass, result.Severity)
+			} else {
+				assert.Equal(t, api.ValidationSeverityFail, result.Severity)
+			}
+		})
+	}
+
+	args := []api.FunctionArgument{{ParameterName: "num-approvers", Value: 3}, {ParameterName: "approver-role", Value: "security"}}
+	_, result, err := genericFnIsApproved(k8skit.K8sResourceProvider, functionContext, parsedData, args, nil)
+	assert.NoError(t, err)
+	// One missing approval still fails, but is only reported as a warning
+	assert.False(t, result.(api.ValidationResult).Passed)
+	assert.Equal(t, []api.ValidationDetail{{Message: "2 of 3 required approvals", Severity: api.ValidationSeverityWarn}}, result.(api.ValidationResult).Details)
+
+	// Approvers from older clients only have users, which count in any role
+	legacyContext := &api.FunctionContext{}
+	require.NoError(t, json.Unmarshal([]byte(`{"ApprovedBy": ["alice@example.com", "bob@example.com"]}`), legacyContext))
+	legacyContext.PreviousContentHash = functionContext.PreviousContentHash
+	args = []api.FunctionArgument{{ParameterName: "num-approvers", Value: 2}}
+	_, result, err = genericFnIsApproved(k8skit.K8sResourceProvider, legacyContext, parsedData, args, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, api.ValidationResultTrue, result)
+	args = append(args, api.FunctionArgument{ParameterName: "approver-role", Value: "security"})
+	_, result, err = genericFnIsApproved(k8skit.K8sResourceProvider, legacyContext, parsedData, args, nil)
+	assert.NoError(t, err)
+	assert.False(t, result.(api.ValidationResult).Passed)
+
+	// Approvals don't apply to changed data
+	functionContext.PreviousContentHash++
+	args = []api.FunctionArgument{{ParameterName: "num-approvers", Value: 1}, {ParameterName: "approver-role", Value: "security"}}
+	_, result, err = genericFnIsApproved(k8skit.K8sResourceProvider, functionContext, parsedData, args, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, api.ValidationResultFalse, result)
+}
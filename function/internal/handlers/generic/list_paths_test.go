@@ -0,0 +1,104 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const listPathsFixture = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: prod
+  annotations:
+    example.com/owner: team-a
+data:
+  config.yaml: "debug: true"
+  empty: {}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: app
+        ports:
+        - containerPort: 8080
+        args: []
+`
+
+func listPaths(t *testing.T, args ...api.FunctionArgument) api.AttributeValueList {
+	parsedData, err := gaby.ParseAll([]byte(listPathsFixture))
+	assert.NoError(t, err)
+	_, output, err := genericFnListPaths(k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, args, nil)
+	assert.NoError(t, err)
+	paths, ok := output.(api.AttributeValueList)
+	assert.True(t, ok)
+	return paths
+}
+
+func pathValues(paths api.AttributeValueList) map[api.ResolvedPath]any {
+	values := map[api.ResolvedPath]any{}
+	for _, path := range paths {
+		values[path.Path] = path.Value
+	}
+	return values
+}
+
+func TestListPaths(t *testing.T) {
+	paths := listPaths(t)
+	assert.Len(t, paths, 14)
+
+	// Keys containing dots are escaped, and empty maps and arrays are leaves
+	configMapPaths := listPaths(t, api.FunctionArgument{ParameterName: "resource-type", Value: "v1/ConfigMap"})
+	assert.Equal(t, map[api.ResolvedPath]any{
+		"apiVersion":         "v1",
+		"kind":               "ConfigMap",
+		"metadata.name":      "settings",
+		"metadata.namespace": "prod",
+		"metadata.annotations.example~1com/owner": "team-a",
+		"data.config~1yaml":                       "debug: true",
+		"data.empty":                              map[string]any{},
+	}, pathValues(configMapPaths))
+	for _, path := range configMapPaths {
+		assert.Equal(t, api.ResourceType("v1/ConfigMap"), path.ResourceType)
+		assert.Equal(t, api.ResourceName("prod/settings"), path.ResourceName)
+	}
+
+	deploymentPaths := listPaths(t, api.FunctionArgument{ParameterName: "resource-type", Value: "apps/v1/Deployment"})
+	assert.Equal(t, map[api.ResolvedPath]any{
+		"apiVersion":                           "apps/v1",
+		"kind":                                 "Deployment",
+		"metadata.name":                        "web",
+		"spec.replicas":                        2,
+		"spec.template.spec.containers.0.name": "app",
+		"spec.template.spec.containers.0.ports.0.containerPort": 8080,
+		"spec.template.spec.containers.0.args":                  []any{},
+	}, pathValues(deploymentPaths))
+}
+
+func TestListPathsMaxDepth(t *testing.T) {
+	paths := listPaths(t,
+		api.FunctionArgument{ParameterName: "resource-type", Value: "apps/v1/Deployment"},
+		api.FunctionArgument{ParameterName: "max-depth", Value: 2},
+	)
+	values := pathValues(paths)
+	assert.Len(t, values, 5)
+	assert.Equal(t, 2, values["spec.replicas"])
+	template, ok := values["spec.template"].(map[string]any)
+	if assert.True(t, ok) {
+		assert.Contains(t, template, "spec")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+// Key orders supported by normalize
+const (
+	KeyOrderOriginal     = "original"
+	KeyOrderAlphabetical = "alphabetical"
+	KeyOrderKubernetes   = "kubernetes"
+)
+
+// kubernetesKeyPriority lists the top-level keys that precede all others, in order, in the
+// Kubernetes key order. Other keys, and the keys of nested maps, retain their original order.
+var kubernetesKeyPriority = []string{"apiVersion", "kind", "metadata"}
+
+func compareKubernetesKeys(a, b string) int {
+	priorityA := slices.Index(kubernetesKeyPriority, a)
+	priorityB := slices.Index(kubernetesKeyPriority, b)
+	switch {
+	case priorityA == priorityB:
+		return 0
+	case priorityA == -1:
+		return 1
+	case priorityB == -1:
+		return -1
+	default:
+		return priorityA - priorityB
+	}
+}
+
+func genericFnNormalize(_ yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	keyOrder := KeyOrderOriginal
+	opts := gaby.DefaultSerializeOptions
+	opts.NormalizeStyles = true
+	for _, arg := range args {
+		switch arg.ParameterName {
+		case "key-order":
+			if arg.Value.(string) != "" {
+				keyOrder = arg.Value.(string)
+			}
+		}
+	}
+	var sortKeys func(doc *gaby.YamlDoc)
+	switch keyOrder {
+	case KeyOrderOriginal:
+	case KeyOrderAlphabetical:
+		sortKeys = func(doc *gaby.YamlDoc) { doc.SortKeys(strings.Compare) }
+	case KeyOrderKubernetes:
+		sortKeys = func(doc *gaby.YamlDoc) { doc.SortTopLevelKeys(compareKubernetesKeys) }
+	default:
+		return parsedData, nil, fmt.Errorf("unsupported key order %q; expected %s, %s, or %s", keyOrder, KeyOrderOriginal, KeyOrderAlphabetical, KeyOrderKubernetes)
+	}
+
+	for i, doc := range parsedData {
+		if doc.IsEmptyDoc() {
+			continue
+		}
+		if sortKeys != nil {
+			sortKeys(doc)
+		}
+		// Re-parse the re-emitted document so that subsequent normalization is a no-op
+		normalizedYAML, err := doc.StringWithOptions(opts)
+		if err != nil {
+			return parsedData, nil, err
+		}
+		normalizedDoc, err := gaby.ParseYAML([]byte(normalizedYAML))
+		if err != nil {
+			return parsedData, nil, err
+		}
+		parsedData[i] = normalizedDoc
+	}
+	return parsedData, nil, nil
+}
@@ -0,0 +1,123 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const normalizeFixture = "metadata:\n" +
+	"  namespace: default\n" +
+	"  name: web   \n" +
+	"  labels: {app: web, 'tier': \"frontend\"}\n" +
+	"kind: Deployment\n" +
+	"spec:\n" +
+	"  replicas: 2 # desired replicas   \n" +
+	"  template:\n" +
+	"    spec:\n" +
+	"      containers:\n" +
+	"      - image: nginx\n" +
+	"        name: nginx\n" +
+	"        args: ['--port', \"8080\", 'true']\n" +
+	"apiVersion: apps/v1\n"
+
+func runNormalize(t *testing.T, yaml string, keyOrder string) string {
+	parsedData, err := gaby.ParseAll([]byte(yaml))
+	require.NoError(t, err)
+	var args []api.FunctionArgument
+	if keyOrder != "" {
+		args = append(args, api.FunctionArgument{ParameterName: "key-order", Value: keyOrder})
+	}
+	result, _, err := genericFnNormalize(k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, args, nil)
+	require.NoError(t, err)
+	return result.String()
+}
+
+func TestNormalize_KubernetesOrder(t *testing.T) {
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  namespace: default
+  name: web
+  labels:
+    app: web
+    tier: frontend
+spec:
+  replicas: 2 # desired replicas
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+        args:
+        - --port
+        - "8080"
+        - "true"
+`, runNormalize(t, normalizeFixture, KeyOrderKubernetes))
+
+	// Keys of nested maps aren't reordered
+	assert.Equal(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  name: web
+  kind: frontend
+`, runNormalize(t, "data:\n  name: web\n  kind: frontend\nkind: ConfigMap\nmetadata:\n  name: settings\napiVersion: v1\n", KeyOrderKubernetes))
+}
+
+func TestNormalize_AlphabeticalOrder(t *testing.T) {
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+    tier: frontend
+  name: web
+  namespace: default
+spec:
+  replicas: 2 # desired replicas
+  template:
+    spec:
+      containers:
+      - args:
+        - --port
+        - "8080"
+        - "true"
+        image: nginx
+        name: nginx
+`, runNormalize(t, normalizeFixture, KeyOrderAlphabetical))
+}
+
+func TestNormalize_Idempotent(t *testing.T) {
+	for _, keyOrder := range []string{"", KeyOrderOriginal, KeyOrderAlphabetical, KeyOrderKubernetes} {
+		once := runNormalize(t, normalizeFixture, keyOrder)
+		twice := runNormalize(t, once, keyOrder)
+		assert.Equal(t, once, twice, "key order %q", keyOrder)
+	}
+
+	// The original key order is preserved by default
+	normalized := runNormalize(t, normalizeFixture, "")
+	parsedData, err := gaby.ParseAll([]byte(normalized))
+	require.NoError(t, err)
+	original, err := gaby.ParseAll([]byte(normalizeFixture))
+	require.NoError(t, err)
+	assert.Equal(t, original[0].Data(), parsedData[0].Data())
+	assert.Equal(t, "metadata", parsedData[0].YNode().Content[0].Value)
+}
+
+func TestNormalize_InvalidKeyOrder(t *testing.T) {
+	parsedData, err := gaby.ParseAll([]byte(normalizeFixture))
+	require.NoError(t, err)
+	args := []api.FunctionArgument{{ParameterName: "key-order", Value: "random"}}
+	_, _, err = genericFnNormalize(k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, args, nil)
+	assert.ErrorContains(t, err, "unsupported key order")
+}
@@ -0,0 +1,104 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func TestOverlayUnit(t *testing.T) {
+	base := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+  labels:
+    app: web
+    legacy: "true"
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: web:1.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+spec:
+  ports:
+  - port: 80
+`
+	overlay := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+  labels:
+    legacy: null
+    tier: frontend
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: web:2.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-settings
+  namespace: prod
+data:
+  mode: production
+`
+	parsedData, err := gaby.ParseAll([]byte(base))
+	assert.NoError(t, err)
+	args := []api.FunctionArgument{{ParameterName: "overlay", Value: overlay}}
+	result, _, err := genericFnOverlayUnit(k8skit.K8sResourceProvider, k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, args, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+  labels:
+    app: web
+    tier: frontend
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: web:2.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-settings
+  namespace: prod
+data:
+  mode: production
+`, result.String())
+}
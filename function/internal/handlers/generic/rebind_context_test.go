@@ -0,0 +1,30 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func TestRebindContext_NoContextPath(t *testing.T) {
+	const data = `metadata:
+  annotations:
+    confighub.com/UnitSlug: old-unit
+`
+	parsedData, err := gaby.ParseAll([]byte(data))
+	assert.NoError(t, err)
+	resourceProvider := yamlkit.NewMockResourceProvider().WithContextPath("UnitSlug", "")
+	functionContext := &api.FunctionContext{UnitSlug: "new-unit", SpaceID: uuid.New()}
+
+	result, _, err := genericFnRebindContext(resourceProvider, functionContext, parsedData, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, data, result.String())
+}
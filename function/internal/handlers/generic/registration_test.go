@@ -0,0 +1,35 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/function/handler"
+)
+
+func TestRegisterStandardFunctions_Conflicts(t *testing.T) {
+	const resourceType = api.ResourceType("apps/v1/Deployment")
+	const path = api.UnresolvedPath("spec.replicas")
+
+	// The mock resource provider has its own path registry
+	resourceProvider := yamlkit.NewMockResourceProvider()
+	assert.NoError(t, RegisterStandardFunctions(handler.NewFunctionHandler(), k8skit.K8sResourceProvider, resourceProvider))
+
+	for i, dataType := range []api.DataType{api.DataTypeInt, api.DataTypeString} {
+		err := yamlkit.RegisterPathsByAttributeName(resourceProvider, api.AttributeNameGeneral, resourceType,
+			api.PathToVisitorInfoType{path: {Path: path, AttributeName: api.AttributeNameGeneral, DataType: dataType}}, nil, nil, false)
+		// Only the second registration conflicts
+		assert.Equal(t, i == 1, err != nil)
+	}
+	var conflict *yamlkit.RegistrationConflictError
+	err := RegisterStandardFunctions(handler.NewFunctionHandler(), k8skit.K8sResourceProvider, resourceProvider)
+	assert.ErrorAs(t, err, &conflict)
+	assert.Equal(t, path, conflict.Path)
+}
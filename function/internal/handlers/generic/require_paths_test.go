@@ -0,0 +1,99 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const requirePathsFixture = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: secure
+  namespace: prod
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: true
+      containers:
+      - name: app
+        image: app:1.0
+        resources:
+          limits:
+            memory: 128Mi
+      - name: sidecar
+        image: sidecar:1.0
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: insecure
+  namespace: prod
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: prod
+`
+
+func runRequirePaths(t *testing.T, paths string) (api.ValidationResult, error) {
+	parsedData, err := gaby.ParseAll([]byte(requirePathsFixture))
+	assert.NoError(t, err)
+	args := []api.FunctionArgument{
+		{ParameterName: "resource-type", Value: "apps/v1/Deployment"},
+		{ParameterName: "paths", Value: paths},
+	}
+	_, output, err := genericFnRequirePaths(k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, args, nil)
+	result, ok := output.(api.ValidationResult)
+	assert.True(t, ok)
+	return result, err
+}
+
+func TestRequirePaths_Present(t *testing.T) {
+	result, err := runRequirePaths(t, "metadata.name, spec.template.spec.containers.0.image")
+	assert.NoError(t, err)
+	assert.True(t, result.Passed)
+}
+
+func TestRequirePaths_Missing(t *testing.T) {
+	result, err := runRequirePaths(t, "spec.template.spec.securityContext,spec.replicas")
+	assert.NoError(t, err)
+	assert.False(t, result.Passed)
+	assert.Equal(t, []string{
+		"resource prod/secure is missing path spec.replicas",
+		"resource prod/insecure is missing path spec.template.spec.securityContext",
+		"resource prod/insecure is missing path spec.replicas",
+	}, result.DetailMessages())
+}
+
+func TestRequirePaths_Wildcard(t *testing.T) {
+	// Only one container of the first Deployment has resources, which is sufficient
+	result, err := runRequirePaths(t, "spec.template.spec.containers.*.resources")
+	assert.NoError(t, err)
+	assert.False(t, result.Passed)
+	assert.Equal(t, []string{"resource prod/insecure is missing path spec.template.spec.containers.*.resources"}, result.DetailMessages())
+
+	result, err = runRequirePaths(t, "spec.template.spec.containers.?name=sidecar.image")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"resource prod/insecure is missing path spec.template.spec.containers.?name=sidecar.image"}, result.DetailMessages())
+}
+
+func TestRequirePaths_NoPaths(t *testing.T) {
+	_, err := runRequirePaths(t, " , ")
+	assert.Error(t, err)
+}
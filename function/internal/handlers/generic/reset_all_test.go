@@ -0,0 +1,29 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func TestResetAll_Float(t *testing.T) {
+	const resourceType = api.ResourceType("example.com/v1/Weighted")
+	const path = api.UnresolvedPath("spec.weight")
+
+	resourceProvider := yamlkit.NewMockResourceProvider()
+	assert.NoError(t, yamlkit.RegisterPathsByAttributeName(resourceProvider, api.AttributeNameGeneral, resourceType,
+		api.PathToVisitorInfoType{path: {Path: path, AttributeName: api.AttributeNameGeneral, DataType: api.DataTypeFloat}}, nil, nil, false))
+
+	docs, err := gaby.ParseAll([]byte("apiVersion: example.com/v1\nkind: Weighted\nmetadata:\n  name: example\nspec:\n  weight: 0.5\n"))
+	assert.NoError(t, err)
+	docs, _, err = genericFnResetAll(resourceProvider, &api.FunctionContext{}, docs, []api.FunctionArgument{{ParameterName: "resource-type", Value: string(resourceType)}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, yamlkit.PlaceHolderBlockApplyFloat, docs[0].Path("spec.weight").Data())
+}
@@ -5,9 +5,14 @@ package generic
 
 import (
 	"bytes"
+	"cmp"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -15,6 +20,7 @@ import (
 	"github.com/cockroachdb/errors/join"
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
+	"github.com/google/uuid"
 	"github.com/labstack/gommon/log"
 	"sigs.k8s.io/yaml"
 
@@ -69,7 +75,13 @@ func RegisterComputeMutations(fh handler.FunctionRegistry, converter configkit.C
 	})
 }
 
-func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.ConfigConverter, resourceProvider yamlkit.ResourceProvider) {
+// RegisterStandardFunctions registers the standard functions supported by all toolchains. The
+// paths of the resource provider must be registered first, since it returns an error for
+// conflicting path registrations, which are programming errors that should fail initialization.
+func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.ConfigConverter, resourceProvider yamlkit.ResourceProvider) error {
+	// Conflicts are retained by the resource provider and returned by ValidateRegistry below.
+	_ = yamlkit.RegisterContextPaths(resourceProvider)
+
 	fh.RegisterFunction("get-resources", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
 			FunctionName: "get-resources",
@@ -157,6 +169,36 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			return genericFnSetReferencesOfType(resourceProvider, functionContext, parsedData, args, liveState)
 		},
 	})
+	fh.RegisterFunction("update-name-references", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "update-name-references",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      true,
+					Description:   "Type (" + resourceProvider.TypeDescription() + ") of the renamed config elements",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "name-mapping",
+					Required:      true,
+					Description:   "JSON object mapping the old names to the new names",
+					DataType:      api.DataTypeString,
+					Example:       `{"old-name": "new-name"}`,
+				},
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Update references to renamed config elements of the specified type from their old names to their new names",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnUpdateNameReferences(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
 	fh.RegisterFunction("get-placeholders", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
 			FunctionName: "get-placeholders",
@@ -226,6 +268,74 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			return genericFnSearchReplace(resourceProvider, functionContext, parsedData, args, liveState)
 		},
 	})
+	minMaxDepth := 0
+	fh.RegisterFunction("list-paths", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "list-paths",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      false,
+					Description:   "Type (" + resourceProvider.TypeDescription() + ") of the resources whose paths to list; all resources by default",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName:    "max-depth",
+					Required:         false,
+					Description:      "Maximum number of path segments; maps and arrays at this depth are returned as values. 0, the default, is unlimited",
+					DataType:         api.DataTypeInt,
+					Example:          "3",
+					ValueConstraints: api.ValueConstraints{Min: &minMaxDepth},
+				},
+			},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "paths",
+				Description: "Paths and values of all leaves of the resources",
+				OutputType:  api.OutputTypeAttributeValueList,
+			},
+			Mutating:              false,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "List the paths and values of all scalars and empty maps and arrays of the resources, to discover the paths accepted by other functions",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnListPaths(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
+	fh.RegisterFunction("expand-env", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "expand-env",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "strict",
+					Required:      true,
+					Description:   "If true, references to undefined variables are errors; otherwise they are left intact",
+					DataType:      api.DataTypeBool,
+				},
+				{
+					ParameterName: "env-key-value",
+					Required:      false,
+					Description:   "key=value format of a variable to substitute; overrides variables from the function context",
+					DataType:      api.DataTypeString,
+					Example:       "CLUSTER_NAME=prod-us-east-1",
+				},
+			},
+			VarArgs:               true,
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            false,
+			Description:           "Replace ${KEY} and $KEY references in all strings of all resource types with the values of the specified variables or of the function context fields, such as UnitSlug and SpaceSlug; $$ is replaced with $",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnExpandEnv(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
 	fh.RegisterFunction("get-string-path", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
 			FunctionName: "get-string-path",
@@ -364,6 +474,75 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			return GenericFnSetIntPath(resourceProvider, functionContext, parsedData, args, liveState, true)
 		},
 	})
+	fh.RegisterFunction("get-float-path", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "get-float-path",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      true,
+					Description:   "Resource type (" + resourceProvider.TypeDescription() + ") of the attribute to get",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "path",
+					Required:      true,
+					Description:   "Path whose value to get",
+					DataType:      api.DataTypeString,
+				},
+			},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "path",
+				Description: "Value of the specified resource path",
+				OutputType:  api.OutputTypeAttributeValueList,
+			},
+			Mutating:              false,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Returns the value(s) of the specified attribute path",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return GenericFnGetFloatPath(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
+	fh.RegisterFunction("set-float-path", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "set-float-path",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      true,
+					Description:   "Resource type (" + resourceProvider.TypeDescription() + ") of the attribute to set",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "path",
+					Required:      true,
+					Description:   "Path of the attribute to set",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "attribute-value",
+					Required:      true,
+					Description:   "Value to set the attribute to",
+					DataType:      api.DataTypeFloat,
+				},
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Set the value(s) of the specified attribute path",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return GenericFnSetFloatPath(resourceProvider, functionContext, parsedData, args, liveState, true)
+		},
+	})
 	fh.RegisterFunction("get-bool-path", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
 			FunctionName: "get-bool-path",
@@ -470,7 +649,16 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 	})
 	fh.RegisterFunction("set-default-names", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
-			FunctionName:          "set-default-names",
+			FunctionName: "set-default-names",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "name-template",
+					Required:      false,
+					Description:   "Go template used to construct the names, overriding the default template registered for each path; fields available are NormalizedUnitName, NormalizedSpaceName, NormalizedResourceName, TrimmedResourceName, and NormalizedResourceType",
+					DataType:      api.DataTypeString,
+					Example:       "{{.NormalizedSpaceName}}-{{.NormalizedResourceName}}",
+				},
+			},
 			Mutating:              true,
 			Validating:            false,
 			Hermetic:              true,
@@ -504,6 +692,34 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			return genericFnGetAttributes(resourceProvider, functionContext, parsedData, args, liveState)
 		},
 	})
+	fh.RegisterFunction("describe-attributes", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "describe-attributes",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      false,
+					Description:   "Resource type (" + resourceProvider.TypeDescription() + ") of the attributes to describe, or * for all resource types; defaults to *",
+					DataType:      api.DataTypeString,
+				},
+			},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "attribute",
+				Description: "Descriptions of the registered attributes",
+				OutputType:  api.OutputTypeCustomJSON,
+			},
+			Mutating:              false,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Returns the registered attributes of the specified resource type, including their data types, value constraints, and getter and setter functions, whether or not they are present in the configuration data",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnDescribeAttributes(fh, resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
 	fh.RegisterFunction("set-attributes", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
 			FunctionName: "set-attributes",
@@ -567,6 +783,46 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			return genericFnGetProvided(resourceProvider, functionContext, parsedData, args, liveState)
 		},
 	})
+	fh.RegisterFunction("get-links", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "get-links",
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "links",
+				Description: "List of Needed attributes paired with Provided attributes that could satisfy them",
+				OutputType:  api.OutputTypeCustomJSON,
+			},
+			Mutating:              false,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Returns proposed links between Needed and Provided attributes",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnGetLinks(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
+	fh.RegisterFunction("drift", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "drift",
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "mutations",
+				Description: "List of mutations that would transform the config data into the live state",
+				OutputType:  api.OutputTypeResourceMutationList,
+			},
+			Mutating:              false,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Diffs the config data with the live state and returns the differences as a list of mutations; resources only in the live state are Adds and resources only in the config data are Deletes",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnDrift(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
 	fh.RegisterFunction("cel-validate", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
 			FunctionName: "cel-validate",
@@ -594,7 +850,7 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
 		},
 		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
-			return genericFnCELValidate(resourceProvider, functionContext, parsedData, args, liveState)
+			return genericFnCELValidate(resourceProvider, fh.CELExtensions(), functionContext, parsedData, args, liveState)
 		},
 	})
 	fh.RegisterFunction("where-filter", &handler.FunctionRegistration{
@@ -631,6 +887,83 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			return genericFnResourceWhereMatch(resourceProvider, functionContext, parsedData, args, liveState)
 		},
 	})
+	fh.RegisterFunction("where-validate", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "where-validate",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      true,
+					Description:   "Resource type (" + resourceProvider.TypeDescription() + ") to validate",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "selector-expression",
+					Required:      true,
+					Description:   "Where filter selecting the resources to validate, with the same syntax as the where-expression of where-filter; a blank expression selects all resources of the type",
+					DataType:      api.DataTypeString,
+					Example:       "metadata.labels.environment = 'prod'",
+				},
+				{
+					ParameterName: "validator-expression",
+					Required:      true,
+					Description:   "Where filter that each selected resource must match, with the same syntax as the where-expression of where-filter",
+					DataType:      api.DataTypeString,
+					Example:       "spec.replicas >= 2",
+				},
+			},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "passed",
+				Description: "True if every selected resource matched the validator, false otherwise",
+				OutputType:  api.OutputTypeValidationResult,
+			},
+			Mutating:              false,
+			Validating:            true,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           `Returns true if every resource of the specified type either doesn't match the selector expression or matches the validator expression`,
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnResourceWhereValidate(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
+	fh.RegisterFunction("require-paths", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "require-paths",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      true,
+					Description:   "Resource type (" + resourceProvider.TypeDescription() + ") to validate",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "paths",
+					Required:      true,
+					Description:   "Comma-separated list of paths that must exist; paths with wildcards or associative lookups must match at least one path",
+					DataType:      api.DataTypeString,
+					Example:       "spec.template.spec.securityContext,spec.template.spec.containers.*.resources",
+				},
+			},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "passed",
+				Description: "True if all of the paths exist in all resources of the specified type, false otherwise",
+				OutputType:  api.OutputTypeValidationResult,
+			},
+			Mutating:              false,
+			Validating:            true,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Returns true if all of the specified paths exist in all resources of the specified type",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnRequirePaths(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
 	fh.RegisterFunction("yq", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
 			FunctionName: "yq",
@@ -641,20 +974,34 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 					Description:   "yq expression",
 					DataType:      api.DataTypeString,
 				},
+				{
+					ParameterName: "mutating",
+					Required:      false,
+					Description:   "Whether to replace the configuration data with the result of the expression, which must contain an assignment, such as .spec.replicas = 3; defaults to false",
+					DataType:      api.DataTypeBool,
+				},
+				{
+					ParameterName: "per-document",
+					Required:      false,
+					Description:   "Whether to evaluate the expression on each document separately, like yq eval, rather than on all of the documents together, like yq eval-all; defaults to false. Mutating expressions are always evaluated on each document separately",
+					DataType:      api.DataTypeBool,
+				},
 			},
 			OutputInfo: &api.FunctionOutput{
 				ResultName:  "yq output",
 				Description: "Output from yq",
 				OutputType:  api.OutputTypeYAML,
 			},
+			// Invocations only change the configuration data if the mutating argument is true
 			Mutating:              false,
 			Validating:            false,
 			Hermetic:              true,
 			Idempotent:            true,
-			Description:           "Returns the result of running yq with the specified expression on the YAML configuration data",
+			Description:           "Returns the result of running yq with the specified expression on the YAML configuration data, and if mutating is true, applies its assignments to each document of the configuration data",
 			FunctionType:          api.FunctionTypeCustom,
 			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
 		},
+		MutatingParameterName: "mutating",
 		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
 			return genericFnYQ(resourceProvider, functionContext, parsedData, args, liveState)
 		},
@@ -669,10 +1016,17 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 					Description:   "Number of approvers",
 					DataType:      api.DataTypeInt,
 				},
+				{
+					ParameterName: "approver-role",
+					Required:      false,
+					Description:   "Role the approvers must have approved in; if omitted or \"any\", approvers in all roles are counted",
+					DataType:      api.DataTypeString,
+					Example:       "security",
+				},
 			},
 			OutputInfo: &api.FunctionOutput{
 				ResultName:  "passed",
-				Description: "True if approvers are present, false otherwise",
+				Description: "True if approvers are present, false otherwise; the failure is reported as a warning if only one more approval is needed",
 				OutputType:  api.OutputTypeValidationResult,
 			},
 			Mutating:              false,
@@ -697,6 +1051,12 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 					Description:   "Context is set if true and removed if false",
 					DataType:      api.DataTypeBool,
 				},
+				{
+					ParameterName: "inject-labels",
+					Required:      false,
+					Description:   "If true and context is set, the " + contextLabelKey + " label of the space is also set in resources that support labels",
+					DataType:      api.DataTypeBool,
+				},
 			},
 			Mutating:              true,
 			Validating:            false,
@@ -710,6 +1070,21 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			return genericFnEnsureContext(resourceProvider, functionContext, parsedData, args, liveState)
 		},
 	})
+	fh.RegisterFunction("rebind-context", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName:          "rebind-context",
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Update the unit slug and space ID context values already present in configuration resource/element attributes to those of the current unit, such as after the unit was cloned or moved; context isn't added or removed",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnRebindContext(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
 
 	fh.RegisterFunction("get-details", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
@@ -797,6 +1172,47 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			return genericFnDeleteResource(resourceProvider, functionContext, parsedData, args, liveState)
 		},
 	})
+	fh.RegisterFunction("copy-resource", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "copy-resource",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      true,
+					Description:   "Type (" + resourceProvider.TypeDescription() + ") of the resource/element to copy",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "source-name",
+					Required:      true,
+					Description:   "Name of the resource/element to copy",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "destination-name",
+					Required:      true,
+					Description:   "Name of the copy",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "overwrite",
+					Required:      false,
+					Description:   "If true, replace an existing resource/element of the type with the destination name rather than failing",
+					DataType:      api.DataTypeBool,
+				},
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            false,
+			Description:           "Copy the specified configuration resource/element under a new name",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnCopyResource(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
 
 	RegisterComputeMutations(fh, converter, resourceProvider)
 
@@ -829,6 +1245,29 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			return genericFnPatchMutations(resourceProvider, functionContext, parsedData, args, liveState)
 		},
 	})
+	fh.RegisterFunction("overlay-unit", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "overlay-unit",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "overlay",
+					Required:      true,
+					Description:   "Config data of another unit to overlay onto the config data; keys set to null are removed",
+					DataType:      api.DataTypeString,
+				},
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Overlays the resources of the provided config data onto resources with the same type and name, merging maps and replacing other values, and appends the other resources",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnOverlayUnit(converter, resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
 	fh.RegisterFunction("reset", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
 			FunctionName: "reset",
@@ -852,6 +1291,29 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			return genericFnReset(resourceProvider, functionContext, parsedData, args, liveState)
 		},
 	})
+	fh.RegisterFunction("reset-all", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "reset-all",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      true,
+					Description:   "Resource type (" + resourceProvider.TypeDescription() + ") of the attributes to reset, or * for all resource types",
+					DataType:      api.DataTypeString,
+				},
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Sets all registered attributes of the specified resource type back to placeholder values",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnResetAll(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
 	fh.RegisterFunction("replicate", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
 			FunctionName: "replicate",
@@ -893,6 +1355,149 @@ func RegisterStandardFunctions(fh handler.FunctionRegistry, converter configkit.
 			return genericFnReplicate(resourceProvider, functionContext, parsedData, args, liveState)
 		},
 	})
+	fh.RegisterFunction("ensure-array-count", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "ensure-array-count",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      true,
+					Description:   "Resource type (" + resourceProvider.TypeDescription() + ") of the resources containing the array",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "path",
+					Required:      true,
+					Description:   "Path of the array within each resource",
+					DataType:      api.DataTypeString,
+					Example:       "spec.ports",
+				},
+				{
+					ParameterName: "count",
+					Required:      true,
+					Description:   "Desired number of elements of the array",
+					DataType:      api.DataTypeInt,
+				},
+				{
+					ParameterName: "template",
+					Required:      false,
+					Description:   "YAML of the element to append when the array has fewer elements than count; defaults to a copy of the last element",
+					DataType:      api.DataTypeString,
+				},
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Remove elements from the end of the specified array or append copies of its last element or of a template until it has count elements",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnEnsureArrayCount(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
+	fh.RegisterFunction("normalize", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "normalize",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "key-order",
+					Required:      false,
+					Description:   "Order of the keys of maps: " + KeyOrderOriginal + " (the default), " + KeyOrderAlphabetical + ", or " + KeyOrderKubernetes + ", which puts apiVersion, kind, and metadata first at the top level of each document",
+					DataType:      api.DataTypeString,
+				},
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Re-emit the configuration data with canonical key order, block style, quoting, and indentation, and without trailing whitespace in comments, without changing its values",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnNormalize(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
+	fh.RegisterFunction("flatten", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "flatten",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      true,
+					Description:   "Resource type (" + resourceProvider.TypeDescription() + ") of the resources to flatten",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "path",
+					Required:      false,
+					Description:   "Path of the map to flatten within each resource; the whole resource if empty",
+					DataType:      api.DataTypeString,
+					Example:       "data",
+				},
+				{
+					ParameterName: "separator",
+					Required:      false,
+					Description:   "Separator between the keys of nested maps in flattened keys; defaults to `.`. Dots within keys are escaped as `~1` when the separator is `.`",
+					DataType:      api.DataTypeString,
+				},
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            false,
+			Description:           "Convert the nested maps at the specified path into a single map with separator-joined keys, as in `a.b.c: value`",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnFlatten(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
+	fh.RegisterFunction("unflatten", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "unflatten",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      true,
+					Description:   "Resource type (" + resourceProvider.TypeDescription() + ") of the resources to unflatten",
+					DataType:      api.DataTypeString,
+				},
+				{
+					ParameterName: "path",
+					Required:      false,
+					Description:   "Path of the map to unflatten within each resource; the whole resource if empty",
+					DataType:      api.DataTypeString,
+					Example:       "data",
+				},
+				{
+					ParameterName: "separator",
+					Required:      false,
+					Description:   "Separator between the keys of nested maps in flattened keys; defaults to `.`. Dots within keys are escaped as `~1` when the separator is `.`",
+					DataType:      api.DataTypeString,
+				},
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            false,
+			Description:           "Convert the separator-joined keys of the map at the specified path into nested maps; the inverse of flatten",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: func(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+			return genericFnUnflatten(resourceProvider, functionContext, parsedData, args, liveState)
+		},
+	})
+
+	// Paths should all be registered by now, so catch conflicting registrations early.
+	if errs := yamlkit.ValidateRegistry(resourceProvider); len(errs) > 0 {
+		return errors.Wrap(join.Join(errs...), "path registry")
+	}
+	return nil
 }
 
 func attributeNameForResourceType(resourceType api.ResourceType) api.AttributeName {
@@ -906,21 +1511,8 @@ func genericFnGetResources(converter configkit.ConfigConverter, resourceProvider
 		bodyFormat = strings.ToLower(args[0].Value.(string))
 	}
 
-	list := make(api.ResourceList, 0, len(parsedData))
-	for _, doc := range parsedData {
-		resourceCategory, err := resourceProvider.ResourceCategoryGetter(doc)
-		if err != nil {
-			return parsedData, nil, err
-		}
-		resourceType, err := resourceProvider.ResourceTypeGetter(doc)
-		if err != nil {
-			return parsedData, nil, err
-		}
-		resourceName, err := resourceProvider.ResourceNameGetter(doc)
-		if err != nil {
-			return parsedData, nil, err
-		}
-
+	// The visitor only reads the documents, so they can be serialized in parallel
+	visitor := func(doc *gaby.YamlDoc, output any, _ int, resourceInfo *api.ResourceInfo) (any, []error) {
 		var resourceBody string
 		switch bodyFormat {
 		case "none":
@@ -928,14 +1520,14 @@ func genericFnGetResources(converter configkit.ConfigConverter, resourceProvider
 		case "json":
 			jsonBytes, err := doc.MarshalJSON()
 			if err != nil {
-				return parsedData, nil, err
+				return output, []error{err}
 			}
 			resourceBody = string(jsonBytes)
 		case "native":
 			yamlBytes := []byte(doc.String())
 			nativeBytes, err := converter.YAMLToNative(yamlBytes)
 			if err != nil {
-				return parsedData, nil, err
+				return output, []error{err}
 			}
 			resourceBody = string(nativeBytes)
 		case "yaml":
@@ -944,17 +1536,16 @@ func genericFnGetResources(converter configkit.ConfigConverter, resourceProvider
 			resourceBody = doc.String()
 		}
 
-		list = append(list, api.Resource{
-			ResourceInfo: api.ResourceInfo{
-				ResourceName:             resourceName,
-				ResourceNameWithoutScope: resourceProvider.RemoveScopeFromResourceName(resourceName),
-				ResourceType:             resourceType,
-				ResourceCategory:         resourceCategory,
-			},
+		return append(output.(api.ResourceList), api.Resource{
+			ResourceInfo: *resourceInfo,
 			ResourceBody: resourceBody,
-		})
+		}), nil
 	}
-	return parsedData, list, nil
+	output, err := yamlkit.VisitResourcesParallel(parsedData, make(api.ResourceList, 0, len(parsedData)), resourceProvider, visitor, runtime.GOMAXPROCS(0))
+	if err != nil {
+		return parsedData, nil, err
+	}
+	return parsedData, output.(api.ResourceList), nil
 }
 
 func genericFnGetResourcesOfType(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
@@ -991,21 +1582,58 @@ func genericFnSetReferencesOfType(resourceProvider yamlkit.ResourceProvider, _ *
 	return parsedData, nil, err
 }
 
+func genericFnUpdateNameReferences(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	resourceType := args[0].Value.(string)
+	nameMappingJSON := args[1].Value.(string)
+
+	var nameMapping map[string]string
+	if err := json.Unmarshal([]byte(nameMappingJSON), &nameMapping); err != nil {
+		return parsedData, nil, errors.Wrap(err, "name-mapping must be a JSON object mapping old names to new names")
+	}
+	// Chained renames would not be idempotent, since a second invocation would rename again.
+	for oldName, newName := range nameMapping {
+		if oldName == "" || newName == "" {
+			return parsedData, nil, errors.New("names in name-mapping must not be empty")
+		}
+		if _, isOldName := nameMapping[newName]; isOldName && newName != oldName {
+			return parsedData, nil, errors.Newf("new name %s is also renamed in name-mapping", newName)
+		}
+	}
+
+	paths := yamlkit.GetPathRegistryForAttributeName(resourceProvider, attributeNameForResourceType(api.ResourceType(resourceType)))
+	if paths == nil {
+		return parsedData, nil, nil
+	}
+	updater := func(currentValue string) string {
+		if newName, ok := nameMapping[currentValue]; ok {
+			return newName
+		}
+		return currentValue
+	}
+	err := yamlkit.UpdateStringPathsFunction(parsedData, paths, []any{}, resourceProvider, updater, false)
+	return parsedData, nil, err
+}
+
 func genericFnGetPlaceholders(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, _ []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	return parsedData, findPlaceholders(resourceProvider, parsedData), nil
+}
+
+// findPlaceholders returns the attributes that are or contain placeholders of any type.
+func findPlaceholders(resourceProvider yamlkit.ResourceProvider, parsedData gaby.Container) api.AttributeValueList {
 	paths := yamlkit.FindYAMLPathsByValue(parsedData, resourceProvider, yamlkit.PlaceHolderBlockApplyString)
 	paths = append(paths, yamlkit.FindYAMLPathsByValue(parsedData, resourceProvider, yamlkit.DeprecatedPlaceHolderBlockApplyString)...)
 	paths = append(paths, yamlkit.FindYAMLPathsByValue(parsedData, resourceProvider, yamlkit.PlaceHolderBlockApplyInt)...)
-	return parsedData, paths, nil
+	paths = append(paths, yamlkit.FindYAMLPathsByValue(parsedData, resourceProvider, yamlkit.PlaceHolderBlockApplyFloat)...)
+	return paths
 }
 
 func genericFnNoPlaceholders(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, _ []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
-	paths := yamlkit.FindYAMLPathsByValue(parsedData, resourceProvider, yamlkit.PlaceHolderBlockApplyString)
-	paths = append(paths, yamlkit.FindYAMLPathsByValue(parsedData, resourceProvider, yamlkit.DeprecatedPlaceHolderBlockApplyString)...)
-	paths = append(paths, yamlkit.FindYAMLPathsByValue(parsedData, resourceProvider, yamlkit.PlaceHolderBlockApplyInt)...)
-	result := api.ValidationResult{
-		Passed:           len(paths) == 0,
-		FailedAttributes: paths,
+	paths := findPlaceholders(resourceProvider, parsedData)
+	if len(paths) == 0 {
+		return parsedData, api.ValidationResultTrue, nil
 	}
+	result := api.ValidationResultFalse
+	result.FailedAttributes = paths
 	return parsedData, result, nil
 }
 
@@ -1022,6 +1650,181 @@ func genericFnSearchReplace(resourceProvider yamlkit.ResourceProvider, functionC
 	return genericSetAttributesFromList(resourceProvider, functionContext, parsedData, attributeList, liveState)
 }
 
+func genericFnListPaths(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	resourceType := api.ResourceType("")
+	maxDepth := 0
+	for _, arg := range args {
+		switch arg.ParameterName {
+		case "resource-type":
+			resourceType = api.ResourceType(arg.Value.(string))
+		case "max-depth":
+			maxDepth = arg.Value.(int)
+		}
+	}
+
+	paths := yamlkit.FindYAMLLeafPaths(parsedData, resourceProvider, maxDepth)
+	if resourceType == "" {
+		return parsedData, paths, nil
+	}
+	filteredPaths := api.AttributeValueList{}
+	for _, path := range paths {
+		if path.ResourceType == resourceType {
+			filteredPaths = append(filteredPaths, path)
+		}
+	}
+	return parsedData, filteredPaths, nil
+}
+
+// contextVariables returns the function context fields that may be referenced by expand-env.
+func contextVariables(functionContext *api.FunctionContext) map[string]string {
+	variables := map[string]string{
+		"UnitSlug":        functionContext.UnitSlug,
+		"UnitDisplayName": functionContext.UnitDisplayName,
+		"SpaceSlug":       functionContext.SpaceSlug,
+		"ToolchainType":   string(functionContext.ToolchainType),
+	}
+	if functionContext.UnitID != uuid.Nil {
+		variables["UnitID"] = functionContext.UnitID.String()
+	}
+	if functionContext.SpaceID != uuid.Nil {
+		variables["SpaceID"] = functionContext.SpaceID.String()
+	}
+	if functionContext.OrganizationID != uuid.Nil {
+		variables["OrganizationID"] = functionContext.OrganizationID.String()
+	}
+	if functionContext.RevisionNum != 0 {
+		variables["RevisionNum"] = strconv.FormatInt(functionContext.RevisionNum, 10)
+	}
+	for key, value := range variables {
+		if value == "" {
+			delete(variables, key)
+		}
+	}
+	return variables
+}
+
+func isEnvVarNameByte(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+// expandEnv replaces ${KEY} and $KEY references in s with the values of the corresponding variables
+// and $$ with $. References to undefined variables are left intact and their names are returned.
+// An empty reference, ${}, is an error.
+func expandEnv(s string, variables map[string]string) (string, []string, error) {
+	var sb strings.Builder
+	var undefined []string
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 >= len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		switch {
+		case s[i+1] == '$':
+			sb.WriteByte('$')
+			i++
+		case s[i+1] == '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				sb.WriteByte(s[i])
+				continue
+			}
+			name := s[i+2 : i+2+end]
+			if name == "" {
+				return s, nil, fmt.Errorf("empty variable reference ${} in %q", s)
+			}
+			value, found := variables[name]
+			if found {
+				sb.WriteString(value)
+			} else {
+				undefined = append(undefined, name)
+				sb.WriteString(s[i : i+3+end])
+			}
+			i += 2 + end
+		case isEnvVarNameByte(s[i+1], true):
+			end := i + 2
+			for end < len(s) && isEnvVarNameByte(s[end], false) {
+				end++
+			}
+			name := s[i+1 : end]
+			value, found := variables[name]
+			if found {
+				sb.WriteString(value)
+			} else {
+				undefined = append(undefined, name)
+				sb.WriteString(s[i:end])
+			}
+			i = end - 1
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String(), undefined, nil
+}
+
+func genericFnExpandEnv(resourceProvider yamlkit.ResourceProvider, functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	strict := args[0].Value.(bool)
+	variables := contextVariables(functionContext)
+	for _, arg := range args[1:] {
+		pairString := arg.Value.(string)
+		key, value, found := strings.Cut(pairString, "=")
+		if !found || key == "" {
+			return parsedData, nil, fmt.Errorf("invalid key-value pair: %s", pairString)
+		}
+		variables[key] = value
+	}
+
+	// Only strings containing $ need to be visited.
+	attributeList := yamlkit.FindYAMLPathsByValue(parsedData, resourceProvider, "$")
+	if len(attributeList) == 0 {
+		return parsedData, nil, nil
+	}
+	resourceTypeToPaths := api.ResourceTypeToPathToVisitorInfoType{}
+	for _, attribute := range attributeList {
+		_, present := resourceTypeToPaths[attribute.ResourceType]
+		if !present {
+			resourceTypeToPaths[attribute.ResourceType] = api.PathToVisitorInfoType{}
+		}
+		path := api.UnresolvedPath(attribute.Path)
+		resourceTypeToPaths[attribute.ResourceType][path] = &api.PathVisitorInfo{
+			Path:          path,
+			ResolvedPath:  attribute.Path,
+			AttributeName: api.AttributeNameGeneral,
+			DataType:      api.DataTypeString,
+		}
+	}
+
+	// Check all the values before changing any of them so that the configuration isn't
+	// partially expanded when an error is returned.
+	undefinedSet := map[string]struct{}{}
+	for _, attribute := range attributeList {
+		currentValue, _ := attribute.Value.(string)
+		_, undefined, err := expandEnv(currentValue, variables)
+		if err != nil {
+			return parsedData, nil, err
+		}
+		for _, name := range undefined {
+			undefinedSet[name] = struct{}{}
+		}
+	}
+	if strict && len(undefinedSet) > 0 {
+		undefined := make([]string, 0, len(undefinedSet))
+		for name := range undefinedSet {
+			undefined = append(undefined, name)
+		}
+		slices.Sort(undefined)
+		return parsedData, nil, fmt.Errorf("undefined variables: %s", strings.Join(undefined, ", "))
+	}
+
+	updater := func(currentValue string) string {
+		// The values were checked above
+		newValue, _, _ := expandEnv(currentValue, variables)
+		return newValue
+	}
+	err := yamlkit.UpdateStringPathsFunction(parsedData, resourceTypeToPaths, []any{}, resourceProvider, updater, false)
+	return parsedData, nil, err
+}
+
 // GetVisitorMapForPath is used to get visitor info for a resolved path.
 func GetVisitorMapForPath(resourceProvider yamlkit.ResourceProvider, rt api.ResourceType, path api.UnresolvedPath) api.ResourceTypeToPathToVisitorInfoType {
 	visitorInfo := yamlkit.GetPathVisitorInfo(resourceProvider, rt, path)
@@ -1040,6 +1843,10 @@ func GetVisitorMapForPath(resourceProvider yamlkit.ResourceProvider, rt api.Reso
 	} else {
 		visitorInfo.Path = path
 	}
+	if strings.Contains(string(path), "#") && visitorInfo.EmbeddedAccessorType == "" {
+		// Detect the format of embedded values, such as URLs, that don't have registered accessors
+		visitorInfo.EmbeddedAccessorType = api.EmbeddedAccessorAuto
+	}
 	resourceTypeToPaths := api.ResourceTypeToPathToVisitorInfoType{
 		rt: {path: visitorInfo},
 	}
@@ -1088,6 +1895,27 @@ func GenericFnSetIntPath(resourceProvider yamlkit.ResourceProvider, _ *api.Funct
 	return parsedData, nil, err
 }
 
+func GenericFnGetFloatPath(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	resourceType := args[0].Value.(string)
+	unresolvedPath := args[1].Value.(string)
+
+	resourceTypeToPaths := GetVisitorMapForPath(resourceProvider, api.ResourceType(resourceType), api.UnresolvedPath(unresolvedPath))
+	values, err := yamlkit.GetPaths[float64](parsedData, resourceTypeToPaths, []any{}, resourceProvider)
+	return parsedData, values, err
+}
+
+func GenericFnSetFloatPath(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte, upsert bool) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	resourceType := args[0].Value.(string)
+	unresolvedPath := args[1].Value.(string)
+	value := args[2].Value.(float64)
+
+	resourceTypeToPaths := GetVisitorMapForPath(resourceProvider, api.ResourceType(resourceType), api.UnresolvedPath(unresolvedPath))
+	err := yamlkit.UpdatePathsValue[float64](parsedData, resourceTypeToPaths, []any{}, resourceProvider, value, upsert)
+	return parsedData, nil, err
+}
+
 func GenericFnGetBoolPath(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
 	// The argument value types should be verified before this function is called
 	resourceType := args[0].Value.(string)
@@ -1150,33 +1978,63 @@ func trimResourceName(resourceName, typeName, spaceName, unitName, separator str
 	return name
 }
 
-func genericFnSetDefaultNames(resourceProvider yamlkit.ResourceProvider, functionContext *api.FunctionContext, parsedData gaby.Container, _ []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+func nameTemplateFuncMap() template.FuncMap {
+	f := template.FuncMap{}
+	f["toUpper"] = strings.ToUpper
+	f["toLower"] = strings.ToLower
+	f["trimSpace"] = strings.TrimSpace
+	f["trimSuffix"] = strings.TrimSuffix
+	f["trimPrefix"] = strings.TrimPrefix
+	return f
+}
+
+// parseNameTemplate parses the name template and evaluates it with empty NameConstructorArgs
+// so that references to unknown fields are reported before any names are changed.
+func parseNameTemplate(nameTemplate string) (*template.Template, error) {
+	tmpl, err := template.New("name").Funcs(nameTemplateFuncMap()).Parse(nameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name template %q: %w", nameTemplate, err)
+	}
+	err = tmpl.Execute(io.Discard, NameConstructorArgs{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid name template %q: %w", nameTemplate, err)
+	}
+	return tmpl, nil
+}
+
+func genericFnSetDefaultNames(resourceProvider yamlkit.ResourceProvider, functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	var overrideTemplate *template.Template
+	if len(args) > 0 {
+		nameTemplate := args[0].Value.(string)
+		if nameTemplate != "" {
+			var err error
+			overrideTemplate, err = parseNameTemplate(nameTemplate)
+			if err != nil {
+				return parsedData, nil, err
+			}
+		}
+	}
 	visitor := func(doc *gaby.YamlDoc, output any, context yamlkit.VisitorContext, currentValue string) (any, error) {
 		if !strings.Contains(currentValue, yamlkit.PlaceHolderBlockApplyString) &&
 			!strings.Contains(currentValue, yamlkit.DeprecatedPlaceHolderBlockApplyString) {
 			return nil, nil
 		}
-		nameTemplate := context.Info.GenerationTemplate
-		if nameTemplate == "" {
-			log.Errorf("no name constructor template: %v", context.Info)
-			return nil, errors.New("internal error") // TODO: create error type
+		tmpl := overrideTemplate
+		if tmpl == nil {
+			nameTemplate := context.Info.GenerationTemplate
+			if nameTemplate == "" {
+				return nil, fmt.Errorf("%w: no name template registered for path %s of %s %q", yamlkit.ErrInternal, string(context.Path), context.ResourceType, context.ResourceName)
+			}
+			var err error
+			tmpl, err = template.New("name").Funcs(nameTemplateFuncMap()).Parse(nameTemplate)
+			if err != nil {
+				return nil, fmt.Errorf("%w: couldn't parse name template %s registered for path %s of %s %q: %w", yamlkit.ErrInternal, nameTemplate, string(context.Path), context.ResourceType, context.ResourceName, err)
+			}
 		}
 		unitName := resourceProvider.NormalizeName(functionContext.UnitSlug)
 		spaceName := resourceProvider.NormalizeName(functionContext.SpaceSlug)
 		resourceName := resourceProvider.NormalizeName(string(context.ResourceName))
 		resourceType := resourceProvider.NormalizeName(string(context.ResourceType))
-		f := template.FuncMap{}
-		f["toUpper"] = strings.ToUpper
-		f["toLower"] = strings.ToLower
-		f["toUpper"] = strings.ToUpper
-		f["trimSpace"] = strings.TrimSpace
-		f["trimSuffix"] = strings.TrimSuffix
-		f["trimPrefix"] = strings.TrimPrefix
-		tmpl, err := template.New("name").Funcs(f).Parse(nameTemplate)
-		if err != nil {
-			log.Errorf("couldn't parse template %s: %v", nameTemplate, err)
-			return nil, errors.New("internal error") // TODO: create an error type
-		}
 		constructorArgs := NameConstructorArgs{
 			unitName,
 			spaceName,
@@ -1185,16 +2043,15 @@ func genericFnSetDefaultNames(resourceProvider yamlkit.ResourceProvider, functio
 			resourceType,
 		}
 		var out bytes.Buffer
-		err = tmpl.Execute(&out, constructorArgs)
+		err := tmpl.Execute(&out, constructorArgs)
 		if err != nil {
-			log.Errorf("error evaluating template: %v", err)
-			return nil, errors.New("internal error") // TODO: create an error type
+			return nil, fmt.Errorf("%w: couldn't evaluate name template for path %s of %s %q: %w", yamlkit.ErrInternal, string(context.Path), context.ResourceType, context.ResourceName, err)
 		} else {
 			defaultName := out.String()
 			// We can't replace the placeholder string because reset doesn't restore the original
 			// string, it replaces the whole field with the placeholder value. The whole new value
-			// for each specific field is expected to be generated by the default name template.
-			// Once the template strings are made extensible via API they will be easier to customize.
+			// for each specific field is expected to be generated by the default name template,
+			// or by the name-template argument if specified.
 			// newValue := strings.ReplaceAll(currentValue, yamlkit.PlaceHolderBlockApplyString, defaultName)
 			newValue := defaultName
 			_, err = doc.SetP(newValue, string(context.Path))
@@ -1255,6 +2112,17 @@ func genericSetAttributesFromList(resourceProvider yamlkit.ResourceProvider, fun
 					multiErrs = append(multiErrs, err)
 				}
 			}
+		case api.DataTypeFloat:
+			floatValue, ok := attribute.Value.(float64)
+			if !ok {
+				multiErrs = append(multiErrs, fmt.Errorf("value of attribute %s is not float: %v", attribute.AttributeName, attribute.Value))
+			} else {
+				setterArgs[2].Value = floatValue
+				parsedData, _, err = GenericFnSetFloatPath(resourceProvider, functionContext, parsedData, setterArgs, liveState, false)
+				if err != nil {
+					multiErrs = append(multiErrs, err)
+				}
+			}
 		case api.DataTypeBool:
 			boolValue, ok := attribute.Value.(bool)
 			if !ok {
@@ -1282,6 +2150,18 @@ func genericFnGetNeeded(resourceProvider yamlkit.ResourceProvider, _ *api.Functi
 	return parsedData, values, err
 }
 
+func genericFnGetLinks(resourceProvider yamlkit.ResourceProvider, functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+	_, needed, err := genericFnGetNeeded(resourceProvider, functionContext, parsedData, args, liveState)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	_, provided, err := genericFnGetProvided(resourceProvider, functionContext, parsedData, args, liveState)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	return parsedData, yamlkit.MatchNeededProvided(needed.(api.AttributeValueList), provided.(api.AttributeValueList)), nil
+}
+
 func genericFnGetProvided(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, _ []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
 	values, err := yamlkit.GetRegisteredProvidedStringPaths(parsedData, resourceProvider)
 	if err != nil {
@@ -1336,7 +2216,8 @@ func genericFnGetProvided(resourceProvider yamlkit.ResourceProvider, _ *api.Func
 						},
 					},
 				},
-				Value: scopelessResourceName,
+				Value:  scopelessResourceName,
+				Source: api.AttributeSourceLiveState,
 			}
 			values = append(values, attributeValue)
 		}
@@ -1344,12 +2225,11 @@ func genericFnGetProvided(resourceProvider yamlkit.ResourceProvider, _ *api.Func
 	return parsedData, values, nil
 }
 
-func genericFnCELValidate(resourceProvider yamlkit.ResourceProvider, functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+func genericFnCELValidate(resourceProvider yamlkit.ResourceProvider, extensions []cel.EnvOption, functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
 	validationExpr := args[0].Value.(string)
 
-	env, err := cel.NewEnv(
-		cel.Variable("r", cel.DynType),
-	)
+	envOptions := append([]cel.EnvOption{cel.Variable("r", cel.DynType)}, extensions...)
+	env, err := cel.NewEnv(envOptions...)
 	if err != nil {
 		return parsedData, api.ValidationResultFalse, fmt.Errorf("failed to create CEL environment: %v", err)
 	}
@@ -1369,7 +2249,7 @@ func genericFnCELValidate(resourceProvider yamlkit.ResourceProvider, functionCon
 	}
 
 	multiErrors := []error{}
-	details := []string{}
+	details := []api.ValidationDetail{}
 	passed := true
 	for _, doc := range parsedData {
 		var dataMap map[string]any
@@ -1394,7 +2274,10 @@ func genericFnCELValidate(resourceProvider yamlkit.ResourceProvider, functionCon
 		}
 		if val != types.True {
 			passed = false
-			details = append(details, "resource "+string(resourceName)+" failed validation expression "+validationExpr)
+			details = append(details, api.ValidationDetail{
+				Message:  "resource " + string(resourceName) + " failed validation expression " + validationExpr,
+				Severity: api.ValidationSeverityFail,
+			})
 		}
 	}
 
@@ -1464,24 +2347,45 @@ func GenericFnResourceWhereMatchWithComparators(resourceProvider yamlkit.Resourc
 	resourceType := args[0].Value.(string)
 	whereExpr := args[1].Value.(string)
 
-	// Allow blank whereExpr: filter by resourceType only
-	if strings.TrimSpace(whereExpr) == "" {
-		_, categoryTypeMap, err := yamlkit.ResourceAndCategoryTypeMaps(parsedData, resourceProvider)
-		if err != nil {
-			return parsedData, api.ValidationResultFalse, err
-		}
-		for categoryType, names := range categoryTypeMap {
-			// Ignore the category for now.
-			if categoryType.ResourceType == api.ResourceType(resourceType) && len(names) > 0 {
-				return parsedData, api.ValidationResultTrue, nil
+	matchingResources, err := matchResourcesWhere(resourceProvider, customComparators, functionContext, parsedData, resourceType, whereExpr, liveState)
+	if err != nil {
+		return parsedData, api.ValidationResultFalse, err
+	}
+	if len(matchingResources) > 0 {
+		return parsedData, api.ValidationResultTrue, nil
+	}
+	return parsedData, api.ValidationResultFalse, nil
+}
+
+// resourcesOfType returns the names of the resources of the specified type.
+func resourcesOfType(resourceProvider yamlkit.ResourceProvider, parsedData gaby.Container, resourceType string) (map[string]bool, error) {
+	resources := map[string]bool{}
+	_, categoryTypeMap, err := yamlkit.ResourceAndCategoryTypeMaps(parsedData, resourceProvider)
+	if err != nil {
+		return nil, err
+	}
+	for categoryType, names := range categoryTypeMap {
+		// Ignore the category for now.
+		if categoryType.ResourceType == api.ResourceType(resourceType) {
+			for _, name := range names {
+				resources[string(name)] = true
 			}
 		}
-		return parsedData, api.ValidationResultFalse, nil
+	}
+	return resources, nil
+}
+
+// matchResourcesWhere returns the names of the resources of the specified type that match
+// the where expression. A blank expression matches all resources of the type.
+func matchResourcesWhere(resourceProvider yamlkit.ResourceProvider, customComparators []api.CustomStringComparator, functionContext *api.FunctionContext, parsedData gaby.Container, resourceType string, whereExpr string, liveState []byte) (map[string]bool, error) {
+	// Allow blank whereExpr: filter by resourceType only
+	if strings.TrimSpace(whereExpr) == "" {
+		return resourcesOfType(resourceProvider, parsedData, resourceType)
 	}
 
 	expressions, err := api.ParseAndValidateWhereFilter(whereExpr)
 	if err != nil {
-		return parsedData, api.ValidationResultFalse, err
+		return nil, err
 	}
 	// Visit and evaluate.
 	// If we allow wildcards, then theoretically the evaluation could be combinatoric to compare
@@ -1489,9 +2393,8 @@ func GenericFnResourceWhereMatchWithComparators(resourceProvider yamlkit.Resourc
 	// are commutative, we don't need to compare every combination. We can compare them independently
 	// in any order. If any expression evaluates to false for a path that exists, then the resource
 	// is not a match. However, if any resource does match, then the config Unit should match.
-	// We could provide another function that accepts multiple expressions and applies a top-level
-	// disjunction to them to allow for selection (e.g., based on resource type) and validation.
-	// With exactly 2 expressions we could pass validation if !match_expr || validate_expr.
+	// where-validate accepts 2 expressions and applies a top-level disjunction to them to allow
+	// for selection and validation: it passes validation if !match_expr || validate_expr.
 	var multiErrs []error
 	var output any
 	matchingResources := map[string]bool{}
@@ -1543,8 +2446,7 @@ func GenericFnResourceWhereMatchWithComparators(resourceProvider yamlkit.Resourc
 			matchingResourcesForExpression := map[string]bool{}
 			attribValues, ok := output.(api.AttributeValueList)
 			if !ok {
-				log.Errorf("couldn't convert output to api.AttributeValueList")
-				multiErrs = append(multiErrs, fmt.Errorf("internal error"))
+				multiErrs = append(multiErrs, fmt.Errorf("%w: output of type %T for path %s is not api.AttributeValueList", yamlkit.ErrInternal, output, expression.Path))
 				continue
 			}
 			for _, attribValue := range attribValues {
@@ -1569,13 +2471,66 @@ func GenericFnResourceWhereMatchWithComparators(resourceProvider yamlkit.Resourc
 		}
 	}
 	if len(multiErrs) != 0 {
-		err = errors.Join(multiErrs...)
+		return nil, errors.Join(multiErrs...)
+	}
+	return matchingResources, nil
+}
+
+func genericFnResourceWhereValidate(resourceProvider yamlkit.ResourceProvider, functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+	resourceType := args[0].Value.(string)
+	selectorExpr := args[1].Value.(string)
+	validatorExpr := args[2].Value.(string)
+
+	// This is the top-level disjunction !selector || validator, evaluated per resource.
+	selectedResources, err := matchResourcesWhere(resourceProvider, nil, functionContext, parsedData, resourceType, selectorExpr, liveState)
+	if err != nil {
 		return parsedData, api.ValidationResultFalse, err
 	}
-	if len(matchingResources) > 0 {
+	validResources, err := matchResourcesWhere(resourceProvider, nil, functionContext, parsedData, resourceType, validatorExpr, liveState)
+	if err != nil {
+		return parsedData, api.ValidationResultFalse, err
+	}
+	details := []string{}
+	for resourceName := range selectedResources {
+		if !validResources[resourceName] {
+			details = append(details, "resource "+resourceName+" was selected but didn't match "+validatorExpr)
+		}
+	}
+	if len(details) == 0 {
 		return parsedData, api.ValidationResultTrue, nil
 	}
-	return parsedData, api.ValidationResultFalse, nil
+	slices.Sort(details)
+	failedResult := api.ValidationResultFalse
+	failedResult.Details = api.ValidationDetailsWithSeverity(details, api.ValidationSeverityFail)
+	return parsedData, failedResult, nil
+}
+
+func genericFnDrift(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, _ []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+	drift := api.ResourceMutationList{}
+	// Live state may not be available, such as when the unit hasn't been applied.
+	if len(bytes.TrimSpace(liveState)) == 0 {
+		return parsedData, drift, nil
+	}
+	// TODO: handle multiple different possible liveState formats for different providers
+	parsedLiveState, err := gaby.ParseAll(liveState)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	// Fields populated by the API server are not reported as drift
+	if normalizer, ok := resourceProvider.(yamlkit.LiveStateNormalizer); ok {
+		normalizer.RemoveLiveStateOnlyFields(parsedLiveState)
+	}
+
+	mutations, err := yamlkit.ComputeMutations(parsedData, parsedLiveState, 0, resourceProvider)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	for _, mutation := range mutations {
+		if mutation.ResourceMutationInfo.MutationType != api.MutationTypeNone {
+			drift = append(drift, mutation)
+		}
+	}
+	return parsedData, drift, nil
 }
 
 func genericFnComputeMutations(converter configkit.ConfigConverter, resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, modifiedParsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
@@ -1621,6 +2576,19 @@ func genericFnPatchMutations(resourceProvider yamlkit.ResourceProvider, _ *api.F
 	return parsedData, nil, err
 }
 
+func genericFnOverlayUnit(converter configkit.ConfigConverter, resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	overlayData, err := converter.NativeToYAML([]byte(args[0].Value.(string)))
+	if err != nil {
+		return parsedData, nil, err
+	}
+	overlay, err := gaby.ParseAll(overlayData)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	parsedData, err = yamlkit.OverlayResources(parsedData, overlay, resourceProvider)
+	return parsedData, nil, err
+}
+
 func genericFnReset(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
 	mutationPredicatesString := args[0].Value.(string)
 	var mutationsPredicates api.ResourceMutationList
@@ -1633,13 +2601,218 @@ func genericFnReset(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionCo
 	return parsedData, nil, err
 }
 
+func genericFnResetAll(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	resourceType := api.ResourceType(args[0].Value.(string))
+	attributePaths := yamlkit.GetPathRegistryForAttributeName(resourceProvider, api.AttributeNameGeneral)
+	if resourceType != api.ResourceTypeAny {
+		// Paths registered for all resource types also apply to the specified type
+		resourceTypePaths := api.ResourceTypeToPathToVisitorInfoType{}
+		for _, registeredType := range []api.ResourceType{resourceType, api.ResourceTypeAny} {
+			if pathInfos, found := attributePaths[registeredType]; found {
+				resourceTypePaths[registeredType] = pathInfos
+			}
+		}
+		attributePaths = resourceTypePaths
+	}
+	visitor := func(doc *gaby.YamlDoc, output any, context yamlkit.VisitorContext, currentValue any) (any, error) {
+		if resourceType != api.ResourceTypeAny && context.ResourceType != resourceType {
+			return output, nil
+		}
+		// Embedded values can't be reset independently of the containing value
+		if context.EmbeddedPath != "" {
+			return output, nil
+		}
+		var err error
+		switch currentValue.(type) {
+		case string:
+			_, err = doc.SetP(yamlkit.PlaceHolderBlockApplyString, string(context.Path))
+		case int:
+			_, err = doc.SetP(yamlkit.PlaceHolderBlockApplyInt, string(context.Path))
+		case float64:
+			_, err = doc.SetP(yamlkit.PlaceHolderBlockApplyFloat, string(context.Path))
+		default:
+			// Not a leaf or no placeholder value. Skip.
+		}
+		return output, errors.WithStack(err)
+	}
+	_, err := yamlkit.VisitPathsAnyType(parsedData, attributePaths, []any{}, nil, resourceProvider, visitor, false)
+	return parsedData, nil, err
+}
+
+func genericFnDescribeAttributes(fh handler.FunctionRegistry, resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	resourceType := api.ResourceTypeAny
+	if len(args) > 0 && args[0].Value.(string) != "" {
+		resourceType = api.ResourceType(args[0].Value.(string))
+	}
+	type attributeKey struct {
+		resourceType  api.ResourceType
+		path          api.UnresolvedPath
+		attributeName api.AttributeName
+	}
+	// The same path may be registered under multiple attribute names, such as the general and
+	// detail attributes, so the descriptions are merged by the attribute name of the path.
+	descriptionMap := map[attributeKey]*api.AttributeDescription{}
+	yamlkit.ReadPathRegistry(resourceProvider, func(pathRegistry api.AttributeNameToResourceTypeToPathToVisitorInfoType) {
+		for registeredAttributeName, resourceTypePaths := range pathRegistry {
+			for registeredType, pathInfos := range resourceTypePaths {
+				// Paths registered for all resource types also apply to the specified type
+				if resourceType != api.ResourceTypeAny && registeredType != resourceType && registeredType != api.ResourceTypeAny {
+					continue
+				}
+				for path, pathInfo := range pathInfos {
+					attributeName := pathInfo.AttributeName
+					if attributeName == "" {
+						attributeName = registeredAttributeName
+					}
+					key := attributeKey{resourceType: registeredType, path: path, attributeName: attributeName}
+					description, found := descriptionMap[key]
+					if !found {
+						description = &api.AttributeDescription{
+							ResourceType:  registeredType,
+							Path:          path,
+							AttributeName: attributeName,
+							DataType:      pathInfo.DataType,
+						}
+						descriptionMap[key] = description
+					}
+					if pathInfo.Info == nil {
+						continue
+					}
+					if description.Description == "" {
+						description.Description = pathInfo.Info.Description
+					}
+					if description.GetterFunctionName == "" && pathInfo.Info.GetterInvocation != nil {
+						description.GetterFunctionName = pathInfo.Info.GetterInvocation.FunctionName
+					}
+					for _, setterInvocation := range pathInfo.Info.SetterInvocations {
+						if !slices.Contains(description.SetterFunctionNames, setterInvocation.FunctionName) {
+							description.SetterFunctionNames = append(description.SetterFunctionNames, setterInvocation.FunctionName)
+						}
+					}
+				}
+			}
+		}
+	})
+
+	descriptions := make(api.AttributeDescriptionList, 0, len(descriptionMap))
+	for _, description := range descriptionMap {
+		slices.Sort(description.SetterFunctionNames)
+		for _, setterFunctionName := range description.SetterFunctionNames {
+			// The value is the last parameter of the setter
+			signature := fh.GetFunctionSignature(setterFunctionName)
+			if signature == nil || len(signature.Parameters) == 0 {
+				continue
+			}
+			valueConstraints := signature.Parameters[len(signature.Parameters)-1].ValueConstraints
+			if valueConstraints.Regexp != "" || valueConstraints.Min != nil || valueConstraints.Max != nil || len(valueConstraints.EnumValues) > 0 {
+				description.ValueConstraints = &valueConstraints
+				break
+			}
+		}
+		descriptions = append(descriptions, *description)
+	}
+	slices.SortFunc(descriptions, func(a, b api.AttributeDescription) int {
+		return cmp.Or(
+			cmp.Compare(a.ResourceType, b.ResourceType),
+			cmp.Compare(a.AttributeName, b.AttributeName),
+			cmp.Compare(a.Path, b.Path),
+		)
+	})
+	return parsedData, descriptions, nil
+}
+
+func genericFnRequirePaths(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	resourceType := api.ResourceType(args[0].Value.(string))
+	paths := []api.UnresolvedPath{}
+	for _, path := range strings.Split(args[1].Value.(string), ",") {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			paths = append(paths, api.UnresolvedPath(path))
+		}
+	}
+	if len(paths) == 0 {
+		return parsedData, api.ValidationResultFalse, errors.New("no paths specified")
+	}
+
+	details := []string{}
+	for _, doc := range parsedData {
+		docResourceType, err := resourceProvider.ResourceTypeGetter(doc)
+		if err != nil {
+			return parsedData, api.ValidationResultFalse, err
+		}
+		if docResourceType != resourceType {
+			continue
+		}
+		resourceName, err := resourceProvider.ResourceNameGetter(doc)
+		if err != nil {
+			return parsedData, api.ValidationResultFalse, err
+		}
+		for _, path := range paths {
+			if !yamlkit.PathExists(doc, path) {
+				details = append(details, "resource "+string(resourceName)+" is missing path "+string(path))
+			}
+		}
+	}
+
+	if len(details) == 0 {
+		return parsedData, api.ValidationResultTrue, nil
+	}
+	failedResult := api.ValidationResultFalse
+	failedResult.Details = api.ValidationDetailsWithSeverity(details, api.ValidationSeverityFail)
+	return parsedData, failedResult, nil
+}
+
 func genericFnYQ(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
 	// The argument value types should be verified before this function is called
-	expression := args[0].Value.(string)
+	expression := ""
+	mutating := false
+	perDocument := false
+	for _, arg := range args {
+		switch arg.ParameterName {
+		case "yq-expression":
+			expression = arg.Value.(string)
+		case "mutating":
+			mutating = arg.Value.(bool)
+		case "per-document":
+			perDocument = arg.Value.(bool)
+		}
+	}
+	if !mutating {
+		output, err := yamlkit.EvalYQExpressionWithOptions(expression, parsedData.String(), yamlkit.YQOptions{PerDocument: perDocument})
+		return parsedData, api.YAMLPayload{Payload: output}, err
+	}
+	hasAssignment, err := yamlkit.YQExpressionHasAssignment(expression)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	if !hasAssignment {
+		return parsedData, nil, fmt.Errorf("mutating yq expression %s must contain an assignment, such as .spec.replicas = 3", expression)
+	}
 
-	output, err := yamlkit.EvalYQExpression(expression, parsedData.String())
-	wrappedOutput := api.YAMLPayload{Payload: output}
-	return parsedData, wrappedOutput, err
+	// The expression is applied to each document separately so that an expression that
+	// filters the documents can't drop the other documents
+	outputs := []string{}
+	newParsedData := make(gaby.Container, 0, len(parsedData))
+	for i, doc := range parsedData {
+		if doc.IsEmptyDoc() {
+			newParsedData = append(newParsedData, doc)
+			continue
+		}
+		output, err := yamlkit.EvalYQExpression(expression, doc.String())
+		if err != nil {
+			return parsedData, nil, err
+		}
+		newDocs, err := gaby.ParseAll([]byte(output))
+		if err != nil {
+			return parsedData, nil, fmt.Errorf("failed to parse output of yq expression %s: %w", expression, err)
+		}
+		if len(newDocs) != 1 || newDocs[0].IsEmptyDoc() {
+			return parsedData, nil, fmt.Errorf("mutating yq expression %s must produce exactly one document for each document, but produced %d for document %d; enclose the path of a filtered assignment in parentheses, such as (select(.kind == \"Deployment\") | .spec.replicas) = 3", expression, len(newDocs), i)
+		}
+		newParsedData = append(newParsedData, newDocs[0])
+		outputs = append(outputs, output)
+	}
+	return newParsedData, api.YAMLPayload{Payload: strings.Join(outputs, "---\n")}, nil
 }
 
 func genericFnIsApproved(resourceProvider yamlkit.ResourceProvider, functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
@@ -1651,14 +2824,40 @@ func genericFnIsApproved(resourceProvider yamlkit.ResourceProvider, functionCont
 		return parsedData, api.ValidationResultFalse, nil
 	}
 
-	if len(functionContext.ApprovedBy) >= numApprovers {
+	approverRole := api.ApproverRoleAny
+	if len(args) > 1 && args[1].Value.(string) != "" {
+		approverRole = args[1].Value.(string)
+	}
+	approvals := 0
+	for _, approver := range functionContext.ApprovedBy {
+		if approverRole == api.ApproverRoleAny || approver.Role == approverRole {
+			approvals++
+		}
+	}
+
+	if approvals >= numApprovers {
 		return parsedData, api.ValidationResultTrue, nil
 	}
-	return parsedData, api.ValidationResultFalse, nil
+	// Unapproved data fails. If one more approval would suffice, the detail is only a warning.
+	result := api.ValidationResultFalse
+	detailSeverity := api.ValidationSeverityFail
+	if approvals > 0 && approvals == numApprovers-1 {
+		detailSeverity = api.ValidationSeverityWarn
+	}
+	result.Details = []api.ValidationDetail{{
+		Message:  fmt.Sprintf("%d of %d required approvals", approvals, numApprovers),
+		Severity: detailSeverity,
+	}}
+	return parsedData, result, nil
 }
 
+// contextLabelKey is the key of the space label that ensure-context sets in resources when
+// inject-labels is true.
+const contextLabelKey = "env"
+
 func genericFnEnsureContext(resourceProvider yamlkit.ResourceProvider, functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
 	addContext := args[0].Value.(bool)
+	injectLabels := len(args) > 1 && args[1].Value.(bool)
 
 	// Check whether adding context is supported by the resource provider
 	if resourceProvider.ContextPath("UnitSlug") == "" {
@@ -1685,33 +2884,67 @@ func genericFnEnsureContext(resourceProvider yamlkit.ResourceProvider, functionC
 		}
 	}
 
-	for _, doc := range parsedData {
-		if addContext {
-			_, err := doc.SetP(functionContext.UnitSlug, resourceProvider.ContextPath("UnitSlug"))
-			if err != nil {
-				return parsedData, nil, err
-			}
-			_, err = doc.SetP(functionContext.SpaceID.String(), resourceProvider.ContextPath("SpaceID"))
-			if err != nil {
-				return parsedData, nil, err
-			}
-			if addRevisionNum {
-				_, err = doc.SetP(fmt.Sprintf("%d", revisionNum), resourceProvider.ContextPath("RevisionNum"))
-				if err != nil {
-					return parsedData, nil, err
+	// The context paths are registered with the resource types excepted by the resource provider
+	// as TypeExceptions, so resources of those types are left untouched.
+	contextValues := map[api.UnresolvedPath]string{
+		yamlkit.ContextPathForField(resourceProvider, "UnitSlug"): functionContext.UnitSlug,
+		yamlkit.ContextPathForField(resourceProvider, "SpaceID"):  functionContext.SpaceID.String(),
+	}
+	if addRevisionNum {
+		contextValues[yamlkit.ContextPathForField(resourceProvider, "RevisionNum")] = fmt.Sprintf("%d", revisionNum)
+	}
+	contextPaths := yamlkit.GetPathRegistryForAttributeName(resourceProvider, api.AttributeNameContext)
+	if addContext {
+		// Only visit the paths of the context values to set
+		for _, pathInfos := range contextPaths {
+			for path := range pathInfos {
+				if _, set := contextValues[path]; !set {
+					delete(pathInfos, path)
 				}
 			}
-		} else {
-			err := doc.DeleteP(resourceProvider.ContextPath("UnitSlug"))
+		}
+	}
+	visitor := func(doc *gaby.YamlDoc, output any, context yamlkit.VisitorContext, currentDoc *gaby.YamlDoc) (any, error) {
+		if !addContext {
+			// Remove all of the context, including the RevisionNum
+			return output, doc.DeleteP(string(context.Path))
+		}
+		_, err := doc.SetP(contextValues[api.UnresolvedPath(context.Path)], string(context.Path))
+		return output, err
+	}
+	_, err := yamlkit.VisitPathsDoc(parsedData, contextPaths, []any{}, nil, resourceProvider, visitor, addContext)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	exceptedResourceTypes := map[api.ResourceType]bool{}
+	for _, pathInfo := range contextPaths[api.ResourceTypeAny] {
+		for resourceType := range pathInfo.TypeExceptions {
+			exceptedResourceTypes[resourceType] = true
+		}
+	}
+	isException := func(doc *gaby.YamlDoc) (bool, error) {
+		if len(exceptedResourceTypes) == 0 {
+			return false, nil
+		}
+		resourceType, err := resourceProvider.ResourceTypeGetter(doc)
+		if err != nil {
+			return false, err
+		}
+		return exceptedResourceTypes[resourceType], nil
+	}
+
+	labelValue, hasLabel := functionContext.Labels[contextLabelKey]
+	labelPath := yamlkit.LabelPath(resourceProvider, contextLabelKey)
+	if addContext && injectLabels && hasLabel && labelPath != "" {
+		for _, doc := range parsedData {
+			exception, err := isException(doc)
 			if err != nil {
 				return parsedData, nil, err
 			}
-			err = doc.DeleteP(resourceProvider.ContextPath("SpaceID"))
-			if err != nil {
-				return parsedData, nil, err
+			if exception {
+				continue
 			}
-			// Delete the RevisionNum regardless
-			err = doc.DeleteP(resourceProvider.ContextPath("RevisionNum"))
+			_, err = doc.SetP(labelValue, labelPath)
 			if err != nil {
 				return parsedData, nil, err
 			}
@@ -1723,7 +2956,14 @@ func genericFnEnsureContext(resourceProvider yamlkit.ResourceProvider, functionC
 		if newHash != functionContext.PreviousContentHash {
 			revisionNum++
 			for _, doc := range parsedData {
-				_, err := doc.SetP(fmt.Sprintf("%d", revisionNum), resourceProvider.ContextPath("RevisionNum"))
+				exception, err := isException(doc)
+				if err != nil {
+					return parsedData, nil, err
+				}
+				if exception {
+					continue
+				}
+				_, err = doc.SetP(fmt.Sprintf("%d", revisionNum), resourceProvider.ContextPath("RevisionNum"))
 				if err != nil {
 					return parsedData, nil, err
 				}
@@ -1733,6 +2973,34 @@ func genericFnEnsureContext(resourceProvider yamlkit.ResourceProvider, functionC
 	return parsedData, nil, nil
 }
 
+// genericFnRebindContext sets the context values that are present in the resources to the
+// values of the function context. Unlike ensure-context, it doesn't add missing context.
+func genericFnRebindContext(resourceProvider yamlkit.ResourceProvider, functionContext *api.FunctionContext, parsedData gaby.Container, _ []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	// Check whether context is supported by the resource provider
+	if resourceProvider.ContextPath("UnitSlug") == "" {
+		return parsedData, nil, nil
+	}
+	contextValues := []struct {
+		contextField string
+		value        string
+	}{
+		{"UnitSlug", functionContext.UnitSlug},
+		{"SpaceID", functionContext.SpaceID.String()},
+	}
+	for _, doc := range parsedData {
+		for _, contextValue := range contextValues {
+			contextPath := resourceProvider.ContextPath(contextValue.contextField)
+			if contextPath == "" || !doc.ExistsP(contextPath) {
+				continue
+			}
+			if _, err := doc.SetP(contextValue.value, contextPath); err != nil {
+				return parsedData, nil, err
+			}
+		}
+	}
+	return parsedData, nil, nil
+}
+
 // genericFnGetDetails.
 func genericFnGetDetails(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, _ []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
 	detailPaths := yamlkit.GetPathRegistryForAttributeName(resourceProvider, api.AttributeNameDetail)
@@ -1752,24 +3020,22 @@ func genericFnReplicate(resourceProvider yamlkit.ResourceProvider, functionConte
 		matchResourceCategory = resourceProvider.DefaultResourceCategory()
 	}
 
-	for i, doc := range parsedData {
+	resourcesByType, err := yamlkit.GroupIndicesByResourceType(parsedData, resourceProvider)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	for _, i := range resourcesByType[matchResourceType] {
+		doc := parsedData[i]
 		resourceCategory, err := resourceProvider.ResourceCategoryGetter(doc)
 		if err != nil {
 			return parsedData, nil, err
 		}
-		resourceType, err := resourceProvider.ResourceTypeGetter(doc)
-		if err != nil {
-			return parsedData, nil, err
-		}
 		resourceName, err := resourceProvider.ResourceNameGetter(doc)
 		if err != nil {
 			return parsedData, nil, err
 		}
 		resourceName = resourceProvider.RemoveScopeFromResourceName(resourceName)
-		// fmt.Printf("%s %s %s\n", string(resourceCategory), string(resourceType), string(resourceName))
-		if resourceCategory != matchResourceCategory ||
-			resourceType != matchResourceType ||
-			resourceName != matchResourceName {
+		if resourceCategory != matchResourceCategory || resourceName != matchResourceName {
 			continue
 		}
 		// Replicate this resource by insertion
@@ -1861,20 +3127,20 @@ func genericFnDeleteResource(resourceProvider yamlkit.ResourceProvider, function
 	targetResourceType := api.ResourceType(args[0].Value.(string))
 	targetResourceName := api.ResourceName(args[1].Value.(string))
 
-	// Use VisitResources to find the existing resource and track its position
-	foundIndex := -1
-	visitor := func(doc *gaby.YamlDoc, output any, index int, resourceInfo *api.ResourceInfo) (any, []error) {
-		if resourceInfo.ResourceType == targetResourceType &&
-			resourceProvider.RemoveScopeFromResourceName(resourceInfo.ResourceName) == resourceProvider.RemoveScopeFromResourceName(targetResourceName) {
-			foundIndex = index
-		}
-		return output, []error{}
-	}
-
-	_, err := yamlkit.VisitResources(parsedData, nil, resourceProvider, visitor)
+	resourcesByType, err := yamlkit.GroupIndicesByResourceType(parsedData, resourceProvider)
 	if err != nil {
 		return parsedData, nil, fmt.Errorf("failed to search for resource to delete: %v", err)
 	}
+	foundIndex := -1
+	for _, i := range resourcesByType[targetResourceType] {
+		resourceName, err := resourceProvider.ResourceNameGetter(parsedData[i])
+		if err != nil {
+			return parsedData, nil, fmt.Errorf("failed to search for resource to delete: %v", err)
+		}
+		if resourceProvider.RemoveScopeFromResourceName(resourceName) == resourceProvider.RemoveScopeFromResourceName(targetResourceName) {
+			foundIndex = i
+		}
+	}
 
 	if foundIndex < 0 {
 		return parsedData, nil, fmt.Errorf("resource with type %s and name %s not found", targetResourceType, targetResourceName)
@@ -1892,6 +3158,60 @@ func genericFnDeleteResource(resourceProvider yamlkit.ResourceProvider, function
 	return newParsedData, nil, nil
 }
 
+func genericFnCopyResource(resourceProvider yamlkit.ResourceProvider, _ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	targetResourceType := api.ResourceType(args[0].Value.(string))
+	sourceName := api.ResourceName(args[1].Value.(string))
+	destinationName := api.ResourceName(args[2].Value.(string))
+	if destinationName == sourceName {
+		return parsedData, nil, fmt.Errorf("destination-name must differ from source-name %s", sourceName)
+	}
+	overwrite := false
+	if len(args) > 3 {
+		overwrite = args[3].Value.(bool)
+	}
+
+	// Use VisitResources to find the source resource and any existing resource with the destination name
+	sourceIndex := -1
+	destinationIndex := -1
+	visitor := func(doc *gaby.YamlDoc, output any, index int, resourceInfo *api.ResourceInfo) (any, []error) {
+		if resourceInfo.ResourceType != targetResourceType {
+			return output, []error{}
+		}
+		switch resourceInfo.ResourceNameWithoutScope {
+		case sourceName:
+			sourceIndex = index
+		case destinationName:
+			destinationIndex = index
+		}
+		return output, []error{}
+	}
+	_, err := yamlkit.VisitResources(parsedData, nil, resourceProvider, visitor)
+	if err != nil {
+		return parsedData, nil, fmt.Errorf("failed to search for resource to copy: %v", err)
+	}
+	if sourceIndex < 0 {
+		return parsedData, nil, fmt.Errorf("resource with type %s and name %s not found", targetResourceType, sourceName)
+	}
+	if destinationIndex >= 0 && !overwrite {
+		return parsedData, nil, fmt.Errorf("resource with type %s and name %s already exists", targetResourceType, destinationName)
+	}
+
+	copiedResource, err := gaby.ParseYAML(parsedData[sourceIndex].Bytes())
+	if err != nil {
+		return parsedData, nil, err
+	}
+	err = resourceProvider.SetResourceName(copiedResource, string(destinationName))
+	if err != nil {
+		return parsedData, nil, err
+	}
+	if destinationIndex >= 0 {
+		parsedData[destinationIndex] = copiedResource
+	} else {
+		parsedData = append(parsedData, copiedResource)
+	}
+	return parsedData, nil, nil
+}
+
 // Generalized path setter and getter functions moved from kubernetes/container_functions.go
 
 func RegisterPathSetterAndGetter(
@@ -0,0 +1,125 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const whereValidateFixture = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+  labels:
+    environment: prod
+spec:
+  replicas: 3
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+  labels:
+    environment: prod
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: sandbox
+  labels:
+    environment: dev
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  labels:
+    environment: prod
+`
+
+func runWhereValidate(t *testing.T, yamlData, selector, validator string) (api.ValidationResult, error) {
+	parsedData, err := gaby.ParseAll([]byte(yamlData))
+	assert.NoError(t, err)
+	args := []api.FunctionArgument{
+		{ParameterName: "resource-type", Value: "apps/v1/Deployment"},
+		{ParameterName: "selector-expression", Value: selector},
+		{ParameterName: "validator-expression", Value: validator},
+	}
+	_, output, err := genericFnResourceWhereValidate(k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, args, nil)
+	result, ok := output.(api.ValidationResult)
+	assert.True(t, ok)
+	return result, err
+}
+
+func TestWhereValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		selector  string
+		validator string
+		passed    bool
+		details   []string
+	}{
+		{
+			name:      "prod with too few replicas",
+			selector:  "metadata.labels.environment = 'prod'",
+			validator: "spec.replicas >= 2",
+			passed:    false,
+			details:   []string{"resource /backend was selected but didn't match spec.replicas >= 2"},
+		},
+		{
+			name:      "non-prod isn't constrained",
+			selector:  "metadata.labels.environment = 'dev'",
+			validator: "spec.replicas >= 1",
+			passed:    true,
+		},
+		{
+			name:      "no resources selected",
+			selector:  "metadata.labels.environment = 'staging'",
+			validator: "spec.replicas >= 100",
+			passed:    true,
+		},
+		{
+			name:      "blank selector selects all resources of the type",
+			selector:  "",
+			validator: "spec.replicas >= 2",
+			passed:    false,
+			details: []string{
+				"resource /backend was selected but didn't match spec.replicas >= 2",
+				"resource /sandbox was selected but didn't match spec.replicas >= 2",
+			},
+		},
+		{
+			name:      "all selected resources valid",
+			selector:  "metadata.labels.environment = 'prod' AND metadata.name != 'backend'",
+			validator: "spec.replicas >= 2",
+			passed:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runWhereValidate(t, whereValidateFixture, tt.selector, tt.validator)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.passed, result.Passed)
+			if !tt.passed {
+				assert.Equal(t, tt.details, result.DetailMessages())
+			}
+		})
+	}
+}
+
+func TestWhereValidate_InvalidExpression(t *testing.T) {
+	result, err := runWhereValidate(t, whereValidateFixture, "metadata.labels.environment = 'prod'", "spec.replicas >>> 2")
+	assert.Error(t, err)
+	assert.False(t, result.Passed)
+}
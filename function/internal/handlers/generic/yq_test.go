@@ -0,0 +1,105 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const yqFixture = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  # Scaled by hand
+  replicas: 1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+data:
+  LOG_LEVEL: info
+`
+
+func runYQ(t *testing.T, expression string, mutating ...bool) (gaby.Container, gaby.Container, any, error) {
+	parsedData, err := gaby.ParseAll([]byte(yqFixture))
+	require.NoError(t, err)
+	args := []api.FunctionArgument{{ParameterName: "yq-expression", Value: expression}}
+	for _, m := range mutating {
+		args = append(args, api.FunctionArgument{ParameterName: "mutating", Value: m})
+	}
+	newParsedData, output, err := genericFnYQ(k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, args, nil)
+	return parsedData, newParsedData, output, err
+}
+
+func TestYQQuery(t *testing.T) {
+	parsedData, newParsedData, output, err := runYQ(t, `select(.kind == "Deployment") | .spec.replicas`)
+	require.NoError(t, err)
+	assert.Equal(t, yqFixture, newParsedData.String())
+	assert.Equal(t, parsedData, newParsedData)
+	assert.Equal(t, "1\n", output.(api.YAMLPayload).Payload)
+
+	// Without mutating, assignments don't change the configuration data
+	_, newParsedData, output, err = runYQ(t, `(select(.kind == "Deployment") | .spec.replicas) = 3`, false)
+	require.NoError(t, err)
+	assert.Equal(t, yqFixture, newParsedData.String())
+	assert.Contains(t, output.(api.YAMLPayload).Payload, "replicas: 3")
+}
+
+func TestYQMutating(t *testing.T) {
+	_, newParsedData, _, err := runYQ(t, `(select(.kind == "Deployment") | .spec.replicas) = 3`, true)
+	require.NoError(t, err)
+	require.Len(t, newParsedData, 2)
+	assert.Equal(t, 3, newParsedData[0].Path("spec.replicas").Data())
+	assert.Contains(t, newParsedData[0].String(), "# Scaled by hand")
+	assert.Equal(t, "info", newParsedData[1].Path("data.LOG_LEVEL").Data())
+
+	_, newParsedData, _, err = runYQ(t, `(select(.kind == "ConfigMap") | .data.LOG_LEVEL) |= "debug"`, true)
+	require.NoError(t, err)
+	assert.Equal(t, "debug", newParsedData[1].Path("data.LOG_LEVEL").Data())
+
+	_, newParsedData, _, err = runYQ(t, `del(.metadata.name)`, true)
+	require.NoError(t, err)
+	assert.False(t, newParsedData[0].ExistsP("metadata.name"))
+
+	// The expression is applied to each document
+	_, newParsedData, output, err := runYQ(t, `.metadata.labels.app = "web"`, true)
+	require.NoError(t, err)
+	require.Len(t, newParsedData, 2)
+	assert.Equal(t, "web", newParsedData[0].Path("metadata.labels.app").Data())
+	assert.Equal(t, "web", newParsedData[1].Path("metadata.labels.app").Data())
+	assert.Contains(t, output.(api.YAMLPayload).Payload, "---\n")
+
+	// Filtering the documents would drop the other documents
+	_, _, _, err = runYQ(t, `select(.kind == "Deployment") | .spec.replicas = 3`, true)
+	assert.ErrorContains(t, err, "must produce exactly one document for each document, but produced 0 for document 1")
+
+	// Queries would replace the configuration data with their results
+	_, _, _, err = runYQ(t, `select(.kind == "Deployment" and .metadata.name != "x=y") | .spec`, true)
+	assert.ErrorContains(t, err, "must contain an assignment")
+}
+
+func TestYQPerDocument(t *testing.T) {
+	parsedData, err := gaby.ParseAll([]byte(yqFixture))
+	require.NoError(t, err)
+	args := []api.FunctionArgument{
+		{ParameterName: "yq-expression", Value: "[.] | length"},
+		{ParameterName: "per-document", Value: true},
+	}
+	_, output, err := genericFnYQ(k8skit.K8sResourceProvider, &api.FunctionContext{}, parsedData, args, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "1\n1\n", output.(api.YAMLPayload).Payload)
+
+	_, _, output, err = runYQ(t, "[.] | length")
+	require.NoError(t, err)
+	assert.Equal(t, "2\n", output.(api.YAMLPayload).Payload)
+}
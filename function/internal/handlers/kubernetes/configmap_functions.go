@@ -0,0 +1,176 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/function/handler"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const (
+	mergeConflictLastWins = "last-wins"
+	mergeConflictError    = "error"
+)
+
+var configMapResourceType = api.ResourceType("v1/ConfigMap")
+var secretResourceType = api.ResourceType("v1/Secret")
+
+// mergedDataFields are the fields containing the key/value pairs of the resource types that can be merged
+var mergedDataFields = map[api.ResourceType][]string{
+	configMapResourceType: {"data", "binaryData"},
+	secretResourceType:    {"data", "stringData"},
+}
+
+func registerConfigMapFunctions(fh handler.FunctionRegistry) {
+	// The namespace is a DNS label and the name is a DNS subdomain
+	resourceNameRegexp := "^([a-z0-9](?:[-a-z0-9]{0,61}[a-z0-9])?/)?" + dnsDomainRegexpString + "$"
+	fh.RegisterFunction("merge-configmaps", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "merge-configmaps",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName:    "target-name",
+					Required:         true,
+					Description:      "Name of the ConfigMap or Secret to merge the keys into, optionally prefixed by its namespace and '/'",
+					DataType:         api.DataTypeString,
+					Example:          "app-config",
+					ValueConstraints: api.ValueConstraints{Regexp: resourceNameRegexp},
+				},
+				{
+					ParameterName:    "conflict",
+					Required:         true,
+					Description:      "How to handle a key present with different values: \"last-wins\" uses the value from the last source, \"error\" fails",
+					DataType:         api.DataTypeEnum,
+					Example:          mergeConflictError,
+					ValueConstraints: api.ValueConstraints{EnumValues: []string{mergeConflictLastWins, mergeConflictError}},
+				},
+				{
+					ParameterName: "delete-sources",
+					Required:      true,
+					Description:   "Delete the source resources after merging them",
+					DataType:      api.DataTypeBool,
+					Example:       "true",
+				},
+				{
+					ParameterName:    "source-name",
+					Required:         true,
+					Description:      "Name of a ConfigMap or Secret to merge into the target, optionally prefixed by its namespace and '/'",
+					DataType:         api.DataTypeString,
+					Example:          "app-config-overrides",
+					ValueConstraints: api.ValueConstraints{Regexp: resourceNameRegexp},
+				},
+			},
+			VarArgs:               true,
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            false,
+			Description:           "Merge the data keys of ConfigMaps or Secrets into a target ConfigMap or Secret of the same type, in the order the sources are specified",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{configMapResourceType, secretResourceType},
+		},
+		Function: k8sFnMergeConfigMaps,
+	})
+}
+
+// findConfigMapOrSecret returns the index of the ConfigMap or Secret with the specified name. The
+// name matches the namespace/name of the resource if it contains a '/' and the name otherwise.
+func findConfigMapOrSecret(parsedData gaby.Container, name string) (int, api.ResourceType, error) {
+	for i, doc := range parsedData {
+		resourceType, err := k8skit.K8sResourceProvider.ResourceTypeGetter(doc)
+		if err != nil {
+			continue // Skip malformed resources
+		}
+		if _, ok := mergedDataFields[resourceType]; !ok {
+			continue
+		}
+		resourceName, err := k8skit.K8sResourceProvider.ResourceNameGetter(doc)
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(name, "/") {
+			_, unscopedName, _ := strings.Cut(string(resourceName), "/")
+			resourceName = api.ResourceName(unscopedName)
+		}
+		if string(resourceName) == name {
+			return i, resourceType, nil
+		}
+	}
+	return -1, "", fmt.Errorf("no ConfigMap or Secret named %s found", name)
+}
+
+func k8sFnMergeConfigMaps(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	targetName := args[0].Value.(string)
+	conflict := args[1].Value.(string)
+	deleteSources := args[2].Value.(bool)
+	sourceNames := []string{}
+	for _, arg := range args[3:] {
+		sourceNames = append(sourceNames, arg.Value.(string))
+	}
+
+	targetIndex, targetType, err := findConfigMapOrSecret(parsedData, targetName)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	target := parsedData[targetIndex]
+	// Remember which source each key came from for error messages. Keys already in the target
+	// came from the target.
+	keySources := map[string]string{}
+	for _, field := range mergedDataFields[targetType] {
+		for key := range target.S(field).ChildrenMap() {
+			keySources[field+"."+key] = targetName
+		}
+	}
+
+	sources := map[*gaby.YamlDoc]struct{}{}
+	for _, sourceName := range sourceNames {
+		sourceIndex, sourceType, err := findConfigMapOrSecret(parsedData, sourceName)
+		if err != nil {
+			return parsedData, nil, err
+		}
+		if sourceIndex == targetIndex {
+			return parsedData, nil, fmt.Errorf("source %s is the target", sourceName)
+		}
+		if sourceType != targetType {
+			return parsedData, nil, fmt.Errorf("source %s is a %s and target %s is a %s", sourceName, sourceType, targetName, targetType)
+		}
+		source := parsedData[sourceIndex]
+		for _, field := range mergedDataFields[targetType] {
+			sourceValues := source.S(field).ChildrenMap()
+			keys := make([]string, 0, len(sourceValues))
+			for key := range sourceValues {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+			for _, key := range keys {
+				value := sourceValues[key].Data()
+				if conflict == mergeConflictError && target.Exists(field, key) {
+					existingValue := target.S(field, key).Data()
+					if fmt.Sprint(existingValue) != fmt.Sprint(value) {
+						return parsedData, nil, fmt.Errorf("key %s in %s of %s conflicts with the value from %s", key, field, sourceName, keySources[field+"."+key])
+					}
+				}
+				if _, err := target.Set(value, field, key); err != nil {
+					return parsedData, nil, err
+				}
+				keySources[field+"."+key] = sourceName
+			}
+		}
+		sources[source] = struct{}{}
+	}
+
+	if deleteSources {
+		parsedData = slices.DeleteFunc(parsedData, func(doc *gaby.YamlDoc) bool {
+			_, isSource := sources[doc]
+			return isSource
+		})
+	}
+	return parsedData, nil, nil
+}
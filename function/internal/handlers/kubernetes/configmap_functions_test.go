@@ -0,0 +1,89 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const mergeConfigMapsFixture = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: prod
+data:
+  LOG_LEVEL: info
+  PORT: "8080"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config-overrides
+  namespace: prod
+data:
+  LOG_LEVEL: debug
+  FEATURE_X: "true"
+binaryData:
+  cert: AQID
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-secret
+  namespace: prod
+data:
+  PORT: OTA5MA==
+`
+
+func runMergeConfigMaps(t *testing.T, conflict string, deleteSources bool, names ...string) (gaby.Container, error) {
+	docs, err := gaby.ParseAll([]byte(mergeConfigMapsFixture))
+	require.NoError(t, err)
+	args := []api.FunctionArgument{
+		{ParameterName: "target-name", Value: names[0]},
+		{ParameterName: "conflict", Value: conflict},
+		{ParameterName: "delete-sources", Value: deleteSources},
+	}
+	for _, name := range names[1:] {
+		args = append(args, api.FunctionArgument{ParameterName: "source-name", Value: name})
+	}
+	registration := testHandler.ListCore()["merge-configmaps"]
+	result, _, err := registration.Function(&fakeContext, docs, args, []byte{})
+	return result, err
+}
+
+func TestMergeConfigMaps_LastWins(t *testing.T) {
+	result, err := runMergeConfigMaps(t, mergeConflictLastWins, true, "app-config", "prod/app-config-overrides")
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	target := result[0]
+	assert.Equal(t, "debug", target.Path("data.LOG_LEVEL").Data())
+	assert.Equal(t, "8080", target.Path("data.PORT").Data())
+	assert.Equal(t, "true", target.Path("data.FEATURE_X").Data())
+	assert.Equal(t, "AQID", target.Path("binaryData.cert").Data())
+	assert.Equal(t, "app-secret", result[1].Path("metadata.name").Data())
+}
+
+func TestMergeConfigMaps_ErrorOnConflict(t *testing.T) {
+	_, err := runMergeConfigMaps(t, mergeConflictError, false, "app-config", "app-config-overrides")
+	assert.EqualError(t, err, "key LOG_LEVEL in data of app-config-overrides conflicts with the value from app-config")
+
+	// The error names where the existing value came from
+	_, err = runMergeConfigMaps(t, mergeConflictError, false, "app-config-overrides", "app-config")
+	assert.EqualError(t, err, "key LOG_LEVEL in data of app-config conflicts with the value from app-config-overrides")
+}
+
+func TestMergeConfigMaps_InvalidSources(t *testing.T) {
+	_, err := runMergeConfigMaps(t, mergeConflictLastWins, false, "app-config", "app-secret")
+	assert.EqualError(t, err, "source app-secret is a v1/Secret and target app-config is a v1/ConfigMap")
+	_, err = runMergeConfigMaps(t, mergeConflictLastWins, false, "app-config", "missing")
+	assert.EqualError(t, err, "no ConfigMap or Secret named missing found")
+	_, err = runMergeConfigMaps(t, mergeConflictLastWins, false, "app-config", "dev/app-config-overrides")
+	assert.EqualError(t, err, "no ConfigMap or Secret named dev/app-config-overrides found")
+}
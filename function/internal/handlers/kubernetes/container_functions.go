@@ -4,8 +4,11 @@
 package kubernetes
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/errors"
@@ -19,6 +22,7 @@ import (
 	"github.com/confighub/sdk/third_party/gaby"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 	quantity "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/version"
 )
 
 var setImageHandler, setImageUriHandler, setImageReferenceHandler, setImageReferenceByUriHandler handler.FunctionImplementation
@@ -147,6 +151,194 @@ func registerContainerFunctions(fh handler.FunctionRegistry) {
 		Function: k8sFnSetImageReferenceByURI,
 	})
 	setImageReferenceByUriHandler = fh.GetHandlerImplementation("set-image-reference-by-uri") // for testing
+	fh.RegisterFunction("pin-images", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "pin-images",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "image-digests",
+					Required:      true,
+					Description:   "JSON object mapping image repository URIs to the digests to pin them to",
+					DataType:      api.DataTypeString,
+					Example:       `{"ghcr.io/acme/api": "sha256:0f5c2d2e2d4f3c4b1a8e5a9e1d7c3b2a6f4e8d9c0b1a2f3e4d5c6b7a8f9e0d1c"}`,
+				},
+			},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "pinned-images",
+				Description: "Images that were pinned and repositories that didn't match any image",
+				OutputType:  api.OutputTypeCustomJSON,
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Set the references of container images with the specified repository URIs to the corresponding digests",
+			FunctionType:          api.FunctionTypeCustom,
+			AttributeName:         api.AttributeNameContainerImages,
+			AffectedResourceTypes: resourceTypes,
+		},
+		Function: k8sFnPinImages,
+	})
+	fh.RegisterFunction("get-images", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "get-images",
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "container-image",
+				Description: "Images of all containers, including init and ephemeral containers",
+				OutputType:  api.OutputTypeAttributeValueList,
+			},
+			Mutating:              false,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Get the images of all containers in all resources, sorted by resource and path",
+			FunctionType:          api.FunctionTypeCustom,
+			AttributeName:         api.AttributeNameContainerImages,
+			AffectedResourceTypes: resourceTypes,
+		},
+		Function: k8sFnGetImages,
+	})
+	fh.RegisterFunction("get-image-digest", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "get-image-digest",
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "image-digest",
+				Description: "Digests of all containers whose images are pinned to digests",
+				OutputType:  api.OutputTypeAttributeValueList,
+			},
+			Mutating:              false,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Get the digests (without the @ separator) of all container images pinned to digests, sorted by resource and path",
+			FunctionType:          api.FunctionTypeCustom,
+			AttributeName:         api.AttributeNameContainerImages,
+			AffectedResourceTypes: resourceTypes,
+		},
+		Function: k8sFnGetImageDigest,
+	})
+	fh.RegisterFunction("set-image-digest", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "set-image-digest",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName:    "container-name",
+					Required:         true,
+					Description:      "Name of the container whose image to pin",
+					DataType:         api.DataTypeString,
+					Example:          "cert-manager-controller",
+					ValueConstraints: api.ValueConstraints{Regexp: convertToFullRegexp(containerNameRegexpString)},
+				},
+				{
+					ParameterName:    "digest",
+					Required:         true,
+					Description:      "SHA256 digest of the image (without the @ separator)",
+					DataType:         api.DataTypeString,
+					Example:          "sha256:0f5c2d2e2d4f3c4b1a8e5a9e1d7c3b2a6f4e8d9c0b1a2f3e4d5c6b7a8f9e0d1c",
+					ValueConstraints: api.ValueConstraints{Regexp: imageSHA256DigestRegexpString},
+				},
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Set the reference of the image of a container to a digest, replacing any tag",
+			FunctionType:          api.FunctionTypeCustom,
+			AttributeName:         api.AttributeNameContainerImages,
+			AffectedResourceTypes: resourceTypes,
+		},
+		Function: k8sFnSetImageDigest,
+	})
+	fh.RegisterFunction("validate-image-pinned", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "validate-image-pinned",
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "passed",
+				Description: "True if all container images are pinned to digests, false otherwise",
+				OutputType:  api.OutputTypeValidationResult,
+			},
+			Mutating:              false,
+			Validating:            true,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Returns true if the images of all containers, including init and ephemeral containers, reference digests rather than tags",
+			FunctionType:          api.FunctionTypeCustom,
+			AttributeName:         api.AttributeNameContainerImages,
+			AffectedResourceTypes: resourceTypes,
+		},
+		Function: k8sFnValidateImagePinned,
+	})
+	fh.RegisterFunction("validate-image-registries", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "validate-image-registries",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "allowed-registries",
+					Required:      true,
+					Description:   "Comma-separated registry hosts, optionally followed by repository prefixes, from which images may be pulled; a leading *. matches any subdomain, and images without a registry host are from docker.io",
+					DataType:      api.DataTypeString,
+					Example:       "ghcr.io/acme,*.internal.example.com",
+				},
+			},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "passed",
+				Description: "True if all container images are from allowed registries, false otherwise",
+				OutputType:  api.OutputTypeValidationResult,
+			},
+			Mutating:              false,
+			Validating:            true,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Returns true if the images of all containers, including init and ephemeral containers, are from the allowed registries",
+			FunctionType:          api.FunctionTypeCustom,
+			AttributeName:         api.AttributeNameContainerImages,
+			AffectedResourceTypes: resourceTypes,
+		},
+		Function: k8sFnValidateImageRegistries,
+	})
+	fh.RegisterFunction("is-image-updated", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "is-image-updated",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName:    "container-name",
+					Required:         true,
+					Description:      "Name of the container whose image to check",
+					DataType:         api.DataTypeString,
+					Example:          "main",
+					ValueConstraints: api.ValueConstraints{Regexp: convertToFullRegexp(containerNameRegexpString)},
+				},
+				{
+					ParameterName: "registry-host",
+					Required:      false,
+					Description:   "Registry host to query, optionally preceded by https://; defaults to the registry of the image",
+					DataType:      api.DataTypeString,
+					Example:       "mirror.example.com",
+				},
+				{
+					ParameterName: "expected-digest",
+					Required:      false,
+					Description:   "Expected digest of the image; defaults to the digest of the latest tag of the repository in the registry",
+					DataType:      api.DataTypeString,
+					Example:       "sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b",
+				},
+			},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "passed",
+				Description: "True if the images of the container are at the expected digest, false otherwise",
+				OutputType:  api.OutputTypeValidationResult,
+			},
+			Mutating:              false,
+			Validating:            true,
+			Hermetic:              false,
+			Idempotent:            false,
+			Description:           "Returns true if the image of the container is at the expected digest. Images referenced by tag are resolved to digests by calling the container registry",
+			FunctionType:          api.FunctionTypeCustom,
+			AttributeName:         api.AttributeNameContainerImages,
+			AffectedResourceTypes: resourceTypes,
+		},
+		ContextFunction: k8sFnIsImageUpdated,
+	})
 	minValue := 0
 	replicasParameters := []api.FunctionParameter{
 		{
@@ -193,6 +385,45 @@ func registerContainerFunctions(fh handler.FunctionRegistry) {
 		},
 		Function: k8sFnSetEnv,
 	})
+	fh.RegisterFunction("set-env-from", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "set-env-from",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName:    "container-name",
+					Required:         true,
+					Description:      "Name of the container whose envFrom sources to update",
+					DataType:         api.DataTypeString,
+					Example:          "main",
+					ValueConstraints: api.ValueConstraints{Regexp: convertToFullRegexp(containerNameRegexpString)},
+				},
+				{
+					ParameterName:    "source-type",
+					Required:         true,
+					Description:      "Type of the source: \"configMap\" for a configMapRef or \"secret\" for a secretRef",
+					DataType:         api.DataTypeEnum,
+					Example:          envFromSourceTypeConfigMap,
+					ValueConstraints: api.ValueConstraints{EnumValues: []string{envFromSourceTypeConfigMap, envFromSourceTypeSecret}},
+				},
+				{
+					ParameterName:    "name",
+					Required:         true,
+					Description:      "Name of the ConfigMap or Secret to add if not already present; empty implies removal of all sources of the type",
+					DataType:         api.DataTypeString,
+					Example:          "app-config",
+					ValueConstraints: api.ValueConstraints{Regexp: "^(" + dnsSubdomainRegexpString + ")?$"},
+				},
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Add a configMapRef or secretRef to the envFrom sources of a container, or remove the sources of the type",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: resourceTypes,
+		},
+		Function: k8sFnSetEnvFrom,
+	})
 	envVarParameters := []api.FunctionParameter{
 		{
 			ParameterName:    "container-name",
@@ -274,6 +505,34 @@ func registerContainerFunctions(fh handler.FunctionRegistry) {
 		},
 		Function: k8sFnSetContainerResources,
 	})
+	resourceTypes = yamlkit.ResourceTypesForPathMap(resourceTypeToContainersPaths)
+	fh.RegisterFunction("require-resource-requests", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "require-resource-requests",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "exempt-containers",
+					Required:      false,
+					Description:   "Comma-separated names of containers, such as sidecars, that aren't required to set requests",
+					DataType:      api.DataTypeString,
+					Example:       "istio-proxy,log-forwarder",
+				},
+			},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "passed",
+				Description: "True if all containers set cpu and memory requests, false otherwise",
+				OutputType:  api.OutputTypeValidationResult,
+			},
+			Mutating:              false,
+			Validating:            true,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Returns true if all containers, including init and ephemeral containers, set both cpu and memory resource requests",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: resourceTypes,
+		},
+		Function: k8sFnRequireResourceRequests,
+	})
 	resourceTypes = yamlkit.ResourceTypesForPathMap(resourceTypeToPodSpecPaths)
 	fh.RegisterFunction("set-pod-defaults", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
@@ -440,8 +699,9 @@ const (
 )
 
 var (
-	imageURIRegexpString       = fmt.Sprintf("(?P<uri>(?:(?:%s)/)?(?:%s))", imageRegistryHostRegexpString, imageRepositoryRegexpString)
-	imageReferenceRegexpString = fmt.Sprintf("(?P<reference>\\:(?:%s)|@(?:%s))", imageTagReferenceRegexpString, imageDigestReferenceRegexpString)
+	imageURIRegexpString = fmt.Sprintf("(?P<uri>(?:(?:%s)/)?(?:%s))", imageRegistryHostRegexpString, imageRepositoryRegexpString)
+	// The reference is a tag, a digest, or a tag followed by a digest, in which case the digest takes precedence
+	imageReferenceRegexpString = fmt.Sprintf("(?P<reference>(?:\\:(?:%s))?@(?:%s)|\\:(?:%s))", imageTagReferenceRegexpString, imageDigestReferenceRegexpString, imageTagReferenceRegexpString)
 	// This expression partitions the image into two pieces, URI and reference
 	imageURIReferenceRegexpString = fmt.Sprintf("^%s%s?$", imageURIRegexpString, imageReferenceRegexpString)
 )
@@ -484,7 +744,7 @@ const (
 func initContainerFunctions() {
 	// This regular expression breaks down an image into its components, but is more
 	// complicated to use for confighubplaceholder. It's not currently used, but is here in case we need it.
-	imageRegexpString := fmt.Sprintf("^(?:(?P<registry>%s)/)?(?P<repository>%s)(?:\\:(?P<tag>%s)|@(?P<digest>%s))?$",
+	imageRegexpString := fmt.Sprintf("^(?:(?P<registry>%s)/)?(?P<repository>%s)(?:\\:(?P<tag>%s))?(?:@(?P<digest>%s))?$",
 		imageRegistryHostRegexpString, imageRepositoryRegexpString, imageTagReferenceRegexpString, imageDigestReferenceRegexpString)
 	imageRegexp = regexp.MustCompile(imageRegexpString)
 	segmentNames := imageRegexp.SubexpNames()
@@ -515,7 +775,7 @@ func initContainerFunctions() {
 				AttributeName: api.AttributeNameContainerName,
 				DataType:      api.DataTypeString,
 			}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameContainerName,
 				resourceType,
@@ -524,7 +784,7 @@ func initContainerFunctions() {
 				nil, // no setter
 				true,
 			)
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameGeneral,
 				resourceType,
@@ -550,7 +810,7 @@ func initContainerFunctions() {
 				AttributeName: api.AttributeNameContainerImage,
 				DataType:      api.DataTypeString,
 			}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameContainerImage,
 				resourceType,
@@ -567,7 +827,7 @@ func initContainerFunctions() {
 				AttributeName: api.AttributeNameContainerImage,
 				DataType:      api.DataTypeString,
 			}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameGeneral,
 				resourceType,
@@ -576,12 +836,12 @@ func initContainerFunctions() {
 				imageSetterFunctionInvocation,
 				true,
 			)
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameContainerImages,
 				resourceType,
 				api.PathToVisitorInfoType{attributePath: pathInfo},
-				nil,
+				imageGetterFunctionInvocation, // identifies the container for get-images
 				nil,
 				true,
 			)
@@ -604,7 +864,7 @@ func initContainerFunctions() {
 				EmbeddedAccessorType:   api.EmbeddedAccessorRegexp,
 				EmbeddedAccessorConfig: imageURIReferenceRegexpString,
 			}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameContainerRepositoryURI,
 				resourceType,
@@ -624,7 +884,7 @@ func initContainerFunctions() {
 				EmbeddedAccessorConfig: imageURIReferenceRegexpString,
 			}
 			pathInfos := api.PathToVisitorInfoType{attributePath: pathInfo}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameGeneral,
 				resourceType,
@@ -633,9 +893,9 @@ func initContainerFunctions() {
 				repoURISetterFunctionInvocation,
 				true,
 			)
-			yamlkit.RegisterNeededPaths(k8skit.K8sResourceProvider, resourceType, pathInfos, repoURISetterFunctionInvocation)
+			yamlkit.MustRegisterNeededPaths(k8skit.K8sResourceProvider, resourceType, pathInfos, repoURISetterFunctionInvocation)
 			addDescriptionToPathInfos(resourceType, pathInfos)
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameDetail,
 				resourceType,
@@ -663,7 +923,7 @@ func initContainerFunctions() {
 				EmbeddedAccessorType:   api.EmbeddedAccessorRegexp,
 				EmbeddedAccessorConfig: imageURIReferenceRegexpString,
 			}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameContainerImageReference,
 				resourceType,
@@ -683,7 +943,7 @@ func initContainerFunctions() {
 				EmbeddedAccessorConfig: imageURIReferenceRegexpString,
 			}
 			pathInfos = api.PathToVisitorInfoType{attributePath: pathInfo}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameGeneral,
 				resourceType,
@@ -693,7 +953,7 @@ func initContainerFunctions() {
 				true,
 			)
 			addDescriptionToPathInfos(resourceType, pathInfos)
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameDetail,
 				resourceType,
@@ -719,7 +979,7 @@ func initContainerFunctions() {
 				AttributeName: attributeNameEnvValue,
 				DataType:      api.DataTypeString,
 			}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				attributeNameEnvValue,
 				resourceType,
@@ -736,7 +996,7 @@ func initContainerFunctions() {
 				AttributeName: attributeNameContainerResources,
 				DataType:      api.DataTypeYAML,
 			}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				attributeNameContainerResources,
 				resourceType,
@@ -766,7 +1026,7 @@ func initContainerFunctions() {
 				DataType:      api.DataTypeInt,
 			},
 		}
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			k8skit.K8sResourceProvider,
 			attributeNameReplicas,
 			resourceType,
@@ -775,7 +1035,7 @@ func initContainerFunctions() {
 			replicasSetterFunctionInvocation,
 			false,
 		)
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			k8skit.K8sResourceProvider,
 			api.AttributeNameGeneral,
 			resourceType,
@@ -785,7 +1045,7 @@ func initContainerFunctions() {
 			true,
 		)
 		addDescriptionToPathInfos(resourceType, pathInfos)
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			k8skit.K8sResourceProvider,
 			api.AttributeNameDetail,
 			resourceType,
@@ -838,7 +1098,7 @@ func initContainerFunctions() {
 					DataType:      api.DataTypeString,
 				},
 			}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameHostname,
 				resourceType,
@@ -847,7 +1107,7 @@ func initContainerFunctions() {
 				hostnameSetterFunctionInvocation,
 				false,
 			)
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameGeneral,
 				resourceType,
@@ -869,7 +1129,7 @@ func initContainerFunctions() {
 					EmbeddedAccessorConfig: dnsSubdomainDomainRegexpString,
 				},
 			}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameSubdomain,
 				resourceType,
@@ -878,7 +1138,7 @@ func initContainerFunctions() {
 				subdomainSetterFunctionInvocation,
 				false,
 			)
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameGeneral,
 				resourceType,
@@ -897,7 +1157,7 @@ func initContainerFunctions() {
 					EmbeddedAccessorConfig: dnsSubdomainDomainRegexpString,
 				},
 			}
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameDomain,
 				resourceType,
@@ -906,7 +1166,7 @@ func initContainerFunctions() {
 				domainSetterFunctionInvocation,
 				false,
 			)
-			yamlkit.RegisterPathsByAttributeName(
+			yamlkit.MustRegisterPathsByAttributeName(
 				k8skit.K8sResourceProvider,
 				api.AttributeNameGeneral,
 				resourceType,
@@ -915,7 +1175,7 @@ func initContainerFunctions() {
 				domainSetterFunctionInvocation,
 				true,
 			)
-			yamlkit.RegisterNeededPaths(k8skit.K8sResourceProvider, resourceType, pathInfos, domainSetterFunctionInvocation)
+			yamlkit.MustRegisterNeededPaths(k8skit.K8sResourceProvider, resourceType, pathInfos, domainSetterFunctionInvocation)
 		}
 	}
 }
@@ -948,6 +1208,393 @@ func k8sFnSetImageReferenceByURI(_ *api.FunctionContext, parsedData gaby.Contain
 	return parsedData, nil, err
 }
 
+// PinnedImage is a container image whose reference was replaced by a digest by pin-images.
+type PinnedImage struct {
+	ResourceName api.ResourceName
+	ResourceType api.ResourceType
+	Path         api.ResolvedPath
+	Image        string // original image
+	PinnedImage  string
+}
+
+// PinImagesResult is the output of pin-images.
+type PinImagesResult struct {
+	Pinned                []PinnedImage
+	UnmatchedRepositories []string
+}
+
+var imageDigestReferenceRegexp = regexp.MustCompile(convertToFullRegexp(imageDigestReferenceRegexpString))
+
+// imageSHA256DigestRegexpString matches the digests accepted by set-image-digest
+const imageSHA256DigestRegexpString = "^sha256:[0-9a-f]{64}$"
+
+var imageSHA256DigestRegexp = regexp.MustCompile(imageSHA256DigestRegexpString)
+
+func k8sFnPinImages(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	imageDigestsJSON := args[0].Value.(string)
+
+	var imageDigests map[string]string
+	if err := json.Unmarshal([]byte(imageDigestsJSON), &imageDigests); err != nil {
+		return parsedData, nil, errors.Wrap(err, "image-digests must be a JSON object mapping repository URIs to digests")
+	}
+	imageURIRegexp := regexp.MustCompile(convertToFullRegexp(imageURIRegexpString))
+	for imageURI, digest := range imageDigests {
+		if !imageURIRegexp.MatchString(imageURI) {
+			return parsedData, nil, errors.Newf("invalid repository URI %s", imageURI)
+		}
+		digest = strings.TrimPrefix(digest, "@")
+		if !imageDigestReferenceRegexp.MatchString(digest) {
+			return parsedData, nil, errors.Newf("invalid digest %s for repository URI %s", imageDigests[imageURI], imageURI)
+		}
+		imageDigests[imageURI] = digest
+	}
+
+	// Returns the pinned image and true if the image's repository URI is in the map
+	pinnedImage := func(currentValue string) (string, bool) {
+		matches := imageURIReferenceRegexp.FindStringSubmatchIndex(currentValue)
+		if len(matches) != 6 {
+			return currentValue, false
+		}
+		currentURI := currentValue[matches[2]:matches[3]]
+		digest, ok := imageDigests[currentURI]
+		if !ok {
+			return currentValue, false
+		}
+		return currentURI + "@" + digest, true
+	}
+
+	resourceTypeToAllImagePaths := yamlkit.GetPathRegistryForAttributeName(k8skit.K8sResourceProvider, api.AttributeNameContainerImages)
+	images, err := yamlkit.GetStringPaths(parsedData, resourceTypeToAllImagePaths, []any{}, k8skit.K8sResourceProvider)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	result := PinImagesResult{Pinned: []PinnedImage{}, UnmatchedRepositories: []string{}}
+	matchedURIs := map[string]bool{}
+	for _, image := range images {
+		currentValue, ok := image.Value.(string)
+		if !ok {
+			continue
+		}
+		newValue, matched := pinnedImage(currentValue)
+		if !matched {
+			continue
+		}
+		matchedURIs[strings.SplitN(newValue, "@", 2)[0]] = true
+		if newValue != currentValue {
+			result.Pinned = append(result.Pinned, PinnedImage{
+				ResourceName: image.ResourceName,
+				ResourceType: image.ResourceType,
+				Path:         image.Path,
+				Image:        currentValue,
+				PinnedImage:  newValue,
+			})
+		}
+	}
+	for imageURI := range imageDigests {
+		if !matchedURIs[imageURI] {
+			result.UnmatchedRepositories = append(result.UnmatchedRepositories, imageURI)
+		}
+	}
+	sort.Strings(result.UnmatchedRepositories)
+
+	updater := func(currentValue string) string {
+		newValue, _ := pinnedImage(currentValue)
+		return newValue
+	}
+	err = yamlkit.UpdateStringPathsFunction(parsedData, resourceTypeToAllImagePaths, []any{}, k8skit.K8sResourceProvider, updater, false)
+	return parsedData, result, err
+}
+
+func k8sFnGetImages(_ *api.FunctionContext, parsedData gaby.Container, _ []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	resourceTypeToAllImagePaths := yamlkit.GetPathRegistryForAttributeName(k8skit.K8sResourceProvider, api.AttributeNameContainerImages)
+	values, err := yamlkit.GetStringPaths(parsedData, resourceTypeToAllImagePaths, []any{}, k8skit.K8sResourceProvider)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		if values[i].ResourceName != values[j].ResourceName {
+			return values[i].ResourceName < values[j].ResourceName
+		}
+		if values[i].ResourceType != values[j].ResourceType {
+			return compareResourceTypes(values[i].ResourceType, values[j].ResourceType) < 0
+		}
+		return values[i].Path < values[j].Path
+	})
+	return parsedData, values, nil
+}
+
+// compareResourceTypes orders resource types of the form <group>/<version>/<kind> by group,
+// version, and kind. Versions are compared as Kubernetes API versions, so that v9 precedes v10
+// and v1beta1 precedes v1.
+func compareResourceTypes(a, b api.ResourceType) int {
+	groupA, versionA, kindA := splitResourceType(a)
+	groupB, versionB, kindB := splitResourceType(b)
+	if groupA != groupB {
+		return strings.Compare(groupA, groupB)
+	}
+	if versionA != versionB {
+		return version.CompareKubeAwareVersionStrings(versionA, versionB)
+	}
+	return strings.Compare(kindA, kindB)
+}
+
+func splitResourceType(resourceType api.ResourceType) (group, apiVersion, kind string) {
+	segments := strings.Split(string(resourceType), "/")
+	kind = segments[len(segments)-1]
+	if len(segments) > 1 {
+		apiVersion = segments[len(segments)-2]
+		group = strings.Join(segments[:len(segments)-2], "/")
+	}
+	return group, apiVersion, kind
+}
+
+// imageDigest returns the digest of image, without the @ separator, and true if image references
+// a digest, with or without a tag.
+func imageDigest(image string) (string, bool) {
+	matches := imageURIReferenceRegexp.FindStringSubmatch(image)
+	if len(matches) != 3 {
+		return "", false
+	}
+	_, digest, found := strings.Cut(matches[2], "@")
+	return digest, found
+}
+
+func k8sFnGetImageDigest(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+	_, output, err := k8sFnGetImages(functionContext, parsedData, args, liveState)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	digests := api.AttributeValueList{}
+	for _, value := range output.(api.AttributeValueList) {
+		image, ok := value.Value.(string)
+		if !ok {
+			continue
+		}
+		digest, ok := imageDigest(image)
+		if !ok {
+			continue
+		}
+		value.Value = digest
+		digests = append(digests, value)
+	}
+	return parsedData, digests, nil
+}
+
+func k8sFnSetImageDigest(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	containerName := args[0].Value.(string)
+	digest := args[1].Value.(string)
+	if !imageSHA256DigestRegexp.MatchString(digest) {
+		return parsedData, nil, errors.Newf("invalid digest %s; expected sha256: followed by 64 lowercase hexadecimal digits", digest)
+	}
+
+	// Replace the whole image rather than the embedded reference so that images without tags are pinned also
+	resourceTypeToImagePaths := yamlkit.GetPathRegistryForAttributeName(k8skit.K8sResourceProvider, api.AttributeNameContainerImage)
+	updater := func(currentValue string) string {
+		matches := imageURIReferenceRegexp.FindStringSubmatch(currentValue)
+		if len(matches) != 3 {
+			return currentValue
+		}
+		return matches[1] + "@" + digest
+	}
+	err := yamlkit.UpdateStringPathsFunction(parsedData, resourceTypeToImagePaths, []any{containerName}, k8skit.K8sResourceProvider, updater, false)
+	return parsedData, nil, err
+}
+
+func k8sFnValidateImagePinned(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+	_, output, err := k8sFnGetImages(functionContext, parsedData, args, liveState)
+	if err != nil {
+		return parsedData, api.ValidationResultFalse, err
+	}
+	details := []string{}
+	failedAttributes := api.AttributeValueList{}
+	for _, value := range output.(api.AttributeValueList) {
+		image, ok := value.Value.(string)
+		if !ok {
+			continue
+		}
+		if _, pinned := imageDigest(image); pinned {
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s %s: image %s is not pinned to a digest", value.ResourceName, value.Path, image))
+		failedAttributes = append(failedAttributes, value)
+	}
+
+	if len(details) == 0 {
+		return parsedData, api.ValidationResultTrue, nil
+	}
+	failedResult := api.ValidationResultFalse
+	failedResult.Details = api.ValidationDetailsWithSeverity(details, api.ValidationSeverityFail)
+	failedResult.FailedAttributes = failedAttributes
+	return parsedData, failedResult, nil
+}
+
+// defaultImageRegistry is the registry of images whose URIs don't start with a registry host.
+const defaultImageRegistry = "docker.io"
+
+// imageRegistryAndRepository splits an image URI, without a reference, into its registry host and
+// repository. As in Docker, the first segment of the URI is the registry host only if it contains
+// a . or : or is localhost.
+func imageRegistryAndRepository(uri string) (string, string) {
+	host, repository, found := strings.Cut(uri, "/")
+	if !found || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		return defaultImageRegistry, uri
+	}
+	return host, repository
+}
+
+// imageRegistryAllowed returns true if the image URI matches an entry of allowedRegistries. Each
+// entry is a registry host, which may start with *. to match subdomains, optionally followed by a
+// repository prefix that matches whole segments of the repository.
+func imageRegistryAllowed(uri string, allowedRegistries []string) bool {
+	registry, repository := imageRegistryAndRepository(uri)
+	for _, allowed := range allowedRegistries {
+		allowedHost, allowedPrefix, _ := strings.Cut(allowed, "/")
+		if strings.HasPrefix(allowedHost, "*.") {
+			if !strings.HasSuffix(registry, allowedHost[1:]) {
+				continue
+			}
+		} else if registry != allowedHost {
+			continue
+		}
+		if allowedPrefix == "" || repository == allowedPrefix || strings.HasPrefix(repository, strings.TrimSuffix(allowedPrefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// imageValueContainerName returns the name of the container of an image returned by get-images,
+// which identifies the container by the argument of its getter invocation.
+func imageValueContainerName(value api.AttributeValue) string {
+	if value.Info != nil && value.Info.GetterInvocation != nil && len(value.Info.GetterInvocation.Arguments) > 0 {
+		if containerName, ok := value.Info.GetterInvocation.Arguments[0].Value.(string); ok {
+			return containerName
+		}
+	}
+	return ""
+}
+
+func k8sFnValidateImageRegistries(functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	allowedRegistries := []string{}
+	for _, allowed := range strings.Split(args[0].Value.(string), ",") {
+		if allowed = strings.TrimSpace(allowed); allowed != "" {
+			allowedRegistries = append(allowedRegistries, allowed)
+		}
+	}
+
+	_, output, err := k8sFnGetImages(functionContext, parsedData, nil, liveState)
+	if err != nil {
+		return parsedData, api.ValidationResultFalse, err
+	}
+	details := []string{}
+	failedAttributes := api.AttributeValueList{}
+	for _, value := range output.(api.AttributeValueList) {
+		image, ok := value.Value.(string)
+		if !ok {
+			continue
+		}
+		uri := image
+		if matches := imageURIReferenceRegexp.FindStringSubmatch(image); len(matches) == 3 {
+			uri = matches[1]
+		}
+		if imageRegistryAllowed(uri, allowedRegistries) {
+			continue
+		}
+		registry, _ := imageRegistryAndRepository(uri)
+		details = append(details, fmt.Sprintf("%s %s: image %s of container %s is from disallowed registry %s",
+			value.ResourceName, value.Path, image, imageValueContainerName(value), registry))
+		failedAttributes = append(failedAttributes, value)
+	}
+
+	if len(details) == 0 {
+		return parsedData, api.ValidationResultTrue, nil
+	}
+	failedResult := api.ValidationResultFalse
+	failedResult.Details = api.ValidationDetailsWithSeverity(details, api.ValidationSeverityFail)
+	failedResult.FailedAttributes = failedAttributes
+	return parsedData, failedResult, nil
+}
+
+// latestImageTag is the tag of images that don't specify a tag or digest.
+const latestImageTag = "latest"
+
+func k8sFnIsImageUpdated(ctx context.Context, functionContext *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, liveState []byte) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	containerName, registryHost, expectedDigest := "", "", ""
+	for _, arg := range args {
+		switch arg.ParameterName {
+		case "container-name":
+			containerName = arg.Value.(string)
+		case "registry-host":
+			registryHost = arg.Value.(string)
+		case "expected-digest":
+			expectedDigest = arg.Value.(string)
+		}
+	}
+
+	_, output, err := k8sFnGetImages(functionContext, parsedData, nil, liveState)
+	if err != nil {
+		return parsedData, api.ValidationResultFalse, err
+	}
+	details := []string{}
+	failedAttributes := api.AttributeValueList{}
+	found := false
+	for _, value := range output.(api.AttributeValueList) {
+		image, ok := value.Value.(string)
+		if !ok || imageValueContainerName(value) != containerName {
+			continue
+		}
+		found = true
+		matches := imageURIReferenceRegexp.FindStringSubmatch(image)
+		if len(matches) != 3 {
+			return parsedData, api.ValidationResultFalse, errors.Newf("invalid image %s of container %s", image, containerName)
+		}
+		registry, repository := imageRegistryAndRepository(matches[1])
+		if registryHost != "" {
+			registry = registryHost
+		}
+
+		actualDigest, pinned := imageDigest(image)
+		if !pinned {
+			tag := strings.TrimPrefix(matches[2], ":")
+			if tag == "" {
+				tag = latestImageTag
+			}
+			actualDigest, err = resolveImageTag(ctx, registry, repository, tag)
+			if err != nil {
+				return parsedData, api.ValidationResultFalse, err
+			}
+		}
+		wantDigest := expectedDigest
+		if wantDigest == "" {
+			wantDigest, err = resolveImageTag(ctx, registry, repository, latestImageTag)
+			if err != nil {
+				return parsedData, api.ValidationResultFalse, err
+			}
+		}
+		if actualDigest == wantDigest {
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s %s: image %s of container %s is at digest %s but expected %s",
+			value.ResourceName, value.Path, image, containerName, actualDigest, wantDigest))
+		failedAttributes = append(failedAttributes, value)
+	}
+	if !found {
+		return parsedData, api.ValidationResultFalse, errors.Newf("no container named %s found", containerName)
+	}
+
+	if len(details) == 0 {
+		return parsedData, api.ValidationResultTrue, nil
+	}
+	failedResult := api.ValidationResultFalse
+	failedResult.Details = api.ValidationDetailsWithSeverity(details, api.ValidationSeverityFail)
+	failedResult.FailedAttributes = failedAttributes
+	return parsedData, failedResult, nil
+}
+
 func k8sFnSetEnv(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
 	multiErrs := []error{}
 	// The argument value types should be verified before this function is called
@@ -1084,6 +1731,97 @@ func k8sFnSetEnv(_ *api.FunctionContext, parsedData gaby.Container, args []api.F
 	return parsedData, nil, nil
 }
 
+const (
+	envFromSourceTypeConfigMap = "configMap"
+	envFromSourceTypeSecret    = "secret"
+)
+
+func k8sFnSetEnvFrom(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	containerName := args[0].Value.(string)
+	sourceType := args[1].Value.(string)
+	name := args[2].Value.(string)
+	if sourceType != envFromSourceTypeConfigMap && sourceType != envFromSourceTypeSecret {
+		return parsedData, nil, errors.Newf("invalid source type %s", sourceType)
+	}
+	refKey := sourceType + "Ref"
+
+	multiErrs := []error{}
+	for _, doc := range parsedData {
+		resourceType, err := k8skit.K8sResourceProvider.ResourceTypeGetter(doc)
+		if err != nil {
+			continue // Skip malformed resources
+		}
+		containersPaths, ok := resourceTypeToContainersPaths[resourceType]
+		if !ok {
+			continue // Skip resource kinds we don't handle
+		}
+
+		for _, containersPath := range containersPaths {
+			unresolvedPath := api.UnresolvedPath(containersPath + ".?name=" + containerName)
+			resolvedContainersPaths, err := yamlkit.ResolveAssociativePaths(doc, unresolvedPath, "", false)
+			if err != nil {
+				continue // skip problematic path
+			}
+			for _, containerPath := range resolvedContainersPaths {
+				container, found, err := yamlkit.YamlSafePathGetDoc(doc, containerPath.Path, true)
+				if !found || err != nil {
+					continue
+				}
+				if err := setContainerEnvFrom(container, refKey, name); err != nil {
+					multiErrs = append(multiErrs, err)
+				}
+			}
+		}
+	}
+
+	if len(multiErrs) != 0 {
+		return parsedData, nil, errors.WithStack(errors.Join(multiErrs...))
+	}
+	return parsedData, nil, nil
+}
+
+// setContainerEnvFrom adds an envFrom entry referencing the named source using refKey, such as
+// configMapRef, unless it's already present. If name is empty, all entries using refKey are removed.
+func setContainerEnvFrom(container *gaby.YamlDoc, refKey, name string) error {
+	envFrom := container.Path("envFrom")
+	if name == "" {
+		if envFrom == nil {
+			return nil
+		}
+		entries := envFrom.Children()
+		for i := len(entries) - 1; i >= 0; i-- {
+			if entries[i].Exists(refKey) {
+				if err := envFrom.ArrayRemove(i); err != nil {
+					return errors.Wrapf(err, "error removing %s", refKey)
+				}
+			}
+		}
+		if len(envFrom.Children()) == 0 {
+			return container.Delete("envFrom")
+		}
+		return nil
+	}
+
+	if envFrom == nil {
+		var err error
+		envFrom, err = container.Array("envFrom")
+		if err != nil {
+			return errors.Wrap(err, "error creating envFrom array")
+		}
+	}
+	for _, entry := range envFrom.Children() {
+		if refName, ok := entry.Path(refKey + ".name").Data().(string); ok && refName == name {
+			return nil
+		}
+	}
+	val := map[string]interface{}{refKey: map[string]interface{}{"name": name}}
+	if err := envFrom.ArrayAppend(val); err != nil {
+		return errors.Wrapf(err, "error appending %s %s", refKey, name)
+	}
+	return nil
+}
+
 const (
 	containerResourceOperationAll   = "all"
 	containerResourceOperationCap   = "cap"
@@ -1210,6 +1948,66 @@ func k8sSetResources(
 	return newDoc, nil
 }
 
+func k8sFnRequireResourceRequests(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	exemptContainers := map[string]bool{}
+	if len(args) > 0 {
+		for _, containerName := range strings.Split(args[0].Value.(string), ",") {
+			if containerName = strings.TrimSpace(containerName); containerName != "" {
+				exemptContainers[containerName] = true
+			}
+		}
+	}
+
+	details := []string{}
+	for _, doc := range parsedData {
+		resourceType, err := k8skit.K8sResourceProvider.ResourceTypeGetter(doc)
+		if err != nil {
+			continue // Skip malformed resources
+		}
+		containersPaths, ok := resourceTypeToContainersPaths[resourceType]
+		if !ok {
+			continue // Skip resource kinds we don't handle
+		}
+		resourceName, _, err := yamlkit.YamlSafePathGetValue[string](doc, k8skit.K8sResourceProvider.ScopelessResourceNamePath(), true)
+		if err != nil {
+			return parsedData, api.ValidationResultFalse, err
+		}
+
+		for _, containersPath := range containersPaths {
+			resolvedContainersPaths, err := yamlkit.ResolveAssociativePaths(doc, api.UnresolvedPath(containersPath+".?name=*"), "", false)
+			if err != nil {
+				continue // skip problematic path
+			}
+			for _, containerPath := range resolvedContainersPaths {
+				container, found, err := yamlkit.YamlSafePathGetDoc(doc, containerPath.Path, true)
+				if !found || err != nil {
+					continue
+				}
+				containerName, _ := container.Path("name").Data().(string)
+				if exemptContainers[containerName] {
+					continue
+				}
+				var missing []string
+				for _, request := range []string{"cpu", "memory"} {
+					if !container.Exists("resources", "requests", request) {
+						missing = append(missing, request)
+					}
+				}
+				if len(missing) > 0 {
+					details = append(details, fmt.Sprintf("%s/%s: missing %s requests", resourceName, containerName, strings.Join(missing, " and ")))
+				}
+			}
+		}
+	}
+
+	if len(details) == 0 {
+		return parsedData, api.ValidationResultTrue, nil
+	}
+	failedResult := api.ValidationResultFalse
+	failedResult.Details = api.ValidationDetailsWithSeverity(details, api.ValidationSeverityFail)
+	return parsedData, failedResult, nil
+}
+
 func k8sFnSetContainerResources(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
 	// The argument value types should be verified before this function is called
 	containerName := args[0].Value.(string)
@@ -1333,7 +2131,7 @@ func k8sFnSetPodDefaults(_ *api.FunctionContext, parsedData gaby.Container, args
 		if !ok {
 			continue // Skip resource kinds we don't handle
 		}
-		log.Infof("traversing resource of type " + string(resourceType))
+		log.Infof("traversing resource of type %s", resourceType)
 
 		for _, podSpecPath := range podSpecPaths {
 			// For some of these attributes, we don't care whether or how they were set.
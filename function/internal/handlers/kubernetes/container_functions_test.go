@@ -4,6 +4,12 @@
 package kubernetes
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -556,3 +562,554 @@ spec:
 `
 	assert.YAMLEq(t, expectedYaml, output.String())
 }
+
+func TestK8sFnSetEnvFrom(t *testing.T) {
+	yamlTestFixture := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:1.0
+        envFrom:
+        - configMapRef:
+            name: existing-config
+      - name: sidecar
+        image: sidecar:1.0
+`
+	configYaml, err := gaby.ParseAll([]byte(yamlTestFixture))
+	assert.NoError(t, err)
+
+	output, _, err := k8sFnSetEnvFrom(&fakeContext, configYaml, stringArgsToFunctionArgs([]string{"api", "configMap", "app-config"}), []byte{})
+	assert.NoError(t, err)
+	output, _, err = k8sFnSetEnvFrom(&fakeContext, output, stringArgsToFunctionArgs([]string{"api", "secret", "app-secret"}), []byte{})
+	assert.NoError(t, err)
+
+	expectedYaml := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:1.0
+        envFrom:
+        - configMapRef:
+            name: existing-config
+        - configMapRef:
+            name: app-config
+        - secretRef:
+            name: app-secret
+      - name: sidecar
+        image: sidecar:1.0
+`
+	assert.YAMLEq(t, expectedYaml, output.String())
+
+	// Re-applying shouldn't duplicate the entries
+	output, _, err = k8sFnSetEnvFrom(&fakeContext, output, stringArgsToFunctionArgs([]string{"api", "configMap", "app-config"}), []byte{})
+	assert.NoError(t, err)
+	output, _, err = k8sFnSetEnvFrom(&fakeContext, output, stringArgsToFunctionArgs([]string{"api", "secret", "app-secret"}), []byte{})
+	assert.NoError(t, err)
+	assert.YAMLEq(t, expectedYaml, output.String())
+
+	// An empty name removes the entries of the source type
+	output, _, err = k8sFnSetEnvFrom(&fakeContext, output, stringArgsToFunctionArgs([]string{"api", "configMap", ""}), []byte{})
+	assert.NoError(t, err)
+	output, _, err = k8sFnSetEnvFrom(&fakeContext, output, stringArgsToFunctionArgs([]string{"api", "secret", ""}), []byte{})
+	assert.NoError(t, err)
+	assert.YAMLEq(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:1.0
+      - name: sidecar
+        image: sidecar:1.0
+`, output.String())
+}
+
+func TestK8sFnSetEnvFrom_AllContainers(t *testing.T) {
+	yamlTestFixture := `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test-pod
+spec:
+  containers:
+  - name: api
+    image: api:1.0
+  - name: sidecar
+    image: sidecar:1.0
+    envFrom:
+    - secretRef:
+        name: app-secret
+`
+	configYaml, err := gaby.ParseAll([]byte(yamlTestFixture))
+	assert.NoError(t, err)
+
+	output, _, err := k8sFnSetEnvFrom(&fakeContext, configYaml, stringArgsToFunctionArgs([]string{"*", "secret", "app-secret"}), []byte{})
+	assert.NoError(t, err)
+
+	expectedYaml := `apiVersion: v1
+kind: Pod
+metadata:
+  name: test-pod
+spec:
+  containers:
+  - name: api
+    image: api:1.0
+    envFrom:
+    - secretRef:
+        name: app-secret
+  - name: sidecar
+    image: sidecar:1.0
+    envFrom:
+    - secretRef:
+        name: app-secret
+`
+	assert.YAMLEq(t, expectedYaml, output.String())
+}
+
+func TestK8sFnGetImages(t *testing.T) {
+	yamlTestFixture := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: migrate:2.0
+      containers:
+      - name: api
+        image: ghcr.io/acme/api:1.2.3
+      - name: proxy
+        image: envoyproxy/envoy@sha256:abc123
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  image: not-a-container
+`
+	configYaml, err := gaby.ParseAll([]byte(yamlTestFixture))
+	assert.NoError(t, err)
+
+	_, output, err := k8sFnGetImages(&fakeContext, configYaml, nil, []byte{})
+	assert.NoError(t, err)
+	values, ok := output.(api.AttributeValueList)
+	if !assert.True(t, ok) || !assert.Len(t, values, 3) {
+		return
+	}
+
+	expected := []struct {
+		path, containerName, image string
+	}{
+		{"spec.template.spec.containers.0.image", "api", "ghcr.io/acme/api:1.2.3"},
+		{"spec.template.spec.containers.1.image", "proxy", "envoyproxy/envoy@sha256:abc123"},
+		{"spec.template.spec.initContainers.0.image", "migrate", "migrate:2.0"},
+	}
+	for i, e := range expected {
+		value := values[i]
+		assert.Equal(t, api.ResourceName("prod/web"), value.ResourceName)
+		assert.Equal(t, api.ResourceType("apps/v1/Deployment"), value.ResourceType)
+		assert.Equal(t, api.ResolvedPath(e.path), value.Path)
+		assert.Equal(t, e.image, value.Value)
+		if assert.NotNil(t, value.Info) && assert.NotNil(t, value.Info.GetterInvocation) && assert.Len(t, value.Info.GetterInvocation.Arguments, 1) {
+			assert.Equal(t, "get-image", value.Info.GetterInvocation.FunctionName)
+			assert.Equal(t, e.containerName, value.Info.GetterInvocation.Arguments[0].Value)
+		}
+	}
+
+	// The output is the same on every invocation
+	_, again, err := k8sFnGetImages(&fakeContext, configYaml, nil, []byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, values, again)
+}
+
+func TestCompareResourceTypes(t *testing.T) {
+	resourceTypes := []api.ResourceType{
+		"example.com/v10/Widget",
+		"apps/v1/Deployment",
+		"example.com/v9/Widget",
+		"example.com/v1/Widget",
+		"example.com/v1beta1/Widget",
+		"v1/Pod",
+		"apps/v1/DaemonSet",
+	}
+	sort.Slice(resourceTypes, func(i, j int) bool {
+		return compareResourceTypes(resourceTypes[i], resourceTypes[j]) < 0
+	})
+	assert.Equal(t, []api.ResourceType{
+		"v1/Pod",
+		"apps/v1/DaemonSet",
+		"apps/v1/Deployment",
+		"example.com/v1beta1/Widget",
+		"example.com/v1/Widget",
+		"example.com/v9/Widget",
+		"example.com/v10/Widget",
+	}, resourceTypes)
+}
+
+func TestK8sFnPinImages(t *testing.T) {
+	yamlTestFixture := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: ghcr.io/acme/api:1.2.3
+      - name: proxy
+        image: envoyproxy/envoy:v1.30.0
+`
+	configYaml, err := gaby.ParseAll([]byte(yamlTestFixture))
+	assert.NoError(t, err)
+
+	digest := "sha256:0f5c2d2e2d4f3c4b1a8e5a9e1d7c3b2a6f4e8d9c0b1a2f3e4d5c6b7a8f9e0d1c"
+	args := stringArgsToFunctionArgs([]string{`{"ghcr.io/acme/api": "` + digest + `", "quay.io/acme/worker": "@` + digest + `"}`})
+	output, result, err := k8sFnPinImages(&fakeContext, configYaml, args, []byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, "ghcr.io/acme/api@"+digest, output[0].Path("spec.template.spec.containers.0.image").Data())
+	assert.Equal(t, "envoyproxy/envoy:v1.30.0", output[0].Path("spec.template.spec.containers.1.image").Data())
+	assert.Equal(t, PinImagesResult{
+		Pinned: []PinnedImage{{
+			ResourceName: "/web",
+			ResourceType: "apps/v1/Deployment",
+			Path:         "spec.template.spec.containers.0.image",
+			Image:        "ghcr.io/acme/api:1.2.3",
+			PinnedImage:  "ghcr.io/acme/api@" + digest,
+		}},
+		UnmatchedRepositories: []string{"quay.io/acme/worker"},
+	}, result)
+
+	// Pinning again doesn't change anything
+	output, result, err = k8sFnPinImages(&fakeContext, output, args, []byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, "ghcr.io/acme/api@"+digest, output[0].Path("spec.template.spec.containers.0.image").Data())
+	assert.Empty(t, result.(PinImagesResult).Pinned)
+	assert.Equal(t, []string{"quay.io/acme/worker"}, result.(PinImagesResult).UnmatchedRepositories)
+
+	_, _, err = k8sFnPinImages(&fakeContext, output, stringArgsToFunctionArgs([]string{`{"ghcr.io/acme/api": "1.2.3"}`}), []byte{})
+	assert.ErrorContains(t, err, "invalid digest")
+	_, _, err = k8sFnPinImages(&fakeContext, output, stringArgsToFunctionArgs([]string{`["ghcr.io/acme/api"]`}), []byte{})
+	assert.ErrorContains(t, err, "must be a JSON object")
+}
+
+func TestK8sFnImageDigest(t *testing.T) {
+	yamlTestFixture := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: migrate
+      containers:
+      - name: api
+        image: ghcr.io/acme/api:1.2.3
+      - name: proxy
+        image: envoyproxy/envoy@sha256:abc123
+      - name: cache
+        image: redis:7.2@sha256:def456
+`
+	configYaml, err := gaby.ParseAll([]byte(yamlTestFixture))
+	assert.NoError(t, err)
+
+	_, output, err := k8sFnGetImageDigest(&fakeContext, configYaml, nil, []byte{})
+	assert.NoError(t, err)
+	values, ok := output.(api.AttributeValueList)
+	if assert.True(t, ok) && assert.Len(t, values, 2) {
+		assert.Equal(t, api.ResolvedPath("spec.template.spec.containers.1.image"), values[0].Path)
+		assert.Equal(t, "sha256:abc123", values[0].Value)
+		// Images may specify a tag as well as a digest
+		assert.Equal(t, api.ResolvedPath("spec.template.spec.containers.2.image"), values[1].Path)
+		assert.Equal(t, "sha256:def456", values[1].Value)
+	}
+	assert.Equal(t, []string{"ghcr.io/acme/api:1.2.3@sha256:abc123", "ghcr.io/acme/api", ":1.2.3@sha256:abc123"},
+		imageURIReferenceRegexp.FindStringSubmatch("ghcr.io/acme/api:1.2.3@sha256:abc123"))
+
+	_, output, err = k8sFnValidateImagePinned(&fakeContext, configYaml, nil, []byte{})
+	assert.NoError(t, err)
+	result, ok := output.(api.ValidationResult)
+	if assert.True(t, ok) {
+		assert.False(t, result.Passed)
+		assert.Equal(t, []string{
+			"/web spec.template.spec.containers.0.image: image ghcr.io/acme/api:1.2.3 is not pinned to a digest",
+			"/web spec.template.spec.initContainers.0.image: image migrate is not pinned to a digest",
+		}, result.DetailMessages())
+		assert.Len(t, result.FailedAttributes, 2)
+	}
+
+	digest := "sha256:0f5c2d2e2d4f3c4b1a8e5a9e1d7c3b2a6f4e8d9c0b1a2f3e4d5c6b7a8f9e0d1c"
+	configYaml, _, err = k8sFnSetImageDigest(&fakeContext, configYaml, stringArgsToFunctionArgs([]string{"*", digest}), []byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, "ghcr.io/acme/api@"+digest, configYaml[0].Path("spec.template.spec.containers.0.image").Data())
+	assert.Equal(t, "envoyproxy/envoy@"+digest, configYaml[0].Path("spec.template.spec.containers.1.image").Data())
+	assert.Equal(t, "redis@"+digest, configYaml[0].Path("spec.template.spec.containers.2.image").Data())
+	assert.Equal(t, "migrate@"+digest, configYaml[0].Path("spec.template.spec.initContainers.0.image").Data())
+
+	_, output, err = k8sFnValidateImagePinned(&fakeContext, configYaml, nil, []byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, api.ValidationResultTrue, output)
+
+	_, _, err = k8sFnSetImageDigest(&fakeContext, configYaml, stringArgsToFunctionArgs([]string{"api", "sha256:ABC"}), []byte{})
+	assert.ErrorContains(t, err, "invalid digest")
+}
+
+func TestK8sFnValidateImageRegistries(t *testing.T) {
+	yamlTestFixture := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: registry.internal.example.com/tools/migrate:2.0
+      containers:
+      - name: api
+        image: ghcr.io/acme/api:1.2.3
+      - name: proxy
+        image: envoyproxy/envoy@sha256:abc123
+      ephemeralContainers:
+      - name: debug
+        image: ghcr.io/other/debug:latest
+`
+	configYaml, err := gaby.ParseAll([]byte(yamlTestFixture))
+	assert.NoError(t, err)
+
+	_, output, err := k8sFnValidateImageRegistries(&fakeContext, configYaml, stringArgsToFunctionArgs([]string{"ghcr.io/acme, *.internal.example.com"}), []byte{})
+	assert.NoError(t, err)
+	result, ok := output.(api.ValidationResult)
+	if assert.True(t, ok) {
+		assert.False(t, result.Passed)
+		assert.Equal(t, []string{
+			"prod/web spec.template.spec.containers.1.image: image envoyproxy/envoy@sha256:abc123 of container proxy is from disallowed registry docker.io",
+			"prod/web spec.template.spec.ephemeralContainers.0.image: image ghcr.io/other/debug:latest of container debug is from disallowed registry ghcr.io",
+		}, result.DetailMessages())
+		assert.Len(t, result.FailedAttributes, 2)
+	}
+
+	_, output, err = k8sFnValidateImageRegistries(&fakeContext, configYaml, stringArgsToFunctionArgs([]string{"ghcr.io,docker.io/envoyproxy,*.example.com"}), []byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, api.ValidationResultTrue, output)
+
+	// Wildcards match subdomains only, and repository prefixes match whole segments
+	_, output, err = k8sFnValidateImageRegistries(&fakeContext, configYaml, stringArgsToFunctionArgs([]string{"*.ghcr.io,ghcr.io/acm,docker.io,registry.internal.example.com"}), []byte{})
+	assert.NoError(t, err)
+	result, ok = output.(api.ValidationResult)
+	if assert.True(t, ok) {
+		assert.False(t, result.Passed)
+		assert.Len(t, result.Details, 2)
+	}
+}
+
+func TestK8sFnIsImageUpdated(t *testing.T) {
+	const (
+		latestDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		oldDigest    = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+	manifests := map[string]string{
+		"/v2/acme/api/manifests/latest": latestDigest,
+		"/v2/acme/api/manifests/1.2.3":  oldDigest,
+		"/v2/acme/api/manifests/1.3.0":  latestDigest,
+	}
+	var registry *httptest.Server
+	realm := ""
+	registry = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Require an anonymous bearer token, as docker.io does
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "repository:acme/api:pull,push", r.URL.Query().Get("scope"))
+			fmt.Fprint(w, `{"token": "anonymous"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s",service="registry",scope="repository:acme/api:pull,push"`, realm))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		digest, ok := manifests[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", digest)
+	}))
+	defer registry.Close()
+	realm = registry.URL + "/token"
+	savedClient := registryHTTPClient
+	registryHTTPClient = registry.Client()
+	defer func() { registryHTTPClient = savedClient }()
+
+	yamlTestFixture := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: ghcr.io/acme/api:%s
+      - name: proxy
+        image: envoyproxy/envoy:v1.30
+`
+	isImageUpdated := func(imageReference string, args ...api.FunctionArgument) (any, error) {
+		configYaml, err := gaby.ParseAll([]byte(fmt.Sprintf(yamlTestFixture, imageReference)))
+		assert.NoError(t, err)
+		args = append([]api.FunctionArgument{
+			{ParameterName: "container-name", Value: "api"},
+			{ParameterName: "registry-host", Value: registry.URL},
+		}, args...)
+		_, output, err := k8sFnIsImageUpdated(context.Background(), &fakeContext, configYaml, args, []byte{})
+		return output, err
+	}
+
+	// Tags are compared with the latest tag by default
+	output, err := isImageUpdated("1.3.0")
+	assert.NoError(t, err)
+	assert.Equal(t, api.ValidationResultTrue, output)
+
+	output, err = isImageUpdated("1.2.3")
+	assert.NoError(t, err)
+	result, ok := output.(api.ValidationResult)
+	if assert.True(t, ok) {
+		assert.False(t, result.Passed)
+		assert.Equal(t, []string{
+			"prod/web spec.template.spec.containers.0.image: image ghcr.io/acme/api:1.2.3 of container api is at digest " + oldDigest + " but expected " + latestDigest,
+		}, result.DetailMessages())
+		assert.Len(t, result.FailedAttributes, 1)
+	}
+
+	// Images pinned to digests are compared without resolving them
+	output, err = isImageUpdated("1.2.3", api.FunctionArgument{ParameterName: "expected-digest", Value: oldDigest})
+	assert.NoError(t, err)
+	assert.Equal(t, api.ValidationResultTrue, output)
+	configYaml, err := gaby.ParseAll([]byte(fmt.Sprintf(yamlTestFixture, "1.2.3")))
+	assert.NoError(t, err)
+	_, _, err = k8sFnSetImageDigest(&fakeContext, configYaml, stringArgsToFunctionArgs([]string{"api", oldDigest}), []byte{})
+	assert.NoError(t, err)
+	_, output, err = k8sFnIsImageUpdated(context.Background(), &fakeContext, configYaml, []api.FunctionArgument{
+		{ParameterName: "container-name", Value: "api"},
+		{ParameterName: "registry-host", Value: registry.URL},
+	}, []byte{})
+	assert.NoError(t, err)
+	result, ok = output.(api.ValidationResult)
+	if assert.True(t, ok) {
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Details[0].Message, "is at digest "+oldDigest+" but expected "+latestDigest)
+	}
+
+	// Registry errors are returned
+	_, err = isImageUpdated("9.9.9")
+	assert.ErrorContains(t, err, "returned 404 Not Found for acme/api:9.9.9")
+	_, err = isImageUpdated("1.3.0", api.FunctionArgument{ParameterName: "container-name", Value: "missing"})
+	assert.EqualError(t, err, "no container named missing found")
+
+	// Registries must use https, and tokens are only requested from the registry
+	_, err = isImageUpdated("1.3.0", api.FunctionArgument{ParameterName: "registry-host", Value: strings.Replace(registry.URL, "https", "http", 1)})
+	assert.ErrorContains(t, err, "must use https")
+	realm = "https://attacker.example.com/token"
+	_, err = isImageUpdated("1.3.0")
+	assert.ErrorContains(t, err, "is not a token service of registry")
+	realm = registry.URL + "/token"
+
+	// Requests are canceled with the context of the invocation
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	configYaml, err = gaby.ParseAll([]byte(fmt.Sprintf(yamlTestFixture, "1.3.0")))
+	assert.NoError(t, err)
+	_, _, err = k8sFnIsImageUpdated(ctx, &fakeContext, configYaml, []api.FunctionArgument{
+		{ParameterName: "container-name", Value: "api"},
+		{ParameterName: "registry-host", Value: registry.URL},
+	}, []byte{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	scheme, params, err := parseAuthChallenge(`Bearer realm="https://auth.docker.io/token", service="registry.docker.io",scope="repository:acme/api:pull,push",note="a \"quoted\" value",error=insufficient_scope`)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:acme/api:pull,push",
+		"note":    `a "quoted" value`,
+		"error":   "insufficient_scope",
+	}, params)
+
+	_, _, err = parseAuthChallenge(`Bearer realm="https://auth.docker.io/token`)
+	assert.ErrorContains(t, err, "unterminated quoted value")
+	_, _, err = parseAuthChallenge(`Bearer realm`)
+	assert.ErrorContains(t, err, "invalid authentication challenge")
+}
+
+func TestK8sFnRequireResourceRequests(t *testing.T) {
+	yamlTestFixture := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: migrate:2.0
+        resources:
+          requests:
+            cpu: 100m
+      containers:
+      - name: api
+        image: api:1.0
+        resources:
+          requests:
+            cpu: 500m
+            memory: 256Mi
+      - name: istio-proxy
+        image: istio/proxyv2:1.22.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+	configYaml, err := gaby.ParseAll([]byte(yamlTestFixture))
+	assert.NoError(t, err)
+
+	_, output, err := k8sFnRequireResourceRequests(&fakeContext, configYaml, nil, []byte{})
+	assert.NoError(t, err)
+	result, ok := output.(api.ValidationResult)
+	if assert.True(t, ok) {
+		assert.False(t, result.Passed)
+		assert.ElementsMatch(t, []string{
+			"web/istio-proxy: missing cpu and memory requests",
+			"web/migrate: missing memory requests",
+		}, result.DetailMessages())
+	}
+
+	_, output, err = k8sFnRequireResourceRequests(&fakeContext, configYaml, stringArgsToFunctionArgs([]string{"istio-proxy, migrate"}), []byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, api.ValidationResultTrue, output)
+}
@@ -0,0 +1,88 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func runDescribeAttributes(t *testing.T, args []api.FunctionArgument) api.AttributeDescriptionList {
+	registration := testHandler.ListCore()["describe-attributes"]
+	// The configuration data doesn't contain any of the attributes
+	_, output, err := registration.Function(&fakeContext, gaby.Container{}, args, []byte{})
+	assert.NoError(t, err)
+	descriptions, ok := output.(api.AttributeDescriptionList)
+	assert.True(t, ok)
+	return descriptions
+}
+
+func findAttributeDescription(descriptions api.AttributeDescriptionList, attributeName api.AttributeName) *api.AttributeDescription {
+	for i := range descriptions {
+		if descriptions[i].AttributeName == attributeName {
+			return &descriptions[i]
+		}
+	}
+	return nil
+}
+
+func TestDescribeAttributes(t *testing.T) {
+	descriptions := runDescribeAttributes(t, stringArgsToFunctionArgs([]string{"apps/v1/Deployment"}))
+	for _, description := range descriptions {
+		assert.Contains(t, []api.ResourceType{"apps/v1/Deployment", api.ResourceTypeAny}, description.ResourceType)
+	}
+
+	image := findAttributeDescription(descriptions, api.AttributeNameContainerImage)
+	if assert.NotNil(t, image) {
+		assert.Equal(t, api.DataTypeString, image.DataType)
+		assert.Equal(t, "get-image", image.GetterFunctionName)
+		assert.Contains(t, image.SetterFunctionNames, "set-image")
+		if assert.NotNil(t, image.ValueConstraints) {
+			assert.NotEmpty(t, image.ValueConstraints.Regexp)
+		}
+	}
+
+	replicas := findAttributeDescription(descriptions, attributeNameReplicas)
+	if assert.NotNil(t, replicas) {
+		assert.Equal(t, api.UnresolvedPath("spec.replicas"), replicas.Path)
+		assert.Equal(t, api.DataTypeInt, replicas.DataType)
+		assert.Equal(t, "get-replicas", replicas.GetterFunctionName)
+		assert.Equal(t, []string{"set-replicas"}, replicas.SetterFunctionNames)
+		if assert.NotNil(t, replicas.ValueConstraints) && assert.NotNil(t, replicas.ValueConstraints.Min) {
+			assert.Equal(t, 0, *replicas.ValueConstraints.Min)
+		}
+	}
+
+	envValue := findAttributeDescription(descriptions, attributeNameEnvValue)
+	if assert.NotNil(t, envValue) {
+		assert.Equal(t, api.DataTypeString, envValue.DataType)
+		assert.Equal(t, "get-env-var", envValue.GetterFunctionName)
+		assert.Equal(t, []string{"set-env-var"}, envValue.SetterFunctionNames)
+		// The env value has no constraints
+		assert.Nil(t, envValue.ValueConstraints)
+	}
+
+	// Attributes are only reported once per path
+	count := 0
+	for _, description := range descriptions {
+		if description.AttributeName == attributeNameReplicas {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestDescribeAttributes_AllResourceTypes(t *testing.T) {
+	all := runDescribeAttributes(t, nil)
+	deployment := runDescribeAttributes(t, stringArgsToFunctionArgs([]string{"apps/v1/Deployment"}))
+	assert.Greater(t, len(all), len(deployment))
+	assert.Equal(t, all, runDescribeAttributes(t, stringArgsToFunctionArgs([]string{"*"})))
+
+	configMap := runDescribeAttributes(t, stringArgsToFunctionArgs([]string{"v1/ConfigMap"}))
+	assert.Nil(t, findAttributeDescription(configMap, attributeNameReplicas))
+}
@@ -0,0 +1,136 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const ensureContextFixture = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: backend
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: backend-config
+  annotations:
+    confighub.com/UnitSlug: stale
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: backend-secret
+`
+
+func runEnsureContext(t *testing.T, docs gaby.Container, addContext bool) gaby.Container {
+	functionContext := &api.FunctionContext{
+		UnitSlug: "my-unit",
+		SpaceID:  uuid.MustParse("7c61626f-ddbe-41af-93f6-b69f4ab6d308"),
+	}
+	registration := testHandler.ListCore()["ensure-context"]
+	docs, _, err := registration.Function(functionContext, docs, []api.FunctionArgument{{ParameterName: "add-context", Value: addContext}}, []byte{})
+	assert.NoError(t, err)
+	return docs
+}
+
+func TestEnsureContext_TypeExceptions(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(ensureContextFixture))
+	assert.NoError(t, err)
+	const unitSlugPath = "metadata.annotations.confighub~1com/UnitSlug"
+	const spaceIDPath = "metadata.annotations.confighub~1com/SpaceID"
+
+	docs = runEnsureContext(t, docs, true)
+	assert.Equal(t, "my-unit", docs[0].Path(unitSlugPath).Data())
+	assert.Equal(t, "7c61626f-ddbe-41af-93f6-b69f4ab6d308", docs[0].Path(spaceIDPath).Data())
+	for _, doc := range docs[1:] {
+		assert.False(t, doc.ExistsP(spaceIDPath), doc.String())
+	}
+	// Resources of excepted types are left untouched
+	assert.False(t, docs[1].ExistsP(unitSlugPath))
+	assert.Equal(t, "stale", docs[2].Path(unitSlugPath).Data())
+	assert.False(t, docs[3].ExistsP(unitSlugPath))
+
+	docs = runEnsureContext(t, docs, false)
+	assert.False(t, docs[0].ExistsP(unitSlugPath))
+	assert.False(t, docs[0].ExistsP(spaceIDPath))
+	assert.Equal(t, "stale", docs[2].Path(unitSlugPath).Data())
+}
+
+func TestContextPathsAreRegisteredWithTypeExceptions(t *testing.T) {
+	contextPaths := yamlkit.GetPathRegistryForAttributeName(k8skit.K8sResourceProvider, api.AttributeNameContext)
+	pathInfo := contextPaths[api.ResourceTypeAny]["metadata.annotations.confighub~1com/UnitSlug"]
+	if assert.NotNil(t, pathInfo) {
+		assert.Equal(t, map[api.ResourceType]struct{}{
+			"v1/ServiceAccount": {},
+			"v1/ConfigMap":      {},
+			"v1/Secret":         {},
+		}, pathInfo.TypeExceptions)
+	}
+}
+
+func TestEnsureContext_InjectLabels(t *testing.T) {
+	functionContext := &api.FunctionContext{
+		UnitSlug: "my-unit",
+		SpaceID:  uuid.MustParse("7c61626f-ddbe-41af-93f6-b69f4ab6d308"),
+		Labels:   map[string]string{"env": "production", "team": "platform"},
+	}
+	registration := testHandler.ListCore()["ensure-context"]
+	ensureContext := func(injectLabels bool) gaby.Container {
+		docs, err := gaby.ParseAll([]byte(ensureContextFixture))
+		assert.NoError(t, err)
+		docs, _, err = registration.Function(functionContext, docs, []api.FunctionArgument{
+			{ParameterName: "add-context", Value: true},
+			{ParameterName: "inject-labels", Value: injectLabels},
+		}, []byte{})
+		assert.NoError(t, err)
+		return docs
+	}
+
+	docs := ensureContext(true)
+	assert.Equal(t, "production", docs[0].Path("metadata.labels.env").Data())
+	assert.False(t, docs[0].ExistsP("metadata.labels.team"))
+	// Context isn't added to the excepted resource types
+	assert.False(t, docs[1].ExistsP("metadata.labels.env"))
+
+	docs = ensureContext(false)
+	assert.False(t, docs[0].ExistsP("metadata.labels.env"))
+}
+
+func TestRebindContext(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(ensureContextFixture))
+	assert.NoError(t, err)
+	const unitSlugPath = "metadata.annotations.confighub~1com/UnitSlug"
+	const spaceIDPath = "metadata.annotations.confighub~1com/SpaceID"
+	// ensure-context leaves the stale context of the ConfigMap, which is an exception
+	docs = runEnsureContext(t, docs, true)
+
+	functionContext := &api.FunctionContext{
+		UnitSlug: "my-clone",
+		SpaceID:  uuid.MustParse("0f5c2d2e-2d4f-4c4b-9a8e-5a9e1d7c3b2a"),
+	}
+	registration := testHandler.ListCore()["rebind-context"]
+	docs, _, err = registration.Function(functionContext, docs, nil, []byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, "my-clone", docs[0].Path(unitSlugPath).Data())
+	assert.Equal(t, "0f5c2d2e-2d4f-4c4b-9a8e-5a9e1d7c3b2a", docs[0].Path(spaceIDPath).Data())
+	// Only the context values already present are updated
+	assert.Equal(t, "my-clone", docs[2].Path(unitSlugPath).Data())
+	assert.False(t, docs[2].ExistsP(spaceIDPath))
+	assert.False(t, docs[3].ExistsP(unitSlugPath))
+}
@@ -0,0 +1,51 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/configkit/yamlkit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func TestGetLinks(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: app-ns
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1.0
+        envFrom:
+        - configMapRef:
+            name: confighubplaceholder
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: app-ns
+`))
+	require.NoError(t, err)
+	_, output, err := testHandler.ListCore()["get-links"].Function(&fakeContext, docs, nil, nil)
+	require.NoError(t, err)
+	candidates, ok := output.([]yamlkit.LinkCandidate)
+	require.True(t, ok)
+
+	require.Len(t, candidates, 1)
+	assert.Equal(t, api.ResourceType("apps/v1/Deployment"), candidates[0].Needed.ResourceType)
+	assert.Equal(t, api.ResolvedPath("spec.template.spec.containers.0.envFrom.0.configMapRef.name"), candidates[0].Needed.Path)
+	assert.Equal(t, api.ResourceType("v1/ConfigMap"), candidates[0].Provided.ResourceType)
+	assert.Equal(t, "app-config", candidates[0].Provided.Value)
+	assert.Equal(t, "set-references-of-type", candidates[0].SetterInvocation.FunctionName)
+}
@@ -0,0 +1,46 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func TestGetProvided_Source(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: app-ns
+`))
+	assert.NoError(t, err)
+	liveState := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: app-ns
+`)
+	_, output, err := testHandler.ListCore()["get-provided"].Function(&fakeContext, docs, nil, liveState)
+	assert.NoError(t, err)
+	values, ok := output.(api.AttributeValueList)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	sources := map[api.AttributeSource][]any{}
+	for _, value := range values {
+		sources[value.Source] = append(sources[value.Source], value.Value)
+	}
+	assert.Equal(t, map[api.AttributeSource][]any{
+		api.AttributeSourceDesiredState: {"app-ns"},
+		api.AttributeSourceLiveState:    {api.ResourceName("app-config")},
+	}, sources)
+	assert.True(t, values.Filter(func(value api.AttributeValue) bool {
+		return value.Source == api.AttributeSourceLiveState
+	})[0].InLiveState)
+}
@@ -0,0 +1,237 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// registryHTTPClient is used to call container registries. It's a variable so that tests can
+// replace it.
+var registryHTTPClient = &http.Client{}
+
+// registryTimeout bounds the time spent resolving a tag, including authentication.
+const registryTimeout = 30 * time.Second
+
+// dockerHubRegistryHost is the host of the registry API of docker.io.
+const dockerHubRegistryHost = "registry-1.docker.io"
+
+// registryTokenHosts lists the hosts of the token services of registries whose tokens aren't
+// issued by the registry host itself. Tokens are only requested from these hosts and from the
+// registry host, so that registries can't direct requests to arbitrary hosts.
+var registryTokenHosts = map[string][]string{
+	dockerHubRegistryHost: {"auth.docker.io"},
+}
+
+// manifestMediaTypes are the manifest and index media types accepted when resolving a tag, so
+// that the digest of a multi-platform image is that of its index.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// registryBaseURL returns the base URL of the registry API of host, which may include the https
+// scheme. Other schemes are rejected, since tokens and digests must not be sent or received in
+// the clear.
+func registryBaseURL(host string) (*url.URL, error) {
+	if scheme, _, found := strings.Cut(host, "://"); found {
+		if scheme != "https" {
+			return nil, errors.Newf("registry %s must use https", host)
+		}
+	} else {
+		if host == defaultImageRegistry {
+			host = dockerHubRegistryHost
+		}
+		host = "https://" + host
+	}
+	baseURL, err := url.Parse(strings.TrimSuffix(host, "/"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid registry %s", host)
+	}
+	return baseURL, nil
+}
+
+// resolveImageTag returns the digest of the manifest that the tag of the repository refers to in
+// the registry at host, using the OCI distribution API. Anonymous bearer tokens are requested
+// from registries that require them, as for public images on docker.io. The requests are bounded
+// by registryTimeout and canceled with ctx.
+func resolveImageTag(ctx context.Context, host, repository, tag string) (string, error) {
+	if (host == defaultImageRegistry || host == dockerHubRegistryHost) && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	baseURL, err := registryBaseURL(host)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, registryTimeout)
+	defer cancel()
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", baseURL, repository, url.PathEscape(tag))
+
+	response, err := getManifest(ctx, http.MethodHead, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode == http.StatusUnauthorized {
+		response.Body.Close()
+		token, err := registryToken(ctx, baseURL.Host, response.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to authenticate to registry %s", host)
+		}
+		response, err = getManifest(ctx, http.MethodHead, manifestURL, token)
+		if err != nil {
+			return "", err
+		}
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", errors.Newf("registry %s returned %s for %s:%s", host, response.Status, repository, tag)
+	}
+	if digest := response.Header.Get("Docker-Content-Digest"); digest != "" {
+		return digest, nil
+	}
+
+	// The digest header is optional, so compute the digest of the manifest instead
+	token := strings.TrimPrefix(response.Request.Header.Get("Authorization"), "Bearer ")
+	response, err = getManifest(ctx, http.MethodGet, manifestURL, token)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", errors.Newf("registry %s returned %s for %s:%s", host, response.Status, repository, tag)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, response.Body); err != nil {
+		return "", errors.Wrapf(err, "failed to read manifest of %s:%s", repository, tag)
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func getManifest(ctx context.Context, method, manifestURL, token string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, method, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := registryHTTPClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch manifest %s", manifestURL)
+	}
+	return response, nil
+}
+
+// registryToken requests an anonymous token as specified by a Bearer WWW-Authenticate challenge
+// from the registry at registryHost. The realm of the challenge must be an https URL of the
+// registry host or of one of its registryTokenHosts.
+func registryToken(ctx context.Context, registryHost, challenge string) (string, error) {
+	scheme, params, err := parseAuthChallenge(challenge)
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", errors.Newf("unsupported authentication challenge %q", challenge)
+	}
+	realm := params["realm"]
+	if realm == "" {
+		return "", errors.Newf("authentication challenge %q has no realm", challenge)
+	}
+	realmURL, err := url.Parse(realm)
+	if err != nil || realmURL.Scheme != "https" {
+		return "", errors.Newf("realm %q of authentication challenge must be an https URL", realm)
+	}
+	if realmURL.Host != registryHost && !slices.Contains(registryTokenHosts[registryHost], realmURL.Host) {
+		return "", errors.Newf("realm %q of authentication challenge is not a token service of registry %s", realm, registryHost)
+	}
+	values := realmURL.Query()
+	for key, value := range params {
+		if key != "realm" {
+			values.Set(key, value)
+		}
+	}
+	realmURL.RawQuery = values.Encode()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, realmURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := registryHTTPClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", errors.Newf("token request returned %s", response.Status)
+	}
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&tokenResponse); err != nil {
+		return "", errors.Wrap(err, "failed to decode token response")
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	return tokenResponse.AccessToken, nil
+}
+
+// parseAuthChallenge parses a WWW-Authenticate challenge of the form
+// <scheme> <key>=<value>, ..., where values may be quoted strings containing commas and
+// backslash-escaped characters, as specified by RFC 7235.
+func parseAuthChallenge(challenge string) (string, map[string]string, error) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for {
+		rest = strings.TrimLeft(rest, " \t,")
+		if rest == "" {
+			return scheme, params, nil
+		}
+		key, value, found := strings.Cut(rest, "=")
+		if !found {
+			return "", nil, errors.Newf("invalid authentication challenge %q", challenge)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimLeft(value, " \t")
+		if !strings.HasPrefix(value, `"`) {
+			value, rest, _ = strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(value)
+			continue
+		}
+		var unquoted strings.Builder
+		closed := false
+		i := 1
+		for ; i < len(value); i++ {
+			if value[i] == '\\' && i+1 < len(value) {
+				i++
+				unquoted.WriteByte(value[i])
+			} else if value[i] == '"' {
+				closed = true
+				break
+			} else {
+				unquoted.WriteByte(value[i])
+			}
+		}
+		if !closed {
+			return "", nil, errors.Newf("unterminated quoted value in authentication challenge %q", challenge)
+		}
+		params[key] = unquoted.String()
+		rest = value[i+1:]
+	}
+}
@@ -4,6 +4,8 @@
 package kubernetes
 
 import (
+	"sync"
+
 	"github.com/confighub/sdk/configkit/k8skit"
 	"github.com/confighub/sdk/function/api"
 	"github.com/confighub/sdk/function/handler"
@@ -15,6 +17,10 @@ type KubernetesRegistrarType struct{}
 
 var KubernetesRegistrar = &KubernetesRegistrarType{}
 
+// The path registry of the resource provider is shared by all function handlers, so paths
+// are only registered once.
+var initFunctionsOnce sync.Once
+
 func initFunctions() {
 	err := InitSchemaFinder()
 	if err != nil {
@@ -26,11 +32,15 @@ func initFunctions() {
 }
 
 func (r *KubernetesRegistrarType) RegisterFunctions(kh handler.FunctionRegistry) {
-	initFunctions()
+	initFunctionsOnce.Do(initFunctions)
 
-	registerStandardFunctions(kh)
+	if err := registerStandardFunctions(kh); err != nil {
+		// Conflicting path registrations are programming errors, so fail initialization
+		panic(err)
+	}
 	registerMetadataFunctions(kh)
 	registerContainerFunctions(kh)
+	registerConfigMapFunctions(kh)
 
 	kh.SetConverter(k8skit.K8sResourceProvider)
 }
@@ -0,0 +1,80 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/function/handler"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func newListTestHandler(t *testing.T) *handler.FunctionHandler {
+	// Reuse the registrations of testHandler, since registering the functions again would
+	// register their paths again
+	fh := handler.NewFunctionHandler()
+	for _, functionName := range []string{"set-replicas", "set-string-path", "get-resources"} {
+		require.NoError(t, fh.RegisterFunction(functionName, testHandler.ListCore()[functionName]))
+	}
+	err := fh.RegisterFunction("configmap-only", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName:          "configmap-only",
+			Mutating:              true,
+			Description:           "Function that only applies to ConfigMaps",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{"v1/ConfigMap"},
+		},
+		Function: func(_ *api.FunctionContext, parsedData gaby.Container, _ []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+			return parsedData, nil, nil
+		},
+	})
+	require.NoError(t, err)
+	return fh
+}
+
+func TestListForResourceType(t *testing.T) {
+	fh := newListTestHandler(t)
+
+	deploymentFunctions := fh.ListForResourceType("apps/v1/Deployment")
+	assert.Contains(t, deploymentFunctions, "set-replicas")
+	assert.Contains(t, deploymentFunctions, "set-string-path", "functions for any resource type should be included")
+	assert.NotContains(t, deploymentFunctions, "configmap-only")
+	assert.Less(t, len(deploymentFunctions), len(fh.ListCore()))
+
+	configMapFunctions := fh.ListForResourceType("v1/ConfigMap")
+	assert.Contains(t, configMapFunctions, "configmap-only")
+	assert.NotContains(t, configMapFunctions, "set-replicas")
+
+	assert.Equal(t, len(fh.ListCore()), len(fh.ListForResourceType("")))
+}
+
+func TestListResourceTypeQueryParameter(t *testing.T) {
+	fh := newListTestHandler(t)
+	e := echo.New()
+	list := func(target string) map[string]api.FunctionSignature {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, fh.List(e.NewContext(req, rec)))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var functions map[string]api.FunctionSignature
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &functions))
+		return functions
+	}
+
+	functions := list("/function/kubernetes/yaml?" + handler.ResourceTypeQueryParameter + "=apps/v1/Deployment")
+	assert.Contains(t, functions, "set-replicas")
+	assert.NotContains(t, functions, "configmap-only")
+
+	functions = list("/function/kubernetes/yaml")
+	assert.Contains(t, functions, "set-replicas")
+	assert.Contains(t, functions, "configmap-only")
+}
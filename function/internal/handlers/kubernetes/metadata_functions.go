@@ -5,6 +5,7 @@ package kubernetes
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/confighub/sdk/configkit/k8skit"
@@ -103,6 +104,171 @@ func registerMetadataFunctions(fh handler.FunctionRegistry) {
 	}
 	generic.RegisterPathSetterAndGetter(fh, "label", labelParameters,
 		" a label", AttributeNameLabelValue, k8skit.K8sResourceProvider, true, true)
+
+	registerMetadataMapFunctions(fh, "labels", "label", AttributeNameLabelValue)
+	registerMetadataMapFunctions(fh, "annotations", "annotation", AttributeNameAnnotationValue)
+
+	fh.RegisterFunction("require-labels", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "require-labels",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "labels",
+					Required:      true,
+					Description:   "Comma-separated list of required label keys, each optionally followed by = and a regular expression that the whole value must match; commas within groups, repetition counts, and character classes of the regular expressions don't separate labels, and other commas in them must be escaped as \\,",
+					DataType:      api.DataTypeString,
+					Example:       "app.kubernetes.io/name,team,env=^(dev|staging|prod)$",
+				},
+				{
+					ParameterName: "resource-type",
+					Required:      false,
+					Description:   "Type (" + k8skit.K8sResourceProvider.TypeDescription() + ") of the resources that require the labels, or * for all resources (the default)",
+					DataType:      api.DataTypeString,
+					Example:       "apps/v1/Deployment",
+				},
+				{
+					ParameterName: "exempt-types",
+					Required:      false,
+					Description:   "Comma-separated list of resource types that don't require the labels",
+					DataType:      api.DataTypeString,
+					Example:       "v1/Namespace,v1/ServiceAccount",
+				},
+			},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "passed",
+				Description: "True if all of the resources have the required labels with matching values, false otherwise",
+				OutputType:  api.OutputTypeValidationResult,
+			},
+			Mutating:              false,
+			Validating:            true,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Returns true if all resources of the specified type, except exempt types, have the required labels, with values matching the regular expressions if specified",
+			FunctionType:          api.FunctionTypeCustom,
+			AttributeName:         AttributeNameLabelValue,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: k8sFnRequireLabels,
+	})
+
+	fh.RegisterFunction("set-resource-scope", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "set-resource-scope",
+			Parameters: []api.FunctionParameter{
+				{
+					ParameterName: "resource-type",
+					Required:      true,
+					Description:   "Type (" + k8skit.K8sResourceProvider.TypeDescription() + ") of the resource to re-scope",
+					DataType:      api.DataTypeString,
+					Example:       "v1/ConfigMap",
+				},
+				{
+					ParameterName: "resource-name",
+					Required:      true,
+					Description:   "Name of the resource to re-scope, with or without its current namespace",
+					DataType:      api.DataTypeString,
+					Example:       "staging/app-config",
+				},
+				{
+					ParameterName:    "new-scope",
+					Required:         true,
+					Description:      "Namespace to move the resource to",
+					DataType:         api.DataTypeString,
+					Example:          "production",
+					ValueConstraints: api.ValueConstraints{Regexp: "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"},
+				},
+			},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "resource",
+				Description: "Identity of the re-scoped resource",
+				OutputType:  api.OutputTypeResourceInfoList,
+			},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Move a single namespaced resource to another namespace, keeping its name; unlike set-namespace, other resources aren't changed",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny}, // technically only namespace-scoped resources
+		},
+		Function: k8sFnSetResourceScope,
+	})
+}
+
+// registerMetadataMapFunctions registers set-, get-, and remove- functions for the entries of the
+// specified metadata map, such as labels, in resources of a specified type.
+func registerMetadataMapFunctions(fh handler.FunctionRegistry, mapName string, entryName string, attributeName api.AttributeName) {
+	resourceTypeParameter := api.FunctionParameter{
+		ParameterName: "resource-type",
+		Required:      true,
+		Description:   "Type (" + k8skit.K8sResourceProvider.TypeDescription() + ") of the resources, or * for all resources",
+		DataType:      api.DataTypeString,
+		Example:       "apps/v1/Deployment",
+	}
+	keyParameter := api.FunctionParameter{
+		ParameterName: "key",
+		Required:      true,
+		Description:   "Key of the " + entryName,
+		DataType:      api.DataTypeString,
+		Example:       "app.kubernetes.io/part-of",
+	}
+	valueParameter := api.FunctionParameter{
+		ParameterName: "value",
+		Required:      true,
+		Description:   "Value of the " + entryName + "; an empty value removes the " + entryName,
+		DataType:      api.DataTypeString,
+		Example:       "checkout",
+	}
+	fh.RegisterFunction("set-"+mapName, &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName:          "set-" + mapName,
+			Parameters:            []api.FunctionParameter{resourceTypeParameter, keyParameter, valueParameter},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Add or update the " + entryName + " with the specified key in resources of the specified type, leaving other " + mapName + " unchanged; an empty value removes the " + entryName,
+			FunctionType:          api.FunctionTypeCustom,
+			AttributeName:         attributeName,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: metadataMapSetter(mapName, entryName),
+	})
+	fh.RegisterFunction("remove-"+mapName, &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName:          "remove-" + mapName,
+			Parameters:            []api.FunctionParameter{resourceTypeParameter, keyParameter},
+			Mutating:              true,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Remove the " + entryName + " with the specified key from resources of the specified type",
+			FunctionType:          api.FunctionTypeCustom,
+			AttributeName:         attributeName,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: metadataMapSetter(mapName, entryName),
+	})
+	fh.RegisterFunction("get-"+mapName, &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "get-" + mapName,
+			Parameters:   []api.FunctionParameter{resourceTypeParameter},
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  entryName + "-value",
+				Description: "Values of the " + mapName + " of the resources",
+				OutputType:  api.OutputTypeAttributeValueList,
+			},
+			Mutating:              false,
+			Validating:            false,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Get the " + mapName + " of resources of the specified type",
+			FunctionType:          api.FunctionTypeCustom,
+			AttributeName:         attributeName,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: metadataMapGetter(mapName, entryName, attributeName),
+	})
 }
 
 const AttributeNameNamespaceNameReference = api.AttributeName("namespace-name-reference")
@@ -126,7 +292,7 @@ func initMetadataFunctions() {
 		FunctionName: "get-resources-of-type",
 		Arguments:    []api.FunctionArgument{{ParameterName: "resource-type", Value: string(namespaceResourceType)}},
 	}
-	yamlkit.RegisterProvidedPaths(k8skit.K8sResourceProvider, namespaceResourceType, pathInfos, getterFunctionInvocation)
+	yamlkit.MustRegisterProvidedPaths(k8skit.K8sResourceProvider, namespaceResourceType, pathInfos, getterFunctionInvocation)
 
 	// These paths are not included in kustomize's namereference list.
 	var resourceTypeToNamespacePath = api.ResourceTypeToPathToVisitorInfoType{
@@ -186,8 +352,8 @@ func initMetadataFunctions() {
 		Arguments:    []api.FunctionArgument{{ParameterName: "resource-type", Value: string(namespaceResourceType)}},
 	}
 	for resourceType, pathInfos := range resourceTypeToNamespacePath {
-		yamlkit.RegisterNeededPaths(k8skit.K8sResourceProvider, resourceType, pathInfos, setterFunctionInvocation)
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterNeededPaths(k8skit.K8sResourceProvider, resourceType, pathInfos, setterFunctionInvocation)
+		yamlkit.MustRegisterPathsByAttributeName(
 			k8skit.K8sResourceProvider,
 			AttributeNameNamespaceNameReference,
 			resourceType,
@@ -196,7 +362,7 @@ func initMetadataFunctions() {
 			setterFunctionInvocation,
 			false,
 		)
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			k8skit.K8sResourceProvider,
 			api.AttributeNameResourceName,
 			resourceType,
@@ -205,7 +371,7 @@ func initMetadataFunctions() {
 			setterFunctionInvocation,
 			false,
 		)
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			k8skit.K8sResourceProvider,
 			api.AttributeNameGeneral,
 			resourceType,
@@ -235,7 +401,7 @@ func initMetadataFunctions() {
 		FunctionName: "get-annotation",
 		// arguments will be filled in during traversal
 	}
-	yamlkit.RegisterPathsByAttributeName(
+	yamlkit.MustRegisterPathsByAttributeName(
 		k8skit.K8sResourceProvider,
 		AttributeNameAnnotationValue,
 		api.ResourceTypeAny,
@@ -264,7 +430,7 @@ func initMetadataFunctions() {
 		FunctionName: "get-label",
 		// arguments will be filled in during traversal
 	}
-	yamlkit.RegisterPathsByAttributeName(
+	yamlkit.MustRegisterPathsByAttributeName(
 		k8skit.K8sResourceProvider,
 		AttributeNameLabelValue,
 		api.ResourceTypeAny,
@@ -319,3 +485,252 @@ func k8sFnNeededNamespaces(_ *api.FunctionContext, parsedData gaby.Container, _
 	values, err := yamlkit.GetNeededStringPaths(parsedData, resourceTypeToNamespacePath, []any{}, k8skit.K8sResourceProvider)
 	return parsedData, values, err
 }
+
+// metadataMapSetter returns the implementation of set-labels and remove-labels, or the
+// equivalent functions for another metadata map. The entry is removed if there's no value
+// argument or the value is empty.
+func metadataMapSetter(mapName string, entryName string) handler.FunctionImplementation {
+	return func(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+		// The argument value types should be verified before this function is called
+		resourceType := api.ResourceType(args[0].Value.(string))
+		key := args[1].Value.(string)
+		if key == "" {
+			return parsedData, nil, fmt.Errorf("%s key must not be empty", entryName)
+		}
+		value := ""
+		if len(args) > 2 {
+			value = args[2].Value.(string)
+		}
+		entryPath := api.UnresolvedPath("metadata." + mapName + ".@%s:" + entryName + "-key")
+		resourceTypeToPaths := generic.GetVisitorMapForPath(k8skit.K8sResourceProvider, resourceType, entryPath)
+		keys := []any{yamlkit.EscapeDotsInPathSegment(key)}
+		remove := value == ""
+		visitor := func(doc *gaby.YamlDoc, output any, context yamlkit.VisitorContext, currentDoc *gaby.YamlDoc) (any, error) {
+			if remove {
+				return output, doc.DeleteP(string(context.Path))
+			}
+			if currentDoc != nil && currentDoc.Data() == value {
+				return output, nil
+			}
+			_, err := doc.SetP(value, string(context.Path))
+			return output, err
+		}
+		_, err := yamlkit.VisitPathsDoc(parsedData, resourceTypeToPaths, keys, nil, k8skit.K8sResourceProvider, visitor, !remove)
+		return parsedData, nil, err
+	}
+}
+
+// metadataMapGetter returns the implementation of get-labels, or the equivalent function for
+// another metadata map.
+func metadataMapGetter(mapName string, entryName string, attributeName api.AttributeName) handler.FunctionImplementation {
+	return func(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+		// The argument value types should be verified before this function is called
+		resourceType := api.ResourceType(args[0].Value.(string))
+		entriesPath := api.UnresolvedPath("metadata." + mapName + ".*@:" + entryName + "-key")
+		resourceTypeToPaths := generic.GetVisitorMapForPath(k8skit.K8sResourceProvider, resourceType, entriesPath)
+		for _, pathInfos := range resourceTypeToPaths {
+			for _, pathInfo := range pathInfos {
+				pathInfo.AttributeName = attributeName
+				pathInfo.DataType = api.DataTypeString
+			}
+		}
+		values, err := yamlkit.GetStringPaths(parsedData, resourceTypeToPaths, []any{}, k8skit.K8sResourceProvider)
+		return parsedData, values, err
+	}
+}
+
+// requiredLabel is a label key required by require-labels and the regular expression its value
+// must match, if any.
+type requiredLabel struct {
+	key          string
+	valuePattern *regexp.Regexp
+}
+
+// splitLabelSpecs splits the labels argument of require-labels at the commas that separate label
+// specs. Commas within the groups, repetition counts, and character classes of regular
+// expressions, such as in ^[a-z]{1,3}$, and commas escaped with a backslash don't separate specs.
+func splitLabelSpecs(labels string) []string {
+	specs := []string{}
+	start, depth, inClass := 0, 0, false
+	for i := 0; i < len(labels); i++ {
+		switch c := labels[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+		case c == '(' || c == '{':
+			depth++
+		case (c == ')' || c == '}') && depth > 0:
+			depth--
+		case c == ',' && depth == 0:
+			specs = append(specs, labels[start:i])
+			start = i + 1
+		}
+	}
+	return append(specs, labels[start:])
+}
+
+func parseRequiredLabels(labels string) ([]requiredLabel, error) {
+	requiredLabels := []requiredLabel{}
+	for _, labelSpec := range splitLabelSpecs(labels) {
+		labelSpec = strings.TrimSpace(labelSpec)
+		if labelSpec == "" {
+			continue
+		}
+		key, pattern, hasPattern := strings.Cut(labelSpec, "=")
+		label := requiredLabel{key: strings.TrimSpace(key)}
+		if label.key == "" {
+			return nil, fmt.Errorf("label key must not be empty in %s", labelSpec)
+		}
+		if hasPattern {
+			valuePattern, err := regexp.Compile("^(?:" + pattern + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression for label %s: %w", label.key, err)
+			}
+			label.valuePattern = valuePattern
+		}
+		requiredLabels = append(requiredLabels, label)
+	}
+	if len(requiredLabels) == 0 {
+		return nil, fmt.Errorf("no labels specified")
+	}
+	return requiredLabels, nil
+}
+
+func k8sFnRequireLabels(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	requiredLabels, err := parseRequiredLabels(args[0].Value.(string))
+	if err != nil {
+		return parsedData, api.ValidationResultFalse, err
+	}
+	resourceType := api.ResourceTypeAny
+	exemptTypes := map[api.ResourceType]struct{}{}
+	for _, arg := range args[1:] {
+		switch arg.ParameterName {
+		case "resource-type":
+			resourceType = api.ResourceType(arg.Value.(string))
+		case "exempt-types":
+			for _, exemptType := range strings.Split(arg.Value.(string), ",") {
+				if exemptType = strings.TrimSpace(exemptType); exemptType != "" {
+					exemptTypes[api.ResourceType(exemptType)] = struct{}{}
+				}
+			}
+		}
+	}
+
+	details := []string{}
+	failedAttributes := api.AttributeValueList{}
+	for _, doc := range parsedData {
+		resourceInfo, err := yamlkit.GetResourceInfo(doc, k8skit.K8sResourceProvider)
+		if err != nil {
+			return parsedData, api.ValidationResultFalse, err
+		}
+		if resourceType != api.ResourceTypeAny && resourceInfo.ResourceType != resourceType {
+			continue
+		}
+		if _, exempt := exemptTypes[resourceInfo.ResourceType]; exempt {
+			continue
+		}
+		for _, label := range requiredLabels {
+			labelPath := api.ResolvedPath("metadata.labels." + yamlkit.EscapeDotsInPathSegment(label.key))
+			value, found, err := yamlkit.YamlSafePathGetValueAnyType(doc, labelPath, true)
+			if err != nil {
+				return parsedData, api.ValidationResultFalse, err
+			}
+			valueString := fmt.Sprint(value)
+			if found && (label.valuePattern == nil || label.valuePattern.MatchString(valueString)) {
+				continue
+			}
+			if found {
+				details = append(details, fmt.Sprintf("%s %s: label %s value %s doesn't match %s", resourceInfo.ResourceType, resourceInfo.ResourceName, label.key, valueString, label.valuePattern))
+			} else {
+				details = append(details, fmt.Sprintf("%s %s: missing label %s", resourceInfo.ResourceType, resourceInfo.ResourceName, label.key))
+			}
+			failedAttributes = append(failedAttributes, api.AttributeValue{
+				AttributeInfo: api.AttributeInfo{
+					AttributeIdentifier: api.AttributeIdentifier{ResourceInfo: *resourceInfo, Path: labelPath},
+					AttributeMetadata:   api.AttributeMetadata{AttributeName: AttributeNameLabelValue, DataType: api.DataTypeString},
+				},
+				Value: value,
+			})
+		}
+	}
+
+	if len(details) == 0 {
+		return parsedData, api.ValidationResultTrue, nil
+	}
+	failedResult := api.ValidationResultFalse
+	failedResult.Details = api.ValidationDetailsWithSeverity(details, api.ValidationSeverityFail)
+	failedResult.FailedAttributes = failedAttributes
+	return parsedData, failedResult, nil
+}
+
+func k8sFnSetResourceScope(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	// The argument value types should be verified before this function is called
+	targetResourceType := api.ResourceType(args[0].Value.(string))
+	targetResourceName := api.ResourceName(args[1].Value.(string))
+	newScope := args[2].Value.(string)
+	if _, isClusterScoped := k8skit.K8sClusterScopedResourceTypes[targetResourceType]; isClusterScoped {
+		return parsedData, nil, fmt.Errorf("resources of type %s are cluster-scoped", targetResourceType)
+	}
+
+	// An exact match of a scoped name takes precedence over matches of the name without the scope
+	resourceProvider := k8skit.K8sResourceProvider
+	scopelessTargetName := targetResourceName
+	if strings.Contains(string(targetResourceName), "/") {
+		scopelessTargetName = resourceProvider.RemoveScopeFromResourceName(targetResourceName)
+	}
+	var exactMatch *gaby.YamlDoc
+	scopelessMatches := []*gaby.YamlDoc{}
+	for _, doc := range parsedData {
+		resourceType, err := resourceProvider.ResourceTypeGetter(doc)
+		if err != nil || resourceType != targetResourceType {
+			continue
+		}
+		resourceName, err := resourceProvider.ResourceNameGetter(doc)
+		if err != nil {
+			return parsedData, nil, err
+		}
+		if resourceName == targetResourceName {
+			exactMatch = doc
+			break
+		}
+		if resourceProvider.RemoveScopeFromResourceName(resourceName) == scopelessTargetName {
+			scopelessMatches = append(scopelessMatches, doc)
+		}
+	}
+	foundDoc := exactMatch
+	if foundDoc == nil {
+		switch len(scopelessMatches) {
+		case 0:
+			return parsedData, nil, fmt.Errorf("resource with type %s and name %s not found", targetResourceType, targetResourceName)
+		case 1:
+			foundDoc = scopelessMatches[0]
+		default:
+			return parsedData, nil, fmt.Errorf("%d resources with type %s are named %s; specify the namespace", len(scopelessMatches), targetResourceType, scopelessTargetName)
+		}
+	}
+
+	namespace, _, err := yamlkit.YamlSafePathGetValue[string](foundDoc, "metadata.namespace", true)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	if namespace != newScope {
+		if _, err := foundDoc.SetP(newScope, "metadata.namespace"); err != nil {
+			return parsedData, nil, err
+		}
+	}
+	resourceName, err := resourceProvider.ResourceNameGetter(foundDoc)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	resourceInfo := api.ResourceInfo{
+		ResourceName:             resourceName,
+		ResourceNameWithoutScope: resourceProvider.RemoveScopeFromResourceName(resourceName),
+		ResourceType:             targetResourceType,
+		ResourceCategory:         api.ResourceCategoryResource,
+	}
+	return parsedData, api.ResourceInfoList{resourceInfo}, nil
+}
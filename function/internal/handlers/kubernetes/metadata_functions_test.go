@@ -0,0 +1,284 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const metadataMapFixture = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+    tier: frontend
+  annotations:
+    example.com/owner: alice
+spec:
+  replicas: 2
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`
+
+func runMetadataMapFunction(t *testing.T, docs gaby.Container, functionName string, stringArgs ...string) gaby.Container {
+	parameterNames := []string{"resource-type", "key", "value"}
+	args := []api.FunctionArgument{}
+	for i, arg := range stringArgs {
+		args = append(args, api.FunctionArgument{ParameterName: parameterNames[i], Value: arg})
+	}
+	registration := testHandler.ListCore()[functionName]
+	require.NotNil(t, registration, functionName)
+	result, _, err := registration.Function(&fakeContext, docs, args, []byte{})
+	require.NoError(t, err)
+	return result
+}
+
+func TestSetLabels(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(metadataMapFixture))
+	require.NoError(t, err)
+
+	// Add a label to all resources, including those without labels
+	docs = runMetadataMapFunction(t, docs, "set-labels", "*", "app.kubernetes.io/part-of", "checkout")
+	assert.Equal(t, "checkout", docs[0].Path("metadata.labels.app~1kubernetes~1io/part-of").Data())
+	assert.Equal(t, "checkout", docs[1].Path("metadata.labels.app~1kubernetes~1io/part-of").Data())
+	assert.Equal(t, "web", docs[0].Path("metadata.labels.app").Data())
+
+	// Update a label only in resources of the specified type
+	docs = runMetadataMapFunction(t, docs, "set-labels", "apps/v1/Deployment", "tier", "backend")
+	assert.Equal(t, "backend", docs[0].Path("metadata.labels.tier").Data())
+	assert.False(t, docs[1].Exists("metadata", "labels", "tier"))
+
+	// Setting the same value again is a no-op
+	before := docs.String()
+	docs = runMetadataMapFunction(t, docs, "set-labels", "apps/v1/Deployment", "tier", "backend")
+	assert.Equal(t, before, docs.String())
+	docs = runMetadataMapFunction(t, docs, "set-labels", "*", "tier", "backend")
+	assert.Equal(t, "backend", docs[1].Path("metadata.labels.tier").Data())
+
+	// An empty value deletes the label
+	docs = runMetadataMapFunction(t, docs, "set-labels", "*", "tier", "")
+	assert.False(t, docs[0].Exists("metadata", "labels", "tier"))
+	assert.False(t, docs[1].Exists("metadata", "labels", "tier"))
+	assert.Equal(t, "web", docs[0].Path("metadata.labels.app").Data())
+}
+
+func TestRemoveAnnotations(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(metadataMapFixture))
+	require.NoError(t, err)
+
+	docs = runMetadataMapFunction(t, docs, "set-annotations", "v1/Service", "example.com/owner", "bob")
+	assert.Equal(t, "alice", docs[0].Path("metadata.annotations.example~1com/owner").Data())
+	assert.Equal(t, "bob", docs[1].Path("metadata.annotations.example~1com/owner").Data())
+
+	docs = runMetadataMapFunction(t, docs, "remove-annotations", "apps/v1/Deployment", "example.com/owner")
+	assert.False(t, docs[0].Exists("metadata", "annotations", "example.com/owner"))
+	assert.Equal(t, "bob", docs[1].Path("metadata.annotations.example~1com/owner").Data())
+
+	// Removing an absent annotation is a no-op
+	before := docs.String()
+	docs = runMetadataMapFunction(t, docs, "remove-annotations", "*", "missing")
+	assert.Equal(t, before, docs.String())
+}
+
+func TestGetLabels(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(metadataMapFixture))
+	require.NoError(t, err)
+
+	registration := testHandler.ListCore()["get-labels"]
+	require.NotNil(t, registration)
+	_, output, err := registration.Function(&fakeContext, docs, []api.FunctionArgument{{ParameterName: "resource-type", Value: "*"}}, []byte{})
+	require.NoError(t, err)
+	values, ok := output.(api.AttributeValueList)
+	require.True(t, ok)
+	labels := map[api.ResolvedPath]any{}
+	for _, value := range values {
+		assert.Equal(t, api.ResourceType("apps/v1/Deployment"), value.ResourceType)
+		assert.Equal(t, AttributeNameLabelValue, value.AttributeName)
+		labels[value.Path] = value.Value
+	}
+	assert.Equal(t, map[api.ResolvedPath]any{"metadata.labels.app": "web", "metadata.labels.tier": "frontend"}, labels)
+}
+
+const resourceScopeFixture = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: staging
+data:
+  LOG_LEVEL: info
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: staging
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1.0
+        envFrom:
+        - configMapRef:
+            name: app-config
+---
+apiVersion: v1
+kind: ClusterRole
+metadata:
+  name: app-config
+`
+
+func TestSetResourceScope(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(resourceScopeFixture))
+	require.NoError(t, err)
+	setScope := func(resourceType, resourceName, newScope string) (any, error) {
+		args := []api.FunctionArgument{
+			{ParameterName: "resource-type", Value: resourceType},
+			{ParameterName: "resource-name", Value: resourceName},
+			{ParameterName: "new-scope", Value: newScope},
+		}
+		_, output, err := k8sFnSetResourceScope(&fakeContext, docs, args, []byte{})
+		return output, err
+	}
+
+	output, err := setScope("v1/ConfigMap", "staging/app-config", "production")
+	require.NoError(t, err)
+	expected := api.ResourceInfoList{{
+		ResourceName:             "production/app-config",
+		ResourceNameWithoutScope: "app-config",
+		ResourceType:             "v1/ConfigMap",
+		ResourceCategory:         api.ResourceCategoryResource,
+	}}
+	assert.Equal(t, expected, output)
+	assert.Equal(t, "production", docs[0].Path("metadata.namespace").Data())
+	assert.Equal(t, "app-config", docs[0].Path("metadata.name").Data())
+	// Only the specified resource is re-scoped
+	assert.Equal(t, "staging", docs[1].Path("metadata.namespace").Data())
+
+	// Re-scoping again, by the name without the scope, doesn't change anything
+	before := docs.String()
+	output, err = setScope("v1/ConfigMap", "app-config", "production")
+	require.NoError(t, err)
+	assert.Equal(t, expected, output)
+	assert.Equal(t, before, docs.String())
+
+	// After moving the Deployment too, its reference resolves to the ConfigMap in the same namespace
+	_, err = setScope("apps/v1/Deployment", "staging/app", "production")
+	require.NoError(t, err)
+	deploymentName, err := k8skit.K8sResourceProvider.ResourceNameGetter(docs[1])
+	require.NoError(t, err)
+	referencedName := docs[1].Path("spec.template.spec.containers.0.envFrom.0.configMapRef.name").Data().(string)
+	configMapName, err := k8skit.K8sResourceProvider.ResourceNameGetter(docs[0])
+	require.NoError(t, err)
+	assert.Equal(t, api.ResourceName("production/app"), deploymentName)
+	assert.Equal(t, configMapName, api.ResourceName("production/"+referencedName))
+
+	_, err = setScope("v1/ConfigMap", "missing", "production")
+	assert.ErrorContains(t, err, "not found")
+	_, err = setScope("rbac.authorization.k8s.io/v1/ClusterRole", "app-config", "production")
+	assert.ErrorContains(t, err, "cluster-scoped")
+}
+
+const requireLabelsFixture = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: shop
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+  labels:
+    app.kubernetes.io/name: web
+    env: production
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: shop
+  labels:
+    app.kubernetes.io/name: web
+    env: prod
+`
+
+func runRequireLabels(t *testing.T, args ...api.FunctionArgument) api.ValidationResult {
+	docs, err := gaby.ParseAll([]byte(requireLabelsFixture))
+	require.NoError(t, err)
+	registration := testHandler.ListCore()["require-labels"]
+	require.NotNil(t, registration)
+	_, output, err := registration.Function(&fakeContext, docs, args, []byte{})
+	require.NoError(t, err)
+	result, ok := output.(api.ValidationResult)
+	require.True(t, ok)
+	return result
+}
+
+func TestRequireLabels(t *testing.T) {
+	labels := api.FunctionArgument{ParameterName: "labels", Value: "app.kubernetes.io/name, env=dev|staging|prod"}
+	exemptNamespaces := api.FunctionArgument{ParameterName: "exempt-types", Value: "v1/Namespace"}
+
+	// The Namespace is missing both labels, and the Deployment's env value doesn't match the whole regexp
+	result := runRequireLabels(t, labels)
+	assert.False(t, result.Passed)
+	assert.Equal(t, []string{
+		"v1/Namespace /shop: missing label app.kubernetes.io/name",
+		"v1/Namespace /shop: missing label env",
+		"apps/v1/Deployment shop/web: label env value production doesn't match ^(?:dev|staging|prod)$",
+	}, result.DetailMessages())
+	require.Len(t, result.FailedAttributes, 3)
+	assert.Equal(t, api.ResolvedPath("metadata.labels.app~1kubernetes~1io/name"), result.FailedAttributes[0].Path)
+	assert.Equal(t, "production", result.FailedAttributes[2].Value)
+
+	result = runRequireLabels(t, labels, exemptNamespaces)
+	assert.False(t, result.Passed)
+	assert.Len(t, result.Details, 1)
+
+	// Only Services are checked
+	result = runRequireLabels(t, labels, api.FunctionArgument{ParameterName: "resource-type", Value: "v1/Service"})
+	assert.True(t, result.Passed)
+
+	result = runRequireLabels(t, api.FunctionArgument{ParameterName: "labels", Value: "app.kubernetes.io/name"}, exemptNamespaces)
+	assert.True(t, result.Passed)
+
+	// Commas within the regular expressions don't separate labels
+	result = runRequireLabels(t, api.FunctionArgument{ParameterName: "labels", Value: "env=^[a-z]{1,4}$,app.kubernetes.io/name"}, exemptNamespaces)
+	assert.False(t, result.Passed)
+	assert.Equal(t, []string{
+		"apps/v1/Deployment shop/web: label env value production doesn't match ^(?:^[a-z]{1,4}$)$",
+	}, result.DetailMessages())
+}
+
+func TestSplitLabelSpecs(t *testing.T) {
+	assert.Equal(t, []string{"team", "env=^[a-z]{1,3}$", "tier=(web|api|a,b)", "name=[,x]", `note=a\,b`, ""},
+		splitLabelSpecs(`team,env=^[a-z]{1,3}$,tier=(web|api|a,b),name=[,x],note=a\,b,`))
+}
+
+func TestRequireLabelsInvalidArguments(t *testing.T) {
+	registration := testHandler.ListCore()["require-labels"]
+	require.NotNil(t, registration)
+	for labels, expected := range map[string]string{
+		" , ":   "no labels specified",
+		"=prod": "label key must not be empty in =prod",
+		"env=(": "invalid regular expression for label env",
+	} {
+		_, _, err := registration.Function(&fakeContext, gaby.Container{}, []api.FunctionArgument{{ParameterName: "labels", Value: labels}}, []byte{})
+		assert.ErrorContains(t, err, expected)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const resetAllFixture = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+  namespace: prod
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: backend
+        image: backend:1.0
+        imagePullPolicy: Always
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: backend-config
+  namespace: prod
+data:
+  key: value
+`
+
+func runResetAll(t *testing.T, resourceType string) gaby.Container {
+	docs, err := gaby.ParseAll([]byte(resetAllFixture))
+	assert.NoError(t, err)
+	registration := testHandler.ListCore()["reset-all"]
+	docs, _, err = registration.Function(&fakeContext, docs, []api.FunctionArgument{{ParameterName: "resource-type", Value: resourceType}}, []byte{})
+	assert.NoError(t, err)
+	return docs
+}
+
+func TestResetAll(t *testing.T) {
+	docs := runResetAll(t, "*")
+	deployment := docs[0]
+	assert.Equal(t, "confighubplaceholder", deployment.Path("metadata.name").Data())
+	assert.Equal(t, "confighubplaceholder", deployment.Path("metadata.namespace").Data())
+	assert.Equal(t, 999999999, deployment.Path("spec.replicas").Data())
+	assert.Equal(t, "confighubplaceholder", deployment.Path("spec.template.spec.containers.0.image").Data())
+	// Paths that aren't registered are unchanged
+	assert.Equal(t, "Always", deployment.Path("spec.template.spec.containers.0.imagePullPolicy").Data())
+	assert.Equal(t, "apps/v1", deployment.Path("apiVersion").Data())
+	configMap := docs[1]
+	assert.Equal(t, "confighubplaceholder", configMap.Path("metadata.name").Data())
+	assert.Equal(t, "value", configMap.Path("data.key").Data())
+}
+
+func TestResetAll_ResourceType(t *testing.T) {
+	docs := runResetAll(t, "v1/ConfigMap")
+	deployment := docs[0]
+	assert.Equal(t, "backend", deployment.Path("metadata.name").Data())
+	assert.Equal(t, "prod", deployment.Path("metadata.namespace").Data())
+	assert.Equal(t, 3, deployment.Path("spec.replicas").Data())
+	assert.Equal(t, "backend:1.0", deployment.Path("spec.template.spec.containers.0.image").Data())
+	configMap := docs[1]
+	assert.Equal(t, "confighubplaceholder", configMap.Path("metadata.name").Data())
+	assert.Equal(t, "confighubplaceholder", configMap.Path("metadata.namespace").Data())
+	assert.Equal(t, "value", configMap.Path("data.key").Data())
+
+	// Resetting again doesn't change anything
+	expected := docs.String()
+	registration := testHandler.ListCore()["reset-all"]
+	docs, _, err := registration.Function(&fakeContext, docs, []api.FunctionArgument{{ParameterName: "resource-type", Value: "v1/ConfigMap"}}, []byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, docs.String())
+}
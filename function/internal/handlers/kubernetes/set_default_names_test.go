@@ -0,0 +1,60 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const setDefaultNamesFixture = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: confighubplaceholder
+`
+
+func runSetDefaultNames(t *testing.T, args []api.FunctionArgument) (gaby.Container, error) {
+	docs, err := gaby.ParseAll([]byte(setDefaultNamesFixture))
+	assert.NoError(t, err)
+	functionContext := &api.FunctionContext{
+		UnitSlug:  "my-unit",
+		SpaceSlug: "my-space",
+	}
+	registration := testHandler.ListCore()["set-default-names"]
+	docs, _, err = registration.Function(functionContext, docs, args, []byte{})
+	return docs, err
+}
+
+func TestSetDefaultNames_DefaultTemplate(t *testing.T) {
+	docs, err := runSetDefaultNames(t, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "my-unit"+k8skit.K8sResourceProvider.NameSeparator()+"my-space", docs[0].Path("metadata.name").Data())
+
+	docs, err = runSetDefaultNames(t, stringArgsToFunctionArgs([]string{""}))
+	assert.NoError(t, err)
+	assert.Equal(t, "my-unit"+k8skit.K8sResourceProvider.NameSeparator()+"my-space", docs[0].Path("metadata.name").Data())
+}
+
+func TestSetDefaultNames_CustomTemplate(t *testing.T) {
+	args := stringArgsToFunctionArgs([]string{"{{.NormalizedSpaceName}}-{{.NormalizedUnitName | toUpper | toLower}}-ns"})
+	docs, err := runSetDefaultNames(t, args)
+	assert.NoError(t, err)
+	assert.Equal(t, "my-space-my-unit-ns", docs[0].Path("metadata.name").Data())
+}
+
+func TestSetDefaultNames_InvalidTemplate(t *testing.T) {
+	docs, err := runSetDefaultNames(t, stringArgsToFunctionArgs([]string{"{{.NormalizedClusterName}}"}))
+	assert.ErrorContains(t, err, "invalid name template")
+	assert.ErrorContains(t, err, "NormalizedClusterName")
+	// Nothing should have been changed
+	assert.Equal(t, "confighubplaceholder", docs[0].Path("metadata.name").Data())
+
+	_, err = runSetDefaultNames(t, stringArgsToFunctionArgs([]string{"{{.NormalizedUnitName"}))
+	assert.ErrorContains(t, err, "invalid name template")
+}
@@ -6,6 +6,7 @@ package kubernetes
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/cockroachdb/errors"
@@ -26,8 +27,10 @@ import (
 	kustomizeexcerpts "github.com/confighub/sdk/third_party/kustomize"
 )
 
-func registerStandardFunctions(fh handler.FunctionRegistry) {
-	generic.RegisterStandardFunctions(fh, k8skit.K8sResourceProvider, k8skit.K8sResourceProvider)
+func registerStandardFunctions(fh handler.FunctionRegistry) error {
+	if err := generic.RegisterStandardFunctions(fh, k8skit.K8sResourceProvider, k8skit.K8sResourceProvider); err != nil {
+		return err
+	}
 
 	// Override some functions with extended implementations
 	fh.RegisterFunction("get-placeholders", &handler.FunctionRegistration{
@@ -118,6 +121,25 @@ func registerStandardFunctions(fh handler.FunctionRegistry) {
 		},
 		Function: k8sFnValidate,
 	})
+	fh.RegisterFunction("validate-resource-names", &handler.FunctionRegistration{
+		FunctionSignature: api.FunctionSignature{
+			FunctionName: "validate-resource-names",
+			OutputInfo: &api.FunctionOutput{
+				ResultName:  "passed",
+				Description: "True if all resource names are valid DNS-1123 labels, false otherwise",
+				OutputType:  api.OutputTypeValidationResult,
+			},
+			Mutating:              false,
+			Validating:            true,
+			Hermetic:              true,
+			Idempotent:            true,
+			Description:           "Returns true if the names of all resources are valid RFC 1123 DNS labels: at most 63 lowercase alphanumeric characters or '-', starting and ending with an alphanumeric character",
+			FunctionType:          api.FunctionTypeCustom,
+			AffectedResourceTypes: []api.ResourceType{api.ResourceTypeAny},
+		},
+		Function: k8sFnValidateResourceNames,
+	})
+	return nil
 }
 
 var noncoreDefaultGroup = map[string]string{
@@ -218,7 +240,7 @@ func initStandardFunctions() {
 				FunctionName: "get-resources-of-type",
 				Arguments:    []api.FunctionArgument{{ParameterName: "resource-type", Value: nbrgvk}},
 			}
-			yamlkit.RegisterProvidedPaths(k8skit.K8sResourceProvider, nbrgvk, pathInfos, getterFunctionInvocation)
+			yamlkit.MustRegisterProvidedPaths(k8skit.K8sResourceProvider, nbrgvk, pathInfos, getterFunctionInvocation)
 			for _, field := range nbr.Referrers {
 				gvk := gvkString(field.Gvk)
 				// This is kind of hacky in lieu of actual schemas. Kustomize always searches arrays.
@@ -245,8 +267,8 @@ func initStandardFunctions() {
 					FunctionName: "set-references-of-type",
 					Arguments:    []api.FunctionArgument{{ParameterName: "resource-type", Value: nbrgvk}},
 				}
-				yamlkit.RegisterNeededPaths(k8skit.K8sResourceProvider, gvk, pathInfos, setterFunctionInvocation)
-				yamlkit.RegisterPathsByAttributeName(
+				yamlkit.MustRegisterNeededPaths(k8skit.K8sResourceProvider, gvk, pathInfos, setterFunctionInvocation)
+				yamlkit.MustRegisterPathsByAttributeName(
 					k8skit.K8sResourceProvider,
 					attributeName,
 					gvk,
@@ -296,7 +318,7 @@ func initStandardFunctions() {
 		FunctionName: "set-default-names",
 	}
 	for resourceType, pathInfos := range defaultNames {
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			k8skit.K8sResourceProvider,
 			api.AttributeNameDefaultName,
 			resourceType,
@@ -305,7 +327,7 @@ func initStandardFunctions() {
 			setterFunctionInvocation,
 			false,
 		)
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			k8skit.K8sResourceProvider,
 			api.AttributeNameGeneral,
 			resourceType,
@@ -327,7 +349,7 @@ func initStandardFunctions() {
 		},
 	}
 	for resourceType, pathInfos := range attributePaths {
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			k8skit.K8sResourceProvider,
 			api.AttributeNameGeneral,
 			resourceType,
@@ -499,7 +521,7 @@ func initStandardFunctions() {
 	}
 	for resourceType, pathInfos := range detailPaths {
 		addDescriptionToPathInfos(resourceType, pathInfos)
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			k8skit.K8sResourceProvider,
 			api.AttributeNameDetail,
 			resourceType,
@@ -553,13 +575,10 @@ func k8sFnNoPlaceholders(_ *api.FunctionContext, parsedData gaby.Container, _ []
 	paths = append(paths, yamlkit.FindYAMLPathsByValue(parsedData, k8skit.K8sResourceProvider, yamlkit.PlaceHolderBlockApplyInt)...)
 	// OriginalName annotations can contain confighubplaceholder values for namespaces and/or names.
 	// Ignore those. They aren't a problem for apply.
-	filteredPaths := make(api.AttributeValueList, 0, len(paths))
-	for _, pathValue := range paths {
+	filteredPaths := paths.Filter(func(pathValue api.AttributeValue) bool {
 		// There may be one of these for each resource in the unit. Remove them all.
-		if string(pathValue.Path) != originalNamePath {
-			filteredPaths = append(filteredPaths, pathValue)
-		}
-	}
+		return string(pathValue.Path) != originalNamePath
+	})
 	result := api.ValidationResult{
 		Passed: len(filteredPaths) == 0,
 	}
@@ -635,6 +654,65 @@ func k8sFnResourceWhereMatch(functionContext *api.FunctionContext, parsedData ga
 	return generic.GenericFnResourceWhereMatchWithComparators(k8skit.K8sResourceProvider, customComparators, functionContext, parsedData, args, liveState)
 }
 
+var dns1123LabelRegexp = regexp.MustCompile(convertToFullRegexp(dns1123LabelRegexpString))
+
+const dns1123LabelMaxLength = 63
+
+func k8sFnValidateResourceNames(_ *api.FunctionContext, parsedData gaby.Container, _ []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
+	details := []string{}
+	for _, doc := range parsedData {
+		resourceType, err := k8skit.K8sResourceProvider.ResourceTypeGetter(doc)
+		if err != nil {
+			return parsedData, api.ValidationResultFalse, err
+		}
+		name, _, err := yamlkit.YamlSafePathGetValue[string](doc, k8skit.K8sResourceProvider.ScopelessResourceNamePath(), true)
+		if err != nil {
+			return parsedData, api.ValidationResultFalse, err
+		}
+		if violation := dns1123LabelViolation(name); violation != "" {
+			details = append(details, fmt.Sprintf("%s %q: %s", resourceType, name, violation))
+		}
+	}
+
+	if len(details) == 0 {
+		return parsedData, api.ValidationResultTrue, nil
+	}
+	failedResult := api.ValidationResultFalse
+	failedResult.Details = api.ValidationDetailsWithSeverity(details, api.ValidationSeverityFail)
+	return parsedData, failedResult, nil
+}
+
+// dns1123LabelViolation returns a description of why name isn't a valid DNS-1123 label, or the
+// empty string if it is valid.
+func dns1123LabelViolation(name string) string {
+	if dns1123LabelRegexp.MatchString(name) {
+		return ""
+	}
+	if name == "" {
+		return "name is empty"
+	}
+	var invalidChars []string
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			quoted := fmt.Sprintf("%q", c)
+			if !slices.Contains(invalidChars, quoted) {
+				invalidChars = append(invalidChars, quoted)
+			}
+		}
+	}
+	var violations []string
+	if len(invalidChars) > 0 {
+		violations = append(violations, "contains invalid characters "+strings.Join(invalidChars, ", ")+"; only lowercase alphanumeric characters and '-' are allowed")
+	}
+	if strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-") {
+		violations = append(violations, "must start and end with an alphanumeric character")
+	}
+	if len(name) > dns1123LabelMaxLength {
+		violations = append(violations, fmt.Sprintf("is %d characters long, more than the maximum of %d", len(name), dns1123LabelMaxLength))
+	}
+	return strings.Join(violations, "; ")
+}
+
 func k8sFnValidate(_ *api.FunctionContext, parsedData gaby.Container, args []api.FunctionArgument, _ []byte) (gaby.Container, any, error) {
 	// TODO: Get CRD schemas
 	v, err := validator.New(nil, validator.Opts{Strict: true, IgnoreMissingSchemas: true})
@@ -668,7 +746,7 @@ func k8sFnValidate(_ *api.FunctionContext, parsedData gaby.Container, args []api
 	}
 
 	failureResult := api.ValidationResultFalse
-	failureResult.Details = details
+	failureResult.Details = api.ValidationDetailsWithSeverity(details, api.ValidationSeverityFail)
 
 	return parsedData, failureResult, join.Join(multiErrs...)
 }
@@ -7,11 +7,21 @@ import (
 	"os"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/configkit/k8skit"
+	"github.com/confighub/sdk/configkit/yamlkit"
 	"github.com/confighub/sdk/function/handler"
 )
 
+var testHandler *handler.FunctionHandler
+
 func TestMain(m *testing.M) {
-	kc := handler.NewFunctionHandler()
-	KubernetesRegistrar.RegisterFunctions(kc)
+	testHandler = handler.NewFunctionHandler()
+	KubernetesRegistrar.RegisterFunctions(testHandler)
 	os.Exit(m.Run())
 }
+
+func TestPathRegistryHasNoConflicts(t *testing.T) {
+	assert.Empty(t, yamlkit.ValidateRegistry(k8skit.K8sResourceProvider))
+}
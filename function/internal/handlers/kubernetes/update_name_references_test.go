@@ -0,0 +1,79 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const updateNameReferencesFixture = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: deployer
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: deployer-binding
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: deployer
+subjects:
+- kind: ServiceAccount
+  name: deployer
+- kind: ServiceAccount
+  name: other
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      serviceAccountName: deployer
+`
+
+func runUpdateNameReferences(t *testing.T, docs gaby.Container, args []string) (gaby.Container, error) {
+	registration := testHandler.ListCore()["update-name-references"]
+	docs, _, err := registration.Function(&fakeContext, docs, stringArgsToFunctionArgs(args), []byte{})
+	return docs, err
+}
+
+func TestUpdateNameReferences(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(updateNameReferencesFixture))
+	assert.NoError(t, err)
+	args := []string{"v1/ServiceAccount", `{"deployer": "prod-deployer"}`}
+
+	docs, err = runUpdateNameReferences(t, docs, args)
+	assert.NoError(t, err)
+	assert.Equal(t, "prod-deployer", docs[1].Path("subjects.0.name").Data())
+	assert.Equal(t, "other", docs[1].Path("subjects.1.name").Data())
+	assert.Equal(t, "prod-deployer", docs[2].Path("spec.template.spec.serviceAccountName").Data())
+	// Only references to ServiceAccounts are updated, not the resources themselves
+	assert.Equal(t, "deployer", docs[0].Path("metadata.name").Data())
+	assert.Equal(t, "deployer", docs[1].Path("roleRef.name").Data())
+
+	// Updating again doesn't change anything
+	updated := docs.String()
+	docs, err = runUpdateNameReferences(t, docs, args)
+	assert.NoError(t, err)
+	assert.Equal(t, updated, docs.String())
+}
+
+func TestUpdateNameReferences_InvalidMapping(t *testing.T) {
+	docs, err := gaby.ParseAll([]byte(updateNameReferencesFixture))
+	assert.NoError(t, err)
+
+	_, err = runUpdateNameReferences(t, docs, []string{"v1/ServiceAccount", `["deployer"]`})
+	assert.ErrorContains(t, err, "name-mapping must be a JSON object")
+	_, err = runUpdateNameReferences(t, docs, []string{"v1/ServiceAccount", `{"a": "b", "b": "c"}`})
+	assert.ErrorContains(t, err, "new name b is also renamed")
+	assert.Equal(t, "deployer", docs[1].Path("subjects.0.name").Data())
+}
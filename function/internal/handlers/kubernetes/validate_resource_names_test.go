@@ -0,0 +1,71 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+func runValidateResourceNames(t *testing.T, yaml string) api.ValidationResult {
+	docs, err := gaby.ParseAll([]byte(yaml))
+	assert.NoError(t, err)
+	registration := testHandler.ListCore()["validate-resource-names"]
+	_, output, err := registration.Function(&fakeContext, docs, nil, []byte{})
+	assert.NoError(t, err)
+	result, ok := output.(api.ValidationResult)
+	assert.True(t, ok)
+	return result
+}
+
+func TestValidateResourceNames_Valid(t *testing.T) {
+	result := runValidateResourceNames(t, `apiVersion: v1
+kind: Namespace
+metadata:
+  name: my-unit-my-space
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-1
+  namespace: my-unit-my-space
+`)
+	assert.True(t, result.Passed)
+	assert.Empty(t, result.Details)
+}
+
+func TestValidateResourceNames_Invalid(t *testing.T) {
+	result := runValidateResourceNames(t, `apiVersion: v1
+kind: Namespace
+metadata:
+  name: My_Unit-my_space
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-
+  namespace: ok
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: `+strings.Repeat("a", 64)+`
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	assert.False(t, result.Passed)
+	assert.Equal(t, []string{
+		`v1/Namespace "My_Unit-my_space": contains invalid characters 'M', '_', 'U'; only lowercase alphanumeric characters and '-' are allowed`,
+		`apps/v1/Deployment "web-": must start and end with an alphanumeric character`,
+		`v1/ConfigMap "` + strings.Repeat("a", 64) + `": is 64 characters long, more than the maximum of 63`,
+	}, result.DetailMessages())
+}
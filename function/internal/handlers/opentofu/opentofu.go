@@ -18,7 +18,10 @@ var OpenTofuRegistrar = &OpenTofuRegistrarType{}
 
 func (r *OpenTofuRegistrarType) RegisterFunctions(fh handler.FunctionRegistry) {
 	initStandardFunctions()
-	registerStandardFunctions(fh)
+	if err := registerStandardFunctions(fh); err != nil {
+		// Conflicting path registrations are programming errors, so fail initialization
+		panic(err)
+	}
 	fh.SetConverter(hclkit.HclResourceProvider)
 }
 
@@ -11,8 +11,8 @@ import (
 	"github.com/confighub/sdk/function/handler"
 )
 
-func registerStandardFunctions(fh handler.FunctionRegistry) {
-	generic.RegisterStandardFunctions(fh, hclkit.HclResourceProvider, hclkit.HclResourceProvider)
+func registerStandardFunctions(fh handler.FunctionRegistry) error {
+	return generic.RegisterStandardFunctions(fh, hclkit.HclResourceProvider, hclkit.HclResourceProvider)
 }
 
 func initStandardFunctions() {
@@ -33,7 +33,7 @@ func initStandardFunctions() {
 		FunctionName: "set-default-names",
 	}
 	for resourceType, pathInfos := range defaultNames {
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			hclkit.HclResourceProvider,
 			api.AttributeNameDefaultName,
 			resourceType,
@@ -42,7 +42,7 @@ func initStandardFunctions() {
 			setterFunctionInvocation,
 			false,
 		)
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			hclkit.HclResourceProvider,
 			api.AttributeNameGeneral,
 			resourceType,
@@ -56,7 +56,7 @@ func initStandardFunctions() {
 	// TODO
 	var attributePaths = api.ResourceTypeToPathToVisitorInfoType{}
 	for resourceType, pathInfos := range attributePaths {
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			hclkit.HclResourceProvider,
 			api.AttributeNameGeneral,
 			resourceType,
@@ -70,7 +70,7 @@ func initStandardFunctions() {
 	// TODO
 	var detailPaths = api.ResourceTypeToPathToVisitorInfoType{}
 	for resourceType, pathInfos := range detailPaths {
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			hclkit.HclResourceProvider,
 			api.AttributeNameDetail,
 			resourceType,
@@ -16,7 +16,10 @@ var PropertiesRegistrar = &PropertiesRegistrarType{}
 
 func (r *PropertiesRegistrarType) RegisterFunctions(fh handler.FunctionRegistry) {
 	initStandardFunctions()
-	registerStandardFunctions(fh)
+	if err := registerStandardFunctions(fh); err != nil {
+		// Conflicting path registrations are programming errors, so fail initialization
+		panic(err)
+	}
 	fh.SetConverter(propkit.PropertiesResourceProvider)
 }
 
@@ -14,8 +14,10 @@ import (
 
 // TODO: refactor to share code that's common across ToolchainTypes
 
-func registerStandardFunctions(fh handler.FunctionRegistry) {
-	generic.RegisterStandardFunctions(fh, propkit.PropertiesResourceProvider, propkit.PropertiesResourceProvider)
+func registerStandardFunctions(fh handler.FunctionRegistry) error {
+	if err := generic.RegisterStandardFunctions(fh, propkit.PropertiesResourceProvider, propkit.PropertiesResourceProvider); err != nil {
+		return err
+	}
 	fh.RegisterFunction("validate", &handler.FunctionRegistration{
 		FunctionSignature: api.FunctionSignature{
 			FunctionName: "validate",
@@ -34,6 +36,7 @@ func registerStandardFunctions(fh handler.FunctionRegistry) {
 		},
 		Function: propFnValidate,
 	})
+	return nil
 }
 
 // This is also defined in the bridge.
@@ -57,7 +60,7 @@ func initStandardFunctions() {
 		FunctionName: "set-default-names",
 	}
 	for resourceType, pathInfos := range defaultNames {
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			propkit.PropertiesResourceProvider,
 			api.AttributeNameDefaultName,
 			resourceType,
@@ -66,7 +69,7 @@ func initStandardFunctions() {
 			setterFunctionInvocation,
 			false,
 		)
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			propkit.PropertiesResourceProvider,
 			api.AttributeNameGeneral,
 			resourceType,
@@ -80,7 +83,7 @@ func initStandardFunctions() {
 	// TODO
 	var attributePaths = api.ResourceTypeToPathToVisitorInfoType{}
 	for resourceType, pathInfos := range attributePaths {
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			propkit.PropertiesResourceProvider,
 			api.AttributeNameGeneral,
 			resourceType,
@@ -94,7 +97,7 @@ func initStandardFunctions() {
 	// TODO
 	var detailPaths = api.ResourceTypeToPathToVisitorInfoType{}
 	for resourceType, pathInfos := range detailPaths {
-		yamlkit.RegisterPathsByAttributeName(
+		yamlkit.MustRegisterPathsByAttributeName(
 			propkit.PropertiesResourceProvider,
 			api.AttributeNameDetail,
 			resourceType,
@@ -119,7 +122,7 @@ func initStandardFunctions() {
 		FunctionName: "set-references-of-type",
 		Arguments:    []api.FunctionArgument{{ParameterName: "resource-type", Value: "v1/Namespace"}},
 	}
-	yamlkit.RegisterNeededPaths(propkit.PropertiesResourceProvider, api.ResourceTypeAny, pathInfos, setterFunctionInvocation)
+	yamlkit.MustRegisterNeededPaths(propkit.PropertiesResourceProvider, api.ResourceTypeAny, pathInfos, setterFunctionInvocation)
 
 }
 
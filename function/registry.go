@@ -8,6 +8,8 @@ import (
 	// but for the worker which needs access across potential 'internal' boundaries,
 	// we centralize the registration calls here.
 
+	"github.com/confighub/sdk/function/internal/handlers/ansible"
+	"github.com/confighub/sdk/function/internal/handlers/appconfig"
 	"github.com/confighub/sdk/function/internal/handlers/kubernetes"
 	"github.com/confighub/sdk/function/internal/handlers/opentofu"
 	"github.com/confighub/sdk/function/internal/handlers/properties"
@@ -27,6 +29,16 @@ func RegisterProperties(fh *handler.FunctionHandler) {
 	properties.PropertiesRegistrar.RegisterFunctions(fh)
 }
 
+// RegisterAppConfigYAML registers AppConfig/YAML functions onto the provided FunctionHandler.
+func RegisterAppConfigYAML(fh *handler.FunctionHandler) {
+	appconfig.AppConfigYAMLRegistrar.RegisterFunctions(fh)
+}
+
+// RegisterAnsibleVars registers Ansible/Vars functions onto the provided FunctionHandler.
+func RegisterAnsibleVars(fh *handler.FunctionHandler) {
+	ansible.AnsibleVarsRegistrar.RegisterFunctions(fh)
+}
+
 // RegisterOpenTofu registers OpenTofu functions onto the provided FunctionHandler.
 func RegisterOpenTofu(fh *handler.FunctionHandler) {
 	opentofu.OpenTofuRegistrar.RegisterFunctions(fh)
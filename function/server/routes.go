@@ -8,10 +8,13 @@ import (
 	"os"
 	"syscall"
 
+	"github.com/confighub/sdk/function/internal/handlers/ansible"
+	"github.com/confighub/sdk/function/internal/handlers/appconfig"
 	"github.com/confighub/sdk/function/internal/handlers/kubernetes"
 	"github.com/confighub/sdk/function/internal/handlers/opentofu"
 	"github.com/confighub/sdk/function/internal/handlers/properties"
 	"github.com/confighub/sdk/function/handler"
+	"github.com/confighub/sdk/third_party/gaby"
 
 	"github.com/labstack/echo/v4"
 )
@@ -19,9 +22,19 @@ import (
 var kubernetesHandler *handler.FunctionHandler
 var propertiesHandler *handler.FunctionHandler
 var opentofuHandler *handler.FunctionHandler
+var appConfigYAMLHandler *handler.FunctionHandler
+var ansibleVarsHandler *handler.FunctionHandler
+
+// Limits on the configuration data accepted by the server, so that it doesn't process
+// arbitrarily large inputs.
+var serverParseOptions = gaby.ParseOptions{
+	MaxDocuments:         10000,
+	MaxDocumentSizeBytes: 4 * 1024 * 1024,
+}
 
 func registerFunctionHandler(parent *echo.Group, h **handler.FunctionHandler, p handler.FunctionProvider) {
 	*h = handler.NewFunctionHandler()
+	(*h).SetParseOptions(serverParseOptions)
 	p.RegisterFunctions(*h)
 	p.SetPathRegistry(*h)
 	group := parent.Group(p.GetToolchainPath())
@@ -36,6 +49,8 @@ func echoSetup(rootRouter *echo.Echo) {
 	registerFunctionHandler(apiRouter, &kubernetesHandler, kubernetes.KubernetesRegistrar)
 	registerFunctionHandler(apiRouter, &propertiesHandler, properties.PropertiesRegistrar)
 	registerFunctionHandler(apiRouter, &opentofuHandler, opentofu.OpenTofuRegistrar)
+	registerFunctionHandler(apiRouter, &appConfigYAMLHandler, appconfig.AppConfigYAMLRegistrar)
+	registerFunctionHandler(apiRouter, &ansibleVarsHandler, ansible.AnsibleVarsRegistrar)
 }
 
 func setupAPIRootAPI(apiRouter *echo.Group) {
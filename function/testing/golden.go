@@ -0,0 +1,72 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package testing
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	stdtesting "testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/workerapi"
+)
+
+// UpdateGoldenEnvVar is the environment variable that, when set to 1, causes GoldenFileTest to
+// write the golden files rather than compare against them.
+const UpdateGoldenEnvVar = "UPDATE_GOLDEN"
+
+const (
+	goldenInputFile     = "input.yaml"
+	goldenArgumentsFile = "args.json"
+	goldenOutputFile    = "output.yaml"
+)
+
+// GoldenFileTest runs the named function of the specified toolchain on the configuration data in
+// <dir>/input.yaml with the arguments in <dir>/args.json and compares the resulting configuration
+// data to the golden file <dir>/output.yaml. The arguments are a JSON array of FunctionArguments,
+// such as [{"ParameterName": "replicas", "Value": 3}]; args.json may be omitted if the function
+// takes no arguments. The function is invoked on a new unit test-unit in space test-space. Run
+// the tests with UPDATE_GOLDEN=1 to regenerate the golden files.
+func GoldenFileTest(t stdtesting.TB, dir string, functionName string, toolchain workerapi.ToolchainType) {
+	t.Helper()
+	h, err := NewToolchainTestHarness(toolchain)
+	require.NoError(t, err)
+
+	input, err := os.ReadFile(filepath.Join(dir, goldenInputFile))
+	require.NoError(t, err)
+	h.WithYAML(string(input)).WithContext(api.FunctionContext{
+		ToolchainType:   toolchain,
+		UnitDisplayName: "TestUnit",
+		UnitSlug:        "test-unit",
+		SpaceSlug:       "test-space",
+		New:             true,
+	})
+
+	argumentsJSON, err := os.ReadFile(filepath.Join(dir, goldenArgumentsFile))
+	if !errors.Is(err, fs.ErrNotExist) {
+		require.NoError(t, err)
+		var arguments []api.FunctionArgument
+		require.NoError(t, json.Unmarshal(argumentsJSON, &arguments), "parsing %s", goldenArgumentsFile)
+		h.arguments = arguments
+	}
+
+	configData, _, err := h.Run(functionName)
+	require.NoError(t, err)
+	output := configData.String()
+
+	outputPath := filepath.Join(dir, goldenOutputFile)
+	if os.Getenv(UpdateGoldenEnvVar) == "1" {
+		require.NoError(t, os.WriteFile(outputPath, []byte(output), 0o644))
+		return
+	}
+	golden, err := os.ReadFile(outputPath)
+	require.NoError(t, err, "run with %s=1 to create the golden file", UpdateGoldenEnvVar)
+	assert.Equal(t, string(golden), output, "output of %s differs from %s; run with %s=1 to update it", functionName, outputPath, UpdateGoldenEnvVar)
+}
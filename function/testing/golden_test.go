@@ -0,0 +1,24 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package testing
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/confighub/sdk/workerapi"
+)
+
+func TestGoldenFiles(t *testing.T) {
+	for _, functionName := range []string{
+		"set-default-names",
+		"replicate",
+		"upsert-resource",
+		"delete-resource",
+	} {
+		t.Run(functionName, func(t *testing.T) {
+			GoldenFileTest(t, filepath.Join("testdata", functionName), functionName, workerapi.ToolchainKubernetesYAML)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package testing provides helpers for testing functions registered with a function handler.
+package testing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	stdtesting "testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/function"
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/function/handler"
+	"github.com/confighub/sdk/third_party/gaby"
+	"github.com/confighub/sdk/workerapi"
+)
+
+// FunctionTestHarness invokes registered functions on configuration data for tests. The
+// configuration data, arguments, live state, and function context are specified using the
+// chainable With* methods. Arguments are validated and ordered as they would be by the function
+// executor, and string argument values are cast to the parameter data types. The results of the
+// most recent Run are retained for the Assert* helpers.
+type FunctionTestHarness struct {
+	handler         *handler.FunctionHandler
+	functionContext api.FunctionContext
+	yaml            string
+	liveState       []byte
+	arguments       []api.FunctionArgument
+
+	configData gaby.Container
+	output     any
+	err        error
+}
+
+// NewFunctionTestHarness returns a harness that invokes functions registered with the provided handler.
+func NewFunctionTestHarness(fh *handler.FunctionHandler) *FunctionTestHarness {
+	return &FunctionTestHarness{
+		handler: fh,
+		functionContext: api.FunctionContext{
+			UnitDisplayName: "TestUnit",
+			New:             true,
+		},
+	}
+}
+
+var registrators = map[workerapi.ToolchainType]func(*handler.FunctionHandler){
+	workerapi.ToolchainKubernetesYAML:      function.RegisterKubernetes,
+	workerapi.ToolchainOpenTofuHCL:         function.RegisterOpenTofu,
+	workerapi.ToolchainAppConfigProperties: function.RegisterProperties,
+	workerapi.ToolchainAppConfigYAML:       function.RegisterAppConfigYAML,
+}
+
+var toolchainHandlers = map[workerapi.ToolchainType]*handler.FunctionHandler{}
+var toolchainHandlersMutex sync.Mutex
+
+// NewToolchainTestHarness returns a harness that invokes the standard functions of the specified
+// toolchain. The functions are registered once per process.
+func NewToolchainTestHarness(toolchain workerapi.ToolchainType) (*FunctionTestHarness, error) {
+	toolchainHandlersMutex.Lock()
+	defer toolchainHandlersMutex.Unlock()
+	fh, ok := toolchainHandlers[toolchain]
+	if !ok {
+		register, ok := registrators[toolchain]
+		if !ok {
+			return nil, fmt.Errorf("no functions registered for toolchain %s", toolchain)
+		}
+		fh = handler.NewFunctionHandler()
+		register(fh)
+		toolchainHandlers[toolchain] = fh
+	}
+	return NewFunctionTestHarness(fh), nil
+}
+
+// NewKubernetesTestHarness returns a harness that invokes the Kubernetes/YAML functions. The functions
+// are registered once per process.
+func NewKubernetesTestHarness() *FunctionTestHarness {
+	h, _ := NewToolchainTestHarness(workerapi.ToolchainKubernetesYAML)
+	return h
+}
+
+// WithYAML sets the configuration data the function will be invoked on.
+func (h *FunctionTestHarness) WithYAML(yaml string) *FunctionTestHarness {
+	h.yaml = yaml
+	return h
+}
+
+// WithArg appends a named argument. Arguments may be specified in any order.
+func (h *FunctionTestHarness) WithArg(name string, value any) *FunctionTestHarness {
+	h.arguments = append(h.arguments, api.FunctionArgument{ParameterName: name, Value: value})
+	return h
+}
+
+// WithLiveState sets the live state passed to the function.
+func (h *FunctionTestHarness) WithLiveState(yaml string) *FunctionTestHarness {
+	h.liveState = []byte(yaml)
+	return h
+}
+
+// WithContext replaces the function context passed to the function.
+func (h *FunctionTestHarness) WithContext(functionContext api.FunctionContext) *FunctionTestHarness {
+	h.functionContext = functionContext
+	return h
+}
+
+// Run invokes the specified function and returns the resulting configuration data, the function
+// output, and the error, if any.
+func (h *FunctionTestHarness) Run(functionName string) (gaby.Container, any, error) {
+	h.configData, h.output, h.err = h.run(functionName)
+	return h.configData, h.output, h.err
+}
+
+func (h *FunctionTestHarness) run(functionName string) (gaby.Container, any, error) {
+	registration, ok := h.handler.ListCore()[functionName]
+	if !ok {
+		return nil, nil, fmt.Errorf("function %s not registered", functionName)
+	}
+	parsedData, err := gaby.ParseAll([]byte(h.yaml))
+	if err != nil {
+		return nil, nil, err
+	}
+	invocation := &api.FunctionInvocation{
+		FunctionName: functionName,
+		Arguments:    append([]api.FunctionArgument{}, h.arguments...),
+	}
+	args, err := handler.ValidateAndBuildArguments(invocation, &registration.FunctionSignature, true)
+	if err != nil {
+		return parsedData, nil, err
+	}
+	functionContext := h.functionContext
+	return registration.Invoke(context.Background(), &functionContext, parsedData, args, h.liveState)
+}
+
+// ConfigData returns the configuration data resulting from the most recent Run.
+func (h *FunctionTestHarness) ConfigData() gaby.Container {
+	return h.configData
+}
+
+// Output returns the function output from the most recent Run.
+func (h *FunctionTestHarness) Output() any {
+	return h.output
+}
+
+// AssertPathEquals asserts that the most recent Run succeeded and that the value at the specified
+// resolved path equals value in the first document containing the path.
+func (h *FunctionTestHarness) AssertPathEquals(t stdtesting.TB, path string, value any) bool {
+	t.Helper()
+	if !assert.NoError(t, h.err) {
+		return false
+	}
+	for _, doc := range h.configData {
+		if doc.ExistsP(path) {
+			return assert.EqualValues(t, value, doc.Path(path).Data(), "value at path %s", path)
+		}
+	}
+	return assert.Fail(t, fmt.Sprintf("path %s not found", path))
+}
+
+// AssertValidationPassed asserts that the most recent Run succeeded and returned a passing
+// ValidationResult or a ValidationResultList that passed entirely.
+func (h *FunctionTestHarness) AssertValidationPassed(t stdtesting.TB) bool {
+	t.Helper()
+	if !assert.NoError(t, h.err) {
+		return false
+	}
+	switch result := h.output.(type) {
+	case api.ValidationResult:
+		return assert.True(t, result.Passed, "validation failed: %v", result.DetailMessages())
+	case api.ValidationResultList:
+		passed := true
+		for _, r := range result {
+			passed = assert.True(t, r.Passed, "validation failed: %v", r.DetailMessages()) && passed
+		}
+		return passed
+	}
+	return assert.Fail(t, fmt.Sprintf("output of type %T is not a validation result", h.output))
+}
@@ -0,0 +1,245 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package testing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confighub/sdk/function/api"
+	"github.com/confighub/sdk/third_party/gaby"
+)
+
+const deploymentYAML = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example-deployment
+  namespace: default
+  labels:
+    app: example
+spec:
+  replicas: 1
+  paused: false
+  template:
+    spec:
+      containers:
+      - name: main
+        image: nginx:1.14.2
+`
+
+const placeholderYAML = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: confighubplaceholder
+`
+
+const floatPlaceholderYAML = `apiVersion: example.com/v1
+kind: Weighted
+metadata:
+  name: example
+spec:
+  weight: 999999999.0
+`
+
+func TestHarness_SetIntPath(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("resource-type", "apps/v1/Deployment").
+		WithArg("path", "spec.replicas").
+		WithArg("attribute-value", 3)
+	_, _, err := h.Run("set-int-path")
+	assert.NoError(t, err)
+	h.AssertPathEquals(t, "spec.replicas", 3)
+}
+
+func TestHarness_SetStringPathWithStringArgs(t *testing.T) {
+	// Arguments may be specified out of order
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("attribute-value", "other").
+		WithArg("path", "metadata.labels.app").
+		WithArg("resource-type", "apps/v1/Deployment")
+	_, _, err := h.Run("set-string-path")
+	assert.NoError(t, err)
+	h.AssertPathEquals(t, "metadata.labels.app", "other")
+}
+
+func TestHarness_SetBoolPathCastsStringArg(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("resource-type", "apps/v1/Deployment").
+		WithArg("path", "spec.paused").
+		WithArg("attribute-value", "true")
+	_, _, err := h.Run("set-bool-path")
+	assert.NoError(t, err)
+	h.AssertPathEquals(t, "spec.paused", true)
+}
+
+func TestHarness_SetFloatPathCastsStringArg(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("resource-type", "apps/v1/Deployment").
+		WithArg("path", "metadata.annotations.ratio").
+		WithArg("attribute-value", "0.75")
+	_, _, err := h.Run("set-float-path")
+	assert.NoError(t, err)
+	h.AssertPathEquals(t, "metadata.annotations.ratio", 0.75)
+
+	h = NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("resource-type", "apps/v1/Deployment").
+		WithArg("path", "spec.replicas").
+		WithArg("attribute-value", "fast")
+	_, _, err = h.Run("set-float-path")
+	assert.ErrorContains(t, err, "cannot convert argument attribute-value value fast to float")
+}
+
+func TestHarness_GetStringPath(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("resource-type", "apps/v1/Deployment").
+		WithArg("path", "spec.template.spec.containers.0.image")
+	_, output, err := h.Run("get-string-path")
+	assert.NoError(t, err)
+	values, ok := output.(api.AttributeValueList)
+	assert.True(t, ok)
+	assert.Len(t, values, 1)
+	assert.Equal(t, "nginx:1.14.2", values[0].Value)
+}
+
+func TestHarness_SetReplicas(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("replicas", 5)
+	_, _, err := h.Run("set-replicas")
+	assert.NoError(t, err)
+	h.AssertPathEquals(t, "spec.replicas", 5)
+}
+
+func TestHarness_SetImage(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("container-name", "main").
+		WithArg("container-image", "nginx:1.27.0")
+	_, _, err := h.Run("set-image")
+	assert.NoError(t, err)
+	h.AssertPathEquals(t, "spec.template.spec.containers.0.image", "nginx:1.27.0")
+}
+
+func TestHarness_SetLabel(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("label-key", "tier").
+		WithArg("label-value", "backend")
+	_, _, err := h.Run("set-label")
+	assert.NoError(t, err)
+	h.AssertPathEquals(t, "metadata.labels.tier", "backend")
+}
+
+func TestHarness_SetNamespace(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("namespace-name", "production")
+	_, _, err := h.Run("set-namespace")
+	assert.NoError(t, err)
+	h.AssertPathEquals(t, "metadata.namespace", "production")
+}
+
+func TestHarness_SearchReplace(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("search-value", "example").
+		WithArg("replace-value", "sample")
+	_, _, err := h.Run("search-replace")
+	assert.NoError(t, err)
+	h.AssertPathEquals(t, "metadata.name", "sample-deployment")
+	h.AssertPathEquals(t, "metadata.labels.app", "sample")
+}
+
+func TestHarness_NoPlaceholders(t *testing.T) {
+	h := NewKubernetesTestHarness().WithYAML(deploymentYAML)
+	_, _, err := h.Run("no-placeholders")
+	assert.NoError(t, err)
+	h.AssertValidationPassed(t)
+
+	h = NewKubernetesTestHarness().WithYAML(placeholderYAML)
+	_, output, err := h.Run("no-placeholders")
+	assert.NoError(t, err)
+	result, ok := output.(api.ValidationResult)
+	assert.True(t, ok)
+	assert.False(t, result.Passed)
+
+	h = NewKubernetesTestHarness().WithYAML(floatPlaceholderYAML)
+	_, output, err = h.Run("no-placeholders")
+	assert.NoError(t, err)
+	result, ok = output.(api.ValidationResult)
+	assert.True(t, ok)
+	assert.False(t, result.Passed)
+	if assert.Len(t, result.FailedAttributes, 1) {
+		assert.Equal(t, api.ResolvedPath("spec.weight"), result.FailedAttributes[0].Path)
+	}
+}
+
+func TestHarness_CELValidate(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithArg("validation-expr", "r.kind != 'Deployment' || r.spec.replicas < 10")
+	_, _, err := h.Run("cel-validate")
+	assert.NoError(t, err)
+	h.AssertValidationPassed(t)
+}
+
+func TestHarness_IsApproved(t *testing.T) {
+	// Approvals only count if the data hasn't changed since approval
+	parsedData, err := gaby.ParseAll([]byte(deploymentYAML))
+	assert.NoError(t, err)
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML).
+		WithContext(api.FunctionContext{
+			UnitDisplayName:     "TestUnit",
+			ApprovedBy:          []api.Approver{{Email: "user1", Role: api.ApproverRoleAny}, {Email: "user2", Role: api.ApproverRoleAny}},
+			PreviousContentHash: api.HashConfigData([]byte(parsedData.String())),
+		}).
+		WithArg("num-approvers", 2)
+	_, _, err = h.Run("is-approved")
+	assert.NoError(t, err)
+	h.AssertValidationPassed(t)
+}
+
+func TestHarness_DeleteResource(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(deploymentYAML+"---\n"+placeholderYAML).
+		WithArg("resource-type", "v1/Namespace").
+		WithArg("resource-name", "/confighubplaceholder")
+	configData, _, err := h.Run("delete-resource")
+	assert.NoError(t, err)
+	assert.Len(t, configData, 1)
+	h.AssertPathEquals(t, "kind", "Deployment")
+}
+
+func TestHarness_UnknownFunction(t *testing.T) {
+	_, _, err := NewKubernetesTestHarness().WithYAML(deploymentYAML).Run("no-such-function")
+	assert.Error(t, err)
+}
+
+func TestHarness_MissingArgument(t *testing.T) {
+	_, _, err := NewKubernetesTestHarness().WithYAML(deploymentYAML).Run("set-replicas")
+	assert.Error(t, err)
+}
+
+func TestHarness_Replicate(t *testing.T) {
+	h := NewKubernetesTestHarness().
+		WithYAML(placeholderYAML+"---\n"+deploymentYAML).
+		WithArg("resource-type", "apps/v1/Deployment").
+		WithArg("resource-name", "example-deployment").
+		WithArg("replicas", 2)
+	configData, _, err := h.Run("replicate")
+	assert.NoError(t, err)
+	if assert.Len(t, configData, 3) {
+		assert.Equal(t, "Namespace", configData[0].Path("kind").Data())
+		assert.Equal(t, "example-deployment0", configData[1].Path("metadata.name").Data())
+		assert.Equal(t, "example-deployment1", configData[2].Path("metadata.name").Data())
+	}
+}
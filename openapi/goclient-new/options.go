@@ -0,0 +1,29 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package goclientnew
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WithTimeout sets the time limit of each request made by the client, including reading the
+// response body. It must follow WithHTTPClient, if used, and the Doer must be an *http.Client,
+// which is copied rather than modified.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if c.Client == nil {
+			c.Client = &http.Client{}
+		}
+		httpClient, ok := c.Client.(*http.Client)
+		if !ok {
+			return fmt.Errorf("cannot set the timeout of a %T", c.Client)
+		}
+		withTimeout := *httpClient
+		withTimeout.Timeout = timeout
+		c.Client = &withTimeout
+		return nil
+	}
+}
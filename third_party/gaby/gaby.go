@@ -2,11 +2,14 @@ package gaby
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -315,6 +318,12 @@ func (c *YamlDoc) IsEmptyDoc() bool {
 	return c.isEmptyDoc || c.YNode().IsZero() || yaml.IsYNodeNilOrEmpty(c.YNode())
 }
 
+// IsNil returns true if the element doesn't exist or is a YAML null, such as the value of
+// `annotations: null` or `annotations:`.
+func (c *YamlDoc) IsNil() bool {
+	return c == nil || c.node.IsNil() || c.node.IsTaggedNull()
+}
+
 func (c *YamlDoc) IsArray() bool {
 	return c.node.YNode().Kind == yaml.SequenceNode
 }
@@ -378,7 +387,8 @@ func (c *YamlDoc) ChildrenMap() map[string]*YamlDoc {
 }
 
 // Set attempts to set the value of a field located by a hierarchy of field
-// names.
+// names. An existing literal or folded string keeps its style if the new value
+// is also a multi-line string.
 func (c *YamlDoc) Set(value interface{}, hierarchy ...string) (*YamlDoc, error) {
 	if c == nil {
 		return nil, ErrInvalidInputObj
@@ -481,18 +491,24 @@ func setValue(node *yaml.Node, value interface{}) error {
 		node.Kind = yaml.ScalarNode
 		node.Value = v
 		node.Tag = yaml.NodeTagString
+		if !strings.Contains(v, "\n") {
+			clearBlockStyle(node)
+		}
 	case int:
 		node.Kind = yaml.ScalarNode
 		node.Value = strconv.Itoa(v)
 		node.Tag = yaml.NodeTagInt
+		clearBlockStyle(node)
 	case bool:
 		node.Kind = yaml.ScalarNode
 		node.Value = strconv.FormatBool(v)
 		node.Tag = yaml.NodeTagBool
+		clearBlockStyle(node)
 	case float64:
 		node.Kind = yaml.ScalarNode
-		node.Value = fmt.Sprintf("%v", v)
+		node.Value = formatFloat(v)
 		node.Tag = yaml.NodeTagFloat
+		clearBlockStyle(node)
 	case map[string]interface{}:
 		node.Kind = yaml.MappingNode
 		for key, val := range v {
@@ -546,6 +562,30 @@ func setValue(node *yaml.Node, value interface{}) error {
 	return nil
 }
 
+// formatFloat formats v so that it's read back as a float rather than an int, without an explicit tag.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return ".nan"
+	case math.IsInf(v, 1):
+		return ".inf"
+	case math.IsInf(v, -1):
+		return "-.inf"
+	}
+	str := strconv.FormatFloat(v, 'g', -1, 64)
+	if !strings.ContainsAny(str, ".e") {
+		str += ".0"
+	}
+	return str
+}
+
+// clearBlockStyle removes the literal (|) or folded (>) style of a scalar node that no longer
+// contains a multi-line string. The block style of an overwritten multi-line string is preserved,
+// so that embedded scripts and the like remain readable.
+func clearBlockStyle(node *yaml.Node) {
+	node.Style &^= yaml.LiteralStyle | yaml.FoldedStyle
+}
+
 // SetP sets the value of a field at a path using dot notation.
 func (c *YamlDoc) SetP(value interface{}, path string) (*YamlDoc, error) {
 	return c.Set(value, DotPathToSlice(path)...)
@@ -840,6 +880,45 @@ func (c *YamlDoc) Merge(source *YamlDoc) error {
 	})
 }
 
+// Overlay merges a source object into an existing destination object, like a Kustomize patch.
+// Maps are merged recursively, and other values in source, including sequences, replace those in
+// the destination. Keys whose value in source is null are removed from the destination.
+func (c *YamlDoc) Overlay(source *YamlDoc) error {
+	err := c.MergeFn(source, func(_, src interface{}) interface{} {
+		return src.(*yaml.RNode).YNode()
+	})
+	if err != nil {
+		return err
+	}
+	return removeNullFields(c.node, source.node)
+}
+
+// removeNullFields removes the fields of destNode whose values in sourceNode are null.
+func removeNullFields(destNode, sourceNode *yaml.RNode) error {
+	if destNode.YNode().Kind != yaml.MappingNode || sourceNode.YNode().Kind != yaml.MappingNode {
+		return nil
+	}
+	sourceFields, err := sourceNode.Fields()
+	if err != nil {
+		return err
+	}
+	for _, key := range sourceFields {
+		sourceValueNode := sourceNode.Field(key).Value
+		if yaml.IsMissingOrNull(sourceValueNode) {
+			if _, err := destNode.Pipe(yaml.Clear(key)); err != nil {
+				return err
+			}
+			continue
+		}
+		if destField := destNode.Field(key); destField != nil {
+			if err := removeNullFields(destField.Value, sourceValueNode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 /*
@@ -1232,6 +1311,145 @@ func (c *YamlDoc) String() string {
 	return string(c.Bytes())
 }
 
+// SerializeOptions control how documents are formatted as YAML.
+type SerializeOptions struct {
+	// IndentSpaces is the number of spaces per indentation level. 0 means 2.
+	IndentSpaces int
+	// QuoteStrings causes string values, but not mapping keys, to be double-quoted.
+	QuoteStrings bool
+	// PreserveComments causes comments to be included in the output.
+	PreserveComments bool
+	// AddTrailingNewline causes the output to end with a newline.
+	AddTrailingNewline bool
+	// NormalizeStyles causes maps and sequences to use block style and strings to be unquoted
+	// unless their values require quoting, except for literal and folded strings, and strips
+	// trailing whitespace from comments.
+	NormalizeStyles bool
+}
+
+// DefaultSerializeOptions are the options used by String.
+var DefaultSerializeOptions = SerializeOptions{
+	IndentSpaces:       2,
+	PreserveComments:   true,
+	AddTrailingNewline: true,
+}
+
+// StringWithOptions marshals an element to a YAML string formatted as specified by opts.
+func (c *YamlDoc) StringWithOptions(opts SerializeOptions) (string, error) {
+	if opts.IndentSpaces == 0 {
+		opts.IndentSpaces = DefaultSerializeOptions.IndentSpaces
+	}
+	var str string
+	if c == nil || c.node == nil || opts.IndentSpaces == 2 && !opts.QuoteStrings && opts.PreserveComments && !opts.NormalizeStyles {
+		str = string(c.Bytes())
+	} else {
+		node := yaml.CopyYNode(c.node.YNode())
+		formatNode(node, opts, false)
+		var b bytes.Buffer
+		encoder := yaml.NewEncoder(&b)
+		encoder.SetIndent(opts.IndentSpaces)
+		if err := encoder.Encode(node); err != nil {
+			return "", err
+		}
+		encoder.Close()
+		str = b.String()
+	}
+	if !opts.AddTrailingNewline {
+		str = strings.TrimSuffix(str, "\n")
+	}
+	return str, nil
+}
+
+// formatNode modifies node and its descendants to remove comments, normalize styles, and quote
+// strings as specified by opts.
+func formatNode(node *yaml.Node, opts SerializeOptions, isKey bool) {
+	if !opts.PreserveComments {
+		node.HeadComment = ""
+		node.LineComment = ""
+		node.FootComment = ""
+	} else if opts.NormalizeStyles {
+		node.HeadComment = stripTrailingWhitespace(node.HeadComment)
+		node.LineComment = stripTrailingWhitespace(node.LineComment)
+		node.FootComment = stripTrailingWhitespace(node.FootComment)
+	}
+	if opts.NormalizeStyles {
+		// Strings that require quoting are still quoted when serialized
+		switch {
+		case node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode:
+			node.Style &^= yaml.FlowStyle
+		case node.Kind == yaml.ScalarNode && node.ShortTag() == yaml.NodeTagString:
+			node.Style &^= yaml.SingleQuotedStyle | yaml.DoubleQuotedStyle
+		}
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if opts.QuoteStrings && !isKey && node.ShortTag() == yaml.NodeTagString {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+	case yaml.MappingNode:
+		for i, child := range node.Content {
+			formatNode(child, opts, i%2 == 0)
+		}
+	default:
+		for _, child := range node.Content {
+			formatNode(child, opts, false)
+		}
+	}
+}
+
+func stripTrailingWhitespace(s string) string {
+	if s == "" {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SortKeys reorders the keys of the maps in the element and its descendants as determined by
+// compare, which returns a negative number if key a should precede key b, a positive number if
+// it should follow b, and 0 to preserve their original order. Comments move with their keys.
+func (c *YamlDoc) SortKeys(compare func(a, b string) int) {
+	if c == nil || c.node == nil {
+		return
+	}
+	sortNodeKeys(c.node.YNode(), compare)
+}
+
+// SortTopLevelKeys reorders the keys of the element, if it is a map, as determined by compare,
+// like SortKeys, without reordering the keys of its descendants.
+func (c *YamlDoc) SortTopLevelKeys(compare func(a, b string) int) {
+	if c == nil || c.node == nil {
+		return
+	}
+	sortMappingKeys(c.node.YNode(), compare)
+}
+
+func sortNodeKeys(node *yaml.Node, compare func(a, b string) int) {
+	for _, child := range node.Content {
+		sortNodeKeys(child, compare)
+	}
+	sortMappingKeys(node, compare)
+}
+
+func sortMappingKeys(node *yaml.Node, compare func(a, b string) int) {
+	if node.Kind != yaml.MappingNode || len(node.Content) < 4 {
+		return
+	}
+	pairs := make([][2]*yaml.Node, len(node.Content)/2)
+	for i := range pairs {
+		pairs[i] = [2]*yaml.Node{node.Content[2*i], node.Content[2*i+1]}
+	}
+	slices.SortStableFunc(pairs, func(a, b [2]*yaml.Node) int {
+		return compare(a[0].Value, b[0].Value)
+	})
+	for i, pair := range pairs {
+		node.Content[2*i], node.Content[2*i+1] = pair[0], pair[1]
+	}
+}
+
 // StringIndent marshals an element to a YAML string formatted with indents.
 func (c *YamlDoc) StringIndent(indent int) string {
 	return string(c.BytesIndent(indent))
@@ -1255,6 +1473,83 @@ func (c *YamlDoc) MarshalJSON() ([]byte, error) {
 	return data, nil
 }
 
+// MarshalOrderedJSON returns the compact JSON encoding of this container. Unlike MarshalJSON,
+// which sorts the keys of maps, it keeps the keys in the order of the document.
+func (c *YamlDoc) MarshalOrderedJSON() ([]byte, error) {
+	if c == nil || c.node == nil {
+		return EmptyDocument, nil
+	}
+	var b bytes.Buffer
+	if err := encodeOrderedJSON(&b, c.node.YNode()); err != nil {
+		return EmptyDocument, err
+	}
+	return b.Bytes(), nil
+}
+
+func encodeOrderedJSON(b *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			b.WriteString("null")
+			return nil
+		}
+		return encodeOrderedJSON(b, node.Content[0])
+	case yaml.AliasNode:
+		return encodeOrderedJSON(b, node.Alias)
+	case yaml.MappingNode:
+		b.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := encodeJSONValue(b, node.Content[i].Value); err != nil {
+				return err
+			}
+			b.WriteByte(':')
+			if err := encodeOrderedJSON(b, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	case yaml.SequenceNode:
+		b.WriteByte('[')
+		for i, child := range node.Content {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := encodeOrderedJSON(b, child); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	default:
+		// Numbers are written as they appear in the document, so that 2.0 doesn't become 2
+		if tag := node.ShortTag(); (tag == "!!int" || tag == "!!float") && json.Valid([]byte(node.Value)) {
+			b.WriteString(node.Value)
+			return nil
+		}
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		return encodeJSONValue(b, value)
+	}
+	return nil
+}
+
+// encodeJSONValue writes the JSON encoding of value to b without escaping HTML characters such
+// as < and >, which would change values that don't need to be changed.
+func encodeJSONValue(b *bytes.Buffer, value any) error {
+	encoder := json.NewEncoder(b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	// Remove the newline added by Encode
+	b.Truncate(b.Len() - 1)
+	return nil
+}
+
 // ParseJSON reads a JSON byte slice and returns a *YamlDoc.
 func ParseJSON(y []byte) (*YamlDoc, error) {
 	node, err := yaml.ConvertJSONToYamlNode(string(y))
@@ -1264,7 +1559,119 @@ func ParseJSON(y []byte) (*YamlDoc, error) {
 	return &YamlDoc{node: node}, nil
 }
 
-// ParseYAML reads a YAML byte slice and returns a *YamlDoc.
+// ParseOrderedJSON reads a JSON byte slice and returns a *YamlDoc. Unlike ParseJSON, it keeps the
+// keys of objects in the order of the document and keeps numbers as they're written, so that
+// MarshalOrderedJSON changes no more of the document than was changed.
+func ParseOrderedJSON(y []byte) (*YamlDoc, error) {
+	decoder := json.NewDecoder(bytes.NewReader(y))
+	decoder.UseNumber()
+	node, err := decodeOrderedJSON(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return &YamlDoc{node: yaml.NewRNode(node)}, nil
+}
+
+func decodeOrderedJSON(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch value := token.(type) {
+	case json.Delim:
+		if value == '{' {
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				child, err := decodeOrderedJSON(decoder)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)}, child)
+			}
+			_, err = decoder.Token()
+			return node, err
+		}
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for decoder.More() {
+			child, err := decodeOrderedJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		_, err = decoder.Token()
+		return node, err
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(value.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(value)}, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+}
+
+// ParseError is returned by ParseYAML and ParseAll when the YAML is syntactically invalid.
+type ParseError struct {
+	// Index is the position of the invalid document in the input, counting from 0
+	Index int
+	// Line is the line of the error within the document, counting from 1, or 0 if unknown
+	Line int
+	// Column is the column of the error within the line, counting from 1, or 0 if unknown
+	Column int
+	// Message describes the error, without its position
+	Message string
+	// Err is the error returned by the YAML parser
+	Err error `json:"-"`
+}
+
+func (e *ParseError) Error() string {
+	position := fmt.Sprintf("YAML document %d", e.Index)
+	if e.Line > 0 {
+		position += fmt.Sprintf(", line %d", e.Line)
+	}
+	if e.Column > 0 {
+		position += fmt.Sprintf(", column %d", e.Column)
+	}
+	return position + ": " + e.Message
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// yamlErrorPositionRegexp matches the position in errors of the YAML parser, such as
+// "yaml: line 2: did not find expected key" or "yaml: line 2, column 3: ...".
+var yamlErrorPositionRegexp = regexp.MustCompile(`^(?:yaml: )?(?:line (\d+)(?:, column (\d+))?: )?(?s)(.*)$`)
+
+// newParseError returns a ParseError for an error of the YAML parser, extracting the position of
+// the error from its message, if present.
+func newParseError(err error) *ParseError {
+	parseError := &ParseError{Message: err.Error(), Err: err}
+	matches := yamlErrorPositionRegexp.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return parseError
+	}
+	parseError.Line, _ = strconv.Atoi(matches[1])
+	parseError.Column, _ = strconv.Atoi(matches[2])
+	parseError.Message = matches[3]
+	return parseError
+}
+
+// ParseYAML reads a YAML byte slice and returns a *YamlDoc. If the YAML is syntactically invalid,
+// the error is a *ParseError.
 func ParseYAML(y []byte) (*YamlDoc, error) {
 	node, err := yaml.Parse(string(y))
 	if errors.Is(err, io.EOF) {
@@ -1282,7 +1689,7 @@ func ParseYAML(y []byte) (*YamlDoc, error) {
 		node := yaml.NewRNode(documentNode) // Wrap in RNode
 		return &YamlDoc{isEmptyDoc: true, node: node}, nil
 	} else if err != nil {
-		return nil, err
+		return nil, newParseError(err)
 	}
 
 	return &YamlDoc{node: node}, nil
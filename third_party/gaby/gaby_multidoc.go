@@ -1,14 +1,27 @@
 package gaby
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"regexp"
 	"strings"
+
+	k8syaml "sigs.k8s.io/yaml"
 )
 
 type Container []*YamlDoc
 
+var documentBOMRegexp = regexp.MustCompile("(?m)(^---[^\n]*\n)\uFEFF")
+
 func NormalizeYAML(y string) string {
+	// Remove the UTF-8 byte order mark written by some Windows editors
+	y = strings.TrimPrefix(y, "\uFEFF")
+	// Convert Windows and old Mac line endings
 	y = strings.ReplaceAll(y, "\r\n", "\n")
+	y = strings.ReplaceAll(y, "\r", "\n")
+	// Remove byte order marks at the start of subsequent documents, such as from concatenated files
+	y = documentBOMRegexp.ReplaceAllString(y, "$1")
 	// Handle comment after document separator without newline
 	re := regexp.MustCompile(`(---)([ \t]*#)`)
 	y = re.ReplaceAllString(y, "$1\n$2")
@@ -29,27 +42,101 @@ func YamlIsEmpty(y string) bool {
 	return y == "" || y == "null" || y == "{}" || y == "[]"
 }
 
-func ParseAll(y []byte) (Container, error) {
-	chunks := strings.Split(NormalizeYAML(string(y)), "\n---\n")
+// yamlIsCommentOnly returns true if the YAML doc contains only comments and blank lines.
+func yamlIsCommentOnly(y string) bool {
+	for _, line := range strings.Split(y, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseOptions control how ParseAll splits and parses multi-document YAML.
+type ParseOptions struct {
+	// MaxDocuments is the maximum number of documents to parse. 0 means unlimited.
+	MaxDocuments int
+	// StrictMode causes empty and comment-only documents to be reported as errors rather than skipped.
+	StrictMode bool
+	// MaxDocumentSizeBytes is the maximum size of each document. 0 means unlimited.
+	MaxDocumentSizeBytes int
+}
+
+// TooManyDocumentsError is returned by ParseAll when the input contains more than
+// ParseOptions.MaxDocuments documents.
+type TooManyDocumentsError struct {
+	MaxDocuments int
+}
+
+func (e *TooManyDocumentsError) Error() string {
+	return fmt.Sprintf("too many YAML documents: the maximum is %d", e.MaxDocuments)
+}
+
+// DocumentTooLargeError is returned by ParseAll when a document is larger than
+// ParseOptions.MaxDocumentSizeBytes.
+type DocumentTooLargeError struct {
+	// Index is the position of the document in the input, counting from 0
+	Index                int
+	SizeBytes            int
+	MaxDocumentSizeBytes int
+}
+
+func (e *DocumentTooLargeError) Error() string {
+	return fmt.Sprintf("YAML document %d is %d bytes: the maximum is %d", e.Index, e.SizeBytes, e.MaxDocumentSizeBytes)
+}
+
+// ParseAll parses multi-document YAML. By default, empty and comment-only documents are skipped
+// and there are no limits on the number or size of documents. Only the first ParseOptions, if
+// any, are used.
+func ParseAll(y []byte, options ...ParseOptions) (Container, error) {
+	var opts ParseOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	normalized := NormalizeYAML(string(y))
 	var multiDoc Container
-	for _, chunk := range chunks {
+	if normalized == "" {
+		return multiDoc, nil
+	}
+	// Documents are split off one at a time so that parsing stops as soon as a limit is exceeded.
+	remaining := normalized
+	for i := 0; remaining != ""; i++ {
+		chunk, rest, _ := strings.Cut(remaining, "\n---\n")
+		remaining = rest
+		if opts.MaxDocumentSizeBytes > 0 && len(chunk) > opts.MaxDocumentSizeBytes {
+			return nil, &DocumentTooLargeError{Index: i, SizeBytes: len(chunk), MaxDocumentSizeBytes: opts.MaxDocumentSizeBytes}
+		}
 		// If the chunk is empty, it will be deserialized as a document with no content, e.g. "---\n---",
 		// or "null\n"
 		// We should not add it to the container.
 		if YamlIsEmpty(chunk) {
+			if opts.StrictMode {
+				return nil, fmt.Errorf("YAML document %d is empty", i)
+			}
 			continue
 		}
+		if opts.MaxDocuments > 0 && len(multiDoc) >= opts.MaxDocuments && !yamlIsCommentOnly(chunk) {
+			return nil, &TooManyDocumentsError{MaxDocuments: opts.MaxDocuments}
+		}
 		// See https://github.com/kubernetes-sigs/kustomize/pull/3431
 		if !strings.HasSuffix(chunk, "\n") {
 			chunk += "\n"
 		}
 		container, err := ParseYAML([]byte(chunk))
 		if err != nil {
+			var parseError *ParseError
+			if errors.As(err, &parseError) {
+				parseError.Index = i
+			}
 			return nil, err
 		}
 		if container.IsEmptyDoc() {
 			// This is a document with only comments, e.g. "---\n# comment\n---"
 			// We should not add it to the container.
+			if opts.StrictMode {
+				return nil, fmt.Errorf("YAML document %d contains only comments", i)
+			}
 			continue
 		}
 		multiDoc = append(multiDoc, container)
@@ -77,6 +164,7 @@ func (m Container) Data() interface{} {
 	return m[0].Data()
 }
 
+// String serializes the documents as multi-document YAML formatted with DefaultSerializeOptions.
 func (m Container) String() string {
 	var result []string
 	for _, c := range m {
@@ -87,3 +175,144 @@ func (m Container) String() string {
 	}
 	return strings.Join(result, "---\n")
 }
+
+// StringWithOptions serializes the documents as multi-document YAML formatted as specified by opts.
+func (m Container) StringWithOptions(opts SerializeOptions) (string, error) {
+	var result []string
+	for i, c := range m {
+		if c.IsEmptyDoc() {
+			continue
+		}
+		docOpts := opts
+		docOpts.AddTrailingNewline = true
+		str, err := c.StringWithOptions(docOpts)
+		if err != nil {
+			return "", fmt.Errorf("failed to serialize YAML document %d: %w", i, err)
+		}
+		result = append(result, str)
+	}
+	str := strings.Join(result, "---\n")
+	if !opts.AddTrailingNewline {
+		str = strings.TrimSuffix(str, "\n")
+	}
+	return str, nil
+}
+
+// ToJSONArray serializes the documents as a JSON array with one element per document.
+func (m Container) ToJSONArray() ([]byte, error) {
+	docs := make([]json.RawMessage, 0, len(m))
+	for i, c := range m {
+		if c.IsEmptyDoc() {
+			continue
+		}
+		jsonDoc, err := k8syaml.YAMLToJSON(c.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert YAML document %d to JSON: %w", i, err)
+		}
+		docs = append(docs, jsonDoc)
+	}
+	return json.Marshal(docs)
+}
+
+// ContainerFromJSONArray parses a JSON array of objects, such as one produced by
+// Container.ToJSONArray, into a Container with one document per element.
+func ContainerFromJSONArray(data []byte) (Container, error) {
+	var jsonDocs []json.RawMessage
+	if err := json.Unmarshal(data, &jsonDocs); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON array: %w", err)
+	}
+	multiDoc := make(Container, 0, len(jsonDocs))
+	for i, jsonDoc := range jsonDocs {
+		yamlDoc, err := k8syaml.JSONToYAML(jsonDoc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert JSON array element %d to YAML: %w", i, err)
+		}
+		container, err := ParseYAML(yamlDoc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON array element %d: %w", i, err)
+		}
+		multiDoc = append(multiDoc, container)
+	}
+	return multiDoc, nil
+}
+
+// MergeOptions control how MergeContainersWithOptions combines containers.
+type MergeOptions struct {
+	// DeduplicateByName causes documents with the same resource type and name to be merged into
+	// one. The resource type is the apiVersion and kind, and the name is the metadata.name,
+	// qualified by the metadata.namespace, if any. Documents without a kind or name are never
+	// considered duplicates. If DeduplicateByName is false, the documents are concatenated.
+	DeduplicateByName bool
+	// ConflictResolver returns the document to keep when incoming has the same resource type and
+	// name as existing. Returning nil removes the document; a later document with the same
+	// resource type and name then takes its place without being resolved. If ConflictResolver is
+	// nil, incoming replaces existing.
+	ConflictResolver func(existing, incoming *YamlDoc) *YamlDoc
+}
+
+// MergeContainers concatenates the documents of the containers, removing duplicate documents
+// with the same resource type and name. For duplicates, the document from the last container
+// wins, at the position of the first occurrence.
+func MergeContainers(containers ...Container) Container {
+	return MergeContainersWithOptions(MergeOptions{DeduplicateByName: true}, containers...)
+}
+
+// resourceTypeAndName returns the resource type and name of the document, and false if the
+// document has no kind or name.
+func resourceTypeAndName(doc *YamlDoc) (string, bool) {
+	getString := func(hierarchy ...string) string {
+		value, _ := doc.Search(hierarchy...).Data().(string)
+		return value
+	}
+	kind := getString("kind")
+	name := getString("metadata", "name")
+	if kind == "" || name == "" {
+		return "", false
+	}
+	if namespace := getString("metadata", "namespace"); namespace != "" {
+		name = namespace + "/" + name
+	}
+	return getString("apiVersion") + "/" + kind + "#" + name, true
+}
+
+// MergeContainersWithOptions concatenates the documents of the containers as specified by opts.
+func MergeContainersWithOptions(opts MergeOptions, containers ...Container) Container {
+	var merged Container
+	positions := map[string]int{}
+	for _, container := range containers {
+		for _, doc := range container {
+			if doc == nil {
+				continue
+			}
+			if !opts.DeduplicateByName {
+				merged = append(merged, doc)
+				continue
+			}
+			key, ok := resourceTypeAndName(doc)
+			if !ok {
+				merged = append(merged, doc)
+				continue
+			}
+			i, isDuplicate := positions[key]
+			if !isDuplicate {
+				positions[key] = len(merged)
+				merged = append(merged, doc)
+				continue
+			}
+			existing := merged[i]
+			if existing != nil && opts.ConflictResolver != nil {
+				merged[i] = opts.ConflictResolver(existing, doc)
+			} else {
+				merged[i] = doc
+			}
+		}
+	}
+	// Remove the documents removed by the ConflictResolver
+	result := merged[:0]
+	for _, doc := range merged {
+		if doc != nil {
+			result = append(result, doc)
+		}
+	}
+	return result
+}
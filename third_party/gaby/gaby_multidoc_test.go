@@ -1,8 +1,10 @@
 package gaby
 
 import (
-	"github.com/stretchr/testify/assert"
+	"errors"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMultiDoc(t *testing.T) {
@@ -79,3 +81,334 @@ rules:
 	assert.NoError(t, err, "Error parsing YAML")
 	assert.Equal(t, 2, len(docs), "Expected 2 documents")
 }
+
+func TestParseAllOptions(t *testing.T) {
+	sample := []byte(`a: 1
+---
+# comment only
+---
+b: 2
+---
+c: 3
+`)
+	// The defaults skip comment-only documents and have no limits
+	docs, err := ParseAll(sample)
+	assert.NoError(t, err)
+	assert.Len(t, docs, 3)
+	docs, err = ParseAll(sample, ParseOptions{MaxDocuments: 3})
+	assert.NoError(t, err)
+	assert.Len(t, docs, 3)
+
+	_, err = ParseAll(sample, ParseOptions{MaxDocuments: 2})
+	var tooMany *TooManyDocumentsError
+	if assert.ErrorAs(t, err, &tooMany) {
+		assert.Equal(t, 2, tooMany.MaxDocuments)
+	}
+	// Documents beyond the limit aren't parsed
+	_, err = ParseAll([]byte("a: 1\n---\nb: [\n"), ParseOptions{MaxDocuments: 1})
+	assert.ErrorAs(t, err, &tooMany)
+
+	_, err = ParseAll(sample, ParseOptions{StrictMode: true})
+	assert.ErrorContains(t, err, "YAML document 1 contains only comments")
+	_, err = ParseAll([]byte("a: 1\n---\nnull\n---\nb: 2\n"), ParseOptions{StrictMode: true})
+	assert.ErrorContains(t, err, "YAML document 1 is empty")
+	// Empty input is not an error in strict mode
+	docs, err = ParseAll([]byte(""), ParseOptions{StrictMode: true})
+	assert.NoError(t, err)
+	assert.Len(t, docs, 0)
+
+	_, err = ParseAll([]byte("a: 1\n---\nb: 123456789\n"), ParseOptions{MaxDocumentSizeBytes: 8})
+	var tooLarge *DocumentTooLargeError
+	if assert.ErrorAs(t, err, &tooLarge) {
+		assert.Equal(t, 1, tooLarge.Index)
+		assert.Equal(t, 12, tooLarge.SizeBytes)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := ParseYAML([]byte("a:\n  - b\n c: d\n"))
+	var parseError *ParseError
+	if assert.ErrorAs(t, err, &parseError) {
+		assert.Equal(t, 0, parseError.Index)
+		assert.Equal(t, 2, parseError.Line)
+		assert.Equal(t, 0, parseError.Column)
+		assert.Equal(t, "did not find expected key", parseError.Message)
+		assert.NotNil(t, errors.Unwrap(err))
+	}
+
+	_, err = ParseAll([]byte("a: 1\n---\nb: 2\n---\nc: [1, 2\n"))
+	if assert.ErrorAs(t, err, &parseError) {
+		assert.Equal(t, 2, parseError.Index)
+		assert.Equal(t, 1, parseError.Line)
+		assert.EqualError(t, err, "YAML document 2, line 1: did not find expected ',' or ']'")
+	}
+
+	_, err = ParseYAML([]byte("a: b: c\n"))
+	if assert.ErrorAs(t, err, &parseError) {
+		assert.Equal(t, 0, parseError.Line)
+		assert.Equal(t, "mapping values are not allowed in this context", parseError.Message)
+	}
+
+	parseError = newParseError(errors.New("yaml: line 3, column 7: found a tab character"))
+	assert.Equal(t, 3, parseError.Line)
+	assert.Equal(t, 7, parseError.Column)
+	assert.Equal(t, "found a tab character", parseError.Message)
+}
+
+func TestContainerJSONArray(t *testing.T) {
+	docs, err := ParseAll([]byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+data:
+  key: value
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: b
+`))
+	assert.NoError(t, err)
+	jsonArray, err := docs.ToJSONArray()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+  {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "a"}, "data": {"key": "value"}},
+  {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "b"}}
+]`, string(jsonArray))
+
+	roundTripped, err := ContainerFromJSONArray(jsonArray)
+	assert.NoError(t, err)
+	assert.Len(t, roundTripped, 2)
+	assert.Equal(t, "a", roundTripped[0].Path("metadata.name").Data())
+	assert.Equal(t, "value", roundTripped[0].Path("data.key").Data())
+	assert.Equal(t, "Namespace", roundTripped[1].Path("kind").Data())
+
+	// An empty container is an empty array rather than null
+	jsonArray, err = Container{}.ToJSONArray()
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(jsonArray))
+
+	_, err = ContainerFromJSONArray([]byte(`{"kind": "Namespace"}`))
+	assert.ErrorContains(t, err, "failed to parse JSON array")
+}
+
+func TestNormalizeLineEndingsAndBOM(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"BOM prefix", "\uFEFFapiVersion: v1\nkind: ConfigMap\n---\napiVersion: v1\nkind: Secret\n"},
+		{"Windows line endings", "apiVersion: v1\r\nkind: ConfigMap\r\n---\r\napiVersion: v1\r\nkind: Secret\r\n"},
+		{"Old Mac line endings", "apiVersion: v1\rkind: ConfigMap\r---\rapiVersion: v1\rkind: Secret\r"},
+		{"BOM prefix and Windows line endings", "\uFEFF---\r\napiVersion: v1\r\nkind: ConfigMap\r\n---\r\napiVersion: v1\r\nkind: Secret\r\n"},
+		{"BOM after document separator", "apiVersion: v1\nkind: ConfigMap\n---\n\uFEFFapiVersion: v1\nkind: Secret\n"},
+		{"BOMs of concatenated files with Windows line endings", "\uFEFFapiVersion: v1\r\nkind: ConfigMap\r\n--- # second file\r\n\uFEFFapiVersion: v1\r\nkind: Secret\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := ParseAll([]byte(tt.data))
+			assert.NoError(t, err)
+			if assert.Len(t, docs, 2) {
+				assert.Equal(t, "v1", docs[0].Path("apiVersion").Data())
+				assert.Equal(t, "ConfigMap", docs[0].Path("kind").Data())
+				assert.Equal(t, "v1", docs[1].Path("apiVersion").Data())
+				assert.Equal(t, "Secret", docs[1].Path("kind").Data())
+				assert.NotContains(t, docs.String(), "\uFEFF")
+			}
+		})
+	}
+}
+
+func TestMergeContainers(t *testing.T) {
+	base, err := ParseAll([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+  namespace: base
+`))
+	assert.NoError(t, err)
+	overlay, err := ParseAll([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+  namespace: prod
+---
+unnamed: true
+---
+unnamed: true
+`))
+	assert.NoError(t, err)
+
+	merged := MergeContainers(base, overlay)
+	assert.Len(t, merged, 6)
+	assert.Equal(t, 3, merged[0].Search("spec", "replicas").Data())
+	assert.Equal(t, "Service", merged[1].Search("kind").Data())
+	assert.Equal(t, "base", merged[2].Search("metadata", "namespace").Data())
+	assert.Equal(t, "prod", merged[3].Search("metadata", "namespace").Data())
+
+	concatenated := MergeContainersWithOptions(MergeOptions{}, base, overlay)
+	assert.Len(t, concatenated, 7)
+	assert.Empty(t, MergeContainers())
+}
+
+func TestMergeContainersConflictResolver(t *testing.T) {
+	first, err := ParseAll([]byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  a: "1"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+`))
+	assert.NoError(t, err)
+	second, err := ParseAll([]byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  b: "2"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+`))
+	assert.NoError(t, err)
+
+	third, err := ParseAll([]byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+data:
+  c: "3"
+`))
+	assert.NoError(t, err)
+
+	merged := MergeContainersWithOptions(MergeOptions{
+		DeduplicateByName: true,
+		ConflictResolver: func(existing, incoming *YamlDoc) *YamlDoc {
+			if existing.Search("kind").Data() == "Secret" {
+				return nil
+			}
+			_, err := existing.Set(incoming.Search("data", "b").Data(), "data", "b")
+			assert.NoError(t, err)
+			return existing
+		},
+	}, first, second, third)
+	if assert.Len(t, merged, 2) {
+		assert.Equal(t, "1", merged[0].Search("data", "a").Data())
+		assert.Equal(t, "2", merged[0].Search("data", "b").Data())
+		// The removed Secret is replaced by the next one rather than resolved against nothing
+		assert.Equal(t, "Secret", merged[1].Search("kind").Data())
+		assert.Equal(t, "3", merged[1].Search("data", "c").Data())
+	}
+}
+
+func TestContainerStringWithOptions(t *testing.T) {
+	sample := []byte(`# deployment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web # the name
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	docs, err := ParseAll(sample)
+	assert.NoError(t, err)
+
+	str, err := docs.StringWithOptions(DefaultSerializeOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, docs.String(), str)
+	assert.Equal(t, string(sample), docs.String())
+
+	str, err = docs.StringWithOptions(SerializeOptions{IndentSpaces: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+    name: web
+spec:
+    replicas: 2
+    template:
+        spec:
+            containers:
+              - name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+    name: web`, str)
+
+	str, err = docs.StringWithOptions(SerializeOptions{QuoteStrings: true, PreserveComments: true, AddTrailingNewline: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `# deployment
+apiVersion: "apps/v1"
+kind: "Deployment"
+metadata:
+  name: "web" # the name
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: "web"
+---
+apiVersion: "v1"
+kind: "Service"
+metadata:
+  name: "web"
+`, str)
+
+	// The documents are not modified
+	assert.Equal(t, string(sample), docs.String())
+}
+
+func TestContainerStringWithNormalizedStyles(t *testing.T) {
+	docs, err := ParseAll([]byte("labels: {app: 'web', version: \"1.0\"}\nargs: ['--port', \"8080\"]\n"))
+	assert.NoError(t, err)
+	opts := DefaultSerializeOptions
+	opts.NormalizeStyles = true
+	opts.IndentSpaces = 4
+	expected := `labels:
+    app: web
+    version: "1.0"
+args:
+  - --port
+  - "8080"
+`
+	str, err := docs.StringWithOptions(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, str)
+}
@@ -3,6 +3,7 @@ package gaby
 import (
 	"bytes"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -81,6 +82,33 @@ func TestNilMethods(t *testing.T) {
 	if err := n.Delete("foo"); err == nil {
 		t.Error("expected error")
 	}
+	if !n.IsNil() {
+		t.Error("expected true")
+	}
+}
+
+func TestIsNil(t *testing.T) {
+	val, err := ParseYAML([]byte(`explicit: null
+tilde: ~
+empty:
+string: "null"
+map: {}
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	for path, exp := range map[string]bool{
+		"explicit": true,
+		"tilde":    true,
+		"empty":    true,
+		"missing":  true,
+		"string":   false,
+		"map":      false,
+	} {
+		if act := val.Path(path).IsNil(); act != exp {
+			t.Errorf("IsNil(%s): %v != %v", path, act, exp)
+		}
+	}
 }
 
 var bigSample = []byte(`a:
@@ -355,3 +383,169 @@ metadata:
 		t.Errorf("Unexpected value: %v != %v", act, exp)
 	}
 }
+
+func TestSetPreservesBlockScalarStyle(t *testing.T) {
+	sample := []byte(`data:
+  literal: |
+    echo a
+    echo b
+  folded: >
+    first
+    second
+`)
+	doc, err := ParseYAML(sample)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	doc.SetP("echo c\necho d\n", "data.literal")
+	doc.SetP("third", "data.folded")
+	exp := `data:
+  literal: |
+    echo c
+    echo d
+  folded: third
+`
+	if act := doc.String(); act != exp {
+		t.Errorf("Unexpected value: %v != %v", act, exp)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	doc, err := ParseYAML([]byte(`spec:
+  replicas: 1
+  paused: true
+  selector:
+    app: a
+  ports:
+  - 80
+  - 443
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	overlay, err := ParseYAML([]byte(`spec:
+  replicas: 3
+  paused: null
+  selector:
+    tier: web
+    removed: null
+  ports:
+  - 8080
+  strategy:
+    type: Recreate
+    unset: null
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if err := doc.Overlay(overlay); err != nil {
+		t.Fatalf("Failed to overlay: %v", err)
+	}
+	exp := `spec:
+  replicas: 3
+  selector:
+    app: a
+    tier: web
+  ports:
+  - 8080
+  strategy:
+    type: Recreate
+`
+	if act := doc.String(); act != exp {
+		t.Errorf("Unexpected value: %v != %v", act, exp)
+	}
+}
+
+func TestMarshalOrderedJSON(t *testing.T) {
+	doc, err := ParseYAML([]byte(`b: "<1>"
+a:
+  d: [1, 2.5, true, null]
+  c: {}
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	act, err := doc.MarshalOrderedJSON()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if exp := `{"b":"<1>","a":{"d":[1,2.5,true,null],"c":{}}}`; string(act) != exp {
+		t.Errorf("Unexpected value: %s != %s", act, exp)
+	}
+}
+
+func TestParseOrderedJSON(t *testing.T) {
+	doc, err := ParseOrderedJSON([]byte(`{"b": "a\/b", "a": {"d": [1, 2.0, 1e3, true, null], "c": {}}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if act := doc.Path("b").Data(); act != "a/b" {
+		t.Errorf("Unexpected value: %v != a/b", act)
+	}
+	act, err := doc.MarshalOrderedJSON()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if exp := `{"b":"a/b","a":{"d":[1,2.0,1e3,true,null],"c":{}}}`; string(act) != exp {
+		t.Errorf("Unexpected value: %s != %s", act, exp)
+	}
+	if _, err := ParseOrderedJSON([]byte(`{"a": 1} {}`)); err == nil {
+		t.Error("Expected an error for trailing data")
+	}
+	if _, err := ParseOrderedJSON([]byte(`{"a": }`)); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
+
+func TestSortKeys(t *testing.T) {
+	sample := []byte(`c: 3
+# comment on a
+a:
+  z: 26
+  y: 25
+b: [2, 1]
+`)
+	doc, err := ParseYAML(sample)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	doc.SortKeys(strings.Compare)
+	expected := `# comment on a
+a:
+  y: 25
+  z: 26
+b: [2, 1]
+c: 3
+`
+	if actual := doc.String(); actual != expected {
+		t.Errorf("Wrong sorted keys:\n%s\nexpected:\n%s", actual, expected)
+	}
+
+	// Keys that compare equal retain their original order
+	doc.SortKeys(func(a, b string) int {
+		if a == "c" {
+			return -1
+		}
+		if b == "c" {
+			return 1
+		}
+		return 0
+	})
+	var keys []string
+	for i := 0; i < len(doc.YNode().Content); i += 2 {
+		keys = append(keys, doc.YNode().Content[i].Value)
+	}
+	if !reflect.DeepEqual(keys, []string{"c", "a", "b"}) {
+		t.Errorf("Wrong key order %v", keys)
+	}
+
+	// Only the keys of the top-level map are reordered
+	doc, err = ParseYAML([]byte("b:\n  z: 26\n  y: 25\na: 1\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	doc.SortTopLevelKeys(strings.Compare)
+	if expected, actual := "a: 1\nb:\n  z: 26\n  y: 25\n", doc.String(); actual != expected {
+		t.Errorf("Wrong sorted top-level keys:\n%s\nexpected:\n%s", actual, expected)
+	}
+}
@@ -15,6 +15,12 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/go-logr/logr"
 
 	"github.com/confighub/sdk/bridge-worker/api"
 	"github.com/confighub/sdk/bridge-worker/lib"
@@ -29,14 +35,57 @@ type ConfighubConnector struct {
 	workerID         string
 	workerSecret     string
 	configHubURL     string
+	logger           logr.Logger
+	eventCallback    func(event ConnectorEvent)
+	operationTimeout time.Duration
+
+	// mu protects the fields below, which are used to stop the connector
+	mu      sync.Mutex
+	worker  *lib.Worker
+	stopped bool
+	stopCh  chan struct{}
 }
 
+// ConnectorEventType identifies a transition in the state of the connection to ConfigHub.
+type ConnectorEventType = lib.ConnectionEventType
+
+const (
+	ConnectorEventConnected    = lib.ConnectionEventConnected
+	ConnectorEventDisconnected = lib.ConnectionEventDisconnected
+	ConnectorEventError        = lib.ConnectionEventError
+	ConnectorEventReconnecting = lib.ConnectionEventReconnecting
+)
+
+// ConnectorEvent describes a transition in the state of the connection to ConfigHub. Err is set
+// for ConnectorEventError events.
+type ConnectorEvent = lib.ConnectionEvent
+
 type ConnectorOptions struct {
 	WorkerID         string
 	WorkerSecret     string
 	ConfigHubURL     string
 	FunctionExecutor *function.FunctionExecutor
 	BridgeDispatcher *BridgeDispatcher
+	// Logger is used for logging by the bridges and functions the connector invokes, via
+	// log.FromContext. If not set, the controller-runtime logger is used.
+	Logger logr.Logger
+	// EventCallback is called on each transition in the state of the connection to ConfigHub.
+	// It is called in its own goroutine and must not block. See WithConnectorEventCallback.
+	EventCallback func(event ConnectorEvent)
+	// DefaultOperationTimeout is the maximum duration of each bridge operation, such as an apply
+	// and the wait for it to take effect, unless ConfigHub provides a timeout for the operation.
+	// When it's exceeded, the operation is canceled and reported as timed out. If not set,
+	// operations have no deadline.
+	DefaultOperationTimeout time.Duration
+}
+
+// WithConnectorEventCallback returns a copy of the options with the callback that is called on
+// each transition in the state of the connection to ConfigHub, such as connecting, disconnecting,
+// and failures. The callback is called in its own goroutine so that it can't stall the connector,
+// but it still must not block, and events may be observed out of order.
+func (opts ConnectorOptions) WithConnectorEventCallback(cb func(event ConnectorEvent)) ConnectorOptions {
+	opts.EventCallback = cb
+	return opts
 }
 
 // NewConnector creates a new ConfighubConnector. WorkerID and WorkerSecret are required.
@@ -48,29 +97,32 @@ func NewConnector(opts ConnectorOptions) (*ConfighubConnector, error) {
 		workerID:         opts.WorkerID,
 		workerSecret:     opts.WorkerSecret,
 		configHubURL:     opts.ConfigHubURL,
+		logger:           opts.Logger,
+		eventCallback:    opts.EventCallback,
+		operationTimeout: opts.DefaultOperationTimeout,
+		stopCh:           make(chan struct{}),
 	}, nil
 }
 
 // Start starts the worker. It opens a persistent connection to ConfigHub and starts performing work based on its configuration.
+// If the connection is closed or lost after it has been established, it is reestablished with exponential
+// backoff, and ConnectorEventReconnecting is reported before each attempt. Start returns when connecting fails
+// or the connector is stopped with Stop.
 func (c *ConfighubConnector) Start() error {
+	return c.StartContext(context.Background())
+}
 
-	// get api info from confighub. First instantiate generated client using confighub url.
-	apiClient, err := goclientnew.NewClientWithResponses(c.configHubURL + "/api")
+// StartContext is like Start, but also returns when ctx is done, which cancels the operations in progress.
+// Use Stop to wait for the operations in progress to complete instead.
+func (c *ConfighubConnector) StartContext(ctx context.Context) error {
+	workerUrl, err := c.getWorkerURL(ctx)
 	if err != nil {
+		if c.eventCallback != nil {
+			event := ConnectorEvent{Type: ConnectorEventError, Timestamp: time.Now(), Err: err}
+			go c.eventCallback(event)
+		}
 		return err
 	}
-	apiInfo, err := apiClient.ApiInfoWithResponse(context.Background())
-	if err != nil {
-		return err
-	}
-	if apiInfo.JSON200 == nil {
-		return fmt.Errorf("failed to get api info: %v", apiInfo.Body)
-	}
-	url, err := url.Parse(c.configHubURL)
-	if err != nil {
-		return err
-	}
-	workerUrl := fmt.Sprintf("%s://%s:%s", url.Scheme, url.Hostname(), apiInfo.JSON200.WorkerPort)
 
 	var bw api.BridgeWorker // api.BridgeWorker is the interface.
 	if c.bridgeDispatcher != nil {
@@ -83,11 +135,105 @@ func (c *ConfighubConnector) Start() error {
 	}
 	adapter := &FunctionWorkerAdapter{executor: c.functionExecutor}
 
-	worker := lib.New(workerUrl, c.workerID, c.workerSecret).
-		WithBridgeWorker(bw).
-		WithFunctionWorker(adapter)
+	reconnectBackOff := &backoff.ExponentialBackOff{
+		InitialInterval:     time.Second,
+		RandomizationFactor: backoff.DefaultRandomizationFactor,
+		Multiplier:          backoff.DefaultMultiplier,
+		MaxInterval:         time.Minute,
+	}
+	reconnectBackOff.Reset()
+	for {
+		var connected atomic.Bool
+		worker := lib.New(workerUrl, c.workerID, c.workerSecret).
+			WithBridgeWorker(bw).
+			WithFunctionWorker(adapter).
+			WithLogger(c.logger).
+			WithConnectionEventCallback(func(event ConnectorEvent) {
+				if event.Type == ConnectorEventConnected {
+					connected.Store(true)
+				}
+				if c.eventCallback != nil {
+					c.eventCallback(event)
+				}
+			}).
+			WithOperationTimeout(c.operationTimeout)
+		c.mu.Lock()
+		if c.stopped {
+			c.mu.Unlock()
+			return nil
+		}
+		c.worker = worker
+		c.mu.Unlock()
 
-	return worker.Start(context.Background())
+		startTime := time.Now()
+		err := worker.Start(ctx)
+		if c.isStopped() || ctx.Err() != nil {
+			return nil
+		}
+		// Failures to connect, such as due to invalid credentials, aren't retried. Only
+		// connections that were established and then closed or lost are reestablished.
+		if !connected.Load() {
+			return err
+		}
+		if time.Since(startTime) > reconnectBackOff.MaxInterval {
+			reconnectBackOff.Reset()
+		}
+		if c.eventCallback != nil {
+			event := ConnectorEvent{Type: ConnectorEventReconnecting, Timestamp: time.Now(), Err: err}
+			go c.eventCallback(event)
+		}
+		select {
+		case <-time.After(reconnectBackOff.NextBackOff()):
+		case <-c.stopCh:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Stop stops the connector gracefully: it stops receiving events from ConfigHub and waits for the
+// operations already received to complete, after which Start returns without reconnecting. If ctx
+// is done first, the remaining operations are canceled and ctx's error is returned.
+func (c *ConfighubConnector) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.stopped {
+		c.stopped = true
+		close(c.stopCh)
+	}
+	worker := c.worker
+	c.mu.Unlock()
+	if worker == nil {
+		return nil
+	}
+	return worker.Stop(ctx)
+}
+
+func (c *ConfighubConnector) isStopped() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stopped
+}
+
+// getWorkerURL returns the URL of the worker endpoint of ConfigHub.
+func (c *ConfighubConnector) getWorkerURL(ctx context.Context) (string, error) {
+	// get api info from confighub. First instantiate generated client using confighub url.
+	apiClient, err := goclientnew.NewClientWithResponses(c.configHubURL + "/api")
+	if err != nil {
+		return "", err
+	}
+	apiInfo, err := apiClient.ApiInfoWithResponse(ctx)
+	if err != nil {
+		return "", err
+	}
+	if apiInfo.JSON200 == nil {
+		return "", fmt.Errorf("failed to get api info: %v", apiInfo.Body)
+	}
+	url, err := url.Parse(c.configHubURL)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s://%s:%s", url.Scheme, url.Hostname(), apiInfo.JSON200.WorkerPort), nil
 }
 
 type NullBridgeWorker struct{}
@@ -118,6 +264,18 @@ func (n *NullBridgeWorker) Import(wctx api.BridgeWorkerContext, payload api.Brid
 	return nil
 }
 
+// Plan reports that there are no changes, since the null bridge worker doesn't manage any resources.
+func (n *NullBridgeWorker) Plan(wctx api.BridgeWorkerContext, payload api.BridgeWorkerPayload) error {
+	return wctx.SendStatus(&api.ActionResult{
+		ActionResultBaseMeta: api.ActionResultBaseMeta{
+			Status:  api.ActionStatusCompleted,
+			Result:  api.ActionResultPlanCompleted,
+			Message: "No changes",
+		},
+		Outputs: []byte("[]"),
+	})
+}
+
 type FunctionWorkerAdapter struct {
 	executor *function.FunctionExecutor
 }
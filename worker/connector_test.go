@@ -0,0 +1,92 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// newTestConnector returns a connector for a server that keeps the event stream open until the
+// connector closes it, and a channel that receives an event when the connector connects.
+func newTestConnector(t *testing.T) (*ConfighubConnector, <-chan ConnectorEvent) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	mux.HandleFunc("/api/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"WorkerPort": %q}`, serverURL.Port())
+	})
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/me", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Slug": "test-worker"}`))
+	})
+	mux.HandleFunc("/api/bridge_worker/test-worker-id/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	connected := make(chan ConnectorEvent, 1)
+	connector, err := NewConnector(ConnectorOptions{
+		WorkerID:     "test-worker-id",
+		WorkerSecret: "test-worker-secret",
+		ConfigHubURL: server.URL,
+		EventCallback: func(event ConnectorEvent) {
+			if event.Type == ConnectorEventConnected {
+				connected <- event
+			}
+		},
+	})
+	require.NoError(t, err)
+	return connector, connected
+}
+
+func waitForStart(t *testing.T, errc <-chan error) {
+	select {
+	case err := <-errc:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start didn't return")
+	}
+}
+
+func TestConnector_StartContextReturnsWhenDone(t *testing.T) {
+	connector, connected := newTestConnector(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- connector.StartContext(ctx)
+	}()
+	<-connected
+
+	cancel()
+	waitForStart(t, errc)
+}
+
+func TestConnector_Stop(t *testing.T) {
+	connector, connected := newTestConnector(t)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- connector.Start()
+	}()
+	<-connected
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, connector.Stop(ctx))
+	waitForStart(t, errc)
+}
@@ -10,7 +10,9 @@ const (
 	ToolchainKubernetesYAML      ToolchainType = "Kubernetes/YAML"
 	ToolchainOpenTofuHCL         ToolchainType = "OpenTofu/HCL"
 	ToolchainAppConfigProperties ToolchainType = "AppConfig/Properties"
+	ToolchainAppConfigYAML       ToolchainType = "AppConfig/YAML"
 	ToolchainAppConfigTOML       ToolchainType = "AppConfig/TOML" // TODO
 	ToolchainAppConfigINI        ToolchainType = "AppConfig/INI"  // TODO
 	ToolchainAppConfigEnv        ToolchainType = "AppConfig/Env"  // TODO
+	ToolchainAnsibleVars         ToolchainType = "Ansible/Vars"
 )